@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/spf13/cobra"
+)
+
+func (a *app) archiveCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Move old audit logs and stock movements to the archive tables",
+		Long: "Moves rows older than ARCHIVE_AFTER_MONTHS out of the hot tables. The server does this every " +
+			"ARCHIVE_INTERVAL; run it by hand to catch up after lowering the setting.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			moved, err := services.NewArchiveService(a.db, a.cfg).Archive(cmd.Context())
+			for table, count := range moved {
+				fmt.Printf("Archived %d rows of %s\n", count, table)
+			}
+			return err
+		},
+	}
+	return cmd
+}
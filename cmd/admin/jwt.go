@@ -0,0 +1,52 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *app) jwtCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "jwt",
+		Short: "Manage the JWT signing secret",
+	}
+	cmd.AddCommand(a.rotateJWTCommand())
+	return cmd
+}
+
+// rotateJWTCommand generates a new secret for JWT_SECRET. The secret lives in
+// the environment or secrets source, so the command prints it rather than
+// storing it; tokens signed with the old secret stop validating once every
+// instance is restarted with the new one.
+func (a *app) rotateJWTCommand() *cobra.Command {
+	var revokeSessions bool
+
+	cmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "Generate a new JWT secret and optionally revoke all sessions",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			secret := make([]byte, 48)
+			if _, err := rand.Read(secret); err != nil {
+				return fmt.Errorf("failed to generate secret: %v", err)
+			}
+
+			if revokeSessions {
+				revoked, err := a.authService().RevokeAllSessions(cmd.Context())
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Revoked %d refresh tokens\n", revoked)
+			}
+
+			fmt.Println("New JWT secret:")
+			fmt.Println(base64.RawURLEncoding.EncodeToString(secret))
+			fmt.Println("Set it as JWT_SECRET (or in the secrets source) and restart every instance.")
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&revokeSessions, "revoke-sessions", false, "revoke every refresh token so users sign in again")
+	return cmd
+}
@@ -0,0 +1,104 @@
+// Command admin runs operational tasks against the same database, S3 bucket
+// and service layer as the API server:
+//
+//	go run ./cmd/admin users create-admin --email ops@example.com --password ...
+//	go run ./cmd/admin jwt rotate --revoke-sessions
+//	go run ./cmd/admin storage reconcile
+//	go run ./cmd/admin search reindex
+//	go run ./cmd/admin export reviews --from 2024-01-01
+//	go run ./cmd/admin pii reencrypt
+//
+// It reads the same .env, secrets source and environment as cmd/server.
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/database"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+// app holds the configuration and database shared by the subcommands. It is
+// filled in before any subcommand runs.
+type app struct {
+	cfg *config.Config
+	db  *gorm.DB
+}
+
+func main() {
+	a := &app{}
+
+	root := &cobra.Command{
+		Use:           "admin",
+		Short:         "Operational tasks for the Sipfinity backend",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return a.init()
+		},
+	}
+	root.AddCommand(
+		a.usersCommand(),
+		a.jwtCommand(),
+		a.storageCommand(),
+		a.searchCommand(),
+		a.exportCommand(),
+		a.piiCommand(),
+		a.archiveCommand(),
+	)
+
+	if err := root.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+func (a *app) init() error {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+	logger.Init()
+
+	if _, err := config.LoadSecrets(); err != nil {
+		return fmt.Errorf("failed to load secrets: %v", err)
+	}
+	a.cfg = config.Load()
+
+	db, err := database.Init(a.cfg.DatabaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %v", err)
+	}
+	a.db = db
+
+	keyring, err := services.NewPIIKeyring(a.cfg)
+	if err != nil {
+		return fmt.Errorf("failed to load PII encryption keys: %v", err)
+	}
+	models.SetPIIKeyring(keyring)
+	return nil
+}
+
+func (a *app) authService() *services.AuthService {
+	// The CLI never signs users up, so validation, email and OTP are not needed
+	return services.NewAuthService(a.db, a.cfg.JWTSecret, nil, nil, nil, nil, a.cfg.BaseURL, services.PasswordPolicy{HistorySize: a.cfg.PasswordHistorySize, MaxAge: a.cfg.PasswordMaxAge}, logger.New(map[string]interface{}{"service": "auth", "source": "cli"}))
+}
+
+func (a *app) jobService() *services.JobService {
+	return services.NewJobService(a.db, services.NewJobHub())
+}
+
+func (a *app) adminService() *services.AdminService {
+	return services.NewAdminService(a.db, a.cfg, nil, nil, a.jobService(), logger.New(map[string]interface{}{"service": "admin", "source": "cli"}))
+}
+
+func (a *app) s3Service() *services.S3Service {
+	return services.NewS3Service(a.cfg.S3Region, a.cfg.S3Endpoint, a.cfg.S3BucketName, a.cfg.S3AccessKey, a.cfg.S3SecretKey, logger.New(map[string]interface{}{"service": "s3", "source": "cli"}))
+}
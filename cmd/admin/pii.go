@@ -0,0 +1,65 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/spf13/cobra"
+)
+
+func (a *app) piiCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pii",
+		Short: "Manage encryption of personal data at rest",
+	}
+	cmd.AddCommand(a.generatePIIKeyCommand(), a.reencryptPIICommand())
+	return cmd
+}
+
+// generatePIIKeyCommand prints a new data key. Like the JWT secret it lives in
+// the environment or secrets source, so it is printed rather than stored.
+func (a *app) generatePIIKeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate-key <id>",
+		Short: "Generate a new AES-256 key for PII_ENCRYPTION_KEYS",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := make([]byte, 32)
+			if _, err := rand.Read(key); err != nil {
+				return fmt.Errorf("failed to generate key: %v", err)
+			}
+
+			fmt.Println("New PII key:")
+			fmt.Printf("%s:%s\n", args[0], base64.StdEncoding.EncodeToString(key))
+			fmt.Println("Put it first in PII_ENCRYPTION_KEYS, keep the old keys after it, restart every instance, then run pii reencrypt.")
+			return nil
+		},
+	}
+	return cmd
+}
+
+// reencryptPIICommand moves every encrypted column onto the current key, and
+// encrypts rows written before encryption was turned on
+func (a *app) reencryptPIICommand() *cobra.Command {
+	var batchSize int
+
+	cmd := &cobra.Command{
+		Use:   "reencrypt",
+		Short: "Re-encrypt personal data with the current PII key",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			counts, err := services.NewPIIService(a.db).Reencrypt(cmd.Context(), batchSize)
+			for table, count := range counts {
+				fmt.Printf("Re-encrypted %d rows in %s\n", count, table)
+			}
+			if err != nil {
+				return err
+			}
+			fmt.Println("Every row uses the current key; older keys can be removed from PII_ENCRYPTION_KEYS.")
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&batchSize, "batch-size", 500, "rows re-encrypted per transaction")
+	return cmd
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/spf13/cobra"
+)
+
+func (a *app) storageCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "storage",
+		Short: "S3 storage maintenance",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "reconcile",
+		Short: "Reconcile S3 product images against image records",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			storage := services.NewStorageService(a.db, a.cfg, a.s3Service(), a.jobService())
+			report, err := storage.Reconcile(cmd.Context(), "cli")
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Report #%d: scanned %d objects, %d orphaned (%d deleted, %d in grace period), %d image rows missing their object\n",
+				report.ID, report.ScannedObjects, report.OrphanObjects, report.DeletedOrphans, report.PendingOrphans, report.MissingImages)
+			return nil
+		},
+	})
+	return cmd
+}
+
+// searchCommand recomputes the stored ranking columns. The typeahead index is
+// held in memory by each server instance and rebuilt there on product changes
+// and every SUGGEST_REFRESH_INTERVAL, so it cannot be rebuilt from here.
+func (a *app) searchCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search",
+		Short: "Search and ranking maintenance",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "reindex",
+		Short: "Recompute product ratings and rating scores used to rank results",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ratings := services.NewRatingService(a.db, a.cfg.RatingPriorWeight, a.cfg.RatingMinReviews)
+			if err := ratings.RefreshAll(cmd.Context()); err != nil {
+				return err
+			}
+			fmt.Println("Product ratings refreshed")
+			return nil
+		},
+	})
+	return cmd
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/spf13/cobra"
+)
+
+func (a *app) usersCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "users",
+		Short: "Manage user accounts",
+	}
+	cmd.AddCommand(a.createAdminCommand())
+	return cmd
+}
+
+func (a *app) createAdminCommand() *cobra.Command {
+	var req services.CreateAdminRequest
+
+	cmd := &cobra.Command{
+		Use:   "create-admin",
+		Short: "Create a platform admin, e.g. the first one on a fresh database",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			user, err := a.authService().CreateAdmin(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Created admin #%d %s\n", user.ID, user.Email)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&req.Email, "email", "", "email address of the admin")
+	cmd.Flags().StringVar(&req.Password, "password", "", "initial password, at least 8 characters")
+	cmd.Flags().StringVar(&req.FirstName, "first-name", "", "first name")
+	cmd.Flags().StringVar(&req.LastName, "last-name", "", "last name")
+	cmd.MarkFlagRequired("email")
+	cmd.MarkFlagRequired("password")
+	return cmd
+}
@@ -0,0 +1,288 @@
+// Command seed fills the database with realistic fake customers, products
+// and reviews for load testing and local frontend development:
+//
+//	go run ./cmd/seed -products 500 -users 100 -reviews 8
+//
+// Rows are inserted directly, without outbox events, so webhooks and event
+// consumers never see seed data. Image rows point at placeholder URLs under
+// the seed/ prefix; there are no S3 objects behind them, so storage
+// reconciliation reports them missing. Every seeded user signs in with
+// -password.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/joho/godotenv"
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/database"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const batchSize = 200
+
+var sizes = []string{"XS", "S", "M", "L", "XL", "XXL", "One Size"}
+
+type options struct {
+	products   int
+	users      int
+	reviews    int // maximum reviews per product
+	images     int // maximum images per product
+	password   string
+	randomSeed uint64
+	force      bool
+}
+
+func main() {
+	var opts options
+	flag.IntVar(&opts.products, "products", 200, "number of products to create")
+	flag.IntVar(&opts.users, "users", 50, "number of customers to create")
+	flag.IntVar(&opts.reviews, "reviews", 5, "maximum reviews per product, capped at -users")
+	flag.IntVar(&opts.images, "images", 3, "maximum images per product")
+	flag.StringVar(&opts.password, "password", "password123", "password of every seeded user")
+	flag.Uint64Var(&opts.randomSeed, "seed", 0, "random seed for reproducible data; 0 picks one")
+	flag.BoolVar(&opts.force, "force", false, "allow seeding when ENVIRONMENT=production")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+	logger.Init()
+
+	if _, err := config.LoadSecrets(); err != nil {
+		logger.Fatal("Failed to load secrets: ", err)
+	}
+	cfg := config.Load()
+	if cfg.Environment == "production" && !opts.force {
+		logger.Fatal("Refusing to seed a production database; pass -force to override")
+	}
+
+	db, err := database.Init(cfg.DatabaseURL)
+	if err != nil {
+		logger.Fatal("Failed to initialize database: ", err)
+	}
+
+	if opts.randomSeed == 0 {
+		opts.randomSeed = uint64(time.Now().UnixNano())
+	}
+	faker := gofakeit.New(opts.randomSeed)
+	logger.Info(fmt.Sprintf("Seeding with -seed %d", opts.randomSeed))
+
+	s := &seeder{db: db, faker: faker, opts: opts}
+	if err := s.run(); err != nil {
+		logger.Fatal("Seeding failed: ", err)
+	}
+
+	// Fill in avg_rating, rating_count and rating_score for the new reviews
+	ratings := services.NewRatingService(db, cfg.RatingPriorWeight, cfg.RatingMinReviews)
+	if err := ratings.RefreshAll(context.Background()); err != nil {
+		logger.Fatal("Failed to refresh product ratings: ", err)
+	}
+
+	logger.Info(fmt.Sprintf("Seeded %d users, %d products, %d images and %d reviews",
+		len(s.userIDs), len(s.productIDs), s.imageCount, s.reviewCount))
+}
+
+type seeder struct {
+	db    *gorm.DB
+	faker *gofakeit.Faker
+	opts  options
+
+	userIDs     []uint
+	productIDs  []uint
+	imageCount  int
+	reviewCount int
+}
+
+func (s *seeder) run() error {
+	if err := s.seedUsers(); err != nil {
+		return err
+	}
+	if err := s.seedProducts(); err != nil {
+		return err
+	}
+	return s.seedReviews()
+}
+
+// seedUsers creates customers sharing one pre-hashed password. Hooks are
+// skipped so BeforeCreate does not bcrypt every row again.
+func (s *seeder) seedUsers() error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(s.opts.password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %v", err)
+	}
+
+	// Emails carry the run's seed so repeated runs do not collide
+	users := make([]models.User, 0, s.opts.users)
+	for i := 0; i < s.opts.users; i++ {
+		firstName, lastName := s.faker.FirstName(), s.faker.LastName()
+		users = append(users, models.User{
+			Email:       fmt.Sprintf("seed-%d-%d@example.com", s.opts.randomSeed, i+1),
+			Password:    string(hash),
+			FirstName:   firstName,
+			LastName:    lastName,
+			PhoneNumber: models.EncryptedString(s.faker.Phone()),
+			Role:        "customer",
+			IsActive:    true,
+		})
+	}
+	if len(users) == 0 {
+		return nil
+	}
+
+	if err := s.db.Session(&gorm.Session{SkipHooks: true}).CreateInBatches(&users, batchSize).Error; err != nil {
+		return fmt.Errorf("failed to create users: %v", err)
+	}
+	for _, user := range users {
+		s.userIDs = append(s.userIDs, user.ID)
+	}
+	logger.Info(fmt.Sprintf("Created %d users", len(users)))
+	return nil
+}
+
+func (s *seeder) seedProducts() error {
+	for start := 0; start < s.opts.products; start += batchSize {
+		count := min(batchSize, s.opts.products-start)
+
+		products := make([]models.Product, 0, count)
+		for i := 0; i < count; i++ {
+			status := models.ProductStatusActive
+			if s.faker.Number(1, 10) == 1 {
+				status = models.ProductStatusInactive
+			}
+			products = append(products, models.Product{
+				Title:       s.faker.ProductName(),
+				SKU:         fmt.Sprintf("SEED-%d-%06d", s.opts.randomSeed%100000, start+i+1),
+				Description: s.faker.ProductDescription(),
+				Price:       s.faker.Price(5, 500),
+				Category:    s.faker.ProductCategory(),
+				Size:        sizes[s.faker.Number(0, len(sizes)-1)],
+				Material:    s.faker.ProductMaterial(),
+				Status:      status,
+				Stock:       s.faker.Number(0, 200),
+				CreatedAt:   s.faker.DateRange(time.Now().AddDate(-1, 0, 0), time.Now()),
+			})
+		}
+
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Omit("Images", "Services", "Reviews").Create(&products).Error; err != nil {
+				return fmt.Errorf("failed to create products: %v", err)
+			}
+
+			var images []models.Image
+			var movements []models.StockMovement
+			for _, product := range products {
+				imageCount := s.faker.Number(1, max(1, s.opts.images))
+				for j := 0; j < imageCount; j++ {
+					key := fmt.Sprintf("seed/%d/%d-%d.jpg", s.opts.randomSeed, product.ID, j+1)
+					images = append(images, models.Image{
+						ProductID:   product.ID,
+						FileName:    fmt.Sprintf("%d-%d.jpg", product.ID, j+1),
+						S3Key:       key,
+						S3URL:       fmt.Sprintf("https://picsum.photos/seed/%d-%d-%d/800/800", s.opts.randomSeed, product.ID, j+1),
+						ContentType: "image/jpeg",
+						Size:        int64(s.faker.Number(40_000, 400_000)),
+						IsActive:    true,
+					})
+				}
+				if product.Stock > 0 {
+					movements = append(movements, models.StockMovement{
+						ProductID:  product.ID,
+						Delta:      product.Stock,
+						StockAfter: product.Stock,
+						Reason:     models.StockReasonInitial,
+						Reference:  "seed",
+					})
+				}
+			}
+
+			if len(images) > 0 {
+				if err := tx.CreateInBatches(&images, batchSize).Error; err != nil {
+					return fmt.Errorf("failed to create images: %v", err)
+				}
+			}
+			if len(movements) > 0 {
+				if err := tx.CreateInBatches(&movements, batchSize).Error; err != nil {
+					return fmt.Errorf("failed to create stock movements: %v", err)
+				}
+			}
+			s.imageCount += len(images)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, product := range products {
+			s.productIDs = append(s.productIDs, product.ID)
+		}
+		logger.Info(fmt.Sprintf("Created %d/%d products", len(s.productIDs), s.opts.products))
+	}
+	return nil
+}
+
+// seedReviews gives each product up to -reviews reviews from distinct users,
+// skewed towards good ratings like a real catalogue
+func (s *seeder) seedReviews() error {
+	if len(s.userIDs) == 0 || s.opts.reviews <= 0 {
+		return nil
+	}
+	ratings := []int{1, 2, 3, 3, 4, 4, 4, 5, 5, 5}
+
+	var reviews []models.Review
+	flush := func() error {
+		if len(reviews) == 0 {
+			return nil
+		}
+		if err := s.db.Omit("User", "Product", "Likes").CreateInBatches(&reviews, batchSize).Error; err != nil {
+			return fmt.Errorf("failed to create reviews: %v", err)
+		}
+		s.reviewCount += len(reviews)
+		reviews = reviews[:0]
+		return nil
+	}
+
+	for _, productID := range s.productIDs {
+		count := s.faker.Number(0, min(s.opts.reviews, len(s.userIDs)))
+		for _, idx := range s.pickUsers(count) {
+			reviews = append(reviews, models.Review{
+				UserID:      s.userIDs[idx],
+				ProductID:   productID,
+				Rating:      ratings[s.faker.Number(0, len(ratings)-1)],
+				Comment:     s.faker.Sentence(s.faker.Number(6, 30)),
+				Visibility:  models.ReviewVisibilityPublished,
+				IsAnonymous: s.faker.Number(1, 10) == 1,
+				CreatedAt:   s.faker.DateRange(time.Now().AddDate(0, -6, 0), time.Now()),
+			})
+		}
+		if len(reviews) >= batchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
+
+// pickUsers returns count distinct indexes into userIDs
+func (s *seeder) pickUsers(count int) []int {
+	picked := make(map[int]bool, count)
+	indexes := make([]int, 0, count)
+	for len(indexes) < count {
+		idx := s.faker.Number(0, len(s.userIDs)-1)
+		if !picked[idx] {
+			picked[idx] = true
+			indexes = append(indexes, idx)
+		}
+	}
+	return indexes
+}
@@ -22,9 +22,23 @@ func main() {
 	// Initialize logger
 	logger.Init()
 
+	// Pull secrets from AWS (if configured) before reading the environment
+	if applied, err := config.LoadSecrets(); err != nil {
+		logger.Fatal("Failed to load secrets: ", err)
+	} else if applied > 0 {
+		logger.Info(fmt.Sprintf("Loaded %d secrets from %s", applied, os.Getenv("SECRETS_SOURCE")))
+	}
+
 	// Load configuration
 	cfg := config.Load()
-	fmt.Printf("Configuration loaded successfully: %+v\n", cfg)
+	warnings, err := cfg.Validate()
+	for _, warning := range warnings {
+		logger.Warn("Config: " + warning)
+	}
+	if err != nil {
+		logger.Fatal(err)
+	}
+	logger.Info("Configuration loaded: " + cfg.Summary())
 
 
 	// Initialize database
@@ -32,6 +46,9 @@ func main() {
 	if err != nil {
 		logger.Fatal("Failed to initialize database", err)
 	}
+	if err := database.UseReplicas(db, cfg.DatabaseReplicaURLs); err != nil {
+		logger.Fatal("Failed to connect to read replicas", err)
+	}
 
 
 	
@@ -2,16 +2,21 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/princeprakhar/ecommerce-backend/internal/models"
 	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/types"
 	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
 )
 
 type AdminHandler struct {
@@ -24,63 +29,37 @@ func NewAdminHandler(adminService *services.AdminService) *AdminHandler {
 
 // CreateProduct handles the creation of a new product with images
 func (h *AdminHandler) CreateProduct(c *gin.Context) {
-	// Parse form data for product information
+	// JSON and multipart forms bind to the same schema and validation rules
 	var productReq models.CreateProductRequest
-	
-	// Try to get JSON data first
-	if c.ContentType() == "application/json" {
-		if err := c.ShouldBindJSON(&productReq); err != nil {
-			utils.SendValidationError(c, "Invalid JSON data: "+err.Error())
+	if err := c.ShouldBind(&productReq); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+	isJSON := c.ContentType() == "application/json"
+	if servicesStr := c.PostForm("services"); !isJSON && servicesStr != "" {
+		if err := json.Unmarshal([]byte(servicesStr), &productReq.Services); err != nil {
+			utils.SendValidationError(c, "Invalid services format")
 			return
 		}
-	} else {
-		// Handle multipart form data
-		productReq.Title = c.PostForm("title")
-		productReq.Description = c.PostForm("description")
-		productReq.Category = c.PostForm("category")
-		productReq.Status = c.PostForm("status")
-		productReq.Material = c.PostForm("material")
-		productReq.Size = c.PostForm("size")
-		if servicesStr := c.PostForm("services"); servicesStr != "" {
-			if err := json.Unmarshal([]byte(servicesStr), &productReq.Services); err != nil {
-				utils.SendValidationError(c, "Invalid services format")
-				return
-			}
-		}
-		// Parse price
-		if priceStr := c.PostForm("price"); priceStr != "" {
-			price, err := strconv.ParseFloat(priceStr, 64)
-			if err != nil {
-				utils.SendValidationError(c, "Invalid price format")
-				return
-			}
-			productReq.Price = price
-		}
-		
-		// Parse stock
-		if stockStr := c.PostForm("stock"); stockStr != "" {
-			stock, err := strconv.Atoi(stockStr)
-			if err != nil {
-				utils.SendValidationError(c, "Invalid stock format")
-				return
-			}
-			productReq.Stock = stock
+	}
+	for _, svc := range productReq.Services {
+		if svc.Name == "" || svc.Link == "" {
+			utils.SendValidationError(c, "Every service needs a name and a link")
+			return
 		}
 	}
 
-	// Validate required fields
-	if productReq.Title == "" {
-		utils.SendValidationError(c, "Product title is required")
-		return
+	// Vendor users always create products for their own vendor
+	if vendorID := c.GetUint("vendor_id"); vendorID != 0 {
+		productReq.VendorID = &vendorID
 	}
-	if productReq.Price <= 0 {
-		utils.SendValidationError(c, "Product price must be greater than 0")
-		return
+	if storeID := c.GetUint("store_id"); storeID != 0 {
+		productReq.StoreID = &storeID
 	}
 
 	// Handle image uploads
 	var imageFiles []*multipart.FileHeader
-	if c.ContentType() != "application/json" {
+	if !isJSON {
 		form, err := c.MultipartForm()
 		if err == nil && form.File["images"] != nil {
 			imageFiles = form.File["images"]
@@ -88,9 +67,9 @@ func (h *AdminHandler) CreateProduct(c *gin.Context) {
 	}
 
 	// Create product with images
-	product, err := h.adminService.CreateProduct(&productReq, imageFiles)
+	product, err := h.adminService.CreateProduct(c.Request.Context(), &productReq, imageFiles)
 	if err != nil {
-		utils.SendError(c, http.StatusBadRequest, "Failed to create product", err)
+		sendServiceError(c, "Failed to create product", err)
 		return
 	}
 
@@ -124,6 +103,9 @@ func (h *AdminHandler) UpdateProduct(c *gin.Context) {
 		if description := c.PostForm("description"); description != "" {
 			updateReq.Description = &description
 		}
+		if sku := c.PostForm("sku"); sku != "" {
+			updateReq.SKU = &sku
+		}
 		if category := c.PostForm("category"); category != "" {
 			updateReq.Category = &category
 		}
@@ -133,6 +115,9 @@ func (h *AdminHandler) UpdateProduct(c *gin.Context) {
 		if status := c.PostForm("status"); status != "" {
 			updateReq.Status = &status
 		}
+		if display, ok := c.GetPostForm("out_of_stock_display"); ok {
+			updateReq.OutOfStockDisplay = &display
+		}
 		// Parse services
 		if servicesStr := c.PostForm("services"); servicesStr != "" {
 			if err := json.Unmarshal([]byte(servicesStr), &updateReq.Services); err != nil {
@@ -162,6 +147,24 @@ func (h *AdminHandler) UpdateProduct(c *gin.Context) {
 			}
 			updateReq.Stock = &stock
 		}
+
+		// Parse order quantity limits
+		if minStr := c.PostForm("min_order_qty"); minStr != "" {
+			minQty, err := strconv.Atoi(minStr)
+			if err != nil {
+				utils.SendValidationError(c, "Invalid min_order_qty format")
+				return
+			}
+			updateReq.MinOrderQty = &minQty
+		}
+		if maxStr := c.PostForm("max_order_qty"); maxStr != "" {
+			maxQty, err := strconv.Atoi(maxStr)
+			if err != nil {
+				utils.SendValidationError(c, "Invalid max_order_qty format")
+				return
+			}
+			updateReq.MaxOrderQty = &maxQty
+		}
 		
 
 		// Handle image uploads
@@ -185,11 +188,16 @@ func (h *AdminHandler) UpdateProduct(c *gin.Context) {
 		utils.SendValidationError(c, "Product price must be greater than 0")
 		return
 	}
+	// Only admins move products between vendors
+	if c.GetUint("vendor_id") != 0 && updateReq.VendorID != nil {
+		utils.SendForbidden(c, "Vendors cannot change a product's vendor")
+		return
+	}
 
 	// Update product
-	product, err := h.adminService.UpdateProduct(c.Request.Context(),uint(productID), &updateReq, imageFiles, deleteImageIDs)
+	product, err := h.adminService.UpdateProduct(c.Request.Context(), c.GetUint("user_id"), uint(productID), &updateReq, imageFiles, deleteImageIDs)
 	if err != nil {
-		utils.SendError(c, http.StatusBadRequest, "Failed to update product", err)
+		sendServiceError(c, "Failed to update product", err)
 		return
 	}
 
@@ -219,9 +227,9 @@ func (h *AdminHandler) UploadProductImages(c *gin.Context) {
 
 	// Use the update method to add images
 	updateReq := models.UpdateProductRequest{} // Empty update request
-	product, err := h.adminService.UpdateProduct(c.Request.Context(),uint(productID), &updateReq, images, nil)
+	product, err := h.adminService.UpdateProduct(c.Request.Context(), c.GetUint("user_id"), uint(productID), &updateReq, images, nil)
 	if err != nil {
-		utils.SendError(c, http.StatusBadRequest, "Failed to upload images", err)
+		sendServiceError(c, "Failed to upload images", err)
 		return
 	}
 
@@ -241,9 +249,9 @@ func (h *AdminHandler) DeleteProductImage(c *gin.Context) {
 
 	// Use the update method to delete specific image
 	updateReq := models.UpdateProductRequest{} // Empty update request
-	product, err := h.adminService.UpdateProduct(c.Request.Context(),uint(productID), &updateReq, nil, []string{imageIDStr})
+	product, err := h.adminService.UpdateProduct(c.Request.Context(), c.GetUint("user_id"), uint(productID), &updateReq, nil, []string{imageIDStr})
 	if err != nil {
-		utils.SendError(c, http.StatusBadRequest, "Failed to delete image", err)
+		sendServiceError(c, "Failed to delete image", err)
 		return
 	}
 
@@ -264,43 +272,172 @@ func (h *AdminHandler) UploadCSV(c *gin.Context) {
 		return
 	}
 
-	response, err := h.adminService.ProcessCSVUpload(file, userEmail)
+	response, err := h.adminService.ProcessCSVUpload(c.Request.Context(), file, userEmail)
 	if err != nil {
-		utils.SendError(c, http.StatusBadRequest, "Failed to process CSV", err)
+		sendServiceError(c, "Failed to process CSV", err)
 		return
 	}
 
 	utils.SendSuccess(c, "CSV processed successfully", response)
 }
 
-func (h *AdminHandler) GetProducts(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+// ImportProductsCSV queues a background import; follow it via /admin/jobs
+func (h *AdminHandler) ImportProductsCSV(c *gin.Context) {
+	file, err := c.FormFile("csv")
+	if err != nil {
+		utils.SendValidationError(c, "No CSV file provided")
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		utils.SendValidationError(c, "Failed to open CSV file")
+		return
+	}
+	defer src.Close()
+
+	content, err := io.ReadAll(src)
+	if err != nil {
+		utils.SendValidationError(c, "Failed to read CSV file")
+		return
+	}
+
+	job, err := h.adminService.StartProductImport(c.Request.Context(), content, file.Filename, c.GetUint("user_id"))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidInput) {
+			utils.SendError(c, http.StatusUnprocessableEntity, "Failed to process CSV", err)
+			return
+		}
+		utils.SendInternalError(c, "Failed to start import", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.APIResponse{
+		Success: true,
+		Message: "Product import started",
+		Data:    job,
+	})
+}
+
+// ExportProducts queues a CSV export uploaded to S3; follow it via /admin/jobs
+func (h *AdminHandler) ExportProducts(c *gin.Context) {
+	job, err := h.adminService.StartProductExport(c.Request.Context(), c.GetUint("user_id"))
+	if err != nil {
+		utils.SendInternalError(c, "Failed to start export", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.APIResponse{
+		Success: true,
+		Message: "Product export started",
+		Data:    job,
+	})
+}
+
+// ExportReviews queues a CSV export of reviews uploaded to S3; follow it via
+// /admin/jobs. ?from= and ?to= are inclusive dates (YYYY-MM-DD) and ?status=
+// limits the export to one visibility.
+func (h *AdminHandler) ExportReviews(c *gin.Context) {
+	var filter services.ReviewExportFilter
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			utils.SendValidationError(c, "Invalid from date, expected YYYY-MM-DD")
+			return
+		}
+		filter.From = &t
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			utils.SendValidationError(c, "Invalid to date, expected YYYY-MM-DD")
+			return
+		}
+		t = t.AddDate(0, 0, 1)
+		filter.To = &t
+	}
+	switch status := c.Query("status"); status {
+	case "", models.ReviewVisibilityPublished, models.ReviewVisibilityPending,
+		models.ReviewVisibilityShadowHidden, models.ReviewVisibilityRemoved:
+		filter.Visibility = status
+	default:
+		utils.SendValidationError(c, "Invalid status")
+		return
+	}
+
+	job, err := h.adminService.StartReviewExport(c.Request.Context(), c.GetUint("user_id"), filter)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidInput) {
+			utils.SendError(c, http.StatusUnprocessableEntity, "Invalid date range", err)
+			return
+		}
+		utils.SendInternalError(c, "Failed to start export", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.APIResponse{
+		Success: true,
+		Message: "Review export started",
+		Data:    job,
+	})
+}
+
+// StreamProducts writes the whole catalogue as newline-delimited JSON, one
+// product per line, flushing after every batch. Accepts ?fields= and ?expand=
+// like the paginated listing.
+func (h *AdminHandler) StreamProducts(c *gin.Context) {
+	proj, err := productProjection(c, services.ExpandImages, services.ExpandServices)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid fields or expand parameter", err)
+		return
+	}
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
 
-	if page < 1 {
-		page = 1
+	encoder := json.NewEncoder(c.Writer)
+	err = h.adminService.StreamProducts(c.Request.Context(), productScope(c), proj, func(batch []models.Product) error {
+		for i := range batch {
+			product, err := proj.Shape(&batch[i])
+			if err != nil {
+				return err
+			}
+			if err := encoder.Encode(product); err != nil {
+				return err
+			}
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		// Headers are already sent; the truncated stream is all the client gets
+		logger.Error("Failed to stream products: ", err)
 	}
-	if limit < 1 || limit > 100 {
-		limit = 20
+}
+
+func (h *AdminHandler) GetProducts(c *gin.Context) {
+	page, limit := utils.ParsePagination(c, 20)
+	proj, err := productProjection(c, services.ExpandImages, services.ExpandReviews, services.ExpandServices)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid fields or expand parameter", err)
+		return
 	}
 
-	products, err := h.adminService.GetProducts(page, limit)
+	products, total, err := h.adminService.GetProducts(c.Request.Context(), page, limit, productScope(c), proj)
 	if err != nil {
 		utils.SendInternalError(c, "Failed to fetch products", err)
 		return
 	}
 
-	// Response with pagination info
-	response := map[string]interface{}{
-		"products": products,
-		"pagination": map[string]interface{}{
-			"page":  page,
-			"limit": limit,
-			"total": len(products),
-		},
+	response := types.NewPaginated("products", products, page, limit, total)
+	shaped, err := proj.ShapePage(&response)
+	if err != nil {
+		utils.SendInternalError(c, "Failed to fetch products", err)
+		return
 	}
 
-	utils.SendSuccess(c, "Products retrieved successfully", response)
+	utils.SendSuccess(c, "Products retrieved successfully", shaped)
 }
 
 // GetProduct handles fetching a single product by ID
@@ -312,14 +449,25 @@ func (h *AdminHandler) GetProduct(c *gin.Context) {
 		return
 	}
 
-	// You'll need to add this method to AdminService
-	product, err := h.adminService.GetProductByID( c.Request.Context(), uint(productID))
+	proj, err := productProjection(c, services.ExpandImages, services.ExpandReviews, services.ExpandServices)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid fields or expand parameter", err)
+		return
+	}
+
+	product, err := h.adminService.GetProductByID(c.Request.Context(), uint(productID), proj)
 	if err != nil {
 		utils.SendError(c, http.StatusNotFound, "Product not found", err)
 		return
 	}
 
-	utils.SendSuccess(c, "Product retrieved successfully", product)
+	shaped, err := proj.Shape(product)
+	if err != nil {
+		utils.SendInternalError(c, "Failed to fetch product", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Product retrieved successfully", shaped)
 }
 
 func (h *AdminHandler) DeleteProduct(c *gin.Context) {
@@ -332,15 +480,99 @@ func (h *AdminHandler) DeleteProduct(c *gin.Context) {
 
 	err = h.adminService.DeleteProduct(c.Request.Context(),uint(productID))
 	if err != nil {
-		utils.SendError(c, http.StatusBadRequest, "Failed to delete product", err)
+		if errors.Is(err, services.ErrProductHasReferences) {
+			utils.SendError(c, http.StatusConflict, "Product cannot be deleted, archive it instead", err)
+			return
+		}
+		sendServiceError(c, "Failed to delete product", err)
 		return
 	}
 
 	utils.SendSuccess(c, "Product deleted successfully", nil)
 }
 
+// GetProductDeleteCheck reports the records referencing a product and whether it can be deleted
+func (h *AdminHandler) GetProductDeleteCheck(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return
+	}
+
+	report, err := h.adminService.GetProductReferences(c.Request.Context(), uint(productID))
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			utils.SendError(c, http.StatusNotFound, "Product not found", err)
+			return
+		}
+		utils.SendInternalError(c, "Failed to check product references", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Product references retrieved successfully", report)
+}
+
+// ArchiveProduct hides a product from the storefront without deleting it
+func (h *AdminHandler) ArchiveProduct(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return
+	}
+
+	product, err := h.adminService.ArchiveProduct(c.Request.Context(), uint(productID))
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			utils.SendError(c, http.StatusNotFound, "Product not found", err)
+			return
+		}
+		utils.SendInternalError(c, "Failed to archive product", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Product archived successfully", product)
+}
+
+// DuplicateProduct copies a product into a new draft; the body is optional
+func (h *AdminHandler) DuplicateProduct(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return
+	}
+
+	var req models.DuplicateProductRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.SendValidationError(c, "Invalid JSON data: "+err.Error())
+			return
+		}
+	}
+
+	product, err := h.adminService.DuplicateProduct(c.Request.Context(), uint(productID), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrProductNotFound):
+			utils.SendError(c, http.StatusNotFound, "Product not found", err)
+		case errors.Is(err, services.ErrInvalidInput):
+			utils.SendError(c, http.StatusUnprocessableEntity, "Failed to duplicate product", err)
+		case errors.Is(err, services.ErrS3Upload):
+			utils.SendError(c, http.StatusBadGateway, "Failed to copy product images", err)
+		default:
+			utils.SendInternalError(c, "Failed to duplicate product", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Product duplicated successfully",
+		Data:    product,
+	})
+}
+
 func (h *AdminHandler) GetDashboard(c *gin.Context) {
-	stats, err := h.adminService.GetDashboardStats()
+	stats, err := h.adminService.GetDashboardStats(c.Request.Context(), c.Query("refresh") == "true")
 	if err != nil {
 		utils.SendInternalError(c, "Failed to fetch dashboard stats", err)
 		return
@@ -389,15 +621,7 @@ func (h *AdminHandler) SearchProducts(c *gin.Context) {
 	query := c.Query("q")
 	category := c.Query("category")
 	brand := c.Query("brand")
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 || limit > 100 {
-		limit = 20
-	}
+	page, limit := utils.ParsePagination(c, 20)
 
 	searchParams := map[string]interface{}{
 		"query":    query,
@@ -405,24 +629,56 @@ func (h *AdminHandler) SearchProducts(c *gin.Context) {
 		"brand":    brand,
 		"page":     page,
 		"limit":    limit,
+		"scope":    productScope(c),
+	}
+
+	proj, err := productProjection(c, services.ExpandImages, services.ExpandReviews)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid fields or expand parameter", err)
+		return
 	}
 
-	// You'll need to add this method to AdminService
-	products, total, err := h.adminService.SearchProducts(searchParams)
+	products, total, err := h.adminService.SearchProducts(c.Request.Context(), searchParams, proj)
 	if err != nil {
 		utils.SendInternalError(c, "Failed to search products", err)
 		return
 	}
 
-	response := map[string]interface{}{
-		"products": products,
-		"pagination": map[string]interface{}{
-			"page":       page,
-			"limit":      limit,
-			"total":      total,
-			"total_pages": (total + limit - 1) / limit,
-		},
+	response := types.NewPaginated("products", products, page, limit, int64(total))
+	shaped, err := proj.ShapePage(&response)
+	if err != nil {
+		utils.SendInternalError(c, "Failed to search products", err)
+		return
 	}
 
-	utils.SendSuccess(c, "Products search completed", response)
-}
\ No newline at end of file
+	utils.SendSuccess(c, "Products search completed", shaped)
+}
+
+// GetProductQualityReport lists products missing images, descriptions,
+// categories or price history, or active without stock, worst first.
+// ?issue= narrows the list to one issue.
+func (h *AdminHandler) GetProductQualityReport(c *gin.Context) {
+	page, limit := utils.ParsePagination(c, 20)
+
+	report, err := h.adminService.GetProductQualityReport(c.Request.Context(), productScope(c), c.Query("issue"), page, limit)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidInput) {
+			utils.SendError(c, http.StatusBadRequest, "Invalid quality report filter", err)
+			return
+		}
+		utils.SendInternalError(c, "Failed to build product quality report", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Product quality report retrieved successfully", gin.H{
+		"counts":     report.Counts,
+		"products":   report.Products,
+		"pagination": types.NewPaginated("products", report.Products, page, limit, report.Total).Pagination,
+	})
+}
+
+// productScope limits admin product queries to the resolved store and, for
+// vendor users, their vendor
+func productScope(c *gin.Context) services.ProductScope {
+	return services.ProductScope{StoreID: c.GetUint("store_id"), VendorID: c.GetUint("vendor_id")}
+}
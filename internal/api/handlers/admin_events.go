@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+)
+
+type AdminEventHandler struct {
+	hub      *services.AdminEventHub
+	upgrader *websocket.Upgrader
+}
+
+func NewAdminEventHandler(hub *services.AdminEventHub, cfg *config.Config) *AdminEventHandler {
+	return &AdminEventHandler{hub: hub, upgrader: newSocketUpgrader(cfg)}
+}
+
+// StreamEvents pushes live domain events (new reviews, flagged content, low
+// stock, catalogue changes) to the admin dashboard. WebSocket upgrades get a
+// socket; any other request is served as Server-Sent Events. ?types= limits
+// the feed to a comma-separated list of event types.
+func (h *AdminEventHandler) StreamEvents(c *gin.Context) {
+	var types []string
+	if raw := c.Query("types"); raw != "" {
+		for _, eventType := range strings.Split(raw, ",") {
+			eventType = strings.TrimSpace(eventType)
+			if !isKnownEventType(eventType) {
+				utils.SendValidationError(c, fmt.Sprintf("Unknown event type %q", eventType))
+				return
+			}
+			types = append(types, eventType)
+		}
+	}
+
+	if strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+		h.streamSocket(c, types)
+		return
+	}
+	h.streamSSE(c, types)
+}
+
+func (h *AdminEventHandler) streamSSE(c *gin.Context, types []string) {
+	events, unsubscribe := h.hub.Subscribe(types)
+	defer unsubscribe()
+
+	startSSE(c)
+	ticker := time.NewTicker(sseHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeSSE(c, event.ID, event.Type, event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := writeSSEHeartbeat(c); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (h *AdminEventHandler) streamSocket(c *gin.Context, types []string) {
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.Error("Failed to upgrade admin event stream: ", err)
+		return
+	}
+	defer conn.Close()
+
+	events, unsubscribe := h.hub.Subscribe(types)
+	defer unsubscribe()
+
+	// Reader loop: only needed to process pongs and notice disconnects
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadDeadline(time.Now().Add(jobSocketPongTimeout))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(jobSocketPongTimeout))
+		})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(jobSocketPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(jobSocketWriteTimeout))
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(jobSocketWriteTimeout))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func isKnownEventType(eventType string) bool {
+	for _, known := range services.KnownEventTypes {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type AdminNoteHandler struct {
+	noteService *services.AdminNoteService
+}
+
+func NewAdminNoteHandler(noteService *services.AdminNoteService) *AdminNoteHandler {
+	return &AdminNoteHandler{noteService: noteService}
+}
+
+func (h *AdminNoteHandler) GetProductNotes(c *gin.Context) {
+	h.getNotes(c, models.NoteSubjectProduct, "product_id")
+}
+
+func (h *AdminNoteHandler) CreateProductNote(c *gin.Context) {
+	h.createNote(c, models.NoteSubjectProduct, "product_id")
+}
+
+func (h *AdminNoteHandler) GetUserNotes(c *gin.Context) {
+	h.getNotes(c, models.NoteSubjectUser, "user_id")
+}
+
+func (h *AdminNoteHandler) CreateUserNote(c *gin.Context) {
+	h.createNote(c, models.NoteSubjectUser, "user_id")
+}
+
+func (h *AdminNoteHandler) UpdateNote(c *gin.Context) {
+	id, ok := parseUintParam(c, "note_id", "Invalid note ID")
+	if !ok {
+		return
+	}
+
+	var req models.AdminNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	note, err := h.noteService.UpdateNote(c.Request.Context(), id, c.GetUint("user_id"), req.Body)
+	if err != nil {
+		sendAdminNoteError(c, "Failed to update note", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Note updated successfully", note)
+}
+
+func (h *AdminNoteHandler) DeleteNote(c *gin.Context) {
+	id, ok := parseUintParam(c, "note_id", "Invalid note ID")
+	if !ok {
+		return
+	}
+
+	if err := h.noteService.DeleteNote(c.Request.Context(), id); err != nil {
+		sendAdminNoteError(c, "Failed to delete note", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Note deleted successfully", nil)
+}
+
+func (h *AdminNoteHandler) getNotes(c *gin.Context, subjectType, param string) {
+	subjectID, ok := parseUintParam(c, param, "Invalid "+subjectType+" ID")
+	if !ok {
+		return
+	}
+
+	notes, err := h.noteService.GetNotes(c.Request.Context(), subjectType, subjectID)
+	if err != nil {
+		sendAdminNoteError(c, "Failed to fetch notes", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Notes retrieved successfully", gin.H{"notes": notes})
+}
+
+func (h *AdminNoteHandler) createNote(c *gin.Context, subjectType, param string) {
+	subjectID, ok := parseUintParam(c, param, "Invalid "+subjectType+" ID")
+	if !ok {
+		return
+	}
+
+	var req models.AdminNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	note, err := h.noteService.CreateNote(c.Request.Context(), subjectType, subjectID, c.GetUint("user_id"), req.Body)
+	if err != nil {
+		sendAdminNoteError(c, "Failed to create note", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Note created successfully",
+		Data:    note,
+	})
+}
+
+func parseUintParam(c *gin.Context, name, message string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, message)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func sendAdminNoteError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrAdminNoteNotFound):
+		utils.SendError(c, http.StatusNotFound, "Note not found", err)
+	case errors.Is(err, services.ErrProductNotFound):
+		utils.SendError(c, http.StatusNotFound, "Product not found", err)
+	case errors.Is(err, services.ErrUserNotFound):
+		utils.SendError(c, http.StatusNotFound, "User not found", err)
+	case errors.Is(err, services.ErrAdminNoteForbidden):
+		utils.SendForbidden(c, "Only the author can edit a note")
+	case errors.Is(err, services.ErrInvalidInput):
+		utils.SendError(c, http.StatusUnprocessableEntity, message, err)
+	default:
+		sendServiceError(c, message, err)
+	}
+}
@@ -0,0 +1,137 @@
+package handlers_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/api/handlers"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/testutil"
+)
+
+// Requests rejected while binding never reach the service, so these run
+// without a database
+func TestCreateProductRejectsInvalidRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/products", handlers.NewAdminHandler(nil).CreateProduct)
+
+	jsonBody := func(body string) (io.Reader, map[string]string) {
+		return strings.NewReader(body), map[string]string{"Content-Type": "application/json"}
+	}
+	formBody := func(fields map[string]string) (io.Reader, map[string]string) {
+		return testutil.Multipart(t, fields)
+	}
+
+	tests := []struct {
+		name string
+		body func() (io.Reader, map[string]string)
+	}{
+		{"json without title", func() (io.Reader, map[string]string) {
+			return jsonBody(`{"price": 10}`)
+		}},
+		{"json with zero price", func() (io.Reader, map[string]string) {
+			return jsonBody(`{"title": "Mug", "price": 0}`)
+		}},
+		{"json with negative stock", func() (io.Reader, map[string]string) {
+			return jsonBody(`{"title": "Mug", "price": 10, "stock": -1}`)
+		}},
+		{"json service without link", func() (io.Reader, map[string]string) {
+			return jsonBody(`{"title": "Mug", "price": 10, "services": [{"name": "Engraving"}]}`)
+		}},
+		{"multipart without price", func() (io.Reader, map[string]string) {
+			return formBody(map[string]string{"title": "Mug"})
+		}},
+		{"multipart with unknown status", func() (io.Reader, map[string]string) {
+			return formBody(map[string]string{"title": "Mug", "price": "10", "status": "archived"})
+		}},
+		{"multipart with malformed services", func() (io.Reader, map[string]string) {
+			return formBody(map[string]string{"title": "Mug", "price": "10", "services": "[{"})
+		}},
+		{"multipart service without link", func() (io.Reader, map[string]string) {
+			return formBody(map[string]string{"title": "Mug", "price": "10", "services": `[{"name": "Engraving"}]`})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, headers := tt.body()
+			req := httptest.NewRequest(http.MethodPost, "/products", body)
+			for name, value := range headers {
+				req.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("expected status 400, got %d: %s", rec.Code, rec.Body)
+			}
+		})
+	}
+}
+
+func TestCreateProduct(t *testing.T) {
+	env := testutil.NewEnv(t)
+	token := env.Login(t, env.CreateAdmin(t))
+
+	t.Run("json", func(t *testing.T) {
+		var product models.Product
+		env.Do(t, http.MethodPost, "/api/v1/admin/products", map[string]interface{}{
+			"title":    "Ceramic mug",
+			"sku":      "HANDLER-JSON-1",
+			"price":    19.99,
+			"stock":    5,
+			"category": "kitchen",
+			"material": "ceramic",
+			"status":   "inactive",
+			"services": []map[string]string{{"name": "Engraving", "link": "https://example.com/engraving"}},
+		}, token).ExpectStatus(t, http.StatusOK).Decode(t, &product)
+
+		if product.ID == 0 || product.Title != "Ceramic mug" || product.SKU != "HANDLER-JSON-1" {
+			t.Fatalf("unexpected product %+v", product)
+		}
+		if product.Price != 19.99 || product.Stock != 5 || product.Material != "ceramic" || product.Status != "inactive" {
+			t.Fatalf("fields were not stored as sent: %+v", product)
+		}
+		if len(product.Services) != 1 || product.Services[0].Name != "Engraving" {
+			t.Fatalf("expected the engraving service, got %+v", product.Services)
+		}
+		if len(product.Images) != 0 {
+			t.Fatalf("expected no images, got %d", len(product.Images))
+		}
+	})
+
+	t.Run("multipart", func(t *testing.T) {
+		body, headers := testutil.Multipart(t, map[string]string{
+			"title":    "Glass tumbler",
+			"sku":      "HANDLER-FORM-1",
+			"price":    "24.50",
+			"stock":    "3",
+			"category": "kitchen",
+			"services": `[{"name": "Gift wrap", "link": "https://example.com/gift-wrap"}]`,
+		}, testutil.FormFile{
+			Field:       "images",
+			Name:        "tumbler.png",
+			ContentType: "image/png",
+			Data:        testutil.PNG(t, 8, 8, 10),
+		})
+
+		var product models.Product
+		env.Do(t, http.MethodPost, "/api/v1/admin/products", body, token, headers).
+			ExpectStatus(t, http.StatusOK).Decode(t, &product)
+
+		if product.Title != "Glass tumbler" || product.Price != 24.50 || product.Stock != 3 || product.Status != "active" {
+			t.Fatalf("fields were not stored as sent: %+v", product)
+		}
+		if len(product.Services) != 1 || product.Services[0].Name != "Gift wrap" {
+			t.Fatalf("expected the gift wrap service, got %+v", product.Services)
+		}
+		if len(product.Images) != 1 || product.Images[0].ContentType != "image/png" || product.Images[0].S3Key == "" {
+			t.Fatalf("expected one stored PNG, got %+v", product.Images)
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type AdminUserHandler struct {
+	authService *services.AuthService
+}
+
+func NewAdminUserHandler(authService *services.AuthService) *AdminUserHandler {
+	return &AdminUserHandler{authService: authService}
+}
+
+// UpdateUserRole promotes a customer to admin or demotes an admin. The user
+// has to sign in again for the new role to apply.
+func (h *AdminUserHandler) UpdateUserRole(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid user ID")
+		return
+	}
+
+	var req models.UpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	user, err := h.authService.UpdateUserRole(c.Request.Context(), c.GetUint("user_id"), uint(userID), req.Role, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUserNotFound):
+			utils.SendError(c, http.StatusNotFound, "User not found", err)
+		default:
+			sendServiceError(c, "Failed to update user role", err)
+		}
+		return
+	}
+
+	utils.SendSuccess(c, "User role updated successfully", user)
+}
+
+// RestoreUser reactivates a deactivated or merged account
+func (h *AdminUserHandler) RestoreUser(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid user ID")
+		return
+	}
+
+	user, err := h.authService.RestoreUser(c.Request.Context(), c.GetUint("user_id"), uint(userID), c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		sendServiceError(c, "Failed to restore user", err)
+		return
+	}
+
+	utils.SendSuccess(c, "User restored successfully", user)
+}
+
+// MergeUsers moves the records of a duplicate account onto the account in
+// the URL and deactivates the duplicate
+func (h *AdminUserHandler) MergeUsers(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid user ID")
+		return
+	}
+
+	var req models.MergeUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	result, err := h.authService.MergeUsers(c.Request.Context(), c.GetUint("user_id"), uint(userID), req.DuplicateUserID, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		sendServiceError(c, "Failed to merge users", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Users merged successfully", result)
+}
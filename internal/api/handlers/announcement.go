@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type AnnouncementHandler struct {
+	announcementService *services.AnnouncementService
+}
+
+func NewAnnouncementHandler(announcementService *services.AnnouncementService) *AnnouncementHandler {
+	return &AnnouncementHandler{announcementService: announcementService}
+}
+
+// GetActiveAnnouncements returns the announcements visible to the caller right now
+func (h *AnnouncementHandler) GetActiveAnnouncements(c *gin.Context) {
+	announcements, err := h.announcementService.GetActiveAnnouncements(c.Request.Context(), c.GetUint("store_id"), c.GetString("user_role"))
+	if err != nil {
+		utils.SendInternalError(c, "Failed to fetch announcements", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Announcements retrieved successfully", announcements)
+}
+
+func (h *AnnouncementHandler) GetAnnouncements(c *gin.Context) {
+	announcements, err := h.announcementService.GetAnnouncements(c.Request.Context(), c.GetUint("store_id"))
+	if err != nil {
+		utils.SendInternalError(c, "Failed to fetch announcements", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Announcements retrieved successfully", announcements)
+}
+
+func (h *AnnouncementHandler) CreateAnnouncement(c *gin.Context) {
+	var req models.CreateAnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	announcement, err := h.announcementService.CreateAnnouncement(c.Request.Context(), c.GetUint("store_id"), c.GetUint("user_id"), &req)
+	if err != nil {
+		sendServiceError(c, "Failed to create announcement", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Announcement created successfully", announcement)
+}
+
+func (h *AnnouncementHandler) UpdateAnnouncement(c *gin.Context) {
+	announcementID, err := strconv.ParseUint(c.Param("announcement_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid announcement ID")
+		return
+	}
+
+	var req models.UpdateAnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	announcement, err := h.announcementService.UpdateAnnouncement(c.Request.Context(), c.GetUint("store_id"), uint(announcementID), &req)
+	if err != nil {
+		sendServiceError(c, "Failed to update announcement", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Announcement updated successfully", announcement)
+}
+
+func (h *AnnouncementHandler) DeleteAnnouncement(c *gin.Context) {
+	announcementID, err := strconv.ParseUint(c.Param("announcement_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid announcement ID")
+		return
+	}
+
+	if err := h.announcementService.DeleteAnnouncement(c.Request.Context(), c.GetUint("store_id"), uint(announcementID)); err != nil {
+		if errors.Is(err, services.ErrAnnouncementNotFound) {
+			utils.SendError(c, http.StatusNotFound, "Announcement not found", err)
+			return
+		}
+		utils.SendInternalError(c, "Failed to delete announcement", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Announcement deleted successfully", nil)
+}
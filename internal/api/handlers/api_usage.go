@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type APIUsageHandler struct {
+	apiUsageService *services.APIUsageService
+}
+
+func NewAPIUsageHandler(apiUsageService *services.APIUsageService) *APIUsageHandler {
+	return &APIUsageHandler{apiUsageService: apiUsageService}
+}
+
+// GetUsageReport returns call counts and costs of metered external APIs
+func (h *APIUsageHandler) GetUsageReport(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil {
+		utils.SendValidationError(c, "Invalid days parameter")
+		return
+	}
+
+	report, err := h.apiUsageService.GetUsageReport(c.Request.Context(), days)
+	if err != nil {
+		sendServiceError(c, "Failed to fetch external API usage", err)
+		return
+	}
+
+	utils.SendSuccess(c, "External API usage retrieved successfully", report)
+}
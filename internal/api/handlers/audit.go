@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/types"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type AuditHandler struct {
+	auditService *services.AuditService
+}
+
+func NewAuditHandler(auditService *services.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// GetAuditLogs lists audit entries, newest first. Filter with ?actor_id=,
+// ?user_id= and ?action=.
+func (h *AuditHandler) GetAuditLogs(c *gin.Context) {
+	page, limit := utils.ParsePagination(c, 50)
+
+	filter := services.AuditLogFilter{Action: c.Query("action")}
+	if actorID := c.Query("actor_id"); actorID != "" {
+		id, err := strconv.ParseUint(actorID, 10, 32)
+		if err != nil {
+			utils.SendValidationError(c, "Invalid actor_id")
+			return
+		}
+		filter.ActorID = uint(id)
+	}
+	if userID := c.Query("user_id"); userID != "" {
+		id, err := strconv.ParseUint(userID, 10, 32)
+		if err != nil {
+			utils.SendValidationError(c, "Invalid user_id")
+			return
+		}
+		filter.SubjectUserID = uint(id)
+	}
+
+	entries, total, err := h.auditService.GetAuditLogs(c.Request.Context(), filter, page, limit)
+	if err != nil {
+		utils.SendInternalError(c, "Failed to fetch audit log", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Audit log retrieved successfully", types.NewPaginated("entries", entries, page, limit, total))
+}
@@ -1,19 +1,71 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 	// "strconv"
 	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
 	"github.com/princeprakhar/ecommerce-backend/internal/services"
 	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
 )
 
 type AuthHandler struct {
 	authService *services.AuthService
+	cartService *services.CartService
+	cfg         *config.Config
 }
 
-func NewAuthHandler(authService *services.AuthService) *AuthHandler {
-	return &AuthHandler{authService: authService}
+func NewAuthHandler(authService *services.AuthService, cartService *services.CartService, cfg *config.Config) *AuthHandler {
+	return &AuthHandler{authService: authService, cartService: cartService, cfg: cfg}
+}
+
+// useCookies reports whether tokens go in httpOnly cookies instead of the
+// response body, per AUTH_COOKIE_MODE and the X-Auth-Mode request header.
+func (h *AuthHandler) useCookies(c *gin.Context) bool {
+	switch strings.ToLower(h.cfg.AuthCookieMode) {
+	case "always":
+		return true
+	case "optional":
+		return strings.EqualFold(c.GetHeader("X-Auth-Mode"), "cookie")
+	default:
+		return false
+	}
+}
+
+func (h *AuthHandler) cookieOptions() utils.CookieOptions {
+	return cookieOptions(h.cfg)
+}
+
+// mergeCart moves the visitor's anonymous cart, if any, into the cart of the
+// user who just signed in. A failed merge leaves the anonymous cart in place
+// and doesn't fail the sign-in.
+func (h *AuthHandler) mergeCart(c *gin.Context, userID uint) {
+	token := cartToken(c)
+	if token == "" {
+		return
+	}
+	if err := h.cartService.MergeAnonymousCart(c.Request.Context(), userID, token); err != nil {
+		logger.Error("Failed to merge anonymous cart: ", err)
+		return
+	}
+	utils.ClearCartCookie(c, h.cookieOptions())
+}
+
+// refreshTokenFromRequest prefers a token in the JSON body and falls back to
+// the refresh cookie.
+func (h *AuthHandler) refreshTokenFromRequest(c *gin.Context) (string, error) {
+	var req services.RefreshRequest
+	err := c.ShouldBindJSON(&req)
+	if err == nil {
+		return req.RefreshToken, nil
+	}
+	if cookie, cookieErr := c.Cookie(utils.RefreshTokenCookie); cookieErr == nil && cookie != "" {
+		return cookie, nil
+	}
+	return "", err
 }
 
 func (h *AuthHandler) Signup(c *gin.Context) {
@@ -22,12 +74,36 @@ func (h *AuthHandler) Signup(c *gin.Context) {
 		utils.SendValidationError(c, "Invalid request data")
 		return
 	}
+	req.StoreID = c.GetUint("store_id")
+	req.Origin = services.SignupOrigin{
+		IPAddress: c.ClientIP(),
+		DeviceID:  c.GetHeader(DeviceIDHeader),
+		UserAgent: c.Request.UserAgent(),
+		Country:   c.GetString("geo_country"),
+	}
 
 	response, err := h.authService.Signup(req)
 	if err != nil {
-		utils.SendError(c, http.StatusBadRequest, "Signup failed", err)
+		switch {
+		case errors.Is(err, services.ErrUserAlreadyExists):
+			utils.SendError(c, http.StatusConflict, "Signup failed", err)
+		case errors.Is(err, services.ErrOTPNotVerified):
+			utils.SendError(c, http.StatusForbidden, "Signup failed", err)
+		default:
+			sendServiceError(c, "Signup failed", err)
+		}
 		return
 	}
+	h.mergeCart(c, response.User.ID)
+
+	if h.useCookies(c) {
+		if err := utils.SetAuthCookies(c, h.cookieOptions(), response.Token.AccessToken, response.Token.AccessTokenExpiresAt, response.Token.RefreshToken, response.Token.RefreshTokenExpiresAt); err != nil {
+			utils.SendInternalError(c, "Failed to set auth cookies", err)
+			return
+		}
+		response.Token.AccessToken = ""
+		response.Token.RefreshToken = ""
+	}
 
 	utils.SendSuccess(c, "User created successfully", response)
 }
@@ -38,12 +114,26 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		utils.SendValidationError(c, "Invalid request data")
 		return
 	}
+	req.StoreID = c.GetUint("store_id")
 
 	response, err := h.authService.Login(req)
 	if err != nil {
+		if sendPasswordExpired(c, err) {
+			return
+		}
 		utils.SendError(c, http.StatusUnauthorized, "Login failed", err)
 		return
 	}
+	h.mergeCart(c, response.User.ID)
+
+	if h.useCookies(c) {
+		if err := utils.SetAuthCookies(c, h.cookieOptions(), response.Token.AccessToken, response.Token.AccessTokenExpiresAt, response.Token.RefreshToken, response.Token.RefreshTokenExpiresAt); err != nil {
+			utils.SendInternalError(c, "Failed to set auth cookies", err)
+			return
+		}
+		response.Token.AccessToken = ""
+		response.Token.RefreshToken = ""
+	}
 
 	utils.SendSuccess(c, "Login successful", response)
 }
@@ -60,9 +150,27 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 	utils.SendSuccess(c, "Profile retrieved successfully", user)
 }
 
-func (h *AuthHandler) RefreshToken(c *gin.Context) {
-	var req services.RefreshRequest
+// IssueStreamTicket returns a short-lived ticket for opening one stream,
+// passed as ?ticket= where the browser cannot send the Authorization header
+func (h *AuthHandler) IssueStreamTicket(c *gin.Context) {
+	var req services.StreamTicketRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	ticket, err := h.authService.IssueStreamTicket(c.Request.Context(), c.GetUint("user_id"), c.GetUint("impersonator_id"), &req)
+	if err != nil {
+		sendServiceError(c, "Failed to issue stream ticket", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Stream ticket issued", ticket)
+}
+
+func (h *AuthHandler) RefreshToken(c *gin.Context) {
+	refreshToken, err := h.refreshTokenFromRequest(c)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
 			"message": "Invalid request",
@@ -71,8 +179,11 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	response, err := h.authService.RefreshToken(req)
+	response, err := h.authService.RefreshToken(services.RefreshRequest{RefreshToken: refreshToken, StoreID: c.GetUint("store_id")})
 	if err != nil {
+		if sendPasswordExpired(c, err) {
+			return
+		}
 		status := http.StatusUnauthorized
 		if err.Error() == "invalid request" {
 			status = http.StatusBadRequest
@@ -85,6 +196,15 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
+	if h.useCookies(c) {
+		if err := utils.SetAuthCookies(c, h.cookieOptions(), response.Token.AccessToken, response.Token.AccessTokenExpiresAt, response.Token.RefreshToken, response.Token.RefreshTokenExpiresAt); err != nil {
+			utils.SendInternalError(c, "Failed to set auth cookies", err)
+			return
+		}
+		response.Token.AccessToken = ""
+		response.Token.RefreshToken = ""
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Token refreshed successfully",
@@ -92,9 +212,25 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	})
 }
 
+// sendPasswordExpired answers with the change-password token when err is a
+// PasswordExpiredError, and reports whether it did
+func sendPasswordExpired(c *gin.Context, err error) bool {
+	var expired *services.PasswordExpiredError
+	if !errors.As(err, &expired) {
+		return false
+	}
+	c.JSON(http.StatusForbidden, utils.APIResponse{
+		Success: false,
+		Message: "Password expired, change it to continue",
+		Data:    expired,
+		Error:   "password_expired",
+	})
+	return true
+}
+
 func (h *AuthHandler) Logout(c *gin.Context) {
-	var req services.RefreshRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	refreshToken, err := h.refreshTokenFromRequest(c)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
 			"message": "Invalid request",
@@ -103,7 +239,10 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		return
 	}
 
-	if err := h.authService.Logout(req.RefreshToken); err != nil {
+	// Always clear cookies, even if the client is in header mode now
+	utils.ClearAuthCookies(c, h.cookieOptions())
+
+	if err := h.authService.Logout(refreshToken); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"message": "Logout failed",
@@ -129,11 +268,38 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 	}
 
 	userID := c.GetUint("user_id")
-	response, err := h.authService.UpdateProfile(userID, req)
+	response, err := h.authService.UpdateProfile(c.Request.Context(), userID, req)
 	if err != nil {
-		utils.SendError(c, http.StatusBadRequest, "Profile update failed", err)
+		sendProfileError(c, err)
 		return
 	}
 
 	utils.SendSuccess(c, "Profile updated successfully", response)
-}
\ No newline at end of file
+}
+
+// sendProfileError answers profile update conflicts with 409 and an error
+// code clients can switch on: email_taken, or version_conflict along with the
+// stored profile
+func sendProfileError(c *gin.Context, err error) {
+	var conflict *services.ProfileConflictError
+	switch {
+	case errors.Is(err, services.ErrEmailTaken):
+		c.JSON(http.StatusConflict, utils.APIResponse{
+			Success: false,
+			Message: "Email is already in use",
+			Data:    gin.H{"field": "email"},
+			Error:   "email_taken",
+		})
+	case errors.As(err, &conflict):
+		c.JSON(http.StatusConflict, utils.APIResponse{
+			Success: false,
+			Message: "Profile was changed by another request, reload and try again",
+			Data:    conflict,
+			Error:   "version_conflict",
+		})
+	case errors.Is(err, services.ErrOTPNotVerified):
+		utils.SendError(c, http.StatusForbidden, "Verify the current and the new phone number first", err)
+	default:
+		sendServiceError(c, "Profile update failed", err)
+	}
+}
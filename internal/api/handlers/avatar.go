@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type AvatarHandler struct {
+	avatarService *services.AvatarService
+}
+
+func NewAvatarHandler(avatarService *services.AvatarService) *AvatarHandler {
+	return &AvatarHandler{avatarService: avatarService}
+}
+
+// UploadAvatar replaces the signed-in user's avatar with the "avatar" form file
+func (h *AvatarHandler) UploadAvatar(c *gin.Context) {
+	file, err := c.FormFile("avatar")
+	if err != nil {
+		utils.SendValidationError(c, "No avatar file provided")
+		return
+	}
+	if file.Size > services.MaxAvatarFileBytes {
+		utils.SendValidationError(c, "Avatar file is too large")
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		utils.SendValidationError(c, "Failed to open file")
+		return
+	}
+	defer src.Close()
+
+	user, err := h.avatarService.SetAvatar(c.Request.Context(), c.GetUint("user_id"), src)
+	if err != nil {
+		sendAvatarError(c, "Failed to upload avatar", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Avatar uploaded successfully", user)
+}
+
+func (h *AvatarHandler) DeleteAvatar(c *gin.Context) {
+	user, err := h.avatarService.DeleteAvatar(c.Request.Context(), c.GetUint("user_id"))
+	if err != nil {
+		sendAvatarError(c, "Failed to delete avatar", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Avatar deleted successfully", user)
+}
+
+func sendAvatarError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		utils.SendError(c, http.StatusNotFound, "User not found", err)
+	case errors.Is(err, services.ErrInvalidAvatar):
+		utils.SendError(c, http.StatusBadRequest, message, err)
+	default:
+		sendServiceError(c, message, err)
+	}
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type BannerHandler struct {
+	bannerService *services.BannerService
+}
+
+func NewBannerHandler(bannerService *services.BannerService) *BannerHandler {
+	return &BannerHandler{bannerService: bannerService}
+}
+
+// GetActiveBanners returns the banners currently showing, keyed by placement.
+// ?placement= limits the result to one placement.
+func (h *BannerHandler) GetActiveBanners(c *gin.Context) {
+	placement := c.Query("placement")
+	if placement != "" && !containsPlacement(placement) {
+		utils.SendValidationError(c, "Invalid placement")
+		return
+	}
+
+	banners, err := h.bannerService.GetActiveBanners(c.Request.Context(), c.GetUint("store_id"), placement)
+	if err != nil {
+		sendBannerError(c, "Failed to fetch banners", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Banners retrieved successfully", gin.H{"banners": banners})
+}
+
+func (h *BannerHandler) GetBanners(c *gin.Context) {
+	banners, err := h.bannerService.GetBanners(c.Request.Context(), c.GetUint("store_id"))
+	if err != nil {
+		sendBannerError(c, "Failed to fetch banners", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Banners retrieved successfully", gin.H{"banners": banners})
+}
+
+func (h *BannerHandler) GetBanner(c *gin.Context) {
+	id, ok := parseBannerID(c)
+	if !ok {
+		return
+	}
+
+	banner, err := h.bannerService.GetBanner(c.Request.Context(), c.GetUint("store_id"), id)
+	if err != nil {
+		sendBannerError(c, "Failed to fetch banner", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Banner retrieved successfully", banner)
+}
+
+func (h *BannerHandler) CreateBanner(c *gin.Context) {
+	var req models.CreateBannerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	banner, err := h.bannerService.CreateBanner(c.Request.Context(), c.GetUint("store_id"), c.GetUint("user_id"), &req)
+	if err != nil {
+		sendBannerError(c, "Failed to create banner", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Banner created successfully",
+		Data:    banner,
+	})
+}
+
+func (h *BannerHandler) UpdateBanner(c *gin.Context) {
+	id, ok := parseBannerID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateBannerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	banner, err := h.bannerService.UpdateBanner(c.Request.Context(), c.GetUint("store_id"), id, &req)
+	if err != nil {
+		sendBannerError(c, "Failed to update banner", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Banner updated successfully", banner)
+}
+
+// UploadBannerImage sets a banner's image from the "image" form file
+func (h *BannerHandler) UploadBannerImage(c *gin.Context) {
+	id, ok := parseBannerID(c)
+	if !ok {
+		return
+	}
+
+	header, err := c.FormFile("image")
+	if err != nil {
+		utils.SendValidationError(c, "No image file provided")
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		utils.SendValidationError(c, "Failed to open file")
+		return
+	}
+	defer file.Close()
+
+	banner, err := h.bannerService.SetImage(c.Request.Context(), c.GetUint("store_id"), id, file, header)
+	if err != nil {
+		sendBannerError(c, "Failed to upload banner image", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Banner image uploaded successfully", banner)
+}
+
+func (h *BannerHandler) DeleteBanner(c *gin.Context) {
+	id, ok := parseBannerID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.bannerService.DeleteBanner(c.Request.Context(), c.GetUint("store_id"), id); err != nil {
+		sendBannerError(c, "Failed to delete banner", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Banner deleted successfully", nil)
+}
+
+func containsPlacement(placement string) bool {
+	for _, p := range services.BannerPlacements {
+		if p == placement {
+			return true
+		}
+	}
+	return false
+}
+
+func parseBannerID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("banner_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid banner ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func sendBannerError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrBannerNotFound):
+		utils.SendError(c, http.StatusNotFound, "Banner not found", err)
+	case errors.Is(err, services.ErrInvalidInput):
+		utils.SendError(c, http.StatusUnprocessableEntity, message, err)
+	default:
+		sendServiceError(c, message, err)
+	}
+}
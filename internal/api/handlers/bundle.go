@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type BundleHandler struct {
+	bundleService *services.BundleService
+}
+
+func NewBundleHandler(bundleService *services.BundleService) *BundleHandler {
+	return &BundleHandler{bundleService: bundleService}
+}
+
+// GetBundle returns the contents, price and availability of a bundle
+func (h *BundleHandler) GetBundle(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return
+	}
+
+	bundle, err := h.bundleService.GetBundle(c.Request.Context(), uint(productID))
+	if err != nil {
+		sendBundleError(c, "Failed to fetch bundle", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Bundle retrieved successfully", bundle)
+}
+
+// SetBundle makes a product a bundle, replacing its previous contents
+func (h *BundleHandler) SetBundle(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return
+	}
+
+	var req models.SetProductBundleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	bundle, err := h.bundleService.SetBundle(c.Request.Context(), uint(productID), c.GetUint("vendor_id"), &req)
+	if err != nil {
+		sendBundleError(c, "Failed to save bundle", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Bundle saved successfully", bundle)
+}
+
+// DeleteBundle turns a bundle back into a plain product
+func (h *BundleHandler) DeleteBundle(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return
+	}
+
+	if err := h.bundleService.DeleteBundle(c.Request.Context(), uint(productID)); err != nil {
+		sendBundleError(c, "Failed to delete bundle", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Bundle deleted successfully", nil)
+}
+
+func sendBundleError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrProductNotFound):
+		utils.SendError(c, http.StatusNotFound, "Product not found", err)
+	case errors.Is(err, services.ErrBundleNotFound):
+		utils.SendError(c, http.StatusNotFound, "Bundle not found", err)
+	case errors.Is(err, services.ErrInvalidInput):
+		utils.SendError(c, http.StatusUnprocessableEntity, message, err)
+	default:
+		sendServiceError(c, message, err)
+	}
+}
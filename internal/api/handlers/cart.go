@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+// CartTokenHeader carries an anonymous cart's token for clients that don't
+// keep cookies. The cart_token cookie is used otherwise.
+const CartTokenHeader = "X-Cart-Token"
+
+type CartHandler struct {
+	cartService *services.CartService
+	cfg         *config.Config
+}
+
+func NewCartHandler(cartService *services.CartService, cfg *config.Config) *CartHandler {
+	return &CartHandler{cartService: cartService, cfg: cfg}
+}
+
+func (h *CartHandler) GetCart(c *gin.Context) {
+	cart, err := h.cartService.GetCart(c.Request.Context(), cartOwner(c))
+	if err != nil {
+		sendServiceError(c, "Failed to fetch cart", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Cart retrieved successfully", cart)
+}
+
+// AddItem adds a product to the caller's cart. A visitor without a cart gets
+// an anonymous one, whose token is returned in the response and in the
+// cart_token cookie.
+func (h *CartHandler) AddItem(c *gin.Context) {
+	var req models.CartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	cart, err := h.cartService.AddItem(c.Request.Context(), cartOwner(c), &req)
+	if err != nil {
+		sendServiceError(c, "Failed to add item to cart", err)
+		return
+	}
+	if cart.Token != "" && cart.ExpiresAt != nil {
+		utils.SetCartCookie(c, cookieOptions(h.cfg), cart.Token, *cart.ExpiresAt)
+	}
+
+	utils.SendSuccess(c, "Item added to cart", cart)
+}
+
+func (h *CartHandler) UpdateItem(c *gin.Context) {
+	productID, ok := parseCartProductID(c)
+	if !ok {
+		return
+	}
+	var req models.UpdateCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	cart, err := h.cartService.SetItemQuantity(c.Request.Context(), cartOwner(c), productID, req.Quantity)
+	if err != nil {
+		sendServiceError(c, "Failed to update cart item", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Cart item updated", cart)
+}
+
+func (h *CartHandler) RemoveItem(c *gin.Context) {
+	productID, ok := parseCartProductID(c)
+	if !ok {
+		return
+	}
+
+	cart, err := h.cartService.RemoveItem(c.Request.Context(), cartOwner(c), productID)
+	if err != nil {
+		sendServiceError(c, "Failed to remove cart item", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Cart item removed", cart)
+}
+
+func (h *CartHandler) ClearCart(c *gin.Context) {
+	if err := h.cartService.ClearCart(c.Request.Context(), cartOwner(c)); err != nil {
+		sendServiceError(c, "Failed to clear cart", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Cart cleared", nil)
+}
+
+// ShareCart snapshots the caller's cart behind a token that others can use
+// to view and import it
+func (h *CartHandler) ShareCart(c *gin.Context) {
+	link, err := h.cartService.ShareCart(c.Request.Context(), cartOwner(c))
+	if err != nil {
+		sendServiceError(c, "Failed to share cart", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Cart shared successfully",
+		Data:    link,
+	})
+}
+
+func (h *CartHandler) GetSharedCart(c *gin.Context) {
+	cart, err := h.cartService.GetSharedCart(c.Request.Context(), cartOwner(c), c.Param("token"))
+	if err != nil {
+		sendServiceError(c, "Failed to fetch shared cart", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Shared cart retrieved successfully", cart)
+}
+
+// ImportSharedCart adds a shared cart's contents to the caller's cart
+func (h *CartHandler) ImportSharedCart(c *gin.Context) {
+	cart, err := h.cartService.ImportSharedCart(c.Request.Context(), cartOwner(c), c.Param("token"))
+	if err != nil {
+		sendServiceError(c, "Failed to import shared cart", err)
+		return
+	}
+	if cart.Token != "" && cart.ExpiresAt != nil {
+		utils.SetCartCookie(c, cookieOptions(h.cfg), cart.Token, *cart.ExpiresAt)
+	}
+
+	utils.SendSuccess(c, "Shared cart imported", cart)
+}
+
+// cartOwner identifies the caller's cart: the signed-in user's, or the
+// anonymous cart of the token in the header or cookie
+func cartOwner(c *gin.Context) services.CartOwner {
+	return services.CartOwner{
+		UserID:  c.GetUint("user_id"),
+		Token:   cartToken(c),
+		StoreID: c.GetUint("store_id"),
+	}
+}
+
+func cartToken(c *gin.Context) string {
+	if token := c.GetHeader(CartTokenHeader); token != "" {
+		return token
+	}
+	token, _ := c.Cookie(utils.CartTokenCookie)
+	return token
+}
+
+func cookieOptions(cfg *config.Config) utils.CookieOptions {
+	return utils.CookieOptions{
+		Domain:   cfg.AuthCookieDomain,
+		Secure:   cfg.AuthCookieSecure,
+		SameSite: cfg.AuthCookieSameSite,
+	}
+}
+
+func parseCartProductID(c *gin.Context) (uint, bool) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return 0, false
+	}
+	return uint(productID), true
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/types"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type CatalogConnectorHandler struct {
+	catalogSyncService *services.CatalogSyncService
+}
+
+func NewCatalogConnectorHandler(catalogSyncService *services.CatalogSyncService) *CatalogConnectorHandler {
+	return &CatalogConnectorHandler{catalogSyncService: catalogSyncService}
+}
+
+func (h *CatalogConnectorHandler) GetConnectors(c *gin.Context) {
+	connectors, err := h.catalogSyncService.GetConnectors(c.Request.Context())
+	if err != nil {
+		sendCatalogConnectorError(c, "Failed to fetch catalog connectors", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Catalog connectors retrieved successfully", connectors)
+}
+
+func (h *CatalogConnectorHandler) GetConnector(c *gin.Context) {
+	connectorID, err := strconv.ParseUint(c.Param("connector_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid catalog connector ID")
+		return
+	}
+
+	connector, err := h.catalogSyncService.GetConnectorByID(c.Request.Context(), uint(connectorID))
+	if err != nil {
+		sendCatalogConnectorError(c, "Failed to fetch catalog connector", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Catalog connector retrieved successfully", connector)
+}
+
+func (h *CatalogConnectorHandler) CreateConnector(c *gin.Context) {
+	var req models.CreateCatalogConnectorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	connector, err := h.catalogSyncService.CreateConnector(c.Request.Context(), c.GetUint("user_id"), &req)
+	if err != nil {
+		sendCatalogConnectorError(c, "Failed to create catalog connector", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Catalog connector created successfully",
+		Data:    connector,
+	})
+}
+
+func (h *CatalogConnectorHandler) UpdateConnector(c *gin.Context) {
+	connectorID, err := strconv.ParseUint(c.Param("connector_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid catalog connector ID")
+		return
+	}
+
+	var req models.UpdateCatalogConnectorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	connector, err := h.catalogSyncService.UpdateConnector(c.Request.Context(), uint(connectorID), &req)
+	if err != nil {
+		sendCatalogConnectorError(c, "Failed to update catalog connector", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Catalog connector updated successfully", connector)
+}
+
+func (h *CatalogConnectorHandler) DeleteConnector(c *gin.Context) {
+	connectorID, err := strconv.ParseUint(c.Param("connector_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid catalog connector ID")
+		return
+	}
+
+	if err := h.catalogSyncService.DeleteConnector(c.Request.Context(), uint(connectorID)); err != nil {
+		sendCatalogConnectorError(c, "Failed to delete catalog connector", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Catalog connector deleted successfully", nil)
+}
+
+// TriggerRun pushes the catalog now instead of waiting for the schedule;
+// ?full=true sends every product instead of only the changed ones
+func (h *CatalogConnectorHandler) TriggerRun(c *gin.Context) {
+	connectorID, err := strconv.ParseUint(c.Param("connector_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid catalog connector ID")
+		return
+	}
+
+	run, err := h.catalogSyncService.TriggerRun(c.Request.Context(), uint(connectorID), c.Query("full") == "true")
+	if err != nil {
+		sendCatalogConnectorError(c, "Failed to start catalog sync", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.APIResponse{
+		Success: true,
+		Message: "Catalog sync started",
+		Data:    run,
+	})
+}
+
+// GetRuns returns the sync log of a connector, newest first
+func (h *CatalogConnectorHandler) GetRuns(c *gin.Context) {
+	connectorID, err := strconv.ParseUint(c.Param("connector_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid catalog connector ID")
+		return
+	}
+
+	page, limit := utils.ParsePagination(c, 20)
+
+	runs, total, err := h.catalogSyncService.GetRuns(c.Request.Context(), uint(connectorID), page, limit)
+	if err != nil {
+		sendCatalogConnectorError(c, "Failed to fetch catalog sync runs", err)
+		return
+	}
+
+	response := types.NewPaginated("runs", runs, page, limit, total)
+
+	utils.SendSuccess(c, "Catalog sync runs retrieved successfully", response)
+}
+
+func sendCatalogConnectorError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrCatalogConnectorNotFound):
+		utils.SendError(c, http.StatusNotFound, "Catalog connector not found", err)
+	case errors.Is(err, services.ErrCatalogConnectorBusy):
+		utils.SendError(c, http.StatusConflict, message, err)
+	case errors.Is(err, services.ErrInvalidInput):
+		utils.SendError(c, http.StatusUnprocessableEntity, message, err)
+	default:
+		sendServiceError(c, message, err)
+	}
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type CategoryHandler struct {
+	categoryService *services.CategoryService
+}
+
+func NewCategoryHandler(categoryService *services.CategoryService) *CategoryHandler {
+	return &CategoryHandler{categoryService: categoryService}
+}
+
+// RenameCategory renames a category on every product that has it
+func (h *CategoryHandler) RenameCategory(c *gin.Context) {
+	var req models.RenameCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	change, err := h.categoryService.RenameCategory(c.Request.Context(), c.GetUint("store_id"), &req)
+	if err != nil {
+		sendCategoryError(c, "Failed to rename category", err)
+		return
+	}
+	utils.SendSuccess(c, "Category renamed successfully", change)
+}
+
+// MergeCategories moves the products of one category into another
+func (h *CategoryHandler) MergeCategories(c *gin.Context) {
+	var req models.MergeCategoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	change, err := h.categoryService.MergeCategories(c.Request.Context(), c.GetUint("store_id"), &req)
+	if err != nil {
+		sendCategoryError(c, "Failed to merge categories", err)
+		return
+	}
+	utils.SendSuccess(c, "Categories merged successfully", change)
+}
+
+func sendCategoryError(c *gin.Context, message string, err error) {
+	if errors.Is(err, services.ErrCategoryExists) {
+		utils.SendError(c, http.StatusConflict, message, err)
+		return
+	}
+	sendServiceError(c, message, err)
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type ChaosHandler struct {
+	chaosService *services.ChaosService
+}
+
+func NewChaosHandler(chaosService *services.ChaosService) *ChaosHandler {
+	return &ChaosHandler{chaosService: chaosService}
+}
+
+// GetRules lists chaos rules and whether injection is enabled on this
+// deployment at all
+func (h *ChaosHandler) GetRules(c *gin.Context) {
+	rules, err := h.chaosService.GetRules(c.Request.Context())
+	if err != nil {
+		utils.SendInternalError(c, "Failed to fetch chaos rules", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Chaos rules retrieved successfully", gin.H{
+		"enabled": h.chaosService.Enabled(),
+		"rules":   rules,
+	})
+}
+
+// CreateRule starts injecting failures, e.g.
+// {"route": "/api/v1/products/*", "latency_ms": 2000, "error_percent": 20}
+func (h *ChaosHandler) CreateRule(c *gin.Context) {
+	var req models.CreateChaosRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	rule, err := h.chaosService.CreateRule(c.Request.Context(), c.GetUint("user_id"), &req)
+	if err != nil {
+		sendServiceError(c, "Failed to create chaos rule", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Chaos rule created successfully",
+		Data:    rule,
+	})
+}
+
+func (h *ChaosHandler) UpdateRule(c *gin.Context) {
+	id, ok := parseChaosRuleID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateChaosRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	rule, err := h.chaosService.UpdateRule(c.Request.Context(), id, &req)
+	if err != nil {
+		sendServiceError(c, "Failed to update chaos rule", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Chaos rule updated successfully", rule)
+}
+
+func (h *ChaosHandler) DeleteRule(c *gin.Context) {
+	id, ok := parseChaosRuleID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.chaosService.DeleteRule(c.Request.Context(), id); err != nil {
+		sendServiceError(c, "Failed to delete chaos rule", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Chaos rule deleted successfully", nil)
+}
+
+func parseChaosRuleID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("rule_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid rule ID")
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type CollectionHandler struct {
+	collectionService *services.CollectionService
+}
+
+func NewCollectionHandler(collectionService *services.CollectionService) *CollectionHandler {
+	return &CollectionHandler{collectionService: collectionService}
+}
+
+// GetActiveCollections lists the storefront's collections without products
+func (h *CollectionHandler) GetActiveCollections(c *gin.Context) {
+	collections, err := h.collectionService.GetActiveCollections(c.Request.Context(), c.GetUint("store_id"))
+	if err != nil {
+		sendCollectionError(c, "Failed to fetch collections", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Collections retrieved successfully", gin.H{"collections": collections})
+}
+
+// GetCollectionBySlug returns a storefront collection with its products
+func (h *CollectionHandler) GetCollectionBySlug(c *gin.Context) {
+	collection, err := h.collectionService.GetCollectionBySlug(c.Request.Context(), c.GetUint("store_id"), c.Param("slug"))
+	if err != nil {
+		sendCollectionError(c, "Failed to fetch collection", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Collection retrieved successfully", collection)
+}
+
+func (h *CollectionHandler) GetCollections(c *gin.Context) {
+	collections, err := h.collectionService.GetCollections(c.Request.Context(), c.GetUint("store_id"))
+	if err != nil {
+		sendCollectionError(c, "Failed to fetch collections", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Collections retrieved successfully", gin.H{"collections": collections})
+}
+
+func (h *CollectionHandler) GetCollection(c *gin.Context) {
+	id, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+
+	collection, err := h.collectionService.GetCollection(c.Request.Context(), c.GetUint("store_id"), id)
+	if err != nil {
+		sendCollectionError(c, "Failed to fetch collection", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Collection retrieved successfully", collection)
+}
+
+func (h *CollectionHandler) CreateCollection(c *gin.Context) {
+	var req models.CreateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	collection, err := h.collectionService.CreateCollection(c.Request.Context(), c.GetUint("store_id"), &req)
+	if err != nil {
+		sendCollectionError(c, "Failed to create collection", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Collection created successfully",
+		Data:    collection,
+	})
+}
+
+func (h *CollectionHandler) UpdateCollection(c *gin.Context) {
+	id, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	collection, err := h.collectionService.UpdateCollection(c.Request.Context(), c.GetUint("store_id"), id, &req)
+	if err != nil {
+		sendCollectionError(c, "Failed to update collection", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Collection updated successfully", collection)
+}
+
+func (h *CollectionHandler) DeleteCollection(c *gin.Context) {
+	id, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.collectionService.DeleteCollection(c.Request.Context(), c.GetUint("store_id"), id); err != nil {
+		sendCollectionError(c, "Failed to delete collection", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Collection deleted successfully", nil)
+}
+
+func parseCollectionID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("collection_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid collection ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func sendCollectionError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrCollectionNotFound):
+		utils.SendError(c, http.StatusNotFound, "Collection not found", err)
+	case errors.Is(err, services.ErrCollectionExists):
+		utils.SendError(c, http.StatusConflict, message, err)
+	case errors.Is(err, services.ErrInvalidInput):
+		utils.SendError(c, http.StatusUnprocessableEntity, message, err)
+	default:
+		sendServiceError(c, message, err)
+	}
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type CustomerGroupHandler struct {
+	customerGroupService *services.CustomerGroupService
+}
+
+func NewCustomerGroupHandler(customerGroupService *services.CustomerGroupService) *CustomerGroupHandler {
+	return &CustomerGroupHandler{customerGroupService: customerGroupService}
+}
+
+func (h *CustomerGroupHandler) GetGroups(c *gin.Context) {
+	groups, err := h.customerGroupService.GetGroups(c.Request.Context())
+	if err != nil {
+		utils.SendInternalError(c, "Failed to fetch customer groups", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Customer groups retrieved successfully", groups)
+}
+
+// GetGroup returns a group with its price rules
+func (h *CustomerGroupHandler) GetGroup(c *gin.Context) {
+	groupID, ok := parseGroupID(c)
+	if !ok {
+		return
+	}
+
+	group, err := h.customerGroupService.GetGroup(c.Request.Context(), groupID)
+	if err != nil {
+		sendServiceError(c, "Failed to fetch customer group", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Customer group retrieved successfully", group)
+}
+
+func (h *CustomerGroupHandler) CreateGroup(c *gin.Context) {
+	var req models.CustomerGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	group, err := h.customerGroupService.CreateGroup(c.Request.Context(), &req)
+	if err != nil {
+		sendCustomerGroupError(c, "Failed to create customer group", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Customer group created successfully",
+		Data:    group,
+	})
+}
+
+func (h *CustomerGroupHandler) UpdateGroup(c *gin.Context) {
+	groupID, ok := parseGroupID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateCustomerGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	group, err := h.customerGroupService.UpdateGroup(c.Request.Context(), groupID, &req)
+	if err != nil {
+		sendCustomerGroupError(c, "Failed to update customer group", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Customer group updated successfully", group)
+}
+
+func (h *CustomerGroupHandler) DeleteGroup(c *gin.Context) {
+	groupID, ok := parseGroupID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.customerGroupService.DeleteGroup(c.Request.Context(), groupID); err != nil {
+		sendServiceError(c, "Failed to delete customer group", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Customer group deleted successfully", nil)
+}
+
+// AddPrice adds a product price or category discount to a group
+func (h *CustomerGroupHandler) AddPrice(c *gin.Context) {
+	groupID, ok := parseGroupID(c)
+	if !ok {
+		return
+	}
+
+	var req models.CustomerGroupPriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	price, err := h.customerGroupService.AddPrice(c.Request.Context(), groupID, &req)
+	if err != nil {
+		sendCustomerGroupError(c, "Failed to add group price", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Group price added successfully",
+		Data:    price,
+	})
+}
+
+func (h *CustomerGroupHandler) DeletePrice(c *gin.Context) {
+	groupID, ok := parseGroupID(c)
+	if !ok {
+		return
+	}
+	priceID, err := strconv.ParseUint(c.Param("price_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid price ID")
+		return
+	}
+
+	if err := h.customerGroupService.DeletePrice(c.Request.Context(), groupID, uint(priceID)); err != nil {
+		sendServiceError(c, "Failed to delete group price", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Group price deleted successfully", nil)
+}
+
+// SetUserGroup assigns a customer to a group, or removes them with a null
+// group_id
+func (h *CustomerGroupHandler) SetUserGroup(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid user ID")
+		return
+	}
+
+	var req models.SetCustomerGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	user, err := h.customerGroupService.SetUserGroup(c.Request.Context(), uint(userID), req.GroupID)
+	if err != nil {
+		sendServiceError(c, "Failed to update customer group", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Customer group updated successfully", user)
+}
+
+func parseGroupID(c *gin.Context) (uint, bool) {
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid customer group ID")
+		return 0, false
+	}
+	return uint(groupID), true
+}
+
+func sendCustomerGroupError(c *gin.Context, message string, err error) {
+	if errors.Is(err, services.ErrCustomerGroupExists) || errors.Is(err, services.ErrGroupPriceExists) {
+		utils.SendError(c, http.StatusConflict, message, err)
+		return
+	}
+	sendServiceError(c, message, err)
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type DashboardHandler struct {
+	snapshotService *services.SnapshotService
+}
+
+func NewDashboardHandler(snapshotService *services.SnapshotService) *DashboardHandler {
+	return &DashboardHandler{snapshotService: snapshotService}
+}
+
+// GetTrends returns daily metric snapshots and period-over-period deltas
+func (h *DashboardHandler) GetTrends(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil {
+		utils.SendValidationError(c, "Invalid days parameter")
+		return
+	}
+
+	trends, err := h.snapshotService.GetTrends(c.Request.Context(), days)
+	if err != nil {
+		sendServiceError(c, "Failed to fetch dashboard trends", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Dashboard trends retrieved successfully", trends)
+}
+
+// CaptureSnapshot records today's metrics on demand
+func (h *DashboardHandler) CaptureSnapshot(c *gin.Context) {
+	snapshot, err := h.snapshotService.CaptureSnapshot(c.Request.Context())
+	if err != nil {
+		utils.SendInternalError(c, "Failed to capture metric snapshot", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Metric snapshot captured successfully", snapshot)
+}
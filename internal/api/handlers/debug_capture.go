@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/types"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type DebugCaptureHandler struct {
+	captureService *services.DebugCaptureService
+}
+
+func NewDebugCaptureHandler(captureService *services.DebugCaptureService) *DebugCaptureHandler {
+	return &DebugCaptureHandler{captureService: captureService}
+}
+
+// GetRules lists capture rules, including expired ones, and whether capture
+// is enabled on this deployment at all
+func (h *DebugCaptureHandler) GetRules(c *gin.Context) {
+	rules, err := h.captureService.GetRules(c.Request.Context())
+	if err != nil {
+		utils.SendInternalError(c, "Failed to fetch debug capture rules", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Debug capture rules retrieved successfully", gin.H{
+		"enabled": h.captureService.Enabled(),
+		"rules":   rules,
+	})
+}
+
+// CreateRule starts recording a route, e.g. {"route": "/api/v1/products/:product_id", "duration": "30m"}
+func (h *DebugCaptureHandler) CreateRule(c *gin.Context) {
+	var req models.CreateDebugCaptureRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	rule, err := h.captureService.CreateRule(c.Request.Context(), c.GetUint("user_id"), &req)
+	if err != nil {
+		sendServiceError(c, "Failed to create debug capture rule", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Debug capture rule created successfully",
+		Data:    rule,
+	})
+}
+
+func (h *DebugCaptureHandler) DeleteRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("rule_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid rule ID")
+		return
+	}
+
+	if err := h.captureService.DeleteRule(c.Request.Context(), uint(id)); err != nil {
+		sendServiceError(c, "Failed to delete debug capture rule", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Debug capture rule deleted successfully", nil)
+}
+
+// GetCaptures lists recorded captures without bodies, optionally filtered by
+// ?rule_id= or ?route=
+func (h *DebugCaptureHandler) GetCaptures(c *gin.Context) {
+	page, limit := utils.ParsePagination(c, 20)
+
+	var ruleID uint64
+	if raw := c.Query("rule_id"); raw != "" {
+		var err error
+		if ruleID, err = strconv.ParseUint(raw, 10, 32); err != nil {
+			utils.SendValidationError(c, "Invalid rule ID")
+			return
+		}
+	}
+
+	captures, total, err := h.captureService.GetCaptures(c.Request.Context(), uint(ruleID), c.Query("route"), page, limit)
+	if err != nil {
+		utils.SendInternalError(c, "Failed to fetch debug captures", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Debug captures retrieved successfully", types.NewPaginated("captures", captures, page, limit, total))
+}
+
+func (h *DebugCaptureHandler) GetCapture(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("capture_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid capture ID")
+		return
+	}
+
+	capture, err := h.captureService.GetCapture(c.Request.Context(), uint(id))
+	if err != nil {
+		sendServiceError(c, "Failed to fetch debug capture", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Debug capture retrieved successfully", capture)
+}
+
+func (h *DebugCaptureHandler) ClearCaptures(c *gin.Context) {
+	deleted, err := h.captureService.ClearCaptures(c.Request.Context())
+	if err != nil {
+		utils.SendInternalError(c, "Failed to clear debug captures", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Debug captures cleared successfully", gin.H{"deleted": deleted})
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type DeliveryHandler struct {
+	deliveryService *services.DeliveryService
+}
+
+func NewDeliveryHandler(deliveryService *services.DeliveryService) *DeliveryHandler {
+	return &DeliveryHandler{deliveryService: deliveryService}
+}
+
+// EstimateDelivery returns the delivery options and dates of a product for
+// ?postcode=, optionally with ?country= and ?quantity=
+func (h *DeliveryHandler) EstimateDelivery(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return
+	}
+	quantity, err := strconv.Atoi(c.DefaultQuery("quantity", "1"))
+	if err != nil {
+		utils.SendValidationError(c, "Invalid quantity")
+		return
+	}
+
+	estimate, err := h.deliveryService.EstimateDelivery(c.Request.Context(), c.GetUint("store_id"), uint(productID), quantity, c.Query("country"), c.Query("postcode"))
+	if err != nil {
+		sendServiceError(c, "Failed to estimate delivery", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Delivery estimated successfully", estimate)
+}
+
+func (h *DeliveryHandler) GetShippingRates(c *gin.Context) {
+	rates, err := h.deliveryService.GetShippingRates(c.Request.Context(), c.GetUint("store_id"))
+	if err != nil {
+		utils.SendInternalError(c, "Failed to fetch shipping rates", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Shipping rates retrieved successfully", gin.H{"rates": rates})
+}
+
+func (h *DeliveryHandler) CreateShippingRate(c *gin.Context) {
+	var req models.ShippingRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	rate, err := h.deliveryService.CreateShippingRate(c.Request.Context(), c.GetUint("store_id"), &req)
+	if err != nil {
+		sendServiceError(c, "Failed to create shipping rate", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Shipping rate created successfully",
+		Data:    rate,
+	})
+}
+
+func (h *DeliveryHandler) UpdateShippingRate(c *gin.Context) {
+	id, ok := parseShippingRateID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateShippingRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	rate, err := h.deliveryService.UpdateShippingRate(c.Request.Context(), c.GetUint("store_id"), id, &req)
+	if err != nil {
+		sendServiceError(c, "Failed to update shipping rate", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Shipping rate updated successfully", rate)
+}
+
+func (h *DeliveryHandler) DeleteShippingRate(c *gin.Context) {
+	id, ok := parseShippingRateID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.deliveryService.DeleteShippingRate(c.Request.Context(), c.GetUint("store_id"), id); err != nil {
+		sendServiceError(c, "Failed to delete shipping rate", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Shipping rate deleted successfully", nil)
+}
+
+func parseShippingRateID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("rate_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid shipping rate ID")
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// notFoundErrors are service errors meaning the addressed record does not exist
+var notFoundErrors = []error{
+	gorm.ErrRecordNotFound,
+	services.ErrProductNotFound,
+	services.ErrReviewNotFound,
+	services.ErrUserNotFound,
+	services.ErrAnnouncementNotFound,
+	services.ErrBannerNotFound,
+	services.ErrBundleNotFound,
+	services.ErrCollectionNotFound,
+	services.ErrFeatureFlagNotFound,
+	services.ErrImportSourceNotFound,
+	services.ErrImageNotFound,
+	services.ErrJobNotFound,
+	services.ErrProductDraftNotFound,
+	services.ErrProductImportNotFound,
+	services.ErrProductReportNotFound,
+	services.ErrPendingDeletionNotFound,
+	services.ErrSavedSearchNotFound,
+	services.ErrStoreNotFound,
+	services.ErrVendorNotFound,
+	services.ErrWebhookNotFound,
+	services.ErrAdminNoteNotFound,
+	services.ErrInvitationNotFound,
+	services.ErrServiceNotFound,
+	services.ErrUploadNotFound,
+	services.ErrRevisionNotFound,
+	services.ErrPriceChangeNotFound,
+	services.ErrPriceTierNotFound,
+	services.ErrCustomerGroupNotFound,
+	services.ErrGroupPriceNotFound,
+	services.ErrQuoteNotFound,
+	services.ErrLocationNotFound,
+	services.ErrShippingRateNotFound,
+	services.ErrReportNotFound,
+	services.ErrDebugCaptureRuleNotFound,
+	services.ErrDebugCaptureNotFound,
+	services.ErrChaosRuleNotFound,
+	services.ErrCategoryNotFound,
+	services.ErrFraudReviewNotFound,
+	services.ErrCartNotFound,
+	services.ErrCartShareNotFound,
+}
+
+// statusForError maps a service error to an HTTP status: 404 for missing
+// records, 422 for input the service rejected and 500 for everything else,
+// including database failures. Malformed requests are rejected with 400 by
+// the handlers before a service is called.
+func statusForError(err error) int {
+	for _, target := range notFoundErrors {
+		if errors.Is(err, target) {
+			return http.StatusNotFound
+		}
+	}
+	switch {
+	case errors.Is(err, services.ErrInvalidInput), errors.Is(err, services.ErrInvalidFilter):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// sendServiceError responds with the status statusForError picks. Handlers
+// with domain-specific errors (conflicts, forbidden, ...) check those first
+// and fall back to this. Order quantity limit violations carry the limits
+// under the quantity_limit error code.
+func sendServiceError(c *gin.Context, message string, err error) {
+	var limit *services.QuantityLimitError
+	if errors.As(err, &limit) {
+		c.JSON(http.StatusUnprocessableEntity, utils.APIResponse{
+			Success: false,
+			Message: limit.Error(),
+			Data:    limit,
+			Error:   "quantity_limit",
+		})
+		return
+	}
+
+	status := statusForError(err)
+	if status == http.StatusInternalServerError {
+		utils.SendInternalError(c, message, err)
+		return
+	}
+	utils.SendError(c, status, message, err)
+}
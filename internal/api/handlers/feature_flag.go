@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/api/middleware"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type FeatureFlagHandler struct {
+	featureFlagService *services.FeatureFlagService
+}
+
+func NewFeatureFlagHandler(featureFlagService *services.FeatureFlagService) *FeatureFlagHandler {
+	return &FeatureFlagHandler{featureFlagService: featureFlagService}
+}
+
+// GetFeatures returns every flag evaluated for the caller
+func (h *FeatureFlagHandler) GetFeatures(c *gin.Context) {
+	utils.SendSuccess(c, "Features retrieved successfully", h.featureFlagService.Evaluate(middleware.FlagSubject(c)))
+}
+
+func (h *FeatureFlagHandler) GetFlags(c *gin.Context) {
+	flags, err := h.featureFlagService.GetFlags(c.Request.Context())
+	if err != nil {
+		sendFeatureFlagError(c, "Failed to fetch feature flags", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Feature flags retrieved successfully", flags)
+}
+
+func (h *FeatureFlagHandler) GetFlag(c *gin.Context) {
+	flag, err := h.featureFlagService.GetFlag(c.Request.Context(), c.Param("key"))
+	if err != nil {
+		sendFeatureFlagError(c, "Failed to fetch feature flag", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Feature flag retrieved successfully", flag)
+}
+
+func (h *FeatureFlagHandler) CreateFlag(c *gin.Context) {
+	var req models.CreateFeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	flag, err := h.featureFlagService.CreateFlag(c.Request.Context(), c.GetUint("user_id"), &req)
+	if err != nil {
+		sendFeatureFlagError(c, "Failed to create feature flag", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Feature flag created successfully",
+		Data:    flag,
+	})
+}
+
+func (h *FeatureFlagHandler) UpdateFlag(c *gin.Context) {
+	var req models.UpdateFeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	flag, err := h.featureFlagService.UpdateFlag(c.Request.Context(), c.Param("key"), c.GetUint("user_id"), &req)
+	if err != nil {
+		sendFeatureFlagError(c, "Failed to update feature flag", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Feature flag updated successfully", flag)
+}
+
+func (h *FeatureFlagHandler) DeleteFlag(c *gin.Context) {
+	if err := h.featureFlagService.DeleteFlag(c.Request.Context(), c.Param("key")); err != nil {
+		sendFeatureFlagError(c, "Failed to delete feature flag", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Feature flag deleted successfully", nil)
+}
+
+func sendFeatureFlagError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrFeatureFlagNotFound):
+		utils.SendError(c, http.StatusNotFound, "Feature flag not found", err)
+	case errors.Is(err, services.ErrFeatureFlagExists):
+		utils.SendError(c, http.StatusConflict, message, err)
+	case errors.Is(err, services.ErrInvalidInput):
+		utils.SendError(c, http.StatusUnprocessableEntity, message, err)
+	default:
+		sendServiceError(c, message, err)
+	}
+}
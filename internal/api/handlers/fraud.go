@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/types"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type FraudHandler struct {
+	fraudService *services.FraudService
+}
+
+func NewFraudHandler(fraudService *services.FraudService) *FraudHandler {
+	return &FraudHandler{fraudService: fraudService}
+}
+
+// GetReviews returns the fraud review queue, riskiest first, optionally
+// filtered by ?status=
+func (h *FraudHandler) GetReviews(c *gin.Context) {
+	status := c.Query("status")
+	switch status {
+	case "", models.FraudReviewPending, models.FraudReviewCleared, models.FraudReviewBlocked:
+	default:
+		utils.SendValidationError(c, "Invalid status")
+		return
+	}
+
+	page, limit := utils.ParsePagination(c, 20)
+
+	reviews, total, err := h.fraudService.GetReviews(c.Request.Context(), status, page, limit)
+	if err != nil {
+		sendServiceError(c, "Failed to fetch fraud reviews", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Fraud reviews retrieved successfully", types.NewPaginated("reviews", reviews, page, limit, total))
+}
+
+// ResolveReview clears a flagged signup or blocks the account
+func (h *FraudHandler) ResolveReview(c *gin.Context) {
+	reviewID, err := strconv.ParseUint(c.Param("review_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid review ID")
+		return
+	}
+
+	var req models.ResolveFraudReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	review, err := h.fraudService.ResolveReview(c.Request.Context(), c.GetUint("user_id"), uint(reviewID), &req, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		sendServiceError(c, "Failed to resolve fraud review", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Fraud review resolved successfully", review)
+}
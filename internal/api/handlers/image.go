@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type ImageHandler struct {
+	imageProxyService *services.ImageProxyService
+}
+
+func NewImageHandler(imageProxyService *services.ImageProxyService) *ImageHandler {
+	return &ImageHandler{imageProxyService: imageProxyService}
+}
+
+// ServeImage returns a resized variant of a stored image, e.g.
+// /img/products/images/2024/01/02/abc.jpg?w=400&h=400&fit=cover&format=png
+func (h *ImageHandler) ServeImage(c *gin.Context) {
+	transform := services.ImageTransform{
+		Format: c.Query("format"),
+		Fit:    c.Query("fit"),
+	}
+
+	var err error
+	if w := c.Query("w"); w != "" {
+		if transform.Width, err = strconv.Atoi(w); err != nil {
+			utils.SendValidationError(c, "Invalid width")
+			return
+		}
+	}
+	if hgt := c.Query("h"); hgt != "" {
+		if transform.Height, err = strconv.Atoi(hgt); err != nil {
+			utils.SendValidationError(c, "Invalid height")
+			return
+		}
+	}
+	if q := c.Query("q"); q != "" {
+		if transform.Quality, err = strconv.Atoi(q); err != nil {
+			utils.SendValidationError(c, "Invalid quality")
+			return
+		}
+	}
+
+	variant, err := h.imageProxyService.GetVariant(c.Request.Context(), c.Param("key"), transform)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrImageNotFound):
+			utils.SendError(c, http.StatusNotFound, "Image not found", err)
+		case errors.Is(err, services.ErrInvalidImageParams):
+			utils.SendError(c, http.StatusBadRequest, "Invalid image request", err)
+		default:
+			utils.SendInternalError(c, "Failed to render image", err)
+		}
+		return
+	}
+
+	etag := `"` + variant.CacheKey + `"`
+	c.Header("ETag", etag)
+	// Variants of a key never change, so CDNs and browsers may cache forever
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, variant.ContentType, variant.Data)
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+// UpdateProductImage sets the alt text and/or caption of a product image
+func (h *AdminHandler) UpdateProductImage(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return
+	}
+	imageID, err := uuid.Parse(c.Param("image_id"))
+	if err != nil {
+		utils.SendValidationError(c, "Invalid image ID")
+		return
+	}
+
+	var req models.UpdateImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	image, err := h.adminService.UpdateImageMetadata(c.Request.Context(), uint(productID), imageID, &req)
+	if err != nil {
+		sendServiceError(c, "Failed to update image", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Image updated successfully", image)
+}
+
+// GenerateAltText queues AI alt text generation for images that have none;
+// follow it via /admin/jobs. An optional product_ids body limits the run.
+func (h *AdminHandler) GenerateAltText(c *gin.Context) {
+	var req models.GenerateAltTextRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.SendValidationError(c, "Invalid request data: "+err.Error())
+			return
+		}
+	}
+
+	job, err := h.adminService.StartAltTextGeneration(c.Request.Context(), c.GetUint("user_id"), req.ProductIDs)
+	if err != nil {
+		utils.SendInternalError(c, "Failed to start alt text generation", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.APIResponse{
+		Success: true,
+		Message: "Alt text generation started",
+		Data:    job,
+	})
+}
+
+// ImportProductImages queues downloading images from a list of URLs, e.g. a
+// supplier catalog, and attaching them to the product. Follow it via
+// /admin/jobs; the job log reports each URL.
+func (h *AdminHandler) ImportProductImages(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return
+	}
+
+	var req models.ImportProductImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	job, err := h.adminService.StartImageImport(c.Request.Context(), c.GetUint("user_id"), uint(productID), req.URLs)
+	if err != nil {
+		sendServiceError(c, "Failed to start image import", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.APIResponse{
+		Success: true,
+		Message: "Image import started",
+		Data:    job,
+	})
+}
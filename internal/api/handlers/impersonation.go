@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type ImpersonationHandler struct {
+	impersonationService *services.ImpersonationService
+}
+
+func NewImpersonationHandler(impersonationService *services.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{impersonationService: impersonationService}
+}
+
+// Impersonate issues a short-lived access token acting as a customer. The
+// token is only returned in the body, never as a cookie, so the admin's own
+// session is left alone. The body is optional.
+func (h *ImpersonationHandler) Impersonate(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid user ID")
+		return
+	}
+
+	var req models.ImpersonateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.SendValidationError(c, "Invalid request data: "+err.Error())
+			return
+		}
+	}
+
+	token, err := h.impersonationService.Impersonate(c.Request.Context(), c.GetUint("user_id"), uint(userID), req.Reason, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUserNotFound):
+			utils.SendError(c, http.StatusNotFound, "User not found", err)
+		case errors.Is(err, services.ErrInvalidInput):
+			utils.SendError(c, http.StatusUnprocessableEntity, "Failed to impersonate user", err)
+		default:
+			utils.SendInternalError(c, "Failed to impersonate user", err)
+		}
+		return
+	}
+
+	utils.SendSuccess(c, "Impersonation token issued", token)
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/types"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type ImportSourceHandler struct {
+	feedImportService *services.FeedImportService
+}
+
+func NewImportSourceHandler(feedImportService *services.FeedImportService) *ImportSourceHandler {
+	return &ImportSourceHandler{feedImportService: feedImportService}
+}
+
+func (h *ImportSourceHandler) GetSources(c *gin.Context) {
+	sources, err := h.feedImportService.GetSources(c.Request.Context())
+	if err != nil {
+		sendImportSourceError(c, "Failed to fetch import sources", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Import sources retrieved successfully", sources)
+}
+
+func (h *ImportSourceHandler) GetSource(c *gin.Context) {
+	sourceID, err := strconv.ParseUint(c.Param("source_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid import source ID")
+		return
+	}
+
+	source, err := h.feedImportService.GetSourceByID(c.Request.Context(), uint(sourceID))
+	if err != nil {
+		sendImportSourceError(c, "Failed to fetch import source", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Import source retrieved successfully", source)
+}
+
+func (h *ImportSourceHandler) CreateSource(c *gin.Context) {
+	var req models.CreateImportSourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	source, err := h.feedImportService.CreateSource(c.Request.Context(), c.GetUint("user_id"), &req)
+	if err != nil {
+		sendImportSourceError(c, "Failed to create import source", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Import source created successfully",
+		Data:    source,
+	})
+}
+
+func (h *ImportSourceHandler) UpdateSource(c *gin.Context) {
+	sourceID, err := strconv.ParseUint(c.Param("source_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid import source ID")
+		return
+	}
+
+	var req models.UpdateImportSourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	source, err := h.feedImportService.UpdateSource(c.Request.Context(), uint(sourceID), &req)
+	if err != nil {
+		sendImportSourceError(c, "Failed to update import source", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Import source updated successfully", source)
+}
+
+func (h *ImportSourceHandler) DeleteSource(c *gin.Context) {
+	sourceID, err := strconv.ParseUint(c.Param("source_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid import source ID")
+		return
+	}
+
+	if err := h.feedImportService.DeleteSource(c.Request.Context(), uint(sourceID)); err != nil {
+		sendImportSourceError(c, "Failed to delete import source", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Import source deleted successfully", nil)
+}
+
+// TriggerRun syncs the feed now instead of waiting for its schedule
+func (h *ImportSourceHandler) TriggerRun(c *gin.Context) {
+	sourceID, err := strconv.ParseUint(c.Param("source_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid import source ID")
+		return
+	}
+
+	run, err := h.feedImportService.TriggerRun(c.Request.Context(), uint(sourceID))
+	if err != nil {
+		sendImportSourceError(c, "Failed to start import run", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.APIResponse{
+		Success: true,
+		Message: "Import run started",
+		Data:    run,
+	})
+}
+
+// GetRuns returns the run history of a source with diff summaries, newest first
+func (h *ImportSourceHandler) GetRuns(c *gin.Context) {
+	sourceID, err := strconv.ParseUint(c.Param("source_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid import source ID")
+		return
+	}
+
+	page, limit := utils.ParsePagination(c, 20)
+
+	runs, total, err := h.feedImportService.GetRuns(c.Request.Context(), uint(sourceID), page, limit)
+	if err != nil {
+		sendImportSourceError(c, "Failed to fetch import runs", err)
+		return
+	}
+
+	response := types.NewPaginated("runs", runs, page, limit, total)
+
+	utils.SendSuccess(c, "Import runs retrieved successfully", response)
+}
+
+func sendImportSourceError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrImportSourceNotFound):
+		utils.SendError(c, http.StatusNotFound, "Import source not found", err)
+	case errors.Is(err, services.ErrImportSourceBusy):
+		utils.SendError(c, http.StatusConflict, message, err)
+	case errors.Is(err, services.ErrInvalidInput):
+		utils.SendError(c, http.StatusUnprocessableEntity, message, err)
+	default:
+		sendServiceError(c, message, err)
+	}
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/types"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type InvitationHandler struct {
+	invitationService *services.InvitationService
+}
+
+func NewInvitationHandler(invitationService *services.InvitationService) *InvitationHandler {
+	return &InvitationHandler{invitationService: invitationService}
+}
+
+// CreateInvitation emails an admin or vendor staff invitation
+func (h *InvitationHandler) CreateInvitation(c *gin.Context) {
+	var req models.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	invitation, err := h.invitationService.CreateInvitation(c.Request.Context(), c.GetUint("user_id"), &req)
+	if err != nil {
+		sendInvitationError(c, "Failed to create invitation", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Invitation sent successfully",
+		Data:    invitation,
+	})
+}
+
+// GetInvitations lists invitations, optionally filtered by ?status=
+func (h *InvitationHandler) GetInvitations(c *gin.Context) {
+	status := c.Query("status")
+	switch status {
+	case "", models.InvitationStatusPending, models.InvitationStatusAccepted, models.InvitationStatusRevoked, models.InvitationStatusExpired:
+	default:
+		utils.SendValidationError(c, "Invalid status")
+		return
+	}
+
+	page, limit := utils.ParsePagination(c, 20)
+
+	invitations, total, err := h.invitationService.GetInvitations(c.Request.Context(), status, page, limit)
+	if err != nil {
+		sendInvitationError(c, "Failed to fetch invitations", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Invitations retrieved successfully", types.NewPaginated("invitations", invitations, page, limit, total))
+}
+
+func (h *InvitationHandler) RevokeInvitation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("invitation_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid invitation ID")
+		return
+	}
+
+	if err := h.invitationService.RevokeInvitation(c.Request.Context(), uint(id)); err != nil {
+		sendInvitationError(c, "Failed to revoke invitation", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Invitation revoked successfully", nil)
+}
+
+// PreviewInvitation is public: the token itself is the credential
+func (h *InvitationHandler) PreviewInvitation(c *gin.Context) {
+	preview, err := h.invitationService.PreviewInvitation(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		sendInvitationError(c, "Failed to fetch invitation", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Invitation is valid", preview)
+}
+
+// AcceptInvitation creates or upgrades the invited account. The user signs
+// in afterwards with the normal login.
+func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
+	var req models.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	user, err := h.invitationService.AcceptInvitation(c.Request.Context(), &req, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		sendInvitationError(c, "Failed to accept invitation", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Invitation accepted, please sign in", user)
+}
+
+func sendInvitationError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvitationNotFound):
+		utils.SendError(c, http.StatusNotFound, "Invitation not found", err)
+	case errors.Is(err, services.ErrInvitationInvalid):
+		utils.SendError(c, http.StatusGone, "Invitation has expired or was already used", err)
+	case errors.Is(err, services.ErrVendorNotFound):
+		utils.SendError(c, http.StatusNotFound, "Vendor not found", err)
+	default:
+		sendServiceError(c, message, err)
+	}
+}
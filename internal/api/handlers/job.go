@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/princeprakhar/ecommerce-backend/internal/api/middleware"
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/types"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+)
+
+const (
+	jobSocketWriteTimeout = 10 * time.Second
+	jobSocketPongTimeout  = 60 * time.Second
+	jobSocketPingInterval = 30 * time.Second
+)
+
+// newSocketUpgrader accepts browser upgrades only from CORS_ALLOWED_ORIGINS.
+// Browsers send cookies with cross-site WebSocket handshakes and CORS does not
+// apply to them, so any page could otherwise open an admin stream. Without
+// configured origins only same-origin upgrades are accepted.
+func newSocketUpgrader(cfg *config.Config) *websocket.Upgrader {
+	upgrader := &websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+	}
+	if len(cfg.CORSAllowedOrigins) > 0 {
+		allowed := cfg.CORSAllowedOrigins
+		upgrader.CheckOrigin = func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			return origin == "" || middleware.OriginAllowed(allowed, origin)
+		}
+	}
+	return upgrader
+}
+
+type JobHandler struct {
+	jobService *services.JobService
+	upgrader   *websocket.Upgrader
+}
+
+func NewJobHandler(jobService *services.JobService, cfg *config.Config) *JobHandler {
+	return &JobHandler{jobService: jobService, upgrader: newSocketUpgrader(cfg)}
+}
+
+func (h *JobHandler) GetJobs(c *gin.Context) {
+	page, limit := utils.ParsePagination(c, 20)
+
+	jobs, total, err := h.jobService.GetJobs(c.Request.Context(), c.Query("type"), page, limit)
+	if err != nil {
+		utils.SendInternalError(c, "Failed to fetch jobs", err)
+		return
+	}
+
+	response := types.NewPaginated("jobs", jobs, page, limit, total)
+
+	utils.SendSuccess(c, "Jobs retrieved successfully", response)
+}
+
+func (h *JobHandler) GetJob(c *gin.Context) {
+	jobID, err := strconv.ParseUint(c.Param("job_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid job ID")
+		return
+	}
+
+	job, err := h.jobService.GetJobByID(c.Request.Context(), uint(jobID))
+	if err != nil {
+		sendJobError(c, "Failed to fetch job", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Job retrieved successfully", job)
+}
+
+// GetJobLogs returns the stored log of a job. Pass after_id to continue from
+// the last line a console has already seen.
+func (h *JobHandler) GetJobLogs(c *gin.Context) {
+	jobID, err := strconv.ParseUint(c.Param("job_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid job ID")
+		return
+	}
+
+	afterID, _ := strconv.ParseUint(c.DefaultQuery("after_id", "0"), 10, 32)
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "500"))
+	if limit < 1 || limit > 1000 {
+		limit = 500
+	}
+
+	logs, err := h.jobService.GetJobLogs(c.Request.Context(), uint(jobID), uint(afterID), limit)
+	if err != nil {
+		sendJobError(c, "Failed to fetch job logs", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Job logs retrieved successfully", logs)
+}
+
+// StreamJobs upgrades to a WebSocket and pushes live job messages. With
+// ?job_id= only that job is followed, otherwise every job is streamed.
+func (h *JobHandler) StreamJobs(c *gin.Context) {
+	var jobID uint64
+	if raw := c.Query("job_id"); raw != "" {
+		var err error
+		jobID, err = strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			utils.SendValidationError(c, "Invalid job ID")
+			return
+		}
+		if _, err := h.jobService.GetJobByID(c.Request.Context(), uint(jobID)); err != nil {
+			sendJobError(c, "Failed to fetch job", err)
+			return
+		}
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.Error("Failed to upgrade job stream: ", err)
+		return
+	}
+	defer conn.Close()
+
+	messages, unsubscribe := h.jobService.Hub().Subscribe(uint(jobID))
+	defer unsubscribe()
+
+	// Reader loop: only needed to process pongs and notice disconnects
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadDeadline(time.Now().Add(jobSocketPongTimeout))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(jobSocketPongTimeout))
+		})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(jobSocketPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(jobSocketWriteTimeout))
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(jobSocketWriteTimeout))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func sendJobError(c *gin.Context, message string, err error) {
+	if errors.Is(err, services.ErrJobNotFound) {
+		utils.SendError(c, http.StatusNotFound, "Job not found", err)
+		return
+	}
+	utils.SendInternalError(c, message, err)
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type MetaHandler struct {
+	metaService *services.MetaService
+}
+
+func NewMetaHandler(metaService *services.MetaService) *MetaHandler {
+	return &MetaHandler{metaService: metaService}
+}
+
+// GetMeta returns the API capabilities document for SDKs and the frontend
+func (h *MetaHandler) GetMeta(c *gin.Context) {
+	meta := h.metaService.GetMeta(c.Request.Context(), c.GetUint("store_id"))
+	// Served under every API version; report the one the client called
+	meta.BasePath = strings.TrimSuffix(c.FullPath(), "/meta")
+
+	c.Header("Cache-Control", "public, max-age=300")
+	utils.SendSuccess(c, "API metadata retrieved successfully", meta)
+}
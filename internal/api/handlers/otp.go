@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type OTPHandler struct {
+	otpService *services.OTPService
+}
+
+func NewOTPHandler(otpService *services.OTPService) *OTPHandler {
+	return &OTPHandler{otpService: otpService}
+}
+
+// SendCode texts a verification code to the given phone number
+func (h *OTPHandler) SendCode(c *gin.Context) {
+	var req models.SendOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	response, err := h.otpService.SendCode(c.Request.Context(), req.Phone, req.Purpose, c.ClientIP())
+	if err != nil {
+		sendOTPError(c, "Failed to send verification code", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Verification code sent", response)
+}
+
+func (h *OTPHandler) VerifyCode(c *gin.Context) {
+	var req models.VerifyOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	if err := h.otpService.VerifyCode(c.Request.Context(), req.Phone, req.Purpose, req.Code); err != nil {
+		sendOTPError(c, "Failed to verify code", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Phone number verified", nil)
+}
+
+func sendOTPError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrOTPRateLimited):
+		utils.SendError(c, http.StatusTooManyRequests, message, err)
+	case errors.Is(err, services.ErrOTPInvalid), errors.Is(err, services.ErrOTPExpired):
+		utils.SendError(c, http.StatusBadRequest, message, err)
+	case errors.Is(err, services.ErrOTPTooManyAttempts):
+		utils.SendError(c, http.StatusTooManyRequests, message, err)
+	case errors.Is(err, services.ErrSMSDelivery):
+		utils.SendError(c, http.StatusBadGateway, message, err)
+	case errors.Is(err, services.ErrInvalidInput):
+		utils.SendError(c, http.StatusUnprocessableEntity, message, err)
+	default:
+		sendServiceError(c, message, err)
+	}
+}
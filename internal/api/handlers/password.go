@@ -28,6 +28,7 @@ func (h *PasswordHandler) ForgotPassword(c *gin.Context) {
 		})
 		return
 	}
+	req.StoreID = c.GetUint("store_id")
 
 	if err := h.authService.ForgotPassword(req); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type PreferenceHandler struct {
+	preferenceService *services.PreferenceService
+}
+
+func NewPreferenceHandler(preferenceService *services.PreferenceService) *PreferenceHandler {
+	return &PreferenceHandler{preferenceService: preferenceService}
+}
+
+func (h *PreferenceHandler) GetPreferences(c *gin.Context) {
+	pref, err := h.preferenceService.GetPreferences(c.Request.Context(), c.GetUint("user_id"))
+	if err != nil {
+		utils.SendInternalError(c, "Failed to fetch preferences", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Preferences retrieved successfully", pref)
+}
+
+func (h *PreferenceHandler) UpdatePreferences(c *gin.Context) {
+	var req models.UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	pref, err := h.preferenceService.UpdatePreferences(c.Request.Context(), c.GetUint("user_id"), &req)
+	if err != nil {
+		sendServiceError(c, "Failed to update preferences", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Preferences updated successfully", pref)
+}
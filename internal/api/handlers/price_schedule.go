@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type PriceScheduleHandler struct {
+	priceScheduleService *services.PriceScheduleService
+}
+
+func NewPriceScheduleHandler(priceScheduleService *services.PriceScheduleService) *PriceScheduleHandler {
+	return &PriceScheduleHandler{priceScheduleService: priceScheduleService}
+}
+
+// GetPriceChanges lists a product's scheduled price changes and sale windows
+func (h *PriceScheduleHandler) GetPriceChanges(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return
+	}
+
+	changes, err := h.priceScheduleService.GetPriceChanges(c.Request.Context(), uint(productID))
+	if err != nil {
+		sendServiceError(c, "Failed to fetch price changes", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Price changes retrieved successfully", changes)
+}
+
+// SchedulePriceChange schedules a new price; with ends_at it is a sale window
+func (h *PriceScheduleHandler) SchedulePriceChange(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return
+	}
+
+	var req models.SchedulePriceChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	change, err := h.priceScheduleService.SchedulePriceChange(c.Request.Context(), c.GetUint("user_id"), uint(productID), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrPriceChangeOverlap) {
+			utils.SendError(c, http.StatusConflict, "Price change overlaps another scheduled change", err)
+			return
+		}
+		sendServiceError(c, "Failed to schedule price change", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Price change scheduled",
+		Data:    change,
+	})
+}
+
+// CancelPriceChange cancels a pending change or ends a running sale early
+func (h *PriceScheduleHandler) CancelPriceChange(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return
+	}
+	changeID, err := strconv.ParseUint(c.Param("change_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid price change ID")
+		return
+	}
+
+	change, err := h.priceScheduleService.CancelPriceChange(c.Request.Context(), uint(productID), uint(changeID))
+	if err != nil {
+		sendServiceError(c, "Failed to cancel price change", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Price change cancelled", change)
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+// QuotePrice prices ?quantity= units of a product after bulk discounts
+func (h *ProductHandler) QuotePrice(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return
+	}
+	quantity, err := strconv.Atoi(c.DefaultQuery("quantity", "1"))
+	if err != nil {
+		utils.SendValidationError(c, "Invalid quantity")
+		return
+	}
+
+	quote, err := h.productService.QuotePrice(c.Request.Context(), c.GetUint("user_id"), uint(productID), quantity)
+	if err != nil {
+		sendServiceError(c, "Failed to price product", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Price calculated successfully", quote)
+}
+
+// GetPriceTiers lists a product's bulk price tiers
+func (h *AdminHandler) GetPriceTiers(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return
+	}
+
+	tiers, err := h.adminService.GetPriceTiers(c.Request.Context(), uint(productID))
+	if err != nil {
+		sendServiceError(c, "Failed to fetch price tiers", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Price tiers retrieved successfully", tiers)
+}
+
+// AddPriceTier adds a bulk price to a product
+func (h *AdminHandler) AddPriceTier(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return
+	}
+
+	var req models.CreatePriceTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	tier, err := h.adminService.AddPriceTier(c.Request.Context(), uint(productID), &req)
+	if err != nil {
+		sendPriceTierError(c, "Failed to add price tier", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Price tier added successfully",
+		Data:    tier,
+	})
+}
+
+func (h *AdminHandler) UpdatePriceTier(c *gin.Context) {
+	productID, tierID, ok := parsePriceTierIDs(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdatePriceTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	tier, err := h.adminService.UpdatePriceTier(c.Request.Context(), productID, tierID, &req)
+	if err != nil {
+		sendPriceTierError(c, "Failed to update price tier", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Price tier updated successfully", tier)
+}
+
+func (h *AdminHandler) DeletePriceTier(c *gin.Context) {
+	productID, tierID, ok := parsePriceTierIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.adminService.DeletePriceTier(c.Request.Context(), productID, tierID); err != nil {
+		sendServiceError(c, "Failed to delete price tier", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Price tier deleted successfully", nil)
+}
+
+func parsePriceTierIDs(c *gin.Context) (uint, uint, bool) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return 0, 0, false
+	}
+	tierID, err := strconv.ParseUint(c.Param("tier_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid price tier ID")
+		return 0, 0, false
+	}
+	return uint(productID), uint(tierID), true
+}
+
+func sendPriceTierError(c *gin.Context, message string, err error) {
+	if errors.Is(err, services.ErrPriceTierExists) {
+		utils.SendError(c, http.StatusConflict, message, err)
+		return
+	}
+	sendServiceError(c, message, err)
+}
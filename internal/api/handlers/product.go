@@ -1,21 +1,26 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
 	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
 )
 
 
 type ProductHandler struct {
 	productService *services.ProductService
+	counterService *services.CounterService
 }
 
-func NewProductHandler(productService *services.ProductService) *ProductHandler {
+func NewProductHandler(productService *services.ProductService, counterService *services.CounterService) *ProductHandler {
 	return &ProductHandler{
 		productService: productService,
+		counterService: counterService,
 	}
 }
 
@@ -26,6 +31,7 @@ func NewProductHandler(productService *services.ProductService) *ProductHandler
 		status := c.Query("status")
 		page, _ := strconv.Atoi(c.Query("page"))
 		limit, _ := strconv.Atoi(c.Query("limit"))
+		minReviews, _ := strconv.Atoi(c.Query("min_reviews"))
 		filter := services.ProductFilter{
 			Category:   c.Query("category"),
 			Material:      c.Query("material"),
@@ -35,27 +41,44 @@ func NewProductHandler(productService *services.ProductService) *ProductHandler
 			Status:   status,
 			Page:       page,
 			Limit:      limit,
+			Sort:       c.Query("sort"),
+			MinReviews: minReviews,
+			ExactCount: c.Query("exact_count") == "true",
+			InStock:    c.Query("in_stock") == "true",
 		}
+		proj, err := productProjection(c, services.ExpandImages, services.ExpandServices)
+		if err != nil {
+			utils.SendError(c, http.StatusBadRequest, "Invalid fields or expand parameter", err)
+			return
+		}
+		filter.Projection = proj
+		filter.StoreID = c.GetUint("store_id")
 		products, err := h.productService.GetProducts(c.Request.Context(), filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to retrieve products",
-			"error":   err.Error(),
-		})
+		if errors.Is(err, services.ErrInvalidFilter) {
+			utils.SendError(c, http.StatusBadRequest, "Invalid product filter", err)
+			return
+		}
+		utils.SendInternalError(c, "Failed to retrieve products", err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "success",
-		"message": "Products retrieved successfully",
-		"data":    products,
-	})
+	// Customer group members see their group's prices
+	if err := h.productService.ApplyCustomerPricing(c.Request.Context(), c.GetUint("user_id"), products.Items); err != nil {
+		utils.SendInternalError(c, "Failed to retrieve products", err)
+		return
+	}
+	shaped, err := proj.ShapePage(products)
+	if err != nil {
+		utils.SendInternalError(c, "Failed to retrieve products", err)
+		return
+	}
+	utils.SendSuccess(c, "Products retrieved successfully", shaped)
 }
 
 
 
 func (h *ProductHandler) GetProduct(c *gin.Context) {
-	productID, err := strconv.Atoi(c.Param("id"))
+	productID, err := strconv.Atoi(c.Param("product_id"))
 	if err != nil {	
 		c.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
@@ -64,25 +87,37 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 		})
 		return
 	}
-	product, err := h.productService.GetProductByID(c.Request.Context(), uint(productID))
+	proj, err := productProjection(c, services.ExpandImages, services.ExpandServices)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to retrieve product",
-			"error":   err.Error(),
-		})
+		utils.SendError(c, http.StatusBadRequest, "Invalid fields or expand parameter", err)
+		return
+	}
+	product, err := h.productService.GetProductByID(c.Request.Context(), uint(productID), proj)
+	if err != nil {
+		sendServiceError(c, "Failed to retrieve product", err)
+		return
+	}
+	h.counterService.RecordView(product.ID)
+	priced := []models.Product{*product}
+	if err := h.productService.ApplyCustomerPricing(c.Request.Context(), c.GetUint("user_id"), priced); err != nil {
+		utils.SendInternalError(c, "Failed to retrieve product", err)
+		return
+	}
+	shaped, err := proj.Shape(&priced[0])
+	if err != nil {
+		utils.SendInternalError(c, "Failed to retrieve product", err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "success",
 		"message": "Product retrieved successfully",
-		"data":    product,
+		"data":    shaped,
 	})
 }
 
 
 func (h *ProductHandler) GetCategories(c *gin.Context) {
-	categories, err := h.productService.GetCategories(c.Request.Context())
+	categories, err := h.productService.GetCategories(c.Request.Context(), c.GetUint("store_id"))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "error",
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/types"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type ProductExtractionHandler struct {
+	extractionService *services.ProductExtractionService
+}
+
+func NewProductExtractionHandler(extractionService *services.ProductExtractionService) *ProductExtractionHandler {
+	return &ProductExtractionHandler{extractionService: extractionService}
+}
+
+// ExtractProducts stages uploaded photos and returns the extracted drafts
+func (h *ProductExtractionHandler) ExtractProducts(c *gin.Context) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		utils.SendValidationError(c, "Failed to parse multipart form")
+		return
+	}
+
+	response, err := h.extractionService.StageImages(c.Request.Context(), form.File["images"], c.GetUint("user_id"))
+	if err != nil {
+		sendProductDraftError(c, "Failed to extract products", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Products extracted successfully", response)
+}
+
+func (h *ProductExtractionHandler) GetDrafts(c *gin.Context) {
+	page, limit := utils.ParsePagination(c, 20)
+
+	drafts, total, err := h.extractionService.GetDrafts(c.Request.Context(), c.DefaultQuery("status", models.ProductDraftStatusPending), page, limit)
+	if err != nil {
+		sendProductDraftError(c, "Failed to fetch product drafts", err)
+		return
+	}
+
+	response := types.NewPaginated("drafts", drafts, page, limit, total)
+
+	utils.SendSuccess(c, "Product drafts retrieved successfully", response)
+}
+
+func (h *ProductExtractionHandler) GetDraft(c *gin.Context) {
+	draftID, err := strconv.ParseUint(c.Param("draft_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid draft ID")
+		return
+	}
+
+	draft, err := h.extractionService.GetDraft(c.Request.Context(), uint(draftID))
+	if err != nil {
+		sendProductDraftError(c, "Failed to fetch product draft", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Product draft retrieved successfully", draft)
+}
+
+// GetDraftImage serves a staged image for review
+func (h *ProductExtractionHandler) GetDraftImage(c *gin.Context) {
+	draftID, err := strconv.ParseUint(c.Param("draft_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid draft ID")
+		return
+	}
+
+	path, err := h.extractionService.GetStagedImage(c.Request.Context(), uint(draftID), c.Param("name"))
+	if err != nil {
+		sendProductDraftError(c, "Failed to fetch staged image", err)
+		return
+	}
+
+	c.File(path)
+}
+
+func (h *ProductExtractionHandler) ApproveDraft(c *gin.Context) {
+	draftID, err := strconv.ParseUint(c.Param("draft_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid draft ID")
+		return
+	}
+
+	var req models.ApproveProductDraftRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.SendValidationError(c, "Invalid request data: "+err.Error())
+			return
+		}
+	}
+
+	product, err := h.extractionService.ApproveDraft(c.Request.Context(), uint(draftID), c.GetUint("user_id"), &req)
+	if err != nil {
+		sendProductDraftError(c, "Failed to approve product draft", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Product draft approved successfully",
+		Data:    product,
+	})
+}
+
+func (h *ProductExtractionHandler) RejectDraft(c *gin.Context) {
+	draftID, err := strconv.ParseUint(c.Param("draft_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid draft ID")
+		return
+	}
+
+	if err := h.extractionService.RejectDraft(c.Request.Context(), uint(draftID), c.GetUint("user_id")); err != nil {
+		sendProductDraftError(c, "Failed to reject product draft", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Product draft rejected successfully", nil)
+}
+
+func sendProductDraftError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrProductDraftNotFound):
+		utils.SendError(c, http.StatusNotFound, "Product draft not found", err)
+	case errors.Is(err, services.ErrImageNotFound):
+		utils.SendError(c, http.StatusNotFound, "Image not found", err)
+	case errors.Is(err, services.ErrProductDraftNotPending):
+		utils.SendError(c, http.StatusConflict, message, err)
+	case errors.Is(err, services.ErrFastAPIUnavailable):
+		utils.SendError(c, http.StatusServiceUnavailable, message, err)
+	case errors.Is(err, services.ErrFastAPIBadInput):
+		utils.SendError(c, http.StatusBadRequest, message, err)
+	case errors.Is(err, services.ErrExtractionFailed):
+		utils.SendError(c, http.StatusBadGateway, message, err)
+	case errors.Is(err, services.ErrInvalidInput):
+		utils.SendError(c, http.StatusUnprocessableEntity, message, err)
+	default:
+		sendServiceError(c, message, err)
+	}
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+const xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+type ProductImportHandler struct {
+	importService *services.ProductImportService
+}
+
+func NewProductImportHandler(importService *services.ProductImportService) *ProductImportHandler {
+	return &ProductImportHandler{importService: importService}
+}
+
+// UploadImport is step one: upload a CSV/XLSX file and get its headers back
+func (h *ProductImportHandler) UploadImport(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		utils.SendValidationError(c, "No file provided")
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		utils.SendValidationError(c, "Failed to open file")
+		return
+	}
+	defer src.Close()
+
+	content, err := io.ReadAll(src)
+	if err != nil {
+		utils.SendValidationError(c, "Failed to read file")
+		return
+	}
+
+	response, err := h.importService.UploadImport(c.Request.Context(), content, file.Filename, c.GetUint("user_id"))
+	if err != nil {
+		sendProductImportError(c, "Failed to upload import file", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Import file uploaded successfully", response)
+}
+
+func (h *ProductImportHandler) GetImport(c *gin.Context) {
+	importID, err := strconv.ParseUint(c.Param("import_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid import ID")
+		return
+	}
+
+	productImport, err := h.importService.GetImport(c.Request.Context(), uint(importID))
+	if err != nil {
+		sendProductImportError(c, "Failed to fetch import", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Import retrieved successfully", productImport)
+}
+
+// RunImport is step two: submit the column mapping and start the import job
+func (h *ProductImportHandler) RunImport(c *gin.Context) {
+	importID, err := strconv.ParseUint(c.Param("import_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid import ID")
+		return
+	}
+
+	var req models.RunProductImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	productImport, err := h.importService.RunImport(c.Request.Context(), uint(importID), &req, c.GetUint("user_id"))
+	if err != nil {
+		sendProductImportError(c, "Failed to start import", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.APIResponse{
+		Success: true,
+		Message: "Product import started",
+		Data:    productImport,
+	})
+}
+
+// DownloadErrors returns the failed rows as an XLSX workbook
+func (h *ProductImportHandler) DownloadErrors(c *gin.Context) {
+	importID, err := strconv.ParseUint(c.Param("import_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid import ID")
+		return
+	}
+
+	workbook, err := h.importService.ErrorWorkbook(c.Request.Context(), uint(importID))
+	if err != nil {
+		sendProductImportError(c, "Failed to build error workbook", err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="import-%d-errors.xlsx"`, importID))
+	c.Data(http.StatusOK, xlsxContentType, workbook)
+}
+
+func sendProductImportError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrProductImportNotFound):
+		utils.SendError(c, http.StatusNotFound, "Import not found", err)
+	case errors.Is(err, services.ErrImportAlreadyRunning):
+		utils.SendError(c, http.StatusConflict, message, err)
+	case errors.Is(err, services.ErrInvalidInput):
+		utils.SendError(c, http.StatusUnprocessableEntity, message, err)
+	default:
+		sendServiceError(c, message, err)
+	}
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/types"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type ProductReportHandler struct {
+	reportService *services.ProductReportService
+}
+
+func NewProductReportHandler(reportService *services.ProductReportService) *ProductReportHandler {
+	return &ProductReportHandler{reportService: reportService}
+}
+
+// ReportProduct lets a customer report a counterfeit or incorrect listing
+func (h *ProductReportHandler) ReportProduct(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return
+	}
+
+	var req models.CreateProductReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	report, err := h.reportService.ReportProduct(c.Request.Context(), uint(productID), c.GetUint("user_id"), &req)
+	if err != nil {
+		sendProductReportError(c, "Failed to report product", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Product reported successfully",
+		Data:    report,
+	})
+}
+
+// GetReports returns the admin report queue, optionally filtered by ?status=
+// and ?product_id=
+func (h *ProductReportHandler) GetReports(c *gin.Context) {
+	status := c.Query("status")
+	switch status {
+	case "", models.ReportStatusOpen, models.ReportStatusReviewing, models.ReportStatusResolved, models.ReportStatusDismissed:
+	default:
+		utils.SendValidationError(c, "Invalid status")
+		return
+	}
+
+	var productID uint64
+	if raw := c.Query("product_id"); raw != "" {
+		var err error
+		if productID, err = strconv.ParseUint(raw, 10, 32); err != nil {
+			utils.SendValidationError(c, "Invalid product ID")
+			return
+		}
+	}
+
+	page, limit := utils.ParsePagination(c, 20)
+
+	reports, total, err := h.reportService.GetReports(c.Request.Context(), status, uint(productID), page, limit)
+	if err != nil {
+		sendProductReportError(c, "Failed to fetch reports", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Reports retrieved successfully", types.NewPaginated("reports", reports, page, limit, total))
+}
+
+func (h *ProductReportHandler) GetReport(c *gin.Context) {
+	id, ok := parseReportID(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.reportService.GetReport(c.Request.Context(), id)
+	if err != nil {
+		sendProductReportError(c, "Failed to fetch report", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Report retrieved successfully", report)
+}
+
+func (h *ProductReportHandler) UpdateReport(c *gin.Context) {
+	id, ok := parseReportID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateProductReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	report, err := h.reportService.UpdateReport(c.Request.Context(), id, c.GetUint("user_id"), &req)
+	if err != nil {
+		sendProductReportError(c, "Failed to update report", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Report updated successfully", report)
+}
+
+func parseReportID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("report_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid report ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func sendProductReportError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrProductReportNotFound):
+		utils.SendError(c, http.StatusNotFound, "Report not found", err)
+	case errors.Is(err, services.ErrProductNotFound):
+		utils.SendError(c, http.StatusNotFound, "Product not found", err)
+	case errors.Is(err, services.ErrProductAlreadyReported):
+		utils.SendError(c, http.StatusConflict, "You have already reported this product", err)
+	default:
+		sendServiceError(c, message, err)
+	}
+}
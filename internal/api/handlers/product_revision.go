@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/types"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+// GetProductRevisions lists a product's revisions, newest first, with the
+// fields each one changed
+func (h *AdminHandler) GetProductRevisions(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return
+	}
+	page, limit := utils.ParsePagination(c, 20)
+
+	revisions, total, err := h.adminService.GetProductRevisions(c.Request.Context(), uint(productID), page, limit)
+	if err != nil {
+		sendServiceError(c, "Failed to fetch revisions", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Revisions retrieved successfully", types.NewPaginated("revisions", revisions, page, limit, total))
+}
+
+// RestoreProductRevision reverts a product to one of its revisions. Vendors
+// cannot move products between vendors, so for them the vendor is kept.
+func (h *AdminHandler) RestoreProductRevision(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return
+	}
+	number, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || number < 1 {
+		utils.SendValidationError(c, "Invalid revision number")
+		return
+	}
+
+	restoreVendor := c.GetUint("vendor_id") == 0
+	product, err := h.adminService.RestoreProductRevision(c.Request.Context(), c.GetUint("user_id"), uint(productID), number, restoreVendor)
+	if err != nil {
+		sendServiceError(c, "Failed to restore revision", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Product restored successfully", product)
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+// GetProductServices lists the services (name and link) of an active product
+func (h *ProductHandler) GetProductServices(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return
+	}
+
+	services, err := h.productService.GetProductServices(c.Request.Context(), uint(productID))
+	if err != nil {
+		sendServiceError(c, "Failed to fetch services", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Services retrieved successfully", services)
+}
+
+// AddProductService adds one service to a product
+func (h *AdminHandler) AddProductService(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return
+	}
+
+	var req models.CreateServiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	service, err := h.adminService.AddProductService(c.Request.Context(), uint(productID), &req)
+	if err != nil {
+		sendServiceError(c, "Failed to add service", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Service added successfully",
+		Data:    service,
+	})
+}
+
+func (h *AdminHandler) UpdateProductService(c *gin.Context) {
+	productID, serviceID, ok := parseProductServiceIDs(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateServiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	service, err := h.adminService.UpdateProductService(c.Request.Context(), productID, serviceID, &req)
+	if err != nil {
+		sendServiceError(c, "Failed to update service", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Service updated successfully", service)
+}
+
+func (h *AdminHandler) DeleteProductService(c *gin.Context) {
+	productID, serviceID, ok := parseProductServiceIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.adminService.DeleteProductService(c.Request.Context(), productID, serviceID); err != nil {
+		sendServiceError(c, "Failed to delete service", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Service deleted successfully", nil)
+}
+
+func parseProductServiceIDs(c *gin.Context) (uint, uint, bool) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return 0, 0, false
+	}
+	serviceID, err := strconv.ParseUint(c.Param("service_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid service ID")
+		return 0, 0, false
+	}
+	return uint(productID), uint(serviceID), true
+}
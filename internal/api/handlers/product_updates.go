@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+)
+
+type ProductUpdateHandler struct {
+	hub *services.ProductUpdateHub
+}
+
+func NewProductUpdateHandler(hub *services.ProductUpdateHub) *ProductUpdateHandler {
+	return &ProductUpdateHandler{hub: hub}
+}
+
+// StreamProductUpdates pushes stock and price changes for ?ids=1,2,3 as
+// Server-Sent Events. The stream opens with the current state of each
+// product, then sends a "product" event whenever one changes. Products are
+// shown as the public product API shows them to the caller: only active
+// products of the resolved store, hidden while out of stock where configured,
+// and priced for the caller's customer group when signed in.
+func (h *ProductUpdateHandler) StreamProductUpdates(c *gin.Context) {
+	var productIDs []uint
+	seen := make(map[uint]bool)
+	for _, raw := range strings.Split(c.Query("ids"), ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil || id == 0 {
+			utils.SendValidationError(c, fmt.Sprintf("Invalid product ID %q", raw))
+			return
+		}
+		if !seen[uint(id)] {
+			seen[uint(id)] = true
+			productIDs = append(productIDs, uint(id))
+		}
+	}
+	if len(productIDs) == 0 {
+		utils.SendValidationError(c, "At least one product ID is required in ids")
+		return
+	}
+	if len(productIDs) > services.MaxProductSubscriptions {
+		utils.SendValidationError(c, fmt.Sprintf("At most %d product IDs can be followed", services.MaxProductSubscriptions))
+		return
+	}
+
+	viewer := services.ProductViewer{StoreID: c.GetUint("store_id"), UserID: c.GetUint("user_id")}
+
+	// Subscribe before reading the state so no change slips in between
+	changes, unsubscribe := h.hub.Subscribe(productIDs)
+	defer unsubscribe()
+
+	snapshot, err := h.hub.State(c.Request.Context(), viewer, productIDs)
+	if err != nil {
+		utils.SendInternalError(c, "Failed to fetch products", err)
+		return
+	}
+
+	// Products the viewer can't see are never mentioned, so the stream gives
+	// away nothing about drafts or other stores' catalogues
+	visible := make(map[uint]bool, len(snapshot))
+	startSSE(c)
+	for _, update := range snapshot {
+		visible[update.ProductID] = true
+		if err := writeSSE(c, "", "product", update); err != nil {
+			return
+		}
+	}
+
+	ticker := time.NewTicker(sseHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case productID, ok := <-changes:
+			if !ok {
+				return
+			}
+			state, err := h.hub.State(c.Request.Context(), viewer, []uint{productID})
+			if err != nil {
+				logger.Error("Failed to read product update:", err)
+				continue
+			}
+			var update services.ProductUpdate
+			switch {
+			case len(state) == 1:
+				update = state[0]
+				visible[productID] = true
+			case visible[productID]:
+				update = services.UnavailableUpdate(productID)
+				delete(visible, productID)
+			default:
+				continue
+			}
+			if err := writeSSE(c, "", "product", update); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := writeSSEHeartbeat(c); err != nil {
+				return
+			}
+		}
+	}
+}
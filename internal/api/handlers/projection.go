@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+)
+
+// productProjection reads ?fields= and ?expand=. It returns nil when neither
+// is present so endpoints keep their historical response shape; an empty
+// ?expand= asks for no relations at all.
+func productProjection(c *gin.Context, defaultExpand ...string) (*services.ProductProjection, error) {
+	fields, hasFields := c.GetQuery("fields")
+	expand, hasExpand := c.GetQuery("expand")
+	if !hasFields && !hasExpand {
+		return nil, nil
+	}
+
+	var fieldList, expandList []string
+	if hasFields {
+		fieldList = strings.Split(fields, ",")
+	}
+	if hasExpand {
+		expandList = strings.Split(expand, ",")
+	}
+	return services.NewProductProjection(fieldList, expandList, defaultExpand...)
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/types"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type QuoteHandler struct {
+	quoteService *services.QuoteService
+}
+
+func NewQuoteHandler(quoteService *services.QuoteService) *QuoteHandler {
+	return &QuoteHandler{quoteService: quoteService}
+}
+
+// CreateQuote submits a quote request for a list of products
+func (h *QuoteHandler) CreateQuote(c *gin.Context) {
+	var req models.CreateQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	quote, err := h.quoteService.CreateQuote(c.Request.Context(), c.GetUint("user_id"), &req)
+	if err != nil {
+		sendServiceError(c, "Failed to request quote", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Quote requested successfully",
+		Data:    quote,
+	})
+}
+
+func (h *QuoteHandler) GetMyQuotes(c *gin.Context) {
+	page, limit := utils.ParsePagination(c, 20)
+
+	quotes, total, err := h.quoteService.GetUserQuotes(c.Request.Context(), c.GetUint("user_id"), page, limit)
+	if err != nil {
+		utils.SendInternalError(c, "Failed to fetch quotes", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Quotes retrieved successfully", types.NewPaginated("quotes", quotes, page, limit, total))
+}
+
+func (h *QuoteHandler) GetMyQuote(c *gin.Context) {
+	quoteID, ok := parseQuoteID(c)
+	if !ok {
+		return
+	}
+
+	quote, err := h.quoteService.GetQuote(c.Request.Context(), c.GetUint("user_id"), quoteID)
+	if err != nil {
+		sendServiceError(c, "Failed to fetch quote", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Quote retrieved successfully", quote)
+}
+
+// DownloadQuote serves the customer's quote as a PDF
+func (h *QuoteHandler) DownloadQuote(c *gin.Context) {
+	quoteID, ok := parseQuoteID(c)
+	if !ok {
+		return
+	}
+
+	pdf, err := h.quoteService.GetQuotePDF(c.Request.Context(), c.GetUint("user_id"), quoteID)
+	if err != nil {
+		sendServiceError(c, "Failed to render quote", err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="quote-%d.pdf"`, quoteID))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+func (h *QuoteHandler) AcceptQuote(c *gin.Context) {
+	quoteID, ok := parseQuoteID(c)
+	if !ok {
+		return
+	}
+
+	quote, err := h.quoteService.AcceptQuote(c.Request.Context(), c.GetUint("user_id"), quoteID)
+	if err != nil {
+		sendQuoteError(c, "Failed to accept quote", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Quote accepted successfully", quote)
+}
+
+func (h *QuoteHandler) DeclineQuote(c *gin.Context) {
+	quoteID, ok := parseQuoteID(c)
+	if !ok {
+		return
+	}
+
+	quote, err := h.quoteService.DeclineQuote(c.Request.Context(), c.GetUint("user_id"), quoteID)
+	if err != nil {
+		sendQuoteError(c, "Failed to decline quote", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Quote declined successfully", quote)
+}
+
+// GetQuotes lists quotes for admins, optionally filtered by ?status=
+func (h *QuoteHandler) GetQuotes(c *gin.Context) {
+	page, limit := utils.ParsePagination(c, 20)
+
+	quotes, total, err := h.quoteService.GetQuotes(c.Request.Context(), c.Query("status"), page, limit)
+	if err != nil {
+		utils.SendInternalError(c, "Failed to fetch quotes", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Quotes retrieved successfully", types.NewPaginated("quotes", quotes, page, limit, total))
+}
+
+func (h *QuoteHandler) GetQuote(c *gin.Context) {
+	quoteID, ok := parseQuoteID(c)
+	if !ok {
+		return
+	}
+
+	quote, err := h.quoteService.GetQuote(c.Request.Context(), 0, quoteID)
+	if err != nil {
+		sendServiceError(c, "Failed to fetch quote", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Quote retrieved successfully", quote)
+}
+
+// RespondToQuote prices a quote and emails it to the customer
+func (h *QuoteHandler) RespondToQuote(c *gin.Context) {
+	quoteID, ok := parseQuoteID(c)
+	if !ok {
+		return
+	}
+
+	var req models.RespondQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	quote, err := h.quoteService.RespondToQuote(c.Request.Context(), c.GetUint("user_id"), quoteID, &req)
+	if err != nil {
+		sendQuoteError(c, "Failed to respond to quote", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Quote sent successfully", quote)
+}
+
+func (h *QuoteHandler) RejectQuote(c *gin.Context) {
+	quoteID, ok := parseQuoteID(c)
+	if !ok {
+		return
+	}
+
+	var req models.RejectQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	quote, err := h.quoteService.RejectQuote(c.Request.Context(), c.GetUint("user_id"), quoteID, req.Note)
+	if err != nil {
+		sendQuoteError(c, "Failed to reject quote", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Quote rejected successfully", quote)
+}
+
+func parseQuoteID(c *gin.Context) (uint, bool) {
+	quoteID, err := strconv.ParseUint(c.Param("quote_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid quote ID")
+		return 0, false
+	}
+	return uint(quoteID), true
+}
+
+func sendQuoteError(c *gin.Context, message string, err error) {
+	if errors.Is(err, services.ErrQuoteState) {
+		utils.SendError(c, http.StatusConflict, message, err)
+		return
+	}
+	sendServiceError(c, message, err)
+}
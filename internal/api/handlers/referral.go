@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/types"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+// DeviceIDHeader carries a client-generated ID that stays the same across
+// sessions on one device, used by the referral fraud checks
+const DeviceIDHeader = "X-Device-ID"
+
+type ReferralHandler struct {
+	referralService *services.ReferralService
+}
+
+func NewReferralHandler(referralService *services.ReferralService) *ReferralHandler {
+	return &ReferralHandler{referralService: referralService}
+}
+
+// GetMyReferral returns the caller's referral code and how many signups it
+// brought in
+func (h *ReferralHandler) GetMyReferral(c *gin.Context) {
+	summary, err := h.referralService.GetMyReferral(c.Request.Context(), c.GetUint("user_id"), c.ClientIP(), c.GetHeader(DeviceIDHeader))
+	if err != nil {
+		sendServiceError(c, "Failed to fetch referral code", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Referral code retrieved successfully", summary)
+}
+
+// GetReferrals lists referred signups, optionally only ?flagged=true ones or
+// those of ?referrer_id=
+func (h *ReferralHandler) GetReferrals(c *gin.Context) {
+	var referrerID uint64
+	if raw := c.Query("referrer_id"); raw != "" {
+		var err error
+		if referrerID, err = strconv.ParseUint(raw, 10, 32); err != nil {
+			utils.SendValidationError(c, "Invalid referrer ID")
+			return
+		}
+	}
+
+	page, limit := utils.ParsePagination(c, 20)
+
+	referrals, total, err := h.referralService.GetReferrals(c.Request.Context(), c.Query("flagged") == "true", uint(referrerID), page, limit)
+	if err != nil {
+		sendServiceError(c, "Failed to fetch referrals", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Referrals retrieved successfully", types.NewPaginated("referrals", referrals, page, limit, total))
+}
+
+// GetReferralReport ranks referrers by signups brought in
+func (h *ReferralHandler) GetReferralReport(c *gin.Context) {
+	page, limit := utils.ParsePagination(c, 20)
+
+	referrers, total, err := h.referralService.GetReferralReport(c.Request.Context(), page, limit)
+	if err != nil {
+		sendServiceError(c, "Failed to build referral report", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Referral report retrieved successfully", types.NewPaginated("referrers", referrers, page, limit, total))
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/types"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type ReportHandler struct {
+	reportService *services.ReportService
+}
+
+func NewReportHandler(reportService *services.ReportService) *ReportHandler {
+	return &ReportHandler{reportService: reportService}
+}
+
+// GetReports lists generated reports with signed download links, optionally
+// filtered by ?type=
+func (h *ReportHandler) GetReports(c *gin.Context) {
+	page, limit := utils.ParsePagination(c, 20)
+
+	reports, total, err := h.reportService.GetReports(c.Request.Context(), c.Query("type"), page, limit)
+	if err != nil {
+		utils.SendInternalError(c, "Failed to fetch reports", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Reports retrieved successfully", types.NewPaginated("reports", reports, page, limit, total))
+}
+
+func (h *ReportHandler) GetReport(c *gin.Context) {
+	id, ok := parseReportID(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.reportService.GetReport(c.Request.Context(), id)
+	if err != nil {
+		sendServiceError(c, "Failed to fetch report", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Report retrieved successfully", report)
+}
+
+// CreateReport starts generating a report; poll it, or its job, until it
+// completes
+func (h *ReportHandler) CreateReport(c *gin.Context) {
+	var req models.CreateReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	report, err := h.reportService.GenerateReport(c.Request.Context(), c.GetUint("user_id"), "manual", req.Type, req.Format)
+	if err != nil {
+		sendServiceError(c, "Failed to start report", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.APIResponse{
+		Success: true,
+		Message: "Report started",
+		Data:    report,
+	})
+}
+
+func (h *ReportHandler) DeleteReport(c *gin.Context) {
+	id, ok := parseReportID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.reportService.DeleteReport(c.Request.Context(), id); err != nil {
+		sendServiceError(c, "Failed to delete report", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Report deleted successfully", nil)
+}
@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
 	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/types"
 	"github.com/princeprakhar/ecommerce-backend/internal/utils"
 )
 
@@ -82,13 +85,51 @@ func (h *ReviewHandler) CreateReview(c *gin.Context) {
 
 	review, err := h.reviewService.CreateReview(userID, req)
 	if err != nil {
-		utils.SendError(c, http.StatusBadRequest, "Failed to create review", err)
+		sendReviewError(c, "Failed to create review", err)
 		return
 	}
 
 	utils.SendSuccess(c, "Review created successfully", review)
 }
 
+// UpdateReview lets the author (or an admin) change a review's rating and comment
+func (h *ReviewHandler) UpdateReview(c *gin.Context) {
+	reviewID, err := strconv.ParseUint(c.Param("review_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid review ID")
+		return
+	}
+
+	var req services.UpdateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	review, err := h.reviewService.UpdateReview(uint(reviewID), c.GetUint("user_id"), c.GetString("user_role") == "admin", req)
+	if err != nil {
+		sendReviewError(c, "Failed to update review", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Review updated successfully", review)
+}
+
+func (h *ReviewHandler) DeleteReview(c *gin.Context) {
+	reviewID, err := strconv.ParseUint(c.Param("review_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid review ID")
+		return
+	}
+
+	if err := h.reviewService.DeleteReview(uint(reviewID), c.GetUint("user_id"), c.GetString("user_role") == "admin"); err != nil {
+		sendReviewError(c, "Failed to delete review", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Review deleted successfully", nil)
+}
+
 func (h *ReviewHandler) GetProductReviews(c *gin.Context) {
 	productIDStr := c.Param("product_id")
 	productID, err := strconv.ParseUint(productIDStr, 10, 32)
@@ -97,23 +138,15 @@ func (h *ReviewHandler) GetProductReviews(c *gin.Context) {
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 || limit > 100 {
-		limit = 10
-	}
+	page, limit := utils.ParsePagination(c, 10)
 
-	reviews, err := h.reviewService.GetProductReviews(uint(productID), page, limit)
+	reviews, total, err := h.reviewService.GetProductReviews(uint(productID), c.GetUint("user_id"), page, limit)
 	if err != nil {
 		utils.SendInternalError(c, "Failed to fetch reviews", err)
 		return
 	}
 
-	utils.SendSuccess(c, "Reviews retrieved successfully", reviews)
+	utils.SendSuccess(c, "Reviews retrieved successfully", types.NewPaginated("reviews", reviews, page, limit, total))
 }
 
 func (h *ReviewHandler) LikeReview(c *gin.Context) {
@@ -136,7 +169,7 @@ func (h *ReviewHandler) LikeReview(c *gin.Context) {
 
 	err = h.reviewService.LikeReview(userID, uint(reviewID), req.IsLike)
 	if err != nil {
-		utils.SendError(c, http.StatusBadRequest, "Failed to like/dislike review", err)
+		sendReviewError(c, "Failed to like/dislike review", err)
 		return
 	}
 
@@ -185,17 +218,98 @@ func (h *ReviewHandler) ModerateReview(c *gin.Context) {
 
 	var req struct {
 		Action string `json:"action" binding:"required"`
+		Reason string `json:"reason"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.SendValidationError(c, "Invalid request data")
 		return
 	}
 
-	err = h.reviewService.ModerateReview(uint(reviewID), req.Action)
+	err = h.reviewService.ModerateReview(uint(reviewID), c.GetUint("user_id"), req.Action, req.Reason)
 	if err != nil {
-		utils.SendError(c, http.StatusBadRequest, "Failed to moderate review", err)
+		sendReviewModerationError(c, "Failed to moderate review", err)
 		return
 	}
 
 	utils.SendSuccess(c, "Review moderated successfully", nil)
+}
+
+// UpdateReviewVisibility lets moderators publish, hold, shadow-hide or remove a review
+func (h *ReviewHandler) UpdateReviewVisibility(c *gin.Context) {
+	reviewID, err := strconv.ParseUint(c.Param("review_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid review ID")
+		return
+	}
+
+	var req models.UpdateReviewVisibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	review, err := h.reviewService.SetReviewVisibility(uint(reviewID), c.GetUint("user_id"), req.Visibility, req.Reason)
+	if err != nil {
+		sendReviewModerationError(c, "Failed to update review visibility", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Review visibility updated successfully", review)
+}
+
+func (h *ReviewHandler) GetReviewModerationHistory(c *gin.Context) {
+	reviewID, err := strconv.ParseUint(c.Param("review_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid review ID")
+		return
+	}
+
+	history, err := h.reviewService.GetReviewModerationHistory(uint(reviewID))
+	if err != nil {
+		sendReviewModerationError(c, "Failed to fetch moderation history", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Moderation history retrieved successfully", history)
+}
+
+// GetReviewEdits lists the earlier versions of a review for moderators
+func (h *ReviewHandler) GetReviewEdits(c *gin.Context) {
+	reviewID, err := strconv.ParseUint(c.Param("review_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid review ID")
+		return
+	}
+
+	edits, err := h.reviewService.GetReviewEdits(uint(reviewID))
+	if err != nil {
+		sendReviewError(c, "Failed to fetch review edits", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Review edits retrieved successfully", edits)
+}
+
+func sendReviewError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrReviewNotFound):
+		utils.SendError(c, http.StatusNotFound, "Review not found", err)
+	case errors.Is(err, services.ErrReviewForbidden):
+		utils.SendForbidden(c, err.Error())
+	case errors.Is(err, services.ErrInvalidInput):
+		utils.SendError(c, http.StatusUnprocessableEntity, message, err)
+	default:
+		sendServiceError(c, message, err)
+	}
+}
+
+func sendReviewModerationError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrReviewNotFound):
+		utils.SendError(c, http.StatusNotFound, "Review not found", err)
+	case errors.Is(err, services.ErrInvalidVisibilityChange):
+		utils.SendError(c, http.StatusBadRequest, message, err)
+	default:
+		sendServiceError(c, message, err)
+	}
 }
\ No newline at end of file
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type SavedSearchHandler struct {
+	savedSearchService *services.SavedSearchService
+	productService     *services.ProductService
+}
+
+func NewSavedSearchHandler(savedSearchService *services.SavedSearchService, productService *services.ProductService) *SavedSearchHandler {
+	return &SavedSearchHandler{savedSearchService: savedSearchService, productService: productService}
+}
+
+func (h *SavedSearchHandler) GetSavedSearches(c *gin.Context) {
+	searches, err := h.savedSearchService.GetSavedSearches(c.Request.Context(), c.GetUint("user_id"))
+	if err != nil {
+		sendSavedSearchError(c, "Failed to fetch saved searches", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Saved searches retrieved successfully", gin.H{"saved_searches": searches})
+}
+
+func (h *SavedSearchHandler) GetSavedSearch(c *gin.Context) {
+	id, ok := parseSavedSearchID(c)
+	if !ok {
+		return
+	}
+
+	search, err := h.savedSearchService.GetSavedSearch(c.Request.Context(), c.GetUint("user_id"), id)
+	if err != nil {
+		sendSavedSearchError(c, "Failed to fetch saved search", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Saved search retrieved successfully", search)
+}
+
+func (h *SavedSearchHandler) CreateSavedSearch(c *gin.Context) {
+	var req models.CreateSavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	search, err := h.savedSearchService.CreateSavedSearch(c.Request.Context(), c.GetUint("user_id"), &req)
+	if err != nil {
+		sendSavedSearchError(c, "Failed to create saved search", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Saved search created successfully",
+		Data:    search,
+	})
+}
+
+func (h *SavedSearchHandler) UpdateSavedSearch(c *gin.Context) {
+	id, ok := parseSavedSearchID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateSavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	search, err := h.savedSearchService.UpdateSavedSearch(c.Request.Context(), c.GetUint("user_id"), id, &req)
+	if err != nil {
+		sendSavedSearchError(c, "Failed to update saved search", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Saved search updated successfully", search)
+}
+
+func (h *SavedSearchHandler) DeleteSavedSearch(c *gin.Context) {
+	id, ok := parseSavedSearchID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.savedSearchService.DeleteSavedSearch(c.Request.Context(), c.GetUint("user_id"), id); err != nil {
+		sendSavedSearchError(c, "Failed to delete saved search", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Saved search deleted successfully", nil)
+}
+
+// GetSavedSearchProducts runs a saved search against the public catalogue
+func (h *SavedSearchHandler) GetSavedSearchProducts(c *gin.Context) {
+	id, ok := parseSavedSearchID(c)
+	if !ok {
+		return
+	}
+
+	search, err := h.savedSearchService.GetSavedSearch(c.Request.Context(), c.GetUint("user_id"), id)
+	if err != nil {
+		sendSavedSearchError(c, "Failed to fetch saved search", err)
+		return
+	}
+
+	filter := services.SavedSearchFilter(search)
+	filter.Page, filter.Limit = utils.ParsePagination(c, services.DefaultPageSize)
+	filter.StoreID = c.GetUint("store_id")
+
+	products, err := h.productService.GetProducts(c.Request.Context(), filter)
+	if err != nil {
+		sendSavedSearchError(c, "Failed to run saved search", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Products retrieved successfully", products)
+}
+
+func parseSavedSearchID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("search_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid saved search ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func sendSavedSearchError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrSavedSearchNotFound):
+		utils.SendError(c, http.StatusNotFound, "Saved search not found", err)
+	case errors.Is(err, services.ErrSavedSearchLimit):
+		utils.SendError(c, http.StatusConflict, message, err)
+	case errors.Is(err, services.ErrInvalidInput), errors.Is(err, services.ErrInvalidFilter):
+		utils.SendError(c, http.StatusUnprocessableEntity, message, err)
+	default:
+		sendServiceError(c, message, err)
+	}
+}
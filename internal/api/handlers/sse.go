@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const sseHeartbeatInterval = 25 * time.Second
+
+// startSSE sends the Server-Sent Events headers and an initial comment so
+// the client sees the stream open straight away
+func startSSE(c *gin.Context) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // stop nginx from buffering the stream
+	c.Status(http.StatusOK)
+	fmt.Fprint(c.Writer, ": connected\n\n")
+	c.Writer.Flush()
+}
+
+// writeSSE sends one event; an empty id leaves the id field out
+func writeSSE(c *gin.Context, id, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if id != "" {
+		if _, err := fmt.Fprintf(c.Writer, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	c.Writer.Flush()
+	return nil
+}
+
+// writeSSEHeartbeat sends a comment line so proxies keep an idle stream open
+func writeSSEHeartbeat(c *gin.Context) error {
+	if _, err := fmt.Fprint(c.Writer, ": ping\n\n"); err != nil {
+		return err
+	}
+	c.Writer.Flush()
+	return nil
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/types"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type StockHandler struct {
+	stockService *services.StockService
+}
+
+func NewStockHandler(stockService *services.StockService) *StockHandler {
+	return &StockHandler{stockService: stockService}
+}
+
+// AdjustStock applies a signed stock change with a reason code
+func (h *StockHandler) AdjustStock(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return
+	}
+
+	var req models.StockAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	movement, err := h.stockService.AdjustStock(c.Request.Context(), uint(productID), c.GetUint("user_id"), &req)
+	if err != nil {
+		sendStockError(c, "Failed to adjust stock", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Stock adjusted successfully", movement)
+}
+
+// GetStockMovements returns the stock ledger of a product, newest first.
+// ?location_id= returns a store location's ledger instead of online stock.
+func (h *StockHandler) GetStockMovements(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return
+	}
+	locationID, err := strconv.ParseUint(c.DefaultQuery("location_id", "0"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid location ID")
+		return
+	}
+
+	page, limit := utils.ParsePagination(c, 20)
+
+	movements, total, err := h.stockService.GetStockMovements(c.Request.Context(), uint(productID), uint(locationID), page, limit)
+	if err != nil {
+		sendStockError(c, "Failed to fetch stock movements", err)
+		return
+	}
+
+	response := types.NewPaginated("movements", movements, page, limit, total)
+
+	utils.SendSuccess(c, "Stock movements retrieved successfully", response)
+}
+
+func sendStockError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrProductNotFound):
+		utils.SendError(c, http.StatusNotFound, "Product not found", err)
+	case errors.Is(err, services.ErrInsufficientStock):
+		utils.SendError(c, http.StatusConflict, message, err)
+	case errors.Is(err, services.ErrInvalidInput):
+		utils.SendError(c, http.StatusUnprocessableEntity, message, err)
+	default:
+		sendServiceError(c, message, err)
+	}
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/types"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type StorageHandler struct {
+	storageService *services.StorageService
+}
+
+func NewStorageHandler(storageService *services.StorageService) *StorageHandler {
+	return &StorageHandler{storageService: storageService}
+}
+
+// GetReport returns the latest S3 reconciliation report
+func (h *StorageHandler) GetReport(c *gin.Context) {
+	report, err := h.storageService.GetLatestReport(c.Request.Context())
+	if err != nil {
+		if errors.Is(err, services.ErrStorageReportNotFound) {
+			utils.SendError(c, http.StatusNotFound, "No storage report yet", err)
+			return
+		}
+		utils.SendInternalError(c, "Failed to fetch storage report", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Storage report retrieved successfully", report)
+}
+
+// Reconcile starts a reconciliation job; progress shows up in the job console
+func (h *StorageHandler) Reconcile(c *gin.Context) {
+	job, err := h.storageService.StartReconcile(c.Request.Context(), c.GetUint("user_id"))
+	if err != nil {
+		utils.SendInternalError(c, "Failed to start storage reconciliation", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.APIResponse{
+		Success: true,
+		Message: "Storage reconciliation started",
+		Data:    job,
+	})
+}
+
+// GetPendingDeletions lists S3 objects queued for deletion. ?stuck=true
+// limits the list to deletions that keep failing.
+func (h *StorageHandler) GetPendingDeletions(c *gin.Context) {
+	stuckOnly := c.Query("stuck") == "true"
+	page, limit := utils.ParsePagination(c, 20)
+
+	deletions, total, err := h.storageService.GetPendingDeletions(c.Request.Context(), stuckOnly, page, limit)
+	if err != nil {
+		utils.SendInternalError(c, "Failed to fetch pending deletions", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Pending deletions retrieved successfully", types.NewPaginated("deletions", deletions, page, limit, total))
+}
+
+// RetryDeletion makes a pending deletion due on the worker's next tick
+func (h *StorageHandler) RetryDeletion(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("deletion_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid deletion ID")
+		return
+	}
+
+	deletion, err := h.storageService.RetryDeletion(c.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, services.ErrPendingDeletionNotFound) {
+			utils.SendError(c, http.StatusNotFound, "Pending deletion not found", err)
+			return
+		}
+		utils.SendInternalError(c, "Failed to retry deletion", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Deletion rescheduled", deletion)
+}
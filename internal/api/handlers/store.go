@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type StoreHandler struct {
+	storeService *services.StoreService
+}
+
+func NewStoreHandler(storeService *services.StoreService) *StoreHandler {
+	return &StoreHandler{storeService: storeService}
+}
+
+func (h *StoreHandler) GetStores(c *gin.Context) {
+	stores, err := h.storeService.GetStores(c.Request.Context())
+	if err != nil {
+		sendStoreError(c, "Failed to fetch stores", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Stores retrieved successfully", gin.H{"stores": stores})
+}
+
+func (h *StoreHandler) GetStore(c *gin.Context) {
+	id, ok := parseStoreID(c)
+	if !ok {
+		return
+	}
+
+	store, err := h.storeService.GetStore(c.Request.Context(), id)
+	if err != nil {
+		sendStoreError(c, "Failed to fetch store", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Store retrieved successfully", store)
+}
+
+func (h *StoreHandler) CreateStore(c *gin.Context) {
+	var req models.CreateStoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	store, err := h.storeService.CreateStore(c.Request.Context(), &req)
+	if err != nil {
+		sendStoreError(c, "Failed to create store", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Store created successfully",
+		Data:    store,
+	})
+}
+
+func (h *StoreHandler) UpdateStore(c *gin.Context) {
+	id, ok := parseStoreID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateStoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	store, err := h.storeService.UpdateStore(c.Request.Context(), id, &req)
+	if err != nil {
+		sendStoreError(c, "Failed to update store", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Store updated successfully", store)
+}
+
+func (h *StoreHandler) DeleteStore(c *gin.Context) {
+	id, ok := parseStoreID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.storeService.DeleteStore(c.Request.Context(), id); err != nil {
+		sendStoreError(c, "Failed to delete store", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Store deleted successfully", nil)
+}
+
+func parseStoreID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("store_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid store ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func sendStoreError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrStoreNotFound):
+		utils.SendError(c, http.StatusNotFound, "Store not found", err)
+	case errors.Is(err, services.ErrStoreExists), errors.Is(err, services.ErrStoreInUse):
+		utils.SendError(c, http.StatusConflict, message, err)
+	case errors.Is(err, services.ErrInvalidInput):
+		utils.SendError(c, http.StatusUnprocessableEntity, message, err)
+	default:
+		sendServiceError(c, message, err)
+	}
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/types"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type LocationHandler struct {
+	locationService *services.LocationService
+}
+
+func NewLocationHandler(locationService *services.LocationService) *LocationHandler {
+	return &LocationHandler{locationService: locationService}
+}
+
+// GetPickupLocations lists the locations a customer can collect from.
+// ?product_id= and ?quantity= mark which hold enough stock; ?lat= and ?lng=
+// sort them by distance.
+func (h *LocationHandler) GetPickupLocations(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.DefaultQuery("product_id", "0"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid product ID")
+		return
+	}
+	quantity, err := strconv.Atoi(c.DefaultQuery("quantity", "1"))
+	if err != nil {
+		utils.SendValidationError(c, "Invalid quantity")
+		return
+	}
+	lat, lng, ok := parseCoordinates(c)
+	if !ok {
+		return
+	}
+
+	locations, err := h.locationService.GetPickupLocations(c.Request.Context(), c.GetUint("store_id"), uint(productID), quantity, lat, lng)
+	if err != nil {
+		sendServiceError(c, "Failed to fetch pickup locations", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Pickup locations retrieved successfully", gin.H{"locations": locations})
+}
+
+func (h *LocationHandler) GetLocations(c *gin.Context) {
+	locations, err := h.locationService.GetLocations(c.Request.Context(), c.GetUint("store_id"))
+	if err != nil {
+		utils.SendInternalError(c, "Failed to fetch locations", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Locations retrieved successfully", gin.H{"locations": locations})
+}
+
+func (h *LocationHandler) GetLocation(c *gin.Context) {
+	id, ok := parseLocationID(c)
+	if !ok {
+		return
+	}
+
+	location, err := h.locationService.GetLocation(c.Request.Context(), c.GetUint("store_id"), id)
+	if err != nil {
+		sendServiceError(c, "Failed to fetch location", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Location retrieved successfully", location)
+}
+
+func (h *LocationHandler) CreateLocation(c *gin.Context) {
+	var req models.CreateStoreLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	location, err := h.locationService.CreateLocation(c.Request.Context(), c.GetUint("store_id"), &req)
+	if err != nil {
+		sendServiceError(c, "Failed to create location", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Location created successfully",
+		Data:    location,
+	})
+}
+
+func (h *LocationHandler) UpdateLocation(c *gin.Context) {
+	id, ok := parseLocationID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateStoreLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	location, err := h.locationService.UpdateLocation(c.Request.Context(), c.GetUint("store_id"), id, &req)
+	if err != nil {
+		sendServiceError(c, "Failed to update location", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Location updated successfully", location)
+}
+
+func (h *LocationHandler) DeleteLocation(c *gin.Context) {
+	id, ok := parseLocationID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.locationService.DeleteLocation(c.Request.Context(), c.GetUint("store_id"), id); err != nil {
+		sendServiceError(c, "Failed to delete location", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Location deleted successfully", nil)
+}
+
+// GetLocationStock lists the products a location holds
+func (h *LocationHandler) GetLocationStock(c *gin.Context) {
+	id, ok := parseLocationID(c)
+	if !ok {
+		return
+	}
+	page, limit := utils.ParsePagination(c, 50)
+
+	stock, total, err := h.locationService.GetLocationStock(c.Request.Context(), c.GetUint("store_id"), id, page, limit)
+	if err != nil {
+		sendServiceError(c, "Failed to fetch location stock", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Location stock retrieved successfully", types.NewPaginated("stock", stock, page, limit, total))
+}
+
+// AdjustLocationStock applies a signed stock change at a location
+func (h *LocationHandler) AdjustLocationStock(c *gin.Context) {
+	id, ok := parseLocationID(c)
+	if !ok {
+		return
+	}
+
+	var req models.LocationStockAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	movement, err := h.locationService.AdjustLocationStock(c.Request.Context(), c.GetUint("store_id"), id, c.GetUint("user_id"), &req)
+	if err != nil {
+		sendStockError(c, "Failed to adjust location stock", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Location stock adjusted successfully", movement)
+}
+
+func parseLocationID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("location_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid location ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// parseCoordinates reads the optional ?lat= and ?lng= pair
+func parseCoordinates(c *gin.Context) (*float64, *float64, bool) {
+	latParam, lngParam := c.Query("lat"), c.Query("lng")
+	if latParam == "" && lngParam == "" {
+		return nil, nil, true
+	}
+	lat, latErr := strconv.ParseFloat(latParam, 64)
+	lng, lngErr := strconv.ParseFloat(lngParam, 64)
+	if latErr != nil || lngErr != nil || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		utils.SendValidationError(c, "Invalid coordinates")
+		return nil, nil, false
+	}
+	return &lat, &lng, true
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type SuggestHandler struct {
+	suggestService *services.SuggestService
+}
+
+func NewSuggestHandler(suggestService *services.SuggestService) *SuggestHandler {
+	return &SuggestHandler{suggestService: suggestService}
+}
+
+// Suggest returns typeahead suggestions for ?q= from product titles,
+// categories and materials
+func (h *SuggestHandler) Suggest(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		utils.SendValidationError(c, "Query parameter q is required")
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "8"))
+
+	suggestions, err := h.suggestService.Suggest(query, limit, c.GetUint("store_id"))
+	if err != nil {
+		utils.SendInternalError(c, "Failed to fetch suggestions", err)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=60")
+	utils.SendSuccess(c, "Suggestions retrieved successfully", gin.H{"suggestions": suggestions})
+}
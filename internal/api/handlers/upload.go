@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type UploadHandler struct {
+	uploadService *services.UploadService
+}
+
+func NewUploadHandler(uploadService *services.UploadService) *UploadHandler {
+	return &UploadHandler{uploadService: uploadService}
+}
+
+// CreateUpload starts a resumable upload. The response gives the part size
+// and count; send each part to PUT /uploads/:upload_id/parts/:part_number.
+func (h *UploadHandler) CreateUpload(c *gin.Context) {
+	var req models.CreateUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	session, err := h.uploadService.CreateUpload(c.Request.Context(), c.GetUint("user_id"), &req)
+	if err != nil {
+		sendUploadError(c, "Failed to start upload", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Upload started",
+		Data:    session,
+	})
+}
+
+// GetUpload returns an upload with the parts received so far
+func (h *UploadHandler) GetUpload(c *gin.Context) {
+	id, ok := parseUploadID(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.uploadService.GetUpload(c.Request.Context(), id)
+	if err != nil {
+		sendUploadError(c, "Failed to fetch upload", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Upload retrieved successfully", session)
+}
+
+// UploadPart stores one part, sent as the raw request body
+func (h *UploadHandler) UploadPart(c *gin.Context) {
+	id, ok := parseUploadID(c)
+	if !ok {
+		return
+	}
+	number, err := strconv.Atoi(c.Param("part_number"))
+	if err != nil {
+		utils.SendValidationError(c, "Invalid part number")
+		return
+	}
+
+	session, err := h.uploadService.UploadPart(c.Request.Context(), id, number, c.Request.Body)
+	if err != nil {
+		sendUploadError(c, "Failed to upload part", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Part uploaded successfully", session)
+}
+
+// CompleteUpload assembles the uploaded parts into the final file
+func (h *UploadHandler) CompleteUpload(c *gin.Context) {
+	id, ok := parseUploadID(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.uploadService.CompleteUpload(c.Request.Context(), id)
+	if err != nil {
+		sendUploadError(c, "Failed to complete upload", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Upload completed successfully", session)
+}
+
+// AbortUpload cancels an upload and discards its parts
+func (h *UploadHandler) AbortUpload(c *gin.Context) {
+	id, ok := parseUploadID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uploadService.AbortUpload(c.Request.Context(), id); err != nil {
+		sendUploadError(c, "Failed to abort upload", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Upload aborted successfully", nil)
+}
+
+func parseUploadID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("upload_id"))
+	if err != nil {
+		utils.SendValidationError(c, "Invalid upload ID")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func sendUploadError(c *gin.Context, message string, err error) {
+	if errors.Is(err, services.ErrUploadClosed) {
+		utils.SendError(c, http.StatusConflict, message, err)
+		return
+	}
+	sendServiceError(c, message, err)
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type VendorHandler struct {
+	vendorService *services.VendorService
+}
+
+func NewVendorHandler(vendorService *services.VendorService) *VendorHandler {
+	return &VendorHandler{vendorService: vendorService}
+}
+
+func (h *VendorHandler) GetVendors(c *gin.Context) {
+	vendors, err := h.vendorService.GetVendors(c.Request.Context())
+	if err != nil {
+		sendVendorError(c, "Failed to fetch vendors", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Vendors retrieved successfully", gin.H{"vendors": vendors})
+}
+
+func (h *VendorHandler) GetVendor(c *gin.Context) {
+	id, ok := parseVendorID(c)
+	if !ok {
+		return
+	}
+
+	vendor, err := h.vendorService.GetVendor(c.Request.Context(), id)
+	if err != nil {
+		sendVendorError(c, "Failed to fetch vendor", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Vendor retrieved successfully", vendor)
+}
+
+func (h *VendorHandler) CreateVendor(c *gin.Context) {
+	var req models.CreateVendorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	vendor, err := h.vendorService.CreateVendor(c.Request.Context(), &req)
+	if err != nil {
+		sendVendorError(c, "Failed to create vendor", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "Vendor created successfully",
+		Data:    vendor,
+	})
+}
+
+func (h *VendorHandler) UpdateVendor(c *gin.Context) {
+	id, ok := parseVendorID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateVendorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	vendor, err := h.vendorService.UpdateVendor(c.Request.Context(), id, &req)
+	if err != nil {
+		sendVendorError(c, "Failed to update vendor", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Vendor updated successfully", vendor)
+}
+
+func (h *VendorHandler) DeleteVendor(c *gin.Context) {
+	id, ok := parseVendorID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.vendorService.DeleteVendor(c.Request.Context(), id); err != nil {
+		sendVendorError(c, "Failed to delete vendor", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Vendor deleted successfully", nil)
+}
+
+func (h *VendorHandler) GetVendorUsers(c *gin.Context) {
+	id, ok := parseVendorID(c)
+	if !ok {
+		return
+	}
+
+	users, err := h.vendorService.GetVendorUsers(c.Request.Context(), id)
+	if err != nil {
+		sendVendorError(c, "Failed to fetch vendor users", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Vendor users retrieved successfully", gin.H{"users": users})
+}
+
+// AssignVendorUser gives an existing customer the vendor role
+func (h *VendorHandler) AssignVendorUser(c *gin.Context) {
+	id, ok := parseVendorID(c)
+	if !ok {
+		return
+	}
+
+	var req models.AssignVendorUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	user, err := h.vendorService.AssignUser(c.Request.Context(), id, req.UserID)
+	if err != nil {
+		sendVendorError(c, "Failed to assign vendor user", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Vendor user assigned successfully", user)
+}
+
+func (h *VendorHandler) RemoveVendorUser(c *gin.Context) {
+	id, ok := parseVendorID(c)
+	if !ok {
+		return
+	}
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid user ID")
+		return
+	}
+
+	if err := h.vendorService.RemoveUser(c.Request.Context(), id, uint(userID)); err != nil {
+		sendVendorError(c, "Failed to remove vendor user", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Vendor user removed successfully", nil)
+}
+
+func parseVendorID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("vendor_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid vendor ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func sendVendorError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrVendorNotFound):
+		utils.SendError(c, http.StatusNotFound, "Vendor not found", err)
+	case errors.Is(err, services.ErrVendorExists):
+		utils.SendError(c, http.StatusConflict, message, err)
+	case errors.Is(err, services.ErrInvalidInput):
+		utils.SendError(c, http.StatusUnprocessableEntity, message, err)
+	default:
+		sendServiceError(c, message, err)
+	}
+}
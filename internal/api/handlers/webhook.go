@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/types"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+type WebhookHandler struct {
+	webhookService *services.WebhookService
+}
+
+func NewWebhookHandler(webhookService *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	webhooks, err := h.webhookService.GetWebhooks(c.Request.Context())
+	if err != nil {
+		utils.SendInternalError(c, "Failed to fetch webhooks", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Webhooks retrieved successfully", webhooks)
+}
+
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	webhookID, err := strconv.ParseUint(c.Param("webhook_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid webhook ID")
+		return
+	}
+
+	webhook, err := h.webhookService.GetWebhookByID(c.Request.Context(), uint(webhookID))
+	if err != nil {
+		sendWebhookError(c, "Failed to fetch webhook", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Webhook retrieved successfully", webhook)
+}
+
+// CreateWebhook registers an endpoint; the signing secret is only returned here
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req models.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	response, err := h.webhookService.CreateWebhook(c.Request.Context(), &req)
+	if err != nil {
+		sendWebhookError(c, "Failed to create webhook", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Webhook created successfully", response)
+}
+
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	webhookID, err := strconv.ParseUint(c.Param("webhook_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid webhook ID")
+		return
+	}
+
+	var req models.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(c.Request.Context(), uint(webhookID), &req)
+	if err != nil {
+		sendWebhookError(c, "Failed to update webhook", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Webhook updated successfully", webhook)
+}
+
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	webhookID, err := strconv.ParseUint(c.Param("webhook_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid webhook ID")
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(c.Request.Context(), uint(webhookID)); err != nil {
+		sendWebhookError(c, "Failed to delete webhook", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Webhook deleted successfully", nil)
+}
+
+// GetDeliveries returns the delivery log of a webhook, newest first
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	webhookID, err := strconv.ParseUint(c.Param("webhook_id"), 10, 32)
+	if err != nil {
+		utils.SendValidationError(c, "Invalid webhook ID")
+		return
+	}
+
+	page, limit := utils.ParsePagination(c, 20)
+
+	deliveries, total, err := h.webhookService.GetDeliveries(c.Request.Context(), uint(webhookID), page, limit)
+	if err != nil {
+		sendWebhookError(c, "Failed to fetch webhook deliveries", err)
+		return
+	}
+
+	response := types.NewPaginated("deliveries", deliveries, page, limit, total)
+
+	utils.SendSuccess(c, "Webhook deliveries retrieved successfully", response)
+}
+
+func sendWebhookError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrWebhookNotFound):
+		utils.SendError(c, http.StatusNotFound, "Webhook not found", err)
+	case errors.Is(err, services.ErrInvalidInput):
+		utils.SendError(c, http.StatusUnprocessableEntity, message, err)
+	default:
+		sendServiceError(c, message, err)
+	}
+}
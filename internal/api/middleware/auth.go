@@ -1,15 +1,51 @@
 package middleware
 
 import (
+	"errors"
+	"net/http"
+	"slices"
 	"strings"
 	"github.com/gin-gonic/gin"
 	"github.com/princeprakhar/ecommerce-backend/internal/config"
 	"github.com/princeprakhar/ecommerce-backend/internal/utils"
 )
 
+// errPasswordExpired is the error code clients check to show the
+// change-password form
+var errPasswordExpired = errors.New("password_expired")
+
 func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
+	return authenticate(cfg, false, false)
+}
+
+// PasswordChangeAuthMiddleware is AuthMiddleware for the change-password
+// endpoint, which also accepts the token issued at login for an expired
+// password
+func PasswordChangeAuthMiddleware(cfg *config.Config) gin.HandlerFunc {
+	return authenticate(cfg, true, false)
+}
+
+// StreamAuthMiddleware is AuthMiddleware for WebSocket and EventSource
+// routes. Browsers cannot set headers on those requests, so it also accepts
+// a stream ticket issued for the route's path as ?ticket=.
+func StreamAuthMiddleware(cfg *config.Config) gin.HandlerFunc {
+	return authenticate(cfg, false, true)
+}
+
+func authenticate(cfg *config.Config, allowPasswordChange, allowStreamTicket bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
+		fromTicket := false
+		if allowStreamTicket && authHeader == "" && c.Query("ticket") != "" {
+			authHeader = "Bearer " + c.Query("ticket")
+			fromTicket = true
+		}
+		// Web clients in cookie mode send the token as an httpOnly cookie
+		if authHeader == "" {
+			if cookie, err := c.Cookie(utils.AccessTokenCookie); err == nil && cookie != "" {
+				authHeader = "Bearer " + cookie
+			}
+		}
 		if authHeader == "" {
 			utils.SendUnauthorized(c, "Authorization header required")
 			c.Abort()
@@ -29,14 +65,83 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		switch {
+		case claims.Type == string(utils.StreamTicket):
+			// A ticket only opens the stream it was issued for
+			if !fromTicket || !slices.Contains(claims.Audience, c.Request.URL.Path) {
+				utils.SendUnauthorized(c, "Invalid token")
+				c.Abort()
+				return
+			}
+		case fromTicket:
+			// Other tokens never travel in the query string
+			utils.SendUnauthorized(c, "Invalid token")
+			c.Abort()
+			return
+		case claims.Type == string(utils.PasswordChangeToken):
+			if !allowPasswordChange {
+				utils.SendError(c, http.StatusForbidden, "Password expired, change it to continue", errPasswordExpired)
+				c.Abort()
+				return
+			}
+		case claims.Type != string(utils.AccessToken):
+			// Refresh tokens are only accepted by the refresh endpoint
+			utils.SendUnauthorized(c, "Invalid token")
+			c.Abort()
+			return
+		}
+		if !inResolvedStore(c, claims) {
+			utils.SendUnauthorized(c, "Token was issued for another store")
+			c.Abort()
+			return
+		}
 
-		c.Set("user_id", claims.UserID)
-		c.Set("user_email", claims.Email)
-		c.Set("user_role", claims.Role)
+		setUser(c, claims)
 		c.Next()
 	}
 }
 
+// OptionalAuthMiddleware populates the user claims when a valid bearer token or
+// access cookie is present but lets anonymous requests through untouched.
+func OptionalAuthMiddleware(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			if cookie, err := c.Cookie(utils.AccessTokenCookie); err == nil && cookie != "" {
+				authHeader = "Bearer " + cookie
+			}
+		}
+		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+		if tokenString == "" || tokenString == authHeader {
+			c.Next()
+			return
+		}
+
+		if claims, err := utils.ValidateToken(tokenString, cfg.JWTSecret); err == nil && claims.Type == string(utils.AccessToken) && inResolvedStore(c, claims) {
+			setUser(c, claims)
+		}
+		c.Next()
+	}
+}
+
+func setUser(c *gin.Context, claims *utils.Claims) {
+	c.Set("user_id", claims.UserID)
+	c.Set("user_email", claims.Email)
+	c.Set("user_role", claims.Role)
+	if claims.StoreID != nil {
+		c.Set("user_store_id", *claims.StoreID)
+	}
+	setImpersonator(c, claims)
+}
+
+// inResolvedStore reports whether claims may be used in the request's
+// store. Tokens of users without a store are accepted in every store, and
+// every token passes when no store was resolved (single-tenant mode).
+func inResolvedStore(c *gin.Context, claims *utils.Claims) bool {
+	storeID := c.GetUint("store_id")
+	return claims.StoreID == nil || storeID == 0 || *claims.StoreID == storeID
+}
+
 func AdminOnly() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role := c.GetString("user_role")
@@ -49,6 +154,20 @@ func AdminOnly() gin.HandlerFunc {
 	}
 }
 
+// DeploymentAdminOnly admits admins who don't belong to a store. Store
+// admins manage their own store's data but not the set of stores.
+func DeploymentAdminOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, storeScoped := c.Get("user_store_id")
+		if c.GetString("user_role") != "admin" || storeScoped {
+			utils.SendForbidden(c, "Deployment admin access required")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
 func CustomerOrAdmin() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role := c.GetString("user_role")
@@ -59,4 +178,4 @@ func CustomerOrAdmin() gin.HandlerFunc {
 		}
 		c.Next()
 	}
-}
\ No newline at end of file
+}
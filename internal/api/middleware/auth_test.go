@@ -0,0 +1,187 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/api/middleware"
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+const testJWTSecret = "middleware-test-secret-that-is-long-enough"
+
+func TestAuthMiddlewareTokenTypes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{JWTSecret: testJWTSecret}
+
+	access, _, err := utils.GenerateAccessToken(1, nil, "user@example.com", "customer", testJWTSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refresh, _, err := utils.GenerateRefreshToken(1, nil, "user@example.com", "customer", testJWTSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	passwordChange, _, err := utils.GeneratePasswordChangeToken(1, nil, "user@example.com", "admin", time.Minute, testJWTSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := gin.New()
+	router.GET("/auth", middleware.AuthMiddleware(cfg), func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/password", middleware.PasswordChangeAuthMiddleware(cfg), func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/optional", middleware.OptionalAuthMiddleware(cfg), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": c.GetUint("user_id")})
+	})
+
+	tests := []struct {
+		name   string
+		path   string
+		token  string
+		status int
+		body   string
+	}{
+		{"access token", "/auth", access, http.StatusOK, ""},
+		{"refresh token", "/auth", refresh, http.StatusUnauthorized, ""},
+		{"password change token", "/auth", passwordChange, http.StatusForbidden, ""},
+		{"password change token on the change endpoint", "/password", passwordChange, http.StatusOK, ""},
+		{"refresh token on the change endpoint", "/password", refresh, http.StatusUnauthorized, ""},
+		{"optional with access token", "/optional", access, http.StatusOK, `{"user_id":1}`},
+		{"optional with refresh token", "/optional", refresh, http.StatusOK, `{"user_id":0}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, rec.Code, rec.Body)
+			}
+			if tt.body != "" && rec.Body.String() != tt.body {
+				t.Fatalf("expected body %s, got %s", tt.body, rec.Body)
+			}
+		})
+	}
+}
+
+func TestAuthMiddlewareStores(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{JWTSecret: testJWTSecret}
+	storeOne, storeTwo := uint(1), uint(2)
+
+	token := func(storeID *uint, role string) string {
+		t.Helper()
+		access, _, err := utils.GenerateAccessToken(1, storeID, "user@example.com", role, testJWTSecret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return access
+	}
+
+	router := gin.New()
+	// Stands in for TenantMiddleware resolving the request to store 1
+	inStore := func(c *gin.Context) { c.Set("store_id", storeOne) }
+	router.GET("/auth", inStore, middleware.AuthMiddleware(cfg), func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/optional", inStore, middleware.OptionalAuthMiddleware(cfg), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": c.GetUint("user_id")})
+	})
+	router.GET("/single-tenant", middleware.AuthMiddleware(cfg), func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/stores", middleware.AuthMiddleware(cfg), middleware.DeploymentAdminOnly(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		name   string
+		path   string
+		token  string
+		status int
+		body   string
+	}{
+		{"token of the store", "/auth", token(&storeOne, "customer"), http.StatusOK, ""},
+		{"token of another store", "/auth", token(&storeTwo, "customer"), http.StatusUnauthorized, ""},
+		{"token without a store", "/auth", token(nil, "admin"), http.StatusOK, ""},
+		{"optional with a token of another store", "/optional", token(&storeTwo, "customer"), http.StatusOK, `{"user_id":0}`},
+		{"optional with a token of the store", "/optional", token(&storeOne, "customer"), http.StatusOK, `{"user_id":1}`},
+		{"single-tenant mode", "/single-tenant", token(&storeTwo, "customer"), http.StatusOK, ""},
+		{"store management by a deployment admin", "/stores", token(nil, "admin"), http.StatusOK, ""},
+		{"store management by a store admin", "/stores", token(&storeOne, "admin"), http.StatusForbidden, ""},
+		{"store management by a customer", "/stores", token(nil, "customer"), http.StatusForbidden, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, rec.Code, rec.Body)
+			}
+			if tt.body != "" && rec.Body.String() != tt.body {
+				t.Fatalf("expected body %s, got %s", tt.body, rec.Body)
+			}
+		})
+	}
+}
+
+func TestAuthMiddlewareStreamTickets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{JWTSecret: testJWTSecret}
+
+	access, _, err := utils.GenerateAccessToken(1, nil, "admin@example.com", "admin", testJWTSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticket := func(path string, ttl time.Duration) string {
+		t.Helper()
+		ticket, _, err := utils.GenerateStreamTicket(1, nil, "admin@example.com", "admin", 0, path, ttl, testJWTSecret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ticket
+	}
+
+	router := gin.New()
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/events", middleware.StreamAuthMiddleware(cfg), ok)
+	router.GET("/jobs/ws", middleware.StreamAuthMiddleware(cfg), ok)
+	router.GET("/products", middleware.AuthMiddleware(cfg), ok)
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		status int
+	}{
+		{"ticket for the stream", "/events?ticket=" + ticket("/events", time.Minute), "", http.StatusOK},
+		{"ticket for another stream", "/jobs/ws?ticket=" + ticket("/events", time.Minute), "", http.StatusUnauthorized},
+		{"expired ticket", "/events?ticket=" + ticket("/events", -time.Minute), "", http.StatusUnauthorized},
+		{"ticket on a regular route", "/products?ticket=" + ticket("/products", time.Minute), "", http.StatusUnauthorized},
+		{"ticket in the header", "/events", ticket("/events", time.Minute), http.StatusUnauthorized},
+		{"access token in the query string", "/events?ticket=" + access, "", http.StatusUnauthorized},
+		{"access_token parameter", "/events?access_token=" + access, "", http.StatusUnauthorized},
+		{"access token in the header", "/events", access, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Accept", "text/event-stream")
+			if tt.header != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, rec.Code, rec.Body)
+			}
+		})
+	}
+}
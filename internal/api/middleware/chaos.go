@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+// ChaosHeader tells clients a response was produced by chaos injection
+const ChaosHeader = "X-Chaos-Rule"
+
+// ChaosMiddleware delays, fails or drops requests according to the chaos
+// rules, for testing clients against realistic failures outside production.
+// Admin routes are exempt so the rules can always be switched off again.
+func ChaosMiddleware(chaos *services.ChaosService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if path, isAPI := apiRelativePath(c.Request.URL.Path); isAPI && strings.HasPrefix(path, "/admin/") {
+			c.Next()
+			return
+		}
+		fault := chaos.FaultFor(c.Request.Method, c.FullPath())
+		if fault == nil {
+			c.Next()
+			return
+		}
+
+		if fault.Latency > 0 {
+			timer := time.NewTimer(fault.Latency)
+			select {
+			case <-timer.C:
+			case <-c.Request.Context().Done():
+				timer.Stop()
+				c.Abort()
+				return
+			}
+		}
+
+		ruleID := strconv.FormatUint(uint64(fault.RuleID), 10)
+		switch {
+		case fault.Drop:
+			dropConnection(c)
+		case fault.Status != 0:
+			c.Header(ChaosHeader, ruleID)
+			utils.SendError(c, fault.Status, "Injected failure", nil)
+			c.Abort()
+		default:
+			c.Header(ChaosHeader, ruleID)
+			c.Next()
+		}
+	}
+}
+
+// dropConnection closes the connection without a response. HTTP/2 streams
+// cannot be hijacked, so they get an empty 502 instead.
+func dropConnection(c *gin.Context) {
+	c.Abort()
+	conn, _, err := c.Writer.Hijack()
+	if err != nil {
+		c.Status(http.StatusBadGateway)
+		return
+	}
+	conn.Close()
+}
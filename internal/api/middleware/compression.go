@@ -0,0 +1,261 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/gzip"
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+)
+
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+)
+
+var (
+	gzipWriters = sync.Pool{New: func() interface{} {
+		w, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+		return w
+	}}
+	brotliWriters = sync.Pool{New: func() interface{} {
+		return brotli.NewWriterLevel(io.Discard, brotli.DefaultCompression)
+	}}
+)
+
+// incompressibleTypes are already compressed; re-encoding only costs CPU
+var incompressibleTypes = []string{
+	"image/",
+	"video/",
+	"audio/",
+	"application/zip",
+	"application/gzip",
+	"application/x-gzip",
+	"application/pdf",
+	"text/event-stream",
+}
+
+// CompressionMiddleware encodes responses with brotli or gzip, whichever the
+// client prefers in Accept-Encoding. Bodies are held back until they reach
+// cfg.CompressionMinSize so small responses skip the overhead; a handler that
+// flushes (streamed lists) starts compressing immediately.
+func CompressionMiddleware(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !cfg.CompressionEnabled || c.Request.Method == http.MethodHead ||
+			strings.EqualFold(c.GetHeader("Upgrade"), "websocket") ||
+			strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{
+			ResponseWriter: c.Writer,
+			encoding:       encoding,
+			minSize:        cfg.CompressionMinSize,
+			status:         http.StatusOK,
+		}
+		c.Writer = writer
+		c.Next()
+		writer.close()
+		c.Writer = writer.ResponseWriter
+	}
+}
+
+// negotiateEncoding picks brotli or gzip from an Accept-Encoding header,
+// honouring q-values and preferring brotli on a tie.
+func negotiateEncoding(header string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != encodingBrotli && name != encodingGzip {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > bestQ || (q == bestQ && name == encodingBrotli) {
+			best, bestQ = name, q
+		}
+	}
+	return best
+}
+
+// compressWriter buffers the start of a body until it knows whether the
+// response is worth compressing, then either streams it through an encoder
+// or writes it unchanged.
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	minSize  int
+	status   int
+	buffer   bytes.Buffer
+	encoder  io.WriteCloser
+	started  bool // headers sent; output goes to encoder or straight through
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	w.status = code
+	if w.started {
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.started && w.buffer.Len() == 0 {
+		// Headers only (e.g. 204, aborts); nothing to compress
+		w.start(false)
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.started {
+		if !w.compressible() {
+			w.start(false)
+		} else {
+			w.buffer.Write(data)
+			if w.buffer.Len() < w.minSize {
+				return len(data), nil
+			}
+			if err := w.start(true); err != nil {
+				return 0, err
+			}
+			return len(data), nil
+		}
+	}
+
+	if w.encoder != nil {
+		return w.encoder.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush commits to compression so streamed output reaches the client
+func (w *compressWriter) Flush() {
+	if !w.started {
+		w.start(w.compressible())
+	}
+	if flusher, ok := w.encoder.(interface{ Flush() error }); ok {
+		flusher.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *compressWriter) Status() int {
+	if w.started {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+func (w *compressWriter) Written() bool {
+	return w.started || w.buffer.Len() > 0
+}
+
+func (w *compressWriter) Size() int {
+	if w.started {
+		return w.ResponseWriter.Size()
+	}
+	return w.buffer.Len()
+}
+
+// compressible reports whether the response as described so far can be encoded
+func (w *compressWriter) compressible() bool {
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" || header.Get("Content-Range") != "" {
+		return false
+	}
+	if w.status < http.StatusOK || w.status == http.StatusNoContent || w.status == http.StatusNotModified {
+		return false
+	}
+	contentType := strings.ToLower(header.Get("Content-Type"))
+	for _, prefix := range incompressibleTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// start sends the headers and drains whatever has been buffered so far
+func (w *compressWriter) start(compress bool) error {
+	w.started = true
+	if compress {
+		header := w.Header()
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		header.Del("Accept-Ranges")
+		w.encoder = w.newEncoder()
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+
+	if w.buffer.Len() == 0 {
+		return nil
+	}
+	var err error
+	if w.encoder != nil {
+		_, err = w.encoder.Write(w.buffer.Bytes())
+	} else {
+		_, err = w.ResponseWriter.Write(w.buffer.Bytes())
+	}
+	w.buffer.Reset()
+	return err
+}
+
+func (w *compressWriter) newEncoder() io.WriteCloser {
+	if w.encoding == encodingBrotli {
+		encoder := brotliWriters.Get().(*brotli.Writer)
+		encoder.Reset(w.ResponseWriter)
+		return encoder
+	}
+	encoder := gzipWriters.Get().(*gzip.Writer)
+	encoder.Reset(w.ResponseWriter)
+	return encoder
+}
+
+// close runs after the handler: small bodies go out as-is, encoders are
+// finished and returned to their pool.
+func (w *compressWriter) close() {
+	if !w.started {
+		// Short bodies, or none at all: send the recorded status unencoded
+		w.start(false)
+	}
+	if w.encoder == nil {
+		return
+	}
+
+	w.encoder.Close()
+	switch encoder := w.encoder.(type) {
+	case *brotli.Writer:
+		encoder.Reset(io.Discard)
+		brotliWriters.Put(encoder)
+	case *gzip.Writer:
+		encoder.Reset(io.Discard)
+		gzipWriters.Put(encoder)
+	}
+	w.encoder = nil
+}
@@ -1,16 +1,59 @@
 package middleware
 
 import (
+	"strings"
+
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
 )
 
-func CORSMiddleware() gin.HandlerFunc {
+// CORSMiddleware applies the CORS_* settings. With no allowed origins (or
+// "*") any origin is accepted, but credentials are then never allowed since
+// browsers refuse them for wildcard origins.
+func CORSMiddleware(cfg *config.Config) gin.HandlerFunc {
 	config := cors.DefaultConfig()
-	config.AllowOrigins = []string{"*"} // Configure as needed for production
 	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization"}
-	config.AllowCredentials = true
+	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-CSRF-Token", "X-Auth-Mode", "X-Device-ID"}
+	if cfg.TenancyMode == "multi" && cfg.TenantHeader != "" {
+		config.AllowHeaders = append(config.AllowHeaders, cfg.TenantHeader)
+	}
+	config.MaxAge = cfg.CORSMaxAge
+
+	allowAll := len(cfg.CORSAllowedOrigins) == 0
+	for _, origin := range cfg.CORSAllowedOrigins {
+		if strings.TrimSpace(origin) == "*" {
+			allowAll = true
+		}
+	}
+
+	if allowAll {
+		config.AllowAllOrigins = true
+		config.AllowCredentials = false
+	} else {
+		config.AllowOrigins = cfg.CORSAllowedOrigins
+		config.AllowWildcard = true // e.g. https://*.sipfinity.com
+		config.AllowCredentials = cfg.CORSAllowCredentials
+	}
 
 	return cors.New(config)
+}
+
+// OriginAllowed reports whether origin matches one of the allowed origins,
+// accepting the same "*" and https://*.example.com wildcards as the CORS
+// middleware
+func OriginAllowed(allowed []string, origin string) bool {
+	for _, pattern := range allowed {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "*" || strings.EqualFold(pattern, origin) {
+			return true
+		}
+		if prefix, suffix, ok := strings.Cut(pattern, "*"); ok &&
+			len(origin) > len(prefix)+len(suffix) &&
+			strings.HasPrefix(strings.ToLower(origin), strings.ToLower(prefix)) &&
+			strings.HasSuffix(strings.ToLower(origin), strings.ToLower(suffix)) {
+			return true
+		}
+	}
+	return false
 }
\ No newline at end of file
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+)
+
+// DebugCapture records sanitized request/response pairs on routes selected
+// through the admin debug capture rules. Register it globally after
+// compression so response bodies are captured before they are encoded.
+// Requests on other routes only pay for a rule lookup.
+func DebugCapture(captures *services.DebugCaptureService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rules := captures.RulesFor(c.Request.Method, c.FullPath())
+		if len(rules) == 0 {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		limit := captures.MaxBody()
+		var requestBody []byte
+		truncated := false
+		if c.Request.Body != nil {
+			// Read at most limit+1 bytes and hand the rest to the handler untouched
+			requestBody, _ = io.ReadAll(io.LimitReader(c.Request.Body, int64(limit)+1))
+			c.Request.Body = readCloser{io.MultiReader(bytes.NewReader(requestBody), c.Request.Body), c.Request.Body}
+			if len(requestBody) > limit {
+				requestBody, truncated = requestBody[:limit], true
+			}
+		}
+
+		writer := &captureWriter{ResponseWriter: c.Writer, limit: limit}
+		c.Writer = writer
+
+		c.Next()
+
+		userID := c.GetUint("user_id")
+		rule := captures.Select(rules, userID)
+		if rule == nil {
+			return
+		}
+
+		capture := &models.DebugCapture{
+			RuleID:          rule.ID,
+			Method:          c.Request.Method,
+			Route:           c.FullPath(),
+			Path:            c.Request.URL.Path,
+			Status:          writer.Status(),
+			DurationMs:      time.Since(start).Milliseconds(),
+			RequestID:       c.GetString("request_id"),
+			ClientIP:        c.ClientIP(),
+			RequestHeaders:  captures.SanitizeHeaders(c.Request.Header),
+			RequestBody:     captures.SanitizeBody(requestBody, c.ContentType()),
+			ResponseHeaders: captures.SanitizeHeaders(writer.Header()),
+			ResponseBody:    captures.SanitizeBody(writer.body.Bytes(), writer.Header().Get("Content-Type")),
+			Truncated:       truncated || writer.truncated,
+		}
+		if userID != 0 {
+			capture.UserID = &userID
+		}
+		captures.Record(capture)
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// captureWriter copies up to limit bytes of the response body
+type captureWriter struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (w *captureWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *captureWriter) capture(data []byte) {
+	room := w.limit - w.body.Len()
+	if len(data) > room {
+		data, w.truncated = data[:max(room, 0)], true
+	}
+	w.body.Write(data)
+}
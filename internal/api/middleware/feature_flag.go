@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+// RequireFeature hides a route behind a feature flag, evaluated per request
+// for the signed-in user (or client IP). Place it after the auth middleware
+// so percentage rollouts bucket by user.
+func RequireFeature(flags *services.FeatureFlagService, key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !flags.IsEnabled(key, FlagSubject(c)) {
+			utils.SendError(c, http.StatusNotFound, "This feature is not available", nil)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// FlagSubject identifies the caller for feature flag evaluation
+func FlagSubject(c *gin.Context) services.FlagSubject {
+	return services.FlagSubject{
+		UserID:   c.GetUint("user_id"),
+		ClientID: c.ClientIP(),
+	}
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+	"github.com/princeprakhar/ecommerce-backend/pkg/geo"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+)
+
+// GeoIPMiddleware locates the client IP, preferring GEOIP_COUNTRY_HEADER
+// when the request comes straight from one of TRUSTED_PROXIES, and stores the result as "geo_country" and
+// "geo_region" and in the request context for services and audit logs.
+// Requests from GEOIP_BLOCKED_COUNTRIES are refused with 451, except health
+// checks. A failed lookup leaves the request unlocated.
+func GeoIPMiddleware(cfg *config.Config, locator services.GeoLocator) gin.HandlerFunc {
+	blocked := make(map[string]bool, len(cfg.GeoIPBlockedCountries))
+	for _, country := range cfg.GeoIPBlockedCountries {
+		blocked[strings.ToUpper(country)] = true
+	}
+
+	trusted := parseTrustedProxies(cfg.TrustedProxies)
+
+	return func(c *gin.Context) {
+		var loc *geo.Location
+		// Anyone can send the header, so it only counts when set by a proxy
+		if cfg.GeoIPCountryHeader != "" && isTrustedProxy(trusted, c.RemoteIP()) {
+			// Cloudflare uses XX for unknown addresses
+			if country := strings.ToUpper(strings.TrimSpace(c.GetHeader(cfg.GeoIPCountryHeader))); len(country) == 2 && country != "XX" {
+				loc = &geo.Location{Country: country}
+			}
+		}
+		if loc == nil {
+			found, err := locator.Lookup(c.Request.Context(), c.ClientIP())
+			if err != nil {
+				logger.Warn("GeoIP lookup failed: ", err)
+			}
+			loc = found
+		}
+		if loc == nil {
+			c.Next()
+			return
+		}
+
+		path, _ := apiRelativePath(c.Request.URL.Path)
+		if blocked[loc.Country] && path != "/health" && path != "/auth/health" {
+			c.AbortWithStatusJSON(http.StatusUnavailableForLegalReasons, utils.APIResponse{
+				Success: false,
+				Message: "This service is not available in your country",
+				Error:   "country_blocked",
+			})
+			return
+		}
+
+		c.Set("geo_country", loc.Country)
+		c.Set("geo_region", loc.Region)
+		c.Request = c.Request.WithContext(geo.ContextWithLocation(c.Request.Context(), loc))
+		c.Next()
+	}
+}
+
+// parseTrustedProxies reads TRUSTED_PROXIES entries, each an IP address or
+// CIDR, as networks. Config validation has already rejected bad entries.
+func parseTrustedProxies(proxies []string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			nets = append(nets, network)
+		}
+	}
+	return nets
+}
+
+func isTrustedProxy(trusted []*net.IPNet, remoteIP string) bool {
+	ip := net.ParseIP(remoteIP)
+	if ip == nil {
+		return false
+	}
+	for _, network := range trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
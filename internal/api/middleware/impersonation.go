@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+// ImpersonationAudit writes every request made with an impersonation token
+// to the audit log once it has been handled. Register it globally: the auth
+// middleware further down the chain is what marks the request.
+func ImpersonationAudit(audit *services.AuditService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		impersonatorID := c.GetUint("impersonator_id")
+		if impersonatorID == 0 {
+			return
+		}
+		userID := c.GetUint("user_id")
+		audit.Record(&models.AuditLog{
+			ActorID:       impersonatorID,
+			SubjectUserID: &userID,
+			Action:        models.AuditActionImpersonatedRequest,
+			Method:        c.Request.Method,
+			Path:          c.Request.URL.RequestURI(),
+			StatusCode:    c.Writer.Status(),
+			IPAddress:     c.ClientIP(),
+			UserAgent:     c.Request.UserAgent(),
+			Country:       c.GetString("geo_country"),
+			Region:        c.GetString("geo_region"),
+		})
+	}
+}
+
+// DenyImpersonation blocks account-level actions (password changes, logout)
+// that support staff must not take on a customer's behalf.
+func DenyImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetUint("impersonator_id") != 0 {
+			utils.SendForbidden(c, "Not allowed while impersonating a user")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// setImpersonator marks a request made with an impersonation token, both for
+// the audit log and, via a response header, for the client
+func setImpersonator(c *gin.Context, claims *utils.Claims) {
+	if claims.ImpersonatorID == 0 {
+		return
+	}
+	c.Set("impersonator_id", claims.ImpersonatorID)
+	c.Header("X-Impersonated-By", strconv.FormatUint(uint64(claims.ImpersonatorID), 10))
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+// maintenanceExemptPaths stay reachable so admins can sign in and clients can
+// detect the maintenance window. Paths are relative to the API version prefix;
+// everything under /admin is exempt too.
+var maintenanceExemptPaths = map[string]bool{
+	"/auth/health":        true,
+	"/auth/login":         true,
+	"/auth/logout":        true,
+	"/auth/refresh-token": true,
+	"/meta":               true,
+	"/features":           true,
+}
+
+// MaintenanceMiddleware answers public requests with 503 while maintenance is
+// on, either forced through MAINTENANCE_MODE or toggled at runtime with the
+// maintenance_mode feature flag. Admin routes, health checks and requests
+// carrying an admin token pass through so the catalog can still be worked on.
+func MaintenanceMiddleware(cfg *config.Config, flags *services.FeatureFlagService) gin.HandlerFunc {
+	retrySeconds := int(cfg.MaintenanceRetryAfter.Seconds())
+
+	return func(c *gin.Context) {
+		if !cfg.MaintenanceMode && !flags.IsEnabled(models.FlagMaintenanceMode, services.FlagSubject{}) {
+			c.Next()
+			return
+		}
+
+		path, isAPI := apiRelativePath(c.Request.URL.Path)
+		if c.Request.Method == http.MethodOptions || path == "/health" ||
+			(isAPI && (maintenanceExemptPaths[strings.TrimSuffix(path, "/")] || strings.HasPrefix(path, "/admin/"))) ||
+			isAdminRequest(c, cfg) {
+			c.Next()
+			return
+		}
+
+		c.Header("Retry-After", strconv.Itoa(retrySeconds))
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, utils.APIResponse{
+			Success: false,
+			Message: cfg.MaintenanceMessage,
+			Data: gin.H{
+				"maintenance":         true,
+				"retry_after_seconds": retrySeconds,
+			},
+		})
+	}
+}
+
+// apiRelativePath strips the /api/vN prefix, e.g. /api/v2/auth/login -> /auth/login
+func apiRelativePath(path string) (string, bool) {
+	rest, ok := strings.CutPrefix(path, "/api/v")
+	if !ok {
+		return path, false
+	}
+	if idx := strings.Index(rest, "/"); idx >= 0 {
+		return rest[idx:], true
+	}
+	return "/", true
+}
+
+// isAdminRequest checks the bearer token or access cookie without requiring one
+func isAdminRequest(c *gin.Context, cfg *config.Config) bool {
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if token == "" {
+		token, _ = c.Cookie(utils.AccessTokenCookie)
+	}
+	if token == "" {
+		return false
+	}
+
+	claims, err := utils.ValidateToken(token, cfg.JWTSecret)
+	return err == nil && claims.Role == "admin" && claims.Type == string(utils.AccessToken)
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+// MetricsAuth guards the Prometheus scrape endpoint with a static bearer
+// token. An empty token leaves the endpoint open, e.g. when it is only
+// reachable from the private network.
+func MetricsAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		provided := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			utils.SendUnauthorized(c, "Invalid metrics token")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
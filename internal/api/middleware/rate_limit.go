@@ -7,6 +7,7 @@ import (
 	"github.com/ulule/limiter/v3/drivers/store/memory"
 	"github.com/princeprakhar/ecommerce-backend/internal/config"
 	"fmt"
+	"time"
 )
 
 func RateLimitMiddleware(cfg *config.Config) gin.HandlerFunc {
@@ -21,4 +22,14 @@ func RateLimitMiddleware(cfg *config.Config) gin.HandlerFunc {
 	return mgin.NewMiddleware(instance, mgin.WithKeyGetter(func(c *gin.Context) string {
 		return fmt.Sprintf("%s:%s", c.ClientIP(), c.Request.URL.Path)
 	}))
-}
\ No newline at end of file
+}
+
+// RouteRateLimit applies a tighter per-client limit to a single expensive or
+// chatty endpoint (e.g. typeahead), on top of the global limit.
+func RouteRateLimit(limit int64, period time.Duration) gin.HandlerFunc {
+	instance := limiter.New(memory.NewStore(), limiter.Rate{Period: period, Limit: limit}, limiter.WithTrustForwardHeader(true))
+
+	return mgin.NewMiddleware(instance, mgin.WithKeyGetter(func(c *gin.Context) string {
+		return fmt.Sprintf("%s:%s", c.ClientIP(), c.FullPath())
+	}))
+}
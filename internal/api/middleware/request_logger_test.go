@@ -0,0 +1,22 @@
+package middleware
+
+import "testing"
+
+func TestRedactQuery(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/api/v1/products", "/api/v1/products"},
+		{"/api/v1/products?page=2", "/api/v1/products?page=2"},
+		{"/api/v1/admin/events?ticket=abc.def&types=stock.low", "/api/v1/admin/events?ticket=redacted&types=stock.low"},
+		{"/api/v1/admin/events?access_token=abc", "/api/v1/admin/events?access_token=redacted"},
+		{"/api/v1/reset?token=abc%zz", "/api/v1/reset?[redacted]"},
+	}
+
+	for _, tt := range tests {
+		if got := redactQuery(tt.path); got != tt.want {
+			t.Errorf("redactQuery(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+)
+
+// SecurityHeadersMiddleware sets browser hardening headers on every response.
+// The API itself serves no HTML, so the default CSP denies everything; the
+// docs UI under DocsPathPrefix gets its own, looser policy.
+func SecurityHeadersMiddleware(cfg *config.Config) gin.HandlerFunc {
+	hsts := ""
+	if cfg.HSTSMaxAge > 0 {
+		hsts = fmt.Sprintf("max-age=%d; includeSubDomains", cfg.HSTSMaxAge)
+	}
+
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("X-Frame-Options", "DENY")
+		header.Set("Referrer-Policy", "strict-origin-when-cross-origin")
+
+		// HSTS is only meaningful over HTTPS; behind a proxy trust X-Forwarded-Proto
+		if hsts != "" && (c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https")) {
+			header.Set("Strict-Transport-Security", hsts)
+		}
+
+		if cfg.DocsPathPrefix != "" && strings.HasPrefix(c.Request.URL.Path, cfg.DocsPathPrefix) {
+			header.Set("Content-Security-Policy", cfg.DocsContentSecurityPolicy)
+		} else if cfg.ContentSecurityPolicy != "" {
+			header.Set("Content-Security-Policy", cfg.ContentSecurityPolicy)
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+// TenantMiddleware resolves the store of each request in multi-tenant mode,
+// from the tenant header or else the Host, and sets "store_id". Routes with a
+// :product_id parameter only reach products of that store. In single-tenant
+// mode it does nothing and "store_id" stays unset.
+func TenantMiddleware(cfg *config.Config, stores *services.StoreService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cfg.TenancyMode != "multi" {
+			c.Next()
+			return
+		}
+
+		store, err := stores.Resolve(c.Request.Context(), c.GetHeader(cfg.TenantHeader), c.Request.Host)
+		if err != nil {
+			if errors.Is(err, services.ErrStoreNotFound) {
+				utils.SendError(c, http.StatusNotFound, "Unknown store", err)
+			} else {
+				utils.SendInternalError(c, "Failed to resolve store", err)
+			}
+			c.Abort()
+			return
+		}
+		c.Set("store_id", store.ID)
+
+		if param := c.Param("product_id"); param != "" {
+			productID, err := strconv.ParseUint(param, 10, 32)
+			if err != nil {
+				utils.SendValidationError(c, "Invalid product ID")
+				c.Abort()
+				return
+			}
+			owned, err := stores.OwnsProduct(c.Request.Context(), store.ID, uint(productID))
+			if err != nil {
+				utils.SendInternalError(c, "Failed to check product store", err)
+				c.Abort()
+				return
+			}
+			if !owned {
+				utils.SendError(c, http.StatusNotFound, "Product not found", services.ErrProductNotFound)
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
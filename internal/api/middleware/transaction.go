@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/database"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// Transaction runs a request inside one database transaction, for handlers
+// that make several writes through different services and need them to
+// succeed or fail together. Services that build their statements with
+// database.Conn join it through the request context, and their own
+// transaction blocks nest in it as savepoints. The transaction is committed
+// when the handler answers with a 2xx and records no errors, and rolled back
+// otherwise. The response is held back until the commit, so a failed commit
+// turns into a 500 instead of a success the database never saw.
+//
+// Add it to individual routes, not globally: a statement that fails inside
+// the transaction aborts the rest of it, and streamed responses are buffered.
+func Transaction(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		tx := db.WithContext(ctx).Begin()
+		if tx.Error != nil {
+			logger.Error("Failed to begin request transaction: ", tx.Error)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, utils.APIResponse{
+				Success: false,
+				Message: "Internal server error",
+			})
+			return
+		}
+
+		writer := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Request = c.Request.WithContext(database.ContextWithTx(ctx, tx))
+
+		committed := false
+		defer func() {
+			// Also runs on panics, so Recovery further up the chain answers
+			// on the real writer
+			c.Writer = writer.ResponseWriter
+			if !committed {
+				tx.Rollback()
+			}
+		}()
+
+		c.Next()
+
+		c.Writer = writer.ResponseWriter
+		status := writer.Status()
+		if status < 200 || status >= 300 || len(c.Errors) > 0 {
+			writer.flush()
+			return
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			logger.Error("Failed to commit request transaction: ", err)
+			c.JSON(http.StatusInternalServerError, utils.APIResponse{
+				Success: false,
+				Message: "Internal server error",
+			})
+			return
+		}
+		committed = true
+		writer.flush()
+	}
+}
+
+// bufferedWriter holds the response body back until flush
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body    bytes.Buffer
+	written bool
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	w.written = true
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+// WriteHeaderNow keeps the status pending; flush sends it
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+func (w *bufferedWriter) Written() bool {
+	return w.written
+}
+
+func (w *bufferedWriter) Flush() {}
+
+func (w *bufferedWriter) flush() {
+	w.ResponseWriter.WriteHeaderNow()
+	if w.body.Len() > 0 {
+		w.ResponseWriter.Write(w.body.Bytes())
+	}
+}
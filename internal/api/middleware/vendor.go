@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+// AdminOrVendor lets admins and vendor users through. Pair it with
+// VendorScope so vendors only see their own products.
+func AdminOrVendor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("user_role")
+		if role != "admin" && role != models.RoleVendor {
+			utils.SendForbidden(c, "Admin or vendor access required")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// VendorScope sets "vendor_id" for vendor users and hides products of other
+// vendors on routes with a :product_id parameter. Admins pass through
+// unscoped, with no vendor_id set.
+func VendorScope(vendors *services.VendorService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("user_role") != models.RoleVendor {
+			c.Next()
+			return
+		}
+
+		vendorID, err := vendors.VendorIDForUser(c.Request.Context(), c.GetUint("user_id"))
+		if err != nil {
+			if errors.Is(err, services.ErrVendorAccess) {
+				utils.SendForbidden(c, "No active vendor for this account")
+			} else {
+				utils.SendInternalError(c, "Failed to resolve vendor", err)
+			}
+			c.Abort()
+			return
+		}
+		c.Set("vendor_id", vendorID)
+
+		if param := c.Param("product_id"); param != "" {
+			productID, err := strconv.ParseUint(param, 10, 32)
+			if err != nil {
+				utils.SendValidationError(c, "Invalid product ID")
+				c.Abort()
+				return
+			}
+			owned, err := vendors.OwnsProduct(c.Request.Context(), vendorID, uint(productID))
+			if err != nil {
+				utils.SendInternalError(c, "Failed to check product vendor", err)
+				c.Abort()
+				return
+			}
+			// Other vendors' products look missing rather than forbidden
+			if !owned {
+				utils.SendError(c, http.StatusNotFound, "Product not found", services.ErrProductNotFound)
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+)
+
+// DeprecationMiddleware marks every response of an API version as deprecated
+// (RFC 9745 Deprecation, RFC 8594 Sunset) and links the same path on the
+// successor version. A zero deprecatedAt disables the headers.
+func DeprecationMiddleware(deprecatedAt, sunset time.Time, fromPrefix, toPrefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !deprecatedAt.IsZero() {
+			header := c.Writer.Header()
+			header.Set("Deprecation", fmt.Sprintf("@%d", deprecatedAt.Unix()))
+			if !sunset.IsZero() {
+				header.Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+			}
+			successor := toPrefix + strings.TrimPrefix(c.Request.URL.Path, fromPrefix)
+			header.Add("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+		}
+		c.Next()
+	}
+}
+
+// V2EnvelopeMiddleware lets v1 handlers serve /api/v2 unchanged: JSON bodies
+// written as utils.APIResponse are rewritten into utils.V2Response, with list
+// pagination lifted into meta. Non-JSON responses (files, images, streams,
+// WebSocket upgrades) pass through untouched.
+func V2EnvelopeMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+			c.Next()
+			return
+		}
+
+		writer := &envelopeWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		if writer.passthrough {
+			return
+		}
+		if !writer.buffered {
+			// Nothing written (e.g. 204); let gin flush the recorded status
+			c.Writer.WriteHeader(writer.status)
+			return
+		}
+		body := writer.body.Bytes()
+
+		var v1 utils.APIResponse
+		var raw struct {
+			Success *bool `json:"success"`
+		}
+		if json.Unmarshal(body, &raw) != nil || raw.Success == nil || json.Unmarshal(body, &v1) != nil {
+			// Not a v1 envelope; send as written
+			c.Writer.WriteHeader(writer.status)
+			c.Writer.Write(body)
+			return
+		}
+
+		c.JSON(writer.status, toV2Response(writer.status, &v1))
+	}
+}
+
+func toV2Response(status int, v1 *utils.APIResponse) utils.V2Response {
+	if !v1.Success || status >= http.StatusBadRequest {
+		response := utils.V2Response{Error: &utils.V2Error{
+			Code:    utils.ErrorCodeForStatus(status),
+			Message: v1.Message,
+			Detail:  v1.Error,
+		}}
+		if details, ok := v1.Data.(map[string]interface{}); ok {
+			response.Meta = details
+		}
+		return response
+	}
+
+	response := utils.V2Response{Data: v1.Data, Meta: map[string]interface{}{}}
+	if v1.Message != "" {
+		response.Meta["message"] = v1.Message
+	}
+
+	// Lists come back as {"<items>": [...], "pagination": {...}}; unwrap the items
+	if fields, ok := v1.Data.(map[string]interface{}); ok && len(fields) == 2 {
+		if pagination, ok := fields["pagination"]; ok {
+			for key, value := range fields {
+				if key != "pagination" {
+					response.Data = value
+				}
+			}
+			response.Meta["pagination"] = pagination
+		}
+	}
+	if len(response.Meta) == 0 {
+		response.Meta = nil
+	}
+	return response
+}
+
+// envelopeWriter buffers JSON bodies so they can be re-enveloped after the
+// handler returns. The decision is made on the first write, once the handler
+// has set its Content-Type.
+type envelopeWriter struct {
+	gin.ResponseWriter
+	body        bytes.Buffer
+	status      int
+	buffered    bool
+	passthrough bool
+}
+
+func (w *envelopeWriter) WriteHeader(code int) {
+	w.status = code
+	if w.passthrough {
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *envelopeWriter) WriteHeaderNow() {
+	if w.decide() {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *envelopeWriter) Write(data []byte) (int, error) {
+	if w.decide() {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.body.Write(data)
+}
+
+func (w *envelopeWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *envelopeWriter) Flush() {
+	if w.decide() {
+		w.ResponseWriter.Flush()
+	}
+}
+
+func (w *envelopeWriter) Status() int {
+	if w.passthrough {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+func (w *envelopeWriter) Written() bool {
+	return w.buffered || (w.passthrough && w.ResponseWriter.Written())
+}
+
+// decide reports whether output goes straight to the client
+func (w *envelopeWriter) decide() bool {
+	if w.passthrough || w.buffered {
+		return w.passthrough
+	}
+	if strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		w.buffered = true
+		return false
+	}
+	w.passthrough = true
+	w.ResponseWriter.WriteHeader(w.status)
+	return true
+}
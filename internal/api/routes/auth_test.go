@@ -0,0 +1,127 @@
+package routes_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/testutil"
+)
+
+func TestAuth(t *testing.T) {
+	env := testutil.NewEnv(t)
+
+	signup := services.SignupRequest{
+		Email:       "new.customer@example.com",
+		Password:    testutil.FixturePassword,
+		FirstName:   "New",
+		LastName:    "Customer",
+		PhoneNumber: "+15550000001",
+	}
+
+	var auth services.AuthResponse
+	t.Run("signup", func(t *testing.T) {
+		env.Do(t, http.MethodPost, "/api/v1/auth/signup", signup, "").
+			ExpectStatus(t, http.StatusOK).Decode(t, &auth)
+
+		if auth.Token.AccessToken == "" || auth.Token.RefreshToken == "" {
+			t.Fatalf("signup returned no tokens: %+v", auth.Token)
+		}
+		if auth.User.Email != signup.Email || auth.User.Role != "customer" {
+			t.Fatalf("unexpected user %+v", auth.User)
+		}
+	})
+
+	t.Run("signup with a taken email", func(t *testing.T) {
+		env.Do(t, http.MethodPost, "/api/v1/auth/signup", signup, "").ExpectStatus(t, http.StatusConflict)
+	})
+
+	t.Run("signup without a phone number", func(t *testing.T) {
+		body := signup
+		body.Email, body.PhoneNumber = "no.phone@example.com", ""
+		env.Do(t, http.MethodPost, "/api/v1/auth/signup", body, "").ExpectStatus(t, http.StatusBadRequest)
+	})
+
+	t.Run("login with a wrong password", func(t *testing.T) {
+		env.Do(t, http.MethodPost, "/api/v1/auth/login", services.LoginRequest{
+			Email:    signup.Email,
+			Password: "not-the-password",
+		}, "").ExpectStatus(t, http.StatusUnauthorized)
+	})
+
+	t.Run("login", func(t *testing.T) {
+		env.Do(t, http.MethodPost, "/api/v1/auth/login", services.LoginRequest{
+			Email:    signup.Email,
+			Password: signup.Password,
+		}, "").ExpectStatus(t, http.StatusOK).Decode(t, &auth)
+
+		if auth.Token.AccessToken == "" || auth.Token.RefreshToken == "" {
+			t.Fatalf("login returned no tokens: %+v", auth.Token)
+		}
+	})
+
+	t.Run("profile", func(t *testing.T) {
+		var user models.User
+		env.Do(t, http.MethodGet, "/api/v1/auth/profile", nil, auth.Token.AccessToken).
+			ExpectStatus(t, http.StatusOK).Decode(t, &user)
+
+		if user.Email != signup.Email || user.FirstName != signup.FirstName {
+			t.Fatalf("unexpected profile %+v", user)
+		}
+	})
+
+	t.Run("profile without a token", func(t *testing.T) {
+		env.Do(t, http.MethodGet, "/api/v1/auth/profile", nil, "").ExpectStatus(t, http.StatusUnauthorized)
+	})
+
+	t.Run("refresh", func(t *testing.T) {
+		var refreshed services.AuthResponse
+		env.Do(t, http.MethodPost, "/api/v1/auth/refresh-token", services.RefreshRequest{
+			RefreshToken: auth.Token.RefreshToken,
+		}, "").ExpectStatus(t, http.StatusOK).Decode(t, &refreshed)
+
+		if refreshed.Token.AccessToken == "" || refreshed.Token.RefreshToken == "" {
+			t.Fatalf("refresh returned no tokens: %+v", refreshed.Token)
+		}
+		auth = refreshed
+	})
+
+	t.Run("logout revokes the refresh token", func(t *testing.T) {
+		body := services.RefreshRequest{RefreshToken: auth.Token.RefreshToken}
+		env.Do(t, http.MethodPost, "/api/v1/auth/logout", body, auth.Token.AccessToken).ExpectStatus(t, http.StatusOK)
+		env.Do(t, http.MethodPost, "/api/v1/auth/refresh-token", body, "").ExpectStatus(t, http.StatusUnauthorized)
+	})
+}
+
+func TestRefreshWithExpiredPassword(t *testing.T) {
+	env := testutil.NewEnv(t, func(cfg *config.Config) {
+		cfg.PasswordMaxAge = 24 * time.Hour
+	})
+	admin := env.CreateAdmin(t)
+
+	var auth services.AuthResponse
+	env.Do(t, http.MethodPost, "/api/v1/auth/login", services.LoginRequest{
+		Email:    admin.Email,
+		Password: testutil.FixturePassword,
+		IsAdmin:  true,
+	}, "").ExpectStatus(t, http.StatusOK).Decode(t, &auth)
+
+	// The password ages past the limit while the session is open
+	if err := env.DB.Model(admin).Update("password_changed_at", time.Now().Add(-48*time.Hour)).Error; err != nil {
+		t.Fatalf("failed to age password: %v", err)
+	}
+
+	body := services.RefreshRequest{RefreshToken: auth.Token.RefreshToken}
+	var expired services.PasswordExpiredError
+	env.Do(t, http.MethodPost, "/api/v1/auth/refresh-token", body, "").
+		ExpectStatus(t, http.StatusForbidden).Decode(t, &expired)
+	if expired.Token == "" {
+		t.Fatal("expected a password change token")
+	}
+
+	// The token family is revoked, so the session cannot be resumed
+	env.Do(t, http.MethodPost, "/api/v1/auth/refresh-token", body, "").ExpectStatus(t, http.StatusUnauthorized)
+}
@@ -0,0 +1,102 @@
+package routes_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/testutil"
+)
+
+func TestCartMergeOnLogin(t *testing.T) {
+	env := testutil.NewEnv(t)
+	user := env.CreateUser(t)
+	token := env.Login(t, user)
+	mug := env.CreateProduct(t, 8)
+	plate := env.CreateProduct(t, 5)
+	if err := env.DB.Model(plate).Update("max_order_qty", 4).Error; err != nil {
+		t.Fatalf("failed to limit product: %v", err)
+	}
+
+	// The user's own cart already holds some of both products
+	for _, item := range []models.CartItemRequest{{ProductID: mug.ID, Quantity: 1}, {ProductID: plate.ID, Quantity: 3}} {
+		env.Do(t, http.MethodPost, "/api/v1/cart/items", item, token).ExpectStatus(t, http.StatusOK)
+	}
+
+	var anonymous models.CartView
+	env.Do(t, http.MethodPost, "/api/v1/cart/items", models.CartItemRequest{ProductID: mug.ID, Quantity: 2}, "").
+		ExpectStatus(t, http.StatusOK).Decode(t, &anonymous)
+	if anonymous.Token == "" {
+		t.Fatal("expected a cart token for the anonymous cart")
+	}
+	cartHeader := map[string]string{"X-Cart-Token": anonymous.Token}
+	env.Do(t, http.MethodPost, "/api/v1/cart/items", models.CartItemRequest{ProductID: plate.ID, Quantity: 2}, "", cartHeader).
+		ExpectStatus(t, http.StatusOK)
+
+	env.Do(t, http.MethodPost, "/api/v1/auth/login", services.LoginRequest{
+		Email:    user.Email,
+		Password: testutil.FixturePassword,
+	}, "", cartHeader).ExpectStatus(t, http.StatusOK)
+
+	var cart models.CartView
+	env.Do(t, http.MethodGet, "/api/v1/cart", nil, token).ExpectStatus(t, http.StatusOK).Decode(t, &cart)
+	quantities := map[uint]int{}
+	for _, line := range cart.Items {
+		quantities[line.ProductID] = line.Quantity
+	}
+	// Quantities are summed, and capped at the product's order maximum
+	if quantities[mug.ID] != 3 || quantities[plate.ID] != 4 {
+		t.Fatalf("unexpected merged quantities %v", quantities)
+	}
+
+	// The anonymous cart is gone once merged
+	var empty models.CartView
+	env.Do(t, http.MethodGet, "/api/v1/cart", nil, "", cartHeader).ExpectStatus(t, http.StatusOK).Decode(t, &empty)
+	if len(empty.Items) != 0 {
+		t.Fatalf("expected the anonymous cart to be merged away, got %+v", empty.Items)
+	}
+
+	t.Run("quantity limits", func(t *testing.T) {
+		path := fmt.Sprintf("/api/v1/cart/items/%d", plate.ID)
+		env.Do(t, http.MethodPut, path, models.UpdateCartItemRequest{Quantity: 5}, token).ExpectStatus(t, http.StatusUnprocessableEntity)
+		env.Do(t, http.MethodDelete, path, nil, token).ExpectStatus(t, http.StatusOK)
+		env.Do(t, http.MethodDelete, path, nil, token).ExpectStatus(t, http.StatusNotFound)
+	})
+}
+
+func TestCartSharing(t *testing.T) {
+	env := testutil.NewEnv(t)
+	sharer := env.Login(t, env.CreateUser(t))
+	recipient := env.Login(t, env.CreateUser(t))
+	lamp := env.CreateProduct(t, 30)
+
+	env.Do(t, http.MethodPost, "/api/v1/cart/share", nil, sharer).ExpectStatus(t, http.StatusUnprocessableEntity)
+	env.Do(t, http.MethodPost, "/api/v1/cart/items", models.CartItemRequest{ProductID: lamp.ID, Quantity: 2}, sharer).
+		ExpectStatus(t, http.StatusOK)
+
+	var link models.CartShareLink
+	env.Do(t, http.MethodPost, "/api/v1/cart/share", nil, sharer).ExpectStatus(t, http.StatusCreated).Decode(t, &link)
+	if link.Token == "" {
+		t.Fatal("expected a share token")
+	}
+
+	// Later changes to the sharer's cart don't reach the share
+	env.Do(t, http.MethodDelete, "/api/v1/cart", nil, sharer).ExpectStatus(t, http.StatusOK)
+
+	var shared models.CartView
+	env.Do(t, http.MethodGet, "/api/v1/cart/shared/"+link.Token, nil, "").ExpectStatus(t, http.StatusOK).Decode(t, &shared)
+	if len(shared.Items) != 1 || shared.Items[0].Quantity != 2 || shared.Subtotal != 60 {
+		t.Fatalf("unexpected shared cart %+v", shared)
+	}
+
+	var cart models.CartView
+	env.Do(t, http.MethodPost, "/api/v1/cart/shared/"+link.Token+"/import", nil, recipient).
+		ExpectStatus(t, http.StatusOK).Decode(t, &cart)
+	if len(cart.Items) != 1 || cart.Items[0].ProductID != lamp.ID || cart.Items[0].Quantity != 2 {
+		t.Fatalf("unexpected imported cart %+v", cart)
+	}
+
+	env.Do(t, http.MethodGet, "/api/v1/cart/shared/not-a-token", nil, "").ExpectStatus(t, http.StatusNotFound)
+}
@@ -0,0 +1,89 @@
+package routes_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/testutil"
+)
+
+func TestProductCRUD(t *testing.T) {
+	env := testutil.NewEnv(t)
+	adminToken := env.Login(t, env.CreateAdmin(t))
+	customerToken := env.Login(t, env.CreateUser(t))
+
+	body := map[string]interface{}{
+		"title":    "Stoneware bowl",
+		"sku":      "ROUTES-CRUD-1",
+		"price":    12.50,
+		"stock":    8,
+		"category": "kitchen",
+		"status":   "active",
+	}
+
+	t.Run("customers cannot create products", func(t *testing.T) {
+		env.Do(t, http.MethodPost, "/api/v1/admin/products", body, customerToken).ExpectStatus(t, http.StatusForbidden)
+	})
+
+	var product models.Product
+	created := t.Run("create", func(t *testing.T) {
+		env.Do(t, http.MethodPost, "/api/v1/admin/products", body, adminToken).
+			ExpectStatus(t, http.StatusOK).Decode(t, &product)
+
+		if product.ID == 0 || product.Title != "Stoneware bowl" || product.Price != 12.50 || product.Stock != 8 {
+			t.Fatalf("unexpected product %+v", product)
+		}
+	})
+	if !created {
+		t.FailNow()
+	}
+	path := fmt.Sprintf("/api/v1/admin/products/%d", product.ID)
+	publicPath := fmt.Sprintf("/api/v1/products/%d", product.ID)
+
+	t.Run("read", func(t *testing.T) {
+		var public models.Product
+		env.Do(t, http.MethodGet, publicPath, nil, customerToken).ExpectStatus(t, http.StatusOK).Decode(t, &public)
+		if public.ID != product.ID || public.Title != product.Title || public.SKU != product.SKU {
+			t.Fatalf("customer got %+v, want %+v", public, product)
+		}
+
+		var admin models.Product
+		env.Do(t, http.MethodGet, path, nil, adminToken).ExpectStatus(t, http.StatusOK).Decode(t, &admin)
+		if admin.ID != product.ID || admin.Stock != product.Stock {
+			t.Fatalf("admin got %+v, want %+v", admin, product)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		var updated models.Product
+		env.Do(t, http.MethodPut, path, map[string]interface{}{
+			"title": "Stoneware serving bowl",
+			"price": "14.00",
+			"stock": 3,
+		}, adminToken).ExpectStatus(t, http.StatusOK).Decode(t, &updated)
+
+		if updated.Title != "Stoneware serving bowl" || updated.Price != 14 || updated.Stock != 3 {
+			t.Fatalf("fields were not updated: %+v", updated)
+		}
+		if updated.SKU != product.SKU || updated.Category != product.Category {
+			t.Fatalf("fields left out of the update changed: %+v", updated)
+		}
+	})
+
+	t.Run("update with an invalid price", func(t *testing.T) {
+		env.Do(t, http.MethodPut, path, map[string]interface{}{"price": "cheap"}, adminToken).
+			ExpectStatus(t, http.StatusBadRequest)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		env.Do(t, http.MethodDelete, path, nil, adminToken).ExpectStatus(t, http.StatusOK)
+		env.Do(t, http.MethodGet, path, nil, adminToken).ExpectStatus(t, http.StatusNotFound)
+		env.Do(t, http.MethodGet, publicPath, nil, customerToken).ExpectStatus(t, http.StatusNotFound)
+	})
+
+	t.Run("read a missing product", func(t *testing.T) {
+		env.Do(t, http.MethodGet, "/api/v1/products/999999", nil, customerToken).ExpectStatus(t, http.StatusNotFound)
+	})
+}
@@ -0,0 +1,97 @@
+package routes_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/testutil"
+	"github.com/princeprakhar/ecommerce-backend/internal/types"
+)
+
+type reviewPage struct {
+	Reviews    []services.ReviewResponse `json:"reviews"`
+	Pagination types.PageInfo            `json:"pagination"`
+}
+
+func TestReviews(t *testing.T) {
+	env := testutil.NewEnv(t)
+	author := env.CreateUser(t)
+	authorToken := env.Login(t, author)
+	otherToken := env.Login(t, env.CreateUser(t))
+	product := env.CreateProduct(t, 45)
+	listPath := fmt.Sprintf("/api/v1/reviews/product/%d", product.ID)
+
+	t.Run("invalid rating", func(t *testing.T) {
+		env.Do(t, http.MethodPost, "/api/v1/reviews/", services.CreateReviewRequest{
+			ProductID: product.ID,
+			Rating:    6,
+		}, authorToken).ExpectStatus(t, http.StatusUnprocessableEntity)
+	})
+
+	t.Run("missing product", func(t *testing.T) {
+		env.Do(t, http.MethodPost, "/api/v1/reviews/", services.CreateReviewRequest{
+			ProductID: 999999,
+			Rating:    4,
+		}, authorToken).ExpectStatus(t, http.StatusNotFound)
+	})
+
+	var review models.Review
+	created := t.Run("create", func(t *testing.T) {
+		env.Do(t, http.MethodPost, "/api/v1/reviews/", services.CreateReviewRequest{
+			ProductID: product.ID,
+			Rating:    4,
+			Comment:   "Sturdy and well made",
+		}, authorToken).ExpectStatus(t, http.StatusOK).Decode(t, &review)
+
+		if review.ID == 0 || review.UserID != author.ID || review.Rating != 4 {
+			t.Fatalf("unexpected review %+v", review)
+		}
+	})
+	if !created {
+		t.FailNow()
+	}
+	path := fmt.Sprintf("/api/v1/reviews/%d", review.ID)
+
+	t.Run("list", func(t *testing.T) {
+		var page reviewPage
+		env.Do(t, http.MethodGet, listPath, nil, otherToken).ExpectStatus(t, http.StatusOK).Decode(t, &page)
+
+		if page.Pagination.Total != 1 || len(page.Reviews) != 1 {
+			t.Fatalf("expected one review, got %+v", page)
+		}
+		if got := page.Reviews[0]; got.ID != review.ID || got.Comment != "Sturdy and well made" || got.UserName != author.FirstName+" "+author.LastName {
+			t.Fatalf("unexpected listed review %+v", got)
+		}
+	})
+
+	t.Run("others cannot edit", func(t *testing.T) {
+		env.Do(t, http.MethodPut, path, services.UpdateReviewRequest{Rating: 1}, otherToken).ExpectStatus(t, http.StatusForbidden)
+		env.Do(t, http.MethodDelete, path, nil, otherToken).ExpectStatus(t, http.StatusForbidden)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		var updated models.Review
+		env.Do(t, http.MethodPut, path, services.UpdateReviewRequest{
+			Rating:  5,
+			Comment: "Even better after a month",
+		}, authorToken).ExpectStatus(t, http.StatusOK).Decode(t, &updated)
+
+		if updated.ID != review.ID || updated.Rating != 5 || updated.Comment != "Even better after a month" || updated.EditedAt == nil {
+			t.Fatalf("review was not updated: %+v", updated)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		env.Do(t, http.MethodDelete, path, nil, authorToken).ExpectStatus(t, http.StatusOK)
+		env.Do(t, http.MethodDelete, path, nil, authorToken).ExpectStatus(t, http.StatusNotFound)
+
+		var page reviewPage
+		env.Do(t, http.MethodGet, listPath, nil, authorToken).ExpectStatus(t, http.StatusOK).Decode(t, &page)
+		if page.Pagination.Total != 0 || len(page.Reviews) != 0 {
+			t.Fatalf("expected no reviews, got %+v", page)
+		}
+	})
+}
@@ -1,119 +1,635 @@
 package routes
 
 import (
+	"context"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/princeprakhar/ecommerce-backend/internal/api/handlers"
 	"github.com/princeprakhar/ecommerce-backend/internal/api/middleware"
 	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
 	"github.com/princeprakhar/ecommerce-backend/internal/services"
 	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"github.com/princeprakhar/ecommerce-backend/pkg/metrics"
 	"gorm.io/gorm"
 )
 
 func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
+	// Client IPs feed rate limits, GeoIP and fraud screening, so only
+	// configured proxies may set them through X-Forwarded-For
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		logger.Fatal("Failed to set trusted proxies: ", err)
+	}
+
 	// Middleware
-	router.Use(gin.Logger())
+	router.Use(middleware.RequestIDMiddleware())
+	router.Use(middleware.RequestLogger())
 	router.Use(gin.Recovery())
-	router.Use(middleware.CORSMiddleware())
+	router.Use(middleware.CORSMiddleware(cfg))
+	router.Use(middleware.SecurityHeadersMiddleware(cfg))
 	router.Use(middleware.RateLimitMiddleware(cfg))
+	router.Use(middleware.CompressionMiddleware(cfg))
 
 
+	// Initialize services
+	eventBus := services.NewEventBus()
+	emailService := services.NewEmailService(cfg)
+	apiUsageService := services.NewAPIUsageService(db, cfg, emailService)
+
 	validationService := services.NewValidationService(
         cfg.AbstractEmailAPIKey,
         cfg.AbstractPhoneNumberAPIKey,
+        cfg.ValidationCacheTTL,
+        cfg.ValidationFallbackLocal,
+        apiUsageService,
     )
 
-
-
-	// Initialize services
-	emailService := services.NewEmailService(cfg)
-	authService := services.NewAuthService(db, cfg.JWTSecret, validationService, emailService, cfg.BaseURL)
-	reviewService := services.NewReviewService(db)
-	productService := services.NewProductService(db)
+	smsSender, err := services.NewSMSSender(cfg)
+	if err != nil {
+		logger.Fatal("Failed to initialize SMS provider: ", err)
+	}
+	otpService := services.NewOTPService(db, cfg, smsSender, apiUsageService)
+	fraudScreener, err := services.NewFraudScreener(cfg, db)
+	if err != nil {
+		logger.Fatal("Failed to initialize fraud screening: ", err)
+	}
+	fraudService := services.NewFraudService(db, fraudScreener, cfg.FraudReviewThreshold)
+	authService := services.NewAuthService(db, cfg.JWTSecret, validationService, emailService, otpService, fraudService, cfg.BaseURL, services.PasswordPolicy{HistorySize: cfg.PasswordHistorySize, MaxAge: cfg.PasswordMaxAge}, logger.New(map[string]interface{}{"service": "auth"}))
+	ratingService := services.NewRatingService(db, cfg.RatingPriorWeight, cfg.RatingMinReviews)
+	counterService := services.NewCounterService(db)
+	reviewService := services.NewReviewService(db, ratingService, counterService)
+	productService := services.NewProductService(db, cfg, eventBus)
 	
 	fastAPIService := services.NewFastAPIService(cfg)
-	adminService := services.NewAdminService(db,cfg, fastAPIService, emailService)
+	jobService := services.NewJobService(db, services.NewJobHub())
+	stockService := services.NewStockService(db)
+	bundleService := services.NewBundleService(db)
+	productImportService := services.NewProductImportService(db, jobService, cfg.ImportBatchSize)
+	feedImportService := services.NewFeedImportService(db, productImportService)
+	catalogSyncService := services.NewCatalogSyncService(db)
+	metaService := services.NewMetaService(cfg, productService)
+	adminService := services.NewAdminService(db,cfg, fastAPIService, emailService, jobService, logger.New(map[string]interface{}{"service": "admin"}))
+	announcementService := services.NewAnnouncementService(db)
+	snapshotService := services.NewSnapshotService(db)
+	webhookService := services.NewWebhookService(db, eventBus)
+	adminEventHub := services.NewAdminEventHub(eventBus)
+	productUpdateHub := services.NewProductUpdateHub(productService, eventBus)
+	suggestService := services.NewSuggestService(db, eventBus)
+	preferenceService := services.NewPreferenceService(db)
+	collectionService := services.NewCollectionService(db, productService)
+	savedSearchService := services.NewSavedSearchService(db, emailService, preferenceService, eventBus, cfg.BaseURL)
+	referralService := services.NewReferralService(db)
+	vendorService := services.NewVendorService(db)
+	storeService := services.NewStoreService(db)
+	auditService := services.NewAuditService(db)
+	invitationService := services.NewInvitationService(db, emailService, cfg.BaseURL, cfg.InvitationTTL)
+	impersonationService := services.NewImpersonationService(db, cfg.JWTSecret, cfg.ImpersonationTTL)
+	s3Service := services.NewS3Service(cfg.S3Region, cfg.S3Endpoint, cfg.S3BucketName, cfg.S3AccessKey, cfg.S3SecretKey, logger.New(map[string]interface{}{"service": "s3"}))
+	imageProxyService := services.NewImageProxyService(cfg, s3Service)
+	productExtractionService := services.NewProductExtractionService(db, cfg, fastAPIService, s3Service)
+	storageService := services.NewStorageService(db, cfg, s3Service, jobService)
+	avatarService := services.NewAvatarService(db, cfg, s3Service)
+	uploadService := services.NewUploadService(db, cfg, s3Service)
+	priceScheduleService := services.NewPriceScheduleService(db)
+	customerGroupService := services.NewCustomerGroupService(db)
+	quoteService := services.NewQuoteService(db, cfg, productService, emailService)
+	cartService := services.NewCartService(db, productService, stockService)
+	locationService := services.NewLocationService(db)
+	deliveryEstimator, err := services.NewDeliveryEstimator(cfg, db)
+	if err != nil {
+		logger.Fatal("Failed to initialize delivery estimator: ", err)
+	}
+	deliveryService := services.NewDeliveryService(db, deliveryEstimator, stockService, cfg.DeliveryDispatchDays)
+	reportService := services.NewReportService(db, cfg, s3Service, jobService)
+	bannerService := services.NewBannerService(db, s3Service)
+	productReportService := services.NewProductReportService(db, cfg.ProductReportThreshold)
+	adminNoteService := services.NewAdminNoteService(db)
+	models.DefaultAvatarURL = cfg.DefaultAvatarURL
+	piiKeyring, err := services.NewPIIKeyring(cfg)
+	if err != nil {
+		logger.Fatal("Failed to load PII encryption keys: ", err)
+	}
+	models.SetPIIKeyring(piiKeyring)
+	featureFlagService := services.NewFeatureFlagService(db)
+	debugCaptureService := services.NewDebugCaptureService(db, cfg)
+	chaosService := services.NewChaosService(db, cfg)
+	archiveService := services.NewArchiveService(db, cfg)
+	categoryService := services.NewCategoryService(db)
+
+	eventPublisher, err := services.NewEventPublisher(cfg)
+	if err != nil {
+		logger.Fatal("Failed to initialize event publisher: ", err)
+	}
+	outboxRelay := services.NewOutboxRelay(db, eventPublisher, eventBus, cfg.EventTopicPrefix)
+
+	// Background jobs
+	go snapshotService.Run(context.Background(), cfg.MetricsSnapshotInterval)
+	go webhookService.Run(context.Background())
+	go outboxRelay.Run(context.Background(), cfg.OutboxPollInterval)
+	go feedImportService.Run(context.Background())
+	go catalogSyncService.Run(context.Background())
+	go authService.RunTokenCleanup(context.Background(), cfg.TokenCleanupInterval)
+	go storageService.Run(context.Background(), cfg.StorageReconcileInterval)
+	go storageService.RunDeletions(context.Background(), cfg.DeletionPollInterval)
+	go featureFlagService.Run(context.Background(), cfg.FlagRefreshInterval)
+	go suggestService.Run(context.Background(), cfg.SuggestRefreshInterval)
+	go ratingService.Run(context.Background(), cfg.RatingRefreshInterval)
+	go uploadService.Run(context.Background())
+	go priceScheduleService.Run(context.Background(), cfg.PriceScheduleInterval)
+	go quoteService.Run(context.Background())
+	go cartService.Run(context.Background())
+	go reportService.Run(context.Background())
+	go debugCaptureService.Run(context.Background())
+	go chaosService.Run(context.Background())
+	go archiveService.Run(context.Background())
+	go counterService.Run(context.Background(), cfg.CounterFlushInterval, cfg.CounterReconcileInterval)
+
+	if err := authService.BootstrapAdmin(context.Background(), cfg.InitialAdminEmail, cfg.InitialAdminPassword); err != nil {
+		logger.Error("Failed to create initial admin: ", err)
+	}
+
+	geoLocator, err := services.NewGeoLocator(cfg, apiUsageService)
+	if err != nil {
+		logger.Fatal("Failed to initialize GeoIP lookups: ", err)
+	}
+	// Registered ahead of maintenance mode and the remaining middleware so
+	// embargoed countries are refused before they run; the global middleware
+	// above, such as logging and rate limits, still sees those requests
+	router.Use(middleware.GeoIPMiddleware(cfg, geoLocator))
+	// Maintenance mode needs the flag service, so it is registered after the services
+	router.Use(middleware.MaintenanceMiddleware(cfg, featureFlagService))
+	// Audits requests made with admin impersonation tokens
+	router.Use(middleware.ImpersonationAudit(auditService))
+	if debugCaptureService.Enabled() {
+		router.Use(middleware.DebugCapture(debugCaptureService))
+	}
+	if chaosService.Enabled() {
+		router.Use(middleware.ChaosMiddleware(chaosService))
+	}
 
 	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(authService)
+	authHandler := handlers.NewAuthHandler(authService, cartService, cfg)
+	avatarHandler := handlers.NewAvatarHandler(avatarService)
+	preferenceHandler := handlers.NewPreferenceHandler(preferenceService)
+	collectionHandler := handlers.NewCollectionHandler(collectionService)
+	bannerHandler := handlers.NewBannerHandler(bannerService)
+	productReportHandler := handlers.NewProductReportHandler(productReportService)
+	adminNoteHandler := handlers.NewAdminNoteHandler(adminNoteService)
 	passwordHandler := handlers.NewPasswordHandler(authService)
+	otpHandler := handlers.NewOTPHandler(otpService)
 	reviewHandler := handlers.NewReviewHandler(reviewService)
 	adminHandler := handlers.NewAdminHandler(adminService)
-	productHandler := handlers.NewProductHandler(productService)
+	productHandler := handlers.NewProductHandler(productService, counterService)
+	announcementHandler := handlers.NewAnnouncementHandler(announcementService)
+	dashboardHandler := handlers.NewDashboardHandler(snapshotService)
+	apiUsageHandler := handlers.NewAPIUsageHandler(apiUsageService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	jobHandler := handlers.NewJobHandler(jobService, cfg)
+	adminEventHandler := handlers.NewAdminEventHandler(adminEventHub, cfg)
+	productUpdateHandler := handlers.NewProductUpdateHandler(productUpdateHub)
+	suggestHandler := handlers.NewSuggestHandler(suggestService)
+	savedSearchHandler := handlers.NewSavedSearchHandler(savedSearchService, productService)
+	referralHandler := handlers.NewReferralHandler(referralService)
+	fraudHandler := handlers.NewFraudHandler(fraudService)
+	vendorHandler := handlers.NewVendorHandler(vendorService)
+	storeHandler := handlers.NewStoreHandler(storeService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	impersonationHandler := handlers.NewImpersonationHandler(impersonationService)
+	adminUserHandler := handlers.NewAdminUserHandler(authService)
+	invitationHandler := handlers.NewInvitationHandler(invitationService)
+	imageHandler := handlers.NewImageHandler(imageProxyService)
+	stockHandler := handlers.NewStockHandler(stockService)
+	bundleHandler := handlers.NewBundleHandler(bundleService)
+	productImportHandler := handlers.NewProductImportHandler(productImportService)
+	importSourceHandler := handlers.NewImportSourceHandler(feedImportService)
+	catalogConnectorHandler := handlers.NewCatalogConnectorHandler(catalogSyncService)
+	productExtractionHandler := handlers.NewProductExtractionHandler(productExtractionService)
+	storageHandler := handlers.NewStorageHandler(storageService)
+	uploadHandler := handlers.NewUploadHandler(uploadService)
+	priceScheduleHandler := handlers.NewPriceScheduleHandler(priceScheduleService)
+	customerGroupHandler := handlers.NewCustomerGroupHandler(customerGroupService)
+	quoteHandler := handlers.NewQuoteHandler(quoteService)
+	cartHandler := handlers.NewCartHandler(cartService, cfg)
+	locationHandler := handlers.NewLocationHandler(locationService)
+	deliveryHandler := handlers.NewDeliveryHandler(deliveryService)
+	reportHandler := handlers.NewReportHandler(reportService)
+	featureFlagHandler := handlers.NewFeatureFlagHandler(featureFlagService)
+	debugCaptureHandler := handlers.NewDebugCaptureHandler(debugCaptureService)
+	chaosHandler := handlers.NewChaosHandler(chaosService)
+	metaHandler := handlers.NewMetaHandler(metaService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService)
 
 	// Health check
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok", "message": "Server is running"})
 	})
 
-	// API routes
-	api := router.Group("/api/v1")
-
-	// Auth routes (public)
-	auth := api.Group("/auth")
-	{
-		auth.GET("/health", func(c *gin.Context) {
-			c.JSON(200, gin.H{"status": "ok", "message": "Auth service is running"})
-		})
-		auth.POST("/signup", authHandler.Signup)
-		auth.POST("/login", authHandler.Login)
-		auth.POST("/logout", middleware.AuthMiddleware(cfg), authHandler.Logout)
-		auth.POST("/refresh-token", authHandler.RefreshToken)
-		auth.GET("/profile", middleware.AuthMiddleware(cfg), authHandler.GetProfile)
-		auth.PUT("/profile-update", middleware.AuthMiddleware(cfg), authHandler.UpdateProfile)
-	}
+	// Prometheus scrape endpoint for business and runtime metrics
+	router.GET("/metrics", middleware.MetricsAuth(cfg.MetricsToken), gin.WrapH(metrics.Handler()))
 
-	// Password reset routes
-	passwordGroup := api.Group("/password")
-	{
-		passwordGroup.POST("/forgot", passwordHandler.ForgotPassword)
-		passwordGroup.GET("/validate-reset-token",  passwordHandler.ValidateResetToken, ) // Requires authentication
-		passwordGroup.POST("/reset", passwordHandler.ResetPassword)
-		passwordGroup.POST("/change", middleware.AuthMiddleware(cfg), passwordHandler.ChangePassword) // Requires authentication
-	}
-	// Review routes
-	reviews := api.Group("/reviews")
-	{
-		reviews.GET("/product/:product_id",middleware.AuthMiddleware(cfg), reviewHandler.GetProductReviews)
-		reviews.POST("/", middleware.AuthMiddleware(cfg), reviewHandler.CreateReview)
-		reviews.POST("/product/like/:product_id",middleware.AuthMiddleware(cfg),reviewHandler.LikeOrDislikeProduct)
-		reviews.GET("/product/like/:product_id",middleware.AuthMiddleware(cfg),reviewHandler.GetProductReaction)
-		reviews.POST("/:review_id/like", middleware.AuthMiddleware(cfg), reviewHandler.LikeReview)
-		reviews.POST("/:review_id/flag", middleware.AuthMiddleware(cfg), middleware.CustomerOrAdmin(), reviewHandler.FlagReview)
-	}
+	// Image transformation proxy (public, cacheable)
+	router.GET("/img/*key", imageHandler.ServeImage)
 
+	// API routes. v1 and v2 share handlers and services: v2 responses are
+	// rewritten into the standardized envelope, and v1 announces its deprecation.
+	mountAPI := func(api *gin.RouterGroup) {
+		// Store management is registered ahead of the tenant middleware so the
+		// first store can be created before any request resolves to one. Only
+		// admins without a store of their own may manage stores.
+		stores := api.Group("/admin/stores", middleware.AuthMiddleware(cfg), middleware.DeploymentAdminOnly())
+		{
+			stores.GET("", storeHandler.GetStores)
+			stores.POST("", storeHandler.CreateStore)
+			stores.GET("/:store_id", storeHandler.GetStore)
+			stores.PUT("/:store_id", storeHandler.UpdateStore)
+			stores.DELETE("/:store_id", storeHandler.DeleteStore)
+		}
 
-	// Product routes
-	products := api.Group("/products")
-	{
-		products.GET("/", middleware.AuthMiddleware(cfg),productHandler.GetAllProducts)
-		products.GET("/:product_id", middleware.AuthMiddleware(cfg),productHandler.GetProduct)
-		products.GET("/category",middleware.AuthMiddleware(cfg),productHandler.GetCategories)
-	}
+		// Every route below is scoped to the request's store in multi-tenant mode
+		api.Use(middleware.TenantMiddleware(cfg, storeService))
+
+		// Capabilities document for client SDKs (public)
+		api.GET("/meta", metaHandler.GetMeta)
+
+		// Feature flags evaluated for the caller (public, rollouts bucket by user when signed in)
+		api.GET("/features", middleware.OptionalAuthMiddleware(cfg), featureFlagHandler.GetFeatures)
+
+		// Auth routes (public)
+		auth := api.Group("/auth")
+		{
+			auth.GET("/health", func(c *gin.Context) {
+				c.JSON(200, gin.H{"status": "ok", "message": "Auth service is running"})
+			})
+			auth.POST("/signup", authHandler.Signup)
+			auth.POST("/login", authHandler.Login)
+			auth.POST("/logout", middleware.AuthMiddleware(cfg), middleware.DenyImpersonation(), authHandler.Logout)
+			auth.POST("/refresh-token", authHandler.RefreshToken)
+			auth.POST("/otp/send", otpHandler.SendCode)
+			auth.POST("/otp/verify", otpHandler.VerifyCode)
+			auth.GET("/invitations/:token", invitationHandler.PreviewInvitation)
+			auth.POST("/invitations/accept", invitationHandler.AcceptInvitation)
+			auth.GET("/profile", middleware.AuthMiddleware(cfg), authHandler.GetProfile)
+			// Tickets for opening admin streams from a browser
+			auth.POST("/stream-ticket", middleware.AuthMiddleware(cfg), authHandler.IssueStreamTicket)
+			auth.PUT("/profile-update", middleware.AuthMiddleware(cfg), middleware.DenyImpersonation(), authHandler.UpdateProfile)
+			auth.POST("/profile/avatar", middleware.AuthMiddleware(cfg), middleware.DenyImpersonation(), avatarHandler.UploadAvatar)
+			auth.DELETE("/profile/avatar", middleware.AuthMiddleware(cfg), middleware.DenyImpersonation(), avatarHandler.DeleteAvatar)
+			auth.GET("/profile/referral", middleware.AuthMiddleware(cfg), referralHandler.GetMyReferral)
+			auth.GET("/preferences", middleware.AuthMiddleware(cfg), preferenceHandler.GetPreferences)
+			auth.PUT("/preferences", middleware.AuthMiddleware(cfg), preferenceHandler.UpdatePreferences)
+		}
+
+		// Password reset routes
+		passwordGroup := api.Group("/password")
+		{
+			passwordGroup.POST("/forgot", passwordHandler.ForgotPassword)
+			passwordGroup.GET("/validate-reset-token",  passwordHandler.ValidateResetToken, ) // Requires authentication
+			passwordGroup.POST("/reset", passwordHandler.ResetPassword)
+			passwordGroup.POST("/change", middleware.PasswordChangeAuthMiddleware(cfg), middleware.DenyImpersonation(), passwordHandler.ChangePassword) // Requires authentication
+		}
+		// Review routes
+		reviews := api.Group("/reviews")
+		{
+			reviews.GET("/product/:product_id",middleware.AuthMiddleware(cfg), reviewHandler.GetProductReviews)
+			reviews.POST("/", middleware.AuthMiddleware(cfg), middleware.RequireFeature(featureFlagService, models.FlagReviewsEnabled), reviewHandler.CreateReview)
+			reviews.POST("/product/like/:product_id",middleware.AuthMiddleware(cfg),reviewHandler.LikeOrDislikeProduct)
+			reviews.GET("/product/like/:product_id",middleware.AuthMiddleware(cfg),reviewHandler.GetProductReaction)
+			reviews.POST("/:review_id/like", middleware.AuthMiddleware(cfg), reviewHandler.LikeReview)
+			reviews.POST("/:review_id/flag", middleware.AuthMiddleware(cfg), middleware.CustomerOrAdmin(), reviewHandler.FlagReview)
+			reviews.PUT("/:review_id", middleware.AuthMiddleware(cfg), reviewHandler.UpdateReview)
+			reviews.DELETE("/:review_id", middleware.AuthMiddleware(cfg), reviewHandler.DeleteReview)
+		}
+
+
+		// Product routes
+		products := api.Group("/products")
+		{
+			products.GET("/", middleware.AuthMiddleware(cfg),productHandler.GetAllProducts)
+			products.GET("/:product_id", middleware.AuthMiddleware(cfg),productHandler.GetProduct)
+			products.GET("/category",middleware.AuthMiddleware(cfg),productHandler.GetCategories)
+			// Live stock and price changes for storefront pages (SSE)
+			products.GET("/updates", middleware.OptionalAuthMiddleware(cfg), productUpdateHandler.StreamProductUpdates)
+			products.GET("/suggest", middleware.RouteRateLimit(int64(cfg.SuggestRateLimit), time.Second), suggestHandler.Suggest)
+			products.POST("/:product_id/report", middleware.AuthMiddleware(cfg), middleware.CustomerOrAdmin(), productReportHandler.ReportProduct)
+			products.GET("/:product_id/services", productHandler.GetProductServices)
+			products.GET("/:product_id/price", productHandler.QuotePrice)
+			products.GET("/:product_id/delivery-estimate", deliveryHandler.EstimateDelivery)
+		}
+
+		// Announcement routes (public, audience depends on the optional token)
+		api.GET("/announcements", middleware.OptionalAuthMiddleware(cfg), announcementHandler.GetActiveAnnouncements)
+
+		// Curated collections (public)
+		api.GET("/collections", collectionHandler.GetActiveCollections)
+		api.GET("/collections/:slug", collectionHandler.GetCollectionBySlug)
+
+		// Storefront banners currently showing, by placement (public)
+		api.GET("/banners", bannerHandler.GetActiveBanners)
 
-	// Admin routes
-	admin := api.Group("/admin", middleware.AuthMiddleware(cfg), middleware.AdminOnly())
-	{
-		admin.GET("/dashboard", adminHandler.GetDashboard)
-		
-		// Product management
-		// admin.POST("/upload/images", adminHandler.UploadImages)
-		// admin.POST("/upload/csv", adminHandler.UploadCSV)
-		admin.GET("/products", adminHandler.GetProducts)
-		admin.POST("/products", adminHandler.CreateProduct)
-		admin.GET("/products/:product_id", adminHandler.GetProduct)
-
-		admin.PUT("/products/:product_id", adminHandler.UpdateProduct)
-		admin.POST("/products/:product_id/images", adminHandler.UploadProductImages)
-		admin.DELETE("/products/:product_id/images/:image_id", adminHandler.DeleteProductImage)
-		admin.DELETE("/products/batch", adminHandler.BatchDeleteProducts)
-		admin.DELETE("/products/:product_id", adminHandler.DeleteProduct)
-		admin.GET("/products/search", adminHandler.SearchProducts)
-
-		// Review moderation
-		admin.GET("/reviews/flagged", reviewHandler.GetFlaggedReviews)
-		admin.POST("/reviews/:review_id/moderate", reviewHandler.ModerateReview)
+		// Pickup points for checkout, optionally with a product's availability (public)
+		api.GET("/pickup-locations", locationHandler.GetPickupLocations)
+
+		// Saved search routes (the caller's own searches only)
+		savedSearches := api.Group("/saved-searches", middleware.AuthMiddleware(cfg))
+		{
+			savedSearches.GET("", savedSearchHandler.GetSavedSearches)
+			savedSearches.POST("", savedSearchHandler.CreateSavedSearch)
+			savedSearches.GET("/:search_id", savedSearchHandler.GetSavedSearch)
+			savedSearches.PUT("/:search_id", savedSearchHandler.UpdateSavedSearch)
+			savedSearches.DELETE("/:search_id", savedSearchHandler.DeleteSavedSearch)
+			savedSearches.GET("/:search_id/products", savedSearchHandler.GetSavedSearchProducts)
+		}
+
+		// B2B quote requests (the caller's own quotes only)
+		quotes := api.Group("/quotes", middleware.AuthMiddleware(cfg))
+		{
+			quotes.GET("", quoteHandler.GetMyQuotes)
+			quotes.POST("", quoteHandler.CreateQuote)
+			quotes.GET("/:quote_id", quoteHandler.GetMyQuote)
+			quotes.GET("/:quote_id/pdf", quoteHandler.DownloadQuote)
+			quotes.POST("/:quote_id/accept", quoteHandler.AcceptQuote)
+			quotes.POST("/:quote_id/decline", quoteHandler.DeclineQuote)
+		}
+
+		// Shopping cart: the signed-in user's, or an anonymous cart found by
+		// its cart token and merged into the user's on login or signup
+		cart := api.Group("/cart", middleware.OptionalAuthMiddleware(cfg))
+		{
+			cart.GET("", cartHandler.GetCart)
+			cart.DELETE("", cartHandler.ClearCart)
+			cart.POST("/items", cartHandler.AddItem)
+			cart.PUT("/items/:product_id", cartHandler.UpdateItem)
+			cart.DELETE("/items/:product_id", cartHandler.RemoveItem)
+			// Shared carts: a snapshot others can view and import into their own
+			cart.POST("/share", cartHandler.ShareCart)
+			cart.GET("/shared/:token", cartHandler.GetSharedCart)
+			cart.POST("/shared/:token/import", cartHandler.ImportSharedCart)
+		}
+
+		// Admin streams, which browsers open with a stream ticket in place of
+		// the Authorization header
+		adminStreams := api.Group("/admin", middleware.StreamAuthMiddleware(cfg), middleware.AdminOnly())
+		{
+			// Background job progress (WebSocket)
+			adminStreams.GET("/jobs/ws", jobHandler.StreamJobs)
+			// Live activity feed (SSE, or WebSocket on upgrade)
+			adminStreams.GET("/events", adminEventHandler.StreamEvents)
+		}
+
+		// Admin routes
+		admin := api.Group("/admin", middleware.AuthMiddleware(cfg), middleware.AdminOnly())
+		{
+			admin.GET("/dashboard", adminHandler.GetDashboard)
+			admin.GET("/dashboard/trends", dashboardHandler.GetTrends)
+			admin.POST("/dashboard/snapshots", dashboardHandler.CaptureSnapshot)
+			admin.GET("/api-usage", apiUsageHandler.GetUsageReport)
+
+			// Catalogue-wide product operations
+			// admin.POST("/upload/images", adminHandler.UploadImages)
+			// admin.POST("/upload/csv", adminHandler.UploadCSV)
+			admin.DELETE("/products/batch", adminHandler.BatchDeleteProducts)
+			admin.POST("/products/import/csv", adminHandler.ImportProductsCSV)
+			admin.POST("/products/export", adminHandler.ExportProducts)
+			admin.POST("/categories/rename", categoryHandler.RenameCategory)
+			admin.POST("/categories/merge", categoryHandler.MergeCategories)
+			admin.POST("/images/alt-text", adminHandler.GenerateAltText)
+			importV2 := middleware.RequireFeature(featureFlagService, models.FlagCSVImportV2)
+			admin.POST("/products/imports", importV2, productImportHandler.UploadImport)
+			admin.GET("/products/imports/:import_id", importV2, productImportHandler.GetImport)
+			admin.POST("/products/imports/:import_id/run", importV2, productImportHandler.RunImport)
+			admin.GET("/products/imports/:import_id/errors", importV2, productImportHandler.DownloadErrors)
+
+			// Image-based product extraction (drafts reviewed before publishing)
+			admin.POST("/products/extract", productExtractionHandler.ExtractProducts)
+			admin.GET("/products/drafts", productExtractionHandler.GetDrafts)
+			admin.GET("/products/drafts/:draft_id", productExtractionHandler.GetDraft)
+			admin.GET("/products/drafts/:draft_id/images/:name", productExtractionHandler.GetDraftImage)
+			admin.POST("/products/drafts/:draft_id/approve", productExtractionHandler.ApproveDraft)
+			admin.POST("/products/drafts/:draft_id/reject", productExtractionHandler.RejectDraft)
+
+			// Scheduled supplier feed imports
+			admin.GET("/import-sources", importSourceHandler.GetSources)
+			admin.POST("/import-sources", importSourceHandler.CreateSource)
+			admin.GET("/import-sources/:source_id", importSourceHandler.GetSource)
+			admin.PUT("/import-sources/:source_id", importSourceHandler.UpdateSource)
+			admin.DELETE("/import-sources/:source_id", importSourceHandler.DeleteSource)
+			admin.POST("/import-sources/:source_id/run", importSourceHandler.TriggerRun)
+			admin.GET("/import-sources/:source_id/runs", importSourceHandler.GetRuns)
+
+			// Catalog push to external Shopify/WooCommerce stores
+			admin.GET("/catalog-connectors", catalogConnectorHandler.GetConnectors)
+			admin.POST("/catalog-connectors", catalogConnectorHandler.CreateConnector)
+			admin.GET("/catalog-connectors/:connector_id", catalogConnectorHandler.GetConnector)
+			admin.PUT("/catalog-connectors/:connector_id", catalogConnectorHandler.UpdateConnector)
+			admin.DELETE("/catalog-connectors/:connector_id", catalogConnectorHandler.DeleteConnector)
+			admin.POST("/catalog-connectors/:connector_id/run", catalogConnectorHandler.TriggerRun)
+			admin.GET("/catalog-connectors/:connector_id/runs", catalogConnectorHandler.GetRuns)
+
+			// Background jobs (import/export progress console)
+			admin.GET("/jobs", jobHandler.GetJobs)
+			admin.GET("/jobs/:job_id", jobHandler.GetJob)
+			admin.GET("/jobs/:job_id/logs", jobHandler.GetJobLogs)
+
+			// Canned reports (CSV/PDF in S3, with signed download links)
+			admin.GET("/reports", reportHandler.GetReports)
+			admin.POST("/reports", reportHandler.CreateReport)
+			admin.GET("/reports/:report_id", reportHandler.GetReport)
+			admin.DELETE("/reports/:report_id", reportHandler.DeleteReport)
+
+			// Feature flags
+			admin.GET("/feature-flags", featureFlagHandler.GetFlags)
+			admin.POST("/feature-flags", featureFlagHandler.CreateFlag)
+			admin.GET("/feature-flags/:key", featureFlagHandler.GetFlag)
+			admin.PUT("/feature-flags/:key", featureFlagHandler.UpdateFlag)
+			admin.DELETE("/feature-flags/:key", featureFlagHandler.DeleteFlag)
+
+			// Request/response recording of selected routes, outside production only
+			admin.GET("/debug-capture/rules", debugCaptureHandler.GetRules)
+			admin.POST("/debug-capture/rules", debugCaptureHandler.CreateRule)
+			admin.DELETE("/debug-capture/rules/:rule_id", debugCaptureHandler.DeleteRule)
+			admin.GET("/debug-capture/captures", debugCaptureHandler.GetCaptures)
+			admin.DELETE("/debug-capture/captures", debugCaptureHandler.ClearCaptures)
+			admin.GET("/debug-capture/captures/:capture_id", debugCaptureHandler.GetCapture)
+
+			// Failure injection for testing clients, outside production only
+			admin.GET("/chaos/rules", chaosHandler.GetRules)
+			admin.POST("/chaos/rules", chaosHandler.CreateRule)
+			admin.PUT("/chaos/rules/:rule_id", chaosHandler.UpdateRule)
+			admin.DELETE("/chaos/rules/:rule_id", chaosHandler.DeleteRule)
+
+			// S3 storage reconciliation
+			admin.GET("/storage/report", storageHandler.GetReport)
+			admin.POST("/storage/reconcile", storageHandler.Reconcile)
+			admin.GET("/storage/pending-deletions", storageHandler.GetPendingDeletions)
+			admin.POST("/storage/pending-deletions/:deletion_id/retry", storageHandler.RetryDeletion)
+
+			// Resumable uploads of large media
+			admin.POST("/uploads", uploadHandler.CreateUpload)
+			admin.GET("/uploads/:upload_id", uploadHandler.GetUpload)
+			admin.PUT("/uploads/:upload_id/parts/:part_number", uploadHandler.UploadPart)
+			admin.POST("/uploads/:upload_id/complete", uploadHandler.CompleteUpload)
+			admin.DELETE("/uploads/:upload_id", uploadHandler.AbortUpload)
+
+			// Review moderation
+			admin.GET("/reviews/flagged", reviewHandler.GetFlaggedReviews)
+			admin.GET("/reviews/export", adminHandler.ExportReviews)
+			admin.POST("/reviews/:review_id/moderate", reviewHandler.ModerateReview)
+			admin.PUT("/reviews/:review_id/visibility", reviewHandler.UpdateReviewVisibility)
+			admin.GET("/reviews/:review_id/moderation", reviewHandler.GetReviewModerationHistory)
+			admin.GET("/reviews/:review_id/edits", reviewHandler.GetReviewEdits)
+
+			// Announcements
+			admin.GET("/announcements", announcementHandler.GetAnnouncements)
+			admin.POST("/announcements", announcementHandler.CreateAnnouncement)
+			admin.PUT("/announcements/:announcement_id", announcementHandler.UpdateAnnouncement)
+			admin.DELETE("/announcements/:announcement_id", announcementHandler.DeleteAnnouncement)
+
+			// Curated collections
+			admin.GET("/collections", collectionHandler.GetCollections)
+			admin.POST("/collections", collectionHandler.CreateCollection)
+			admin.GET("/collections/:collection_id", collectionHandler.GetCollection)
+			admin.PUT("/collections/:collection_id", collectionHandler.UpdateCollection)
+			admin.DELETE("/collections/:collection_id", collectionHandler.DeleteCollection)
+
+			// Storefront banners
+			admin.GET("/banners", bannerHandler.GetBanners)
+			admin.POST("/banners", bannerHandler.CreateBanner)
+			admin.GET("/banners/:banner_id", bannerHandler.GetBanner)
+			admin.PUT("/banners/:banner_id", bannerHandler.UpdateBanner)
+			admin.POST("/banners/:banner_id/image", bannerHandler.UploadBannerImage)
+			admin.DELETE("/banners/:banner_id", bannerHandler.DeleteBanner)
+
+			// Store locations, pickup points and the stock they hold
+			admin.GET("/locations", locationHandler.GetLocations)
+			admin.POST("/locations", locationHandler.CreateLocation)
+			admin.GET("/locations/:location_id", locationHandler.GetLocation)
+			admin.PUT("/locations/:location_id", locationHandler.UpdateLocation)
+			admin.DELETE("/locations/:location_id", locationHandler.DeleteLocation)
+			admin.GET("/locations/:location_id/stock", locationHandler.GetLocationStock)
+			admin.POST("/locations/:location_id/stock/adjust", locationHandler.AdjustLocationStock)
+
+			// Carrier rate tables used for delivery estimates
+			admin.GET("/shipping-rates", deliveryHandler.GetShippingRates)
+			admin.POST("/shipping-rates", deliveryHandler.CreateShippingRate)
+			admin.PUT("/shipping-rates/:rate_id", deliveryHandler.UpdateShippingRate)
+			admin.DELETE("/shipping-rates/:rate_id", deliveryHandler.DeleteShippingRate)
+
+			// Customer reports of product listings
+			admin.GET("/product-reports", productReportHandler.GetReports)
+			admin.GET("/product-reports/:report_id", productReportHandler.GetReport)
+			admin.PUT("/product-reports/:report_id", productReportHandler.UpdateReport)
+
+			// Internal staff notes (admins only, never shown to vendors or customers)
+			admin.GET("/products/:product_id/notes", adminNoteHandler.GetProductNotes)
+			admin.POST("/products/:product_id/notes", adminNoteHandler.CreateProductNote)
+			admin.GET("/users/:user_id/notes", adminNoteHandler.GetUserNotes)
+			admin.POST("/users/:user_id/notes", adminNoteHandler.CreateUserNote)
+			admin.PUT("/notes/:note_id", adminNoteHandler.UpdateNote)
+			admin.DELETE("/notes/:note_id", adminNoteHandler.DeleteNote)
+
+			// Webhooks
+			admin.GET("/webhooks", webhookHandler.GetWebhooks)
+			admin.POST("/webhooks", webhookHandler.CreateWebhook)
+			admin.GET("/webhooks/:webhook_id", webhookHandler.GetWebhook)
+			admin.PUT("/webhooks/:webhook_id", webhookHandler.UpdateWebhook)
+			admin.DELETE("/webhooks/:webhook_id", webhookHandler.DeleteWebhook)
+			admin.GET("/webhooks/:webhook_id/deliveries", webhookHandler.GetDeliveries)
+
+			// Customer support: act as a customer, with every request audited
+			admin.POST("/users/:user_id/impersonate", impersonationHandler.Impersonate)
+			admin.PUT("/users/:user_id/role", adminUserHandler.UpdateUserRole)
+			admin.POST("/users/:user_id/restore", adminUserHandler.RestoreUser)
+			admin.POST("/users/:user_id/merge", adminUserHandler.MergeUsers)
+
+			// Referral program performance and suspected self-referrals
+			admin.GET("/referrals", referralHandler.GetReferrals)
+			admin.GET("/referrals/report", referralHandler.GetReferralReport)
+
+			// High-risk signups held for review
+			admin.GET("/fraud-reviews", fraudHandler.GetReviews)
+			admin.POST("/fraud-reviews/:review_id/resolve", fraudHandler.ResolveReview)
+
+			// Customer groups and their prices
+			admin.GET("/customer-groups", customerGroupHandler.GetGroups)
+			admin.POST("/customer-groups", customerGroupHandler.CreateGroup)
+			admin.GET("/customer-groups/:group_id", customerGroupHandler.GetGroup)
+			admin.PUT("/customer-groups/:group_id", customerGroupHandler.UpdateGroup)
+			admin.DELETE("/customer-groups/:group_id", customerGroupHandler.DeleteGroup)
+			admin.POST("/customer-groups/:group_id/prices", customerGroupHandler.AddPrice)
+			admin.DELETE("/customer-groups/:group_id/prices/:price_id", customerGroupHandler.DeletePrice)
+			admin.PUT("/users/:user_id/customer-group", customerGroupHandler.SetUserGroup)
+
+			// Quote requests: price them for the customer or turn them down
+			admin.GET("/quotes", quoteHandler.GetQuotes)
+			admin.GET("/quotes/:quote_id", quoteHandler.GetQuote)
+			admin.POST("/quotes/:quote_id/respond", quoteHandler.RespondToQuote)
+			admin.POST("/quotes/:quote_id/reject", quoteHandler.RejectQuote)
+
+			admin.GET("/invitations", invitationHandler.GetInvitations)
+			admin.POST("/invitations", invitationHandler.CreateInvitation)
+			admin.DELETE("/invitations/:invitation_id", invitationHandler.RevokeInvitation)
+			admin.GET("/audit-logs", auditHandler.GetAuditLogs)
+
+			// Vendors (multi-vendor catalogue)
+			admin.GET("/vendors", vendorHandler.GetVendors)
+			admin.POST("/vendors", vendorHandler.CreateVendor)
+			admin.GET("/vendors/:vendor_id", vendorHandler.GetVendor)
+			admin.PUT("/vendors/:vendor_id", vendorHandler.UpdateVendor)
+			admin.DELETE("/vendors/:vendor_id", vendorHandler.DeleteVendor)
+			admin.GET("/vendors/:vendor_id/users", vendorHandler.GetVendorUsers)
+			admin.POST("/vendors/:vendor_id/users", vendorHandler.AssignVendorUser)
+			admin.DELETE("/vendors/:vendor_id/users/:user_id", vendorHandler.RemoveVendorUser)
+		}
+
+		// Product management, shared with vendor users who only see and
+		// change their own vendor's products
+		catalog := api.Group("/admin", middleware.AuthMiddleware(cfg), middleware.AdminOrVendor(), middleware.VendorScope(vendorService))
+		{
+			catalog.GET("/products", adminHandler.GetProducts)
+			catalog.POST("/products", adminHandler.CreateProduct)
+			catalog.GET("/products/:product_id", adminHandler.GetProduct)
+			catalog.PUT("/products/:product_id", adminHandler.UpdateProduct)
+			catalog.POST("/products/:product_id/images", adminHandler.UploadProductImages)
+			catalog.POST("/products/:product_id/images/import", adminHandler.ImportProductImages)
+			catalog.PATCH("/products/:product_id/images/:image_id", adminHandler.UpdateProductImage)
+			catalog.DELETE("/products/:product_id/images/:image_id", adminHandler.DeleteProductImage)
+			catalog.POST("/products/:product_id/services", adminHandler.AddProductService)
+			catalog.PUT("/products/:product_id/services/:service_id", adminHandler.UpdateProductService)
+			catalog.DELETE("/products/:product_id/services/:service_id", adminHandler.DeleteProductService)
+			catalog.DELETE("/products/:product_id", adminHandler.DeleteProduct)
+			catalog.GET("/products/:product_id/delete-check", adminHandler.GetProductDeleteCheck)
+			catalog.POST("/products/:product_id/archive", adminHandler.ArchiveProduct)
+			catalog.POST("/products/:product_id/duplicate", adminHandler.DuplicateProduct)
+			catalog.GET("/products/:product_id/revisions", adminHandler.GetProductRevisions)
+			catalog.POST("/products/:product_id/revisions/:revision/restore", adminHandler.RestoreProductRevision)
+			catalog.GET("/products/:product_id/price-changes", priceScheduleHandler.GetPriceChanges)
+			catalog.POST("/products/:product_id/price-changes", priceScheduleHandler.SchedulePriceChange)
+			catalog.DELETE("/products/:product_id/price-changes/:change_id", priceScheduleHandler.CancelPriceChange)
+			catalog.GET("/products/:product_id/price-tiers", adminHandler.GetPriceTiers)
+			catalog.POST("/products/:product_id/price-tiers", adminHandler.AddPriceTier)
+			catalog.PUT("/products/:product_id/price-tiers/:tier_id", adminHandler.UpdatePriceTier)
+			catalog.DELETE("/products/:product_id/price-tiers/:tier_id", adminHandler.DeletePriceTier)
+			catalog.POST("/products/:product_id/stock/adjust", stockHandler.AdjustStock)
+			catalog.GET("/products/:product_id/stock/movements", stockHandler.GetStockMovements)
+			catalog.GET("/products/:product_id/bundle", bundleHandler.GetBundle)
+			catalog.PUT("/products/:product_id/bundle", bundleHandler.SetBundle)
+			catalog.DELETE("/products/:product_id/bundle", bundleHandler.DeleteBundle)
+			catalog.GET("/products/search", adminHandler.SearchProducts)
+			catalog.GET("/products/quality", adminHandler.GetProductQualityReport)
+			catalog.GET("/products/stream", adminHandler.StreamProducts)
+		}
 	}
 
+	v1 := router.Group("/api/v1")
+	v1.Use(middleware.DeprecationMiddleware(cfg.APIV1DeprecatedAt, cfg.APIV1Sunset, "/api/v1", "/api/v2"))
+	v1.Use(middleware.CSRFMiddleware())
+	mountAPI(v1)
+
+	v2 := router.Group("/api/v2")
+	v2.Use(middleware.V2EnvelopeMiddleware())
+	v2.Use(middleware.CSRFMiddleware())
+	mountAPI(v2)
+
 	logger.Info("Routes initialized successfully")
 }
\ No newline at end of file
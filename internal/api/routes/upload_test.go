@@ -0,0 +1,108 @@
+package routes_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/testutil"
+)
+
+func TestProductImageUploads(t *testing.T) {
+	env := testutil.NewEnv(t)
+	token := env.Login(t, env.CreateAdmin(t))
+	product := env.CreateProduct(t, 30)
+	path := fmt.Sprintf("/api/v1/admin/products/%d/images", product.ID)
+
+	t.Run("without images", func(t *testing.T) {
+		body, headers := testutil.Multipart(t, nil)
+		env.Do(t, http.MethodPost, path, body, token, headers).ExpectStatus(t, http.StatusBadRequest)
+	})
+
+	var image models.Image
+	t.Run("upload", func(t *testing.T) {
+		body, headers := testutil.Multipart(t, nil, testutil.FormFile{
+			Field:       "images",
+			Name:        "front.png",
+			ContentType: "image/png",
+			Data:        testutil.PNG(t, 16, 16, 40),
+		})
+
+		var updated models.Product
+		env.Do(t, http.MethodPost, path, body, token, headers).ExpectStatus(t, http.StatusOK).Decode(t, &updated)
+		if len(updated.Images) != 1 || updated.Images[0].ContentType != "image/png" || updated.Images[0].S3URL == "" {
+			t.Fatalf("expected one stored PNG, got %+v", updated.Images)
+		}
+		image = updated.Images[0]
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		var updated models.Product
+		env.Do(t, http.MethodDelete, path+"/"+image.ID.String(), nil, token).
+			ExpectStatus(t, http.StatusOK).Decode(t, &updated)
+		if len(updated.Images) != 0 {
+			t.Fatalf("expected the image to be removed, got %+v", updated.Images)
+		}
+	})
+}
+
+func TestResumableUploads(t *testing.T) {
+	env := testutil.NewEnv(t)
+	token := env.Login(t, env.CreateAdmin(t))
+	product := env.CreateProduct(t, 30)
+	data := testutil.PNG(t, 32, 32, 90)
+	rawBody := map[string]string{"Content-Type": "application/octet-stream"}
+
+	t.Run("unsupported content type", func(t *testing.T) {
+		env.Do(t, http.MethodPost, "/api/v1/admin/uploads", models.CreateUploadRequest{
+			FileName:    "notes.txt",
+			ContentType: "text/plain",
+			Size:        10,
+		}, token).ExpectStatus(t, http.StatusUnprocessableEntity)
+	})
+
+	t.Run("product image", func(t *testing.T) {
+		var session models.UploadSession
+		env.Do(t, http.MethodPost, "/api/v1/admin/uploads", models.CreateUploadRequest{
+			FileName:    "side.png",
+			ContentType: "image/png",
+			Size:        int64(len(data)),
+			ProductID:   &product.ID,
+		}, token).ExpectStatus(t, http.StatusOK).Decode(t, &session)
+		if session.PartCount != 1 || session.Status != models.UploadStatusUploading {
+			t.Fatalf("unexpected session %+v", session)
+		}
+		path := "/api/v1/admin/uploads/" + session.ID.String()
+
+		env.Do(t, http.MethodPost, path+"/complete", nil, token).ExpectStatus(t, http.StatusUnprocessableEntity)
+		env.Do(t, http.MethodPut, path+"/parts/1", bytes.NewReader(data), token, rawBody).ExpectStatus(t, http.StatusOK)
+		env.Do(t, http.MethodPost, path+"/complete", nil, token).ExpectStatus(t, http.StatusOK).Decode(t, &session)
+		if session.Status != models.UploadStatusCompleted || session.ImageID == nil || session.URL == "" {
+			t.Fatalf("expected a completed upload with an image, got %+v", session)
+		}
+
+		var updated models.Product
+		env.Do(t, http.MethodGet, fmt.Sprintf("/api/v1/admin/products/%d", product.ID), nil, token).
+			ExpectStatus(t, http.StatusOK).Decode(t, &updated)
+		if len(updated.Images) != 1 || updated.Images[0].ID != *session.ImageID {
+			t.Fatalf("expected image %s on the product, got %+v", session.ImageID, updated.Images)
+		}
+
+		env.Do(t, http.MethodPost, path+"/complete", nil, token).ExpectStatus(t, http.StatusConflict)
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		var session models.UploadSession
+		env.Do(t, http.MethodPost, "/api/v1/admin/uploads", models.CreateUploadRequest{
+			FileName:    "draft.png",
+			ContentType: "image/png",
+			Size:        int64(len(data)),
+		}, token).ExpectStatus(t, http.StatusOK).Decode(t, &session)
+		path := "/api/v1/admin/uploads/" + session.ID.String()
+
+		env.Do(t, http.MethodDelete, path, nil, token).ExpectStatus(t, http.StatusOK)
+		env.Do(t, http.MethodPut, path+"/parts/1", bytes.NewReader(data), token, rawBody).ExpectStatus(t, http.StatusConflict)
+	})
+}
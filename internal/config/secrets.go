@@ -0,0 +1,100 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/ssm"
+)
+
+// LoadSecrets pulls secrets from AWS into the process environment before Load
+// reads it. SECRETS_SOURCE selects the backend:
+//   - secretsmanager: SECRETS_ID names a secret holding a JSON object of ENV_NAME: value
+//   - ssm: SECRETS_SSM_PATH is a parameter path; each parameter's last segment is the env name
+//
+// Variables already set in the environment win, so local overrides keep working.
+func LoadSecrets() (int, error) {
+	source := strings.ToLower(os.Getenv("SECRETS_SOURCE"))
+	if source == "" || source == "none" {
+		return 0, nil
+	}
+
+	region := getEnv("AWS_REGION", getEnv("S3_REGION", "us-east-1"))
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(region)})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create AWS session: %v", err)
+	}
+
+	var values map[string]string
+	switch source {
+	case "secretsmanager":
+		values, err = loadSecretsManager(sess, os.Getenv("SECRETS_ID"))
+	case "ssm":
+		values, err = loadSSMParameters(sess, os.Getenv("SECRETS_SSM_PATH"))
+	default:
+		return 0, fmt.Errorf("unknown SECRETS_SOURCE %q", source)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for key, value := range values {
+		if _, set := os.LookupEnv(key); set {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return applied, fmt.Errorf("failed to set %s: %v", key, err)
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+func loadSecretsManager(sess *session.Session, secretID string) (map[string]string, error) {
+	if secretID == "" {
+		return nil, fmt.Errorf("SECRETS_ID is required when SECRETS_SOURCE=secretsmanager")
+	}
+
+	out, err := secretsmanager.New(sess).GetSecretValue(&secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret %s: %v", secretID, err)
+	}
+
+	values := make(map[string]string)
+	if err := json.Unmarshal([]byte(aws.StringValue(out.SecretString)), &values); err != nil {
+		return nil, fmt.Errorf("secret %s must be a JSON object of string values: %v", secretID, err)
+	}
+	return values, nil
+}
+
+func loadSSMParameters(sess *session.Session, parameterPath string) (map[string]string, error) {
+	if parameterPath == "" {
+		return nil, fmt.Errorf("SECRETS_SSM_PATH is required when SECRETS_SOURCE=ssm")
+	}
+
+	values := make(map[string]string)
+	err := ssm.New(sess).GetParametersByPathPages(&ssm.GetParametersByPathInput{
+		Path:           aws.String(parameterPath),
+		Recursive:      aws.Bool(true),
+		WithDecryption: aws.Bool(true),
+	}, func(page *ssm.GetParametersByPathOutput, lastPage bool) bool {
+		for _, param := range page.Parameters {
+			name := strings.ToUpper(path.Base(aws.StringValue(param.Name)))
+			values[name] = aws.StringValue(param.Value)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SSM parameters under %s: %v", parameterPath, err)
+	}
+	return values, nil
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+const minJWTSecretLength = 32
+
+// Validate checks the loaded configuration. In production, missing secrets and
+// development defaults are errors; elsewhere they come back as warnings so
+// local setups keep working out of the box.
+func (c *Config) Validate() (warnings []string, err error) {
+	var problems []string
+	production := c.Environment == "production"
+	report := func(msg string) {
+		if production {
+			problems = append(problems, msg)
+		} else {
+			warnings = append(warnings, msg)
+		}
+	}
+
+	switch {
+	case c.JWTSecret == defaultJWTSecret:
+		report("JWT_SECRET is using the built-in development default")
+	case len(c.JWTSecret) < minJWTSecretLength:
+		report(fmt.Sprintf("JWT_SECRET should be at least %d characters", minJWTSecretLength))
+	}
+	if c.DatabaseURL == defaultDatabaseURL {
+		report("DATABASE_URL is not set")
+	}
+	if c.FastAPIKey == defaultFastAPIKey {
+		report("FASTAPI_INTERNAL_KEY is using the built-in development default")
+	}
+	if c.S3BucketName == defaultS3BucketName {
+		report("S3_BUCKET_NAME is not set")
+	}
+	if c.SMTPUsername == "" || c.SMTPPassword == "" {
+		report("SMTP_USERNAME and SMTP_PASSWORD are required to send email")
+	}
+	if c.SMSProvider == "twilio" && (c.TwilioAccountSID == "" || c.TwilioAuthToken == "" || c.TwilioFromNumber == "") {
+		report("SMS_PROVIDER=twilio requires TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN and TWILIO_FROM_NUMBER")
+	}
+	if len(c.PIIEncryptionKeys) == 0 || c.PIIBlindIndexKey == "" {
+		report("PII_ENCRYPTION_KEYS and PII_BLIND_INDEX_KEY are not set, so phone numbers are stored in plaintext")
+	}
+	if production && c.DebugCaptureEnabled {
+		problems = append(problems, "DEBUG_CAPTURE_ENABLED must not be set in production")
+	}
+	if production && c.ChaosEnabled {
+		problems = append(problems, "CHAOS_ENABLED must not be set in production")
+	}
+	if production && c.SMSProvider == "log" && len(c.OTPRequiredFor) > 0 {
+		problems = append(problems, "SMS_PROVIDER=log cannot deliver OTP codes in production")
+	}
+
+	// Misconfigured in every environment
+	if c.InitialAdminEmail != "" && len(c.InitialAdminPassword) < 8 {
+		problems = append(problems, "INITIAL_ADMIN_EMAIL requires INITIAL_ADMIN_PASSWORD of at least 8 characters")
+	}
+	if c.UploadPartSizeMB < 5 {
+		problems = append(problems, "UPLOAD_PART_SIZE_MB must be at least 5, the S3 minimum part size")
+	}
+	if c.AuthCookieMode != "off" && strings.EqualFold(c.AuthCookieSameSite, "none") && !c.AuthCookieSecure {
+		problems = append(problems, "AUTH_COOKIE_SAMESITE=none requires AUTH_COOKIE_SECURE=true")
+	}
+	if c.OutOfStockDisplay != "show" && c.OutOfStockDisplay != "hide" {
+		problems = append(problems, "OUT_OF_STOCK_DISPLAY must be show or hide")
+	}
+	if c.FraudScreening != "rules" && c.FraudScreening != "off" {
+		problems = append(problems, "FRAUD_SCREENING must be rules or off")
+	}
+	if c.GeoIPProvider != "maxmind" && c.GeoIPProvider != "off" {
+		problems = append(problems, "GEOIP_PROVIDER must be maxmind or off")
+	}
+	for _, country := range c.GeoIPBlockedCountries {
+		if len(country) != 2 {
+			problems = append(problems, fmt.Sprintf("GEOIP_BLOCKED_COUNTRIES entry %q is not a two-letter country code", country))
+		}
+	}
+	if c.GeoIPCountryHeader != "" && len(c.TrustedProxies) == 0 {
+		warnings = append(warnings, "GEOIP_COUNTRY_HEADER is ignored until TRUSTED_PROXIES lists the proxies that set it")
+	}
+	for _, proxy := range c.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			problems = append(problems, fmt.Sprintf("TRUSTED_PROXIES entry %q is not an IP address or CIDR", proxy))
+		}
+	}
+
+	if len(problems) > 0 {
+		return warnings, errors.New("invalid configuration: " + strings.Join(problems, "; "))
+	}
+	return warnings, nil
+}
+
+// Summary renders the configuration for boot logs with secrets redacted
+func (c *Config) Summary() string {
+	v := reflect.ValueOf(*c)
+	t := v.Type()
+
+	parts := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Name
+		value := fmt.Sprint(v.Field(i).Interface())
+		switch {
+		case name == "DatabaseURL" || name == "NATSURL":
+			value = redactURL(value)
+		case name == "DatabaseReplicaURLs":
+			redacted := make([]string, 0, len(c.DatabaseReplicaURLs))
+			for _, u := range c.DatabaseReplicaURLs {
+				redacted = append(redacted, redactURL(u))
+			}
+			value = fmt.Sprint(redacted)
+		case secretFields[name]:
+			value = redact(value)
+		}
+		parts = append(parts, name+"="+value)
+	}
+	return strings.Join(parts, " ")
+}
+
+// secretFields are never printed, only whether they are set
+var secretFields = map[string]bool{
+	"JWTSecret":                 true,
+	"FastAPIKey":                true,
+	"SMTPPassword":              true,
+	"AbstractEmailAPIKey":       true,
+	"AbstractPhoneNumberAPIKey": true,
+	"S3AccessKey":               true,
+	"S3SecretKey":               true,
+	"TwilioAccountSID":          true,
+	"TwilioAuthToken":           true,
+	"MetricsToken":              true,
+	"InitialAdminPassword":      true,
+	"PIIEncryptionKeys":         true,
+	"PIIBlindIndexKey":          true,
+	"MaxMindLicenseKey":         true,
+}
+
+func redact(value string) string {
+	if value == "" {
+		return `""`
+	}
+	return "[redacted]"
+}
+
+// redactURL keeps the host and database visible but hides credentials,
+// including a bare token in the user part as NATS URLs allow
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "[redacted]"
+	}
+	if u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), "redacted")
+		} else {
+			u.User = url.User("redacted")
+		}
+	}
+	return u.String()
+}
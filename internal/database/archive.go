@@ -0,0 +1,56 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// ArchivedTable is a high-volume table whose old rows are moved to an
+// archive table with the same columns. View unions both for reads that must
+// cover the whole history, such as admin reports.
+type ArchivedTable struct {
+	Table   string
+	Archive string
+	View    string
+	model   interface{}
+}
+
+// ArchivedTables lists the tables the archive job moves rows out of. Rows
+// are archived by created_at.
+var ArchivedTables = []ArchivedTable{
+	{Table: "audit_logs", Archive: "audit_logs_archive", View: "audit_logs_all", model: &models.AuditLog{}},
+	{Table: "stock_movements", Archive: "stock_movements_archive", View: "stock_movements_all", model: &models.StockMovement{}},
+}
+
+// Columns returns the table's column names in model order
+func (t ArchivedTable) Columns(db *gorm.DB) ([]string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(t.model); err != nil {
+		return nil, err
+	}
+	return stmt.Schema.DBNames, nil
+}
+
+// migrateArchiveViews recreates the views unioning each table with its
+// archive, so they pick up columns added to the model
+func migrateArchiveViews(db *gorm.DB) error {
+	for _, t := range ArchivedTables {
+		columns, err := t.Columns(db)
+		if err != nil {
+			return err
+		}
+		list := strings.Join(columns, ", ")
+
+		if err := db.Exec("DROP VIEW IF EXISTS " + t.View).Error; err != nil {
+			return err
+		}
+		if err := db.Exec(fmt.Sprintf("CREATE VIEW %s AS SELECT %s FROM %s UNION ALL SELECT %s FROM %s",
+			t.View, list, t.Table, list, t.Archive)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -17,6 +17,10 @@ func Init(databaseURL string) (*gorm.DB, error) {
 
 	// Auto migrate schemas
 	err = db.AutoMigrate(
+		// Referenced by users and products, so migrated first
+		&models.Store{},
+		&models.Vendor{},
+		&models.CustomerGroup{},
 		&models.User{},
 		&models.Product{},
 		&models.Review{},
@@ -26,10 +30,155 @@ func Init(databaseURL string) (*gorm.DB, error) {
 		&models.Image{},
 		&models.Service{},
 		&models.ProductReaction{},
+		&models.Announcement{},
+		&models.MetricSnapshot{},
+		&models.ExternalAPIUsage{},
+		&models.Webhook{},
+		&models.WebhookDelivery{},
+		&models.OutboxEvent{},
+		&models.Job{},
+		&models.JobLog{},
+		&models.ReviewModeration{},
+		&models.ReviewEdit{},
+		&models.StockMovement{},
+		&models.ProductImport{},
+		&models.ImportSource{},
+		&models.ImportSourceRun{},
+		&models.ProductDraft{},
+		&models.OTPCode{},
+		&models.StorageReport{},
+		&models.FeatureFlag{},
+		&models.ProductBundle{},
+		&models.ProductBundleItem{},
+		&models.SavedSearch{},
+		&models.SavedSearchMatch{},
+		&models.UserPreference{},
+		&models.Collection{},
+		&models.CollectionItem{},
+		&models.Banner{},
+		&models.ProductReport{},
+		&models.AdminNote{},
+		&models.PendingDeletion{},
+		&models.AuditLog{},
+		&models.Invitation{},
+		&models.PasswordHistory{},
+		&models.TokenCleanupRun{},
+		&models.ImageObject{},
+		&models.UploadSession{},
+		&models.ProductRevision{},
+		&models.ScheduledPriceChange{},
+		&models.PriceTier{},
+		&models.CustomerGroupPrice{},
+		&models.Quote{},
+		&models.QuoteItem{},
+		&models.Cart{},
+		&models.CartItem{},
+		&models.CartShare{},
+		&models.CartShareItem{},
+		&models.StoreLocation{},
+		&models.LocationStock{},
+		&models.ShippingRate{},
+		&models.Report{},
+		&models.DebugCaptureRule{},
+		&models.DebugCapture{},
+		&models.ChaosRule{},
+		&models.AuditLogArchive{},
+		&models.StockMovementArchive{},
+		&models.ReferralCode{},
+		&models.Referral{},
+		&models.FraudReview{},
+		&models.CatalogConnector{},
+		&models.CatalogSyncItem{},
+		&models.CatalogSyncRun{},
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := migrateReviewVisibility(db); err != nil {
+		return nil, err
+	}
+	if err := migrateRefreshTokenHashes(db); err != nil {
+		return nil, err
+	}
+	if err := migrateSharedImageKeys(db); err != nil {
+		return nil, err
+	}
+	if err := migrateStoreScopedEmails(db); err != nil {
+		return nil, err
+	}
+	if err := migrateOTPPhoneIndex(db); err != nil {
+		return nil, err
+	}
+	if err := migrateArchiveViews(db); err != nil {
+		return nil, err
+	}
+	if err := migrateProductFilterIndexes(db); err != nil {
+		return nil, err
+	}
+
 	return db, nil
-}
\ No newline at end of file
+}
+
+// migrateReviewVisibility carries the old is_active flag over to the
+// visibility column, then drops it.
+func migrateReviewVisibility(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&models.Review{}, "is_active") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("UPDATE reviews SET visibility = ? WHERE is_active = ?", models.ReviewVisibilityRemoved, false).Error; err != nil {
+			return err
+		}
+		return tx.Migrator().DropColumn(&models.Review{}, "is_active")
+	})
+}
+
+// migrateRefreshTokenHashes replaces plaintext refresh tokens with their
+// SHA-256 hashes, giving each existing token its own family, then drops the
+// plaintext column.
+func migrateRefreshTokenHashes(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&models.RefreshToken{}, "token") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`UPDATE refresh_tokens
+			SET token_hash = encode(sha256(token::bytea), 'hex'), family_id = gen_random_uuid()::text
+			WHERE token_hash IS NULL OR token_hash = ''`).Error; err != nil {
+			return err
+		}
+		return tx.Migrator().DropColumn(&models.RefreshToken{}, "token")
+	})
+}
+
+// migrateOTPPhoneIndex drops the index on otp_codes.phone. The column is
+// encrypted now and codes are looked up by phone_hash instead.
+func migrateOTPPhoneIndex(db *gorm.DB) error {
+	return db.Exec("DROP INDEX IF EXISTS idx_otp_phone_purpose").Error
+}
+
+// migrateStoreScopedEmails makes emails unique per store instead of across
+// the deployment, so the same person can have an account in each store.
+// Users without a store share one scope. Older schemas named the global
+// constraint after the column.
+func migrateStoreScopedEmails(db *gorm.DB) error {
+	for _, name := range []string{"uni_users_email", "users_email_key"} {
+		if err := db.Exec("ALTER TABLE users DROP CONSTRAINT IF EXISTS " + name).Error; err != nil {
+			return err
+		}
+	}
+	return db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_users_store_email ON users (COALESCE(store_id, 0), email)").Error
+}
+
+// migrateSharedImageKeys drops the unique constraint on images.s3_key, which
+// deduplicated images share. Older schemas named it after the column.
+func migrateSharedImageKeys(db *gorm.DB) error {
+	for _, name := range []string{"uni_images_s3_key", "images_s3_key_key"} {
+		if err := db.Exec("ALTER TABLE images DROP CONSTRAINT IF EXISTS " + name).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
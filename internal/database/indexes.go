@@ -0,0 +1,48 @@
+package database
+
+import (
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// productFilterIndexes support the public product listing: status is always
+// filtered on, the sort picks the ordering, and price ranges scan the price
+// index. They are built concurrently so startup does not block writes to a
+// large products table.
+var productFilterIndexes = []string{
+	"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_products_status_created ON products (status, created_at DESC)",
+	"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_products_status_price ON products (status, price, created_at DESC)",
+	"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_products_status_rating ON products (status, rating_score DESC, rating_count DESC, created_at DESC)",
+	"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_products_store_status_created ON products (store_id, status, created_at DESC) WHERE store_id IS NOT NULL",
+}
+
+// productTrigramIndexes serve the LOWER(column) LIKE '%term%' filters, which
+// a btree index cannot. They need the pg_trgm extension.
+var productTrigramIndexes = []string{
+	"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_products_category_trgm ON products USING gin (LOWER(category) gin_trgm_ops)",
+	"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_products_material_trgm ON products USING gin (LOWER(material) gin_trgm_ops)",
+	"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_products_title_trgm ON products USING gin (LOWER(title) gin_trgm_ops)",
+	"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_products_description_trgm ON products USING gin (LOWER(description) gin_trgm_ops)",
+}
+
+// migrateProductFilterIndexes creates the indexes behind product filtering
+// and sorting. Without the rights to create pg_trgm the substring filters
+// keep working on sequential scans, so that is logged rather than fatal.
+func migrateProductFilterIndexes(db *gorm.DB) error {
+	for _, statement := range productFilterIndexes {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+		logger.Warn("pg_trgm is unavailable, product search runs without trigram indexes: ", err)
+		return nil
+	}
+	for _, statement := range productTrigramIndexes {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
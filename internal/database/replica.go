@@ -0,0 +1,35 @@
+package database
+
+import (
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// replicaResolver names the resolver that Replica selects
+const replicaResolver = "replicas"
+
+// UseReplicas connects to the read replicas. Queries only go to them when
+// they are built with Replica; everything else, including writes and reads
+// that must see a write just made, stays on the primary.
+func UseReplicas(db *gorm.DB, replicaURLs []string) error {
+	if len(replicaURLs) == 0 {
+		return nil
+	}
+
+	replicas := make([]gorm.Dialector, 0, len(replicaURLs))
+	for _, url := range replicaURLs {
+		replicas = append(replicas, postgres.Open(url))
+	}
+	return db.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   dbresolver.RandomPolicy{},
+	}, replicaResolver))
+}
+
+// Replica routes the read-only queries of db to a random replica. Use it for
+// catalog reads that tolerate a little replication lag; without replicas, or
+// inside a transaction, queries run on the primary as usual.
+func Replica(db *gorm.DB) *gorm.DB {
+	return db.Clauses(dbresolver.Use(replicaResolver))
+}
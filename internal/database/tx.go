@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// ContextWithTx returns a copy of ctx carrying tx. Statements built with
+// Conn from ctx, or a context derived from it, then execute inside tx.
+func ContextWithTx(ctx context.Context, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// TxFromContext returns the transaction carried by ctx, or nil
+func TxFromContext(ctx context.Context) *gorm.DB {
+	tx, _ := ctx.Value(txKey{}).(*gorm.DB)
+	return tx
+}
+
+// Conn returns db bound to ctx, running inside the transaction ctx carries
+// if there is one. Services that can take part in a request transaction use
+// it in place of db.WithContext(ctx); their own Transaction blocks then nest
+// as savepoints, so a failed block undoes only its own writes.
+func Conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+// Records an admin note can be attached to
+const (
+	NoteSubjectProduct = "product"
+	NoteSubjectUser    = "user"
+)
+
+// AdminNote is internal context left by support or catalogue staff on a
+// product or user. Notes are only served from admin endpoints and never
+// appear in public responses.
+type AdminNote struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	SubjectType string    `json:"subject_type" gorm:"not null;index:idx_admin_notes_subject"`
+	SubjectID   uint      `json:"subject_id" gorm:"not null;index:idx_admin_notes_subject"`
+	AuthorID    uint      `json:"author_id" gorm:"not null"`
+	Body        string    `json:"body" gorm:"type:text;not null"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	Author *User `json:"author,omitempty" gorm:"foreignKey:AuthorID"`
+}
+
+type AdminNoteRequest struct {
+	Body string `json:"body" binding:"required,max=5000"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	AnnouncementSeverityInfo     = "info"
+	AnnouncementSeverityWarning  = "warning"
+	AnnouncementSeverityCritical = "critical"
+
+	AnnouncementAudienceAll       = "all"
+	AnnouncementAudienceCustomers = "customers"
+	AnnouncementAudienceAdmins    = "admins"
+)
+
+// Announcement is a banner message (maintenance window, promotion, ...) that
+// API clients poll and display to the matching audience while it is scheduled.
+// Announcements without a store, such as maintenance windows created in
+// single-tenant mode, are shown in every store.
+type Announcement struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	StoreID   *uint      `json:"store_id,omitempty" gorm:"index"`
+	Title     string     `json:"title" gorm:"not null"`
+	Message   string     `json:"message"`
+	Severity  string     `json:"severity" gorm:"default:'info'"`
+	Audience  string     `json:"audience" gorm:"default:'all';index"`
+	StartsAt  *time.Time `json:"starts_at,omitempty"`
+	EndsAt    *time.Time `json:"ends_at,omitempty"`
+	IsActive  bool       `json:"is_active" gorm:"default:true"`
+	CreatedBy uint       `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type CreateAnnouncementRequest struct {
+	Title    string     `json:"title" binding:"required"`
+	Message  string     `json:"message"`
+	Severity string     `json:"severity" binding:"omitempty,oneof=info warning critical"`
+	Audience string     `json:"audience" binding:"omitempty,oneof=all customers admins"`
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+	IsActive *bool      `json:"is_active,omitempty"`
+}
+
+type UpdateAnnouncementRequest struct {
+	Title    *string    `json:"title,omitempty"`
+	Message  *string    `json:"message,omitempty"`
+	Severity *string    `json:"severity,omitempty" binding:"omitempty,oneof=info warning critical"`
+	Audience *string    `json:"audience,omitempty" binding:"omitempty,oneof=all customers admins"`
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+	IsActive *bool      `json:"is_active,omitempty"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// ExternalAPIUsage aggregates calls to a metered third-party API per day.
+type ExternalAPIUsage struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Service   string    `json:"service" gorm:"not null;uniqueIndex:idx_api_usage_service_date"`
+	Date      time.Time `json:"date" gorm:"type:date;not null;uniqueIndex:idx_api_usage_service_date"`
+	Calls     int64     `json:"calls" gorm:"default:0"`
+	Failures  int64     `json:"failures" gorm:"default:0"`
+	Cost      float64   `json:"cost" gorm:"default:0"`
+	LatencyMs int64     `json:"latency_ms" gorm:"default:0"` // summed over all calls
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,18 @@
+package models
+
+// AuditLogArchive holds audit log entries moved out of audit_logs once they
+// are older than ARCHIVE_AFTER_MONTHS. The audit_logs_all view reads both.
+type AuditLogArchive AuditLog
+
+func (AuditLogArchive) TableName() string {
+	return "audit_logs_archive"
+}
+
+// StockMovementArchive holds stock movements moved out of stock_movements
+// once they are older than ARCHIVE_AFTER_MONTHS. The stock_movements_all
+// view reads both.
+type StockMovementArchive StockMovement
+
+func (StockMovementArchive) TableName() string {
+	return "stock_movements_archive"
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/pkg/geo"
+	"gorm.io/gorm"
+)
+
+// Audit actions
+const (
+	AuditActionImpersonationStarted = "impersonation.started"
+	AuditActionImpersonatedRequest  = "impersonation.request"
+	AuditActionUserRoleChanged      = "user.role_changed"
+	AuditActionInvitationAccepted   = "invitation.accepted"
+	AuditActionUserRestored         = "user.restored"
+	AuditActionUserMerged           = "user.merged"
+	AuditActionUserBlocked          = "user.blocked"
+)
+
+// AuditLog records a privileged action. ActorID is the staff member
+// responsible; SubjectUserID is the customer acted on or as, if any.
+type AuditLog struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	ActorID       uint      `json:"actor_id" gorm:"not null;index"`
+	SubjectUserID *uint     `json:"subject_user_id,omitempty" gorm:"index"`
+	Action        string    `json:"action" gorm:"not null;index"`
+	Method        string    `json:"method,omitempty"`
+	Path          string    `json:"path,omitempty"`
+	StatusCode    int       `json:"status_code,omitempty"`
+	IPAddress     string    `json:"ip_address,omitempty"`
+	UserAgent     string    `json:"user_agent,omitempty"`
+	Details       string    `json:"details,omitempty"`
+	Country       string    `json:"country,omitempty"` // of IPAddress, when the request was located
+	Region        string    `json:"region,omitempty"`
+	CreatedAt     time.Time `json:"created_at" gorm:"index"`
+}
+
+// BeforeCreate records where the request behind the entry came from
+func (a *AuditLog) BeforeCreate(tx *gorm.DB) error {
+	if loc := geo.FromContext(tx.Statement.Context); loc != nil && a.Country == "" {
+		a.Country, a.Region = loc.Country, loc.Region
+	}
+	return nil
+}
+
+// ImpersonateRequest optionally explains why support is acting as a customer
+type ImpersonateRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
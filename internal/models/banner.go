@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+)
+
+// Storefront spots a banner can be shown in
+const (
+	BannerPlacementHomeHero       = "home_hero"
+	BannerPlacementHomeStrip      = "home_strip"
+	BannerPlacementCategoryTop    = "category_top"
+	BannerPlacementProductSidebar = "product_sidebar"
+)
+
+// Banner is a promotional image shown in a storefront placement while it is
+// active and inside its schedule window. Banners in the same placement are
+// shown in SortOrder.
+type Banner struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	StoreID   *uint      `json:"store_id,omitempty" gorm:"index"`
+	Title     string     `json:"title" gorm:"not null"`
+	ImageURL  string     `json:"image_url"`
+	ImageKey  string     `json:"-"` // S3 key of the uploaded image
+	LinkURL   string     `json:"link_url,omitempty"`
+	Placement string     `json:"placement" gorm:"not null;index"`
+	StartsAt  *time.Time `json:"starts_at,omitempty"`
+	EndsAt    *time.Time `json:"ends_at,omitempty"`
+	SortOrder int        `json:"sort_order" gorm:"default:0"`
+	IsActive  bool       `json:"is_active" gorm:"default:true"`
+	CreatedBy uint       `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	Store *Store `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+type CreateBannerRequest struct {
+	Title     string     `json:"title" binding:"required,max=200"`
+	LinkURL   string     `json:"link_url" binding:"omitempty,url"`
+	Placement string     `json:"placement" binding:"required,oneof=home_hero home_strip category_top product_sidebar"`
+	StartsAt  *time.Time `json:"starts_at,omitempty"`
+	EndsAt    *time.Time `json:"ends_at,omitempty"`
+	SortOrder int        `json:"sort_order"`
+	IsActive  *bool      `json:"is_active,omitempty"`
+}
+
+type UpdateBannerRequest struct {
+	Title     *string    `json:"title,omitempty" binding:"omitempty,max=200"`
+	LinkURL   *string    `json:"link_url,omitempty" binding:"omitempty,url|len=0"`
+	Placement *string    `json:"placement,omitempty" binding:"omitempty,oneof=home_hero home_strip category_top product_sidebar"`
+	StartsAt  *time.Time `json:"starts_at,omitempty"`
+	EndsAt    *time.Time `json:"ends_at,omitempty"`
+	SortOrder *int       `json:"sort_order,omitempty"`
+	IsActive  *bool      `json:"is_active,omitempty"`
+}
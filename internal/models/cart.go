@@ -0,0 +1,98 @@
+package models
+
+import (
+	"time"
+)
+
+// Cart holds the products a shopper intends to buy. A signed-in user has one
+// cart, found by UserID. A visitor's cart is anonymous: it is found by the
+// cart token handed out when it was created, of which only a SHA-256 hash is
+// stored, and it expires unless it is used. Logging in or signing up merges
+// the anonymous cart into the user's.
+type Cart struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    *uint      `json:"user_id,omitempty" gorm:"uniqueIndex"`
+	TokenHash *string    `json:"-" gorm:"uniqueIndex"`
+	StoreID   *uint      `json:"store_id,omitempty" gorm:"index"`   // nil in single-tenant mode
+	ExpiresAt *time.Time `json:"expires_at,omitempty" gorm:"index"` // anonymous carts only
+	Items     []CartItem `json:"items" gorm:"foreignKey:CartID;constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	User *User `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// CartItem is one product line of a cart
+type CartItem struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CartID    uint      `json:"cart_id" gorm:"not null;uniqueIndex:idx_cart_items_cart_product"`
+	ProductID uint      `json:"product_id" gorm:"not null;uniqueIndex:idx_cart_items_cart_product;index"`
+	Quantity  int       `json:"quantity" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Product *Product `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+type CartItemRequest struct {
+	ProductID uint `json:"product_id" binding:"required"`
+	Quantity  int  `json:"quantity" binding:"required,gt=0"`
+}
+
+type UpdateCartItemRequest struct {
+	Quantity int `json:"quantity" binding:"required,gt=0"`
+}
+
+// CartView is a cart priced for the caller. Lines that can't be bought as
+// they stand, e.g. because the product was withdrawn or is out of stock, are
+// listed with an issue and left out of the subtotal.
+type CartView struct {
+	ID        uint       `json:"id,omitempty"`
+	Items     []CartLine `json:"items"`
+	Subtotal  float64    `json:"subtotal"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// Token is set only on the response that created an anonymous cart;
+	// the client sends it back to reach the cart
+	Token string `json:"cart_token,omitempty"`
+}
+
+type CartLine struct {
+	ProductID uint    `json:"product_id"`
+	Title     string  `json:"title"`
+	SKU       string  `json:"sku,omitempty"`
+	Quantity  int     `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"`
+	Total     float64 `json:"total"`
+	Available bool    `json:"available"`
+	Issue     string  `json:"issue,omitempty"`
+}
+
+// CartShare is a snapshot of a cart's contents that anyone holding its token
+// can view and import into their own cart until it expires. Only a SHA-256
+// hash of the token is stored.
+type CartShare struct {
+	ID        uint            `json:"id" gorm:"primaryKey"`
+	TokenHash string          `json:"-" gorm:"uniqueIndex"`
+	SharedBy  *uint           `json:"shared_by,omitempty"` // nil when shared from an anonymous cart
+	StoreID   *uint           `json:"store_id,omitempty" gorm:"index"`
+	ExpiresAt time.Time       `json:"expires_at" gorm:"not null;index"`
+	Items     []CartShareItem `json:"items" gorm:"foreignKey:ShareID;constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time       `json:"created_at"`
+
+	Sharer *User `json:"-" gorm:"foreignKey:SharedBy;constraint:OnDelete:SET NULL"`
+}
+
+type CartShareItem struct {
+	ID        uint `json:"id" gorm:"primaryKey"`
+	ShareID   uint `json:"share_id" gorm:"not null;index"`
+	ProductID uint `json:"product_id" gorm:"not null"`
+	Quantity  int  `json:"quantity" gorm:"not null"`
+
+	Product *Product `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// CartShareLink is returned once when a cart is shared
+type CartShareLink struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	CatalogPlatformShopify     = "shopify"
+	CatalogPlatformWooCommerce = "woocommerce"
+
+	CatalogSyncStatusRunning   = "running"
+	CatalogSyncStatusSucceeded = "succeeded"
+	CatalogSyncStatusFailed    = "failed"
+)
+
+// CatalogSyncFields are the product fields a connector mapping can send to
+// custom remote product fields. Price, stock, SKU and images are always
+// synced.
+var CatalogSyncFields = []string{"title", "description", "category", "material", "size"}
+
+// CatalogConnector pushes the catalog to an external Shopify or WooCommerce
+// store. Each run sends the products updated since the last one; a full run
+// sends everything.
+type CatalogConnector struct {
+	ID          uint              `json:"id" gorm:"primaryKey"`
+	Name        string            `json:"name" gorm:"not null"`
+	Platform    string            `json:"platform" gorm:"not null"`
+	StoreURL    string            `json:"store_url" gorm:"not null"`                // e.g. https://shop.myshopify.com
+	AccessToken EncryptedString   `json:"-"`                                        // Shopify admin token or WooCommerce consumer key
+	APISecret   EncryptedString   `json:"-"`                                        // WooCommerce consumer secret
+	LocationID  string            `json:"location_id,omitempty"`                    // Shopify location whose inventory is set
+	Mapping     map[string]string `json:"mapping" gorm:"type:text;serializer:json"` // product field -> remote field, "" to skip
+	Schedule    string            `json:"schedule,omitempty"`                       // 5-field cron expression; empty syncs on demand only
+	IsActive    bool              `json:"is_active" gorm:"default:true;index"`
+
+	// Products updated after this point, in (updated_at, id) order, have not
+	// been pushed yet
+	SyncedThrough   *time.Time `json:"synced_through,omitempty"`
+	SyncedThroughID uint       `json:"-"`
+
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	NextRunAt *time.Time `json:"next_run_at,omitempty" gorm:"index"`
+	CreatedBy uint       `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// CatalogSyncItem links a product to its copy in a connector's store
+type CatalogSyncItem struct {
+	ID                uint      `json:"id" gorm:"primaryKey"`
+	ConnectorID       uint      `json:"connector_id" gorm:"not null;uniqueIndex:idx_catalog_sync_items_product"`
+	ProductID         uint      `json:"product_id" gorm:"not null;uniqueIndex:idx_catalog_sync_items_product"`
+	ExternalID        string    `json:"external_id"`
+	ExternalVariantID string    `json:"external_variant_id,omitempty"`
+	ImagesHash        string    `json:"-" gorm:"column:images_hash"` // images last sent, so unchanged ones are not re-uploaded
+	SyncedAt          time.Time `json:"synced_at"`
+}
+
+// CatalogSyncRun is one push of a connector's catalog
+type CatalogSyncRun struct {
+	ID          uint               `json:"id" gorm:"primaryKey"`
+	ConnectorID uint               `json:"connector_id" gorm:"not null;index"`
+	Status      string             `json:"status" gorm:"index"`
+	Trigger     string             `json:"trigger"` // schedule or manual
+	Full        bool               `json:"full"`
+	Total       int                `json:"total"`
+	Created     int                `json:"created"`
+	Updated     int                `json:"updated"`
+	Removed     int                `json:"removed"`
+	Failed      int                `json:"failed"`
+	Errors      []CatalogSyncError `json:"errors,omitempty" gorm:"type:text;serializer:json"`
+	Error       string             `json:"error,omitempty"`
+	StartedAt   time.Time          `json:"started_at"`
+	FinishedAt  *time.Time         `json:"finished_at,omitempty"`
+}
+
+// CatalogSyncError is a product the remote store rejected
+type CatalogSyncError struct {
+	ProductID uint   `json:"product_id"`
+	SKU       string `json:"sku,omitempty"`
+	Message   string `json:"message"`
+}
+
+type CreateCatalogConnectorRequest struct {
+	Name        string            `json:"name" binding:"required"`
+	Platform    string            `json:"platform" binding:"required,oneof=shopify woocommerce"`
+	StoreURL    string            `json:"store_url" binding:"required,url"`
+	AccessToken string            `json:"access_token" binding:"required"`
+	APISecret   string            `json:"api_secret"`
+	LocationID  string            `json:"location_id"`
+	Mapping     map[string]string `json:"mapping"`
+	Schedule    string            `json:"schedule"`
+	IsActive    *bool             `json:"is_active"`
+}
+
+type UpdateCatalogConnectorRequest struct {
+	Name        *string           `json:"name,omitempty"`
+	StoreURL    *string           `json:"store_url,omitempty" binding:"omitempty,url"`
+	AccessToken *string           `json:"access_token,omitempty"`
+	APISecret   *string           `json:"api_secret,omitempty"`
+	LocationID  *string           `json:"location_id,omitempty"`
+	Mapping     map[string]string `json:"mapping,omitempty"`
+	Schedule    *string           `json:"schedule,omitempty"`
+	IsActive    *bool             `json:"is_active,omitempty"`
+}
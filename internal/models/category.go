@@ -0,0 +1,14 @@
+package models
+
+// Categories are free text on products; these requests change them across
+// the catalogue
+
+type RenameCategoryRequest struct {
+	From string `json:"from" binding:"required,max=100"`
+	To   string `json:"to" binding:"required,max=100"`
+}
+
+type MergeCategoriesRequest struct {
+	Source string `json:"source" binding:"required,max=100"` // category that disappears
+	Target string `json:"target" binding:"required,max=100"` // category its products move to
+}
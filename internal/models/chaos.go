@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+)
+
+// ChaosRule injects failures into requests on a route so clients can be
+// tested against slow and failing responses. Route is the registered
+// pattern, e.g. /api/v1/products/:product_id, or a prefix ending in *; an
+// empty Method matches every method. Percentages are per request.
+type ChaosRule struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	Method       string     `json:"method,omitempty"`
+	Route        string     `json:"route" gorm:"not null"`
+	LatencyMs    int        `json:"latency_ms"`
+	JitterMs     int        `json:"jitter_ms"` // random extra latency up to this much
+	ErrorPercent int        `json:"error_percent"`
+	ErrorStatus  int        `json:"error_status" gorm:"default:503"`
+	DropPercent  int        `json:"drop_percent"` // connections closed without a response
+	Enabled      bool       `json:"enabled" gorm:"default:true"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	CreatedBy    uint       `json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+type CreateChaosRuleRequest struct {
+	Method       string     `json:"method" binding:"omitempty,oneof=GET POST PUT PATCH DELETE"`
+	Route        string     `json:"route" binding:"required,startswith=/"`
+	LatencyMs    int        `json:"latency_ms" binding:"min=0,max=60000"`
+	JitterMs     int        `json:"jitter_ms" binding:"min=0,max=60000"`
+	ErrorPercent int        `json:"error_percent" binding:"min=0,max=100"`
+	ErrorStatus  int        `json:"error_status" binding:"omitempty,min=400,max=599"`
+	DropPercent  int        `json:"drop_percent" binding:"min=0,max=100"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+}
+
+type UpdateChaosRuleRequest struct {
+	LatencyMs    *int       `json:"latency_ms,omitempty" binding:"omitempty,min=0,max=60000"`
+	JitterMs     *int       `json:"jitter_ms,omitempty" binding:"omitempty,min=0,max=60000"`
+	ErrorPercent *int       `json:"error_percent,omitempty" binding:"omitempty,min=0,max=100"`
+	ErrorStatus  *int       `json:"error_status,omitempty" binding:"omitempty,min=400,max=599"`
+	DropPercent  *int       `json:"drop_percent,omitempty" binding:"omitempty,min=0,max=100"`
+	Enabled      *bool      `json:"enabled,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+}
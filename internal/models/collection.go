@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+)
+
+// Collection is a curated, ordered list of products shown as a storefront
+// section such as "New Arrivals" or "Summer Sale".
+type Collection struct {
+	ID             uint             `json:"id" gorm:"primaryKey"`
+	StoreID        *uint            `json:"store_id,omitempty" gorm:"index"`
+	Title          string           `json:"title" gorm:"not null"`
+	Slug           string           `json:"slug" gorm:"not null;index"` // unique within a store
+	Description    string           `json:"description,omitempty"`
+	BannerImageURL string           `json:"banner_image_url,omitempty"`
+	IsActive       bool             `json:"is_active" gorm:"default:true;index"`
+	SortOrder      int              `json:"sort_order" gorm:"default:0"`
+	Items          []CollectionItem `json:"-" gorm:"foreignKey:CollectionID;constraint:OnDelete:CASCADE"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+
+	// ProductIDs lists every product in order, for admins; Products holds
+	// the active ones, for the storefront
+	ProductIDs []uint    `json:"product_ids,omitempty" gorm:"-"`
+	Products   []Product `json:"products,omitempty" gorm:"-"`
+
+	Store *Store `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+type CollectionItem struct {
+	ID           uint `json:"id" gorm:"primaryKey"`
+	CollectionID uint `json:"collection_id" gorm:"not null;uniqueIndex:idx_collection_product"`
+	ProductID    uint `json:"product_id" gorm:"not null;uniqueIndex:idx_collection_product;index"`
+	Position     int  `json:"position" gorm:"not null"`
+
+	Product Product `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+type CreateCollectionRequest struct {
+	Title          string `json:"title" binding:"required,max=200"`
+	Slug           string `json:"slug" binding:"max=100"` // derived from the title when empty
+	Description    string `json:"description"`
+	BannerImageURL string `json:"banner_image_url" binding:"omitempty,url"`
+	IsActive       *bool  `json:"is_active"`
+	SortOrder      int    `json:"sort_order"`
+	ProductIDs     []uint `json:"product_ids"`
+}
+
+// UpdateCollectionRequest changes only the fields that are set. ProductIDs
+// replaces the whole list, in the given order.
+type UpdateCollectionRequest struct {
+	Title          *string `json:"title" binding:"omitempty,max=200"`
+	Slug           *string `json:"slug" binding:"omitempty,max=100"`
+	Description    *string `json:"description"`
+	BannerImageURL *string `json:"banner_image_url" binding:"omitempty,url|len=0"`
+	IsActive       *bool   `json:"is_active"`
+	SortOrder      *int    `json:"sort_order"`
+	ProductIDs     *[]uint `json:"product_ids"`
+}
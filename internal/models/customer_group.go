@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+)
+
+// CustomerGroup is a segment of customers, e.g. retail, wholesale or VIP,
+// whose members see the group's prices
+type CustomerGroup struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"not null;uniqueIndex"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	Prices []CustomerGroupPrice `json:"prices,omitempty" gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE"`
+}
+
+// CustomerGroupPrice is a group's price rule for one product or a whole
+// category. Product rules set a fixed Price or a DiscountPercent; category
+// rules only discount. A product rule wins over its category's rule.
+type CustomerGroupPrice struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	GroupID         uint      `json:"group_id" gorm:"not null;uniqueIndex:idx_group_prices_product,where:product_id IS NOT NULL;uniqueIndex:idx_group_prices_category,where:category <> ''"`
+	ProductID       *uint     `json:"product_id,omitempty" gorm:"uniqueIndex:idx_group_prices_product,where:product_id IS NOT NULL"`
+	Category        string    `json:"category,omitempty" gorm:"uniqueIndex:idx_group_prices_category,where:category <> ''"`
+	Price           *float64  `json:"price,omitempty"`
+	DiscountPercent *float64  `json:"discount_percent,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+
+	Product *Product `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+type CustomerGroupRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=500"`
+}
+
+type UpdateCustomerGroupRequest struct {
+	Name        *string `json:"name" binding:"omitempty,max=100"`
+	Description *string `json:"description" binding:"omitempty,max=500"`
+}
+
+// CustomerGroupPriceRequest targets either product_id or category, with
+// either price (products only) or discount_percent
+type CustomerGroupPriceRequest struct {
+	ProductID       *uint    `json:"product_id"`
+	Category        string   `json:"category"`
+	Price           *float64 `json:"price" binding:"omitempty,gt=0"`
+	DiscountPercent *float64 `json:"discount_percent" binding:"omitempty,gt=0,lt=100"`
+}
+
+// SetCustomerGroupRequest assigns a user to a group; a null group_id removes
+// them from theirs
+type SetCustomerGroupRequest struct {
+	GroupID *uint `json:"group_id"`
+}
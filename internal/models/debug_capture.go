@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+)
+
+// DebugCaptureRule selects a route whose requests and responses are recorded
+// until the rule expires. Route is the registered pattern, e.g.
+// /api/v1/products/:product_id; an empty Method matches every method.
+type DebugCaptureRule struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Method    string    `json:"method,omitempty"`
+	Route     string    `json:"route" gorm:"not null"`
+	UserID    *uint     `json:"user_id,omitempty"` // only record this user's requests
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
+	CreatedBy uint      `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DebugCapture is one recorded request/response pair. Credentials are
+// stripped from headers and sensitive JSON fields are redacted before the
+// row is written.
+type DebugCapture struct {
+	ID              uint              `json:"id" gorm:"primaryKey"`
+	RuleID          uint              `json:"rule_id" gorm:"index"`
+	Method          string            `json:"method"`
+	Route           string            `json:"route" gorm:"index"`
+	Path            string            `json:"path"`
+	Status          int               `json:"status"`
+	DurationMs      int64             `json:"duration_ms"`
+	UserID          *uint             `json:"user_id,omitempty"`
+	RequestID       string            `json:"request_id,omitempty"`
+	ClientIP        string            `json:"client_ip,omitempty"`
+	RequestHeaders  map[string]string `json:"request_headers,omitempty" gorm:"type:text;serializer:json"`
+	RequestBody     string            `json:"request_body,omitempty" gorm:"type:text"`
+	ResponseHeaders map[string]string `json:"response_headers,omitempty" gorm:"type:text;serializer:json"`
+	ResponseBody    string            `json:"response_body,omitempty" gorm:"type:text"`
+	Truncated       bool              `json:"truncated"`
+	CreatedAt       time.Time         `json:"created_at" gorm:"index"`
+}
+
+type CreateDebugCaptureRuleRequest struct {
+	Method   string `json:"method" binding:"omitempty,oneof=GET POST PUT PATCH DELETE"`
+	Route    string `json:"route" binding:"required,startswith=/"`
+	UserID   *uint  `json:"user_id,omitempty"`
+	Duration string `json:"duration"` // e.g. 30m; defaults to an hour, at most a day
+}
@@ -0,0 +1,180 @@
+package models
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Encrypted values are stored as "enc:v1:<key id>:<base64 nonce+ciphertext>"
+// so rows written under an older key stay readable after a rotation. Values
+// without the prefix are legacy plaintext and are returned as is.
+const encryptedPrefix = "enc:v1:"
+
+var piiKeyIDPattern = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+
+var (
+	ErrPIIKeysMissing = errors.New("value is encrypted but no PII keys are configured")
+	ErrUnknownPIIKey  = errors.New("value is encrypted with an unknown PII key")
+)
+
+// PIIKey is one AES-256 data key and the ID it is stored under
+type PIIKey struct {
+	ID  string
+	Key []byte
+}
+
+// PIIKeyring encrypts PII columns. The first key encrypts new values, the
+// others only decrypt values written before a rotation.
+type PIIKeyring struct {
+	currentID string
+	aeads     map[string]cipher.AEAD
+	blindKey  []byte
+}
+
+func NewPIIKeyring(keys []PIIKey, blindIndexKey []byte) (*PIIKeyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one PII encryption key is required")
+	}
+	if len(blindIndexKey) < 32 {
+		return nil, errors.New("PII blind index key must be at least 32 bytes")
+	}
+
+	k := &PIIKeyring{currentID: keys[0].ID, aeads: make(map[string]cipher.AEAD), blindKey: blindIndexKey}
+	for _, key := range keys {
+		if !piiKeyIDPattern.MatchString(key.ID) {
+			return nil, fmt.Errorf("invalid PII key ID %q: use letters, digits and dashes", key.ID)
+		}
+		if _, exists := k.aeads[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate PII key ID %q", key.ID)
+		}
+		if len(key.Key) != 32 {
+			return nil, fmt.Errorf("PII key %q must be 32 bytes for AES-256", key.ID)
+		}
+		block, err := aes.NewCipher(key.Key)
+		if err != nil {
+			return nil, fmt.Errorf("PII key %q: %v", key.ID, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("PII key %q: %v", key.ID, err)
+		}
+		k.aeads[key.ID] = aead
+	}
+	return k, nil
+}
+
+// CurrentPrefix is the prefix of every value encrypted with the current key.
+// Stored values without it still need re-encrypting.
+func (k *PIIKeyring) CurrentPrefix() string {
+	return encryptedPrefix + k.currentID + ":"
+}
+
+func (k *PIIKeyring) encrypt(plaintext string) (string, error) {
+	aead := k.aeads[k.currentID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %v", err)
+	}
+	// The key ID is authenticated so a value cannot be relabelled
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(k.currentID))
+	return k.CurrentPrefix() + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (k *PIIKeyring) decrypt(stored string) (string, error) {
+	keyID, payload, ok := strings.Cut(strings.TrimPrefix(stored, encryptedPrefix), ":")
+	if !ok {
+		return "", errors.New("malformed encrypted value")
+	}
+	aead, ok := k.aeads[keyID]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownPIIKey, keyID)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("malformed encrypted value")
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(keyID))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value with PII key %s: %v", keyID, err)
+	}
+	return string(plaintext), nil
+}
+
+// piiKeyring is set from config at startup. Without it new values are stored
+// in plaintext, which is only allowed outside production.
+var piiKeyring *PIIKeyring
+
+func SetPIIKeyring(k *PIIKeyring) {
+	piiKeyring = k
+}
+
+// CurrentPIIKeyring returns the keyring set at startup, or nil
+func CurrentPIIKeyring() *PIIKeyring {
+	return piiKeyring
+}
+
+// EncryptedString is a string column encrypted on write and decrypted on
+// read. Equality lookups need a BlindIndex column next to it, since the same
+// value encrypts differently every time.
+type EncryptedString string
+
+func (s EncryptedString) Value() (driver.Value, error) {
+	if s == "" || piiKeyring == nil {
+		return string(s), nil
+	}
+	return piiKeyring.encrypt(string(s))
+}
+
+func (s *EncryptedString) Scan(value interface{}) error {
+	var stored string
+	switch v := value.(type) {
+	case nil:
+		*s = ""
+		return nil
+	case string:
+		stored = v
+	case []byte:
+		stored = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into EncryptedString", value)
+	}
+
+	if !strings.HasPrefix(stored, encryptedPrefix) {
+		*s = EncryptedString(stored)
+		return nil
+	}
+	if piiKeyring == nil {
+		return ErrPIIKeysMissing
+	}
+	plaintext, err := piiKeyring.decrypt(stored)
+	if err != nil {
+		return err
+	}
+	*s = EncryptedString(plaintext)
+	return nil
+}
+
+// BlindIndex is a keyed hash of value for equality lookups on an encrypted
+// column. Callers normalize the value first so equal inputs hash equally.
+func BlindIndex(value string) string {
+	if value == "" {
+		return ""
+	}
+	var key []byte
+	if piiKeyring != nil {
+		key = piiKeyring.blindKey
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
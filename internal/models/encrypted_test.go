@@ -0,0 +1,130 @@
+package models
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var (
+	testKeyOne = PIIKey{ID: "k1", Key: bytes.Repeat([]byte{1}, 32)}
+	testKeyTwo = PIIKey{ID: "k2", Key: bytes.Repeat([]byte{2}, 32)}
+)
+
+func testKeyring(t *testing.T, blindKey byte, keys ...PIIKey) *PIIKeyring {
+	t.Helper()
+	keyring, err := NewPIIKeyring(keys, bytes.Repeat([]byte{blindKey}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keyring
+}
+
+// encryptWith stores value as EncryptedString.Value does under keyring
+func encryptWith(t *testing.T, keyring *PIIKeyring, value string) string {
+	t.Helper()
+	SetPIIKeyring(keyring)
+	stored, err := EncryptedString(value).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stored.(string)
+}
+
+func TestEncryptedString(t *testing.T) {
+	t.Cleanup(func() { SetPIIKeyring(nil) })
+
+	// k2 replaced k1, which stays in the ring to read older values
+	before := testKeyring(t, 9, testKeyOne)
+	after := testKeyring(t, 9, testKeyTwo, testKeyOne)
+	onlyNew := testKeyring(t, 9, testKeyTwo)
+
+	old := encryptWith(t, before, "+15550000001")
+	current := encryptWith(t, after, "+15550000001")
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(current, after.CurrentPrefix()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed[len(sealed)-1] ^= 0xff
+	tampered := after.CurrentPrefix() + base64.StdEncoding.EncodeToString(sealed)
+	relabelled := before.CurrentPrefix() + strings.TrimPrefix(current, after.CurrentPrefix())
+
+	tests := []struct {
+		name    string
+		keyring *PIIKeyring
+		stored  string
+		want    string
+		err     error // nil with wantErr set means any error
+		wantErr bool
+	}{
+		{"current key", after, current, "+15550000001", nil, false},
+		{"older key after a rotation", after, old, "+15550000001", nil, false},
+		{"legacy plaintext", after, "+15550000002", "+15550000002", nil, false},
+		{"key dropped from the ring", onlyNew, old, "", ErrUnknownPIIKey, true},
+		{"no keys configured", nil, current, "", ErrPIIKeysMissing, true},
+		{"tampered ciphertext", after, tampered, "", nil, true},
+		{"ciphertext relabelled with another key", after, relabelled, "", nil, true},
+		{"malformed value", after, encryptedPrefix + "k2", "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetPIIKeyring(tt.keyring)
+			var got EncryptedString
+			err := got.Scan(tt.stored)
+			switch {
+			case tt.wantErr && err == nil:
+				t.Fatalf("expected an error, got %q", got)
+			case tt.err != nil && !errors.Is(err, tt.err):
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			case !tt.wantErr && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case !tt.wantErr && string(got) != tt.want:
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("new values use the current key", func(t *testing.T) {
+		if !strings.HasPrefix(current, "enc:v1:k2:") {
+			t.Fatalf("expected a value under k2, got %q", current)
+		}
+		if again := encryptWith(t, after, "+15550000001"); again == current {
+			t.Fatal("expected a fresh nonce for every encryption")
+		}
+	})
+}
+
+func TestBlindIndex(t *testing.T) {
+	t.Cleanup(func() { SetPIIKeyring(nil) })
+
+	index := func(keyring *PIIKeyring, value string) string {
+		SetPIIKeyring(keyring)
+		return BlindIndex(value)
+	}
+	ring := testKeyring(t, 9, testKeyOne)
+	rotated := testKeyring(t, 9, testKeyTwo, testKeyOne)
+	otherBlindKey := testKeyring(t, 7, testKeyOne)
+
+	tests := []struct {
+		name  string
+		a, b  string
+		equal bool
+	}{
+		{"same value", index(ring, "+15550000001"), index(ring, "+15550000001"), true},
+		{"same value after a key rotation", index(ring, "+15550000001"), index(rotated, "+15550000001"), true},
+		{"different values", index(ring, "+15550000001"), index(ring, "+15550000002"), false},
+		{"different blind index keys", index(ring, "+15550000001"), index(otherBlindKey, "+15550000001"), false},
+		{"empty value", index(ring, ""), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if (tt.a == tt.b) != tt.equal {
+				t.Fatalf("expected equal=%v, got %q and %q", tt.equal, tt.a, tt.b)
+			}
+		})
+	}
+}
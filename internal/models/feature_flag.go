@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+)
+
+// Flags referenced from code. Unknown keys may still be created by admins
+// and read by clients through /api/v1/features.
+const (
+	FlagReviewsEnabled = "reviews_enabled"
+	FlagCSVImportV2    = "csv_import_v2"
+	FlagNewSearch      = "new_search"
+
+	// FlagMaintenanceMode puts public endpoints into maintenance (see MaintenanceMiddleware)
+	FlagMaintenanceMode = "maintenance_mode"
+)
+
+// FeatureFlag toggles a feature at runtime. When enabled, RolloutPercentage
+// limits it to a stable slice of users (or anonymous clients); UserIDs are
+// always included regardless of the percentage.
+type FeatureFlag struct {
+	ID                uint      `json:"id" gorm:"primaryKey"`
+	Key               string    `json:"key" gorm:"uniqueIndex;not null"`
+	Description       string    `json:"description"`
+	Enabled           bool      `json:"enabled" gorm:"default:false"`
+	RolloutPercentage int       `json:"rollout_percentage" gorm:"default:100"`
+	UserIDs           []uint    `json:"user_ids,omitempty" gorm:"type:text;serializer:json"`
+	UpdatedBy         uint      `json:"updated_by"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+type CreateFeatureFlagRequest struct {
+	Key               string `json:"key" binding:"required"`
+	Description       string `json:"description"`
+	Enabled           bool   `json:"enabled"`
+	RolloutPercentage *int   `json:"rollout_percentage,omitempty" binding:"omitempty,min=0,max=100"`
+	UserIDs           []uint `json:"user_ids,omitempty"`
+}
+
+type UpdateFeatureFlagRequest struct {
+	Description       *string `json:"description,omitempty"`
+	Enabled           *bool   `json:"enabled,omitempty"`
+	RolloutPercentage *int    `json:"rollout_percentage,omitempty" binding:"omitempty,min=0,max=100"`
+	UserIDs           *[]uint `json:"user_ids,omitempty"`
+}
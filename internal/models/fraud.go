@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+)
+
+// Reasons fraud screening gives for a risk score
+const (
+	RiskReasonDisposableEmail = "disposable_email"
+	RiskReasonIPVelocity      = "ip_velocity"     // many recent signups from the same IP
+	RiskReasonDeviceVelocity  = "device_velocity" // many recent signups from the same device
+	RiskReasonCountryMismatch = "country_mismatch"
+)
+
+// Fraud review states
+const (
+	FraudReviewPending = "pending"
+	FraudReviewCleared = "cleared"
+	FraudReviewBlocked = "blocked"
+)
+
+// RiskAssessment is the outcome of fraud screening: a score from 0 (no
+// signals) to 100 and the reasons behind it
+type RiskAssessment struct {
+	Score   int      `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+// FraudReview queues a high-risk signup for an admin to clear or block
+type FraudReview struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"user_id" gorm:"not null;index"`
+	Score      int        `json:"score"`
+	Reasons    []string   `json:"reasons" gorm:"type:text;serializer:json"`
+	IPAddress  string     `json:"ip_address,omitempty"`
+	Status     string     `json:"status" gorm:"default:'pending';index"`
+	ReviewedBy *uint      `json:"reviewed_by,omitempty"`
+	ReviewNote string     `json:"review_note,omitempty" gorm:"type:text"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	User User `json:"user,omitempty" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// ResolveFraudReviewRequest clears a flagged signup or blocks the account
+type ResolveFraudReviewRequest struct {
+	Decision string `json:"decision" binding:"required,oneof=cleared blocked"`
+	Note     string `json:"note" binding:"max=1000"`
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	FeedFormatCSV  = "csv"
+	FeedFormatJSON = "json"
+
+	ImportRunStatusRunning   = "running"
+	ImportRunStatusSucceeded = "succeeded"
+	ImportRunStatusFailed    = "failed"
+)
+
+// ImportSource is a supplier feed that is fetched on a cron schedule and
+// synced into the catalog, matching products by SKU.
+type ImportSource struct {
+	ID                uint              `json:"id" gorm:"primaryKey"`
+	Name              string            `json:"name" gorm:"not null"`
+	URL               string            `json:"url" gorm:"not null"`
+	Format            string            `json:"format" gorm:"default:'csv'"`
+	Schedule          string            `json:"schedule" gorm:"not null"`                 // standard 5-field cron expression
+	Mapping           map[string]string `json:"mapping" gorm:"type:text;serializer:json"` // product field -> column/key
+	Headers           map[string]string `json:"-" gorm:"type:text;serializer:json"`       // extra request headers, e.g. auth
+	DeactivateMissing bool              `json:"deactivate_missing" gorm:"default:false"`
+	IsActive          bool              `json:"is_active" gorm:"default:true;index"`
+	SyncedSKUs        []string          `json:"-" gorm:"column:synced_skus;type:text;serializer:json"` // SKUs seen in the last successful run
+	LastRunAt         *time.Time        `json:"last_run_at,omitempty"`
+	NextRunAt         *time.Time        `json:"next_run_at,omitempty" gorm:"index"`
+	CreatedBy         uint              `json:"created_by"`
+	CreatedAt         time.Time         `json:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
+}
+
+// ImportSourceRun is one sync of an ImportSource with its diff summary.
+type ImportSourceRun struct {
+	ID          uint             `json:"id" gorm:"primaryKey"`
+	SourceID    uint             `json:"source_id" gorm:"not null;index"`
+	Status      string           `json:"status" gorm:"index"`
+	Trigger     string           `json:"trigger"` // schedule or manual
+	TotalRows   int              `json:"total_rows"`
+	Created     int              `json:"created"`
+	Updated     int              `json:"updated"`
+	Unchanged   int              `json:"unchanged"`
+	Deactivated int              `json:"deactivated"`
+	Failed      int              `json:"failed"`
+	Diff        FeedDiff         `json:"diff" gorm:"type:text;serializer:json"`
+	Errors      []ImportRowError `json:"errors,omitempty" gorm:"type:text;serializer:json"`
+	Error       string           `json:"error,omitempty"`
+	StartedAt   time.Time        `json:"started_at"`
+	FinishedAt  *time.Time       `json:"finished_at,omitempty"`
+}
+
+// FeedDiff lists what a run changed, capped to keep rows small
+type FeedDiff struct {
+	Created     []string            `json:"created,omitempty"`
+	Updated     map[string][]string `json:"updated,omitempty"` // SKU -> changed fields
+	Deactivated []string            `json:"deactivated,omitempty"`
+	Truncated   bool                `json:"truncated,omitempty"`
+}
+
+type CreateImportSourceRequest struct {
+	Name              string            `json:"name" binding:"required"`
+	URL               string            `json:"url" binding:"required,url"`
+	Format            string            `json:"format" binding:"omitempty,oneof=csv json"`
+	Schedule          string            `json:"schedule" binding:"required"`
+	Mapping           map[string]string `json:"mapping" binding:"required"`
+	Headers           map[string]string `json:"headers"`
+	DeactivateMissing bool              `json:"deactivate_missing"`
+	IsActive          *bool             `json:"is_active"`
+}
+
+type UpdateImportSourceRequest struct {
+	Name              *string           `json:"name,omitempty"`
+	URL               *string           `json:"url,omitempty" binding:"omitempty,url"`
+	Format            *string           `json:"format,omitempty" binding:"omitempty,oneof=csv json"`
+	Schedule          *string           `json:"schedule,omitempty"`
+	Mapping           map[string]string `json:"mapping,omitempty"`
+	Headers           map[string]string `json:"headers,omitempty"`
+	DeactivateMissing *bool             `json:"deactivate_missing,omitempty"`
+	IsActive          *bool             `json:"is_active,omitempty"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+)
+
+// Invitation states. A pending invitation past ExpiresAt can no longer be
+// accepted and is listed as expired.
+const (
+	InvitationStatusPending  = "pending"
+	InvitationStatusAccepted = "accepted"
+	InvitationStatusRevoked  = "revoked"
+	InvitationStatusExpired  = "expired"
+)
+
+// Invitation grants a staff role to whoever accepts it with the emailed
+// token. Only a SHA-256 hash of the token is stored, and each invitation can
+// be accepted once.
+type Invitation struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	Email      string     `json:"email" gorm:"not null;index"`
+	Role       string     `json:"role" gorm:"not null"`
+	VendorID   *uint      `json:"vendor_id,omitempty"` // set for vendor invitations
+	TokenHash  string     `json:"-" gorm:"uniqueIndex"`
+	Status     string     `json:"status" gorm:"default:'pending';index"`
+	InvitedBy  uint       `json:"invited_by" gorm:"not null"`
+	ExpiresAt  time.Time  `json:"expires_at" gorm:"not null"`
+	AcceptedBy *uint      `json:"accepted_by,omitempty"` // user created or promoted on acceptance
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	// Relations
+	Inviter *User   `json:"inviter,omitempty" gorm:"foreignKey:InvitedBy"`
+	Vendor  *Vendor `json:"vendor,omitempty" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+type CreateInvitationRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Role     string `json:"role" binding:"required,oneof=admin vendor"`
+	VendorID uint   `json:"vendor_id"` // required for vendor invitations
+}
+
+// AcceptInvitationRequest creates the invited account. When the email already
+// has an account, Password must be its current password and the name fields
+// are ignored.
+type AcceptInvitationRequest struct {
+	Token       string `json:"token" binding:"required"`
+	Password    string `json:"password" binding:"required"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	PhoneNumber string `json:"phone_number"`
+}
+
+// InvitationPreview is what the public accept page shows before sign-up
+type InvitationPreview struct {
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+
+	JobTypeProductImport    = "product_import"
+	JobTypeProductExport    = "product_export"
+	JobTypeStorageReconcile = "storage_reconcile"
+	JobTypeReviewExport     = "review_export"
+	JobTypeImageAltText     = "image_alt_text"
+	JobTypeImageImport      = "image_import"
+	JobTypeReport           = "report"
+
+	JobLogInfo  = "info"
+	JobLogWarn  = "warn"
+	JobLogError = "error"
+)
+
+// Job tracks a long-running admin task such as a CSV import or an export.
+type Job struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	Type       string     `json:"type" gorm:"not null;index"`
+	Status     string     `json:"status" gorm:"default:'queued';index"`
+	Total      int        `json:"total"`
+	Processed  int        `json:"processed"`
+	Failed     int        `json:"failed"`
+	Progress   int        `json:"progress"` // percentage, 0-100
+	Result     string     `json:"result,omitempty" gorm:"type:text"`
+	ResultURL  string     `json:"result_url,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	CreatedBy  uint       `json:"created_by" gorm:"index"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// JobLog is a single line of a job's output, e.g. a row-level import error.
+type JobLog struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	JobID     uint      `json:"job_id" gorm:"not null;index"`
+	Level     string    `json:"level" gorm:"not null"`
+	Message   string    `json:"message" gorm:"type:text"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// MetricSnapshot stores the key dashboard figures for a single day so trends
+// can be charted without recomputing history.
+type MetricSnapshot struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	Date           time.Time `json:"date" gorm:"type:date;uniqueIndex;not null"`
+	TotalProducts  int64     `json:"total_products"`
+	ActiveProducts int64     `json:"active_products"`
+	TotalUsers     int64     `json:"total_users"`
+	TotalReviews   int64     `json:"total_reviews"`
+	StockUnits     int64     `json:"stock_units"`
+	StockValue     float64   `json:"stock_value"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
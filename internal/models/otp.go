@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	OTPPurposeSignup         = "signup"
+	OTPPurposePasswordChange = "password_change"
+	OTPPurposeCheckout       = "checkout"
+	OTPPurposePhoneChange    = "phone_change"
+)
+
+// OTPCode is a one-time SMS code. Only an HMAC of the code is stored. The
+// phone number is encrypted, so codes are looked up by PhoneHash.
+type OTPCode struct {
+	ID          uint            `json:"id" gorm:"primaryKey"`
+	Phone       EncryptedString `json:"phone" gorm:"not null"`
+	PhoneHash   string          `json:"-" gorm:"index:idx_otp_phone_hash_purpose"`
+	Purpose     string          `json:"purpose" gorm:"not null;index:idx_otp_phone_hash_purpose"`
+	CodeHash    string          `json:"-" gorm:"not null"`
+	Attempts    int             `json:"attempts" gorm:"default:0"`
+	ExpiresAt   time.Time       `json:"expires_at" gorm:"not null"`
+	VerifiedAt  *time.Time      `json:"verified_at,omitempty"`
+	ConsumedAt  *time.Time      `json:"consumed_at,omitempty"` // set once the verification is used, or when superseded
+	RequestedIP string          `json:"-"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// BeforeSave keeps the blind index in step with the phone number
+func (o *OTPCode) BeforeSave(tx *gorm.DB) error {
+	if o.Phone != "" {
+		o.PhoneHash = BlindIndex(string(o.Phone))
+	}
+	return nil
+}
+
+type SendOTPRequest struct {
+	Phone   string `json:"phone" binding:"required"`
+	Purpose string `json:"purpose" binding:"required,oneof=signup password_change checkout phone_change"`
+}
+
+type VerifyOTPRequest struct {
+	Phone   string `json:"phone" binding:"required"`
+	Purpose string `json:"purpose" binding:"required,oneof=signup password_change checkout phone_change"`
+	Code    string `json:"code" binding:"required,len=6,numeric"`
+}
+
+type SendOTPResponse struct {
+	ExpiresAt   time.Time `json:"expires_at"`
+	ResendAfter time.Time `json:"resend_after"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// OutboxEvent is a domain event written in the same transaction as the
+// business change it describes; a relay publishes it afterwards.
+type OutboxEvent struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	EventID       string     `json:"event_id" gorm:"type:uuid;uniqueIndex;not null"`
+	Type          string     `json:"type" gorm:"not null;index"`
+	AggregateType string     `json:"aggregate_type" gorm:"not null"`
+	AggregateID   string     `json:"aggregate_id" gorm:"not null"`
+	Payload       string     `json:"payload" gorm:"type:jsonb;not null"`
+	Attempts      int        `json:"attempts" gorm:"default:0"`
+	LastError     string     `json:"last_error,omitempty"`
+	PublishedAt   *time.Time `json:"published_at,omitempty" gorm:"index"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+)
+
+// Scheduled price change statuses
+const (
+	PriceChangePending   = "pending"
+	PriceChangeActive    = "active" // sale window in progress
+	PriceChangeCompleted = "completed"
+	PriceChangeCancelled = "cancelled"
+)
+
+// ScheduledPriceChange sets a product's price at StartsAt. With EndsAt it is
+// a sale window: the previous price is shown as the compare-at price while it
+// runs and restored when it ends, so manual price edits made during a sale
+// are lost. Without EndsAt the change is permanent.
+type ScheduledPriceChange struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	ProductID     uint       `json:"product_id" gorm:"not null;index"`
+	Price         float64    `json:"price" gorm:"not null"`
+	StartsAt      time.Time  `json:"starts_at" gorm:"not null;index"`
+	EndsAt        *time.Time `json:"ends_at,omitempty" gorm:"index"`
+	Status        string     `json:"status" gorm:"not null;default:'pending';index"`
+	OriginalPrice *float64   `json:"original_price,omitempty"` // price before the change applied
+	CreatedBy     uint       `json:"created_by"`
+	AppliedAt     *time.Time `json:"applied_at,omitempty"`
+	EndedAt       *time.Time `json:"ended_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	Product *Product `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// IsSale reports whether the change is a sale window rather than permanent
+func (c *ScheduledPriceChange) IsSale() bool {
+	return c.EndsAt != nil
+}
+
+type SchedulePriceChangeRequest struct {
+	Price    float64    `json:"price" binding:"required,gt=0"`
+	StartsAt time.Time  `json:"starts_at" binding:"required"`
+	EndsAt   *time.Time `json:"ends_at"` // omit for a permanent change
+}
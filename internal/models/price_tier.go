@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+// PriceTier is a bulk unit price: buying at least MinQuantity of the product
+// costs UnitPrice each
+type PriceTier struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	ProductID   uint      `json:"product_id" gorm:"not null;uniqueIndex:idx_price_tiers_quantity"`
+	MinQuantity int       `json:"min_quantity" gorm:"not null;uniqueIndex:idx_price_tiers_quantity"`
+	UnitPrice   float64   `json:"unit_price" gorm:"not null"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	Product *Product `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+type CreatePriceTierRequest struct {
+	MinQuantity int     `json:"min_quantity" binding:"required,gt=1"`
+	UnitPrice   float64 `json:"unit_price" binding:"required,gt=0"`
+}
+
+type UpdatePriceTierRequest struct {
+	MinQuantity *int     `json:"min_quantity" binding:"omitempty,gt=1"`
+	UnitPrice   *float64 `json:"unit_price" binding:"omitempty,gt=0"`
+}
+
+// PriceQuote is the price of a quantity of a product after tier discounts
+type PriceQuote struct {
+	ProductID uint       `json:"product_id"`
+	Quantity  int        `json:"quantity"`
+	UnitPrice float64    `json:"unit_price"`
+	Total     float64    `json:"total"`
+	Tier      *PriceTier `json:"tier,omitempty"` // tier applied, if any
+}
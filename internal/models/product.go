@@ -8,9 +8,30 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	ProductStatusActive   = "active"
+	ProductStatusInactive = "inactive"
+	ProductStatusArchived = "archived"
+	ProductStatusDraft    = "draft" // hidden from the storefront until an admin activates it
+)
+
+// How an active product without stock is presented
+const (
+	OutOfStockDefault = ""     // follows OUT_OF_STOCK_DISPLAY
+	OutOfStockShow    = "show" // listed, marked out of stock
+	OutOfStockHide    = "hide" // left out of listings; the product page stays reachable
+)
+
+// Product availability, computed when a product is served
+const (
+	AvailabilityInStock    = "in_stock"
+	AvailabilityOutOfStock = "out_of_stock"
+)
+
 type Product struct {
 	ID          uint      `json:"id" gorm:"primaryKey"`
 	Title       string    `json:"title" gorm:"not null"`
+	SKU         string    `json:"sku,omitempty" gorm:"index:idx_products_sku,unique,where:sku <> ''"`
 	Description string    `json:"description"`
 	Price       float64   `json:"price" gorm:"not null"`
 	Category    string    `json:"category"`
@@ -18,17 +39,46 @@ type Product struct {
 	Material    string    `json:"material,omitempty"`
 	Status      string    `json:"status" gorm:"default:'active'"`
 	Stock       int       `json:"stock" gorm:"default:0"`
+	AvgRating   float64   `json:"avg_rating" gorm:"default:0"`         // mean of published reviews
+	RatingCount int       `json:"rating_count" gorm:"default:0"`       // published reviews
+	RatingScore float64   `json:"rating_score" gorm:"default:0;index"` // weighted score used to rank by rating
+	VendorID    *uint     `json:"vendor_id,omitempty" gorm:"index"`
+	StoreID     *uint     `json:"store_id,omitempty" gorm:"index"` // nil in single-tenant mode
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	Images      []Image   `json:"images" gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE"`
 	LikeCount    int  `gorm:"default:0"`
 	DislikeCount int  `gorm:"default:0"`
+	ViewCount    int  `json:"view_count" gorm:"default:0"` // detail page views, flushed in batches
+
+	// Overrides OUT_OF_STOCK_DISPLAY for this product
+	OutOfStockDisplay string `json:"out_of_stock_display,omitempty" gorm:"default:''"`
+	// in_stock or out_of_stock; set on storefront responses, where products
+	// without stock stay viewable but cannot be bought
+	Availability string `json:"availability,omitempty" gorm:"-"`
+
+	// Units a single purchase must stay within; 0 means no limit
+	MinOrderQty int `json:"min_order_qty,omitempty" gorm:"default:0"`
+	MaxOrderQty int `json:"max_order_qty,omitempty" gorm:"default:0"`
+
+	// Price before the running sale window; nil outside sales
+	CompareAtPrice *float64 `json:"compare_at_price,omitempty"`
+
+	// Bulk unit prices, by quantity; loaded on the product detail
+	PriceTiers []PriceTier `json:"price_tiers,omitempty" gorm:"foreignKey:ProductID"`
 
 	// Fixed Services relationship
 	Services []Service `json:"services,omitempty" gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE"`
 
 	// Relations
 	Reviews []Review `json:"reviews,omitempty"`
+
+	// Seller of the product; nil for the store's own catalogue
+	Vendor *Vendor `json:"vendor,omitempty" gorm:"constraint:OnDelete:SET NULL"`
+	Store  *Store  `json:"-" gorm:"constraint:OnDelete:RESTRICT"`
+
+	// Set when the product is sold as a kit of other products
+	Bundle *ProductBundle `json:"bundle,omitempty" gorm:"foreignKey:ProductID"`
 }
 type ProductReaction struct {
 	ID         uint `gorm:"primaryKey"`
@@ -54,16 +104,20 @@ type Service struct {
 }
 
 type Image struct {
-	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	ProductID   uint      `gorm:"not null;index" json:"product_id"`
-	FileName    string    `gorm:"not null" json:"file_name"`
-	S3Key       string    `gorm:"not null;unique" json:"s3_key"`
-	S3URL       string    `gorm:"not null" json:"s3_url"`
-	ContentType string    `gorm:"not null" json:"content_type"`
-	Size        int64     `json:"size"`
-	IsActive    bool      `json:"is_active" gorm:"default:true"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ProductID   uint       `gorm:"not null;index" json:"product_id"`
+	FileName    string     `gorm:"not null" json:"file_name"`
+	S3Key       string     `gorm:"not null;index" json:"s3_key"`                // shared by images with the same content
+	SHA256      string     `gorm:"column:sha256;index" json:"sha256,omitempty"` // content hash; empty for images uploaded before deduplication
+	S3URL       string     `gorm:"not null" json:"s3_url"`
+	ContentType string     `gorm:"not null" json:"content_type"`
+	Size        int64      `json:"size"`
+	IsActive    bool       `json:"is_active" gorm:"default:true"`
+	AltText     string     `json:"alt_text"`             // describes the image for screen readers and search engines
+	Caption     string     `json:"caption"`              // shown next to the image
+	MissingAt   *time.Time `json:"missing_at,omitempty"` // set when storage reconciliation cannot find the S3 object
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 
 	// Belongs to relationship
 	Product Product `json:"-" gorm:"constraint:OnDelete:CASCADE"`
@@ -85,16 +139,32 @@ type ProductUploadResponse struct {
 
 
 
+// CreateProductRequest is bound from JSON or from multipart form fields of the
+// same names. In a multipart form, services is a JSON-encoded array and image
+// files are sent as "images".
 type CreateProductRequest struct {
-	Title       string                 `json:"title" binding:"required"`
-	Description string                 `json:"description"`
-	Price       float64                `json:"price" binding:"required,gt=0"`
-	Category    string                 `json:"category"`
-	Material    string                 `json:"material,omitempty"`
-	Size        string                 `json:"size"`
-	Stock       int                    `json:"stock"`
-	Status      string                 `json:"status" binding:"required,oneof=active inactive"`
-	Services    []CreateServiceRequest `json:"services,omitempty"`
+	Title       string                 `json:"title" form:"title" binding:"required"`
+	SKU         string                 `json:"sku,omitempty" form:"sku"`
+	Description string                 `json:"description" form:"description"`
+	Price       float64                `json:"price" form:"price" binding:"required,gt=0"`
+	Category    string                 `json:"category" form:"category"`
+	Material    string                 `json:"material,omitempty" form:"material"`
+	Size        string                 `json:"size" form:"size"`
+	Stock       int                    `json:"stock" form:"stock" binding:"gte=0"`
+	MinOrderQty int                    `json:"min_order_qty,omitempty" form:"min_order_qty" binding:"gte=0"` // 0 for no minimum
+	MaxOrderQty int                    `json:"max_order_qty,omitempty" form:"max_order_qty" binding:"gte=0"` // 0 for no maximum
+	Status      string                 `json:"status" form:"status" binding:"omitempty,oneof=active inactive"` // defaults to active
+	VendorID    *uint                  `json:"vendor_id,omitempty" form:"vendor_id"`                           // forced to the caller's vendor for vendor users
+	StoreID     *uint                  `json:"-" form:"-"`                                                     // set from the resolved store
+	Services    []CreateServiceRequest `json:"services,omitempty" form:"-"`
+}
+
+// DuplicateProductRequest overrides fields on a product copy. Every field is
+// optional; the copy keeps the source title with a " (Copy)" suffix and no SKU.
+type DuplicateProductRequest struct {
+	Title      *string `json:"title,omitempty"`
+	SKU        string  `json:"sku,omitempty"`
+	CopyImages bool    `json:"copy_images"`
 }
 
 type CreateServiceRequest struct {
@@ -102,8 +172,33 @@ type CreateServiceRequest struct {
 	Link string `json:"link" binding:"required"`
 }
 
+// UpdateImageRequest changes the accessibility metadata of one image. Omitted
+// fields are left as they are; an empty string clears the field.
+type UpdateImageRequest struct {
+	AltText *string `json:"alt_text,omitempty"`
+	Caption *string `json:"caption,omitempty"`
+}
+
+// GenerateAltTextRequest limits AI alt text generation to some products;
+// empty means every image without alt text
+type GenerateAltTextRequest struct {
+	ProductIDs []uint `json:"product_ids,omitempty"`
+}
+
+// ImportProductImagesRequest lists remote images, e.g. from a supplier
+// catalog, to download and attach to a product
+type ImportProductImagesRequest struct {
+	URLs []string `json:"urls" binding:"required,min=1,max=50,dive,required"`
+}
+
+type UpdateServiceRequest struct {
+	Name *string `json:"name,omitempty"`
+	Link *string `json:"link,omitempty"`
+}
+
 type UpdateProductRequest struct {
 	Title       *string  `json:"title,omitempty"`
+	SKU         *string  `json:"sku,omitempty"`
 	Description *string  `json:"description,omitempty"`
 	Price       *float64 	`json:"price,string,omitempty"`
 	Category    *string  `json:"category,omitempty"`
@@ -111,5 +206,9 @@ type UpdateProductRequest struct {
 	Size        *string  `json:"size,omitempty"`
 	Stock       *int     `json:"stock,omitempty"`
 	Status      *string  `json:"status,omitempty"`
+	VendorID    *uint    `json:"vendor_id,omitempty"` // 0 moves the product back to the store's own catalogue
+	OutOfStockDisplay *string `json:"out_of_stock_display,omitempty"` // "" follows the store setting
+	MinOrderQty *int     `json:"min_order_qty,omitempty"` // 0 removes the limit
+	MaxOrderQty *int     `json:"max_order_qty,omitempty"` // 0 removes the limit
 	Services    []CreateServiceRequest `json:"services,omitempty"` 
 }
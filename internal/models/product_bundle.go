@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+)
+
+// Bundle pricing modes
+const (
+	BundlePricingSumDiscount = "sum_discount" // sum of component prices less DiscountPercent
+	BundlePricingFixed       = "fixed"        // FixedPrice regardless of components
+)
+
+// ProductBundle turns a product into a kit sold as a set of component
+// products. The parent keeps its own listing; stock is drawn from the
+// components.
+type ProductBundle struct {
+	ID              uint                `json:"id" gorm:"primaryKey"`
+	ProductID       uint                `json:"product_id" gorm:"not null;uniqueIndex"`
+	PricingMode     string              `json:"pricing_mode" gorm:"not null;default:'sum_discount'"`
+	DiscountPercent float64             `json:"discount_percent"`
+	FixedPrice      float64             `json:"fixed_price,omitempty"`
+	Items           []ProductBundleItem `json:"items" gorm:"foreignKey:BundleID;constraint:OnDelete:CASCADE"`
+	CreatedAt       time.Time           `json:"created_at"`
+	UpdatedAt       time.Time           `json:"updated_at"`
+
+	// Computed from the components when the bundle is loaded
+	Price             float64 `json:"price" gorm:"-"`
+	InStock           bool    `json:"in_stock" gorm:"-"`
+	AvailableQuantity int     `json:"available_quantity" gorm:"-"` // complete bundles the component stock allows
+
+	Product Product `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+type ProductBundleItem struct {
+	ID          uint     `json:"id" gorm:"primaryKey"`
+	BundleID    uint     `json:"bundle_id" gorm:"not null;index"`
+	ComponentID uint     `json:"component_id" gorm:"not null;index"`
+	Quantity    int      `json:"quantity" gorm:"not null;default:1"`
+	Component   *Product `json:"component,omitempty" gorm:"foreignKey:ComponentID;constraint:OnDelete:RESTRICT"`
+}
+
+type SetProductBundleRequest struct {
+	PricingMode     string                     `json:"pricing_mode" binding:"required,oneof=sum_discount fixed"`
+	DiscountPercent float64                    `json:"discount_percent" binding:"gte=0,lt=100"`
+	FixedPrice      float64                    `json:"fixed_price" binding:"gte=0"`
+	Items           []ProductBundleItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+type ProductBundleItemRequest struct {
+	ProductID uint `json:"product_id" binding:"required"`
+	Quantity  int  `json:"quantity" binding:"required,min=1"`
+}
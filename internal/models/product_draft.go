@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	ProductDraftStatusPending  = "pending"
+	ProductDraftStatusApproved = "approved"
+	ProductDraftStatusRejected = "rejected"
+)
+
+// ProductDraft is a product extracted from uploaded images by the FastAPI
+// service, waiting for an admin to review it. Its images stay in the local
+// staging directory until the draft is approved.
+type ProductDraft struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	BatchID     string    `json:"batch_id" gorm:"not null;index"`
+	Status      string    `json:"status" gorm:"default:'pending';index"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Price       float64   `json:"price"`
+	Category    string    `json:"category"`
+	Brand       string    `json:"brand,omitempty"`
+	SKU         string    `json:"sku,omitempty"`
+	Images      []string  `json:"images" gorm:"type:text;serializer:json"` // staged file names
+	ProductID   *uint     `json:"product_id,omitempty"`
+	CreatedBy   uint      `json:"created_by"`
+	ReviewedBy  *uint     `json:"reviewed_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ApproveProductDraftRequest lets the reviewer correct extracted fields
+// before the product is created.
+type ApproveProductDraftRequest struct {
+	Title       *string  `json:"title,omitempty"`
+	SKU         *string  `json:"sku,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Price       *float64 `json:"price,omitempty" binding:"omitempty,gt=0"`
+	Category    *string  `json:"category,omitempty"`
+	Material    string   `json:"material,omitempty"`
+	Size        string   `json:"size,omitempty"`
+	Stock       int      `json:"stock" binding:"gte=0"`
+	Status      string   `json:"status" binding:"omitempty,oneof=active inactive"`
+	Images      []string `json:"images,omitempty"` // subset of the staged images to keep
+}
+
+type ProductExtractionResponse struct {
+	BatchID string         `json:"batch_id"`
+	Message string         `json:"message,omitempty"`
+	Drafts  []ProductDraft `json:"drafts"`
+}
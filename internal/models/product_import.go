@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	ImportModeInsert = "insert" // only create new products
+	ImportModeUpsert = "upsert" // update products matched by SKU, create the rest
+	ImportModeUpdate = "update" // only update products matched by SKU
+
+	ImportStatusUploaded  = "uploaded"
+	ImportStatusRunning   = "running"
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed"
+
+	JobTypeProductMappedImport = "product_mapped_import"
+)
+
+// ProductImportFields are the product attributes a spreadsheet column can be
+// mapped to.
+var ProductImportFields = []string{"sku", "title", "description", "price", "category", "material", "size", "stock", "status"}
+
+// ProductImport holds an uploaded CSV/XLSX sheet between the upload step, the
+// column mapping step and the import run.
+type ProductImport struct {
+	ID        uint              `json:"id" gorm:"primaryKey"`
+	FileName  string            `json:"file_name"`
+	Format    string            `json:"format"` // csv or xlsx
+	Headers   []string          `json:"headers" gorm:"type:text;serializer:json"`
+	Rows      [][]string        `json:"-" gorm:"type:text;serializer:json"`
+	Mapping   map[string]string `json:"mapping,omitempty" gorm:"type:text;serializer:json"` // product field -> header
+	Mode      string            `json:"mode,omitempty"`
+	Status    string            `json:"status" gorm:"default:'uploaded';index"`
+	JobID     *uint             `json:"job_id,omitempty"`
+	TotalRows int               `json:"total_rows"`
+	Created   int               `json:"created"`
+	Updated   int               `json:"updated"`
+	Failed    int               `json:"failed"`
+	Errors    []ImportRowError  `json:"errors,omitempty" gorm:"type:text;serializer:json"`
+	CreatedBy uint              `json:"created_by"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+type ImportRowError struct {
+	Row     int    `json:"row"` // 1-based sheet row, header is row 1
+	Message string `json:"message"`
+}
+
+type ProductImportUploadResponse struct {
+	Import           *ProductImport    `json:"import"`
+	Headers          []string          `json:"headers"`
+	SampleRows       [][]string        `json:"sample_rows"`
+	SuggestedMapping map[string]string `json:"suggested_mapping"`
+	Fields           []string          `json:"fields"`
+}
+
+type RunProductImportRequest struct {
+	Mapping map[string]string `json:"mapping" binding:"required"`
+	Mode    string            `json:"mode" binding:"omitempty,oneof=insert upsert update"`
+}
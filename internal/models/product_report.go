@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+)
+
+// Reasons a customer can give when reporting a product listing
+const (
+	ReportReasonCounterfeit   = "counterfeit"
+	ReportReasonIncorrectInfo = "incorrect_info"
+	ReportReasonProhibited    = "prohibited"
+	ReportReasonOffensive     = "offensive"
+	ReportReasonOther         = "other"
+)
+
+// Product report states. Open and reviewing reports count towards the
+// automatic de-listing threshold.
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusReviewing = "reviewing"
+	ReportStatusResolved  = "resolved"
+	ReportStatusDismissed = "dismissed"
+)
+
+// ProductReport is a customer's report of a counterfeit, incorrect or
+// otherwise problematic listing. Each customer can report a product once.
+type ProductReport struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	ProductID      uint       `json:"product_id" gorm:"not null;uniqueIndex:idx_product_reports_product_reporter"`
+	ReporterID     uint       `json:"reporter_id" gorm:"not null;uniqueIndex:idx_product_reports_product_reporter"`
+	Reason         string     `json:"reason" gorm:"not null"`
+	Details        string     `json:"details,omitempty" gorm:"type:text"`
+	Status         string     `json:"status" gorm:"default:'open';index"`
+	DelistedAt     *time.Time `json:"delisted_at,omitempty"` // set on the report that took the product over the threshold
+	ResolvedBy     *uint      `json:"resolved_by,omitempty"`
+	ResolutionNote string     `json:"resolution_note,omitempty" gorm:"type:text"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// Relations
+	Product  Product `json:"product,omitempty" gorm:"constraint:OnDelete:CASCADE"`
+	Reporter User    `json:"reporter,omitempty" gorm:"foreignKey:ReporterID"`
+}
+
+type CreateProductReportRequest struct {
+	Reason  string `json:"reason" binding:"required,oneof=counterfeit incorrect_info prohibited offensive other"`
+	Details string `json:"details" binding:"max=2000"`
+}
+
+type UpdateProductReportRequest struct {
+	Status string `json:"status" binding:"required,oneof=open reviewing resolved dismissed"`
+	Note   string `json:"note" binding:"max=2000"`
+}
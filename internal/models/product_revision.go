@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+)
+
+// Product revision sources
+const (
+	RevisionSourceBaseline = "baseline" // state before the first recorded edit
+	RevisionSourceUpdate   = "update"
+	RevisionSourceRestore  = "restore"
+)
+
+// ProductRevision is a snapshot of a product taken after an edit. Number
+// counts up from 1 per product.
+type ProductRevision struct {
+	ID           uint            `json:"id" gorm:"primaryKey"`
+	ProductID    uint            `json:"product_id" gorm:"not null;uniqueIndex:idx_product_revisions_number"`
+	Number       int             `json:"number" gorm:"not null;uniqueIndex:idx_product_revisions_number"`
+	Source       string          `json:"source" gorm:"not null"`
+	RestoredFrom *int            `json:"restored_from,omitempty"` // revision number a restore reverted to
+	ActorID      *uint           `json:"actor_id,omitempty" gorm:"index"`
+	Snapshot     ProductSnapshot `json:"snapshot" gorm:"type:text;serializer:json;not null"`
+	CreatedAt    time.Time       `json:"created_at"`
+
+	// Field-level differences from the previous revision; not stored
+	Changes []FieldChange `json:"changes" gorm:"-"`
+}
+
+// ProductSnapshot is the editable state of a product
+type ProductSnapshot struct {
+	Title       string                 `json:"title"`
+	SKU         string                 `json:"sku"`
+	Description string                 `json:"description"`
+	Price       float64                `json:"price"`
+	Category    string                 `json:"category"`
+	Size        string                 `json:"size"`
+	Material    string                 `json:"material"`
+	Status      string                 `json:"status"`
+	Stock       int                    `json:"stock"`
+	VendorID    *uint                  `json:"vendor_id"`
+	Services    []CreateServiceRequest `json:"services"`
+	Images      []ImageSnapshot        `json:"images"`
+}
+
+// ImageSnapshot identifies an active image of a snapshotted product
+type ImageSnapshot struct {
+	ID      string `json:"id"`
+	URL     string `json:"url"`
+	AltText string `json:"alt_text,omitempty"`
+	Caption string `json:"caption,omitempty"`
+}
+
+// FieldChange is one snapshot field that differs between two revisions
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+)
+
+// Quote statuses
+const (
+	QuoteStatusRequested = "requested" // waiting for an admin to price it
+	QuoteStatusQuoted    = "quoted"    // priced; the customer can accept until ExpiresAt
+	QuoteStatusAccepted  = "accepted"
+	QuoteStatusDeclined  = "declined" // by the customer
+	QuoteStatusRejected  = "rejected" // by an admin
+	QuoteStatusExpired   = "expired"
+)
+
+// Quote is a customer's request for negotiated prices on a list of products,
+// typically for a wholesale purchase. Admins answer it with a unit price per
+// item and an expiry date.
+type Quote struct {
+	ID           uint        `json:"id" gorm:"primaryKey"`
+	UserID       uint        `json:"user_id" gorm:"not null;index"`
+	Status       string      `json:"status" gorm:"not null;default:'requested';index"`
+	CustomerNote string      `json:"customer_note,omitempty" gorm:"type:text"`
+	AdminNote    string      `json:"admin_note,omitempty" gorm:"type:text"`
+	Total        float64     `json:"total"` // at quoted prices once quoted, list prices before
+	ExpiresAt    *time.Time  `json:"expires_at,omitempty" gorm:"index"`
+	RespondedBy  *uint       `json:"responded_by,omitempty"`
+	RespondedAt  *time.Time  `json:"responded_at,omitempty"`
+	DecidedAt    *time.Time  `json:"decided_at,omitempty"` // accepted, declined or rejected
+	Items        []QuoteItem `json:"items" gorm:"foreignKey:QuoteID;constraint:OnDelete:CASCADE"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+
+	User *User `json:"user,omitempty" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// QuoteItem is one product line of a quote. Title and SKU are copied so the
+// quote still reads correctly after the product changes or is deleted.
+type QuoteItem struct {
+	ID              uint     `json:"id" gorm:"primaryKey"`
+	QuoteID         uint     `json:"quote_id" gorm:"not null;index"`
+	ProductID       *uint    `json:"product_id" gorm:"index"`
+	ProductTitle    string   `json:"product_title"`
+	SKU             string   `json:"sku,omitempty"`
+	Quantity        int      `json:"quantity" gorm:"not null"`
+	ListUnitPrice   float64  `json:"list_unit_price"` // the customer's price when they asked
+	QuotedUnitPrice *float64 `json:"quoted_unit_price,omitempty"`
+
+	Product *Product `json:"-" gorm:"constraint:OnDelete:SET NULL"`
+}
+
+type CreateQuoteRequest struct {
+	Items []QuoteItemRequest `json:"items" binding:"required,min=1,max=100,dive"`
+	Note  string             `json:"note" binding:"max=2000"`
+}
+
+type QuoteItemRequest struct {
+	ProductID uint `json:"product_id" binding:"required"`
+	Quantity  int  `json:"quantity" binding:"required,gt=0"`
+}
+
+// RespondQuoteRequest prices a quote. Items left out keep their list price;
+// without expires_at the quote is valid for QUOTE_VALIDITY.
+type RespondQuoteRequest struct {
+	Items     []QuotedItemPrice `json:"items" binding:"dive"`
+	Note      string            `json:"note" binding:"max=2000"`
+	ExpiresAt *time.Time        `json:"expires_at"`
+}
+
+type QuotedItemPrice struct {
+	ItemID    uint    `json:"item_id" binding:"required"`
+	UnitPrice float64 `json:"unit_price" binding:"required,gt=0"`
+}
+
+type RejectQuoteRequest struct {
+	Note string `json:"note" binding:"max=2000"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+)
+
+// Fraud signals recorded on a referral
+const (
+	ReferralFlagSameIP     = "same_ip"     // signed up from the IP the referrer last used
+	ReferralFlagSameDevice = "same_device" // signed up from the device the referrer last used
+	ReferralFlagRepeatIP   = "repeat_ip"   // another signup with the referrer's code came from the same IP
+)
+
+// ReferralCode is a customer's personal referral code, created the first
+// time they ask for it. The IP and device they last fetched it from are kept
+// to spot customers referring themselves.
+type ReferralCode struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	UserID        uint      `json:"user_id" gorm:"not null;uniqueIndex"`
+	Code          string    `json:"code" gorm:"not null;uniqueIndex"`
+	LastIPAddress string    `json:"-"`
+	LastDeviceID  string    `json:"-"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	User User `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// Referral attributes a signup to the referral code it used. Each user can
+// be referred once.
+type Referral struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ReferrerID uint      `json:"referrer_id" gorm:"not null;index"`
+	ReferredID uint      `json:"referred_id" gorm:"not null;uniqueIndex"`
+	Code       string    `json:"code" gorm:"not null"`
+	IPAddress  string    `json:"ip_address,omitempty" gorm:"index"`
+	DeviceID   string    `json:"device_id,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	Flags      []string  `json:"flags,omitempty" gorm:"type:text;serializer:json"`
+	Flagged    bool      `json:"flagged" gorm:"default:false;index"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	Referrer User `json:"referrer,omitempty" gorm:"foreignKey:ReferrerID;constraint:OnDelete:CASCADE"`
+	Referred User `json:"referred,omitempty" gorm:"foreignKey:ReferredID;constraint:OnDelete:CASCADE"`
+}
+
+// ReferralSummary is what a customer sees of their own referrals
+type ReferralSummary struct {
+	Code      string `json:"code"`
+	Referrals int64  `json:"referrals"`
+}
+
+// ReferrerStats is one referrer's line in the admin referral report
+type ReferrerStats struct {
+	ReferrerID     uint      `json:"referrer_id"`
+	Email          string    `json:"email"`
+	Code           string    `json:"code"`
+	Referrals      int64     `json:"referrals"`
+	Flagged        int64     `json:"flagged"`
+	FirstReferral  time.Time `json:"first_referral_at"`
+	LatestReferral time.Time `json:"latest_referral_at"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+)
+
+// Canned report types
+const (
+	ReportInventoryValuation = "inventory_valuation"
+	ReportSlowMovingStock    = "slow_moving_stock"
+	ReportReviewSentiment    = "review_sentiment"
+	ReportUserGrowth         = "user_growth"
+)
+
+// Report file formats
+const (
+	ReportFormatCSV = "csv"
+	ReportFormatPDF = "pdf"
+)
+
+// Report statuses
+const (
+	ReportStatusPending   = "pending"
+	ReportStatusRunning   = "running"
+	ReportStatusCompleted = "completed"
+	ReportStatusFailed    = "failed"
+)
+
+// Report is one generated report file. The file lives in S3 and is handed
+// out through short-lived signed links.
+type Report struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	Type        string     `json:"type" gorm:"not null;index"`
+	Format      string     `json:"format" gorm:"not null"`
+	Status      string     `json:"status" gorm:"not null;default:'pending';index"`
+	Trigger     string     `json:"trigger"` // schedule or manual
+	Rows        int        `json:"rows"`
+	FileKey     string     `json:"-"`
+	Size        int64      `json:"size"`
+	Error       string     `json:"error,omitempty"`
+	JobID       *uint      `json:"job_id,omitempty"`
+	CreatedBy   *uint      `json:"created_by,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DownloadURL string     `json:"download_url,omitempty" gorm:"-"`
+}
+
+type CreateReportRequest struct {
+	Type   string `json:"type" binding:"required,oneof=inventory_valuation slow_moving_stock review_sentiment user_growth"`
+	Format string `json:"format" binding:"omitempty,oneof=csv pdf"`
+}
@@ -4,16 +4,29 @@ import (
 	"time"
 )
 
+// Review visibility states. Only published reviews are listed publicly;
+// authors keep seeing their own pending and shadow-hidden reviews.
+const (
+	ReviewVisibilityPublished    = "published"
+	ReviewVisibilityPending      = "pending"
+	ReviewVisibilityShadowHidden = "shadow_hidden"
+	ReviewVisibilityRemoved      = "removed"
+)
+
 type Review struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	UserID    uint      `json:"user_id" gorm:"not null"`
-	ProductID uint      `json:"product_id" gorm:"not nullconstraint:OnDelete:CASCADE;"`
-	Rating    int       `json:"rating" gorm:"check:rating >= 1 AND rating <= 5"`
-	Comment   string    `json:"comment"`
-	IsFlagged bool      `json:"is_flagged" gorm:"default:false"`
-	IsActive  bool      `json:"is_active" gorm:"default:true"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	UserID      uint       `json:"user_id" gorm:"not null"`
+	ProductID   uint       `json:"product_id" gorm:"not nullconstraint:OnDelete:CASCADE;"`
+	Rating      int        `json:"rating" gorm:"check:rating >= 1 AND rating <= 5"`
+	Comment     string     `json:"comment"`
+	IsFlagged   bool       `json:"is_flagged" gorm:"default:false"`
+	Visibility  string     `json:"visibility" gorm:"default:'published';index"`
+	IsAnonymous bool       `json:"is_anonymous" gorm:"default:false"` // hides the author's name on public listings
+	EditedAt    *time.Time `json:"edited_at,omitempty"`               // last change of rating or comment by the author
+	LikeCount    int       `json:"like_count" gorm:"default:0"`
+	DislikeCount int       `json:"dislike_count" gorm:"default:0"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 
 	// Relations
 	User    User         `json:"user,omitempty"`
@@ -32,6 +45,33 @@ type ReviewLike struct {
 	Review Review `json:"review,omitempty"`
 }
 
+// ReviewModeration records every visibility transition made by a moderator
+type ReviewModeration struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	ReviewID       uint      `json:"review_id" gorm:"not null;index"`
+	ModeratorID    uint      `json:"moderator_id"`
+	FromVisibility string    `json:"from_visibility"`
+	ToVisibility   string    `json:"to_visibility"`
+	Reason         string    `json:"reason" gorm:"type:text"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ReviewEdit keeps the rating and comment a review had before each edit, so
+// moderators can see what was changed
+type ReviewEdit struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	ReviewID        uint      `json:"review_id" gorm:"not null;index"`
+	EditorID        uint      `json:"editor_id"`
+	PreviousRating  int       `json:"previous_rating"`
+	PreviousComment string    `json:"previous_comment" gorm:"type:text"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type UpdateReviewVisibilityRequest struct {
+	Visibility string `json:"visibility" binding:"required,oneof=published pending shadow_hidden removed"`
+	Reason     string `json:"reason"`
+}
+
 // Ensure one like/dislike per user per review
 func (ReviewLike) TableName() string {
 	return "review_likes"
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+)
+
+// SavedSearch is a named product filter kept by a customer. With Notify set,
+// the customer is emailed when a newly published product matches it.
+type SavedSearch struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	UserID         uint       `json:"user_id" gorm:"not null;index"`
+	Name           string     `json:"name" gorm:"not null"`
+	Category       string     `json:"category,omitempty"`
+	Material       string     `json:"material,omitempty"`
+	Search         string     `json:"search,omitempty"`
+	MinPrice       float64    `json:"min_price,omitempty"`
+	MaxPrice       float64    `json:"max_price,omitempty"`
+	Notify         bool       `json:"notify" gorm:"default:false;index"`
+	LastNotifiedAt *time.Time `json:"last_notified_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	User User `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// SavedSearchMatch records that a customer was told about a product, so a
+// product that is edited or re-published is only announced once per search.
+type SavedSearchMatch struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	SavedSearchID uint      `json:"saved_search_id" gorm:"not null;uniqueIndex:idx_saved_search_product"`
+	ProductID     uint      `json:"product_id" gorm:"not null;uniqueIndex:idx_saved_search_product"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	SavedSearch SavedSearch `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+type CreateSavedSearchRequest struct {
+	Name     string  `json:"name" binding:"required,max=100"`
+	Category string  `json:"category" binding:"max=100"`
+	Material string  `json:"material" binding:"max=100"`
+	Search   string  `json:"search" binding:"max=255"`
+	MinPrice float64 `json:"min_price" binding:"gte=0"`
+	MaxPrice float64 `json:"max_price" binding:"gte=0"`
+	Notify   bool    `json:"notify"`
+}
+
+type UpdateSavedSearchRequest struct {
+	Name     *string  `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Category *string  `json:"category,omitempty" binding:"omitempty,max=100"`
+	Material *string  `json:"material,omitempty" binding:"omitempty,max=100"`
+	Search   *string  `json:"search,omitempty" binding:"omitempty,max=255"`
+	MinPrice *float64 `json:"min_price,omitempty" binding:"omitempty,gte=0"`
+	MaxPrice *float64 `json:"max_price,omitempty" binding:"omitempty,gte=0"`
+	Notify   *bool    `json:"notify,omitempty"`
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+)
+
+// Stock statuses of a delivery estimate
+const (
+	DeliveryInStock    = "in_stock"
+	DeliveryOutOfStock = "out_of_stock"
+)
+
+// ShippingRate is one row of a carrier rate table: what a carrier service
+// charges and how many business days it takes to reach a destination. An
+// empty Country or PostcodePrefix matches every destination; the most
+// specific matching row of a carrier service wins.
+type ShippingRate struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	StoreID        *uint     `json:"store_id,omitempty" gorm:"index"`
+	Carrier        string    `json:"carrier" gorm:"not null"`
+	Service        string    `json:"service" gorm:"not null"` // e.g. standard, express
+	Country        string    `json:"country,omitempty" gorm:"index"`
+	PostcodePrefix string    `json:"postcode_prefix,omitempty"`
+	Price          float64   `json:"price"`
+	MinDays        int       `json:"min_days"`
+	MaxDays        int       `json:"max_days"`
+	IsActive       bool      `json:"is_active" gorm:"default:true"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	Store *Store `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+type ShippingRateRequest struct {
+	Carrier        string   `json:"carrier" binding:"required,max=100"`
+	Service        string   `json:"service" binding:"required,max=100"`
+	Country        string   `json:"country" binding:"omitempty,len=2"`
+	PostcodePrefix string   `json:"postcode_prefix" binding:"max=20"`
+	Price          *float64 `json:"price" binding:"required,gte=0"`
+	MinDays        int      `json:"min_days" binding:"gte=0"`
+	MaxDays        int      `json:"max_days" binding:"gtefield=MinDays"`
+	IsActive       *bool    `json:"is_active"`
+}
+
+type UpdateShippingRateRequest struct {
+	Carrier        *string  `json:"carrier" binding:"omitempty,min=1,max=100"`
+	Service        *string  `json:"service" binding:"omitempty,min=1,max=100"`
+	Country        *string  `json:"country" binding:"omitempty,len=2|len=0"`
+	PostcodePrefix *string  `json:"postcode_prefix" binding:"omitempty,max=20"`
+	Price          *float64 `json:"price" binding:"omitempty,gte=0"`
+	MinDays        *int     `json:"min_days" binding:"omitempty,gte=0"`
+	MaxDays        *int     `json:"max_days" binding:"omitempty,gte=0"`
+	IsActive       *bool    `json:"is_active"`
+}
+
+// DeliveryOption is one way a product can be delivered. The dates are only
+// set when the product is in stock.
+type DeliveryOption struct {
+	Carrier      string  `json:"carrier"`
+	Service      string  `json:"service"`
+	Price        float64 `json:"price"`
+	MinDays      int     `json:"min_days"` // business days in transit
+	MaxDays      int     `json:"max_days"`
+	EarliestDate string  `json:"earliest_date,omitempty"` // YYYY-MM-DD
+	LatestDate   string  `json:"latest_date,omitempty"`
+}
+
+// DeliveryEstimate answers when a product would arrive at a postcode
+type DeliveryEstimate struct {
+	ProductID    uint             `json:"product_id"`
+	Quantity     int              `json:"quantity"`
+	Postcode     string           `json:"postcode"`
+	Country      string           `json:"country,omitempty"`
+	StockStatus  string           `json:"stock_status"`
+	ShipsFrom    string           `json:"ships_from,omitempty"` // the store location stock is sent from, if not online stock
+	DispatchDate string           `json:"dispatch_date,omitempty"`
+	Options      []DeliveryOption `json:"options"`
+}
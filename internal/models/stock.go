@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+)
+
+// Stock movement reason codes
+const (
+	StockReasonInitial    = "initial"
+	StockReasonRestock    = "restock"
+	StockReasonSale       = "sale"
+	StockReasonReturn     = "return"
+	StockReasonDamage     = "damage"
+	StockReasonLoss       = "loss"
+	StockReasonCorrection = "correction"
+)
+
+// StockMovement is an append-only ledger entry for every stock change, so the
+// current stock of a product can always be explained. Movements with a
+// LocationID change that store location's stock rather than online stock.
+type StockMovement struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ProductID  uint      `json:"product_id" gorm:"not null;index"`
+	LocationID *uint     `json:"location_id,omitempty" gorm:"index"`
+	Delta      int       `json:"delta"`
+	StockAfter int       `json:"stock_after"`
+	Reason     string    `json:"reason" gorm:"not null;index"`
+	Reference  string    `json:"reference,omitempty"` // e.g. an order or supplier invoice number
+	Note       string    `json:"note,omitempty" gorm:"type:text"`
+	CreatedBy  uint      `json:"created_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type StockAdjustmentRequest struct {
+	Delta     int    `json:"delta" binding:"required"`
+	Reason    string `json:"reason" binding:"required,oneof=restock sale return damage loss correction"`
+	Reference string `json:"reference"`
+	Note      string `json:"note"`
+}
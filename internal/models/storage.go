@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+)
+
+// StorageReport is the result of one S3 reconciliation run: objects with no
+// Image row (orphans) and Image rows whose object is gone (missing).
+type StorageReport struct {
+	ID                uint                 `json:"id" gorm:"primaryKey"`
+	Prefix            string               `json:"prefix"`
+	Trigger           string               `json:"trigger"` // schedule or manual
+	ScannedObjects    int                  `json:"scanned_objects"`
+	ScannedBytes      int64                `json:"scanned_bytes"`
+	ReferencedObjects int                  `json:"referenced_objects"`
+	OrphanObjects     int                  `json:"orphan_objects"`
+	OrphanBytes       int64                `json:"orphan_bytes"`
+	PendingOrphans    int                  `json:"pending_orphans"` // orphans still inside the grace period
+	DeletedOrphans    int                  `json:"deleted_orphans"`
+	MissingImages     int                  `json:"missing_images"`
+	Orphans           []string             `json:"orphans,omitempty" gorm:"type:text;serializer:json"`
+	Missing           []MissingImageRecord `json:"missing,omitempty" gorm:"type:text;serializer:json"`
+	Truncated         bool                 `json:"truncated"`
+	Error             string               `json:"error,omitempty"`
+	StartedAt         time.Time            `json:"started_at"`
+	FinishedAt        *time.Time           `json:"finished_at,omitempty"`
+}
+
+type MissingImageRecord struct {
+	ImageID   string `json:"image_id"`
+	ProductID uint   `json:"product_id"`
+	S3Key     string `json:"s3_key"`
+}
+
+// PendingDeletion is an S3 object queued for deletion in the same transaction
+// that stopped referencing it. A background worker deletes the object and
+// then the row, retrying failures with backoff.
+type PendingDeletion struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	S3Key         string    `json:"s3_key" gorm:"not null"`
+	Reason        string    `json:"reason"` // e.g. product_deleted, image_replaced
+	Attempts      int       `json:"attempts" gorm:"default:0"`
+	LastError     string    `json:"last_error,omitempty" gorm:"type:text"`
+	NextAttemptAt time.Time `json:"next_attempt_at" gorm:"index"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ImageObject is one product image stored in S3, shared by every Image row
+// with the same content. RefCount is the number of active images using it;
+// the object is queued for deletion when it drops to zero.
+type ImageObject struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	SHA256      string    `json:"sha256" gorm:"column:sha256;not null;uniqueIndex"`
+	S3Key       string    `json:"s3_key" gorm:"not null;uniqueIndex"`
+	S3URL       string    `json:"s3_url" gorm:"not null"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	RefCount    int       `json:"ref_count" gorm:"not null;default:0"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// Store is one storefront of a multi-tenant deployment. Requests are matched
+// to a store by the tenant header (slug) or by Domain.
+type Store struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"not null"`
+	Slug      string    `json:"slug" gorm:"not null;uniqueIndex"`
+	Domain    string    `json:"domain,omitempty" gorm:"index:idx_stores_domain,unique,where:domain <> ''"`
+	IsActive  bool      `json:"is_active" gorm:"default:true"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CreateStoreRequest struct {
+	Name     string `json:"name" binding:"required,max=100"`
+	Slug     string `json:"slug" binding:"omitempty,max=100"`
+	Domain   string `json:"domain" binding:"omitempty,hostname"`
+	IsActive *bool  `json:"is_active,omitempty"`
+}
+
+type UpdateStoreRequest struct {
+	Name     *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Slug     *string `json:"slug,omitempty" binding:"omitempty,min=1,max=100"`
+	Domain   *string `json:"domain,omitempty" binding:"omitempty,hostname"`
+	IsActive *bool   `json:"is_active,omitempty"`
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"time"
+)
+
+// StoreLocation is a physical shop or pickup point. Its stock is tracked in
+// LocationStock, separately from the product's online stock.
+type StoreLocation struct {
+	ID            uint           `json:"id" gorm:"primaryKey"`
+	StoreID       *uint          `json:"store_id,omitempty" gorm:"index"`
+	Name          string         `json:"name" gorm:"not null"`
+	AddressLine1  string         `json:"address_line1" gorm:"not null"`
+	AddressLine2  string         `json:"address_line2,omitempty"`
+	City          string         `json:"city" gorm:"not null"`
+	Region        string         `json:"region,omitempty"`
+	Postcode      string         `json:"postcode" gorm:"index"`
+	Country       string         `json:"country" gorm:"not null"` // ISO 3166-1 alpha-2
+	Latitude      *float64       `json:"latitude,omitempty"`
+	Longitude     *float64       `json:"longitude,omitempty"`
+	Phone         string         `json:"phone,omitempty"`
+	Hours         []OpeningHours `json:"hours" gorm:"type:text;serializer:json"`
+	PickupEnabled bool           `json:"pickup_enabled" gorm:"default:true"`
+	IsActive      bool           `json:"is_active" gorm:"default:true"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+
+	Store *Store `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// OpeningHours is one opening period of a weekday in the location's local
+// time. A day can have several periods; days without any are closed.
+type OpeningHours struct {
+	Day    int    `json:"day" binding:"min=0,max=6"` // 0 is Sunday
+	Opens  string `json:"opens" binding:"required"`  // HH:MM
+	Closes string `json:"closes" binding:"required"` // HH:MM
+}
+
+// LocationStock is how many units of a product a location holds
+type LocationStock struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	LocationID uint      `json:"location_id" gorm:"not null;uniqueIndex:idx_location_stock"`
+	ProductID  uint      `json:"product_id" gorm:"not null;uniqueIndex:idx_location_stock;index"`
+	Quantity   int       `json:"quantity" gorm:"not null;default:0"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	Location *StoreLocation `json:"-" gorm:"foreignKey:LocationID;constraint:OnDelete:CASCADE"`
+	Product  *Product       `json:"product,omitempty" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// PickupLocation is a location offered at checkout. Available and
+// DistanceKm are only set when the request asked for a product or gave
+// coordinates.
+type PickupLocation struct {
+	StoreLocation
+	Available  *bool    `json:"available,omitempty"`
+	Quantity   *int     `json:"quantity,omitempty"`
+	DistanceKm *float64 `json:"distance_km,omitempty"`
+}
+
+type CreateStoreLocationRequest struct {
+	Name          string         `json:"name" binding:"required,max=100"`
+	AddressLine1  string         `json:"address_line1" binding:"required,max=200"`
+	AddressLine2  string         `json:"address_line2" binding:"max=200"`
+	City          string         `json:"city" binding:"required,max=100"`
+	Region        string         `json:"region" binding:"max=100"`
+	Postcode      string         `json:"postcode" binding:"max=20"`
+	Country       string         `json:"country" binding:"required,len=2"`
+	Latitude      *float64       `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude     *float64       `json:"longitude" binding:"omitempty,min=-180,max=180"`
+	Phone         string         `json:"phone" binding:"max=30"`
+	Hours         []OpeningHours `json:"hours" binding:"dive"`
+	PickupEnabled *bool          `json:"pickup_enabled"`
+	IsActive      *bool          `json:"is_active"`
+}
+
+type UpdateStoreLocationRequest struct {
+	Name          *string         `json:"name" binding:"omitempty,min=1,max=100"`
+	AddressLine1  *string         `json:"address_line1" binding:"omitempty,min=1,max=200"`
+	AddressLine2  *string         `json:"address_line2" binding:"omitempty,max=200"`
+	City          *string         `json:"city" binding:"omitempty,min=1,max=100"`
+	Region        *string         `json:"region" binding:"omitempty,max=100"`
+	Postcode      *string         `json:"postcode" binding:"omitempty,max=20"`
+	Country       *string         `json:"country" binding:"omitempty,len=2"`
+	Latitude      *float64        `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude     *float64        `json:"longitude" binding:"omitempty,min=-180,max=180"`
+	Phone         *string         `json:"phone" binding:"omitempty,max=30"`
+	Hours         *[]OpeningHours `json:"hours" binding:"omitempty,dive"`
+	PickupEnabled *bool           `json:"pickup_enabled"`
+	IsActive      *bool           `json:"is_active"`
+}
+
+// LocationStockAdjustmentRequest changes a location's stock of a product
+// with the same reason codes as online stock
+type LocationStockAdjustmentRequest struct {
+	ProductID uint   `json:"product_id" binding:"required"`
+	Delta     int    `json:"delta" binding:"required"`
+	Reason    string `json:"reason" binding:"required,oneof=restock sale return damage loss correction"`
+	Reference string `json:"reference"`
+	Note      string `json:"note"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+)
+
+// TokenCleanupRun records one pass of the token cleanup job, so the admin
+// dashboard can show when it last ran and how much it removed
+type TokenCleanupRun struct {
+	ID                  uint       `json:"id" gorm:"primaryKey"`
+	RefreshTokens       int64      `json:"refresh_tokens"`        // expired, or revoked past the retention period
+	PasswordResetTokens int64      `json:"password_reset_tokens"` // used or expired
+	Error               string     `json:"error,omitempty"`
+	StartedAt           time.Time  `json:"started_at" gorm:"index"`
+	FinishedAt          *time.Time `json:"finished_at,omitempty"`
+}
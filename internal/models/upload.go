@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	UploadStatusUploading = "uploading"
+	UploadStatusCompleted = "completed"
+	UploadStatusAborted   = "aborted"
+	UploadStatusExpired   = "expired"
+)
+
+// UploadSession is a resumable upload backed by an S3 multipart upload. The
+// client sends numbered parts in any order, retrying any that fail, and
+// completes the session once every part is in.
+type UploadSession struct {
+	ID          uuid.UUID    `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CreatedBy   uint         `json:"created_by" gorm:"not null;index"`
+	ProductID   *uint        `json:"product_id,omitempty" gorm:"index"` // image uploads become a product image on completion
+	FileName    string       `json:"file_name" gorm:"not null"`
+	ContentType string       `json:"content_type" gorm:"not null"`
+	Size        int64        `json:"size"`      // declared total size in bytes
+	PartSize    int64        `json:"part_size"` // every part but the last has exactly this size
+	PartCount   int          `json:"part_count"`
+	Parts       []UploadPart `json:"parts" gorm:"type:text;serializer:json"`
+	S3Key       string       `json:"s3_key" gorm:"not null"`
+	S3UploadID  string       `json:"-" gorm:"not null"`
+	Status      string       `json:"status" gorm:"default:'uploading';index"`
+	ImageID     *uuid.UUID   `json:"image_id,omitempty" gorm:"type:uuid"`
+	URL         string       `json:"url,omitempty"` // set once completed
+	ExpiresAt   time.Time    `json:"expires_at" gorm:"index"`
+	CompletedAt *time.Time   `json:"completed_at,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// UploadPart is a part S3 accepted
+type UploadPart struct {
+	Number int    `json:"number"`
+	Size   int64  `json:"size"`
+	ETag   string `json:"etag"`
+}
+
+func (u *UploadSession) BeforeCreate(tx *gorm.DB) error {
+	if u.ID == uuid.Nil {
+		u.ID = uuid.New()
+	}
+	return nil
+}
+
+// UploadedBytes is the total size of the parts received so far
+func (u *UploadSession) UploadedBytes() int64 {
+	var total int64
+	for _, part := range u.Parts {
+		total += part.Size
+	}
+	return total
+}
+
+type CreateUploadRequest struct {
+	FileName    string `json:"file_name" binding:"required"`
+	ContentType string `json:"content_type" binding:"required"`
+	Size        int64  `json:"size" binding:"required,gt=0"`
+	ProductID   *uint  `json:"product_id,omitempty"`
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
@@ -8,26 +9,74 @@ import (
 
 type User struct {
 	ID           uint      `json:"id" gorm:"primaryKey"`
-	Email        string    `json:"email" gorm:"unique;not null"`
+	Email        string    `json:"email" gorm:"not null;index"` // unique per store, see database.migrateStoreScopedEmails
 	Password     string    `json:"-" gorm:"not null"` // Hide password in JSON
 	FirstName    string    `json:"first_name"`
 	LastName     string    `json:"last_name"`
-	PhoneNumber  string    `json:"phone_number"`
+	PhoneNumber  EncryptedString `json:"phone_number"` // encrypted at rest
 	Role         string    `json:"role" gorm:"default:customer"`
 	IsActive     bool      `json:"is_active" gorm:"default:true"`
+	VendorID     *uint     `json:"vendor_id,omitempty" gorm:"index"` // set for vendor-role users
+	StoreID      *uint     `json:"store_id,omitempty" gorm:"index"`  // store the user signed up in; nil users may sign in to any store
+	CustomerGroupID *uint  `json:"customer_group_id,omitempty" gorm:"index"` // members see the group's prices
+	MergedIntoID *uint     `json:"merged_into_id,omitempty"` // set when the account was merged into another and deactivated
+	AvatarURL    string    `json:"avatar_url"`
+	AvatarKey    string    `json:"-"` // S3 key of the uploaded avatar, empty when none
+	PasswordChangedAt *time.Time `json:"-"` // nil until the first change; expiry then counts from CreatedAt
+	Version      int       `json:"version" gorm:"not null;default:1"` // bumped by every profile update, for optimistic locking
+	Country      string    `json:"country,omitempty"` // ISO 3166 code given at signup
+	SignupIP       string   `json:"-" gorm:"index"`
+	SignupDeviceID string   `json:"-" gorm:"index"`
+	RiskScore      int      `json:"-" gorm:"default:0"` // from fraud screening at signup
+	RiskReasons    []string `json:"-" gorm:"type:text;serializer:json"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 	
 	// Add refresh token fields
 	RefreshTokens []RefreshToken `json:"-" gorm:"foreignKey:UserID"`
+	Vendor        *Vendor        `json:"-" gorm:"constraint:OnDelete:SET NULL"`
+	Store         *Store         `json:"-" gorm:"constraint:OnDelete:RESTRICT"`
+	CustomerGroup *CustomerGroup `json:"-" gorm:"constraint:OnDelete:SET NULL"`
 }
 
-// New RefreshToken model
+// PasswordHistory keeps previous password hashes of users under the password
+// policy so recent passwords cannot be reused
+type PasswordHistory struct {
+	ID           uint      `gorm:"primaryKey"`
+	UserID       uint      `gorm:"not null;index"`
+	PasswordHash string    `gorm:"not null"`
+	CreatedAt    time.Time `gorm:"index"`
+
+	User User `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// UpdateUserRoleRequest promotes a customer to admin or demotes an admin.
+// Vendor users are managed through the vendor endpoints.
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin customer"`
+}
+
+// MergeUsersRequest names the duplicate account folded into the one in the URL
+type MergeUsersRequest struct {
+	DuplicateUserID uint `json:"duplicate_user_id" binding:"required"`
+}
+
+// UserMergeResult reports how many records of each kind moved to the
+// surviving account
+type UserMergeResult struct {
+	User         *User            `json:"user"`
+	MergedUserID uint             `json:"merged_user_id"`
+	Moved        map[string]int64 `json:"moved"`
+}
+
+// RefreshToken stores only a SHA-256 hash of the token. Tokens rotated from
+// the same login share a FamilyID so a replayed token can revoke them all.
 type RefreshToken struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
 	UserID    uint      `json:"user_id" gorm:"not null"`
-	Token     string    `json:"token" gorm:"unique;not null"`
-	ExpiresAt time.Time `json:"expires_at" gorm:"not null"`
+	TokenHash string    `json:"-" gorm:"uniqueIndex"`
+	FamilyID  string    `json:"family_id" gorm:"index"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
 	IsRevoked bool      `json:"is_revoked" gorm:"default:false"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -36,6 +85,20 @@ type RefreshToken struct {
 	User User `json:"-" gorm:"foreignKey:UserID"`
 }
 
+// DefaultAvatarURL is returned for users without an uploaded avatar. It is
+// set from config at startup and never stored on the user row.
+var DefaultAvatarURL string
+
+// MarshalJSON fills in the default avatar so clients always get an image URL
+func (u User) MarshalJSON() ([]byte, error) {
+	type user User
+	out := user(u)
+	if out.AvatarURL == "" {
+		out.AvatarURL = DefaultAvatarURL
+	}
+	return json.Marshal(out)
+}
+
 // BeforeCreate hook for password hashing
 func (u *User) BeforeCreate(tx *gorm.DB) error {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+)
+
+// Notification channels a user can receive an event on
+const (
+	ChannelEmail = "email"
+	ChannelSMS   = "sms"
+)
+
+// Notification event types users can opt in or out of. Security messages
+// such as password resets and one-time codes are always sent.
+const (
+	NotificationSavedSearchMatch = "saved_search_match"
+)
+
+// NotificationChannels lists the channels each event type may use
+var NotificationChannels = map[string][]string{
+	NotificationSavedSearchMatch: {ChannelEmail},
+}
+
+// DefaultNotificationSettings apply to users who never saved preferences
+func DefaultNotificationSettings() map[string][]string {
+	return map[string][]string{
+		NotificationSavedSearchMatch: {ChannelEmail},
+	}
+}
+
+// UserPreference holds a user's storefront and notification settings.
+// Notifications maps an event type to the channels it is delivered on; an
+// event type with no entry uses its default channels.
+type UserPreference struct {
+	ID              uint                `json:"-" gorm:"primaryKey"`
+	UserID          uint                `json:"user_id" gorm:"not null;uniqueIndex"`
+	MarketingEmails bool                `json:"marketing_emails" gorm:"default:false"`
+	Currency        string              `json:"currency" gorm:"size:3;default:'USD'"`
+	Locale          string              `json:"locale" gorm:"size:16;default:'en'"`
+	Notifications   map[string][]string `json:"notifications" gorm:"type:text;serializer:json"`
+	CreatedAt       time.Time           `json:"created_at"`
+	UpdatedAt       time.Time           `json:"updated_at"`
+
+	User User `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// UpdatePreferencesRequest changes only the fields that are set. Each
+// notifications entry replaces the channels of that event type; an empty
+// list turns the event off.
+type UpdatePreferencesRequest struct {
+	MarketingEmails *bool               `json:"marketing_emails"`
+	Currency        *string             `json:"currency" binding:"omitempty,len=3"`
+	Locale          *string             `json:"locale" binding:"omitempty,min=2,max=16"`
+	Notifications   map[string][]string `json:"notifications"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+)
+
+// RoleVendor is the user role of a vendor's staff. Vendor users manage only
+// their own vendor's products through the admin API.
+const RoleVendor = "vendor"
+
+// Vendor is a seller whose products are listed in the catalogue. Every field
+// is public; product responses embed the vendor.
+type Vendor struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"not null"`
+	Slug        string    `json:"slug" gorm:"not null;uniqueIndex"`
+	Description string    `json:"description,omitempty"`
+	LogoURL     string    `json:"logo_url,omitempty"`
+	IsActive    bool      `json:"is_active" gorm:"default:true"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CreateVendorRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Slug        string `json:"slug" binding:"omitempty,max=100"`
+	Description string `json:"description"`
+	LogoURL     string `json:"logo_url" binding:"omitempty,url"`
+	IsActive    *bool  `json:"is_active,omitempty"`
+}
+
+type UpdateVendorRequest struct {
+	Name        *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Slug        *string `json:"slug,omitempty" binding:"omitempty,min=1,max=100"`
+	Description *string `json:"description,omitempty"`
+	LogoURL     *string `json:"logo_url,omitempty" binding:"omitempty,url"`
+	IsActive    *bool   `json:"is_active,omitempty"`
+}
+
+// AssignVendorUserRequest gives a user the vendor role for a vendor
+type AssignVendorUserRequest struct {
+	UserID uint `json:"user_id" binding:"required"`
+}
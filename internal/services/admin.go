@@ -9,10 +9,14 @@ import (
 	"mime/multipart"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/princeprakhar/ecommerce-backend/internal/config"
 	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"github.com/princeprakhar/ecommerce-backend/pkg/metrics"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
@@ -30,21 +34,28 @@ type AdminService struct {
 	cfg            *config.Config
 	emailService   *EmailService
 	s3Service      *S3Service
+	jobService     *JobService
+	log            logger.Logger
+
+	statsMu sync.Mutex
+	stats   *DashboardStats
 }
 
-func NewAdminService(db *gorm.DB, cfg *config.Config, fastAPIService *FastAPIService, emailService *EmailService) *AdminService {
+func NewAdminService(db *gorm.DB, cfg *config.Config, fastAPIService *FastAPIService, emailService *EmailService, jobService *JobService, log logger.Logger) *AdminService {
 	return &AdminService{
 		db:             db,
 		cfg:            cfg,
 		fastAPIService: fastAPIService,
 		emailService:   emailService,
-		s3Service:      NewS3Service(cfg.S3Region, cfg.S3BucketName, cfg.S3AccessKey, cfg.S3SecretKey),
+		jobService:     jobService,
+		s3Service:      NewS3Service(cfg.S3Region, cfg.S3Endpoint, cfg.S3BucketName, cfg.S3AccessKey, cfg.S3SecretKey, log.WithFields(map[string]interface{}{"service": "s3"})),
+		log:            log,
 	}
 }
 
-func (s *AdminService) CreateProduct(productReq *models.CreateProductRequest, imageFiles []*multipart.FileHeader) (*models.Product, error) {
+func (s *AdminService) CreateProduct(ctx context.Context, productReq *models.CreateProductRequest, imageFiles []*multipart.FileHeader) (*models.Product, error) {
 	if productReq == nil {
-		return nil, errors.New("product request cannot be nil")
+		return nil, fmt.Errorf("%w: product request cannot be nil", ErrInvalidInput)
 	}
 
 	// Validate product data
@@ -52,8 +63,12 @@ func (s *AdminService) CreateProduct(productReq *models.CreateProductRequest, im
 		return nil, err
 	}
 
+	// Set context timeout; it also bounds the image uploads
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
 	// Start database transaction
-	tx := s.db.Begin()
+	tx := s.db.WithContext(ctx).Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -63,6 +78,7 @@ func (s *AdminService) CreateProduct(productReq *models.CreateProductRequest, im
 	// Create product first
 	product := &models.Product{
 		Title:       productReq.Title,
+		SKU:         strings.TrimSpace(productReq.SKU),
 		Description: productReq.Description,
 		Price:       productReq.Price,
 		Category:    productReq.Category,
@@ -70,6 +86,10 @@ func (s *AdminService) CreateProduct(productReq *models.CreateProductRequest, im
 		Material:    productReq.Material,
 		Status:      productReq.Status,
 		Stock:       productReq.Stock,
+		MinOrderQty: productReq.MinOrderQty,
+		MaxOrderQty: productReq.MaxOrderQty,
+		VendorID:    vendorIDOrNil(productReq.VendorID),
+		StoreID:     productReq.StoreID,
 		Images:      []models.Image{},
 		Services:    []models.Service{},
 	}
@@ -78,8 +98,8 @@ func (s *AdminService) CreateProduct(productReq *models.CreateProductRequest, im
 		// Handle services if provided
 		for _, svc := range productReq.Services {
 			service := models.Service{
-				Name: svc.Name,
-				Link: svc.Link,
+				Name: strings.TrimSpace(svc.Name),
+				Link: strings.TrimSpace(svc.Link),
 			}
 			product.Services = append(product.Services, service)
 		}
@@ -89,10 +109,16 @@ func (s *AdminService) CreateProduct(productReq *models.CreateProductRequest, im
 		tx.Rollback()
 		return nil, fmt.Errorf("failed to create product: %v", err)
 	}
+	if product.Stock > 0 {
+		if _, err := recordStockMovement(tx, product.ID, product.Stock, product.Stock, models.StockReasonInitial, "", "", 0); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
 
 	// Upload images if provided
 	if len(imageFiles) > 0 {
-		uploadResults, err := s.s3Service.UploadMultipleImages(imageFiles)
+		uploadResults, err := uploadProductImages(ctx, s.db, s.s3Service, imageFiles)
 		if err != nil {
 			tx.Rollback()
 			return nil, fmt.Errorf("failed to upload images: %v", err)
@@ -108,19 +134,20 @@ func (s *AdminService) CreateProduct(productReq *models.CreateProductRequest, im
 				S3URL:       result.URL,
 				ContentType: result.ContentType,
 				Size:        result.Size,
+				SHA256:      result.SHA256,
 				IsActive:    true,
 			}
 			images = append(images, image)
 		}
 
+		if err := retainImageObjects(tx, images); err != nil {
+			tx.Rollback()
+			s.discardUploads(ctx, uploadResults)
+			return nil, err
+		}
 		if err := tx.Create(&images).Error; err != nil {
 			tx.Rollback()
-			// Clean up uploaded files
-			var keys []string
-			for _, result := range uploadResults {
-				keys = append(keys, result.Key)
-			}
-			s.s3Service.DeleteMultipleImages(keys)
+			s.discardUploads(ctx, uploadResults)
 			return nil, fmt.Errorf("failed to create image records: %v", err)
 		}
 
@@ -128,20 +155,35 @@ func (s *AdminService) CreateProduct(productReq *models.CreateProductRequest, im
 
 	}
 
+	if err := recordOutboxEvent(tx, EventProductCreated, "product", product.ID, product); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %v", err)
 	}
+	metrics.ProductsCreated.WithLabelValues("form").Inc()
 
 	// Load the complete product with images
-	if err := s.db.Preload("Images").First(product, product.ID).Error; err != nil {
+	if err := s.db.WithContext(ctx).Preload("Images").First(product, product.ID).Error; err != nil {
 		return nil, fmt.Errorf("failed to load created product: %v", err)
 	}
 
 	return product, nil
 }
 
-func (s *AdminService) UpdateProduct(ctx context.Context, productID uint, updateReq *models.UpdateProductRequest, imageFiles []*multipart.FileHeader, deleteImageIDs []string) (*models.Product, error) {
+// UpdateProduct applies an edit by actorID and records the result as a new
+// product revision
+func (s *AdminService) UpdateProduct(ctx context.Context, actorID, productID uint, updateReq *models.UpdateProductRequest, imageFiles []*multipart.FileHeader, deleteImageIDs []string) (*models.Product, error) {
+	return s.updateProduct(ctx, productID, updateReq, imageFiles, deleteImageIDs, models.ProductRevision{
+		Source:  models.RevisionSourceUpdate,
+		ActorID: &actorID,
+	})
+}
+
+func (s *AdminService) updateProduct(ctx context.Context, productID uint, updateReq *models.UpdateProductRequest, imageFiles []*multipart.FileHeader, deleteImageIDs []string, revision models.ProductRevision) (*models.Product, error) {
 	// Input validation
 	if productID == 0 {
 		return nil, fmt.Errorf("%w: invalid product ID", ErrInvalidInput)
@@ -171,6 +213,10 @@ func (s *AdminService) UpdateProduct(ctx context.Context, productID uint, update
 		}
 		return nil, fmt.Errorf("%w: failed to find product: %v", ErrDatabaseQuery, err)
 	}
+	if err := recordBaselineRevision(tx, productID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 
 	// Build update data
 	updateData := make(map[string]interface{})
@@ -180,6 +226,10 @@ func (s *AdminService) UpdateProduct(ctx context.Context, productID uint, update
 		updateData["title"] = strings.TrimSpace(*updateReq.Title)
 		hasUpdates = true
 	}
+	if updateReq.SKU != nil {
+		updateData["sku"] = strings.TrimSpace(*updateReq.SKU)
+		hasUpdates = true
+	}
 	if updateReq.Description != nil {
 		updateData["description"] = strings.TrimSpace(*updateReq.Description)
 		hasUpdates = true
@@ -209,13 +259,42 @@ func (s *AdminService) UpdateProduct(ctx context.Context, productID uint, update
 			tx.Rollback()
 			return nil, fmt.Errorf("%w: stock cannot be negative", ErrInvalidInput)
 		}
-		updateData["stock"] = *updateReq.Stock
+		// Stock is written separately so the change lands in the ledger
 		hasUpdates = true
 	}
 	if updateReq.Size != nil {
 		updateData["size"] = strings.TrimSpace(*updateReq.Size)
 		hasUpdates = true
 	}
+	if updateReq.VendorID != nil {
+		updateData["vendor_id"] = vendorIDOrNil(updateReq.VendorID)
+		hasUpdates = true
+	}
+	if updateReq.OutOfStockDisplay != nil {
+		display := strings.TrimSpace(*updateReq.OutOfStockDisplay)
+		if display != models.OutOfStockDefault && display != models.OutOfStockShow && display != models.OutOfStockHide {
+			tx.Rollback()
+			return nil, fmt.Errorf("%w: out_of_stock_display must be show, hide or empty", ErrInvalidInput)
+		}
+		updateData["out_of_stock_display"] = display
+		hasUpdates = true
+	}
+	if updateReq.MinOrderQty != nil || updateReq.MaxOrderQty != nil {
+		minQty, maxQty := product.MinOrderQty, product.MaxOrderQty
+		if updateReq.MinOrderQty != nil {
+			minQty = *updateReq.MinOrderQty
+		}
+		if updateReq.MaxOrderQty != nil {
+			maxQty = *updateReq.MaxOrderQty
+		}
+		if err := validateOrderLimits(minQty, maxQty); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		updateData["min_order_qty"] = minQty
+		updateData["max_order_qty"] = maxQty
+		hasUpdates = true
+	}
 
 	// Add updated_at timestamp
 	if hasUpdates {
@@ -229,6 +308,12 @@ func (s *AdminService) UpdateProduct(ctx context.Context, productID uint, update
 			return nil, fmt.Errorf("%w: failed to update product: %v", ErrDatabaseQuery, err)
 		}
 	}
+	if updateReq.Stock != nil {
+		if err := setStockTx(tx, product.ID, *updateReq.Stock, models.StockReasonCorrection, "", 0); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
 
 	// Handle services update
 	if updateReq.Services != nil {
@@ -241,15 +326,16 @@ func (s *AdminService) UpdateProduct(ctx context.Context, productID uint, update
 		// Then, insert new services
 		var services []models.Service
 		for _, svc := range updateReq.Services {
-			if svc.Name == "" {
-				tx.Rollback()
-				return nil, fmt.Errorf("%w: service name cannot be empty", ErrInvalidInput)
-			}
-			services = append(services, models.Service{
+			service := models.Service{
 				ProductID: product.ID,
 				Name:      strings.TrimSpace(svc.Name),
 				Link:      strings.TrimSpace(svc.Link),
-			})
+			}
+			if err := validateService(service.Name, service.Link); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+			services = append(services, service)
 		}
 
 		if len(services) > 0 {
@@ -264,14 +350,20 @@ func (s *AdminService) UpdateProduct(ctx context.Context, productID uint, update
 	var keysToDelete []string
 	if len(deleteImageIDs) > 0 {
 		var imagesToDelete []models.Image
-		if err := tx.Where("product_id = ? AND id IN ?", productID, deleteImageIDs).Find(&imagesToDelete).Error; err != nil {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("product_id = ? AND id IN ? AND is_active = ?", productID, deleteImageIDs, true).
+			Find(&imagesToDelete).Error; err != nil {
 			tx.Rollback()
 			return nil, fmt.Errorf("%w: failed to find images to delete: %v", ErrDatabaseQuery, err)
 		}
 
-		for _, img := range imagesToDelete {
-			keysToDelete = append(keysToDelete, img.S3Key)
+		// Objects shared with other images stay until their last image goes
+		released, err := releaseImageObjects(tx, imagesToDelete)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
 		}
+		keysToDelete = released
 
 		// Soft delete images from database
 		if err := tx.Model(&models.Image{}).Where("product_id = ? AND id IN ?", productID, deleteImageIDs).Update("is_active", false).Error; err != nil {
@@ -290,7 +382,7 @@ func (s *AdminService) UpdateProduct(ctx context.Context, productID uint, update
 			}
 		}
 
-		uploadResults, err := s.s3Service.UploadMultipleImages(imageFiles)
+		uploadResults, err := uploadProductImages(ctx, s.db, s.s3Service, imageFiles)
 		if err != nil {
 			tx.Rollback()
 			return nil, fmt.Errorf("%w: failed to upload new images: %v", ErrS3Upload, err)
@@ -306,43 +398,46 @@ func (s *AdminService) UpdateProduct(ctx context.Context, productID uint, update
 				S3URL:       result.URL,
 				ContentType: result.ContentType,
 				Size:        result.Size,
+				SHA256:      result.SHA256,
 				IsActive:    true,
 			}
 			newImages = append(newImages, image)
 		}
 
+		if err := retainImageObjects(tx, newImages); err != nil {
+			tx.Rollback()
+			s.discardUploads(ctx, uploadResults)
+			return nil, err
+		}
+
 		if err := tx.Create(&newImages).Error; err != nil {
 			tx.Rollback()
-			// Clean up uploaded files
-			var keys []string
-			for _, result := range uploadResults {
-				keys = append(keys, result.Key)
-			}
-			go func() {
-				if cleanupErr := s.s3Service.DeleteMultipleImages(keys); cleanupErr != nil {
-					// Log cleanup error
-					fmt.Printf("Warning: Failed to cleanup uploaded images: %v\n", cleanupErr)
-				}
-			}()
+			s.discardUploads(ctx, uploadResults)
 			return nil, fmt.Errorf("%w: failed to create new image records: %v", ErrDatabaseQuery, err)
 		}
 	}
 
+	if err := recordProductRevision(tx, productID, revision); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Record the change with the product as it will be after commit
+	if err := publishProductUpdated(tx, productID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	// Removed images are deleted from S3 by the storage worker after commit
+	if err := queueS3Deletions(tx, DeletionReasonImageRemoved, keysToDelete); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("%w: failed to commit transaction: %v", ErrDatabaseQuery, err)
 	}
 
-	// Delete old images from S3 after successful database commit
-	if len(keysToDelete) > 0 {
-		go func() {
-			if err := s.s3Service.DeleteMultipleImages(keysToDelete); err != nil {
-				// Log error but don't fail the operation
-				fmt.Printf("Warning: Failed to delete images from S3: %v\n", err)
-			}
-		}()
-	}
-
 	// Load updated product with all relations
 	var updatedProduct models.Product
 	if err := s.db.WithContext(ctx).
@@ -380,13 +475,30 @@ func (s *AdminService) DeleteProduct(ctx context.Context, productID uint) error
 		return fmt.Errorf("%w: failed to find product: %v", ErrDatabaseQuery, err)
 	}
 
-	// Collect image S3 keys for deletion
-	var keysToDelete []string
+	// Refuse to hard-delete products that orders still point at
+	report, err := s.collectProductReferences(tx, &product)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if !report.CanDelete {
+		tx.Rollback()
+		return fmt.Errorf("%w (%s)", ErrProductHasReferences, report.Reason)
+	}
+
+	// Collect the S3 keys no other product's images use. Inactive images
+	// released their objects when they were removed.
+	var activeImages []models.Image
 	for _, img := range product.Images {
-		if img.S3Key != "" {
-			keysToDelete = append(keysToDelete, img.S3Key)
+		if img.IsActive {
+			activeImages = append(activeImages, img)
 		}
 	}
+	keysToDelete, err := releaseImageObjects(tx, activeImages)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
 
 	// 1. Delete review likes
 	// Delete review likes where the related review belongs to the product
@@ -428,29 +540,30 @@ if err := tx.Where("review_id IN (?)",
 		return fmt.Errorf("%w: failed to delete product: %v", ErrDatabaseQuery, err)
 	}
 
-	if err := tx.Commit().Error; err != nil {
-		return fmt.Errorf("%w: failed to commit transaction: %v", ErrDatabaseQuery, err)
+	if err := recordOutboxEvent(tx, EventProductDeleted, "product", productID, map[string]interface{}{"id": productID}); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := queueS3Deletions(tx, DeletionReasonProductDeleted, keysToDelete); err != nil {
+		tx.Rollback()
+		return err
 	}
 
-	// Delete images from S3 (async)
-	if len(keysToDelete) > 0 {
-		go func() {
-			if err := s.s3Service.DeleteMultipleImages(keysToDelete); err != nil {
-				fmt.Printf("Warning: Failed to delete images from S3 for product %d: %v\n", productID, err)
-			} else {
-				fmt.Printf("Successfully deleted %d images from S3 for product %d\n", len(keysToDelete), productID)
-			}
-		}()
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("%w: failed to commit transaction: %v", ErrDatabaseQuery, err)
 	}
 
 	return nil
 }
 
-func (s *AdminService) ProcessCSVUpload(file *multipart.FileHeader, adminEmail string) (*models.ProductUploadResponse, error) {
+// ProcessCSVUpload imports a CSV synchronously. Rows are created in batches
+// of IMPORT_BATCH_SIZE, so cancelling ctx stops the import and keeps the
+// batches created so far.
+func (s *AdminService) ProcessCSVUpload(ctx context.Context, file *multipart.FileHeader, adminEmail string) (*models.ProductUploadResponse, error) {
 	// Open CSV file
 	src, err := file.Open()
 	if err != nil {
-		return nil, errors.New("failed to open CSV file")
+		return nil, fmt.Errorf("%w: failed to open CSV file: %v", ErrInvalidInput, err)
 	}
 	defer src.Close()
 
@@ -458,16 +571,30 @@ func (s *AdminService) ProcessCSVUpload(file *multipart.FileHeader, adminEmail s
 	reader := csv.NewReader(src)
 	records, err := reader.ReadAll()
 	if err != nil {
-		return nil, errors.New("failed to parse CSV file")
+		return nil, fmt.Errorf("%w: failed to parse CSV file: %v", ErrInvalidInput, err)
 	}
 
 	if len(records) < 2 {
-		return nil, errors.New("CSV file must have header and at least one data row")
+		return nil, fmt.Errorf("%w: CSV file must have header and at least one data row", ErrInvalidInput)
 	}
 
 	// Expected CSV format: name,description,price,category,brand,sku,stock
 	processedCount := 0
 	var failedRows []string
+	batchSize := importBatchSize(s.cfg.ImportBatchSize)
+	var batch []*models.Product
+	var batchRows []int
+
+	flush := func() error {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("CSV import cancelled after %d products: %v", processedCount, err)
+		}
+		created, failed := s.createCSVProducts(ctx, batch, batchRows, batchSize)
+		processedCount += created
+		failedRows = append(failedRows, failed...)
+		batch, batchRows = batch[:0], batchRows[:0]
+		return nil
+	}
 
 	for i, record := range records[1:] { // Skip header
 		if len(record) < 7 {
@@ -486,7 +613,7 @@ func (s *AdminService) ProcessCSVUpload(file *multipart.FileHeader, adminEmail s
 			stock = 0
 		}
 
-		product := models.Product{
+		product := &models.Product{
 			Title:       strings.TrimSpace(record[0]),
 			Description: strings.TrimSpace(record[1]),
 			Price:       price,
@@ -498,12 +625,23 @@ func (s *AdminService) ProcessCSVUpload(file *multipart.FileHeader, adminEmail s
 			Images:      []models.Image{}, // No images in CSV upload
 		}
 
-		if err := s.db.Create(&product).Error; err == nil {
-			processedCount++
-		} else {
-			failedRows = append(failedRows, fmt.Sprintf("Row %d: %s", i+2, err.Error()))
+		batch = append(batch, product)
+		batchRows = append(batchRows, i+2)
+		if len(batch) == batchSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(batch) > 0 {
+		if err := flush(); err != nil {
+			return nil, err
 		}
 	}
+	metrics.ProductsCreated.WithLabelValues("csv").Add(float64(processedCount))
+
+	metrics.CSVRows.WithLabelValues(metrics.CSVRowImported).Add(float64(processedCount))
+	metrics.CSVRows.WithLabelValues(metrics.CSVRowFailed).Add(float64(len(failedRows)))
 
 	message := fmt.Sprintf("CSV processed successfully. %d products added", processedCount)
 	if len(failedRows) > 0 {
@@ -517,62 +655,206 @@ func (s *AdminService) ProcessCSVUpload(file *multipart.FileHeader, adminEmail s
 	}, nil
 }
 
-func (s *AdminService) GetProducts(page, limit int) ([]models.Product, error) {
+// discardUploads queues images uploaded for a transaction that rolled back.
+// The queue write uses its own connection since the transaction is gone.
+func (s *AdminService) discardUploads(ctx context.Context, results []*UploadResult) {
+	// Reused objects belong to other images
+	keys := uploadedKeys(results)
+	if err := queueS3Deletions(s.db, DeletionReasonUploadAborted, keys); err != nil {
+		s.log.WithContext(ctx).WithFields(map[string]interface{}{"s3_keys": keys}).Error("Failed to queue cleanup of uploaded images: ", err)
+	}
+}
+
+// createCSVProducts inserts a batch of CSV products with one statement. When
+// that fails, the rows are retried one by one so a single bad row only fails
+// itself; it returns how many were created and the failed rows.
+func (s *AdminService) createCSVProducts(ctx context.Context, products []*models.Product, rows []int, batchSize int) (int, []string) {
+	batchCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	err := s.db.WithContext(batchCtx).CreateInBatches(products, batchSize).Error
+	cancel()
+	if err == nil {
+		return len(products), nil
+	}
+
+	created := 0
+	var failed []string
+	for i, product := range products {
+		product.ID = 0
+		if err := s.createCSVProduct(ctx, product); err != nil {
+			failed = append(failed, fmt.Sprintf("Row %d: %s", rows[i], err.Error()))
+			continue
+		}
+		created++
+	}
+	return created, failed
+}
+
+func (s *AdminService) createCSVProduct(ctx context.Context, product *models.Product) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+	return s.db.WithContext(ctx).Create(product).Error
+}
+
+// ProductScope limits admin product queries to the caller's store and, for
+// vendor users, their vendor. Zero values leave a dimension unscoped.
+type ProductScope struct {
+	StoreID  uint
+	VendorID uint
+}
+
+func (p ProductScope) apply(db *gorm.DB) *gorm.DB {
+	return db.Scopes(storeScope(p.StoreID), vendorScope(p.VendorID))
+}
+
+// GetProducts lists products of any status within scope. A nil projection
+// loads active images, reviews and services.
+func (s *AdminService) GetProducts(ctx context.Context, page, limit int, scope ProductScope, proj *ProductProjection) ([]models.Product, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
 	var products []models.Product
+	var total int64
 	offset := (page - 1) * limit
+	db := s.db.WithContext(ctx)
 
-	err := s.db.Preload("Images", "is_active = ?", true).
-		Preload("Reviews").Preload("Services").
+	if err := scope.apply(db.Model(&models.Product{})).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := proj.apply(scope.apply(db), "is_active = ?", true).
 		Order("created_at DESC").
 		Offset(offset).
 		Limit(limit).
 		Find(&products).Error
 
-	return products, err
+	return products, total, err
+}
+
+// StreamProducts walks the whole catalogue in id order, handing each batch to
+// fn so callers can write it out without holding every product in memory.
+func (s *AdminService) StreamProducts(ctx context.Context, scope ProductScope, proj *ProductProjection, fn func([]models.Product) error) error {
+	var batch []models.Product
+	err := proj.apply(scope.apply(s.db.WithContext(ctx)), "is_active = ?", true).
+		Order("id ASC").
+		FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+	if err != nil {
+		return fmt.Errorf("%w: failed to stream products: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
+
+// DashboardStats summarises the catalogue for the admin dashboard
+type DashboardStats struct {
+	TotalProducts      int64                   `json:"total_products"` // every product that is not archived
+	ActiveProducts     int64                   `json:"active_products"`
+	InactiveProducts   int64                   `json:"inactive_products"`
+	ArchivedProducts   int64                   `json:"archived_products"`
+	OutOfStockProducts int64                   `json:"out_of_stock_products"` // active products with no stock left
+	TotalUsers         int64                   `json:"total_users"`
+	TotalReviews       int64                   `json:"total_reviews"`
+	FlaggedReviews     int64                   `json:"flagged_reviews"`
+	Vendors            []VendorStats           `json:"vendors"`                 // product counts per vendor
+	TokenCleanup       *models.TokenCleanupRun `json:"token_cleanup,omitempty"` // latest token cleanup run
+	GeneratedAt        time.Time               `json:"generated_at"`
 }
 
-func (s *AdminService) GetDashboardStats() (map[string]interface{}, error) {
-	var stats map[string]interface{} = make(map[string]interface{})
+// GetDashboardStats returns the dashboard counters, served from memory for
+// DashboardCacheTTL so dashboard refreshes don't each run a set of counts.
+// refresh bypasses the cache.
+func (s *AdminService) GetDashboardStats(ctx context.Context, refresh bool) (*DashboardStats, error) {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
 
-	// Total products
-	var totalProducts int64
-	s.db.Model(&models.Product{}).Where("is_active = ?", true).Count(&totalProducts)
-	stats["total_products"] = totalProducts
+	if !refresh && s.stats != nil && time.Since(s.stats.GeneratedAt) < s.cfg.DashboardCacheTTL {
+		return s.stats, nil
+	}
 
-	// Total users
-	var totalUsers int64
-	s.db.Model(&models.User{}).Where("is_active = ?", true).Count(&totalUsers)
-	stats["total_users"] = totalUsers
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+	db := s.db.WithContext(ctx)
+	stats := &DashboardStats{GeneratedAt: time.Now()}
+
+	var byStatus []struct {
+		Status string
+		Count  int64
+	}
+	if err := db.Model(&models.Product{}).Select("status, COUNT(*) AS count").Group("status").Scan(&byStatus).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to count products: %v", ErrDatabaseQuery, err)
+	}
+	for _, row := range byStatus {
+		switch row.Status {
+		case models.ProductStatusActive:
+			stats.ActiveProducts = row.Count
+		case models.ProductStatusInactive:
+			stats.InactiveProducts = row.Count
+		case models.ProductStatusArchived:
+			stats.ArchivedProducts = row.Count
+		}
+		if row.Status != models.ProductStatusArchived {
+			stats.TotalProducts += row.Count
+		}
+	}
+
+	counts := []struct {
+		target *int64
+		query  *gorm.DB
+	}{
+		{&stats.OutOfStockProducts, db.Model(&models.Product{}).Where("status = ? AND stock <= 0", models.ProductStatusActive)},
+		{&stats.TotalUsers, db.Model(&models.User{}).Where("is_active = ?", true)},
+		{&stats.TotalReviews, db.Model(&models.Review{}).Where("visibility = ?", models.ReviewVisibilityPublished)},
+		{&stats.FlaggedReviews, db.Model(&models.Review{}).Where("is_flagged = ? AND visibility <> ?", true, models.ReviewVisibilityRemoved)},
+	}
+	for _, count := range counts {
+		if err := count.query.Count(count.target).Error; err != nil {
+			return nil, fmt.Errorf("%w: failed to compute dashboard stats: %v", ErrDatabaseQuery, err)
+		}
+	}
 
-	// Total reviews
-	var totalReviews int64
-	s.db.Model(&models.Review{}).Where("is_active = ?", true).Count(&totalReviews)
-	stats["total_reviews"] = totalReviews
+	vendors, err := vendorProductStats(db)
+	if err != nil {
+		return nil, err
+	}
+	stats.Vendors = vendors
 
-	// Flagged reviews
-	var flaggedReviews int64
-	s.db.Model(&models.Review{}).Where("is_flagged = ? AND is_active = ?", true, true).Count(&flaggedReviews)
-	stats["flagged_reviews"] = flaggedReviews
+	var cleanup models.TokenCleanupRun
+	result := db.Order("started_at DESC").Limit(1).Find(&cleanup)
+	if result.Error != nil {
+		return nil, fmt.Errorf("%w: failed to fetch token cleanup run: %v", ErrDatabaseQuery, result.Error)
+	}
+	if result.RowsAffected > 0 {
+		stats.TokenCleanup = &cleanup
+	}
 
+	s.stats = stats
 	return stats, nil
 }
 
 func (s *AdminService) validateProductRequest(req *models.CreateProductRequest) error {
 	if req.Title == "" {
-		return errors.New("product title cannot be empty")
+		return fmt.Errorf("%w: product title cannot be empty", ErrInvalidInput)
 	}
 	if req.Price <= 0 {
-		return errors.New("product price must be greater than 0")
+		return fmt.Errorf("%w: product price must be greater than 0", ErrInvalidInput)
 	}
 	if req.Stock < 0 {
-		return errors.New("product stock cannot be negative")
+		return fmt.Errorf("%w: product stock cannot be negative", ErrInvalidInput)
+	}
+	if err := validateOrderLimits(req.MinOrderQty, req.MaxOrderQty); err != nil {
+		return err
+	}
+	for _, svc := range req.Services {
+		if err := validateService(strings.TrimSpace(svc.Name), strings.TrimSpace(svc.Link)); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
 // Add these methods to your AdminService in services/admin.go
 
-func (s *AdminService) GetProductByID(ctx context.Context, productID uint) (*models.Product, error) {
+func (s *AdminService) GetProductByID(ctx context.Context, productID uint, proj *ProductProjection) (*models.Product, error) {
 	// Input validation
 	if productID == 0 {
 		return nil, fmt.Errorf("invalid product ID")
@@ -584,11 +866,8 @@ func (s *AdminService) GetProductByID(ctx context.Context, productID uint) (*mod
 
 	var product models.Product
 
-	// Admin can access products regardless of status (active/inactive)
-	err := s.db.WithContext(ctx).
-		Preload("Images"). // Load all images (active and inactive for admin)
-		Preload("Reviews").
-		Preload("Services"). // If you have services relation
+	// Admin can access products regardless of status, with active and inactive images
+	err := proj.apply(s.db.WithContext(ctx)).
 		Where("id = ?", productID).
 		First(&product).Error
 
@@ -599,18 +878,25 @@ func (s *AdminService) GetProductByID(ctx context.Context, productID uint) (*mod
 		return nil, fmt.Errorf("%w: failed to fetch product: %v", ErrDatabaseQuery, err)
 	}
 
+	if product.Bundle, err = loadBundle(s.db.WithContext(ctx), productID); err != nil {
+		return nil, err
+	}
+
 	return &product, nil
 }
 
-func (s *AdminService) SearchProducts(params map[string]interface{}) ([]models.Product, int, error) {
+func (s *AdminService) SearchProducts(ctx context.Context, params map[string]interface{}, proj *ProductProjection) ([]models.Product, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
 	var products []models.Product
 	var total int64
 
-	query := s.db.Model(&models.Product{}).Where("is_active = ?", true)
+	query := s.db.WithContext(ctx).Model(&models.Product{}).Where("status <> ?", models.ProductStatusArchived)
 
 	// Apply search filters
 	if searchQuery, ok := params["query"].(string); ok && searchQuery != "" {
-		query = query.Where("name ILIKE ? OR description ILIKE ?", "%"+searchQuery+"%", "%"+searchQuery+"%")
+		query = query.Where("title ILIKE ? OR description ILIKE ?", "%"+searchQuery+"%", "%"+searchQuery+"%")
 	}
 
 	if category, ok := params["category"].(string); ok && category != "" {
@@ -621,6 +907,10 @@ func (s *AdminService) SearchProducts(params map[string]interface{}) ([]models.P
 		query = query.Where("brand = ?", brand)
 	}
 
+	if scope, ok := params["scope"].(ProductScope); ok {
+		query = scope.apply(query)
+	}
+
 	// Get total count
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -631,8 +921,10 @@ func (s *AdminService) SearchProducts(params map[string]interface{}) ([]models.P
 	limit := params["limit"].(int)
 	offset := (page - 1) * limit
 
-	err := query.Preload("Images", "is_active = ?", true).
-		Preload("Reviews").
+	if proj == nil {
+		proj, _ = NewProductProjection(nil, nil, ExpandImages, ExpandReviews)
+	}
+	err := proj.apply(query, "is_active = ?", true).
 		Order("created_at DESC").
 		Offset(offset).
 		Limit(limit).
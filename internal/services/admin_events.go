@@ -0,0 +1,67 @@
+package services
+
+import (
+	"sync"
+)
+
+const adminEventSubscriberBuffer = 64
+
+// AdminEventHub relays domain events from the EventBus to admin dashboards
+// connected to the live activity feed. Each subscriber may narrow the feed to
+// a set of event types.
+type AdminEventHub struct {
+	mu          sync.RWMutex
+	subscribers map[chan Event]map[string]bool
+}
+
+func NewAdminEventHub(eventBus *EventBus) *AdminEventHub {
+	h := &AdminEventHub{subscribers: make(map[chan Event]map[string]bool)}
+	if eventBus != nil {
+		eventBus.Subscribe(h.Broadcast)
+	}
+	return h
+}
+
+// Subscribe registers a listener for the given event types, or for every
+// event when types is empty.
+func (h *AdminEventHub) Subscribe(types []string) (<-chan Event, func()) {
+	var filter map[string]bool
+	if len(types) > 0 {
+		filter = make(map[string]bool, len(types))
+		for _, eventType := range types {
+			filter[eventType] = true
+		}
+	}
+
+	ch := make(chan Event, adminEventSubscriberBuffer)
+	h.mu.Lock()
+	h.subscribers[ch] = filter
+	h.mu.Unlock()
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		if _, ok := h.subscribers[ch]; ok {
+			delete(h.subscribers, ch)
+			close(ch)
+		}
+		h.mu.Unlock()
+	}
+	return ch, unsubscribe
+}
+
+func (h *AdminEventHub) Broadcast(event Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch, filter := range h.subscribers {
+		if filter != nil && !filter[event.Type] {
+			continue
+		}
+		// A dashboard that falls behind misses events rather than stalling
+		// the bus; it can reload its state from the regular endpoints.
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
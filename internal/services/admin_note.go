@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAdminNoteNotFound  = errors.New("note not found")
+	ErrAdminNoteForbidden = errors.New("only the author can edit a note")
+)
+
+// AdminNoteService stores internal staff notes on products and users
+type AdminNoteService struct {
+	db *gorm.DB
+}
+
+func NewAdminNoteService(db *gorm.DB) *AdminNoteService {
+	return &AdminNoteService{db: db}
+}
+
+// GetNotes returns the notes on a record, newest first
+func (s *AdminNoteService) GetNotes(ctx context.Context, subjectType string, subjectID uint) ([]models.AdminNote, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := s.checkSubject(ctx, subjectType, subjectID); err != nil {
+		return nil, err
+	}
+
+	notes := make([]models.AdminNote, 0)
+	err := s.db.WithContext(ctx).Preload("Author").
+		Where("subject_type = ? AND subject_id = ?", subjectType, subjectID).
+		Order("created_at DESC").
+		Find(&notes).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch notes: %v", ErrDatabaseQuery, err)
+	}
+	return notes, nil
+}
+
+func (s *AdminNoteService) CreateNote(ctx context.Context, subjectType string, subjectID, authorID uint, body string) (*models.AdminNote, error) {
+	if strings.TrimSpace(body) == "" {
+		return nil, fmt.Errorf("%w: note cannot be empty", ErrInvalidInput)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := s.checkSubject(ctx, subjectType, subjectID); err != nil {
+		return nil, err
+	}
+
+	note := models.AdminNote{
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+		AuthorID:    authorID,
+		Body:        strings.TrimSpace(body),
+	}
+	if err := s.db.WithContext(ctx).Create(&note).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to create note: %v", ErrDatabaseQuery, err)
+	}
+	return s.getNote(ctx, note.ID)
+}
+
+// UpdateNote changes a note's body; only its author may edit it
+func (s *AdminNoteService) UpdateNote(ctx context.Context, id, editorID uint, body string) (*models.AdminNote, error) {
+	if strings.TrimSpace(body) == "" {
+		return nil, fmt.Errorf("%w: note cannot be empty", ErrInvalidInput)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	note, err := s.getNote(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if note.AuthorID != editorID {
+		return nil, ErrAdminNoteForbidden
+	}
+
+	note.Body = strings.TrimSpace(body)
+	if err := s.db.WithContext(ctx).Model(note).Update("body", note.Body).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to update note: %v", ErrDatabaseQuery, err)
+	}
+	return note, nil
+}
+
+// DeleteNote removes a note; any admin may delete, so stale notes can be
+// cleaned up after their author has left
+func (s *AdminNoteService) DeleteNote(ctx context.Context, id uint) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).Delete(&models.AdminNote{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("%w: failed to delete note: %v", ErrDatabaseQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: note %d not found", ErrAdminNoteNotFound, id)
+	}
+	return nil
+}
+
+func (s *AdminNoteService) getNote(ctx context.Context, id uint) (*models.AdminNote, error) {
+	var note models.AdminNote
+	if err := s.db.WithContext(ctx).Preload("Author").First(&note, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: note %d not found", ErrAdminNoteNotFound, id)
+		}
+		return nil, fmt.Errorf("%w: failed to fetch note: %v", ErrDatabaseQuery, err)
+	}
+	return &note, nil
+}
+
+// checkSubject makes sure the noted record exists
+func (s *AdminNoteService) checkSubject(ctx context.Context, subjectType string, subjectID uint) error {
+	var model interface{}
+	var notFound error
+	switch subjectType {
+	case models.NoteSubjectProduct:
+		model, notFound = &models.Product{}, ErrProductNotFound
+	case models.NoteSubjectUser:
+		model, notFound = &models.User{}, ErrUserNotFound
+	default:
+		return fmt.Errorf("%w: unknown note subject %q", ErrInvalidInput, subjectType)
+	}
+
+	if err := s.db.WithContext(ctx).Select("id").First(model, subjectID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: %s %d not found", notFound, subjectType, subjectID)
+		}
+		return fmt.Errorf("%w: failed to find %s: %v", ErrDatabaseQuery, subjectType, err)
+	}
+	return nil
+}
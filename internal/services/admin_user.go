@@ -0,0 +1,341 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrUserAlreadyExists = errors.New("user already exists")
+
+// CreateAdminRequest describes an admin account created outside the public
+// signup flow, e.g. from the admin CLI
+type CreateAdminRequest struct {
+	Email     string
+	Password  string
+	FirstName string
+	LastName  string
+}
+
+// CreateAdmin creates an active platform admin. The account belongs to no
+// store, so it can sign in to any of them.
+func (s *AuthService) CreateAdmin(ctx context.Context, req CreateAdminRequest) (*models.User, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if !utils.IsValidEmail(email) {
+		return nil, fmt.Errorf("%w: invalid email format", ErrInvalidInput)
+	}
+	if !utils.IsValidPassword(req.Password) {
+		return nil, fmt.Errorf("%w: password must be at least 8 characters", ErrInvalidInput)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var existing int64
+	if err := s.db.WithContext(ctx).Model(&models.User{}).Where("email = ?", email).Count(&existing).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to check existing users: %v", ErrDatabaseQuery, err)
+	}
+	if existing > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUserAlreadyExists, email)
+	}
+
+	user := models.User{
+		Email:     email,
+		Password:  req.Password, // Will be hashed in BeforeCreate hook
+		FirstName: utils.SanitizeString(req.FirstName),
+		LastName:  utils.SanitizeString(req.LastName),
+		Role:      "admin",
+		IsActive:  true,
+	}
+	if err := s.db.WithContext(ctx).Create(&user).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to create admin: %v", ErrDatabaseQuery, err)
+	}
+
+	s.log.WithFields(map[string]interface{}{"user_id": user.ID}).Info("Created admin " + user.Email)
+	return &user, nil
+}
+
+// BootstrapAdmin creates the first admin from INITIAL_ADMIN_EMAIL and
+// INITIAL_ADMIN_PASSWORD. It does nothing when email is empty or an admin
+// already exists, so the variables can stay set across restarts.
+func (s *AuthService) BootstrapAdmin(ctx context.Context, email, password string) error {
+	if email == "" {
+		return nil
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var admins int64
+	if err := s.db.WithContext(queryCtx).Model(&models.User{}).Where("role = ?", "admin").Count(&admins).Error; err != nil {
+		return fmt.Errorf("%w: failed to count admins: %v", ErrDatabaseQuery, err)
+	}
+	if admins > 0 {
+		return nil
+	}
+
+	_, err := s.CreateAdmin(ctx, CreateAdminRequest{Email: email, Password: password})
+	return err
+}
+
+// UpdateUserRole promotes a customer to admin or demotes an admin to
+// customer, and records the change in the audit log. The user's sessions are
+// revoked since tokens carry the role. Admins cannot change their own role,
+// and the last active admin cannot be demoted.
+func (s *AuthService) UpdateUserRole(ctx context.Context, adminID, userID uint, role, ipAddress, userAgent string) (*models.User, error) {
+	if adminID == userID {
+		return nil, fmt.Errorf("%w: cannot change your own role", ErrInvalidInput)
+	}
+	if role != "admin" && role != "customer" {
+		return nil, fmt.Errorf("%w: role must be admin or customer", ErrInvalidInput)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var user models.User
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: user %d not found", ErrUserNotFound, userID)
+			}
+			return fmt.Errorf("%w: failed to fetch user: %v", ErrDatabaseQuery, err)
+		}
+		if user.Role == models.RoleVendor {
+			return fmt.Errorf("%w: remove the user from their vendor first", ErrInvalidInput)
+		}
+		if user.Role == role {
+			return nil
+		}
+
+		if user.Role == "admin" {
+			var others int64
+			if err := tx.Model(&models.User{}).
+				Where("role = ? AND is_active = ? AND id <> ?", "admin", true, user.ID).
+				Count(&others).Error; err != nil {
+				return fmt.Errorf("%w: failed to count admins: %v", ErrDatabaseQuery, err)
+			}
+			if others == 0 {
+				return fmt.Errorf("%w: cannot demote the last admin", ErrInvalidInput)
+			}
+		}
+
+		previous := user.Role
+		user.Role = role
+		if err := tx.Model(&user).Update("role", role).Error; err != nil {
+			return fmt.Errorf("%w: failed to update role: %v", ErrDatabaseQuery, err)
+		}
+		if err := tx.Model(&models.RefreshToken{}).
+			Where("user_id = ? AND is_revoked = ?", user.ID, false).
+			Update("is_revoked", true).Error; err != nil {
+			return fmt.Errorf("%w: failed to revoke sessions: %v", ErrDatabaseQuery, err)
+		}
+
+		entry := models.AuditLog{
+			ActorID:       adminID,
+			SubjectUserID: &user.ID,
+			Action:        models.AuditActionUserRoleChanged,
+			IPAddress:     ipAddress,
+			UserAgent:     userAgent,
+			Details:       previous + " -> " + role,
+		}
+		if err := tx.Create(&entry).Error; err != nil {
+			return fmt.Errorf("%w: failed to write audit log: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// RevokeAllSessions revokes every refresh token, signing all users out once
+// their access tokens expire. Used after rotating the JWT secret.
+func (s *AuthService) RevokeAllSessions(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("is_revoked = ?", false).
+		Update("is_revoked", true)
+	if result.Error != nil {
+		return 0, fmt.Errorf("%w: failed to revoke sessions: %v", ErrDatabaseQuery, result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// RestoreUser reactivates a deactivated account, including one that was
+// merged into another. Records moved by the merge stay with the surviving
+// account.
+func (s *AuthService) RestoreUser(ctx context.Context, adminID, userID uint, ipAddress, userAgent string) (*models.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var user models.User
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: user %d not found", ErrUserNotFound, userID)
+			}
+			return fmt.Errorf("%w: failed to fetch user: %v", ErrDatabaseQuery, err)
+		}
+		if user.IsActive {
+			return fmt.Errorf("%w: user is already active", ErrInvalidInput)
+		}
+
+		details := "reactivated"
+		if user.MergedIntoID != nil {
+			details = fmt.Sprintf("reactivated after merge into user %d", *user.MergedIntoID)
+		}
+		user.IsActive, user.MergedIntoID = true, nil
+		if err := tx.Model(&user).Select("is_active", "merged_into_id").Updates(&user).Error; err != nil {
+			return fmt.Errorf("%w: failed to restore user: %v", ErrDatabaseQuery, err)
+		}
+
+		entry := models.AuditLog{
+			ActorID:       adminID,
+			SubjectUserID: &user.ID,
+			Action:        models.AuditActionUserRestored,
+			IPAddress:     ipAddress,
+			UserAgent:     userAgent,
+			Details:       details,
+		}
+		if err := tx.Create(&entry).Error; err != nil {
+			return fmt.Errorf("%w: failed to write audit log: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// MergeUsers folds a duplicate customer account into the surviving one: its
+// reviews, review likes, product reactions, quotes, saved searches,
+// referrals and admin notes move over, and the duplicate is deactivated. Where the survivor
+// already has a review or reaction on the same product, or a like on the same
+// review, the duplicate's one stays behind so each user keeps at most one.
+// The duplicate's sessions and reset links are revoked rather than moved, so
+// nobody holding them ends up signed in as the surviving account.
+func (s *AuthService) MergeUsers(ctx context.Context, adminID, survivorID, duplicateID uint, ipAddress, userAgent string) (*models.UserMergeResult, error) {
+	if survivorID == duplicateID {
+		return nil, fmt.Errorf("%w: cannot merge a user into itself", ErrInvalidInput)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	result := &models.UserMergeResult{MergedUserID: duplicateID, Moved: make(map[string]int64)}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Locked in ID order so concurrent merges of the same pair cannot deadlock
+		var users []models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", []uint{survivorID, duplicateID}).
+			Order("id").
+			Find(&users).Error; err != nil {
+			return fmt.Errorf("%w: failed to fetch users: %v", ErrDatabaseQuery, err)
+		}
+		var survivor, duplicate *models.User
+		for i := range users {
+			if users[i].ID == survivorID {
+				survivor = &users[i]
+			} else {
+				duplicate = &users[i]
+			}
+		}
+		if survivor == nil {
+			return fmt.Errorf("%w: user %d not found", ErrUserNotFound, survivorID)
+		}
+		if duplicate == nil {
+			return fmt.Errorf("%w: user %d not found", ErrUserNotFound, duplicateID)
+		}
+		if survivor.Role != "customer" || duplicate.Role != "customer" {
+			return fmt.Errorf("%w: only customer accounts can be merged", ErrInvalidInput)
+		}
+		if !survivor.IsActive {
+			return fmt.Errorf("%w: the surviving account is not active", ErrInvalidInput)
+		}
+		if duplicate.MergedIntoID != nil {
+			return fmt.Errorf("%w: user %d was already merged into user %d", ErrInvalidInput, duplicate.ID, *duplicate.MergedIntoID)
+		}
+		if !sameStore(survivor.StoreID, duplicate.StoreID) {
+			return fmt.Errorf("%w: the accounts belong to different stores", ErrInvalidInput)
+		}
+
+		moves := []struct {
+			name  string
+			query *gorm.DB
+			set   map[string]interface{}
+		}{
+			{"reviews", tx.Model(&models.Review{}).
+				Where("user_id = ? AND product_id NOT IN (?)", duplicateID, tx.Model(&models.Review{}).Select("product_id").Where("user_id = ?", survivorID)),
+				map[string]interface{}{"user_id": survivorID}},
+			{"review_likes", tx.Model(&models.ReviewLike{}).
+				Where("user_id = ? AND review_id NOT IN (?)", duplicateID, tx.Model(&models.ReviewLike{}).Select("review_id").Where("user_id = ?", survivorID)),
+				map[string]interface{}{"user_id": survivorID}},
+			{"product_reactions", tx.Model(&models.ProductReaction{}).
+				Where("user_id = ? AND product_id NOT IN (?)", duplicateID, tx.Model(&models.ProductReaction{}).Select("product_id").Where("user_id = ?", survivorID)),
+				map[string]interface{}{"user_id": survivorID}},
+			{"quotes", tx.Model(&models.Quote{}).Where("user_id = ?", duplicateID),
+				map[string]interface{}{"user_id": survivorID}},
+			{"saved_searches", tx.Model(&models.SavedSearch{}).Where("user_id = ?", duplicateID),
+				map[string]interface{}{"user_id": survivorID}},
+			{"referrals", tx.Model(&models.Referral{}).Where("referrer_id = ? AND referred_id <> ?", duplicateID, survivorID),
+				map[string]interface{}{"referrer_id": survivorID}},
+			{"admin_notes", tx.Model(&models.AdminNote{}).Where("subject_type = ? AND subject_id = ?", models.NoteSubjectUser, duplicateID),
+				map[string]interface{}{"subject_id": survivorID}},
+			{"refresh_tokens_revoked", tx.Model(&models.RefreshToken{}).Where("user_id = ? AND is_revoked = ?", duplicateID, false),
+				map[string]interface{}{"is_revoked": true}},
+			{"reset_tokens_revoked", tx.Model(&models.PasswordResetToken{}).Where("user_id = ? AND is_used = ?", duplicateID, false),
+				map[string]interface{}{"is_used": true}},
+		}
+		for _, move := range moves {
+			res := move.query.Updates(move.set)
+			if res.Error != nil {
+				return fmt.Errorf("%w: failed to move %s: %v", ErrDatabaseQuery, move.name, res.Error)
+			}
+			result.Moved[move.name] = res.RowsAffected
+		}
+
+		duplicate.IsActive, duplicate.MergedIntoID = false, &survivor.ID
+		if err := tx.Model(duplicate).Select("is_active", "merged_into_id").Updates(duplicate).Error; err != nil {
+			return fmt.Errorf("%w: failed to deactivate user: %v", ErrDatabaseQuery, err)
+		}
+
+		entry := models.AuditLog{
+			ActorID:       adminID,
+			SubjectUserID: &duplicate.ID,
+			Action:        models.AuditActionUserMerged,
+			IPAddress:     ipAddress,
+			UserAgent:     userAgent,
+			Details: fmt.Sprintf("%s merged into user %d (%s): %d reviews, %d review likes, %d reactions, %d quotes, %d saved searches, %d referrals, %d notes moved",
+				duplicate.Email, survivor.ID, survivor.Email, result.Moved["reviews"], result.Moved["review_likes"], result.Moved["product_reactions"],
+				result.Moved["quotes"], result.Moved["saved_searches"], result.Moved["referrals"], result.Moved["admin_notes"]),
+		}
+		if err := tx.Create(&entry).Error; err != nil {
+			return fmt.Errorf("%w: failed to write audit log: %v", ErrDatabaseQuery, err)
+		}
+
+		result.User = survivor
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func sameStore(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var ErrAnnouncementNotFound = errors.New("announcement not found")
+
+type AnnouncementService struct {
+	db *gorm.DB
+}
+
+func NewAnnouncementService(db *gorm.DB) *AnnouncementService {
+	return &AnnouncementService{db: db}
+}
+
+// audiencesForRole returns the audiences a caller with the given role may see.
+// Anonymous callers only receive announcements targeted at everyone.
+func audiencesForRole(role string) []string {
+	switch role {
+	case "admin":
+		return []string{models.AnnouncementAudienceAll, models.AnnouncementAudienceAdmins}
+	case "customer":
+		return []string{models.AnnouncementAudienceAll, models.AnnouncementAudienceCustomers}
+	default:
+		return []string{models.AnnouncementAudienceAll}
+	}
+}
+
+// GetActiveAnnouncements returns the announcements currently inside their
+// schedule window for the caller's store and role, most severe first.
+func (s *AnnouncementService) GetActiveAnnouncements(ctx context.Context, storeID uint, role string) ([]models.Announcement, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	now := time.Now()
+	announcements := make([]models.Announcement, 0)
+	query := s.db.WithContext(ctx)
+	if storeID != 0 {
+		query = query.Where("store_id = ? OR store_id IS NULL", storeID)
+	}
+	err := query.
+		Where("is_active = ?", true).
+		Where("audience IN ?", audiencesForRole(role)).
+		Where("starts_at IS NULL OR starts_at <= ?", now).
+		Where("ends_at IS NULL OR ends_at > ?", now).
+		Order("CASE severity WHEN 'critical' THEN 0 WHEN 'warning' THEN 1 ELSE 2 END").
+		Order("created_at DESC").
+		Find(&announcements).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch announcements: %v", ErrDatabaseQuery, err)
+	}
+
+	return announcements, nil
+}
+
+func (s *AnnouncementService) GetAnnouncements(ctx context.Context, storeID uint) ([]models.Announcement, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	announcements := make([]models.Announcement, 0)
+	if err := s.db.WithContext(ctx).Scopes(storeScope(storeID)).Order("created_at DESC").Find(&announcements).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch announcements: %v", ErrDatabaseQuery, err)
+	}
+	return announcements, nil
+}
+
+func (s *AnnouncementService) GetAnnouncementByID(ctx context.Context, storeID, id uint) (*models.Announcement, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var announcement models.Announcement
+	if err := s.db.WithContext(ctx).Scopes(storeScope(storeID)).First(&announcement, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAnnouncementNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to fetch announcement: %v", ErrDatabaseQuery, err)
+	}
+	return &announcement, nil
+}
+
+func (s *AnnouncementService) CreateAnnouncement(ctx context.Context, storeID, adminID uint, req *models.CreateAnnouncementRequest) (*models.Announcement, error) {
+	if strings.TrimSpace(req.Title) == "" {
+		return nil, fmt.Errorf("%w: title cannot be empty", ErrInvalidInput)
+	}
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return nil, fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidInput)
+	}
+
+	announcement := models.Announcement{
+		Title:     strings.TrimSpace(req.Title),
+		Message:   strings.TrimSpace(req.Message),
+		Severity:  req.Severity,
+		Audience:  req.Audience,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+		IsActive:  true,
+		CreatedBy: adminID,
+	}
+	if storeID != 0 {
+		announcement.StoreID = &storeID
+	}
+	if announcement.Severity == "" {
+		announcement.Severity = models.AnnouncementSeverityInfo
+	}
+	if announcement.Audience == "" {
+		announcement.Audience = models.AnnouncementAudienceAll
+	}
+	if req.IsActive != nil {
+		announcement.IsActive = *req.IsActive
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := createAllColumns(s.db.WithContext(ctx), &announcement); err != nil {
+		return nil, fmt.Errorf("%w: failed to create announcement: %v", ErrDatabaseQuery, err)
+	}
+	return &announcement, nil
+}
+
+func (s *AnnouncementService) UpdateAnnouncement(ctx context.Context, storeID, id uint, req *models.UpdateAnnouncementRequest) (*models.Announcement, error) {
+	announcement, err := s.GetAnnouncementByID(ctx, storeID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updateData := make(map[string]interface{})
+	if req.Title != nil {
+		if strings.TrimSpace(*req.Title) == "" {
+			return nil, fmt.Errorf("%w: title cannot be empty", ErrInvalidInput)
+		}
+		updateData["title"] = strings.TrimSpace(*req.Title)
+	}
+	if req.Message != nil {
+		updateData["message"] = strings.TrimSpace(*req.Message)
+	}
+	if req.Severity != nil {
+		updateData["severity"] = *req.Severity
+	}
+	if req.Audience != nil {
+		updateData["audience"] = *req.Audience
+	}
+	if req.StartsAt != nil {
+		updateData["starts_at"] = *req.StartsAt
+	}
+	if req.EndsAt != nil {
+		updateData["ends_at"] = *req.EndsAt
+	}
+	if req.IsActive != nil {
+		updateData["is_active"] = *req.IsActive
+	}
+
+	startsAt, endsAt := announcement.StartsAt, announcement.EndsAt
+	if req.StartsAt != nil {
+		startsAt = req.StartsAt
+	}
+	if req.EndsAt != nil {
+		endsAt = req.EndsAt
+	}
+	if startsAt != nil && endsAt != nil && !endsAt.After(*startsAt) {
+		return nil, fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidInput)
+	}
+
+	if len(updateData) == 0 {
+		return announcement, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Model(announcement).Updates(updateData).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to update announcement: %v", ErrDatabaseQuery, err)
+	}
+	return s.GetAnnouncementByID(ctx, storeID, id)
+}
+
+func (s *AnnouncementService) DeleteAnnouncement(ctx context.Context, storeID, id uint) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).Scopes(storeScope(storeID)).Delete(&models.Announcement{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("%w: failed to delete announcement: %v", ErrDatabaseQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrAnnouncementNotFound
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Metered external services tracked by APIUsageService
+const (
+	ExternalAPIAbstractEmail = "abstract_email"
+	ExternalAPIAbstractPhone = "abstract_phone"
+	ExternalAPISMS           = "sms"
+	ExternalAPIGeocoding     = "geocoding"
+)
+
+const usageRecordTimeout = 5 * time.Second
+
+type APIUsageService struct {
+	db           *gorm.DB
+	cfg          *config.Config
+	emailService *EmailService
+}
+
+func NewAPIUsageService(db *gorm.DB, cfg *config.Config, emailService *EmailService) *APIUsageService {
+	return &APIUsageService{
+		db:           db,
+		cfg:          cfg,
+		emailService: emailService,
+	}
+}
+
+type APIUsageSummary struct {
+	Service       string   `json:"service"`
+	CallsToday    int64    `json:"calls_today"`
+	CostToday     float64  `json:"cost_today"`
+	CallsMonth    int64    `json:"calls_month"`
+	CostMonth     float64  `json:"cost_month"`
+	FailuresMonth int64    `json:"failures_month"`
+	FailureRate   float64  `json:"failure_rate"`   // month to date, 0-1
+	AvgLatencyMs  float64  `json:"avg_latency_ms"` // month to date
+	DailyBudget   *float64 `json:"daily_budget,omitempty"`
+	MonthlyBudget *float64 `json:"monthly_budget,omitempty"`
+
+	// Budgets above count calls; these count spend
+	DailyCostBudget   *float64 `json:"daily_cost_budget,omitempty"`
+	MonthlyCostBudget *float64 `json:"monthly_cost_budget,omitempty"`
+}
+
+type APIUsageReport struct {
+	Days    int                       `json:"days"`
+	Summary []APIUsageSummary         `json:"summary"`
+	Daily   []models.ExternalAPIUsage `json:"daily"`
+}
+
+// Record counts one call to an external service and how long it took. It
+// never fails the caller: bookkeeping errors are logged and swallowed.
+func (s *APIUsageService) Record(service string, success bool, latency time.Duration) {
+	if s == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), usageRecordTimeout)
+	defer cancel()
+
+	now := time.Now().UTC()
+	cost := s.cfg.ExternalAPICosts[service]
+	failures := int64(0)
+	if !success {
+		failures = 1
+	}
+
+	usage := models.ExternalAPIUsage{
+		Service:   service,
+		Date:      time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		Calls:     1,
+		Failures:  failures,
+		Cost:      cost,
+		LatencyMs: latency.Milliseconds(),
+	}
+
+	err := s.db.WithContext(ctx).Clauses(
+		clause.OnConflict{
+			Columns: []clause.Column{{Name: "service"}, {Name: "date"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"calls":      gorm.Expr("external_api_usages.calls + 1"),
+				"failures":   gorm.Expr("external_api_usages.failures + ?", failures),
+				"cost":       gorm.Expr("external_api_usages.cost + ?", cost),
+				"latency_ms": gorm.Expr("external_api_usages.latency_ms + ?", usage.LatencyMs),
+				"updated_at": now,
+			}),
+		},
+		clause.Returning{},
+	).Create(&usage).Error
+	if err != nil {
+		logger.WithFields(map[string]interface{}{"service": service}).
+			Error("Failed to record external API usage: ", err)
+		return
+	}
+
+	s.checkBudgets(ctx, service, usage.Calls, usage.Cost, cost)
+}
+
+// checkBudgets alerts once per period for each budget this call crossed.
+// callsToday and costToday are today's totals including this call, as
+// returned by the atomic increment, so exactly one call sees the crossing
+// however many run concurrently. Earlier days of the month do not change
+// any more, which keeps the monthly check exact too.
+func (s *APIUsageService) checkBudgets(ctx context.Context, service string, callsToday int64, costToday, cost float64) {
+	calls := float64(callsToday)
+	if budget, ok := s.cfg.ExternalAPIDailyBudgets[service]; ok && crossedBudget(calls-1, calls, budget) {
+		s.alert(service, "daily", "calls", calls, budget)
+	}
+	if budget, ok := s.cfg.ExternalAPIDailyCostBudgets[service]; ok && crossedBudget(costToday-cost, costToday, budget) {
+		s.alert(service, "daily", "cost", costToday, budget)
+	}
+
+	callBudget, hasCallBudget := s.cfg.ExternalAPIMonthlyBudgets[service]
+	costBudget, hasCostBudget := s.cfg.ExternalAPIMonthlyCostBudgets[service]
+	if !hasCallBudget && !hasCostBudget {
+		return
+	}
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	var earlier struct {
+		Calls int64
+		Cost  float64
+	}
+	if err := s.db.WithContext(ctx).Model(&models.ExternalAPIUsage{}).
+		Select("COALESCE(SUM(calls), 0) AS calls, COALESCE(SUM(cost), 0) AS cost").
+		Where("service = ? AND date >= ? AND date < ?", service, monthStart, today).
+		Scan(&earlier).Error; err != nil {
+		logger.WithFields(map[string]interface{}{"service": service}).
+			Error("Failed to sum monthly external API usage: ", err)
+		return
+	}
+
+	callsMonth := float64(earlier.Calls) + calls
+	if hasCallBudget && crossedBudget(callsMonth-1, callsMonth, callBudget) {
+		s.alert(service, "monthly", "calls", callsMonth, callBudget)
+	}
+	costMonth := earlier.Cost + costToday
+	if hasCostBudget && crossedBudget(costMonth-cost, costMonth, costBudget) {
+		s.alert(service, "monthly", "cost", costMonth, costBudget)
+	}
+}
+
+// crossedBudget reports whether usage went from below budget to at or above
+// it
+func crossedBudget(previous, current, budget float64) bool {
+	return budget > 0 && previous < budget && budget <= current
+}
+
+// alert reports a crossed budget; metric is "calls" or "cost"
+func (s *APIUsageService) alert(service, period, metric string, used, budget float64) {
+	logger.WithFields(map[string]interface{}{
+		"service": service,
+		"period":  period,
+		"metric":  metric,
+		"used":    used,
+		"budget":  budget,
+	}).Warn("External API budget reached")
+
+	if s.emailService == nil || s.cfg.AlertEmail == "" {
+		return
+	}
+
+	go func() {
+		subject := fmt.Sprintf("External API budget reached: %s", service)
+		amount := "<strong>Calls:</strong> %.0f<br><strong>Budget:</strong> %.0f"
+		if metric == "cost" {
+			amount = "<strong>Cost:</strong> %.2f<br><strong>Budget:</strong> %.2f"
+		}
+		body := fmt.Sprintf(`
+		<h2>External API Budget Alert</h2>
+		<p>The %s %s budget for <strong>%s</strong> has been reached.</p>
+		<p>`+amount+`</p>
+	`, period, metric, service, used, budget)
+		if err := s.emailService.SendEmail(s.cfg.AlertEmail, subject, body); err != nil {
+			logger.Error("Failed to send external API budget alert: ", err)
+		}
+	}()
+}
+
+// GetUsageReport returns per-day usage for the last `days` days plus today's
+// and month-to-date totals per service.
+func (s *APIUsageService) GetUsageReport(ctx context.Context, days int) (*APIUsageReport, error) {
+	if days < 1 || days > MaxTrendDays {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", ErrInvalidInput, MaxTrendDays)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	since := today.AddDate(0, 0, -days+1)
+	if monthStart.Before(since) {
+		since = monthStart
+	}
+
+	var rows []models.ExternalAPIUsage
+	if err := s.db.WithContext(ctx).
+		Where("date >= ?", since).
+		Order("date DESC, service ASC").
+		Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch external API usage: %v", ErrDatabaseQuery, err)
+	}
+
+	summaries := make(map[string]*APIUsageSummary)
+	summaryFor := func(service string) *APIUsageSummary {
+		if summary, ok := summaries[service]; ok {
+			return summary
+		}
+		summary := &APIUsageSummary{Service: service}
+		if budget, ok := s.cfg.ExternalAPIDailyBudgets[service]; ok {
+			summary.DailyBudget = &budget
+		}
+		if budget, ok := s.cfg.ExternalAPIMonthlyBudgets[service]; ok {
+			summary.MonthlyBudget = &budget
+		}
+		if budget, ok := s.cfg.ExternalAPIDailyCostBudgets[service]; ok {
+			summary.DailyCostBudget = &budget
+		}
+		if budget, ok := s.cfg.ExternalAPIMonthlyCostBudgets[service]; ok {
+			summary.MonthlyCostBudget = &budget
+		}
+		summaries[service] = summary
+		return summary
+	}
+
+	report := &APIUsageReport{Days: days, Daily: []models.ExternalAPIUsage{}}
+	latencyMonth := make(map[string]int64)
+	windowStart := today.AddDate(0, 0, -days+1)
+	for _, row := range rows {
+		summary := summaryFor(row.Service)
+		if row.Date.Equal(today) {
+			summary.CallsToday += row.Calls
+			summary.CostToday += row.Cost
+		}
+		if !row.Date.Before(monthStart) {
+			summary.CallsMonth += row.Calls
+			summary.CostMonth += row.Cost
+			summary.FailuresMonth += row.Failures
+			latencyMonth[row.Service] += row.LatencyMs
+		}
+		if !row.Date.Before(windowStart) {
+			report.Daily = append(report.Daily, row)
+		}
+	}
+
+	// Surface configured services even before their first call
+	for service := range s.cfg.ExternalAPIDailyBudgets {
+		summaryFor(service)
+	}
+	for service := range s.cfg.ExternalAPIMonthlyBudgets {
+		summaryFor(service)
+	}
+	for service := range s.cfg.ExternalAPIDailyCostBudgets {
+		summaryFor(service)
+	}
+	for service := range s.cfg.ExternalAPIMonthlyCostBudgets {
+		summaryFor(service)
+	}
+
+	report.Summary = make([]APIUsageSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		if summary.CallsMonth > 0 {
+			summary.FailureRate = float64(summary.FailuresMonth) / float64(summary.CallsMonth)
+			summary.AvgLatencyMs = float64(latencyMonth[summary.Service]) / float64(summary.CallsMonth)
+		}
+		report.Summary = append(report.Summary, *summary)
+	}
+	sort.Slice(report.Summary, func(i, j int) bool {
+		return report.Summary[i].Service < report.Summary[j].Service
+	})
+
+	return report, nil
+}
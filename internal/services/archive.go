@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/database"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+)
+
+const archiveBatchSize = 5000
+
+// ArchiveService keeps high-volume history tables small by moving rows older
+// than ARCHIVE_AFTER_MONTHS to archive tables. Reads that need the whole
+// history go through the *_all views instead.
+type ArchiveService struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+func NewArchiveService(db *gorm.DB, cfg *config.Config) *ArchiveService {
+	return &ArchiveService{db: db, cfg: cfg}
+}
+
+// Run archives once per ARCHIVE_INTERVAL until ctx is cancelled. It returns
+// at once when archiving is disabled.
+func (s *ArchiveService) Run(ctx context.Context) {
+	if s.cfg.ArchiveAfterMonths <= 0 {
+		return
+	}
+	interval := s.cfg.ArchiveInterval
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Archive(ctx); err != nil {
+				logger.Error("Archiving failed: ", err)
+			}
+		}
+	}
+}
+
+// Archive moves rows created before the cutoff to the archive tables and
+// returns how many were moved per table. Each batch moves its rows in a
+// single statement, so a row is never in both tables or in neither, and
+// instances running concurrently never move the same row twice.
+func (s *ArchiveService) Archive(ctx context.Context) (map[string]int64, error) {
+	if s.cfg.ArchiveAfterMonths <= 0 {
+		return nil, fmt.Errorf("%w: archiving is disabled; set ARCHIVE_AFTER_MONTHS", ErrInvalidInput)
+	}
+	cutoff := time.Now().AddDate(0, -s.cfg.ArchiveAfterMonths, 0)
+
+	moved := make(map[string]int64, len(database.ArchivedTables))
+	for _, table := range database.ArchivedTables {
+		count, err := s.archiveTable(ctx, table, cutoff)
+		moved[table.Table] = count
+		if err != nil {
+			return moved, err
+		}
+		if count > 0 {
+			logger.Info(fmt.Sprintf("Archived %d rows of %s created before %s", count, table.Table, cutoff.Format("2006-01-02")))
+		}
+	}
+	return moved, nil
+}
+
+func (s *ArchiveService) archiveTable(ctx context.Context, table database.ArchivedTable, cutoff time.Time) (int64, error) {
+	columns, err := table.Columns(s.db)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read columns of %s: %v", table.Table, err)
+	}
+	list := strings.Join(columns, ", ")
+	query := fmt.Sprintf(`WITH moved AS (
+			DELETE FROM %[1]s WHERE id IN (
+				SELECT id FROM %[1]s WHERE created_at < ? ORDER BY id LIMIT ?)
+			RETURNING %[2]s)
+		INSERT INTO %[3]s (%[2]s) SELECT %[2]s FROM moved`, table.Table, list, table.Archive)
+
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		batchCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+		result := s.db.WithContext(batchCtx).Exec(query, cutoff, archiveBatchSize)
+		cancel()
+		if result.Error != nil {
+			return total, fmt.Errorf("%w: failed to archive %s: %v", ErrDatabaseQuery, table.Table, result.Error)
+		}
+
+		total += result.RowsAffected
+		if result.RowsAffected < archiveBatchSize {
+			return total, nil
+		}
+	}
+}
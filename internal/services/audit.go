@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+)
+
+const auditRecordTimeout = 5 * time.Second
+
+// AuditLogFilter narrows the audit log listing; zero values match everything
+type AuditLogFilter struct {
+	ActorID       uint
+	SubjectUserID uint
+	Action        string
+}
+
+type AuditService struct {
+	db *gorm.DB
+}
+
+func NewAuditService(db *gorm.DB) *AuditService {
+	return &AuditService{db: db}
+}
+
+// Record appends an entry to the audit log. It never fails the caller:
+// write errors are logged and swallowed.
+func (s *AuditService) Record(entry *models.AuditLog) {
+	if s == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), auditRecordTimeout)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Create(entry).Error; err != nil {
+		logger.Error("Failed to write audit log entry ", entry.Action, " for actor ", entry.ActorID, ": ", err)
+	}
+}
+
+// GetAuditLogs lists audit entries, newest first, including archived ones
+func (s *AuditService) GetAuditLogs(ctx context.Context, filter AuditLogFilter, page, limit int) ([]models.AuditLog, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	query := s.db.WithContext(ctx).Table("audit_logs_all")
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.SubjectUserID != 0 {
+		query = query.Where("subject_user_id = ?", filter.SubjectUserID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to count audit log entries: %v", ErrDatabaseQuery, err)
+	}
+
+	entries := make([]models.AuditLog, 0)
+	if err := query.Order("id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&entries).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to fetch audit log: %v", ErrDatabaseQuery, err)
+	}
+	return entries, total, nil
+}
@@ -1,28 +1,45 @@
 package services
 
 import (
+	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
 	"github.com/princeprakhar/ecommerce-backend/internal/models"
 	"github.com/princeprakhar/ecommerce-backend/internal/utils"
 	"gorm.io/gorm"
 	"github.com/princeprakhar/ecommerce-backend/internal/types"
+	"github.com/princeprakhar/ecommerce-backend/pkg/metrics"
 )
 
+var ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+
+// refreshTokenRetention is how long revoked tokens are kept for reuse detection
+const refreshTokenRetention = 7 * 24 * time.Hour
+
 type AuthService struct {
 	db                *gorm.DB
 	jwtSecret         string
 	validationService *ValidationService
 	emailService      *EmailService
+	otpService        *OTPService
+	fraudService      *FraudService
 	baseURL           string
+	passwordPolicy    PasswordPolicy
+	log               logger.Logger
 }
 
 type ForgotPasswordRequest struct {
-    Email string `json:"email" binding:"required"`
+    Email   string `json:"email" binding:"required"`
+    StoreID uint   `json:"-"` // resolved store in multi-tenant mode
 }
 
 type ResetPasswordRequest struct {
@@ -40,35 +57,45 @@ type UpdateProfileRequest struct {
 	LastName    string `json:"last_name"`
 	Email      string `json:"email" binding:"required,email"`
 	PhoneNumber string `json:"phone_number"`
+	Version     *int   `json:"version"` // version the client last read; omit to update whatever is stored
 }
 
-func NewAuthService(db *gorm.DB, jwtSecret string, validationService *ValidationService, emailService *EmailService, baseURL string) *AuthService {
+func NewAuthService(db *gorm.DB, jwtSecret string, validationService *ValidationService, emailService *EmailService, otpService *OTPService, fraudService *FraudService, baseURL string, passwordPolicy PasswordPolicy, log logger.Logger) *AuthService {
 	return &AuthService{
 		db:                db,
 		jwtSecret:         jwtSecret,
 		validationService: validationService,
 		emailService:      emailService,
+		otpService:        otpService,
+		fraudService:      fraudService,
 		baseURL:           baseURL,
+		passwordPolicy:    passwordPolicy,
+		log:               log,
 	}
 }
 
 type SignupRequest struct {
-	Email       string `json:"email" binding:"required"`
-	Password    string `json:"password" binding:"required"`
-	FirstName   string `json:"first_name"`
-	LastName    string `json:"last_name"`
-	PhoneNumber string `json:"phone_number" binding:"required"`
-	Role        string `json:"role"`
+	Email        string       `json:"email" binding:"required"`
+	Password     string       `json:"password" binding:"required"`
+	FirstName    string       `json:"first_name"`
+	LastName     string       `json:"last_name"`
+	PhoneNumber  string       `json:"phone_number" binding:"required"`
+	Country      string       `json:"country" binding:"omitempty,len=2"` // optional ISO 3166 code the customer lives in
+	ReferralCode string       `json:"referral_code"`                     // optional; attributes the signup to the code's owner
+	StoreID      uint         `json:"-"`                                 // resolved store in multi-tenant mode
+	Origin       SignupOrigin `json:"-"`                                 // request IP and device, for fraud screening
 }
 
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required"`
 	Password string `json:"password" binding:"required"`
 	IsAdmin  bool   `json:"is_admin"` // Optional, for admin login
+	StoreID  uint   `json:"-"`        // resolved store in multi-tenant mode
 }
 
 type RefreshRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
+	StoreID      uint   `json:"-"` // resolved store in multi-tenant mode
 }
 
 type AuthResponse struct {
@@ -84,12 +111,12 @@ type AuthResponse struct {
 func (s *AuthService) Signup(req SignupRequest) (*AuthResponse, error) {
 	// Basic email format validation first
 	if !utils.IsValidEmail(req.Email) {
-		return nil, errors.New("invalid email format")
+		return nil, fmt.Errorf("%w: invalid email format", ErrInvalidInput)
 	}
 
 	// Basic password validation
 	if !utils.IsValidPassword(req.Password) {
-		return nil, errors.New("password must be at least 8 characters")
+		return nil, fmt.Errorf("%w: password must be at least 8 characters", ErrInvalidInput)
 	}
 
 	// Email validation
@@ -99,7 +126,7 @@ func (s *AuthService) Signup(req SignupRequest) (*AuthResponse, error) {
 			return nil, fmt.Errorf("email validation failed: %v", err)
 		}
 		if !emailValid {
-			return nil, errors.New("email address is not valid or deliverable")
+			return nil, fmt.Errorf("%w: email address is not valid or deliverable", ErrInvalidInput)
 		}
 	} else {
 		return nil, errors.New("email validation service unavailable")
@@ -113,45 +140,95 @@ func (s *AuthService) Signup(req SignupRequest) (*AuthResponse, error) {
 				return nil, fmt.Errorf("phone validation failed: %v", err)
 			}
 			if !phoneValid {
-				return nil, errors.New("phone number is not valid")
+				return nil, fmt.Errorf("%w: phone number is not valid", ErrInvalidInput)
 			}
 		} else {
 			return nil, errors.New("phone validation service unavailable")
 		}
 	}
 
-	// Set default role
-	if req.Role == "" {
-		req.Role = "customer"
+	// Check if user already exists. Emails are unique per store, and a
+	// store's customers can't take the email of a user without a store.
+	var existingUser models.User
+	if err := s.db.Scopes(storeUserScope(req.StoreID)).Where("email = ?", req.Email).First(&existingUser).Error; err == nil {
+		return nil, ErrUserAlreadyExists
 	}
 
-	if !utils.IsValidRole(req.Role) {
-		return nil, errors.New("invalid role")
+	var referralCode *models.ReferralCode
+	if strings.TrimSpace(req.ReferralCode) != "" {
+		code, err := findReferralCode(s.db, req.ReferralCode)
+		if errors.Is(err, ErrReferralCodeNotFound) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		referralCode = code
 	}
 
-	// Check if user already exists
-	var existingUser models.User
-	if err := s.db.Where("email = ?", req.Email).First(&existingUser).Error; err == nil {
-		return nil, errors.New("user already exists")
+	// Fraud screening scores the signup but never blocks it; high-risk
+	// accounts are queued for an admin instead
+	var risk *models.RiskAssessment
+	if s.fraudService != nil {
+		assessment, err := s.fraudService.ScreenSignup(context.Background(), SignupScreening{Email: req.Email, Country: req.Country, Origin: req.Origin})
+		if err != nil {
+			s.log.Warn("Fraud screening failed, signing up unscreened: ", err)
+		}
+		risk = assessment
 	}
 
-	// Create user
-	user := models.User{
-		Email:       utils.SanitizeString(req.Email),
-		Password:    req.Password, // Will be hashed in BeforeCreate hook
-		FirstName:   utils.SanitizeString(req.FirstName),
-		LastName:    utils.SanitizeString(req.LastName),
-		PhoneNumber: utils.SanitizeString(req.PhoneNumber),
-		Role:        req.Role,
-		IsActive:    true,
+	// Phone ownership check, when enabled via OTP_REQUIRED_FOR
+	if s.otpService.IsRequired(models.OTPPurposeSignup) {
+		if err := s.otpService.ConsumeVerification(context.Background(), req.PhoneNumber, models.OTPPurposeSignup); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := s.db.Create(&user).Error; err != nil {
+	// Create user
+	user := models.User{
+		Email:          utils.SanitizeString(req.Email),
+		Password:       req.Password, // Will be hashed in BeforeCreate hook
+		FirstName:      utils.SanitizeString(req.FirstName),
+		LastName:       utils.SanitizeString(req.LastName),
+		PhoneNumber:    models.EncryptedString(utils.SanitizeString(req.PhoneNumber)),
+		Role:           "customer", // admins are created via bootstrap or promotion, never signup
+		IsActive:       true,
+		Country:        strings.ToUpper(strings.TrimSpace(req.Country)),
+		SignupIP:       req.Origin.IPAddress,
+		SignupDeviceID: req.Origin.DeviceID,
+	}
+	if req.StoreID != 0 {
+		user.StoreID = &req.StoreID
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		if referralCode != nil {
+			if err := recordReferral(tx, referralCode, user.ID, req.Origin); err != nil {
+				return err
+			}
+		}
+		if risk != nil {
+			if err := s.fraudService.recordSignupRisk(tx, &user, risk, req.Origin.IPAddress); err != nil {
+				return err
+			}
+		}
+		return recordOutboxEvent(tx, EventUserSignedUp, "user", user.ID, map[string]interface{}{
+			"id":         user.ID,
+			"email":      user.Email,
+			"role":       user.Role,
+			"created_at": user.CreatedAt,
+		})
+	})
+	if err != nil {
 		return nil, errors.New("failed to create user")
 	}
+	metrics.Signups.WithLabelValues(user.Role).Inc()
 
 	// Generate token pair
-	tokenPair, err := utils.GenerateTokenPair(user.ID, user.Email, user.Role, s.jwtSecret)
+	tokenPair, err := utils.GenerateTokenPair(user.ID, user.StoreID, user.Email, user.Role, s.jwtSecret)
 	if err != nil {
 		return nil, errors.New("failed to generate tokens")
 	}
@@ -159,7 +236,8 @@ func (s *AuthService) Signup(req SignupRequest) (*AuthResponse, error) {
 	// Store refresh token in database
 	refreshToken := models.RefreshToken{
 		UserID:    user.ID,
-		Token:     tokenPair.RefreshToken,
+		TokenHash: hashRefreshToken(tokenPair.RefreshToken),
+		FamilyID:  uuid.New().String(),
 		ExpiresAt: time.Unix(tokenPair.RefreshTokenExpiresAt, 0),
 		IsRevoked: false,
 	}
@@ -197,26 +275,45 @@ func (s *AuthService) Login(req LoginRequest) (*AuthResponse, error) {
 		role = "customer"
 	}
 
-	// Find user
+	// Find user. The same email may belong to users of other stores; the
+	// store's own user comes before one without a store.
 	var user models.User
-	if err := s.db.Where("email = ? AND is_active = ?", req.Email, true).First(&user).Error; err != nil {
+	if err := s.db.Scopes(storeUserScope(req.StoreID)).Where("email = ? AND is_active = ?", req.Email, true).
+		Order("store_id NULLS LAST").First(&user).Error; err != nil {
+		metrics.LoginFailures.WithLabelValues(metrics.LoginUnknownUser).Inc()
 		return nil, errors.New("invalid credentials")
 	}
 
 	// Check password
 	if !user.CheckPassword(req.Password)  {
+		metrics.LoginFailures.WithLabelValues(metrics.LoginBadPassword).Inc()
 		return nil, errors.New("invalid credentials")
 	}
 
-	if user.Role != role {
+	// Vendor users sign in to the admin panel alongside admins
+	if user.Role != role && !(req.IsAdmin && user.Role == models.RoleVendor) {
+		metrics.LoginFailures.WithLabelValues(metrics.LoginWrongRole).Inc()
 		return nil, errors.New("invalid credentials")
 	}
 
+	// Users belong to the store they signed up in; users without a store
+	// (e.g. platform admins) may sign in to any store
+	if req.StoreID != 0 && user.StoreID != nil && *user.StoreID != req.StoreID {
+		metrics.LoginFailures.WithLabelValues(metrics.LoginWrongStore).Inc()
+		return nil, errors.New("invalid credentials")
+	}
+
+	// Privileged users past the password age get a token that only allows
+	// changing the password, not a session
+	if s.passwordExpired(&user) {
+		return nil, s.passwordExpiredError(&user)
+	}
+
 	// Revoke all existing refresh tokens for this user (optional security measure)
 	s.db.Model(&models.RefreshToken{}).Where("user_id = ?", user.ID).Update("is_revoked", true)
 
 	// Generate new token pair
-	tokenPair, err := utils.GenerateTokenPair(user.ID, user.Email, user.Role, s.jwtSecret)
+	tokenPair, err := utils.GenerateTokenPair(user.ID, user.StoreID, user.Email, user.Role, s.jwtSecret)
 	if err != nil {
 		return nil, errors.New("failed to generate tokens")
 	}
@@ -224,7 +321,8 @@ func (s *AuthService) Login(req LoginRequest) (*AuthResponse, error) {
 	// Store new refresh token
 	refreshToken := models.RefreshToken{
 		UserID:    user.ID,
-		Token:     tokenPair.RefreshToken,
+		TokenHash: hashRefreshToken(tokenPair.RefreshToken),
+		FamilyID:  uuid.New().String(),
 		ExpiresAt: time.Unix(tokenPair.RefreshTokenExpiresAt, 0),
 		IsRevoked: false,
 	}
@@ -261,7 +359,7 @@ func (s *AuthService) RefreshToken(req RefreshRequest) (*types.AuthResponse, err
 	}
 
 	var refreshToken models.RefreshToken
-	if err := s.db.Where("token = ? AND is_revoked = ? AND expires_at > ?", req.RefreshToken, false, time.Now()).
+	if err := s.db.Where("token_hash = ? AND expires_at > ?", hashRefreshToken(req.RefreshToken), time.Now()).
 		First(&refreshToken).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("refresh token not found or expired")
@@ -269,12 +367,35 @@ func (s *AuthService) RefreshToken(req RefreshRequest) (*types.AuthResponse, err
 		return nil, err
 	}
 
+	// A rotated token being presented again means it was copied: revoke the
+	// whole family so neither the thief nor the victim can keep refreshing.
+	if refreshToken.IsRevoked {
+		s.revokeTokenFamily(refreshToken)
+		return nil, ErrRefreshTokenReused
+	}
+
 	var user models.User
 	if err := s.db.Where("id = ? AND is_active = ?", refreshToken.UserID, true).
 		First(&user).Error; err != nil {
 		return nil, errors.New("user not found")
 	}
 
+	// Sessions stay in the user's store, as Login does
+	if req.StoreID != 0 && user.StoreID != nil && *user.StoreID != req.StoreID {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	// A session cannot outlive the password age: end the token family and
+	// hand out a change-password token, as Login does
+	if s.passwordExpired(&user) {
+		if err := s.db.Model(&models.RefreshToken{}).
+			Where("family_id = ?", refreshToken.FamilyID).
+			Update("is_revoked", true).Error; err != nil {
+			return nil, errors.New("failed to revoke old token")
+		}
+		return nil, s.passwordExpiredError(&user)
+	}
+
 	// Transactional revoke and new insert
 	tx := s.db.Begin()
 	defer func() {
@@ -283,13 +404,19 @@ func (s *AuthService) RefreshToken(req RefreshRequest) (*types.AuthResponse, err
 		}
 	}()
 
-	refreshToken.IsRevoked = true
-	if err := tx.Save(&refreshToken).Error; err != nil {
+	result := tx.Model(&refreshToken).Where("is_revoked = ?", false).Update("is_revoked", true)
+	if result.Error != nil {
 		tx.Rollback()
 		return nil, errors.New("failed to revoke old token")
 	}
+	if result.RowsAffected == 0 {
+		// Lost a race with another refresh using the same token
+		tx.Rollback()
+		s.revokeTokenFamily(refreshToken)
+		return nil, ErrRefreshTokenReused
+	}
 
-	tokenPair, err := utils.GenerateTokenPair(user.ID, user.Email, user.Role, s.jwtSecret)
+	tokenPair, err := utils.GenerateTokenPair(user.ID, user.StoreID, user.Email, user.Role, s.jwtSecret)
 	if err != nil {
 		tx.Rollback()
 		return nil, errors.New("failed to generate new tokens")
@@ -297,7 +424,8 @@ func (s *AuthService) RefreshToken(req RefreshRequest) (*types.AuthResponse, err
 
 	newRefresh := models.RefreshToken{
 		UserID:    user.ID,
-		Token:     tokenPair.RefreshToken,
+		TokenHash: hashRefreshToken(tokenPair.RefreshToken),
+		FamilyID:  refreshToken.FamilyID,
 		ExpiresAt: time.Unix(tokenPair.RefreshTokenExpiresAt, 0),
 		IsRevoked: false,
 	}
@@ -324,7 +452,7 @@ func (s *AuthService) RefreshToken(req RefreshRequest) (*types.AuthResponse, err
 func (s *AuthService) Logout(refreshToken string) error {
 	// Revoke the refresh token
 	return s.db.Model(&models.RefreshToken{}).
-		Where("token = ?", refreshToken).
+		Where("token_hash = ?", hashRefreshToken(refreshToken)).
 		Update("is_revoked", true).Error
 }
 
@@ -335,6 +463,25 @@ func (s *AuthService) LogoutAll(userID uint) error {
 		Update("is_revoked", true).Error
 }
 
+func (s *AuthService) revokeTokenFamily(token models.RefreshToken) {
+	log := s.log.WithFields(map[string]interface{}{
+		"user_id":   token.UserID,
+		"family_id": token.FamilyID,
+	})
+	log.Warn("Refresh token reuse detected, revoking token family")
+
+	if err := s.db.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND is_revoked = ?", token.FamilyID, false).
+		Update("is_revoked", true).Error; err != nil {
+		log.Error("Failed to revoke refresh token family: ", err)
+	}
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 func (s *AuthService) GetUserByID(userID uint) (*models.User, error) {
 	var user models.User
 	if err := s.db.Where("id = ? AND is_active = ?", userID, true).First(&user).Error; err != nil {
@@ -359,7 +506,8 @@ func (s *AuthService) ForgotPassword(req ForgotPasswordRequest) error {
     }
 
     var user models.User
-    if err := s.db.Where("email = ? AND is_active = ?", req.Email, true).First(&user).Error; err != nil {
+    if err := s.db.Scopes(storeUserScope(req.StoreID)).Where("email = ? AND is_active = ?", req.Email, true).
+        Order("store_id NULLS LAST").First(&user).Error; err != nil {
         return nil // Don't reveal if email exists
     }
 
@@ -382,10 +530,11 @@ func (s *AuthService) ForgotPassword(req ForgotPasswordRequest) error {
     if err := s.db.Create(&passwordResetToken).Error; err != nil {
         return errors.New("failed to create reset token")
     }
+    metrics.PasswordResets.WithLabelValues(metrics.PasswordResetRequested).Inc()
 
     if s.emailService != nil {
         if err := s.emailService.SendPasswordResetEmail(user.Email, resetToken, s.baseURL); err != nil {
-            fmt.Printf("Failed to send password reset email: %v\n", err)
+            s.log.WithFields(map[string]interface{}{"user_id": user.ID}).Error("Failed to send password reset email: ", err)
         }
     }
 
@@ -408,16 +557,28 @@ func (s *AuthService) ResetPassword(req ResetPasswordRequest) error {
         return errors.New("user not found")
     }
 
+    if err := s.checkPasswordReuse(&user, req.NewPassword); err != nil {
+        return err
+    }
+
+    oldHash := user.Password
     if err := user.UpdatePassword(req.NewPassword); err != nil {
         return errors.New("failed to update password")
     }
 
-    if err := s.db.Save(&user).Error; err != nil {
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Save(&user).Error; err != nil {
+            return err
+        }
+        return s.recordPasswordChange(tx, &user, oldHash)
+    })
+    if err != nil {
         return errors.New("failed to save new password")
     }
 
     resetToken.IsUsed = true
     s.db.Save(&resetToken)
+    metrics.PasswordResets.WithLabelValues(metrics.PasswordResetCompleted).Inc()
 
     s.db.Model(&models.RefreshToken{}).
         Where("user_id = ?", user.ID).
@@ -440,11 +601,32 @@ func (s *AuthService) ChangePassword(userID uint, req ChangePasswordRequest) err
         return errors.New("current password is incorrect")
     }
 
+    if s.otpService.IsRequired(models.OTPPurposePasswordChange) {
+        // Fail closed: without a phone on file there is nothing to verify
+        if user.PhoneNumber == "" {
+            return fmt.Errorf("%w: add a phone number to your profile first", ErrOTPNotVerified)
+        }
+        if err := s.otpService.ConsumeVerification(context.Background(), string(user.PhoneNumber), models.OTPPurposePasswordChange); err != nil {
+            return err
+        }
+    }
+
+    if err := s.checkPasswordReuse(&user, req.NewPassword); err != nil {
+        return err
+    }
+
+    oldHash := user.Password
     if err := user.UpdatePassword(req.NewPassword); err != nil {
         return errors.New("failed to update password")
     }
 
-    if err := s.db.Save(&user).Error; err != nil {
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Save(&user).Error; err != nil {
+            return err
+        }
+        return s.recordPasswordChange(tx, &user, oldHash)
+    })
+    if err != nil {
         return errors.New("failed to save new password")
     }
 
@@ -469,7 +651,26 @@ func (s *AuthService) ValidateResetToken(token string) (*models.User, error) {
 
 
 
-func (s *AuthService) UpdateProfile(userID uint, req UpdateProfileRequest) (*models.User, error) {
+// ErrEmailTaken is returned when a profile update would give a user another
+// user's email
+var ErrEmailTaken = errors.New("email is already in use")
+
+// ProfileConflictError is returned by UpdateProfile when the profile changed
+// after the client read it. Current is the stored profile to merge against.
+type ProfileConflictError struct {
+	Current *models.User `json:"current"`
+}
+
+func (e *ProfileConflictError) Error() string {
+	return fmt.Sprintf("profile was modified by another request (now at version %d)", e.Current.Version)
+}
+
+// UpdateProfile updates the user's name, email and phone number. The update
+// is conditional on the user's version: req.Version when the client sends it,
+// otherwise the version read in the same transaction, so concurrent updates
+// cannot silently overwrite each other. Changing the phone number needs a
+// verified code on both the old and the new number when OTP is enforced.
+func (s *AuthService) UpdateProfile(ctx context.Context, userID uint, req UpdateProfileRequest) (*models.User, error) {
 	// Validate email format
 	if !utils.IsValidEmail(req.Email) && s.validationService != nil {
 		// If validation service is available, use it to validate email
@@ -478,11 +679,10 @@ func (s *AuthService) UpdateProfile(userID uint, req UpdateProfileRequest) (*mod
 			return nil, fmt.Errorf("email validation failed: %v", err)
 		}
 		if !emailValid {
-			return nil, errors.New("invalid email format")
+			return nil, fmt.Errorf("%w: invalid email format", ErrInvalidInput)
 		}
 	}
 
-	
 	// Validate phone number if provided
 	if req.PhoneNumber != "" && s.validationService != nil {
 		phoneValid, err := s.validationService.IsPhoneValid(req.PhoneNumber)
@@ -490,23 +690,111 @@ func (s *AuthService) UpdateProfile(userID uint, req UpdateProfileRequest) (*mod
 			return nil, fmt.Errorf("phone validation failed: %v", err)
 		}
 		if !phoneValid {
-			return nil, errors.New("phone number is not valid")
+			return nil, fmt.Errorf("%w: phone number is not valid", ErrInvalidInput)
 		}
 	}
 
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
 	var user models.User
-	if err := s.db.Where("id = ? AND is_active = ?", userID, true).First(&user).Error; err != nil {
-		return nil, errors.New("user not found")
-	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND is_active = ?", userID, true).First(&user).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: user %d not found", ErrUserNotFound, userID)
+			}
+			return fmt.Errorf("%w: failed to fetch user: %v", ErrDatabaseQuery, err)
+		}
+		if req.Version != nil && *req.Version != user.Version {
+			return &ProfileConflictError{Current: &user}
+		}
+		if err := s.verifyPhoneChange(ctx, string(user.PhoneNumber), req.PhoneNumber); err != nil {
+			return err
+		}
 
-	user.FirstName = utils.SanitizeString(req.FirstName)
-	user.LastName = utils.SanitizeString(req.LastName)
-	user.Email = utils.SanitizeString(req.Email)
-	user.PhoneNumber = utils.SanitizeString(req.PhoneNumber)
+		email := utils.SanitizeString(req.Email)
+		if !strings.EqualFold(email, user.Email) {
+			var storeID uint
+			if user.StoreID != nil {
+				storeID = *user.StoreID
+			}
+			var taken int64
+			if err := tx.Model(&models.User{}).Scopes(storeUserScope(storeID)).
+				Where("LOWER(email) = LOWER(?) AND id <> ?", email, user.ID).
+				Count(&taken).Error; err != nil {
+				return fmt.Errorf("%w: failed to check email: %v", ErrDatabaseQuery, err)
+			}
+			if taken > 0 {
+				return ErrEmailTaken
+			}
+		}
 
-	if err := s.db.Save(&user).Error; err != nil {
-		return nil, errors.New("failed to update profile")
+		updates := map[string]interface{}{
+			"first_name":   utils.SanitizeString(req.FirstName),
+			"last_name":    utils.SanitizeString(req.LastName),
+			"email":        email,
+			"phone_number": models.EncryptedString(utils.SanitizeString(req.PhoneNumber)),
+			"version":      gorm.Expr("version + 1"),
+		}
+		result := tx.Model(&models.User{}).Where("id = ? AND version = ?", user.ID, user.Version).Updates(updates)
+		if result.Error != nil {
+			if isUniqueViolation(result.Error) {
+				return ErrEmailTaken
+			}
+			return fmt.Errorf("%w: failed to update profile: %v", ErrDatabaseQuery, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			// Another update committed between the read and the write
+			if err := tx.First(&user, user.ID).Error; err != nil {
+				return fmt.Errorf("%w: failed to fetch user: %v", ErrDatabaseQuery, err)
+			}
+			return &ProfileConflictError{Current: &user}
+		}
+		if err := tx.First(&user, user.ID).Error; err != nil {
+			return fmt.Errorf("%w: failed to fetch user: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &user, nil
-}
\ No newline at end of file
+}
+
+// phoneChangeNeedsOTP reports whether phone changes must be verified. The
+// phone receives the password change codes, so enforcing those implies
+// verifying phone changes too, or a hijacked session could swap the number.
+func (s *AuthService) phoneChangeNeedsOTP() bool {
+	return s.otpService.IsRequired(models.OTPPurposePhoneChange) || s.otpService.IsRequired(models.OTPPurposePasswordChange)
+}
+
+// verifyPhoneChange consumes phone_change verifications for the current and
+// the new number when the number changes. The number cannot be removed while
+// verification is enforced.
+func (s *AuthService) verifyPhoneChange(ctx context.Context, current, requested string) error {
+	current = utils.NormalizePhone(current)
+	requested = utils.NormalizePhone(utils.SanitizeString(requested))
+	if current == requested || !s.phoneChangeNeedsOTP() {
+		return nil
+	}
+	if requested == "" {
+		return fmt.Errorf("%w: phone number cannot be removed while phone verification is required", ErrInvalidInput)
+	}
+	if current != "" {
+		if err := s.otpService.ConsumeVerification(ctx, current, models.OTPPurposePhoneChange); err != nil {
+			return fmt.Errorf("current phone number: %w", err)
+		}
+	}
+	if err := s.otpService.ConsumeVerification(ctx, requested, models.OTPPurposePhoneChange); err != nil {
+		return fmt.Errorf("new phone number: %w", err)
+	}
+	return nil
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint
+// violation, e.g. from two requests claiming the same email at once
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+)
+
+const (
+	avatarPrefix       = "users/avatars/"
+	avatarQuality      = 85
+	MaxAvatarFileBytes = 5 * 1024 * 1024
+)
+
+var ErrInvalidAvatar = errors.New("invalid avatar image")
+
+// AvatarService stores user avatars in S3. Uploads are center-cropped to a
+// square and re-encoded as JPEG, so the stored file never carries the
+// original's metadata.
+type AvatarService struct {
+	db        *gorm.DB
+	s3Service *S3Service
+	size      int
+}
+
+func NewAvatarService(db *gorm.DB, cfg *config.Config, s3Service *S3Service) *AvatarService {
+	size := cfg.AvatarSize
+	if size <= 0 {
+		size = 256
+	}
+	return &AvatarService{db: db, s3Service: s3Service, size: size}
+}
+
+// SetAvatar replaces a user's avatar with the uploaded image
+func (s *AvatarService) SetAvatar(ctx context.Context, userID uint, file io.Reader) (*models.User, error) {
+	data, err := io.ReadAll(io.LimitReader(file, MaxAvatarFileBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read avatar: %v", err)
+	}
+	if len(data) > MaxAvatarFileBytes {
+		return nil, fmt.Errorf("%w: file exceeds %d bytes", ErrInvalidAvatar, MaxAvatarFileBytes)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAvatar, err)
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, resizeImage(src, s.size, s.size, ImageFitCover, s.size), &jpeg.Options{Quality: avatarQuality}); err != nil {
+		return nil, fmt.Errorf("failed to encode avatar: %v", err)
+	}
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%s%d/%s.jpg", avatarPrefix, userID, uuid.New().String())
+	if err := s.s3Service.PutObject(key, "image/jpeg", buf.Bytes()); err != nil {
+		return nil, err
+	}
+
+	previousKey := user.AvatarKey
+	user.AvatarKey = key
+	user.AvatarURL = s.s3Service.ObjectURL(key)
+	if err := s.saveAvatar(ctx, user); err != nil {
+		// The row still points at the previous avatar, so drop the new object
+		s.deleteObject(key)
+		return nil, err
+	}
+
+	s.deleteObject(previousKey)
+	return user, nil
+}
+
+// DeleteAvatar removes a user's avatar; they fall back to the default one
+func (s *AvatarService) DeleteAvatar(ctx context.Context, userID uint) (*models.User, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.AvatarKey == "" && user.AvatarURL == "" {
+		return user, nil
+	}
+
+	previousKey := user.AvatarKey
+	user.AvatarKey = ""
+	user.AvatarURL = ""
+	if err := s.saveAvatar(ctx, user); err != nil {
+		return nil, err
+	}
+
+	s.deleteObject(previousKey)
+	return user, nil
+}
+
+func (s *AvatarService) getUser(ctx context.Context, userID uint) (*models.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var user models.User
+	if err := s.db.WithContext(ctx).Where("id = ? AND is_active = ?", userID, true).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: user %d not found", ErrUserNotFound, userID)
+		}
+		return nil, fmt.Errorf("%w: failed to fetch user: %v", ErrDatabaseQuery, err)
+	}
+	return &user, nil
+}
+
+func (s *AvatarService) saveAvatar(ctx context.Context, user *models.User) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).Model(user).
+		Select("avatar_url", "avatar_key").
+		Updates(user).Error
+	if err != nil {
+		return fmt.Errorf("%w: failed to update avatar: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
+
+// deleteObject removes a replaced avatar. Failures only leave an unreferenced
+// object behind, so they are logged rather than returned.
+func (s *AvatarService) deleteObject(key string) {
+	if key == "" {
+		return
+	}
+	if err := s.s3Service.DeleteImage(key); err != nil {
+		logger.Error("Failed to delete avatar ", key, ": ", err)
+	}
+}
@@ -0,0 +1,300 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+)
+
+const (
+	bannerImagePrefix = "banners/"
+
+	// bannerCacheTTL bounds how long another instance's banner changes take
+	// to reach this one; changes made here apply immediately
+	bannerCacheTTL = time.Minute
+)
+
+var ErrBannerNotFound = errors.New("banner not found")
+
+// BannerPlacements lists the valid storefront placements
+var BannerPlacements = []string{
+	models.BannerPlacementHomeHero,
+	models.BannerPlacementHomeStrip,
+	models.BannerPlacementCategoryTop,
+	models.BannerPlacementProductSidebar,
+}
+
+// BannerService manages storefront banners. Active banners are cached per
+// store until the next change, the next schedule boundary or bannerCacheTTL,
+// whichever comes first.
+type BannerService struct {
+	db        *gorm.DB
+	s3Service *S3Service
+
+	mu    sync.Mutex
+	cache map[uint]bannerCacheEntry
+}
+
+type bannerCacheEntry struct {
+	banners   map[string][]models.Banner
+	expiresAt time.Time
+}
+
+func NewBannerService(db *gorm.DB, s3Service *S3Service) *BannerService {
+	return &BannerService{db: db, s3Service: s3Service, cache: make(map[uint]bannerCacheEntry)}
+}
+
+// GetActiveBanners returns the banners currently showing in a store, keyed by
+// placement. A non-empty placement limits the result to that placement.
+func (s *BannerService) GetActiveBanners(ctx context.Context, storeID uint, placement string) (map[string][]models.Banner, error) {
+	s.mu.Lock()
+	entry, ok := s.cache[storeID]
+	s.mu.Unlock()
+
+	if !ok || !time.Now().Before(entry.expiresAt) {
+		var err error
+		if entry, err = s.loadActiveBanners(ctx, storeID); err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		s.cache[storeID] = entry
+		s.mu.Unlock()
+	}
+
+	if placement == "" {
+		return entry.banners, nil
+	}
+	return map[string][]models.Banner{placement: entry.banners[placement]}, nil
+}
+
+func (s *BannerService) GetBanners(ctx context.Context, storeID uint) ([]models.Banner, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	banners := make([]models.Banner, 0)
+	err := s.db.WithContext(ctx).Scopes(storeScope(storeID)).
+		Order("placement ASC, sort_order ASC, created_at DESC").
+		Find(&banners).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch banners: %v", ErrDatabaseQuery, err)
+	}
+	return banners, nil
+}
+
+func (s *BannerService) GetBanner(ctx context.Context, storeID, id uint) (*models.Banner, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var banner models.Banner
+	if err := s.db.WithContext(ctx).Scopes(storeScope(storeID)).First(&banner, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: banner %d not found", ErrBannerNotFound, id)
+		}
+		return nil, fmt.Errorf("%w: failed to fetch banner: %v", ErrDatabaseQuery, err)
+	}
+	return &banner, nil
+}
+
+// CreateBanner creates a banner without an image; upload one with SetImage.
+// Banners without an image are never shown.
+func (s *BannerService) CreateBanner(ctx context.Context, storeID, adminID uint, req *models.CreateBannerRequest) (*models.Banner, error) {
+	if strings.TrimSpace(req.Title) == "" {
+		return nil, fmt.Errorf("%w: title cannot be empty", ErrInvalidInput)
+	}
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return nil, fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidInput)
+	}
+
+	banner := models.Banner{
+		Title:     strings.TrimSpace(req.Title),
+		LinkURL:   req.LinkURL,
+		Placement: req.Placement,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+		SortOrder: req.SortOrder,
+		IsActive:  true,
+		CreatedBy: adminID,
+	}
+	if storeID != 0 {
+		banner.StoreID = &storeID
+	}
+	if req.IsActive != nil {
+		banner.IsActive = *req.IsActive
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := createAllColumns(s.db.WithContext(ctx), &banner); err != nil {
+		return nil, fmt.Errorf("%w: failed to create banner: %v", ErrDatabaseQuery, err)
+	}
+
+	s.invalidate(storeID)
+	return &banner, nil
+}
+
+func (s *BannerService) UpdateBanner(ctx context.Context, storeID, id uint, req *models.UpdateBannerRequest) (*models.Banner, error) {
+	banner, err := s.GetBanner(ctx, storeID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updateData := make(map[string]interface{})
+	if req.Title != nil {
+		if strings.TrimSpace(*req.Title) == "" {
+			return nil, fmt.Errorf("%w: title cannot be empty", ErrInvalidInput)
+		}
+		updateData["title"] = strings.TrimSpace(*req.Title)
+	}
+	if req.LinkURL != nil {
+		updateData["link_url"] = *req.LinkURL
+	}
+	if req.Placement != nil {
+		updateData["placement"] = *req.Placement
+	}
+	if req.StartsAt != nil {
+		updateData["starts_at"] = *req.StartsAt
+	}
+	if req.EndsAt != nil {
+		updateData["ends_at"] = *req.EndsAt
+	}
+	if req.SortOrder != nil {
+		updateData["sort_order"] = *req.SortOrder
+	}
+	if req.IsActive != nil {
+		updateData["is_active"] = *req.IsActive
+	}
+
+	startsAt, endsAt := banner.StartsAt, banner.EndsAt
+	if req.StartsAt != nil {
+		startsAt = req.StartsAt
+	}
+	if req.EndsAt != nil {
+		endsAt = req.EndsAt
+	}
+	if startsAt != nil && endsAt != nil && !endsAt.After(*startsAt) {
+		return nil, fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidInput)
+	}
+
+	if len(updateData) == 0 {
+		return banner, nil
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := s.db.WithContext(queryCtx).Model(banner).Updates(updateData).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to update banner: %v", ErrDatabaseQuery, err)
+	}
+
+	s.invalidate(storeID)
+	return s.GetBanner(ctx, storeID, id)
+}
+
+// SetImage uploads a banner's image, replacing any previous one
+func (s *BannerService) SetImage(ctx context.Context, storeID, id uint, file multipart.File, header *multipart.FileHeader) (*models.Banner, error) {
+	banner, err := s.GetBanner(ctx, storeID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.s3Service.UploadImageAt(ctx, bannerImagePrefix, file, header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	previousKey := banner.ImageKey
+	banner.ImageKey = result.Key
+	banner.ImageURL = result.URL
+	if err := s.db.WithContext(queryCtx).Model(banner).Select("image_url", "image_key").Updates(banner).Error; err != nil {
+		s.deleteImage(result.Key)
+		return nil, fmt.Errorf("%w: failed to update banner image: %v", ErrDatabaseQuery, err)
+	}
+
+	s.deleteImage(previousKey)
+	s.invalidate(storeID)
+	return banner, nil
+}
+
+func (s *BannerService) DeleteBanner(ctx context.Context, storeID, id uint) error {
+	banner, err := s.GetBanner(ctx, storeID, id)
+	if err != nil {
+		return err
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := s.db.WithContext(queryCtx).Delete(banner).Error; err != nil {
+		return fmt.Errorf("%w: failed to delete banner: %v", ErrDatabaseQuery, err)
+	}
+
+	s.deleteImage(banner.ImageKey)
+	s.invalidate(storeID)
+	return nil
+}
+
+// loadActiveBanners reads the store's showing banners and works out when the
+// result goes stale: at the next start or end time, or after bannerCacheTTL
+func (s *BannerService) loadActiveBanners(ctx context.Context, storeID uint) (bannerCacheEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	now := time.Now()
+	var candidates []models.Banner
+	err := s.db.WithContext(ctx).Scopes(storeScope(storeID)).
+		Where("is_active = ? AND image_url <> ''", true).
+		Where("ends_at IS NULL OR ends_at > ?", now).
+		Order("sort_order ASC, created_at DESC").
+		Find(&candidates).Error
+	if err != nil {
+		return bannerCacheEntry{}, fmt.Errorf("%w: failed to fetch banners: %v", ErrDatabaseQuery, err)
+	}
+
+	entry := bannerCacheEntry{
+		banners:   make(map[string][]models.Banner),
+		expiresAt: now.Add(bannerCacheTTL),
+	}
+	for _, banner := range candidates {
+		if banner.StartsAt != nil && banner.StartsAt.After(now) {
+			if banner.StartsAt.Before(entry.expiresAt) {
+				entry.expiresAt = *banner.StartsAt
+			}
+			continue
+		}
+		if banner.EndsAt != nil && banner.EndsAt.Before(entry.expiresAt) {
+			entry.expiresAt = *banner.EndsAt
+		}
+		entry.banners[banner.Placement] = append(entry.banners[banner.Placement], banner)
+	}
+	return entry, nil
+}
+
+func (s *BannerService) invalidate(storeID uint) {
+	s.mu.Lock()
+	delete(s.cache, storeID)
+	s.mu.Unlock()
+}
+
+// deleteImage removes a replaced or orphaned banner image. Failures only
+// leave an unreferenced object behind, so they are logged rather than
+// returned.
+func (s *BannerService) deleteImage(key string) {
+	if key == "" {
+		return
+	}
+	if err := s.s3Service.DeleteImage(key); err != nil {
+		logger.Error("Failed to delete banner image ", key, ": ", err)
+	}
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var ErrBundleNotFound = errors.New("product bundle not found")
+
+type BundleService struct {
+	db *gorm.DB
+}
+
+func NewBundleService(db *gorm.DB) *BundleService {
+	return &BundleService{db: db}
+}
+
+// GetBundle returns the bundle of a product with its components, price and
+// availability.
+func (s *BundleService) GetBundle(ctx context.Context, productID uint) (*models.ProductBundle, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	bundle, err := loadBundle(s.db.WithContext(ctx), productID)
+	if err != nil {
+		return nil, err
+	}
+	if bundle == nil {
+		return nil, fmt.Errorf("%w: product %d is not a bundle", ErrBundleNotFound, productID)
+	}
+	return bundle, nil
+}
+
+// SetBundle makes a product a bundle of the given components, replacing any
+// previous contents. Bundles cannot be nested. A non-zero vendorID limits the
+// components to that vendor's products.
+func (s *BundleService) SetBundle(ctx context.Context, productID, vendorID uint, req *models.SetProductBundleRequest) (*models.ProductBundle, error) {
+	if req.PricingMode == models.BundlePricingFixed && req.FixedPrice <= 0 {
+		return nil, fmt.Errorf("%w: fixed_price must be greater than 0", ErrInvalidInput)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").First(&models.Product{}, productID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: product with ID %d not found", ErrProductNotFound, productID)
+			}
+			return fmt.Errorf("%w: failed to find product: %v", ErrDatabaseQuery, err)
+		}
+		if err := validateBundleItems(tx, productID, vendorID, req.Items); err != nil {
+			return err
+		}
+
+		var bundle models.ProductBundle
+		err := tx.Where("product_id = ?", productID).First(&bundle).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: failed to find bundle: %v", ErrDatabaseQuery, err)
+		}
+		bundle.ProductID = productID
+		bundle.PricingMode = req.PricingMode
+		bundle.DiscountPercent = req.DiscountPercent
+		bundle.FixedPrice = req.FixedPrice
+		if req.PricingMode == models.BundlePricingFixed {
+			bundle.DiscountPercent = 0
+		} else {
+			bundle.FixedPrice = 0
+		}
+		if err := tx.Save(&bundle).Error; err != nil {
+			return fmt.Errorf("%w: failed to save bundle: %v", ErrDatabaseQuery, err)
+		}
+
+		if err := tx.Where("bundle_id = ?", bundle.ID).Delete(&models.ProductBundleItem{}).Error; err != nil {
+			return fmt.Errorf("%w: failed to replace bundle items: %v", ErrDatabaseQuery, err)
+		}
+		items := make([]models.ProductBundleItem, 0, len(req.Items))
+		for _, item := range req.Items {
+			items = append(items, models.ProductBundleItem{BundleID: bundle.ID, ComponentID: item.ProductID, Quantity: item.Quantity})
+		}
+		if err := tx.Create(&items).Error; err != nil {
+			return fmt.Errorf("%w: failed to save bundle items: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetBundle(ctx, productID)
+}
+
+// DeleteBundle turns a bundle back into a plain product
+func (s *BundleService) DeleteBundle(ctx context.Context, productID uint) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	// Items go with the bundle through the foreign key cascade
+	result := s.db.WithContext(ctx).Where("product_id = ?", productID).Delete(&models.ProductBundle{})
+	if result.Error != nil {
+		return fmt.Errorf("%w: failed to delete bundle: %v", ErrDatabaseQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: product %d is not a bundle", ErrBundleNotFound, productID)
+	}
+	return nil
+}
+
+func validateBundleItems(tx *gorm.DB, productID, vendorID uint, items []models.ProductBundleItemRequest) error {
+	componentIDs := make([]uint, 0, len(items))
+	seen := make(map[uint]bool, len(items))
+	for _, item := range items {
+		if item.ProductID == productID {
+			return fmt.Errorf("%w: a bundle cannot contain itself", ErrInvalidInput)
+		}
+		if seen[item.ProductID] {
+			return fmt.Errorf("%w: product %d is listed more than once", ErrInvalidInput, item.ProductID)
+		}
+		seen[item.ProductID] = true
+		componentIDs = append(componentIDs, item.ProductID)
+	}
+
+	var found int64
+	if err := tx.Model(&models.Product{}).Where("id IN ?", componentIDs).Count(&found).Error; err != nil {
+		return fmt.Errorf("%w: failed to find components: %v", ErrDatabaseQuery, err)
+	}
+	if int(found) != len(componentIDs) {
+		return fmt.Errorf("%w: one or more component products do not exist", ErrInvalidInput)
+	}
+	if vendorID != 0 {
+		var owned int64
+		if err := tx.Model(&models.Product{}).Scopes(vendorScope(vendorID)).Where("id IN ?", componentIDs).Count(&owned).Error; err != nil {
+			return fmt.Errorf("%w: failed to find components: %v", ErrDatabaseQuery, err)
+		}
+		if owned != found {
+			return fmt.Errorf("%w: bundle components must be products of your vendor", ErrInvalidInput)
+		}
+	}
+
+	var nested int64
+	if err := tx.Model(&models.ProductBundle{}).Where("product_id IN ?", componentIDs).Count(&nested).Error; err != nil {
+		return fmt.Errorf("%w: failed to check components: %v", ErrDatabaseQuery, err)
+	}
+	if nested > 0 {
+		return fmt.Errorf("%w: a bundle cannot contain another bundle", ErrInvalidInput)
+	}
+	if err := tx.Model(&models.ProductBundleItem{}).Where("component_id = ?", productID).Count(&nested).Error; err != nil {
+		return fmt.Errorf("%w: failed to check components: %v", ErrDatabaseQuery, err)
+	}
+	if nested > 0 {
+		return fmt.Errorf("%w: product %d is a component of another bundle", ErrInvalidInput, productID)
+	}
+	return nil
+}
+
+// loadBundle returns the bundle of a product with computed price and
+// availability, or nil when the product is not a bundle.
+func loadBundle(db *gorm.DB, productID uint) (*models.ProductBundle, error) {
+	var bundle models.ProductBundle
+	err := db.Preload("Items.Component").Where("product_id = ?", productID).First(&bundle).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%w: failed to load bundle: %v", ErrDatabaseQuery, err)
+	}
+
+	var sum float64
+	available := math.MaxInt
+	for _, item := range bundle.Items {
+		component := item.Component
+		if component == nil || component.Status != models.ProductStatusActive {
+			available = 0
+			continue
+		}
+		sum += component.Price * float64(item.Quantity)
+		if units := component.Stock / item.Quantity; units < available {
+			available = units
+		}
+	}
+	if len(bundle.Items) == 0 {
+		available = 0
+	}
+
+	if bundle.PricingMode == models.BundlePricingFixed {
+		bundle.Price = bundle.FixedPrice
+	} else {
+		bundle.Price = math.Round(sum*(100-bundle.DiscountPercent)) / 100
+	}
+	bundle.AvailableQuantity = available
+	bundle.InStock = available > 0
+	return &bundle, nil
+}
+
+// bundleItemsTx returns the components of a bundle, or nil for a plain product
+func bundleItemsTx(tx *gorm.DB, productID uint) ([]models.ProductBundleItem, error) {
+	var items []models.ProductBundleItem
+	err := tx.Joins("JOIN product_bundles ON product_bundles.id = product_bundle_items.bundle_id").
+		Where("product_bundles.product_id = ?", productID).
+		Find(&items).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to load bundle items: %v", ErrDatabaseQuery, err)
+	}
+	return items, nil
+}
@@ -0,0 +1,615 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/database"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrCartNotFound      = errors.New("cart not found")
+	ErrCartShareNotFound = errors.New("shared cart not found")
+)
+
+const (
+	// anonymousCartTTL is how long an anonymous cart lives after its last change
+	anonymousCartTTL = 30 * 24 * time.Hour
+	// maxCartItems caps the distinct products in one cart
+	maxCartItems        = 100
+	cartCleanupInterval = time.Hour
+	// cartShareTTL is how long a shared cart link can be viewed and imported
+	cartShareTTL = 7 * 24 * time.Hour
+)
+
+// CartOwner identifies the cart a request works on: the signed-in user's, or
+// else the anonymous cart of Token. StoreID is the resolved store, 0 in
+// single-tenant mode.
+type CartOwner struct {
+	UserID  uint
+	Token   string
+	StoreID uint
+}
+
+// CartService keeps shopping carts for signed-in users and anonymous
+// visitors, and merges a visitor's cart into their own when they sign in
+type CartService struct {
+	db             *gorm.DB
+	productService *ProductService
+	stockService   *StockService
+}
+
+func NewCartService(db *gorm.DB, productService *ProductService, stockService *StockService) *CartService {
+	return &CartService{
+		db:             db,
+		productService: productService,
+		stockService:   stockService,
+	}
+}
+
+// GetCart returns the owner's cart priced for them. An owner without a cart
+// gets an empty one.
+func (s *CartService) GetCart(ctx context.Context, owner CartOwner) (*models.CartView, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var cart models.Cart
+	if err := findCart(database.Conn(queryCtx, s.db), owner, &cart); err != nil {
+		if errors.Is(err, ErrCartNotFound) {
+			return &models.CartView{Items: []models.CartLine{}}, nil
+		}
+		return nil, err
+	}
+	return s.view(ctx, owner.UserID, cart.ID)
+}
+
+// AddItem puts quantity units of a product in the owner's cart, on top of
+// any already there. An anonymous owner without a valid token gets a new
+// cart, whose token is returned once in the view.
+func (s *CartService) AddItem(ctx context.Context, owner CartOwner, req *models.CartItemRequest) (*models.CartView, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var cart models.Cart
+	var token string
+	err := database.Conn(queryCtx, s.db).Transaction(func(tx *gorm.DB) error {
+		var err error
+		token, err = findOrCreateCart(tx, owner, &cart)
+		if err != nil {
+			return err
+		}
+		product, err := findCartProduct(tx, owner.StoreID, req.ProductID)
+		if err != nil {
+			return err
+		}
+
+		var item models.CartItem
+		err = tx.Where("cart_id = ? AND product_id = ?", cart.ID, req.ProductID).First(&item).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			var count int64
+			if err := tx.Model(&models.CartItem{}).Where("cart_id = ?", cart.ID).Count(&count).Error; err != nil {
+				return fmt.Errorf("%w: failed to count cart items: %v", ErrDatabaseQuery, err)
+			}
+			if count >= maxCartItems {
+				return fmt.Errorf("%w: a cart holds at most %d products", ErrInvalidInput, maxCartItems)
+			}
+			item = models.CartItem{CartID: cart.ID, ProductID: req.ProductID}
+		case err != nil:
+			return fmt.Errorf("%w: failed to find cart item: %v", ErrDatabaseQuery, err)
+		}
+
+		item.Quantity += req.Quantity
+		if err := checkOrderQuantity(product, item.Quantity); err != nil {
+			return err
+		}
+		if err := tx.Save(&item).Error; err != nil {
+			return fmt.Errorf("%w: failed to save cart item: %v", ErrDatabaseQuery, err)
+		}
+		return touchCart(tx, &cart)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	view, err := s.view(ctx, owner.UserID, cart.ID)
+	if err != nil {
+		return nil, err
+	}
+	view.Token = token
+	return view, nil
+}
+
+// SetItemQuantity replaces the quantity of a product already in the cart
+func (s *CartService) SetItemQuantity(ctx context.Context, owner CartOwner, productID uint, quantity int) (*models.CartView, error) {
+	if quantity < 1 {
+		return nil, fmt.Errorf("%w: quantity must be at least 1", ErrInvalidInput)
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var cart models.Cart
+	err := database.Conn(queryCtx, s.db).Transaction(func(tx *gorm.DB) error {
+		if err := findCart(tx.Clauses(clause.Locking{Strength: "UPDATE"}), owner, &cart); err != nil {
+			return err
+		}
+		var item models.CartItem
+		if err := findCartItem(tx, cart.ID, productID, &item); err != nil {
+			return err
+		}
+		product, err := findCartProduct(tx, owner.StoreID, productID)
+		if err != nil {
+			return err
+		}
+		if err := checkOrderQuantity(product, quantity); err != nil {
+			return err
+		}
+		if err := tx.Model(&item).Update("quantity", quantity).Error; err != nil {
+			return fmt.Errorf("%w: failed to update cart item: %v", ErrDatabaseQuery, err)
+		}
+		return touchCart(tx, &cart)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.view(ctx, owner.UserID, cart.ID)
+}
+
+// RemoveItem takes a product out of the cart
+func (s *CartService) RemoveItem(ctx context.Context, owner CartOwner, productID uint) (*models.CartView, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var cart models.Cart
+	err := database.Conn(queryCtx, s.db).Transaction(func(tx *gorm.DB) error {
+		if err := findCart(tx.Clauses(clause.Locking{Strength: "UPDATE"}), owner, &cart); err != nil {
+			return err
+		}
+		var item models.CartItem
+		if err := findCartItem(tx, cart.ID, productID, &item); err != nil {
+			return err
+		}
+		if err := tx.Delete(&item).Error; err != nil {
+			return fmt.Errorf("%w: failed to remove cart item: %v", ErrDatabaseQuery, err)
+		}
+		return touchCart(tx, &cart)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.view(ctx, owner.UserID, cart.ID)
+}
+
+// ClearCart empties the owner's cart
+func (s *CartService) ClearCart(ctx context.Context, owner CartOwner) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	return database.Conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		var cart models.Cart
+		if err := findCart(tx.Clauses(clause.Locking{Strength: "UPDATE"}), owner, &cart); err != nil {
+			return err
+		}
+		if err := tx.Where("cart_id = ?", cart.ID).Delete(&models.CartItem{}).Error; err != nil {
+			return fmt.Errorf("%w: failed to clear cart: %v", ErrDatabaseQuery, err)
+		}
+		return touchCart(tx, &cart)
+	})
+}
+
+// MergeAnonymousCart moves the anonymous cart of token into userID's cart
+// and deletes it. Quantities of a product in both carts are summed and
+// capped at the product's order maximum; products no longer for sale are
+// dropped. An unknown or expired token is not an error.
+func (s *CartService) MergeAnonymousCart(ctx context.Context, userID uint, token string) error {
+	if token == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	return database.Conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		var anonymous models.Cart
+		if err := findCart(tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Items"), CartOwner{Token: token}, &anonymous); err != nil {
+			if errors.Is(err, ErrCartNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		var cart models.Cart
+		owner := CartOwner{UserID: userID}
+		if anonymous.StoreID != nil {
+			owner.StoreID = *anonymous.StoreID
+		}
+		if _, err := findOrCreateCart(tx, owner, &cart); err != nil {
+			return err
+		}
+		if err := mergeCartItems(tx, &cart, anonymous.Items); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&anonymous).Error; err != nil {
+			return fmt.Errorf("%w: failed to delete anonymous cart: %v", ErrDatabaseQuery, err)
+		}
+		logger.Info(fmt.Sprintf("Merged anonymous cart %d into cart %d of user %d", anonymous.ID, cart.ID, userID))
+		return touchCart(tx, &cart)
+	})
+}
+
+// ShareCart snapshots the owner's cart behind a new share token
+func (s *CartService) ShareCart(ctx context.Context, owner CartOwner) (*models.CartShareLink, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	token, err := generateCartToken()
+	if err != nil {
+		return nil, err
+	}
+	share := models.CartShare{
+		TokenHash: hashRefreshToken(token),
+		ExpiresAt: time.Now().Add(cartShareTTL),
+	}
+	if owner.UserID != 0 {
+		share.SharedBy = &owner.UserID
+	}
+
+	err = database.Conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		var cart models.Cart
+		err := findCart(tx.Preload("Items"), owner, &cart)
+		if err != nil && !errors.Is(err, ErrCartNotFound) {
+			return err
+		}
+		if len(cart.Items) == 0 {
+			return fmt.Errorf("%w: cannot share an empty cart", ErrInvalidInput)
+		}
+		share.StoreID = cart.StoreID
+		for _, item := range cart.Items {
+			share.Items = append(share.Items, models.CartShareItem{ProductID: item.ProductID, Quantity: item.Quantity})
+		}
+		if err := tx.Create(&share).Error; err != nil {
+			return fmt.Errorf("%w: failed to share cart: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &models.CartShareLink{Token: token, ExpiresAt: share.ExpiresAt}, nil
+}
+
+// GetSharedCart returns the contents of a shared cart, priced for the
+// owner of the cart it would be imported into
+func (s *CartService) GetSharedCart(ctx context.Context, owner CartOwner, token string) (*models.CartView, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var share models.CartShare
+	db := database.Conn(queryCtx, s.db).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("Items.Product", func(db *gorm.DB) *gorm.DB { return db.Select("id", "title", "sku") })
+	if err := findCartShare(db, owner.StoreID, token, &share); err != nil {
+		return nil, err
+	}
+
+	items := make([]models.CartItem, 0, len(share.Items))
+	for _, item := range share.Items {
+		items = append(items, models.CartItem{ProductID: item.ProductID, Quantity: item.Quantity, Product: item.Product})
+	}
+	view, err := s.priceLines(ctx, owner.UserID, items)
+	if err != nil {
+		return nil, err
+	}
+	view.ExpiresAt = &share.ExpiresAt
+	return view, nil
+}
+
+// ImportSharedCart adds a shared cart's contents to the owner's cart by the
+// same rules as merging an anonymous cart on login. An anonymous owner
+// without a cart gets a new one, whose token is returned once in the view.
+func (s *CartService) ImportSharedCart(ctx context.Context, owner CartOwner, token string) (*models.CartView, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var cart models.Cart
+	var cartToken string
+	err := database.Conn(queryCtx, s.db).Transaction(func(tx *gorm.DB) error {
+		var share models.CartShare
+		if err := findCartShare(tx.Preload("Items"), owner.StoreID, token, &share); err != nil {
+			return err
+		}
+
+		var err error
+		cartToken, err = findOrCreateCart(tx, owner, &cart)
+		if err != nil {
+			return err
+		}
+		items := make([]models.CartItem, 0, len(share.Items))
+		for _, item := range share.Items {
+			items = append(items, models.CartItem{ProductID: item.ProductID, Quantity: item.Quantity})
+		}
+		if err := mergeCartItems(tx, &cart, items); err != nil {
+			return err
+		}
+		return touchCart(tx, &cart)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	view, err := s.view(ctx, owner.UserID, cart.ID)
+	if err != nil {
+		return nil, err
+	}
+	view.Token = cartToken
+	return view, nil
+}
+
+// Run deletes expired anonymous carts until ctx is cancelled
+func (s *CartService) Run(ctx context.Context) {
+	ticker := time.NewTicker(cartCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.deleteExpiredCarts(ctx); err != nil {
+				logger.Error("Failed to delete expired carts: ", err)
+			}
+		}
+	}
+}
+
+func (s *CartService) deleteExpiredCarts(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).
+		Where("user_id IS NULL AND expires_at <= ?", time.Now()).
+		Delete(&models.Cart{}).Error; err != nil {
+		return fmt.Errorf("%w: failed to delete expired carts: %v", ErrDatabaseQuery, err)
+	}
+	if err := s.db.WithContext(ctx).
+		Where("expires_at <= ?", time.Now()).
+		Delete(&models.CartShare{}).Error; err != nil {
+		return fmt.Errorf("%w: failed to delete expired cart shares: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
+
+// view prices every line of a cart for userID (0 for visitors) and checks
+// that it can be bought
+func (s *CartService) view(ctx context.Context, userID, cartID uint) (*models.CartView, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var cart models.Cart
+	if err := database.Conn(queryCtx, s.db).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC, id ASC") }).
+		Preload("Items.Product", func(db *gorm.DB) *gorm.DB { return db.Select("id", "title", "sku") }).
+		First(&cart, cartID).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to load cart: %v", ErrDatabaseQuery, err)
+	}
+
+	view, err := s.priceLines(ctx, userID, cart.Items)
+	if err != nil {
+		return nil, err
+	}
+	view.ID = cart.ID
+	view.ExpiresAt = cart.ExpiresAt
+	return view, nil
+}
+
+// priceLines prices items for userID and checks that each can be bought.
+// items must have their product's title and SKU loaded.
+func (s *CartService) priceLines(ctx context.Context, userID uint, items []models.CartItem) (*models.CartView, error) {
+	view := models.CartView{Items: make([]models.CartLine, 0, len(items))}
+	var subtotal float64
+	for _, item := range items {
+		line := models.CartLine{ProductID: item.ProductID, Quantity: item.Quantity}
+		if item.Product != nil {
+			line.Title = item.Product.Title
+			line.SKU = item.Product.SKU
+		}
+
+		price, err := s.productService.QuotePrice(ctx, userID, item.ProductID, item.Quantity)
+		if err == nil {
+			line.UnitPrice = price.UnitPrice
+			line.Total = price.Total
+			err = s.stockService.CheckAvailability(ctx, item.ProductID, item.Quantity)
+		}
+		switch {
+		case err == nil:
+			line.Available = true
+			subtotal += line.Total
+		case errors.Is(err, ErrProductNotFound), errors.Is(err, ErrInsufficientStock), errors.Is(err, ErrInvalidInput):
+			line.Issue = err.Error()
+		default:
+			return nil, err
+		}
+		view.Items = append(view.Items, line)
+	}
+	view.Subtotal = math.Round(subtotal*100) / 100
+	return &view, nil
+}
+
+// findCartShare loads the unexpired share of token, made in the store when
+// storeID is set
+func findCartShare(db *gorm.DB, storeID uint, token string, share *models.CartShare) error {
+	query := db.Where("token_hash = ? AND expires_at > ?", hashRefreshToken(token), time.Now())
+	if storeID != 0 {
+		query = query.Where("store_id = ?", storeID)
+	}
+	if err := query.First(share).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCartShareNotFound
+		}
+		return fmt.Errorf("%w: failed to find shared cart: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
+
+// findCart loads the owner's cart: the user's, or the unexpired anonymous
+// cart of the token
+func findCart(db *gorm.DB, owner CartOwner, cart *models.Cart) error {
+	var query *gorm.DB
+	switch {
+	case owner.UserID != 0:
+		query = db.Where("user_id = ?", owner.UserID)
+	case owner.Token != "":
+		query = db.Where("token_hash = ? AND user_id IS NULL AND expires_at > ?", hashRefreshToken(owner.Token), time.Now())
+	default:
+		return ErrCartNotFound
+	}
+	if err := query.First(cart).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCartNotFound
+		}
+		return fmt.Errorf("%w: failed to find cart: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
+
+// findOrCreateCart loads and locks the owner's cart, creating it if needed.
+// It returns the token of a newly created anonymous cart.
+func findOrCreateCart(tx *gorm.DB, owner CartOwner, cart *models.Cart) (string, error) {
+	err := findCart(tx.Clauses(clause.Locking{Strength: "UPDATE"}), owner, cart)
+	if !errors.Is(err, ErrCartNotFound) {
+		return "", err
+	}
+
+	*cart = models.Cart{}
+	if owner.StoreID != 0 {
+		cart.StoreID = &owner.StoreID
+	}
+	if owner.UserID != 0 {
+		cart.UserID = &owner.UserID
+		// A concurrent request may create the user's cart first
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(cart).Error; err != nil {
+			return "", fmt.Errorf("%w: failed to create cart: %v", ErrDatabaseQuery, err)
+		}
+		return "", findCart(tx.Clauses(clause.Locking{Strength: "UPDATE"}), owner, cart)
+	}
+
+	token, err := generateCartToken()
+	if err != nil {
+		return "", err
+	}
+	hash := hashRefreshToken(token)
+	expiresAt := time.Now().Add(anonymousCartTTL)
+	cart.TokenHash = &hash
+	cart.ExpiresAt = &expiresAt
+	if err := tx.Create(cart).Error; err != nil {
+		return "", fmt.Errorf("%w: failed to create cart: %v", ErrDatabaseQuery, err)
+	}
+	return token, nil
+}
+
+// findCartProduct loads a product that can be put in a cart: active, and in
+// the store when storeID is set
+func findCartProduct(tx *gorm.DB, storeID, productID uint) (*models.Product, error) {
+	query := tx.Select("id", "min_order_qty", "max_order_qty").
+		Where("id = ? AND status = ?", productID, models.ProductStatusActive)
+	if storeID != 0 {
+		query = query.Where("store_id = ?", storeID)
+	}
+	var product models.Product
+	if err := query.First(&product).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: product with ID %d not found", ErrProductNotFound, productID)
+		}
+		return nil, fmt.Errorf("%w: failed to find product: %v", ErrDatabaseQuery, err)
+	}
+	return &product, nil
+}
+
+func findCartItem(tx *gorm.DB, cartID, productID uint, item *models.CartItem) error {
+	if err := tx.Where("cart_id = ? AND product_id = ?", cartID, productID).First(item).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: product %d is not in the cart", ErrCartNotFound, productID)
+		}
+		return fmt.Errorf("%w: failed to find cart item: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
+
+// mergeCartItems adds items to cart, summing the quantities of products
+// already in it and capping them at the product's order maximum. Products
+// that are no longer for sale, and products beyond the cart's item limit,
+// are skipped.
+func mergeCartItems(tx *gorm.DB, cart *models.Cart, items []models.CartItem) error {
+	var existing []models.CartItem
+	if err := tx.Where("cart_id = ?", cart.ID).Find(&existing).Error; err != nil {
+		return fmt.Errorf("%w: failed to load cart items: %v", ErrDatabaseQuery, err)
+	}
+	byProduct := make(map[uint]models.CartItem, len(existing))
+	for _, item := range existing {
+		byProduct[item.ProductID] = item
+	}
+
+	var storeID uint
+	if cart.StoreID != nil {
+		storeID = *cart.StoreID
+	}
+	for _, incoming := range items {
+		product, err := findCartProduct(tx, storeID, incoming.ProductID)
+		if errors.Is(err, ErrProductNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		item, ok := byProduct[incoming.ProductID]
+		if !ok {
+			if len(byProduct) >= maxCartItems {
+				continue
+			}
+			item = models.CartItem{CartID: cart.ID, ProductID: incoming.ProductID}
+		}
+		item.Quantity += incoming.Quantity
+		if product.MaxOrderQty > 0 && item.Quantity > product.MaxOrderQty {
+			item.Quantity = product.MaxOrderQty
+		}
+		if err := tx.Save(&item).Error; err != nil {
+			return fmt.Errorf("%w: failed to save cart item: %v", ErrDatabaseQuery, err)
+		}
+		byProduct[item.ProductID] = item
+	}
+	return nil
+}
+
+// touchCart bumps the cart's updated_at and extends an anonymous cart's life
+func touchCart(tx *gorm.DB, cart *models.Cart) error {
+	updates := map[string]interface{}{"updated_at": time.Now()}
+	if cart.UserID == nil {
+		expiresAt := time.Now().Add(anonymousCartTTL)
+		updates["expires_at"] = expiresAt
+		cart.ExpiresAt = &expiresAt
+	}
+	if err := tx.Model(cart).Updates(updates).Error; err != nil {
+		return fmt.Errorf("%w: failed to update cart: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
+
+func generateCartToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate cart token: %v", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,327 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+)
+
+const (
+	// shopifyAPIVersion is the Shopify Admin REST API release connectors use
+	shopifyAPIVersion = "2024-01"
+	// catalogAPIRetries is how often a rate limited request is retried
+	catalogAPIRetries = 3
+	// maxCatalogRetryWait caps how long a Retry-After header is honoured
+	maxCatalogRetryWait = 10 * time.Second
+)
+
+// catalogListing is a product as it is sent to a remote store. The catalog
+// has no variants, so every product becomes a single-variant listing.
+type catalogListing struct {
+	SKU            string
+	Fields         map[string]string // remote field -> value, from the connector mapping
+	Price          float64
+	CompareAtPrice *float64
+	Stock          int
+	Active         bool
+	Images         []catalogImage
+	SendImages     bool // false when the remote images are already up to date
+}
+
+type catalogImage struct {
+	Src string `json:"src"`
+	Alt string `json:"alt,omitempty"`
+}
+
+// catalogPlatform creates and updates listings in one kind of remote store
+type catalogPlatform interface {
+	// Upsert creates the listing, or updates it when item already has an
+	// external ID, and stores the remote IDs on item
+	Upsert(ctx context.Context, item *models.CatalogSyncItem, listing *catalogListing) (created bool, err error)
+	// Remove takes down the listing of a product that no longer exists
+	Remove(ctx context.Context, item *models.CatalogSyncItem) error
+}
+
+// defaultCatalogMappings are the remote fields each platform fills unless
+// the connector mapping overrides them
+var defaultCatalogMappings = map[string]map[string]string{
+	models.CatalogPlatformShopify: {
+		"title":       "title",
+		"description": "body_html",
+		"category":    "product_type",
+	},
+	models.CatalogPlatformWooCommerce: {
+		"title":       "name",
+		"description": "description",
+	},
+}
+
+func newCatalogPlatform(connector *models.CatalogConnector, client *http.Client) (catalogPlatform, error) {
+	baseURL := strings.TrimRight(connector.StoreURL, "/")
+	switch connector.Platform {
+	case models.CatalogPlatformShopify:
+		api := &catalogAPI{
+			baseURL: baseURL + "/admin/api/" + shopifyAPIVersion,
+			client:  client,
+			authorize: func(req *http.Request) {
+				req.Header.Set("X-Shopify-Access-Token", string(connector.AccessToken))
+			},
+		}
+		return &shopifyPlatform{api: api, locationID: connector.LocationID}, nil
+	case models.CatalogPlatformWooCommerce:
+		api := &catalogAPI{
+			baseURL: baseURL + "/wp-json/wc/v3",
+			client:  client,
+			authorize: func(req *http.Request) {
+				req.SetBasicAuth(string(connector.AccessToken), string(connector.APISecret))
+			},
+		}
+		return &wooCommercePlatform{api: api}, nil
+	default:
+		return nil, fmt.Errorf("unsupported catalog platform %q", connector.Platform)
+	}
+}
+
+// catalogAPIError is a non-2xx response from a remote store
+type catalogAPIError struct {
+	Status int
+	Body   string
+}
+
+func (e *catalogAPIError) Error() string {
+	return fmt.Sprintf("store returned HTTP %d: %s", e.Status, e.Body)
+}
+
+func isCatalogNotFound(err error) bool {
+	var apiErr *catalogAPIError
+	return errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound
+}
+
+// catalogAPI sends JSON requests to a store's REST API, waiting out rate
+// limits
+type catalogAPI struct {
+	baseURL   string
+	client    *http.Client
+	authorize func(req *http.Request)
+}
+
+func (a *catalogAPI) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to encode request: %v", err)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, a.baseURL+path, bytes.NewReader(payload))
+		if err != nil {
+			return fmt.Errorf("invalid store URL: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+		a.authorize(req)
+
+		resp, err := a.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("store request failed: %v", err)
+		}
+		respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read store response: %v", err)
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < catalogAPIRetries {
+			wait := time.Second
+			if seconds, err := strconv.ParseFloat(resp.Header.Get("Retry-After"), 64); err == nil && seconds > 0 {
+				wait = time.Duration(seconds * float64(time.Second))
+			}
+			if wait > maxCatalogRetryWait {
+				wait = maxCatalogRetryWait
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+			continue
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return &catalogAPIError{Status: resp.StatusCode, Body: truncateRunes(strings.TrimSpace(string(respBody)), 300)}
+		}
+		if out != nil && len(respBody) > 0 {
+			if err := json.Unmarshal(respBody, out); err != nil {
+				return fmt.Errorf("failed to parse store response: %v", err)
+			}
+		}
+		return nil
+	}
+}
+
+func formatCatalogPrice(price float64) string {
+	return strconv.FormatFloat(price, 'f', 2, 64)
+}
+
+// shopifyPlatform syncs through the Shopify Admin REST API. Stock is set on
+// the connector's location, since variant quantities are read-only.
+type shopifyPlatform struct {
+	api        *catalogAPI
+	locationID string
+}
+
+func (p *shopifyPlatform) Upsert(ctx context.Context, item *models.CatalogSyncItem, listing *catalogListing) (bool, error) {
+	product := make(map[string]interface{}, len(listing.Fields)+3)
+	for field, value := range listing.Fields {
+		product[field] = value
+	}
+	product["status"] = "draft"
+	if listing.Active {
+		product["status"] = "active"
+	}
+	variant := map[string]interface{}{
+		"sku":                  listing.SKU,
+		"price":                formatCatalogPrice(listing.Price),
+		"compare_at_price":     nil,
+		"inventory_management": "shopify",
+	}
+	if listing.CompareAtPrice != nil {
+		variant["compare_at_price"] = formatCatalogPrice(*listing.CompareAtPrice)
+	}
+	product["variants"] = []map[string]interface{}{variant}
+
+	var result struct {
+		Product struct {
+			ID       int64 `json:"id"`
+			Variants []struct {
+				ID              int64 `json:"id"`
+				InventoryItemID int64 `json:"inventory_item_id"`
+			} `json:"variants"`
+		} `json:"product"`
+	}
+
+	created := item.ExternalID == ""
+	if !created {
+		if id, err := strconv.ParseInt(item.ExternalVariantID, 10, 64); err == nil {
+			variant["id"] = id
+		}
+		if listing.SendImages {
+			product["images"] = listing.Images
+		}
+		err := p.api.do(ctx, http.MethodPut, "/products/"+item.ExternalID+".json", map[string]interface{}{"product": product}, &result)
+		if isCatalogNotFound(err) {
+			// Deleted in the store; list it again
+			delete(variant, "id")
+			created = true
+		} else if err != nil {
+			return false, err
+		}
+	}
+	if created {
+		product["images"] = listing.Images
+		if err := p.api.do(ctx, http.MethodPost, "/products.json", map[string]interface{}{"product": product}, &result); err != nil {
+			return false, err
+		}
+	}
+
+	item.ExternalID = strconv.FormatInt(result.Product.ID, 10)
+	if len(result.Product.Variants) > 0 {
+		item.ExternalVariantID = strconv.FormatInt(result.Product.Variants[0].ID, 10)
+	}
+
+	locationID, err := strconv.ParseInt(p.locationID, 10, 64)
+	if err == nil && len(result.Product.Variants) > 0 && result.Product.Variants[0].InventoryItemID != 0 {
+		level := map[string]interface{}{
+			"location_id":       locationID,
+			"inventory_item_id": result.Product.Variants[0].InventoryItemID,
+			"available":         listing.Stock,
+		}
+		if err := p.api.do(ctx, http.MethodPost, "/inventory_levels/set.json", level, nil); err != nil {
+			return created, fmt.Errorf("failed to set inventory: %v", err)
+		}
+	}
+	return created, nil
+}
+
+// Remove archives the listing so its order history stays in Shopify
+func (p *shopifyPlatform) Remove(ctx context.Context, item *models.CatalogSyncItem) error {
+	body := map[string]interface{}{"product": map[string]interface{}{"status": "archived"}}
+	err := p.api.do(ctx, http.MethodPut, "/products/"+item.ExternalID+".json", body, nil)
+	if isCatalogNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// wooCommercePlatform syncs through the WooCommerce REST API as simple
+// products with managed stock
+type wooCommercePlatform struct {
+	api *catalogAPI
+}
+
+func (p *wooCommercePlatform) Upsert(ctx context.Context, item *models.CatalogSyncItem, listing *catalogListing) (bool, error) {
+	product := make(map[string]interface{}, len(listing.Fields)+8)
+	for field, value := range listing.Fields {
+		product[field] = value
+	}
+	product["type"] = "simple"
+	product["sku"] = listing.SKU
+	product["status"] = "draft"
+	if listing.Active {
+		product["status"] = "publish"
+	}
+	// WooCommerce shows a sale as a sale price below the regular price
+	product["regular_price"] = formatCatalogPrice(listing.Price)
+	product["sale_price"] = ""
+	if listing.CompareAtPrice != nil {
+		product["regular_price"] = formatCatalogPrice(*listing.CompareAtPrice)
+		product["sale_price"] = formatCatalogPrice(listing.Price)
+	}
+	product["manage_stock"] = true
+	product["stock_quantity"] = listing.Stock
+
+	var result struct {
+		ID int64 `json:"id"`
+	}
+
+	created := item.ExternalID == ""
+	if !created {
+		if listing.SendImages {
+			product["images"] = listing.Images
+		}
+		err := p.api.do(ctx, http.MethodPut, "/products/"+item.ExternalID, product, &result)
+		if isCatalogNotFound(err) {
+			created = true
+		} else if err != nil {
+			return false, err
+		}
+	}
+	if created {
+		product["images"] = listing.Images
+		if err := p.api.do(ctx, http.MethodPost, "/products", product, &result); err != nil {
+			return false, err
+		}
+	}
+
+	item.ExternalID = strconv.FormatInt(result.ID, 10)
+	return created, nil
+}
+
+// Remove moves the listing to the WooCommerce trash
+func (p *wooCommercePlatform) Remove(ctx context.Context, item *models.CatalogSyncItem) error {
+	err := p.api.do(ctx, http.MethodDelete, "/products/"+item.ExternalID, nil, nil)
+	if isCatalogNotFound(err) {
+		return nil
+	}
+	return err
+}
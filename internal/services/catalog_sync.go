@@ -0,0 +1,609 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrCatalogConnectorNotFound = errors.New("catalog connector not found")
+	ErrCatalogConnectorBusy     = errors.New("catalog connector is already syncing")
+)
+
+const (
+	catalogRequestTimeout = 30 * time.Second
+	catalogSyncBatchSize  = 100
+	maxCatalogSyncErrors  = 200
+	// catalogRunStaleAfter is when a run still marked running is assumed
+	// lost. Runs page through the catalog under store rate limits, so they
+	// get longer than feed imports.
+	catalogRunStaleAfter = 6 * time.Hour
+)
+
+// CatalogSyncService pushes products, prices, stock and images to external
+// Shopify and WooCommerce stores, on a schedule or on demand. Products are
+// matched to their remote listings through CatalogSyncItem rows.
+type CatalogSyncService struct {
+	db     *gorm.DB
+	client *http.Client
+}
+
+func NewCatalogSyncService(db *gorm.DB) *CatalogSyncService {
+	return &CatalogSyncService{
+		db:     db,
+		client: &http.Client{Timeout: catalogRequestTimeout},
+	}
+}
+
+func (s *CatalogSyncService) GetConnectors(ctx context.Context) ([]models.CatalogConnector, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	connectors := make([]models.CatalogConnector, 0)
+	if err := s.db.WithContext(ctx).Order("created_at DESC").Find(&connectors).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch catalog connectors: %v", ErrDatabaseQuery, err)
+	}
+	return connectors, nil
+}
+
+func (s *CatalogSyncService) GetConnectorByID(ctx context.Context, id uint) (*models.CatalogConnector, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var connector models.CatalogConnector
+	if err := s.db.WithContext(ctx).First(&connector, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCatalogConnectorNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to fetch catalog connector: %v", ErrDatabaseQuery, err)
+	}
+	return &connector, nil
+}
+
+func (s *CatalogSyncService) CreateConnector(ctx context.Context, userID uint, req *models.CreateCatalogConnectorRequest) (*models.CatalogConnector, error) {
+	connector := &models.CatalogConnector{
+		Name:        strings.TrimSpace(req.Name),
+		Platform:    req.Platform,
+		StoreURL:    strings.TrimSpace(req.StoreURL),
+		AccessToken: models.EncryptedString(strings.TrimSpace(req.AccessToken)),
+		APISecret:   models.EncryptedString(strings.TrimSpace(req.APISecret)),
+		LocationID:  strings.TrimSpace(req.LocationID),
+		Mapping:     req.Mapping,
+		Schedule:    strings.TrimSpace(req.Schedule),
+		IsActive:    true,
+		CreatedBy:   userID,
+	}
+	if req.IsActive != nil {
+		connector.IsActive = *req.IsActive
+	}
+
+	if err := s.prepareConnector(connector); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Create(connector).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to create catalog connector: %v", ErrDatabaseQuery, err)
+	}
+	return connector, nil
+}
+
+// UpdateConnector changes a connector. Credentials are only replaced when
+// given, since they are never returned to read back.
+func (s *CatalogSyncService) UpdateConnector(ctx context.Context, id uint, req *models.UpdateCatalogConnectorRequest) (*models.CatalogConnector, error) {
+	connector, err := s.GetConnectorByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		connector.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.StoreURL != nil {
+		connector.StoreURL = strings.TrimSpace(*req.StoreURL)
+	}
+	if req.AccessToken != nil && strings.TrimSpace(*req.AccessToken) != "" {
+		connector.AccessToken = models.EncryptedString(strings.TrimSpace(*req.AccessToken))
+	}
+	if req.APISecret != nil && strings.TrimSpace(*req.APISecret) != "" {
+		connector.APISecret = models.EncryptedString(strings.TrimSpace(*req.APISecret))
+	}
+	if req.LocationID != nil {
+		connector.LocationID = strings.TrimSpace(*req.LocationID)
+	}
+	if req.Mapping != nil {
+		connector.Mapping = req.Mapping
+	}
+	if req.Schedule != nil {
+		connector.Schedule = strings.TrimSpace(*req.Schedule)
+	}
+	if req.IsActive != nil {
+		connector.IsActive = *req.IsActive
+	}
+
+	if err := s.prepareConnector(connector); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Save(connector).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to update catalog connector: %v", ErrDatabaseQuery, err)
+	}
+	return connector, nil
+}
+
+// DeleteConnector removes a connector with its runs and product links. The
+// listings stay in the remote store.
+func (s *CatalogSyncService) DeleteConnector(ctx context.Context, id uint) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.CatalogConnector{}, id)
+		if result.Error != nil {
+			return fmt.Errorf("%w: failed to delete catalog connector: %v", ErrDatabaseQuery, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrCatalogConnectorNotFound
+		}
+		if err := tx.Where("connector_id = ?", id).Delete(&models.CatalogSyncItem{}).Error; err != nil {
+			return fmt.Errorf("%w: failed to delete catalog sync items: %v", ErrDatabaseQuery, err)
+		}
+		if err := tx.Where("connector_id = ?", id).Delete(&models.CatalogSyncRun{}).Error; err != nil {
+			return fmt.Errorf("%w: failed to delete catalog sync runs: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+}
+
+func (s *CatalogSyncService) GetRuns(ctx context.Context, connectorID uint, page, limit int) ([]models.CatalogSyncRun, int64, error) {
+	if _, err := s.GetConnectorByID(ctx, connectorID); err != nil {
+		return nil, 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	query := s.db.WithContext(ctx).Model(&models.CatalogSyncRun{}).Where("connector_id = ?", connectorID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to count catalog sync runs: %v", ErrDatabaseQuery, err)
+	}
+
+	runs := make([]models.CatalogSyncRun, 0)
+	if err := query.Order("started_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&runs).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to fetch catalog sync runs: %v", ErrDatabaseQuery, err)
+	}
+	return runs, total, nil
+}
+
+// TriggerRun pushes the catalog now instead of waiting for the schedule.
+// A full run sends every product, not just those changed since the last run.
+func (s *CatalogSyncService) TriggerRun(ctx context.Context, id uint, full bool) (*models.CatalogSyncRun, error) {
+	connector, err := s.GetConnectorByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := s.startRun(connector, "manual", full)
+	if err != nil {
+		return nil, err
+	}
+	go s.sync(context.Background(), connector, run)
+	return run, nil
+}
+
+// Run is the scheduler loop: every tick it syncs the active connectors that
+// are due.
+func (s *CatalogSyncService) Run(ctx context.Context) {
+	ticker := time.NewTicker(feedSchedulerTick)
+	defer ticker.Stop()
+
+	s.failStaleRuns(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.failStaleRuns(ctx)
+			s.runDueConnectors(ctx)
+		}
+	}
+}
+
+// failStaleRuns fails runs left running by a crashed instance, which would
+// otherwise keep their connector busy forever
+func (s *CatalogSyncService) failStaleRuns(ctx context.Context) {
+	now := time.Now()
+	result := s.db.WithContext(ctx).Model(&models.CatalogSyncRun{}).
+		Where("status = ? AND started_at < ?", models.CatalogSyncStatusRunning, now.Add(-catalogRunStaleAfter)).
+		Updates(map[string]interface{}{
+			"status":      models.CatalogSyncStatusFailed,
+			"error":       "run did not finish and was abandoned",
+			"finished_at": now,
+		})
+	if result.Error != nil {
+		logger.Error("Failed to fail stale catalog sync runs: ", result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		logger.Warn("Failed ", result.RowsAffected, " stale catalog sync runs")
+	}
+}
+
+func (s *CatalogSyncService) runDueConnectors(ctx context.Context) {
+	var connectors []models.CatalogConnector
+	if err := s.db.WithContext(ctx).
+		Where("is_active = ? AND next_run_at <= ?", true, time.Now()).
+		Find(&connectors).Error; err != nil {
+		logger.Error("Failed to load due catalog connectors: ", err)
+		return
+	}
+
+	for i := range connectors {
+		connector := &connectors[i]
+		next, err := nextScheduledRun(connector.Schedule, time.Now())
+		if err != nil {
+			logger.Error("Invalid schedule on catalog connector ", connector.ID, ": ", err)
+			continue
+		}
+
+		// Claim the slot so only one instance runs this occurrence
+		result := s.db.WithContext(ctx).Model(&models.CatalogConnector{}).
+			Where("id = ? AND next_run_at = ?", connector.ID, connector.NextRunAt).
+			Update("next_run_at", next)
+		if result.Error != nil || result.RowsAffected == 0 {
+			continue
+		}
+
+		run, err := s.startRun(connector, "schedule", false)
+		if err != nil {
+			logger.Error("Failed to start catalog sync for connector ", connector.ID, ": ", err)
+			continue
+		}
+		s.sync(ctx, connector, run)
+	}
+}
+
+// startRun records a new run unless the connector already has one running.
+// The connector row is locked while checking, so two triggers cannot both
+// start.
+func (s *CatalogSyncService) startRun(connector *models.CatalogConnector, trigger string, full bool) (*models.CatalogSyncRun, error) {
+	run := &models.CatalogSyncRun{
+		ConnectorID: connector.ID,
+		Status:      models.CatalogSyncStatusRunning,
+		Trigger:     trigger,
+		Full:        full,
+		StartedAt:   time.Now(),
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var locked models.CatalogConnector
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&locked, connector.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCatalogConnectorNotFound
+			}
+			return fmt.Errorf("%w: failed to lock catalog connector: %v", ErrDatabaseQuery, err)
+		}
+
+		var running int64
+		if err := tx.Model(&models.CatalogSyncRun{}).
+			Where("connector_id = ? AND status = ?", connector.ID, models.CatalogSyncStatusRunning).
+			Count(&running).Error; err != nil {
+			return fmt.Errorf("%w: failed to check running catalog syncs: %v", ErrDatabaseQuery, err)
+		}
+		if running > 0 {
+			return ErrCatalogConnectorBusy
+		}
+
+		if err := tx.Create(run).Error; err != nil {
+			return fmt.Errorf("%w: failed to create catalog sync run: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// sync pushes the products updated since the connector's high-water mark,
+// oldest first, then takes down the listings of deleted products. The mark
+// only advances past products that synced, so a product the store rejected
+// is sent again next run.
+func (s *CatalogSyncService) sync(ctx context.Context, connector *models.CatalogConnector, run *models.CatalogSyncRun) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.finishRun(run, fmt.Errorf("sync panicked: %v", r))
+		}
+	}()
+
+	platform, err := newCatalogPlatform(connector, s.client)
+	if err != nil {
+		s.finishRun(run, err)
+		return
+	}
+	mapping := catalogMapping(connector)
+
+	query := func() *gorm.DB {
+		q := s.db.WithContext(ctx).Model(&models.Product{})
+		if !run.Full && connector.SyncedThrough != nil {
+			q = q.Where("(updated_at, id) > (?, ?)", *connector.SyncedThrough, connector.SyncedThroughID)
+		}
+		return q
+	}
+
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		s.finishRun(run, fmt.Errorf("%w: failed to count products: %v", ErrDatabaseQuery, err))
+		return
+	}
+	run.Total = int(total)
+
+	markTime, markID := connector.SyncedThrough, connector.SyncedThroughID
+	if run.Full {
+		markTime, markID = nil, 0
+	}
+	stalled := false
+
+	var lastTime time.Time
+	var lastID uint
+	for first := true; ; first = false {
+		q := query()
+		if !first {
+			q = q.Where("(updated_at, id) > (?, ?)", lastTime, lastID)
+		}
+		var batch []models.Product
+		if err := q.Preload("Images", "is_active = ?", true).
+			Order("updated_at ASC, id ASC").
+			Limit(catalogSyncBatchSize).
+			Find(&batch).Error; err != nil {
+			s.finishRun(run, fmt.Errorf("%w: failed to read products: %v", ErrDatabaseQuery, err))
+			return
+		}
+		if len(batch) == 0 {
+			break
+		}
+		lastTime, lastID = batch[len(batch)-1].UpdatedAt, batch[len(batch)-1].ID
+
+		items, err := s.loadSyncItems(ctx, connector.ID, batch)
+		if err != nil {
+			s.finishRun(run, err)
+			return
+		}
+
+		for i := range batch {
+			if err := ctx.Err(); err != nil {
+				s.finishRun(run, err)
+				return
+			}
+			product := &batch[i]
+			if err := s.pushProduct(ctx, platform, mapping, connector.ID, product, items[product.ID], run); err != nil {
+				s.recordSyncError(run, product, err)
+				stalled = true
+				continue
+			}
+			if !stalled {
+				updatedAt := product.UpdatedAt
+				markTime, markID = &updatedAt, product.ID
+			}
+		}
+	}
+
+	if err := s.removeDeleted(ctx, platform, connector.ID, run); err != nil {
+		s.finishRun(run, err)
+		return
+	}
+
+	now := time.Now()
+	connector.SyncedThrough, connector.SyncedThroughID = markTime, markID
+	connector.LastRunAt = &now
+	if err := s.db.Model(connector).Select("synced_through", "synced_through_id", "last_run_at").Updates(connector).Error; err != nil {
+		logger.Error("Failed to update catalog connector ", connector.ID, ": ", err)
+	}
+
+	s.finishRun(run, nil)
+}
+
+func (s *CatalogSyncService) loadSyncItems(ctx context.Context, connectorID uint, products []models.Product) (map[uint]*models.CatalogSyncItem, error) {
+	ids := make([]uint, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.ID)
+	}
+
+	var items []models.CatalogSyncItem
+	if err := s.db.WithContext(ctx).Where("connector_id = ? AND product_id IN ?", connectorID, ids).Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to load catalog sync items: %v", ErrDatabaseQuery, err)
+	}
+	byProduct := make(map[uint]*models.CatalogSyncItem, len(items))
+	for i := range items {
+		byProduct[items[i].ProductID] = &items[i]
+	}
+	return byProduct, nil
+}
+
+// pushProduct creates or updates one product's listing and records the link
+func (s *CatalogSyncService) pushProduct(ctx context.Context, platform catalogPlatform, mapping map[string]string, connectorID uint, product *models.Product, item *models.CatalogSyncItem, run *models.CatalogSyncRun) error {
+	if item == nil {
+		item = &models.CatalogSyncItem{ConnectorID: connectorID, ProductID: product.ID}
+	}
+
+	values := map[string]string{
+		"title":       product.Title,
+		"description": product.Description,
+		"category":    product.Category,
+		"material":    product.Material,
+		"size":        product.Size,
+	}
+	listing := &catalogListing{
+		SKU:            product.SKU,
+		Fields:         make(map[string]string, len(mapping)),
+		Price:          product.Price,
+		CompareAtPrice: product.CompareAtPrice,
+		Stock:          product.Stock,
+		Active:         product.Status == models.ProductStatusActive,
+	}
+	for field, remote := range mapping {
+		listing.Fields[remote] = values[field]
+	}
+
+	hasher := sha256.New()
+	for _, image := range product.Images {
+		listing.Images = append(listing.Images, catalogImage{Src: image.S3URL, Alt: image.AltText})
+		fmt.Fprintf(hasher, "%s\x00%s\x00", image.S3URL, image.AltText)
+	}
+	imagesHash := hex.EncodeToString(hasher.Sum(nil))
+	listing.SendImages = item.ImagesHash != imagesHash
+
+	created, upsertErr := platform.Upsert(ctx, item, listing)
+	if item.ExternalID == "" {
+		return upsertErr
+	}
+
+	// Record the listing even when a later step such as setting stock
+	// failed, so the next run updates it instead of listing it twice
+	if upsertErr == nil {
+		item.ImagesHash = imagesHash
+	}
+	item.SyncedAt = time.Now()
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "connector_id"}, {Name: "product_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"external_id", "external_variant_id", "images_hash", "synced_at"}),
+	}).Create(item).Error
+	if err != nil {
+		return fmt.Errorf("%w: listed as %s but failed to record it: %v", ErrDatabaseQuery, item.ExternalID, err)
+	}
+	if upsertErr != nil {
+		return upsertErr
+	}
+
+	if created {
+		run.Created++
+	} else {
+		run.Updated++
+	}
+	return nil
+}
+
+// removeDeleted takes down the listings of products that were deleted here
+func (s *CatalogSyncService) removeDeleted(ctx context.Context, platform catalogPlatform, connectorID uint, run *models.CatalogSyncRun) error {
+	var orphans []models.CatalogSyncItem
+	if err := s.db.WithContext(ctx).
+		Where("connector_id = ? AND NOT EXISTS (SELECT 1 FROM products WHERE products.id = catalog_sync_items.product_id)", connectorID).
+		Find(&orphans).Error; err != nil {
+		return fmt.Errorf("%w: failed to find deleted products: %v", ErrDatabaseQuery, err)
+	}
+
+	for i := range orphans {
+		item := &orphans[i]
+		if err := platform.Remove(ctx, item); err != nil {
+			s.recordSyncError(run, &models.Product{ID: item.ProductID}, fmt.Errorf("failed to remove listing %s: %v", item.ExternalID, err))
+			continue
+		}
+		if err := s.db.WithContext(ctx).Delete(item).Error; err != nil {
+			return fmt.Errorf("%w: failed to delete catalog sync item: %v", ErrDatabaseQuery, err)
+		}
+		run.Removed++
+	}
+	return nil
+}
+
+func (s *CatalogSyncService) recordSyncError(run *models.CatalogSyncRun, product *models.Product, err error) {
+	run.Failed++
+	if len(run.Errors) < maxCatalogSyncErrors {
+		run.Errors = append(run.Errors, models.CatalogSyncError{ProductID: product.ID, SKU: product.SKU, Message: err.Error()})
+	}
+}
+
+func (s *CatalogSyncService) finishRun(run *models.CatalogSyncRun, err error) {
+	now := time.Now()
+	run.FinishedAt = &now
+	run.Status = models.CatalogSyncStatusSucceeded
+	if err != nil {
+		run.Status = models.CatalogSyncStatusFailed
+		run.Error = err.Error()
+		logger.Error("Catalog sync run ", run.ID, " for connector ", run.ConnectorID, " failed: ", err)
+	}
+	if err := s.db.Save(run).Error; err != nil {
+		logger.Error("Failed to save catalog sync run ", run.ID, ": ", err)
+	}
+}
+
+// catalogMapping is the platform's default mapping with the connector's
+// overrides applied; a field mapped to "" is not sent
+func catalogMapping(connector *models.CatalogConnector) map[string]string {
+	mapping := make(map[string]string)
+	for field, remote := range defaultCatalogMappings[connector.Platform] {
+		mapping[field] = remote
+	}
+	for field, remote := range connector.Mapping {
+		if remote = strings.TrimSpace(remote); remote == "" {
+			delete(mapping, field)
+			continue
+		}
+		mapping[field] = remote
+	}
+	return mapping
+}
+
+// prepareConnector validates a connector and computes its next run time
+func (s *CatalogSyncService) prepareConnector(connector *models.CatalogConnector) error {
+	if connector.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidInput)
+	}
+	if _, ok := defaultCatalogMappings[connector.Platform]; !ok {
+		return fmt.Errorf("%w: unsupported platform %q", ErrInvalidInput, connector.Platform)
+	}
+	// Credentials go with every request
+	if !strings.HasPrefix(connector.StoreURL, "https://") {
+		return fmt.Errorf("%w: store_url must be https", ErrInvalidInput)
+	}
+	if connector.AccessToken == "" {
+		return fmt.Errorf("%w: access_token is required", ErrInvalidInput)
+	}
+	if connector.Platform == models.CatalogPlatformWooCommerce && connector.APISecret == "" {
+		return fmt.Errorf("%w: api_secret is required for WooCommerce", ErrInvalidInput)
+	}
+
+	known := make(map[string]bool, len(models.CatalogSyncFields))
+	for _, field := range models.CatalogSyncFields {
+		known[field] = true
+	}
+	for field := range connector.Mapping {
+		if !known[field] {
+			return fmt.Errorf("%w: unknown product field %q", ErrInvalidInput, field)
+		}
+	}
+	if catalogMapping(connector)["title"] == "" {
+		return fmt.Errorf("%w: mapping must send title", ErrInvalidInput)
+	}
+
+	connector.NextRunAt = nil
+	if connector.Schedule != "" {
+		next, err := nextScheduledRun(connector.Schedule, time.Now())
+		if err != nil {
+			return fmt.Errorf("%w: invalid schedule: %v", ErrInvalidInput, err)
+		}
+		connector.NextRunAt = &next
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrCategoryExists   = errors.New("category already exists")
+)
+
+// Catalog change actions, sent in catalog.changed events
+const (
+	CatalogCategoryRenamed = "category_renamed"
+	CatalogCategoryMerged  = "category_merged"
+)
+
+// CategoryChange reports what a rename or merge changed
+type CategoryChange struct {
+	Action        string `json:"action"`
+	From          string `json:"from"`
+	To            string `json:"to"`
+	Products      int64  `json:"products"`
+	GroupPrices   int64  `json:"group_prices"`
+	SavedSearches int64  `json:"saved_searches"`
+}
+
+// CategoryService renames and merges the free-text product categories.
+// Categories match case-insensitively, as customer group prices do, so a
+// rename can also fix the case of a category.
+type CategoryService struct {
+	db *gorm.DB
+}
+
+func NewCategoryService(db *gorm.DB) *CategoryService {
+	return &CategoryService{db: db}
+}
+
+// RenameCategory moves every product in from to the new name to. Renaming
+// onto another existing category is refused; that is a merge.
+func (s *CategoryService) RenameCategory(ctx context.Context, storeID uint, req *models.RenameCategoryRequest) (*CategoryChange, error) {
+	from, to := strings.TrimSpace(req.From), strings.TrimSpace(req.To)
+	if from == "" || to == "" {
+		return nil, fmt.Errorf("%w: category names cannot be empty", ErrInvalidInput)
+	}
+	if from == to {
+		return nil, fmt.Errorf("%w: the new name is the same as the current one", ErrInvalidInput)
+	}
+	return s.moveCategory(ctx, storeID, CatalogCategoryRenamed, from, to, !strings.EqualFold(from, to))
+}
+
+// MergeCategories moves every product in source to target, which must
+// already exist. Where a customer group has prices for both, target's is
+// kept.
+func (s *CategoryService) MergeCategories(ctx context.Context, storeID uint, req *models.MergeCategoriesRequest) (*CategoryChange, error) {
+	source, target := strings.TrimSpace(req.Source), strings.TrimSpace(req.Target)
+	if source == "" || target == "" {
+		return nil, fmt.Errorf("%w: category names cannot be empty", ErrInvalidInput)
+	}
+	if strings.EqualFold(source, target) {
+		return nil, fmt.Errorf("%w: cannot merge a category into itself", ErrInvalidInput)
+	}
+	return s.moveCategory(ctx, storeID, CatalogCategoryMerged, source, target, false)
+}
+
+// moveCategory relabels the products in from as to, in the caller's store
+// when storeID is set. Customer group prices and saved searches are global,
+// so they follow only once no product in any store is left in from.
+func (s *CategoryService) moveCategory(ctx context.Context, storeID uint, action, from, to string, toMustBeNew bool) (*CategoryChange, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	change := &CategoryChange{Action: action, From: from, To: to}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		products := func() *gorm.DB {
+			return tx.Model(&models.Product{}).Scopes(storeScope(storeID))
+		}
+
+		var existing int64
+		if err := products().Where("LOWER(TRIM(category)) = LOWER(?)", to).Count(&existing).Error; err != nil {
+			return fmt.Errorf("%w: failed to look up category: %v", ErrDatabaseQuery, err)
+		}
+		if toMustBeNew && existing > 0 {
+			return fmt.Errorf("%w: %q; merge the categories instead", ErrCategoryExists, to)
+		}
+		if action == CatalogCategoryMerged && existing == 0 {
+			return fmt.Errorf("%w: %q", ErrCategoryNotFound, to)
+		}
+
+		result := products().
+			Where("LOWER(TRIM(category)) = LOWER(?)", from).
+			UpdateColumns(map[string]interface{}{"category": to, "updated_at": gorm.Expr("NOW()")})
+		if result.Error != nil {
+			return fmt.Errorf("%w: failed to update products: %v", ErrDatabaseQuery, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: %q", ErrCategoryNotFound, from)
+		}
+		change.Products = result.RowsAffected
+
+		var remaining int64
+		if err := tx.Model(&models.Product{}).Where("LOWER(TRIM(category)) = LOWER(?)", from).Count(&remaining).Error; err != nil {
+			return fmt.Errorf("%w: failed to look up category: %v", ErrDatabaseQuery, err)
+		}
+		if remaining == 0 {
+			if err := s.moveCategoryReferences(tx, change); err != nil {
+				return err
+			}
+		}
+
+		return recordOutboxEvent(tx, EventCatalogChanged, "category", to, change)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// moveCategoryReferences points customer group prices and saved searches at
+// the new category. A group that already prices the new category keeps that
+// price and drops the old one.
+func (s *CategoryService) moveCategoryReferences(tx *gorm.DB, change *CategoryChange) error {
+	err := tx.Where("LOWER(TRIM(category)) = LOWER(?)", change.From).
+		Where("group_id IN (?)", tx.Model(&models.CustomerGroupPrice{}).
+			Select("group_id").
+			Where("LOWER(TRIM(category)) = LOWER(?)", change.To)).
+		Where("NOT LOWER(TRIM(category)) = LOWER(?)", change.To).
+		Delete(&models.CustomerGroupPrice{}).Error
+	if err != nil {
+		return fmt.Errorf("%w: failed to drop duplicate group prices: %v", ErrDatabaseQuery, err)
+	}
+
+	result := tx.Model(&models.CustomerGroupPrice{}).
+		Where("LOWER(TRIM(category)) = LOWER(?)", change.From).
+		Update("category", change.To)
+	if result.Error != nil {
+		return fmt.Errorf("%w: failed to update group prices: %v", ErrDatabaseQuery, result.Error)
+	}
+	change.GroupPrices = result.RowsAffected
+
+	result = tx.Model(&models.SavedSearch{}).
+		Where("LOWER(TRIM(category)) = LOWER(?)", change.From).
+		Update("category", change.To)
+	if result.Error != nil {
+		return fmt.Errorf("%w: failed to update saved searches: %v", ErrDatabaseQuery, result.Error)
+	}
+	change.SavedSearches = result.RowsAffected
+	return nil
+}
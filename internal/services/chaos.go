@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+)
+
+const chaosRefresh = 30 * time.Second
+
+var ErrChaosRuleNotFound = errors.New("chaos rule not found")
+
+// ChaosFault is what to do to one request: wait Latency, then either fail
+// with Status, drop the connection, or carry on when both are unset
+type ChaosFault struct {
+	RuleID  uint
+	Latency time.Duration
+	Status  int
+	Drop    bool
+}
+
+// ChaosService decides which requests get injected failures. Rules are
+// served from memory and reloaded every 30 seconds and after this instance's
+// own writes, so every instance of a staging deployment follows the same rules.
+type ChaosService struct {
+	db      *gorm.DB
+	enabled bool
+
+	mu    sync.RWMutex
+	rules []models.ChaosRule
+}
+
+func NewChaosService(db *gorm.DB, cfg *config.Config) *ChaosService {
+	return &ChaosService{db: db, enabled: cfg.ChaosEnabled}
+}
+
+// Enabled reports whether CHAOS_ENABLED is set
+func (s *ChaosService) Enabled() bool {
+	return s.enabled
+}
+
+// Run loads the rules and refreshes them until the context is cancelled. It
+// returns at once when chaos injection is disabled.
+func (s *ChaosService) Run(ctx context.Context) {
+	if !s.enabled {
+		return
+	}
+
+	if err := s.Reload(ctx); err != nil {
+		logger.Error("Failed to load chaos rules: ", err)
+	}
+
+	ticker := time.NewTicker(chaosRefresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Reload(ctx); err != nil {
+				logger.Error("Failed to refresh chaos rules: ", err)
+			}
+		}
+	}
+}
+
+// Reload replaces the in-memory rules with the enabled, unexpired rows
+func (s *ChaosService) Reload(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var rules []models.ChaosRule
+	if err := s.db.WithContext(ctx).
+		Where("enabled = ? AND (expires_at IS NULL OR expires_at > ?)", true, time.Now()).
+		Order("id").
+		Find(&rules).Error; err != nil {
+		return fmt.Errorf("%w: failed to load chaos rules: %v", ErrDatabaseQuery, err)
+	}
+
+	s.mu.Lock()
+	s.rules = rules
+	s.mu.Unlock()
+	return nil
+}
+
+// FaultFor rolls the dice for a request on method and route. The first
+// matching rule applies; nil means the request runs normally.
+func (s *ChaosService) FaultFor(method, route string) *ChaosFault {
+	if !s.enabled || route == "" {
+		return nil
+	}
+
+	now := time.Now()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, rule := range s.rules {
+		if !chaosRouteMatches(rule.Route, route) || (rule.Method != "" && rule.Method != method) {
+			continue
+		}
+		if rule.ExpiresAt != nil && !rule.ExpiresAt.After(now) {
+			continue
+		}
+
+		fault := &ChaosFault{RuleID: rule.ID, Latency: time.Duration(rule.LatencyMs) * time.Millisecond}
+		if rule.JitterMs > 0 {
+			fault.Latency += time.Duration(rand.Intn(rule.JitterMs+1)) * time.Millisecond
+		}
+		roll := rand.Intn(100)
+		switch {
+		case roll < rule.DropPercent:
+			fault.Drop = true
+		case roll < rule.DropPercent+rule.ErrorPercent:
+			fault.Status = rule.ErrorStatus
+			if fault.Status == 0 {
+				fault.Status = http.StatusServiceUnavailable
+			}
+		}
+		return fault
+	}
+	return nil
+}
+
+// chaosRouteMatches compares a rule's route with a request's route pattern;
+// a trailing * matches any route with that prefix
+func chaosRouteMatches(ruleRoute, route string) bool {
+	if prefix, ok := strings.CutSuffix(ruleRoute, "*"); ok {
+		return strings.HasPrefix(route, prefix)
+	}
+	return ruleRoute == route
+}
+
+func (s *ChaosService) GetRules(ctx context.Context) ([]models.ChaosRule, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	rules := make([]models.ChaosRule, 0)
+	if err := s.db.WithContext(ctx).Order("id").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch chaos rules: %v", ErrDatabaseQuery, err)
+	}
+	return rules, nil
+}
+
+func (s *ChaosService) getRule(ctx context.Context, id uint) (*models.ChaosRule, error) {
+	var rule models.ChaosRule
+	if err := s.db.WithContext(ctx).First(&rule, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrChaosRuleNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to fetch chaos rule: %v", ErrDatabaseQuery, err)
+	}
+	return &rule, nil
+}
+
+func (s *ChaosService) CreateRule(ctx context.Context, adminID uint, req *models.CreateChaosRuleRequest) (*models.ChaosRule, error) {
+	if !s.enabled {
+		return nil, fmt.Errorf("%w: chaos injection is disabled; set CHAOS_ENABLED outside production", ErrInvalidInput)
+	}
+	if req.ErrorPercent+req.DropPercent > 100 {
+		return nil, fmt.Errorf("%w: error_percent and drop_percent add up to more than 100", ErrInvalidInput)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	rule := &models.ChaosRule{
+		Method:       req.Method,
+		Route:        strings.TrimSpace(req.Route),
+		LatencyMs:    req.LatencyMs,
+		JitterMs:     req.JitterMs,
+		ErrorPercent: req.ErrorPercent,
+		ErrorStatus:  req.ErrorStatus,
+		DropPercent:  req.DropPercent,
+		Enabled:      true,
+		ExpiresAt:    req.ExpiresAt,
+		CreatedBy:    adminID,
+	}
+	if rule.ErrorStatus == 0 {
+		rule.ErrorStatus = http.StatusServiceUnavailable
+	}
+	if err := s.db.WithContext(ctx).Create(rule).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to create chaos rule: %v", ErrDatabaseQuery, err)
+	}
+
+	s.reloadAfterWrite(ctx)
+	return rule, nil
+}
+
+func (s *ChaosService) UpdateRule(ctx context.Context, id uint, req *models.UpdateChaosRuleRequest) (*models.ChaosRule, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	rule, err := s.getRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updateData := make(map[string]interface{})
+	if req.LatencyMs != nil {
+		updateData["latency_ms"], rule.LatencyMs = *req.LatencyMs, *req.LatencyMs
+	}
+	if req.JitterMs != nil {
+		updateData["jitter_ms"], rule.JitterMs = *req.JitterMs, *req.JitterMs
+	}
+	if req.ErrorPercent != nil {
+		updateData["error_percent"], rule.ErrorPercent = *req.ErrorPercent, *req.ErrorPercent
+	}
+	if req.ErrorStatus != nil {
+		updateData["error_status"] = *req.ErrorStatus
+	}
+	if req.DropPercent != nil {
+		updateData["drop_percent"], rule.DropPercent = *req.DropPercent, *req.DropPercent
+	}
+	if req.Enabled != nil {
+		updateData["enabled"] = *req.Enabled
+	}
+	if req.ExpiresAt != nil {
+		updateData["expires_at"] = *req.ExpiresAt
+	}
+	if rule.ErrorPercent+rule.DropPercent > 100 {
+		return nil, fmt.Errorf("%w: error_percent and drop_percent add up to more than 100", ErrInvalidInput)
+	}
+	if len(updateData) == 0 {
+		return rule, nil
+	}
+
+	if err := s.db.WithContext(ctx).Model(rule).Updates(updateData).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to update chaos rule: %v", ErrDatabaseQuery, err)
+	}
+
+	s.reloadAfterWrite(ctx)
+	return s.getRule(ctx, id)
+}
+
+func (s *ChaosService) DeleteRule(ctx context.Context, id uint) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).Delete(&models.ChaosRule{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("%w: failed to delete chaos rule: %v", ErrDatabaseQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrChaosRuleNotFound
+	}
+
+	s.reloadAfterWrite(ctx)
+	return nil
+}
+
+// reloadAfterWrite applies a change on this instance right away; other
+// instances pick it up on their next refresh.
+func (s *ChaosService) reloadAfterWrite(ctx context.Context) {
+	if err := s.Reload(ctx); err != nil {
+		logger.Warn("Failed to reload chaos rules after update: ", err)
+	}
+}
@@ -0,0 +1,293 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// MaxCollectionProducts keeps storefront sections a reasonable size
+const MaxCollectionProducts = 100
+
+var (
+	ErrCollectionNotFound = errors.New("collection not found")
+	ErrCollectionExists   = errors.New("collection slug already in use")
+)
+
+// CollectionService manages curated product lists. Collections belong to a
+// store in multi-tenant mode; a zero store ID means single-tenant.
+type CollectionService struct {
+	db             *gorm.DB
+	productService *ProductService
+}
+
+func NewCollectionService(db *gorm.DB, productService *ProductService) *CollectionService {
+	return &CollectionService{db: db, productService: productService}
+}
+
+// GetCollections lists all collections of a store for admins, with the
+// product IDs of each
+func (s *CollectionService) GetCollections(ctx context.Context, storeID uint) ([]models.Collection, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	collections := make([]models.Collection, 0)
+	err := s.db.WithContext(ctx).Scopes(storeScope(storeID)).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Order("sort_order ASC, title ASC").
+		Find(&collections).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch collections: %v", ErrDatabaseQuery, err)
+	}
+	for i := range collections {
+		collections[i].ProductIDs = collectionProductIDs(collections[i].Items)
+	}
+	return collections, nil
+}
+
+func (s *CollectionService) GetCollection(ctx context.Context, storeID, id uint) (*models.Collection, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var collection models.Collection
+	err := s.db.WithContext(ctx).Scopes(storeScope(storeID)).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		First(&collection, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: collection %d not found", ErrCollectionNotFound, id)
+		}
+		return nil, fmt.Errorf("%w: failed to fetch collection: %v", ErrDatabaseQuery, err)
+	}
+	collection.ProductIDs = collectionProductIDs(collection.Items)
+	return &collection, nil
+}
+
+// GetActiveCollections lists a store's active collections without their
+// products, for storefront navigation
+func (s *CollectionService) GetActiveCollections(ctx context.Context, storeID uint) ([]models.Collection, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	collections := make([]models.Collection, 0)
+	err := s.db.WithContext(ctx).Scopes(storeScope(storeID)).
+		Where("is_active = ?", true).
+		Order("sort_order ASC, title ASC").
+		Find(&collections).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch collections: %v", ErrDatabaseQuery, err)
+	}
+	return collections, nil
+}
+
+// GetCollectionBySlug returns an active collection with its active products
+// in curated order
+func (s *CollectionService) GetCollectionBySlug(ctx context.Context, storeID uint, slug string) (*models.Collection, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var collection models.Collection
+	err := s.db.WithContext(ctx).Scopes(storeScope(storeID)).
+		Where("slug = ? AND is_active = ?", strings.ToLower(slug), true).
+		First(&collection).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: collection %q not found", ErrCollectionNotFound, slug)
+		}
+		return nil, fmt.Errorf("%w: failed to fetch collection: %v", ErrDatabaseQuery, err)
+	}
+
+	products := make([]models.Product, 0)
+	err = s.db.WithContext(ctx).Select("products.*").
+		Joins("JOIN collection_items ON collection_items.product_id = products.id").
+		Where("collection_items.collection_id = ? AND products.status = ?", collection.ID, models.ProductStatusActive).
+		Order("collection_items.position ASC").
+		Find(&products).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch collection products: %v", ErrDatabaseQuery, err)
+	}
+	if err := s.productService.loadProductRelations(ctx, products, nil); err != nil {
+		return nil, fmt.Errorf("failed to load product relations: %v", err)
+	}
+	collection.Products = products
+	return &collection, nil
+}
+
+func (s *CollectionService) CreateCollection(ctx context.Context, storeID uint, req *models.CreateCollectionRequest) (*models.Collection, error) {
+	collection := models.Collection{
+		Title:          strings.TrimSpace(req.Title),
+		Slug:           slugify(req.Slug),
+		Description:    req.Description,
+		BannerImageURL: req.BannerImageURL,
+		IsActive:       true,
+		SortOrder:      req.SortOrder,
+	}
+	if storeID != 0 {
+		collection.StoreID = &storeID
+	}
+	if collection.Slug == "" {
+		collection.Slug = slugify(collection.Title)
+	}
+	if req.IsActive != nil {
+		collection.IsActive = *req.IsActive
+	}
+	if collection.Title == "" || collection.Slug == "" {
+		return nil, fmt.Errorf("%w: collection title must contain letters or digits", ErrInvalidInput)
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	err := s.db.WithContext(queryCtx).Transaction(func(tx *gorm.DB) error {
+		if err := checkCollectionSlugFree(tx, storeID, &collection); err != nil {
+			return err
+		}
+		if err := createAllColumns(tx, &collection, "Items"); err != nil {
+			return fmt.Errorf("%w: failed to create collection: %v", ErrDatabaseQuery, err)
+		}
+		return replaceCollectionItems(tx, storeID, collection.ID, req.ProductIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetCollection(ctx, storeID, collection.ID)
+}
+
+func (s *CollectionService) UpdateCollection(ctx context.Context, storeID, id uint, req *models.UpdateCollectionRequest) (*models.Collection, error) {
+	collection, err := s.GetCollection(ctx, storeID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		collection.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Slug != nil {
+		collection.Slug = slugify(*req.Slug)
+	}
+	if req.Description != nil {
+		collection.Description = *req.Description
+	}
+	if req.BannerImageURL != nil {
+		collection.BannerImageURL = *req.BannerImageURL
+	}
+	if req.IsActive != nil {
+		collection.IsActive = *req.IsActive
+	}
+	if req.SortOrder != nil {
+		collection.SortOrder = *req.SortOrder
+	}
+	if collection.Title == "" || collection.Slug == "" {
+		return nil, fmt.Errorf("%w: collection title and slug cannot be empty", ErrInvalidInput)
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	err = s.db.WithContext(queryCtx).Transaction(func(tx *gorm.DB) error {
+		if err := checkCollectionSlugFree(tx, storeID, collection); err != nil {
+			return err
+		}
+		err := tx.Model(collection).
+			Select("title", "slug", "description", "banner_image_url", "is_active", "sort_order").
+			Updates(collection).Error
+		if err != nil {
+			return fmt.Errorf("%w: failed to update collection: %v", ErrDatabaseQuery, err)
+		}
+		if req.ProductIDs != nil {
+			return replaceCollectionItems(tx, storeID, collection.ID, *req.ProductIDs)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetCollection(ctx, storeID, id)
+}
+
+func (s *CollectionService) DeleteCollection(ctx context.Context, storeID, id uint) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	// Items go with the collection through the foreign key cascade
+	result := s.db.WithContext(ctx).Scopes(storeScope(storeID)).Delete(&models.Collection{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("%w: failed to delete collection: %v", ErrDatabaseQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: collection %d not found", ErrCollectionNotFound, id)
+	}
+	return nil
+}
+
+func checkCollectionSlugFree(tx *gorm.DB, storeID uint, collection *models.Collection) error {
+	var count int64
+	err := tx.Model(&models.Collection{}).Scopes(storeScope(storeID)).
+		Where("slug = ? AND id <> ?", collection.Slug, collection.ID).
+		Count(&count).Error
+	if err != nil {
+		return fmt.Errorf("%w: failed to check collection slug: %v", ErrDatabaseQuery, err)
+	}
+	if count > 0 {
+		return ErrCollectionExists
+	}
+	return nil
+}
+
+// replaceCollectionItems sets a collection's products to productIDs, in order.
+// Products must exist in the collection's store; any status is allowed so a
+// draft can be curated before it is published.
+func replaceCollectionItems(tx *gorm.DB, storeID, collectionID uint, productIDs []uint) error {
+	if len(productIDs) > MaxCollectionProducts {
+		return fmt.Errorf("%w: a collection holds at most %d products", ErrInvalidInput, MaxCollectionProducts)
+	}
+	seen := make(map[uint]bool, len(productIDs))
+	for _, id := range productIDs {
+		if seen[id] {
+			return fmt.Errorf("%w: product %d is listed more than once", ErrInvalidInput, id)
+		}
+		seen[id] = true
+	}
+
+	if len(productIDs) > 0 {
+		var found int64
+		err := tx.Model(&models.Product{}).Scopes(storeScope(storeID)).
+			Where("id IN ?", productIDs).
+			Count(&found).Error
+		if err != nil {
+			return fmt.Errorf("%w: failed to find products: %v", ErrDatabaseQuery, err)
+		}
+		if int(found) != len(productIDs) {
+			return fmt.Errorf("%w: one or more products do not exist", ErrInvalidInput)
+		}
+	}
+
+	if err := tx.Where("collection_id = ?", collectionID).Delete(&models.CollectionItem{}).Error; err != nil {
+		return fmt.Errorf("%w: failed to replace collection items: %v", ErrDatabaseQuery, err)
+	}
+	if len(productIDs) == 0 {
+		return nil
+	}
+	items := make([]models.CollectionItem, 0, len(productIDs))
+	for i, id := range productIDs {
+		items = append(items, models.CollectionItem{CollectionID: collectionID, ProductID: id, Position: i})
+	}
+	if err := tx.Create(&items).Error; err != nil {
+		return fmt.Errorf("%w: failed to save collection items: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
+
+func collectionProductIDs(items []models.CollectionItem) []uint {
+	ids := make([]uint, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ProductID)
+	}
+	return ids
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"github.com/princeprakhar/ecommerce-backend/pkg/metrics"
+	"gorm.io/gorm"
+)
+
+// Counter is a denormalized count column kept next to the rows it counts
+type Counter struct {
+	Name   string
+	Table  string
+	Column string
+	// Source counts the rows behind the column per owner ID, as
+	// "SELECT <owner> AS id, COUNT(*) AS n ... GROUP BY <owner>"; empty when
+	// the counter is its own source of truth
+	Source string
+}
+
+var (
+	CounterProductLikes = Counter{Name: "product_likes", Table: "products", Column: "like_count",
+		Source: "SELECT product_id AS id, COUNT(*) AS n FROM product_reactions WHERE is_like GROUP BY product_id"}
+	CounterProductDislikes = Counter{Name: "product_dislikes", Table: "products", Column: "dislike_count",
+		Source: "SELECT product_id AS id, COUNT(*) AS n FROM product_reactions WHERE is_dislike GROUP BY product_id"}
+	CounterReviewLikes = Counter{Name: "review_likes", Table: "reviews", Column: "like_count",
+		Source: "SELECT review_id AS id, COUNT(*) AS n FROM review_likes WHERE is_like GROUP BY review_id"}
+	CounterReviewDislikes = Counter{Name: "review_dislikes", Table: "reviews", Column: "dislike_count",
+		Source: "SELECT review_id AS id, COUNT(*) AS n FROM review_likes WHERE NOT is_like GROUP BY review_id"}
+	// Views are only counted, so there is nothing to reconcile them against
+	CounterProductViews = Counter{Name: "product_views", Table: "products", Column: "view_count"}
+)
+
+// Counters lists every counter the service maintains
+var Counters = []Counter{
+	CounterProductLikes,
+	CounterProductDislikes,
+	CounterReviewLikes,
+	CounterReviewDislikes,
+	CounterProductViews,
+}
+
+// CounterDrift is what one reconciliation corrected
+type CounterDrift struct {
+	Counter string `json:"counter"`
+	Rows    int64  `json:"rows"`  // rows whose stored count was wrong
+	Total   int64  `json:"total"` // sum of the absolute corrections
+}
+
+// CounterService keeps the count columns on products and reviews. Changes
+// are applied as single UPDATE ... SET n = n + delta statements, so
+// concurrent requests never lose an increment, and the columns are
+// periodically recomputed from their source tables to repair drift left by
+// bulk operations such as account merges. Views are buffered in memory and
+// flushed in batches, since they would otherwise write on every page load.
+type CounterService struct {
+	db *gorm.DB
+
+	mu    sync.Mutex
+	views map[uint]int
+}
+
+func NewCounterService(db *gorm.DB) *CounterService {
+	return &CounterService{db: db, views: make(map[uint]int)}
+}
+
+// Add changes the counter of row id by delta. Call it in the transaction
+// that changed the counted rows.
+func (s *CounterService) Add(tx *gorm.DB, counter Counter, id uint, delta int) error {
+	if delta == 0 {
+		return nil
+	}
+	err := tx.Table(counter.Table).
+		Where("id = ?", id).
+		UpdateColumn(counter.Column, gorm.Expr(counter.Column+" + ?", delta)).Error
+	if err != nil {
+		return fmt.Errorf("%w: failed to update %s: %v", ErrDatabaseQuery, counter.Name, err)
+	}
+	return nil
+}
+
+// RecordView counts a product view. It is written by the next flush.
+func (s *CounterService) RecordView(productID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.views[productID]++
+}
+
+// FlushViews writes the buffered views. Views that fail to write are put
+// back for the next flush.
+func (s *CounterService) FlushViews(ctx context.Context) error {
+	s.mu.Lock()
+	views := s.views
+	s.views = make(map[uint]int)
+	s.mu.Unlock()
+
+	if len(views) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for productID, n := range views {
+			if err := s.Add(tx, CounterProductViews, productID, n); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.mu.Lock()
+		for productID, n := range views {
+			s.views[productID] += n
+		}
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Reconcile recomputes every counter that has a source table and reports
+// the drift it corrected. An increment committed while a counter is being
+// recomputed can be overwritten; the next run puts it back.
+func (s *CounterService) Reconcile(ctx context.Context) ([]CounterDrift, error) {
+	drifts := make([]CounterDrift, 0, len(Counters))
+	for _, counter := range Counters {
+		if counter.Source == "" {
+			continue
+		}
+		drift, err := s.reconcile(ctx, counter)
+		if err != nil {
+			return drifts, err
+		}
+		metrics.CounterDriftRows.WithLabelValues(counter.Name).Set(float64(drift.Rows))
+		metrics.CounterDrift.WithLabelValues(counter.Name).Add(float64(drift.Total))
+		drifts = append(drifts, drift)
+	}
+	return drifts, nil
+}
+
+func (s *CounterService) reconcile(ctx context.Context, counter Counter) (CounterDrift, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	// RETURNING can see the FROM list, which still holds the stored value
+	query := fmt.Sprintf(`WITH fixed AS (
+	UPDATE %[1]s t SET %[2]s = a.n
+	FROM (
+		SELECT r.id, r.%[2]s AS stored, COALESCE(src.n, 0) AS n
+		FROM %[1]s r LEFT JOIN (%[3]s) src ON src.id = r.id
+	) a
+	WHERE t.id = a.id AND a.stored <> a.n
+	RETURNING ABS(a.n - a.stored) AS drift
+)
+SELECT COUNT(*), COALESCE(SUM(drift), 0) FROM fixed`, counter.Table, counter.Column, counter.Source)
+
+	drift := CounterDrift{Counter: counter.Name}
+	if err := s.db.WithContext(ctx).Raw(query).Row().Scan(&drift.Rows, &drift.Total); err != nil {
+		return drift, fmt.Errorf("%w: failed to reconcile %s: %v", ErrDatabaseQuery, counter.Name, err)
+	}
+	return drift, nil
+}
+
+// Run flushes views every flushInterval and reconciles the counters at
+// startup and every reconcileInterval until the context is cancelled
+func (s *CounterService) Run(ctx context.Context, flushInterval, reconcileInterval time.Duration) {
+	if flushInterval <= 0 {
+		flushInterval = 10 * time.Second
+	}
+	if reconcileInterval <= 0 {
+		reconcileInterval = time.Hour
+	}
+
+	s.reconcileAndLog(ctx)
+
+	flush := time.NewTicker(flushInterval)
+	defer flush.Stop()
+	reconcile := time.NewTicker(reconcileInterval)
+	defer reconcile.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.FlushViews(context.Background()); err != nil {
+				logger.Error("Failed to flush product views: ", err)
+			}
+			return
+		case <-flush.C:
+			if err := s.FlushViews(ctx); err != nil {
+				logger.Error("Failed to flush product views: ", err)
+			}
+		case <-reconcile.C:
+			s.reconcileAndLog(ctx)
+		}
+	}
+}
+
+func (s *CounterService) reconcileAndLog(ctx context.Context) {
+	drifts, err := s.Reconcile(ctx)
+	if err != nil {
+		logger.Error("Failed to reconcile counters: ", err)
+	}
+	for _, drift := range drifts {
+		if drift.Rows > 0 {
+			logger.WithFields(map[string]interface{}{"counter": drift.Counter, "rows": drift.Rows, "total": drift.Total}).
+				Warn("Counter drifted from its source table and was corrected")
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package services
+
+import "gorm.io/gorm"
+
+// createAllColumns inserts value with every column in the statement. GORM
+// leaves zero values out of a plain Create, so an explicit false, such as
+// is_active=false, would otherwise be replaced by the column default.
+func createAllColumns(db *gorm.DB, value interface{}, omit ...string) error {
+	return db.Select("*").Omit(append([]string{"id"}, omit...)...).Create(value).Error
+}
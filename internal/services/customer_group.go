@@ -0,0 +1,324 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCustomerGroupNotFound = errors.New("customer group not found")
+	ErrCustomerGroupExists   = errors.New("customer group already exists")
+	ErrGroupPriceNotFound    = errors.New("customer group price not found")
+	ErrGroupPriceExists      = errors.New("customer group already has a price for this product or category")
+)
+
+// CustomerGroupService manages customer groups, their price rules and
+// membership
+type CustomerGroupService struct {
+	db *gorm.DB
+}
+
+func NewCustomerGroupService(db *gorm.DB) *CustomerGroupService {
+	return &CustomerGroupService{db: db}
+}
+
+func (s *CustomerGroupService) GetGroups(ctx context.Context) ([]models.CustomerGroup, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	groups := []models.CustomerGroup{}
+	if err := s.db.WithContext(ctx).Order("name ASC").Find(&groups).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch customer groups: %v", ErrDatabaseQuery, err)
+	}
+	return groups, nil
+}
+
+// GetGroup returns a group with its price rules
+func (s *CustomerGroupService) GetGroup(ctx context.Context, id uint) (*models.CustomerGroup, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var group models.CustomerGroup
+	if err := s.db.WithContext(ctx).Preload("Prices", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).First(&group, id).Error; err != nil {
+		return nil, customerGroupError(err, id)
+	}
+	return &group, nil
+}
+
+func (s *CustomerGroupService) CreateGroup(ctx context.Context, req *models.CustomerGroupRequest) (*models.CustomerGroup, error) {
+	group := models.CustomerGroup{
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+	}
+	if group.Name == "" {
+		return nil, fmt.Errorf("%w: name cannot be empty", ErrInvalidInput)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Create(&group).Error; err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: %s", ErrCustomerGroupExists, group.Name)
+		}
+		return nil, fmt.Errorf("%w: failed to create customer group: %v", ErrDatabaseQuery, err)
+	}
+	return &group, nil
+}
+
+func (s *CustomerGroupService) UpdateGroup(ctx context.Context, id uint, req *models.UpdateCustomerGroupRequest) (*models.CustomerGroup, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var group models.CustomerGroup
+	db := s.db.WithContext(ctx)
+	if err := db.First(&group, id).Error; err != nil {
+		return nil, customerGroupError(err, id)
+	}
+	if req.Name != nil {
+		group.Name = strings.TrimSpace(*req.Name)
+		if group.Name == "" {
+			return nil, fmt.Errorf("%w: name cannot be empty", ErrInvalidInput)
+		}
+	}
+	if req.Description != nil {
+		group.Description = strings.TrimSpace(*req.Description)
+	}
+
+	if err := db.Model(&group).Updates(map[string]interface{}{
+		"name":        group.Name,
+		"description": group.Description,
+	}).Error; err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: %s", ErrCustomerGroupExists, group.Name)
+		}
+		return nil, fmt.Errorf("%w: failed to update customer group: %v", ErrDatabaseQuery, err)
+	}
+	return &group, nil
+}
+
+// DeleteGroup deletes a group and its price rules; members go back to
+// regular prices
+func (s *CustomerGroupService) DeleteGroup(ctx context.Context, id uint) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).Delete(&models.CustomerGroup{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("%w: failed to delete customer group: %v", ErrDatabaseQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: %d", ErrCustomerGroupNotFound, id)
+	}
+	return nil
+}
+
+// AddPrice adds a price rule for a product or a category to a group
+func (s *CustomerGroupService) AddPrice(ctx context.Context, groupID uint, req *models.CustomerGroupPriceRequest) (*models.CustomerGroupPrice, error) {
+	price := models.CustomerGroupPrice{
+		GroupID:         groupID,
+		ProductID:       req.ProductID,
+		Category:        strings.TrimSpace(req.Category),
+		Price:           req.Price,
+		DiscountPercent: req.DiscountPercent,
+	}
+	if (price.ProductID == nil) == (price.Category == "") {
+		return nil, fmt.Errorf("%w: set either product_id or category", ErrInvalidInput)
+	}
+	if (price.Price == nil) == (price.DiscountPercent == nil) {
+		return nil, fmt.Errorf("%w: set either price or discount_percent", ErrInvalidInput)
+	}
+	if price.Price != nil && price.ProductID == nil {
+		return nil, fmt.Errorf("%w: category prices can only be discounts", ErrInvalidInput)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	db := s.db.WithContext(ctx)
+	if err := db.Select("id").First(&models.CustomerGroup{}, groupID).Error; err != nil {
+		return nil, customerGroupError(err, groupID)
+	}
+	if price.ProductID != nil {
+		if err := db.Select("id").First(&models.Product{}, *price.ProductID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("%w: product with ID %d not found", ErrProductNotFound, *price.ProductID)
+			}
+			return nil, fmt.Errorf("%w: failed to find product: %v", ErrDatabaseQuery, err)
+		}
+	}
+
+	if err := db.Create(&price).Error; err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrGroupPriceExists
+		}
+		return nil, fmt.Errorf("%w: failed to create group price: %v", ErrDatabaseQuery, err)
+	}
+	return &price, nil
+}
+
+func (s *CustomerGroupService) DeletePrice(ctx context.Context, groupID, priceID uint) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).Where("id = ? AND group_id = ?", priceID, groupID).Delete(&models.CustomerGroupPrice{})
+	if result.Error != nil {
+		return fmt.Errorf("%w: failed to delete group price: %v", ErrDatabaseQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: price %d in group %d", ErrGroupPriceNotFound, priceID, groupID)
+	}
+	return nil
+}
+
+// SetUserGroup moves a customer into a group, or out of theirs when groupID
+// is nil
+func (s *CustomerGroupService) SetUserGroup(ctx context.Context, userID uint, groupID *uint) (*models.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	db := s.db.WithContext(ctx)
+	var user models.User
+	if err := db.First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: user %d not found", ErrUserNotFound, userID)
+		}
+		return nil, fmt.Errorf("%w: failed to fetch user: %v", ErrDatabaseQuery, err)
+	}
+	if user.Role != "customer" {
+		return nil, fmt.Errorf("%w: only customers can join customer groups", ErrInvalidInput)
+	}
+	if groupID != nil {
+		if err := db.Select("id").First(&models.CustomerGroup{}, *groupID).Error; err != nil {
+			return nil, customerGroupError(err, *groupID)
+		}
+	}
+
+	user.CustomerGroupID = groupID
+	if err := db.Model(&user).Update("customer_group_id", groupID).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to update customer group: %v", ErrDatabaseQuery, err)
+	}
+	return &user, nil
+}
+
+// ApplyCustomerPricing replaces the prices of products with those of userID's
+// customer group, where lower. The regular price becomes the compare-at
+// price unless a sale already set one. Guests and customers outside a group
+// keep regular prices.
+func (s *ProductService) ApplyCustomerPricing(ctx context.Context, userID uint, products []models.Product) error {
+	if userID == 0 || len(products) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	rules, err := loadGroupPrices(s.db.WithContext(ctx), userID, products)
+	if err != nil {
+		return err
+	}
+	for i := range products {
+		applyGroupPrice(&products[i], rules)
+	}
+	return nil
+}
+
+// groupPriceRules are the price rules of one customer group that can apply to
+// a set of products
+type groupPriceRules struct {
+	byProduct  map[uint]*models.CustomerGroupPrice
+	byCategory map[string]*models.CustomerGroupPrice // lower-cased category
+	categories map[uint]string                       // product ID -> category
+}
+
+// loadGroupPrices loads the rules of userID's group for products, or nil when
+// the user is in no group
+func loadGroupPrices(db *gorm.DB, userID uint, products []models.Product) (*groupPriceRules, error) {
+	var user models.User
+	if err := db.Select("id", "customer_group_id").Limit(1).Find(&user, userID).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch customer group: %v", ErrDatabaseQuery, err)
+	}
+	if user.CustomerGroupID == nil {
+		return nil, nil
+	}
+	groupID := user.CustomerGroupID
+
+	ids := make([]uint, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.ID)
+	}
+
+	var prices []models.CustomerGroupPrice
+	if err := db.Where("group_id = ? AND (product_id IN ? OR category <> '')", *groupID, ids).Find(&prices).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch group prices: %v", ErrDatabaseQuery, err)
+	}
+
+	rules := &groupPriceRules{
+		byProduct:  make(map[uint]*models.CustomerGroupPrice),
+		byCategory: make(map[string]*models.CustomerGroupPrice),
+		categories: make(map[uint]string),
+	}
+	for i := range prices {
+		if prices[i].ProductID != nil {
+			rules.byProduct[*prices[i].ProductID] = &prices[i]
+		} else {
+			rules.byCategory[strings.ToLower(prices[i].Category)] = &prices[i]
+		}
+	}
+
+	// Projected products may not carry their category, so look it up
+	if len(rules.byCategory) > 0 {
+		var categorized []models.Product
+		if err := db.Select("id", "category").Where("id IN ?", ids).Find(&categorized).Error; err != nil {
+			return nil, fmt.Errorf("%w: failed to fetch categories: %v", ErrDatabaseQuery, err)
+		}
+		for _, product := range categorized {
+			rules.categories[product.ID] = strings.ToLower(product.Category)
+		}
+	}
+	return rules, nil
+}
+
+func applyGroupPrice(product *models.Product, rules *groupPriceRules) {
+	if rules == nil || product.Price <= 0 {
+		return
+	}
+	rule, ok := rules.byProduct[product.ID]
+	if !ok {
+		rule, ok = rules.byCategory[rules.categories[product.ID]]
+	}
+	if !ok {
+		return
+	}
+
+	price := product.Price
+	if rule.Price != nil {
+		price = *rule.Price
+	} else if rule.DiscountPercent != nil {
+		price = math.Round(product.Price*(100-*rule.DiscountPercent)) / 100
+	}
+	if price >= product.Price {
+		return
+	}
+
+	if product.CompareAtPrice == nil {
+		regular := product.Price
+		product.CompareAtPrice = &regular
+	}
+	product.Price = price
+}
+
+func customerGroupError(err error, id uint) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: %d", ErrCustomerGroupNotFound, id)
+	}
+	return fmt.Errorf("%w: failed to find customer group: %v", ErrDatabaseQuery, err)
+}
@@ -0,0 +1,364 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+)
+
+const (
+	debugCaptureDefaultDuration = time.Hour
+	debugCaptureMaxDuration     = 24 * time.Hour
+	debugCaptureRecordTimeout   = 5 * time.Second
+	debugCaptureRefresh         = 30 * time.Second
+)
+
+var (
+	ErrDebugCaptureRuleNotFound = errors.New("debug capture rule not found")
+	ErrDebugCaptureNotFound     = errors.New("debug capture not found")
+)
+
+// debugCaptureHeaders are the only request and response headers recorded;
+// credentials and cookies are never kept
+var debugCaptureHeaders = []string{
+	"Accept", "Accept-Encoding", "Accept-Language", "Content-Type", "Content-Length",
+	"User-Agent", "Origin", "Referer", "If-None-Match", "X-Auth-Mode", "X-Request-ID",
+	"Cache-Control", "Etag", "Location", "Retry-After",
+}
+
+// debugCaptureRedactedFields are JSON keys whose values are replaced before a
+// body is stored, at any depth
+var debugCaptureRedactedFields = map[string]bool{
+	"password":         true,
+	"current_password": true,
+	"new_password":     true,
+	"confirm_password": true,
+	"token":            true,
+	"access_token":     true,
+	"ticket":           true,
+	"refresh_token":    true,
+	"reset_token":      true,
+	"secret":           true,
+	"api_key":          true,
+	"code":             true,
+	"phone":            true,
+	"phone_number":     true,
+}
+
+// DebugCaptureService records sanitized request/response pairs for routes an
+// admin selected, to debug client issues that are hard to reproduce. Rules
+// are served from memory and reloaded on every Run interval and after this
+// instance's own writes. The capture table is trimmed to DEBUG_CAPTURE_MAX_ROWS.
+type DebugCaptureService struct {
+	db      *gorm.DB
+	enabled bool
+	maxRows int
+	maxBody int
+
+	mu    sync.RWMutex
+	rules []models.DebugCaptureRule
+}
+
+func NewDebugCaptureService(db *gorm.DB, cfg *config.Config) *DebugCaptureService {
+	return &DebugCaptureService{
+		db:      db,
+		enabled: cfg.DebugCaptureEnabled,
+		maxRows: cfg.DebugCaptureMaxRows,
+		maxBody: cfg.DebugCaptureMaxBodyKB * 1024,
+	}
+}
+
+// Enabled reports whether DEBUG_CAPTURE_ENABLED is set
+func (s *DebugCaptureService) Enabled() bool {
+	return s.enabled
+}
+
+// MaxBody is the number of bytes of each body that is kept
+func (s *DebugCaptureService) MaxBody() int {
+	return s.maxBody
+}
+
+// Run loads the rules, then refreshes them and trims the capture table every
+// 30 seconds until the context is cancelled. It returns at once when capture
+// is disabled.
+func (s *DebugCaptureService) Run(ctx context.Context) {
+	if !s.enabled {
+		return
+	}
+
+	if err := s.Reload(ctx); err != nil {
+		logger.Error("Failed to load debug capture rules: ", err)
+	}
+
+	ticker := time.NewTicker(debugCaptureRefresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Reload(ctx); err != nil {
+				logger.Error("Failed to refresh debug capture rules: ", err)
+			}
+			if err := s.trim(ctx); err != nil {
+				logger.Error("Failed to trim debug captures: ", err)
+			}
+		}
+	}
+}
+
+// Reload replaces the in-memory rules with the unexpired rows of the table
+func (s *DebugCaptureService) Reload(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var rules []models.DebugCaptureRule
+	if err := s.db.WithContext(ctx).Where("expires_at > ?", time.Now()).Find(&rules).Error; err != nil {
+		return fmt.Errorf("%w: failed to load debug capture rules: %v", ErrDatabaseQuery, err)
+	}
+
+	s.mu.Lock()
+	s.rules = rules
+	s.mu.Unlock()
+	return nil
+}
+
+// RulesFor returns the active rules matching a request's method and route
+// pattern. The user filter is applied by Select once the request is handled.
+func (s *DebugCaptureService) RulesFor(method, route string) []models.DebugCaptureRule {
+	if !s.enabled || route == "" {
+		return nil
+	}
+
+	now := time.Now()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matched []models.DebugCaptureRule
+	for _, rule := range s.rules {
+		if rule.Route == route && (rule.Method == "" || rule.Method == method) && rule.ExpiresAt.After(now) {
+			matched = append(matched, rule)
+		}
+	}
+	return matched
+}
+
+// Select picks the first of rules that applies to userID, 0 for anonymous
+// requests
+func (s *DebugCaptureService) Select(rules []models.DebugCaptureRule, userID uint) *models.DebugCaptureRule {
+	for i := range rules {
+		if rules[i].UserID == nil || *rules[i].UserID == userID {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// Record stores a capture. It never fails the request being captured: write
+// errors are logged and swallowed.
+func (s *DebugCaptureService) Record(capture *models.DebugCapture) {
+	ctx, cancel := context.WithTimeout(context.Background(), debugCaptureRecordTimeout)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Create(capture).Error; err != nil {
+		logger.Error("Failed to record debug capture for ", capture.Route, ": ", err)
+	}
+}
+
+// trim deletes the oldest captures beyond the row cap
+func (s *DebugCaptureService) trim(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	return s.db.WithContext(ctx).Exec(`DELETE FROM debug_captures WHERE id <= (
+		SELECT id FROM debug_captures ORDER BY id DESC OFFSET ? LIMIT 1)`, s.maxRows).Error
+}
+
+// SanitizeHeaders keeps the allow-listed headers
+func (s *DebugCaptureService) SanitizeHeaders(header http.Header) map[string]string {
+	kept := make(map[string]string)
+	for _, name := range debugCaptureHeaders {
+		if value := header.Get(name); value != "" {
+			kept[name] = value
+		}
+	}
+	return kept
+}
+
+// SanitizeBody redacts sensitive fields of a JSON body. Other content types
+// are summarized rather than stored, since they are mostly file uploads.
+func (s *DebugCaptureService) SanitizeBody(body []byte, contentType string) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if !strings.Contains(contentType, "json") {
+		return fmt.Sprintf("[%d bytes of %s omitted]", len(body), contentType)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		// Usually a body cut off at the size limit, which cannot be parsed
+		return fmt.Sprintf("[%d bytes of unparseable JSON omitted]", len(body))
+	}
+	sanitized, err := json.Marshal(redactDebugFields(value))
+	if err != nil {
+		return ""
+	}
+	return string(sanitized)
+}
+
+func redactDebugFields(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if debugCaptureRedactedFields[strings.ToLower(key)] {
+				v[key] = "[redacted]"
+			} else {
+				v[key] = redactDebugFields(field)
+			}
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactDebugFields(v[i])
+		}
+	}
+	return value
+}
+
+func (s *DebugCaptureService) GetRules(ctx context.Context) ([]models.DebugCaptureRule, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	rules := make([]models.DebugCaptureRule, 0)
+	if err := s.db.WithContext(ctx).Order("id DESC").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch debug capture rules: %v", ErrDatabaseQuery, err)
+	}
+	return rules, nil
+}
+
+func (s *DebugCaptureService) CreateRule(ctx context.Context, adminID uint, req *models.CreateDebugCaptureRuleRequest) (*models.DebugCaptureRule, error) {
+	if !s.enabled {
+		return nil, fmt.Errorf("%w: debug capture is disabled; set DEBUG_CAPTURE_ENABLED outside production", ErrInvalidInput)
+	}
+
+	duration := debugCaptureDefaultDuration
+	if req.Duration != "" {
+		parsed, err := time.ParseDuration(req.Duration)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("%w: invalid duration %q", ErrInvalidInput, req.Duration)
+		}
+		duration = parsed
+	}
+	if duration > debugCaptureMaxDuration {
+		return nil, fmt.Errorf("%w: captures can run for at most %s", ErrInvalidInput, debugCaptureMaxDuration)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	rule := &models.DebugCaptureRule{
+		Method:    req.Method,
+		Route:     strings.TrimSpace(req.Route),
+		UserID:    req.UserID,
+		ExpiresAt: time.Now().Add(duration),
+		CreatedBy: adminID,
+	}
+	if err := s.db.WithContext(ctx).Create(rule).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to create debug capture rule: %v", ErrDatabaseQuery, err)
+	}
+
+	s.reloadAfterWrite(ctx)
+	return rule, nil
+}
+
+// DeleteRule stops a capture early. Captures it already recorded are kept.
+func (s *DebugCaptureService) DeleteRule(ctx context.Context, id uint) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).Delete(&models.DebugCaptureRule{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("%w: failed to delete debug capture rule: %v", ErrDatabaseQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrDebugCaptureRuleNotFound
+	}
+
+	s.reloadAfterWrite(ctx)
+	return nil
+}
+
+// GetCaptures lists captures newest first without their bodies, optionally
+// for one rule or route
+func (s *DebugCaptureService) GetCaptures(ctx context.Context, ruleID uint, route string, page, limit int) ([]models.DebugCapture, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	query := s.db.WithContext(ctx).Model(&models.DebugCapture{})
+	if ruleID != 0 {
+		query = query.Where("rule_id = ?", ruleID)
+	}
+	if route != "" {
+		query = query.Where("route = ?", route)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to count debug captures: %v", ErrDatabaseQuery, err)
+	}
+
+	captures := make([]models.DebugCapture, 0)
+	if err := query.Omit("request_body", "response_body").
+		Order("id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&captures).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to fetch debug captures: %v", ErrDatabaseQuery, err)
+	}
+	return captures, total, nil
+}
+
+func (s *DebugCaptureService) GetCapture(ctx context.Context, id uint) (*models.DebugCapture, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var capture models.DebugCapture
+	if err := s.db.WithContext(ctx).First(&capture, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDebugCaptureNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to fetch debug capture: %v", ErrDatabaseQuery, err)
+	}
+	return &capture, nil
+}
+
+// ClearCaptures deletes every recorded capture and returns how many there were
+func (s *DebugCaptureService) ClearCaptures(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&models.DebugCapture{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("%w: failed to clear debug captures: %v", ErrDatabaseQuery, result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// reloadAfterWrite applies a change on this instance right away; other
+// instances pick it up on their next refresh.
+func (s *DebugCaptureService) reloadAfterWrite(ctx context.Context) {
+	if err := s.Reload(ctx); err != nil {
+		logger.Warn("Failed to reload debug capture rules after update: ", err)
+	}
+}
@@ -0,0 +1,352 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var ErrShippingRateNotFound = errors.New("shipping rate not found")
+
+// Shipment describes what a DeliveryEstimator is asked to price
+type Shipment struct {
+	StoreID   uint
+	ProductID uint
+	Quantity  int
+	Origin    *models.StoreLocation // nil when shipped from online stock
+	Country   string
+	Postcode  string // upper case without spaces
+}
+
+// DeliveryEstimator returns the carrier services that can deliver a
+// shipment, with their price and transit time in business days.
+type DeliveryEstimator interface {
+	Estimate(ctx context.Context, shipment Shipment) ([]models.DeliveryOption, error)
+}
+
+// NewDeliveryEstimator picks the estimator from DELIVERY_ESTIMATOR. "rates"
+// uses the admin-managed rate tables; carrier APIs can be added here.
+func NewDeliveryEstimator(cfg *config.Config, db *gorm.DB) (DeliveryEstimator, error) {
+	switch strings.ToLower(cfg.DeliveryEstimator) {
+	case "", "rates":
+		return &rateTableEstimator{db: db}, nil
+	default:
+		return nil, fmt.Errorf("unsupported delivery estimator %q", cfg.DeliveryEstimator)
+	}
+}
+
+// rateTableEstimator prices shipments from the ShippingRate table
+type rateTableEstimator struct {
+	db *gorm.DB
+}
+
+func (e *rateTableEstimator) Estimate(ctx context.Context, shipment Shipment) ([]models.DeliveryOption, error) {
+	var rates []models.ShippingRate
+	query := e.db.WithContext(ctx).Scopes(storeScope(shipment.StoreID)).Where("is_active = ?", true)
+	if shipment.Country != "" {
+		query = query.Where("country = '' OR country = ?", shipment.Country)
+	} else {
+		query = query.Where("country = ''")
+	}
+	if err := query.Find(&rates).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch shipping rates: %v", ErrDatabaseQuery, err)
+	}
+
+	// Keep the most specific matching rate of each carrier service
+	best := make(map[string]models.ShippingRate)
+	for _, rate := range rates {
+		prefix := normalizePostcode(rate.PostcodePrefix)
+		if !strings.HasPrefix(shipment.Postcode, prefix) {
+			continue
+		}
+		key := rate.Carrier + "\x00" + rate.Service
+		if current, ok := best[key]; !ok || rateSpecificity(rate) > rateSpecificity(current) {
+			best[key] = rate
+		}
+	}
+
+	options := make([]models.DeliveryOption, 0, len(best))
+	for _, rate := range best {
+		options = append(options, models.DeliveryOption{
+			Carrier: rate.Carrier,
+			Service: rate.Service,
+			Price:   rate.Price,
+			MinDays: rate.MinDays,
+			MaxDays: rate.MaxDays,
+		})
+	}
+	return options, nil
+}
+
+// rateSpecificity ranks a country match above any postcode prefix length
+func rateSpecificity(rate models.ShippingRate) int {
+	specificity := len(normalizePostcode(rate.PostcodePrefix))
+	if rate.Country != "" {
+		specificity += 1000
+	}
+	return specificity
+}
+
+// DeliveryService estimates delivery dates and manages the shipping rate
+// tables the default estimator reads
+type DeliveryService struct {
+	db           *gorm.DB
+	estimator    DeliveryEstimator
+	stockService *StockService
+	dispatchDays int
+}
+
+func NewDeliveryService(db *gorm.DB, estimator DeliveryEstimator, stockService *StockService, dispatchDays int) *DeliveryService {
+	return &DeliveryService{
+		db:           db,
+		estimator:    estimator,
+		stockService: stockService,
+		dispatchDays: dispatchDays,
+	}
+}
+
+// EstimateDelivery works out how quantity units of a product would reach a
+// postcode. Online stock ships first; otherwise a store location holding
+// enough stock, preferring one in the destination country. Options are
+// sorted by price and only carry dates when the product is in stock.
+func (s *DeliveryService) EstimateDelivery(ctx context.Context, storeID, productID uint, quantity int, country, postcode string) (*models.DeliveryEstimate, error) {
+	postcode = normalizePostcode(postcode)
+	if postcode == "" {
+		return nil, fmt.Errorf("%w: postcode is required", ErrInvalidInput)
+	}
+	if quantity < 1 {
+		return nil, fmt.Errorf("%w: quantity must be at least 1", ErrInvalidInput)
+	}
+	country = strings.ToUpper(strings.TrimSpace(country))
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Scopes(storeScope(storeID)).
+		Where("status = ?", models.ProductStatusActive).
+		Select("id").First(&models.Product{}, productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: product with ID %d not found", ErrProductNotFound, productID)
+		}
+		return nil, fmt.Errorf("%w: failed to find product: %v", ErrDatabaseQuery, err)
+	}
+
+	estimate := models.DeliveryEstimate{
+		ProductID:   productID,
+		Quantity:    quantity,
+		Postcode:    postcode,
+		Country:     country,
+		StockStatus: models.DeliveryInStock,
+	}
+	shipment := Shipment{
+		StoreID:   storeID,
+		ProductID: productID,
+		Quantity:  quantity,
+		Country:   country,
+		Postcode:  postcode,
+	}
+
+	err := s.stockService.CheckAvailability(ctx, productID, quantity)
+	switch {
+	case errors.Is(err, ErrInsufficientStock):
+		origin, err := s.findStockedLocation(ctx, storeID, productID, quantity, country)
+		if err != nil {
+			return nil, err
+		}
+		if origin == nil {
+			estimate.StockStatus = models.DeliveryOutOfStock
+		} else {
+			shipment.Origin = origin
+			estimate.ShipsFrom = origin.Name
+		}
+	case err != nil:
+		return nil, err
+	}
+
+	options, err := s.estimator.Estimate(ctx, shipment)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(options, func(i, j int) bool {
+		if options[i].Price != options[j].Price {
+			return options[i].Price < options[j].Price
+		}
+		return options[i].MinDays < options[j].MinDays
+	})
+
+	if estimate.StockStatus == models.DeliveryInStock {
+		dispatch := addBusinessDays(time.Now(), s.dispatchDays)
+		estimate.DispatchDate = dispatch.Format("2006-01-02")
+		for i := range options {
+			options[i].EarliestDate = addBusinessDays(dispatch, options[i].MinDays).Format("2006-01-02")
+			options[i].LatestDate = addBusinessDays(dispatch, options[i].MaxDays).Format("2006-01-02")
+		}
+	}
+	estimate.Options = options
+	return &estimate, nil
+}
+
+// findStockedLocation returns an active location holding quantity units of
+// the product, or nil when none does
+func (s *DeliveryService) findStockedLocation(ctx context.Context, storeID, productID uint, quantity int, country string) (*models.StoreLocation, error) {
+	var locations []models.StoreLocation
+	if err := s.db.WithContext(ctx).
+		Scopes(storeScope(storeID)).
+		Joins("JOIN location_stocks ON location_stocks.location_id = store_locations.id").
+		Where("store_locations.is_active = ? AND location_stocks.product_id = ? AND location_stocks.quantity >= ?", true, productID, quantity).
+		Order("location_stocks.quantity DESC").
+		Find(&locations).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to find stocked locations: %v", ErrDatabaseQuery, err)
+	}
+	if len(locations) == 0 {
+		return nil, nil
+	}
+	for i := range locations {
+		if locations[i].Country == country {
+			return &locations[i], nil
+		}
+	}
+	return &locations[0], nil
+}
+
+func (s *DeliveryService) GetShippingRates(ctx context.Context, storeID uint) ([]models.ShippingRate, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	rates := []models.ShippingRate{}
+	if err := s.db.WithContext(ctx).Scopes(storeScope(storeID)).
+		Order("carrier ASC, service ASC, country ASC, postcode_prefix ASC").
+		Find(&rates).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch shipping rates: %v", ErrDatabaseQuery, err)
+	}
+	return rates, nil
+}
+
+func (s *DeliveryService) CreateShippingRate(ctx context.Context, storeID uint, req *models.ShippingRateRequest) (*models.ShippingRate, error) {
+	rate := models.ShippingRate{
+		Carrier:        strings.TrimSpace(req.Carrier),
+		Service:        strings.TrimSpace(req.Service),
+		Country:        strings.ToUpper(req.Country),
+		PostcodePrefix: normalizePostcode(req.PostcodePrefix),
+		Price:          *req.Price,
+		MinDays:        req.MinDays,
+		MaxDays:        req.MaxDays,
+		IsActive:       true,
+	}
+	if storeID != 0 {
+		rate.StoreID = &storeID
+	}
+	if req.IsActive != nil {
+		rate.IsActive = *req.IsActive
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := createAllColumns(s.db.WithContext(ctx), &rate); err != nil {
+		return nil, fmt.Errorf("%w: failed to create shipping rate: %v", ErrDatabaseQuery, err)
+	}
+	return &rate, nil
+}
+
+func (s *DeliveryService) UpdateShippingRate(ctx context.Context, storeID, id uint, req *models.UpdateShippingRateRequest) (*models.ShippingRate, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	db := s.db.WithContext(ctx)
+	var rate models.ShippingRate
+	if err := findShippingRate(db, storeID, id, &rate); err != nil {
+		return nil, err
+	}
+
+	if req.Carrier != nil {
+		rate.Carrier = strings.TrimSpace(*req.Carrier)
+	}
+	if req.Service != nil {
+		rate.Service = strings.TrimSpace(*req.Service)
+	}
+	if req.Country != nil {
+		rate.Country = strings.ToUpper(*req.Country)
+	}
+	if req.PostcodePrefix != nil {
+		rate.PostcodePrefix = normalizePostcode(*req.PostcodePrefix)
+	}
+	if req.Price != nil {
+		rate.Price = *req.Price
+	}
+	if req.MinDays != nil {
+		rate.MinDays = *req.MinDays
+	}
+	if req.MaxDays != nil {
+		rate.MaxDays = *req.MaxDays
+	}
+	if req.IsActive != nil {
+		rate.IsActive = *req.IsActive
+	}
+	if rate.MaxDays < rate.MinDays {
+		return nil, fmt.Errorf("%w: max_days cannot be less than min_days", ErrInvalidInput)
+	}
+
+	if err := db.Model(&rate).Updates(map[string]interface{}{
+		"carrier":         rate.Carrier,
+		"service":         rate.Service,
+		"country":         rate.Country,
+		"postcode_prefix": rate.PostcodePrefix,
+		"price":           rate.Price,
+		"min_days":        rate.MinDays,
+		"max_days":        rate.MaxDays,
+		"is_active":       rate.IsActive,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to update shipping rate: %v", ErrDatabaseQuery, err)
+	}
+	return &rate, nil
+}
+
+func (s *DeliveryService) DeleteShippingRate(ctx context.Context, storeID, id uint) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).Scopes(storeScope(storeID)).Delete(&models.ShippingRate{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("%w: failed to delete shipping rate: %v", ErrDatabaseQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: rate %d", ErrShippingRateNotFound, id)
+	}
+	return nil
+}
+
+func findShippingRate(db *gorm.DB, storeID, id uint, rate *models.ShippingRate) error {
+	if err := db.Scopes(storeScope(storeID)).First(rate, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: rate %d", ErrShippingRateNotFound, id)
+		}
+		return fmt.Errorf("%w: failed to find shipping rate: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
+
+// addBusinessDays moves forward days weekdays, skipping weekends
+func addBusinessDays(from time.Time, days int) time.Time {
+	date := from
+	for days > 0 {
+		date = date.AddDate(0, 0, 1)
+		if date.Weekday() != time.Saturday && date.Weekday() != time.Sunday {
+			days--
+		}
+	}
+	return date
+}
+
+// normalizePostcode upper-cases a postcode and drops spaces and dashes so
+// prefixes compare reliably
+func normalizePostcode(postcode string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.ToUpper(strings.TrimSpace(postcode)))
+}
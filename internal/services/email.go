@@ -3,6 +3,9 @@ package services
 import (
 	"crypto/tls"
 	"fmt"
+	"html"
+	"io"
+	"time"
 
 	"github.com/princeprakhar/ecommerce-backend/internal/config"
 	"gopkg.in/gomail.v2"
@@ -30,6 +33,10 @@ func (s *EmailService) SendEmail(to, subject, body string, attachmentPath ...str
 		}
 	}
 
+	return s.send(m)
+}
+
+func (s *EmailService) send(m *gomail.Message) error {
 	d := gomail.NewDialer(s.config.SMTPHost, s.config.SMTPPort, s.config.SMTPUsername, s.config.SMTPPassword)
 	d.TLSConfig = &tls.Config{InsecureSkipVerify: true}
 
@@ -49,6 +56,39 @@ func (s *EmailService) SendProductUploadNotification(adminEmail, filePath string
 	return s.SendEmail(adminEmail, subject, body, filePath)
 }
 
+func (s *EmailService) SendSavedSearchMatch(email, searchName, productTitle string, price float64, productURL string) error {
+	subject := fmt.Sprintf("New match for your saved search \"%s\"", searchName)
+	body := fmt.Sprintf(`
+		<h2>A new product matches your saved search</h2>
+		<p><strong>%s</strong> matches your saved search <em>%s</em>.</p>
+		<p><strong>Price:</strong> %.2f</p>
+		<p><a href="%s">View the product</a></p>
+		<p>You can turn these emails off by editing the saved search in your account.</p>
+		<p>Best regards,<br>Your E-commerce Team</p>
+	`, html.EscapeString(productTitle), html.EscapeString(searchName), price, productURL)
+
+	return s.SendEmail(email, subject, body)
+}
+
+// SendInvitationEmail invites someone to join as staff. The link opens the
+// accept page, which asks for a password (or the existing one) and submits
+// the token.
+func (s *EmailService) SendInvitationEmail(email, inviter, role, token, baseURL string, expiresAt time.Time) error {
+	acceptLink := fmt.Sprintf("%s/accept-invitation?token=%s", baseURL, token)
+
+	subject := "You've been invited to Sipfinity"
+	body := fmt.Sprintf(`
+		<h2>You've been invited</h2>
+		<p>%s has invited you to join Sipfinity as <strong>%s</strong>.</p>
+		<p><a href="%s">Accept the invitation</a></p>
+		<p>The invitation can be used once and expires on %s.</p>
+		<p>If you weren't expecting this, you can ignore this email.</p>
+		<p>Best regards,<br>Your E-commerce Team</p>
+	`, html.EscapeString(inviter), html.EscapeString(role), acceptLink, expiresAt.UTC().Format("January 2, 2006 15:04 MST"))
+
+	return s.SendEmail(email, subject, body)
+}
+
 func (s *EmailService) SendPasswordResetEmail(email, resetToken, baseURL string) error {
 	resetLink := fmt.Sprintf("%s/validate-token/?token=%s", baseURL, resetToken)
 
@@ -109,3 +149,25 @@ func (s *EmailService) SendPasswordResetEmail(email, resetToken, baseURL string)
 
 	return s.SendEmail(email, subject, body)
 }
+
+// SendQuoteEmail sends a priced quote to the customer with the quote PDF
+// attached
+func (s *EmailService) SendQuoteEmail(email string, quoteID uint, total float64, expiresAt time.Time, pdf []byte) error {
+	m := gomail.NewMessage()
+	m.SetHeader("From", s.config.FromEmail)
+	m.SetHeader("To", email)
+	m.SetHeader("Subject", fmt.Sprintf("Your quote #%d is ready", quoteID))
+	m.SetBody("text/html", fmt.Sprintf(`
+		<h2>Your quote is ready</h2>
+		<p>We've priced your quote request #%d.</p>
+		<p><strong>Total:</strong> %.2f</p>
+		<p>The quote is attached and can be accepted from your account until %s.</p>
+		<p>Best regards,<br>Your E-commerce Team</p>
+	`, quoteID, total, expiresAt.UTC().Format("January 2, 2006 15:04 MST")))
+	m.Attach(fmt.Sprintf("quote-%d.pdf", quoteID), gomail.SetCopyFunc(func(w io.Writer) error {
+		_, err := w.Write(pdf)
+		return err
+	}))
+
+	return s.send(m)
+}
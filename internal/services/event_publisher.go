@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/segmentio/kafka-go"
+)
+
+// EventPublisher ships outbox events to an external message broker.
+type EventPublisher interface {
+	Publish(ctx context.Context, topic, key string, payload []byte) error
+	Close() error
+}
+
+// NewEventPublisher builds the broker client selected by EVENT_BROKER. It
+// returns nil when no broker is configured, in which case events are only
+// dispatched in-process.
+func NewEventPublisher(cfg *config.Config) (EventPublisher, error) {
+	switch strings.ToLower(cfg.EventBroker) {
+	case "", "none":
+		return nil, nil
+	case "kafka":
+		if len(cfg.KafkaBrokers) == 0 {
+			return nil, fmt.Errorf("KAFKA_BROKERS must be set when EVENT_BROKER=kafka")
+		}
+		return &kafkaPublisher{
+			writer: &kafka.Writer{
+				Addr:                   kafka.TCP(cfg.KafkaBrokers...),
+				Balancer:               &kafka.Hash{},
+				RequiredAcks:           kafka.RequireAll,
+				AllowAutoTopicCreation: true,
+				BatchTimeout:           10 * time.Millisecond,
+			},
+		}, nil
+	case "nats":
+		conn, err := nats.Connect(cfg.NATSURL, nats.MaxReconnects(-1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to NATS: %v", err)
+		}
+		return &natsPublisher{conn: conn}, nil
+	default:
+		return nil, fmt.Errorf("unsupported EVENT_BROKER %q", cfg.EventBroker)
+	}
+}
+
+type kafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+func (p *kafkaPublisher) Publish(ctx context.Context, topic, key string, payload []byte) error {
+	return p.writer.WriteMessages(ctx, kafka.Message{
+		Topic: topic,
+		Key:   []byte(key),
+		Value: payload,
+	})
+}
+
+func (p *kafkaPublisher) Close() error {
+	return p.writer.Close()
+}
+
+type natsPublisher struct {
+	conn *nats.Conn
+}
+
+func (p *natsPublisher) Publish(ctx context.Context, topic, key string, payload []byte) error {
+	msg := nats.NewMsg(topic)
+	msg.Header.Set("Event-Key", key)
+	msg.Data = payload
+	if err := p.conn.PublishMsg(msg); err != nil {
+		return err
+	}
+	// Flush so a successful return means the server has the message
+	return p.conn.FlushWithContext(ctx)
+}
+
+func (p *natsPublisher) Close() error {
+	p.conn.Close()
+	return nil
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+)
+
+// Domain event types published on the EventBus
+const (
+	EventUserSignedUp   = "user.signed_up"
+	EventProductCreated = "product.created"
+	EventProductUpdated = "product.updated"
+	EventProductDeleted = "product.deleted"
+	EventReviewCreated  = "review.created"
+	EventReviewFlagged  = "review.flagged"
+	EventReviewUpdated  = "review.updated"
+	EventReviewDeleted  = "review.deleted"
+	EventStockLow       = "stock.low"
+	EventStockChanged   = "stock.changed"
+	EventQuoteAccepted  = "quote.accepted"
+	EventCatalogChanged = "catalog.changed" // products moved between categories in bulk
+)
+
+// KnownEventTypes lists the events integrations may subscribe to
+var KnownEventTypes = []string{
+	EventUserSignedUp,
+	EventProductCreated,
+	EventProductUpdated,
+	EventProductDeleted,
+	EventReviewCreated,
+	EventReviewFlagged,
+	EventReviewUpdated,
+	EventReviewDeleted,
+	EventStockLow,
+	EventStockChanged,
+	EventQuoteAccepted,
+	EventCatalogChanged,
+}
+
+type Event struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+type EventHandler func(Event)
+
+// EventBus is a small in-process pub/sub used to fan domain events out to
+// integrations without coupling the publishing services to them.
+type EventBus struct {
+	mu       sync.RWMutex
+	handlers []EventHandler
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+func (b *EventBus) Subscribe(handler EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish delivers the event to every subscriber asynchronously so slow
+// integrations never block the request that triggered the event.
+func (b *EventBus) Publish(eventType string, data interface{}) {
+	if b == nil {
+		return
+	}
+
+	b.PublishEvent(Event{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	})
+}
+
+// PublishEvent dispatches an already-built event, keeping its ID so consumers
+// can deduplicate events relayed more than once.
+func (b *EventBus) PublishEvent(event Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	handlers := make([]EventHandler, len(b.handlers))
+	copy(handlers, b.handlers)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		go func(h EventHandler) {
+			defer func() {
+				if r := recover(); r != nil {
+					logger.Error("Event handler panicked for ", event.Type, ": ", r)
+				}
+			}()
+			h(event)
+		}(handler)
+	}
+}
+
+func isKnownEventType(eventType string) bool {
+	for _, known := range KnownEventTypes {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
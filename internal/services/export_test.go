@@ -0,0 +1,22 @@
+package services
+
+import (
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// ProductListSQL returns the page query GetProducts sends for filter, with
+// its arguments inlined, so tests can check its plan
+func ProductListSQL(s *ProductService, filter ProductFilter) (string, error) {
+	if err := filter.ValidateAndNormalize(); err != nil {
+		return "", err
+	}
+	return s.db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		var products []models.Product
+		return s.listQuery(tx, filter).
+			Offset((filter.Page - 1) * filter.Limit).
+			Limit(filter.Limit).
+			Order(productSortOrders[filter.Sort]).
+			Find(&products)
+	}), nil
+}
@@ -2,18 +2,52 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"mime/multipart"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 	"github.com/princeprakhar/ecommerce-backend/internal/config"
 )
 
+var (
+	// ErrFastAPIUnavailable means the service could not be reached, timed out,
+	// returned a 5xx, or the circuit breaker is open. Retrying later may help.
+	ErrFastAPIUnavailable = errors.New("extraction service unavailable")
+	// ErrFastAPIBadInput means the service rejected the request itself.
+	ErrFastAPIBadInput = errors.New("extraction service rejected the input")
+)
+
+// FastAPIError carries the HTTP status and message returned by the service.
+// It unwraps to ErrFastAPIUnavailable or ErrFastAPIBadInput.
+type FastAPIError struct {
+	StatusCode int
+	Message    string
+	kind       error
+}
+
+func (e *FastAPIError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("%v: %s", e.kind, e.Message)
+	}
+	return fmt.Sprintf("%v: HTTP %d: %s", e.kind, e.StatusCode, e.Message)
+}
+
+func (e *FastAPIError) Unwrap() error {
+	return e.kind
+}
+
 type FastAPIService struct {
-	config *config.Config
+	config  *config.Config
+	client  *http.Client
+	breaker *circuitBreaker
 }
 
 type FastAPIResponse struct {
@@ -34,63 +68,227 @@ type ProductData struct {
 }
 
 func NewFastAPIService(config *config.Config) *FastAPIService {
-	return &FastAPIService{config: config}
+	return &FastAPIService{
+		config:  config,
+		client:  &http.Client{Timeout: config.FastAPITimeout},
+		breaker: newCircuitBreaker(config.FastAPIBreakerThreshold, config.FastAPIBreakerCooldown),
+	}
 }
 
-func (s *FastAPIService) ProcessImages(images []string) (*FastAPIResponse, error) {
-	// Create multipart form
+// ProcessImages sends images to the extraction endpoint. Extraction has no
+// side effects on our data, so the call is retried like an idempotent one.
+func (s *FastAPIService) ProcessImages(ctx context.Context, images []string) (*FastAPIResponse, error) {
+	// Build the multipart body once so every attempt can replay it
 	var buf bytes.Buffer
 	writer := multipart.NewWriter(&buf)
 
-	// Add images to form
 	for _, imagePath := range images {
-		file, err := os.Open(imagePath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to open image %s: %v", imagePath, err)
+		if err := addFormFile(writer, "images", imagePath); err != nil {
+			return nil, err
 		}
-		defer file.Close()
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finalize form: %v", err)
+	}
+
+	var fastAPIResp FastAPIResponse
+	err := s.do(ctx, http.MethodPost, "/upload/images", writer.FormDataContentType(), buf.Bytes(), true, &fastAPIResp)
+	if err != nil {
+		return nil, err
+	}
+	return &fastAPIResp, nil
+}
 
-		part, err := writer.CreateFormFile(fmt.Sprintf("images"), filepath.Base(imagePath))
-		if err != nil {
-			return nil, fmt.Errorf("failed to create form file: %v", err)
+// ImageDescriptionInput identifies an image to describe by its public URL
+type ImageDescriptionInput struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+// ImageDescription is the alt text generated for one image
+type ImageDescription struct {
+	ID      string `json:"id"`
+	AltText string `json:"alt_text"`
+}
+
+// DescribeImages asks the AI service for alt text for each image. Like
+// ProcessImages it has no side effects, so it is retried.
+func (s *FastAPIService) DescribeImages(ctx context.Context, images []ImageDescriptionInput) ([]ImageDescription, error) {
+	body, err := json.Marshal(map[string]interface{}{"images": images})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %v", err)
+	}
+
+	var resp struct {
+		Descriptions []ImageDescription `json:"descriptions"`
+	}
+	if err := s.do(ctx, http.MethodPost, "/describe/images", "application/json", body, true, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Descriptions, nil
+}
+
+func addFormFile(writer *multipart.Writer, field, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open image %s: %v", path, err)
+	}
+	defer file.Close()
+
+	part, err := writer.CreateFormFile(field, filepath.Base(path))
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %v", err)
+	}
+
+	if _, err := io.Copy(part, file); err != nil {
+		return fmt.Errorf("failed to copy file content: %v", err)
+	}
+	return nil
+}
+
+// do sends a request through the circuit breaker. Idempotent requests are
+// retried with exponential backoff when the service is unavailable.
+func (s *FastAPIService) do(ctx context.Context, method, path, contentType string, body []byte, idempotent bool, out interface{}) error {
+	attempts := 1
+	if idempotent && s.config.FastAPIMaxRetries > 0 {
+		attempts += s.config.FastAPIMaxRetries
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if waitErr := sleepWithContext(ctx, fastAPIBackoff(attempt)); waitErr != nil {
+				return &FastAPIError{Message: waitErr.Error(), kind: ErrFastAPIUnavailable}
+			}
 		}
 
-		_, err = io.Copy(part, file)
-		if err != nil {
-			return nil, fmt.Errorf("failed to copy file content: %v", err)
+		if !s.breaker.allow() {
+			return &FastAPIError{Message: "circuit breaker is open", kind: ErrFastAPIUnavailable}
 		}
-	}
 
-	writer.Close()
+		err = s.send(ctx, method, path, contentType, body, out)
+		if err == nil || errors.Is(err, ErrFastAPIBadInput) {
+			// A rejected request still proves the service is up
+			s.breaker.success()
+			return err
+		}
+		s.breaker.failure()
+	}
+	return err
+}
 
-	// Create request
-	url := fmt.Sprintf("%s/upload/images", s.config.FastAPIURL)
-	req, err := http.NewRequest("POST", url, &buf)
+func (s *FastAPIService) send(ctx context.Context, method, path, contentType string, body []byte, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, s.config.FastAPIURL+path, bytes.NewReader(body))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+		return fmt.Errorf("failed to create request: %v", err)
 	}
 
-	// Set headers
-	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Content-Type", contentType)
 	req.Header.Set("X-Internal-API-Key", s.config.FastAPIKey)
 
-	// Send request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := s.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
+		return &FastAPIError{Message: err.Error(), kind: ErrFastAPIUnavailable}
 	}
 	defer resp.Body.Close()
 
-	// Parse response
-	var fastAPIResp FastAPIResponse
-	if err := json.NewDecoder(resp.Body).Decode(&fastAPIResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %v", err)
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &FastAPIError{StatusCode: resp.StatusCode, Message: "failed to read response: " + err.Error(), kind: ErrFastAPIUnavailable}
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("FastAPI error: %s", fastAPIResp.Message)
+		var errResp struct {
+			Message string `json:"message"`
+			Detail  interface{} `json:"detail"`
+		}
+		message := http.StatusText(resp.StatusCode)
+		if json.Unmarshal(data, &errResp) == nil {
+			if errResp.Message != "" {
+				message = errResp.Message
+			} else if errResp.Detail != nil {
+				message = fmt.Sprint(errResp.Detail)
+			}
+		}
+
+		kind := ErrFastAPIBadInput
+		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusRequestTimeout {
+			kind = ErrFastAPIUnavailable
+		}
+		return &FastAPIError{StatusCode: resp.StatusCode, Message: message, kind: kind}
 	}
 
-	return &fastAPIResp, nil
+	if err := json.Unmarshal(data, out); err != nil {
+		return &FastAPIError{StatusCode: resp.StatusCode, Message: "failed to decode response: " + err.Error(), kind: ErrFastAPIUnavailable}
+	}
+	return nil
+}
+
+// fastAPIBackoff returns 500ms, 1s, 2s, ... with up to 50% jitter
+func fastAPIBackoff(attempt int) time.Duration {
+	delay := 500 * time.Millisecond << (attempt - 1)
+	if delay > 10*time.Second {
+		delay = 10 * time.Second
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// circuitBreaker opens after threshold consecutive failures and rejects calls
+// until cooldown has passed, then lets a single trial call through.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	trial     bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.threshold <= 0 || b.failures < b.threshold {
+		return true
+	}
+	if time.Now().Before(b.openUntil) || b.trial {
+		return false
+	}
+	b.trial = true
+	return true
+}
+
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.trial = false
+}
+
+func (b *circuitBreaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.trial = false
+	if b.threshold > 0 && b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.cooldown)
+	}
 }
\ No newline at end of file
@@ -0,0 +1,304 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrFeatureFlagNotFound = errors.New("feature flag not found")
+	ErrFeatureFlagExists   = errors.New("feature flag already exists")
+)
+
+var flagKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,62}$`)
+
+// defaultFlags applies to known flags until an admin creates a row for them,
+// so shipped features stay on and unfinished ones stay off on a fresh database.
+var defaultFlags = map[string]bool{
+	models.FlagReviewsEnabled:  true,
+	models.FlagCSVImportV2:     true,
+	models.FlagNewSearch:       false,
+	models.FlagMaintenanceMode: false,
+}
+
+// FlagSubject identifies who a flag is evaluated for. Rollouts bucket by
+// user when signed in and by ClientID (e.g. IP) otherwise.
+type FlagSubject struct {
+	UserID   uint
+	ClientID string
+}
+
+// FeatureFlagService serves flag checks from memory. Every instance reloads
+// the table on an interval, and immediately after its own admin writes, so
+// toggles take effect without a redeploy.
+type FeatureFlagService struct {
+	db    *gorm.DB
+	mu    sync.RWMutex
+	flags map[string]models.FeatureFlag
+}
+
+func NewFeatureFlagService(db *gorm.DB) *FeatureFlagService {
+	return &FeatureFlagService{
+		db:    db,
+		flags: make(map[string]models.FeatureFlag),
+	}
+}
+
+// Run loads the flags and refreshes them on every interval until the context is cancelled
+func (s *FeatureFlagService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	if err := s.Reload(ctx); err != nil {
+		logger.Error("Failed to load feature flags: ", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Reload(ctx); err != nil {
+				logger.Error("Failed to refresh feature flags: ", err)
+			}
+		}
+	}
+}
+
+// Reload replaces the in-memory flags with the current table contents
+func (s *FeatureFlagService) Reload(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var rows []models.FeatureFlag
+	if err := s.db.WithContext(ctx).Find(&rows).Error; err != nil {
+		return fmt.Errorf("%w: failed to load feature flags: %v", ErrDatabaseQuery, err)
+	}
+
+	flags := make(map[string]models.FeatureFlag, len(rows))
+	for _, flag := range rows {
+		flags[flag.Key] = flag
+	}
+
+	s.mu.Lock()
+	s.flags = flags
+	s.mu.Unlock()
+	return nil
+}
+
+// IsEnabled evaluates a flag for the subject. Unknown flags are off.
+func (s *FeatureFlagService) IsEnabled(key string, subject FlagSubject) bool {
+	s.mu.RLock()
+	flag, ok := s.flags[key]
+	s.mu.RUnlock()
+	if !ok {
+		return defaultFlags[key]
+	}
+	return evaluateFlag(&flag, subject)
+}
+
+// Evaluate returns every known flag's state for the subject, for clients
+// that branch on flags themselves.
+func (s *FeatureFlagService) Evaluate(subject FlagSubject) map[string]bool {
+	result := make(map[string]bool, len(defaultFlags))
+	for key, enabled := range defaultFlags {
+		result[key] = enabled
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for key, flag := range s.flags {
+		result[key] = evaluateFlag(&flag, subject)
+	}
+	return result
+}
+
+func evaluateFlag(flag *models.FeatureFlag, subject FlagSubject) bool {
+	if !flag.Enabled {
+		return false
+	}
+	if subject.UserID != 0 {
+		for _, id := range flag.UserIDs {
+			if id == subject.UserID {
+				return true
+			}
+		}
+	}
+	if flag.RolloutPercentage >= 100 {
+		return true
+	}
+	if flag.RolloutPercentage <= 0 {
+		return false
+	}
+	return rolloutBucket(flag.Key, subject) < flag.RolloutPercentage
+}
+
+// rolloutBucket maps a subject to 0-99, stable per flag so raising the
+// percentage only ever adds subjects.
+func rolloutBucket(key string, subject FlagSubject) int {
+	id := subject.ClientID
+	if subject.UserID != 0 {
+		id = "user:" + strconv.FormatUint(uint64(subject.UserID), 10)
+	}
+	if id == "" {
+		return 100
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(key + ":" + id))
+	return int(h.Sum32() % 100)
+}
+
+func (s *FeatureFlagService) GetFlags(ctx context.Context) ([]models.FeatureFlag, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	flags := make([]models.FeatureFlag, 0)
+	if err := s.db.WithContext(ctx).Order("key ASC").Find(&flags).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch feature flags: %v", ErrDatabaseQuery, err)
+	}
+	return flags, nil
+}
+
+func (s *FeatureFlagService) GetFlag(ctx context.Context, key string) (*models.FeatureFlag, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var flag models.FeatureFlag
+	if err := s.db.WithContext(ctx).Where("key = ?", key).First(&flag).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFeatureFlagNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to fetch feature flag: %v", ErrDatabaseQuery, err)
+	}
+	return &flag, nil
+}
+
+func (s *FeatureFlagService) CreateFlag(ctx context.Context, adminID uint, req *models.CreateFeatureFlagRequest) (*models.FeatureFlag, error) {
+	key := strings.ToLower(strings.TrimSpace(req.Key))
+	if !flagKeyPattern.MatchString(key) {
+		return nil, fmt.Errorf("%w: key must be snake_case letters, digits and underscores", ErrInvalidInput)
+	}
+
+	flag := models.FeatureFlag{
+		Key:               key,
+		Description:       strings.TrimSpace(req.Description),
+		Enabled:           req.Enabled,
+		RolloutPercentage: 100,
+		UserIDs:           uniqueUserIDs(req.UserIDs),
+		UpdatedBy:         adminID,
+	}
+	if req.RolloutPercentage != nil {
+		flag.RolloutPercentage = *req.RolloutPercentage
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var count int64
+	if err := s.db.WithContext(queryCtx).Model(&models.FeatureFlag{}).Where("key = ?", key).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to check feature flag: %v", ErrDatabaseQuery, err)
+	}
+	if count > 0 {
+		return nil, ErrFeatureFlagExists
+	}
+
+	if err := createAllColumns(s.db.WithContext(queryCtx), &flag); err != nil {
+		return nil, fmt.Errorf("%w: failed to create feature flag: %v", ErrDatabaseQuery, err)
+	}
+
+	s.reloadAfterWrite(ctx)
+	return &flag, nil
+}
+
+func (s *FeatureFlagService) UpdateFlag(ctx context.Context, key string, adminID uint, req *models.UpdateFeatureFlagRequest) (*models.FeatureFlag, error) {
+	flag, err := s.GetFlag(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	updateData := map[string]interface{}{"updated_by": adminID}
+	if req.Description != nil {
+		updateData["description"] = strings.TrimSpace(*req.Description)
+	}
+	if req.Enabled != nil {
+		updateData["enabled"] = *req.Enabled
+	}
+	if req.RolloutPercentage != nil {
+		updateData["rollout_percentage"] = *req.RolloutPercentage
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	err = s.db.WithContext(queryCtx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(flag).Updates(updateData).Error; err != nil {
+			return err
+		}
+		// Updates with a map skips the JSON serializer, so save the list through the struct
+		if req.UserIDs != nil {
+			flag.UserIDs = uniqueUserIDs(*req.UserIDs)
+			return tx.Model(flag).Select("user_ids").Updates(flag).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to update feature flag: %v", ErrDatabaseQuery, err)
+	}
+
+	s.reloadAfterWrite(ctx)
+	return s.GetFlag(ctx, key)
+}
+
+func (s *FeatureFlagService) DeleteFlag(ctx context.Context, key string) error {
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	result := s.db.WithContext(queryCtx).Where("key = ?", key).Delete(&models.FeatureFlag{})
+	if result.Error != nil {
+		return fmt.Errorf("%w: failed to delete feature flag: %v", ErrDatabaseQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrFeatureFlagNotFound
+	}
+
+	s.reloadAfterWrite(ctx)
+	return nil
+}
+
+// reloadAfterWrite applies a change on this instance right away; other
+// instances pick it up on their next refresh.
+func (s *FeatureFlagService) reloadAfterWrite(ctx context.Context) {
+	if err := s.Reload(ctx); err != nil {
+		logger.Warn("Failed to reload feature flags after update: ", err)
+	}
+}
+
+func uniqueUserIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if id != 0 && !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	sort.Slice(unique, func(i, j int) bool { return unique[i] < unique[j] })
+	return unique
+}
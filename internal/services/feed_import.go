@@ -0,0 +1,646 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrImportSourceNotFound = errors.New("import source not found")
+	ErrImportSourceBusy     = errors.New("import source is already syncing")
+)
+
+const (
+	feedFetchTimeout  = 60 * time.Second
+	maxFeedBytes      = 20 * 1024 * 1024
+	maxFeedDiffItems  = 200
+	feedSchedulerTick = time.Minute
+	// feedRunStaleAfter is when a run still marked running is assumed lost,
+	// e.g. because the instance running it crashed
+	feedRunStaleAfter = time.Hour
+)
+
+type FeedImportService struct {
+	db            *gorm.DB
+	importService *ProductImportService
+	client        *http.Client
+}
+
+func NewFeedImportService(db *gorm.DB, importService *ProductImportService) *FeedImportService {
+	return &FeedImportService{
+		db:            db,
+		importService: importService,
+		client:        newPublicHTTPClient(feedFetchTimeout),
+	}
+}
+
+func (s *FeedImportService) GetSources(ctx context.Context) ([]models.ImportSource, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	sources := make([]models.ImportSource, 0)
+	if err := s.db.WithContext(ctx).Order("created_at DESC").Find(&sources).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch import sources: %v", ErrDatabaseQuery, err)
+	}
+	return sources, nil
+}
+
+func (s *FeedImportService) GetSourceByID(ctx context.Context, id uint) (*models.ImportSource, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var source models.ImportSource
+	if err := s.db.WithContext(ctx).First(&source, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrImportSourceNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to fetch import source: %v", ErrDatabaseQuery, err)
+	}
+	return &source, nil
+}
+
+func (s *FeedImportService) CreateSource(ctx context.Context, userID uint, req *models.CreateImportSourceRequest) (*models.ImportSource, error) {
+	source := &models.ImportSource{
+		Name:              strings.TrimSpace(req.Name),
+		URL:               strings.TrimSpace(req.URL),
+		Format:            req.Format,
+		Schedule:          strings.TrimSpace(req.Schedule),
+		Mapping:           req.Mapping,
+		Headers:           req.Headers,
+		DeactivateMissing: req.DeactivateMissing,
+		IsActive:          true,
+		CreatedBy:         userID,
+	}
+	if source.Format == "" {
+		source.Format = models.FeedFormatCSV
+	}
+	if req.IsActive != nil {
+		source.IsActive = *req.IsActive
+	}
+
+	if err := s.prepareSource(source); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Create(source).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to create import source: %v", ErrDatabaseQuery, err)
+	}
+	return source, nil
+}
+
+func (s *FeedImportService) UpdateSource(ctx context.Context, id uint, req *models.UpdateImportSourceRequest) (*models.ImportSource, error) {
+	source, err := s.GetSourceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		source.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.URL != nil {
+		source.URL = strings.TrimSpace(*req.URL)
+	}
+	if req.Format != nil {
+		source.Format = *req.Format
+	}
+	if req.Schedule != nil {
+		source.Schedule = strings.TrimSpace(*req.Schedule)
+	}
+	if req.Mapping != nil {
+		source.Mapping = req.Mapping
+	}
+	if req.Headers != nil {
+		source.Headers = req.Headers
+	}
+	if req.DeactivateMissing != nil {
+		source.DeactivateMissing = *req.DeactivateMissing
+	}
+	if req.IsActive != nil {
+		source.IsActive = *req.IsActive
+	}
+
+	if err := s.prepareSource(source); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Save(source).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to update import source: %v", ErrDatabaseQuery, err)
+	}
+	return source, nil
+}
+
+func (s *FeedImportService) DeleteSource(ctx context.Context, id uint) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.ImportSource{}, id)
+		if result.Error != nil {
+			return fmt.Errorf("%w: failed to delete import source: %v", ErrDatabaseQuery, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrImportSourceNotFound
+		}
+		if err := tx.Where("source_id = ?", id).Delete(&models.ImportSourceRun{}).Error; err != nil {
+			return fmt.Errorf("%w: failed to delete import runs: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+}
+
+func (s *FeedImportService) GetRuns(ctx context.Context, sourceID uint, page, limit int) ([]models.ImportSourceRun, int64, error) {
+	if _, err := s.GetSourceByID(ctx, sourceID); err != nil {
+		return nil, 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	query := s.db.WithContext(ctx).Model(&models.ImportSourceRun{}).Where("source_id = ?", sourceID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to count import runs: %v", ErrDatabaseQuery, err)
+	}
+
+	runs := make([]models.ImportSourceRun, 0)
+	if err := query.Order("started_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&runs).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to fetch import runs: %v", ErrDatabaseQuery, err)
+	}
+	return runs, total, nil
+}
+
+// TriggerRun starts an immediate sync outside the schedule
+func (s *FeedImportService) TriggerRun(ctx context.Context, id uint) (*models.ImportSourceRun, error) {
+	source, err := s.GetSourceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := s.startRun(source, "manual")
+	if err != nil {
+		return nil, err
+	}
+	go s.sync(context.Background(), source, run)
+	return run, nil
+}
+
+// Run is the scheduler loop: every tick it syncs the active sources that are due.
+func (s *FeedImportService) Run(ctx context.Context) {
+	ticker := time.NewTicker(feedSchedulerTick)
+	defer ticker.Stop()
+
+	s.failStaleRuns(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.failStaleRuns(ctx)
+			s.runDueSources(ctx)
+		}
+	}
+}
+
+// failStaleRuns fails runs left running by a crashed instance, which would
+// otherwise keep their source busy forever
+func (s *FeedImportService) failStaleRuns(ctx context.Context) {
+	now := time.Now()
+	result := s.db.WithContext(ctx).Model(&models.ImportSourceRun{}).
+		Where("status = ? AND started_at < ?", models.ImportRunStatusRunning, now.Add(-feedRunStaleAfter)).
+		Updates(map[string]interface{}{
+			"status":      models.ImportRunStatusFailed,
+			"error":       "run did not finish and was abandoned",
+			"finished_at": now,
+		})
+	if result.Error != nil {
+		logger.Error("Failed to fail stale import runs: ", result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		logger.Warn("Failed ", result.RowsAffected, " stale import runs")
+	}
+}
+
+func (s *FeedImportService) runDueSources(ctx context.Context) {
+	var sources []models.ImportSource
+	if err := s.db.WithContext(ctx).
+		Where("is_active = ? AND next_run_at <= ?", true, time.Now()).
+		Find(&sources).Error; err != nil {
+		logger.Error("Failed to load due import sources: ", err)
+		return
+	}
+
+	for i := range sources {
+		source := &sources[i]
+		next, err := nextScheduledRun(source.Schedule, time.Now())
+		if err != nil {
+			logger.Error("Invalid schedule on import source ", source.ID, ": ", err)
+			continue
+		}
+
+		// Claim the slot so only one instance runs this occurrence
+		result := s.db.WithContext(ctx).Model(&models.ImportSource{}).
+			Where("id = ? AND next_run_at = ?", source.ID, source.NextRunAt).
+			Update("next_run_at", next)
+		if result.Error != nil || result.RowsAffected == 0 {
+			continue
+		}
+
+		run, err := s.startRun(source, "schedule")
+		if err != nil {
+			logger.Error("Failed to start import run for source ", source.ID, ": ", err)
+			continue
+		}
+		s.sync(ctx, source, run)
+	}
+}
+
+// startRun records a new run unless the source already has one running. The
+// source row is locked while checking, so two triggers cannot both start.
+func (s *FeedImportService) startRun(source *models.ImportSource, trigger string) (*models.ImportSourceRun, error) {
+	run := &models.ImportSourceRun{
+		SourceID:  source.ID,
+		Status:    models.ImportRunStatusRunning,
+		Trigger:   trigger,
+		StartedAt: time.Now(),
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var locked models.ImportSource
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&locked, source.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrImportSourceNotFound
+			}
+			return fmt.Errorf("%w: failed to lock import source: %v", ErrDatabaseQuery, err)
+		}
+
+		var running int64
+		if err := tx.Model(&models.ImportSourceRun{}).
+			Where("source_id = ? AND status = ?", source.ID, models.ImportRunStatusRunning).
+			Count(&running).Error; err != nil {
+			return fmt.Errorf("%w: failed to check running imports: %v", ErrDatabaseQuery, err)
+		}
+		if running > 0 {
+			return ErrImportSourceBusy
+		}
+
+		if err := tx.Create(run).Error; err != nil {
+			return fmt.Errorf("%w: failed to create import run: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// sync fetches the feed and creates, updates or deactivates products by SKU
+func (s *FeedImportService) sync(ctx context.Context, source *models.ImportSource, run *models.ImportSourceRun) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.finishRun(run, fmt.Errorf("sync panicked: %v", r))
+		}
+	}()
+
+	rows, err := s.fetchFeed(ctx, source)
+	if err != nil {
+		s.finishRun(run, err)
+		return
+	}
+	if len(rows) == 0 {
+		// An empty feed is far more likely a supplier outage than an empty
+		// catalog; never deactivate everything because of it.
+		s.finishRun(run, fmt.Errorf("feed returned no rows"))
+		return
+	}
+
+	run.TotalRows = len(rows)
+	reference := fmt.Sprintf("feed %d run #%d", source.ID, run.ID)
+	run.Diff.Updated = make(map[string][]string)
+	seen := make(map[string]bool, len(rows))
+
+	for i, row := range rows {
+		values := make(map[string]string, len(source.Mapping))
+		for field, column := range source.Mapping {
+			values[field] = strings.TrimSpace(row[column])
+		}
+
+		sku := values["sku"]
+		if sku == "" {
+			s.recordRowError(run, i+1, "sku is empty")
+			continue
+		}
+		if seen[sku] {
+			s.recordRowError(run, i+1, fmt.Sprintf("duplicate SKU %q in feed", sku))
+			continue
+		}
+		seen[sku] = true
+
+		var existing models.Product
+		err := s.db.WithContext(ctx).Where("sku = ?", sku).First(&existing).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if err := s.importService.createImportedProduct(ctx, values, reference, 0); err != nil {
+				s.recordRowError(run, i+1, fmt.Sprintf("%s: %v", sku, err))
+				continue
+			}
+			run.Created++
+			run.Diff.Created = appendDiff(run, run.Diff.Created, sku)
+		case err != nil:
+			s.recordRowError(run, i+1, fmt.Sprintf("%s: failed to look up product: %v", sku, err))
+		default:
+			changed := changedProductFields(&existing, values)
+			if len(changed) == 0 {
+				run.Unchanged++
+				continue
+			}
+			if err := s.importService.updateImportedProduct(ctx, &existing, values, reference, 0); err != nil {
+				s.recordRowError(run, i+1, fmt.Sprintf("%s: %v", sku, err))
+				continue
+			}
+			run.Updated++
+			if len(run.Diff.Updated) < maxFeedDiffItems {
+				run.Diff.Updated[sku] = changed
+			} else {
+				run.Diff.Truncated = true
+			}
+		}
+	}
+
+	if source.DeactivateMissing {
+		if err := s.deactivateMissing(ctx, source, seen, run); err != nil {
+			s.finishRun(run, err)
+			return
+		}
+	}
+
+	syncedSKUs := make([]string, 0, len(seen))
+	for sku := range seen {
+		syncedSKUs = append(syncedSKUs, sku)
+	}
+	now := time.Now()
+	source.SyncedSKUs = syncedSKUs
+	source.LastRunAt = &now
+	if err := s.db.Model(source).Select("synced_skus", "last_run_at").Updates(source).Error; err != nil {
+		logger.Error("Failed to update import source ", source.ID, ": ", err)
+	}
+
+	s.finishRun(run, nil)
+}
+
+// deactivateMissing marks products that were in the previous feed but not in
+// this one as inactive.
+func (s *FeedImportService) deactivateMissing(ctx context.Context, source *models.ImportSource, seen map[string]bool, run *models.ImportSourceRun) error {
+	var missing []string
+	for _, sku := range source.SyncedSKUs {
+		if !seen[sku] {
+			missing = append(missing, sku)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var products []models.Product
+		if err := tx.Where("sku IN ? AND status = ?", missing, models.ProductStatusActive).Find(&products).Error; err != nil {
+			return fmt.Errorf("%w: failed to load missing products: %v", ErrDatabaseQuery, err)
+		}
+
+		for i := range products {
+			product := &products[i]
+			if err := tx.Model(product).Update("status", models.ProductStatusInactive).Error; err != nil {
+				return fmt.Errorf("%w: failed to deactivate product: %v", ErrDatabaseQuery, err)
+			}
+			if err := recordOutboxEvent(tx, EventProductUpdated, "product", product.ID, product); err != nil {
+				return err
+			}
+			run.Deactivated++
+			run.Diff.Deactivated = appendDiff(run, run.Diff.Deactivated, product.SKU)
+		}
+		return nil
+	})
+}
+
+func (s *FeedImportService) fetchFeed(ctx context.Context, source *models.ImportSource) ([]map[string]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, feedFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid feed URL: %v", err)
+	}
+	for key, value := range source.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch feed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("feed returned HTTP %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feed: %v", err)
+	}
+	if len(body) > maxFeedBytes {
+		return nil, fmt.Errorf("feed is larger than %d bytes", maxFeedBytes)
+	}
+
+	if source.Format == models.FeedFormatJSON {
+		return parseJSONFeed(body)
+	}
+	return parseCSVFeed(body)
+}
+
+func (s *FeedImportService) recordRowError(run *models.ImportSourceRun, row int, message string) {
+	run.Failed++
+	if len(run.Errors) < maxFeedDiffItems {
+		run.Errors = append(run.Errors, models.ImportRowError{Row: row, Message: message})
+	}
+}
+
+func (s *FeedImportService) finishRun(run *models.ImportSourceRun, err error) {
+	now := time.Now()
+	run.FinishedAt = &now
+	run.Status = models.ImportRunStatusSucceeded
+	if err != nil {
+		run.Status = models.ImportRunStatusFailed
+		run.Error = err.Error()
+		logger.Error("Import run ", run.ID, " for source ", run.SourceID, " failed: ", err)
+	}
+	if err := s.db.Save(run).Error; err != nil {
+		logger.Error("Failed to save import run ", run.ID, ": ", err)
+	}
+}
+
+// prepareSource validates a source and computes its next run time
+func (s *FeedImportService) prepareSource(source *models.ImportSource) error {
+	if source.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidInput)
+	}
+	if !strings.HasPrefix(source.URL, "http://") && !strings.HasPrefix(source.URL, "https://") {
+		return fmt.Errorf("%w: url must be http or https", ErrInvalidInput)
+	}
+	if source.Mapping["sku"] == "" {
+		return fmt.Errorf("%w: mapping must include sku", ErrInvalidInput)
+	}
+
+	known := make(map[string]bool, len(models.ProductImportFields))
+	for _, field := range models.ProductImportFields {
+		known[field] = true
+	}
+	for field := range source.Mapping {
+		if !known[field] {
+			return fmt.Errorf("%w: unknown product field %q", ErrInvalidInput, field)
+		}
+	}
+
+	next, err := nextScheduledRun(source.Schedule, time.Now())
+	if err != nil {
+		return fmt.Errorf("%w: invalid schedule: %v", ErrInvalidInput, err)
+	}
+	source.NextRunAt = &next
+	return nil
+}
+
+func nextScheduledRun(schedule string, from time.Time) (time.Time, error) {
+	parsed, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parsed.Next(from), nil
+}
+
+func parseCSVFeed(body []byte) ([]map[string]string, error) {
+	reader := csv.NewReader(bytes.NewReader(body))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV feed: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	headers := records[0]
+	rows := make([]map[string]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		if isBlankRow(record) {
+			continue
+		}
+		row := make(map[string]string, len(headers))
+		for i, header := range headers {
+			if i < len(record) {
+				row[strings.TrimSpace(header)] = record[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseJSONFeed accepts an array of flat objects, or an object wrapping one
+// under "products", "items" or "data".
+func parseJSONFeed(body []byte) ([]map[string]string, error) {
+	var items []map[string]interface{}
+	if err := json.Unmarshal(body, &items); err != nil {
+		var wrapper map[string][]map[string]interface{}
+		if werr := json.Unmarshal(body, &wrapper); werr != nil {
+			return nil, fmt.Errorf("failed to parse JSON feed: %v", err)
+		}
+		for _, key := range []string{"products", "items", "data"} {
+			if list, ok := wrapper[key]; ok {
+				items = list
+				break
+			}
+		}
+	}
+
+	rows := make([]map[string]string, 0, len(items))
+	for _, item := range items {
+		row := make(map[string]string, len(item))
+		for key, value := range item {
+			switch v := value.(type) {
+			case nil:
+				row[key] = ""
+			case string:
+				row[key] = v
+			case float64:
+				row[key] = strconv.FormatFloat(v, 'f', -1, 64)
+			default:
+				row[key] = fmt.Sprint(v)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// changedProductFields reports which mapped values differ from the product
+func changedProductFields(product *models.Product, values map[string]string) []string {
+	var changed []string
+	compare := func(field, current string) {
+		if value := values[field]; value != "" && value != current {
+			changed = append(changed, field)
+		}
+	}
+	compare("title", product.Title)
+	compare("description", product.Description)
+	compare("category", product.Category)
+	compare("material", product.Material)
+	compare("size", product.Size)
+	if raw := values["status"]; raw != "" && !strings.EqualFold(raw, product.Status) {
+		changed = append(changed, "status")
+	}
+	if raw := values["price"]; raw != "" {
+		if price, err := parseImportPrice(raw); err != nil || price != product.Price {
+			changed = append(changed, "price")
+		}
+	}
+	if raw := values["stock"]; raw != "" {
+		if stock, err := parseImportStock(raw); err != nil || stock != product.Stock {
+			changed = append(changed, "stock")
+		}
+	}
+	return changed
+}
+
+func appendDiff(run *models.ImportSourceRun, list []string, sku string) []string {
+	if len(list) >= maxFeedDiffItems {
+		run.Diff.Truncated = true
+		return list
+	}
+	return append(list, sku)
+}
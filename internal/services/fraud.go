@@ -0,0 +1,272 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrFraudReviewNotFound = errors.New("fraud review not found")
+
+// SignupOrigin is where a signup request came from
+type SignupOrigin struct {
+	IPAddress string
+	DeviceID  string // X-Device-ID, when the client sends one
+	UserAgent string
+	Country   string // ISO 3166 code of the request IP, when known
+}
+
+// SignupScreening is what fraud screening knows about a signup
+type SignupScreening struct {
+	Email   string
+	Country string // country the customer gave, if any
+	Origin  SignupOrigin
+}
+
+// FraudScreener scores signups for fraud risk
+type FraudScreener interface {
+	ScreenSignup(ctx context.Context, signup SignupScreening) (*models.RiskAssessment, error)
+}
+
+// NewFraudScreener picks the screener from FRAUD_SCREENING. "rules" scores
+// signups with the built-in checks; external risk providers can be added
+// here.
+func NewFraudScreener(cfg *config.Config, db *gorm.DB) (FraudScreener, error) {
+	switch strings.ToLower(cfg.FraudScreening) {
+	case "", "rules":
+		domains := make(map[string]bool, len(defaultDisposableDomains)+len(cfg.FraudDisposableDomains))
+		for _, domain := range defaultDisposableDomains {
+			domains[domain] = true
+		}
+		for _, domain := range cfg.FraudDisposableDomains {
+			domains[strings.ToLower(domain)] = true
+		}
+		return &ruleFraudScreener{db: db, velocity: cfg.FraudSignupVelocity, disposable: domains}, nil
+	case "off":
+		return noFraudScreener{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported fraud screening %q", cfg.FraudScreening)
+	}
+}
+
+// Points each rule adds to the risk score
+const (
+	riskPointsDisposableEmail = 40
+	riskPointsVelocity        = 35
+	riskPointsCountryMismatch = 25
+)
+
+// fraudVelocityWindow is how far back signups from the same IP or device
+// are counted
+const fraudVelocityWindow = time.Hour
+
+// defaultDisposableDomains are common throwaway mailbox providers;
+// FRAUD_DISPOSABLE_DOMAINS adds to them
+var defaultDisposableDomains = []string{
+	"mailinator.com", "guerrillamail.com", "sharklasers.com", "10minutemail.com",
+	"tempmail.com", "temp-mail.org", "yopmail.com", "trashmail.com",
+	"getnada.com", "dispostable.com", "maildrop.cc", "throwawaymail.com",
+}
+
+// ruleFraudScreener flags disposable email domains, bursts of signups from
+// one IP or device, and customers whose stated country differs from where
+// their request came from
+type ruleFraudScreener struct {
+	db         *gorm.DB
+	velocity   int // signups per IP or device per window before the next one is risky
+	disposable map[string]bool
+}
+
+func (r *ruleFraudScreener) ScreenSignup(ctx context.Context, signup SignupScreening) (*models.RiskAssessment, error) {
+	assessment := &models.RiskAssessment{Reasons: []string{}}
+	add := func(points int, reason string) {
+		assessment.Score += points
+		assessment.Reasons = append(assessment.Reasons, reason)
+	}
+
+	if _, domain, ok := strings.Cut(strings.ToLower(strings.TrimSpace(signup.Email)), "@"); ok && r.disposable[domain] {
+		add(riskPointsDisposableEmail, models.RiskReasonDisposableEmail)
+	}
+
+	if r.velocity > 0 {
+		since := time.Now().Add(-fraudVelocityWindow)
+		checks := []struct {
+			column string
+			value  string
+			reason string
+		}{
+			{"signup_ip", signup.Origin.IPAddress, models.RiskReasonIPVelocity},
+			{"signup_device_id", signup.Origin.DeviceID, models.RiskReasonDeviceVelocity},
+		}
+		for _, check := range checks {
+			if check.value == "" {
+				continue
+			}
+			var recent int64
+			if err := r.db.WithContext(ctx).Model(&models.User{}).
+				Where(check.column+" = ? AND created_at > ?", check.value, since).
+				Count(&recent).Error; err != nil {
+				return nil, fmt.Errorf("%w: failed to count recent signups: %v", ErrDatabaseQuery, err)
+			}
+			if recent >= int64(r.velocity) {
+				add(riskPointsVelocity, check.reason)
+			}
+		}
+	}
+
+	if signup.Country != "" && signup.Origin.Country != "" && !strings.EqualFold(signup.Country, signup.Origin.Country) {
+		add(riskPointsCountryMismatch, models.RiskReasonCountryMismatch)
+	}
+
+	if assessment.Score > 100 {
+		assessment.Score = 100
+	}
+	return assessment, nil
+}
+
+// noFraudScreener scores every signup as risk free
+type noFraudScreener struct{}
+
+func (noFraudScreener) ScreenSignup(ctx context.Context, signup SignupScreening) (*models.RiskAssessment, error) {
+	return &models.RiskAssessment{Reasons: []string{}}, nil
+}
+
+// FraudService screens signups and keeps the queue of high-risk ones for
+// admins to clear or block. Orders are not screened yet since the store has
+// no checkout.
+type FraudService struct {
+	db        *gorm.DB
+	screener  FraudScreener
+	threshold int
+}
+
+// NewFraudService queues signups scoring at least threshold for review; 0
+// disables the queue
+func NewFraudService(db *gorm.DB, screener FraudScreener, threshold int) *FraudService {
+	return &FraudService{db: db, screener: screener, threshold: threshold}
+}
+
+// ScreenSignup scores a signup before the account is created
+func (s *FraudService) ScreenSignup(ctx context.Context, signup SignupScreening) (*models.RiskAssessment, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+	return s.screener.ScreenSignup(ctx, signup)
+}
+
+// recordSignupRisk stores the assessment on the new user and queues the
+// signup for review when it scores at or above the threshold. Call it in
+// the signup transaction.
+func (s *FraudService) recordSignupRisk(tx *gorm.DB, user *models.User, assessment *models.RiskAssessment, ipAddress string) error {
+	if assessment.Score == 0 {
+		return nil
+	}
+	if err := tx.Model(user).Select("risk_score", "risk_reasons").
+		Updates(&models.User{RiskScore: assessment.Score, RiskReasons: assessment.Reasons}).Error; err != nil {
+		return fmt.Errorf("%w: failed to store risk score: %v", ErrDatabaseQuery, err)
+	}
+	user.RiskScore, user.RiskReasons = assessment.Score, assessment.Reasons
+
+	if s.threshold <= 0 || assessment.Score < s.threshold {
+		return nil
+	}
+	review := models.FraudReview{
+		UserID:    user.ID,
+		Score:     assessment.Score,
+		Reasons:   assessment.Reasons,
+		IPAddress: ipAddress,
+		Status:    models.FraudReviewPending,
+	}
+	if err := tx.Create(&review).Error; err != nil {
+		return fmt.Errorf("%w: failed to queue fraud review: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
+
+// GetReviews lists fraud reviews, riskiest first, optionally by status
+func (s *FraudService) GetReviews(ctx context.Context, status string, page, limit int) ([]models.FraudReview, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	query := s.db.WithContext(ctx).Model(&models.FraudReview{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to count fraud reviews: %v", ErrDatabaseQuery, err)
+	}
+
+	reviews := make([]models.FraudReview, 0)
+	if err := query.Preload("User").
+		Order("score DESC, id ASC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&reviews).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to fetch fraud reviews: %v", ErrDatabaseQuery, err)
+	}
+	return reviews, total, nil
+}
+
+// ResolveReview records an admin's decision on a pending review. Blocking
+// deactivates the account and signs it out everywhere, and is audited.
+func (s *FraudService) ResolveReview(ctx context.Context, adminID, reviewID uint, req *models.ResolveFraudReviewRequest, ipAddress, userAgent string) (*models.FraudReview, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var review models.FraudReview
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&review, reviewID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: fraud review %d not found", ErrFraudReviewNotFound, reviewID)
+			}
+			return fmt.Errorf("%w: failed to fetch fraud review: %v", ErrDatabaseQuery, err)
+		}
+		if review.Status != models.FraudReviewPending {
+			return fmt.Errorf("%w: fraud review is already %s", ErrInvalidInput, review.Status)
+		}
+
+		now := time.Now()
+		review.Status = req.Decision
+		review.ReviewedBy = &adminID
+		review.ReviewNote = strings.TrimSpace(req.Note)
+		review.ReviewedAt = &now
+		if err := tx.Model(&review).Select("status", "reviewed_by", "review_note", "reviewed_at").Updates(&review).Error; err != nil {
+			return fmt.Errorf("%w: failed to update fraud review: %v", ErrDatabaseQuery, err)
+		}
+		if review.Status != models.FraudReviewBlocked {
+			return nil
+		}
+
+		if err := tx.Model(&models.User{}).Where("id = ?", review.UserID).Update("is_active", false).Error; err != nil {
+			return fmt.Errorf("%w: failed to deactivate user: %v", ErrDatabaseQuery, err)
+		}
+		if err := tx.Model(&models.RefreshToken{}).Where("user_id = ? AND is_revoked = ?", review.UserID, false).
+			Update("is_revoked", true).Error; err != nil {
+			return fmt.Errorf("%w: failed to revoke sessions: %v", ErrDatabaseQuery, err)
+		}
+		entry := models.AuditLog{
+			ActorID:       adminID,
+			SubjectUserID: &review.UserID,
+			Action:        models.AuditActionUserBlocked,
+			IPAddress:     ipAddress,
+			UserAgent:     userAgent,
+			Details:       fmt.Sprintf("blocked after fraud review %d (score %d: %s)", review.ID, review.Score, strings.Join(review.Reasons, ", ")),
+		}
+		if err := tx.Create(&entry).Error; err != nil {
+			return fmt.Errorf("%w: failed to write audit log: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
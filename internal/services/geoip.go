@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/pkg/geo"
+	"golang.org/x/sync/singleflight"
+)
+
+// ExternalAPIGeoIP is the usage tracking name of the GeoIP web service
+const ExternalAPIGeoIP = "geoip"
+
+const (
+	// geoCacheSize bounds the cached lookups; the cache starts over when full
+	geoCacheSize = 10000
+	// geoErrorCacheTTL is how long a failed lookup is remembered, so an
+	// outage doesn't send every request to the web service
+	geoErrorCacheTTL = 30 * time.Second
+)
+
+// GeoLocator finds where an IP address is. A nil location means unknown.
+type GeoLocator interface {
+	Lookup(ctx context.Context, ip string) (*geo.Location, error)
+}
+
+// NewGeoLocator picks the locator from GEOIP_PROVIDER. "maxmind" uses the
+// MaxMind GeoIP2 web service; "off" leaves requests unlocated unless
+// GEOIP_COUNTRY_HEADER names a header set by a trusted proxy or CDN.
+func NewGeoLocator(cfg *config.Config, usageService *APIUsageService) (GeoLocator, error) {
+	switch strings.ToLower(cfg.GeoIPProvider) {
+	case "maxmind":
+		if cfg.MaxMindAccountID == "" || cfg.MaxMindLicenseKey == "" {
+			return nil, fmt.Errorf("maxmind GeoIP provider requires MAXMIND_ACCOUNT_ID and MAXMIND_LICENSE_KEY")
+		}
+		locator := &maxMindLocator{
+			accountID:    cfg.MaxMindAccountID,
+			licenseKey:   cfg.MaxMindLicenseKey,
+			client:       &http.Client{Timeout: 3 * time.Second},
+			usageService: usageService,
+		}
+		return newCachedGeoLocator(locator, cfg.GeoIPCacheTTL), nil
+	case "", "off":
+		return noGeoLocator{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported GeoIP provider %q", cfg.GeoIPProvider)
+	}
+}
+
+// maxMindLocator queries the GeoIP2 City web service
+type maxMindLocator struct {
+	accountID    string
+	licenseKey   string
+	client       *http.Client
+	usageService *APIUsageService
+}
+
+func (m *maxMindLocator) Lookup(ctx context.Context, ip string) (_ *geo.Location, err error) {
+	start := time.Now()
+	defer func() { m.usageService.Record(ExternalAPIGeoIP, err == nil, time.Since(start)) }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://geoip.maxmind.com/geoip/v2.1/city/"+ip, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GeoIP request: %v", err)
+	}
+	req.SetBasicAuth(m.accountID, m.licenseKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GeoIP lookup failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Reserved and unallocated addresses are not found, which is not an error
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GeoIP service returned status: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Country struct {
+			ISOCode string `json:"iso_code"`
+		} `json:"country"`
+		Subdivisions []struct {
+			ISOCode string `json:"iso_code"`
+		} `json:"subdivisions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse GeoIP response: %v", err)
+	}
+	if result.Country.ISOCode == "" {
+		return nil, nil
+	}
+	loc := &geo.Location{Country: result.Country.ISOCode}
+	if len(result.Subdivisions) > 0 {
+		loc.Region = result.Subdivisions[0].ISOCode
+	}
+	return loc, nil
+}
+
+// noGeoLocator locates nothing
+type noGeoLocator struct{}
+
+func (noGeoLocator) Lookup(ctx context.Context, ip string) (*geo.Location, error) {
+	return nil, nil
+}
+
+type geoCacheEntry struct {
+	loc      *geo.Location
+	err      error
+	cachedAt time.Time
+}
+
+func (e geoCacheEntry) fresh(ttl time.Duration) bool {
+	if e.err != nil {
+		ttl = geoErrorCacheTTL
+	}
+	return time.Since(e.cachedAt) < ttl
+}
+
+// cachedGeoLocator remembers lookups for ttl, including unknown addresses,
+// and failed lookups for geoErrorCacheTTL. Concurrent lookups of one address
+// share a single request, and private or loopback addresses are never
+// looked up.
+type cachedGeoLocator struct {
+	next  GeoLocator
+	ttl   time.Duration
+	group singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]geoCacheEntry
+}
+
+func newCachedGeoLocator(next GeoLocator, ttl time.Duration) *cachedGeoLocator {
+	return &cachedGeoLocator{next: next, ttl: ttl, entries: make(map[string]geoCacheEntry)}
+}
+
+func (c *cachedGeoLocator) Lookup(ctx context.Context, ip string) (*geo.Location, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil || parsed.IsPrivate() || parsed.IsLoopback() || parsed.IsLinkLocalUnicast() || parsed.IsUnspecified() {
+		return nil, nil
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[ip]
+	c.mu.Unlock()
+	if ok && entry.fresh(c.ttl) {
+		return entry.loc, entry.err
+	}
+
+	result, err, _ := c.group.Do(ip, func() (interface{}, error) {
+		loc, err := c.next.Lookup(ctx, ip)
+		// A lookup cut short by the caller says nothing about the service
+		if c.ttl > 0 && ctx.Err() == nil {
+			c.mu.Lock()
+			if len(c.entries) >= geoCacheSize {
+				c.entries = make(map[string]geoCacheEntry)
+			}
+			c.entries[ip] = geoCacheEntry{loc: loc, err: err, cachedAt: time.Now()}
+			c.mu.Unlock()
+		}
+		return loc, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*geo.Location), nil
+}
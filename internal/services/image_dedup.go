@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DeletionReasonDuplicateUpload marks an object uploaded while another
+// request stored the same content first
+const DeletionReasonDuplicateUpload = "duplicate_upload"
+
+// Product images are stored once per distinct content. Uploads hash the
+// content and reuse the ImageObject with the same hash instead of storing it
+// again; retainImageObjects and releaseImageObjects keep ImageObject.RefCount
+// in step with the active Image rows inside the caller's transaction.
+
+// uploadProductImages uploads product images like S3Service.UploadMultipleImages,
+// reusing the stored object of any file whose content is already in S3. On
+// failure the objects uploaded by this call are deleted again.
+func uploadProductImages(ctx context.Context, db *gorm.DB, s3Service *S3Service, files []*multipart.FileHeader) ([]*UploadResult, error) {
+	var results []*UploadResult
+	var uploadErrors []string
+
+	for i, fileHeader := range files {
+		if err := ctx.Err(); err != nil {
+			uploadErrors = append(uploadErrors, fmt.Sprintf("upload cancelled: %v", err))
+			break
+		}
+
+		result, err := uploadProductImage(ctx, db, s3Service, fileHeader)
+		if err != nil {
+			uploadErrors = append(uploadErrors, fmt.Sprintf("file %d (%s): %v", i+1, fileHeader.Filename, err))
+			continue
+		}
+		results = append(results, result)
+	}
+
+	if len(uploadErrors) > 0 {
+		for _, key := range uploadedKeys(results) {
+			if err := s3Service.DeleteImage(key); err != nil {
+				s3Service.log.WithContext(ctx).WithFields(map[string]interface{}{"s3_key": key}).Warn("Failed to clean up partial upload: ", err)
+			}
+		}
+		return nil, fmt.Errorf("upload errors: %s", strings.Join(uploadErrors, "; "))
+	}
+	return results, nil
+}
+
+func uploadProductImage(ctx context.Context, db *gorm.DB, s3Service *S3Service, header *multipart.FileHeader) (*UploadResult, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open: %v", err)
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return nil, fmt.Errorf("failed to read file: %v", err)
+	}
+	hash := hex.EncodeToString(hasher.Sum(nil))
+
+	if result, err := reuseImageObject(ctx, db, hash, header.Filename); result != nil || err != nil {
+		return result, err
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to rewind file: %v", err)
+	}
+	result, err := s3Service.UploadImage(ctx, file, header)
+	if err != nil {
+		return nil, err
+	}
+	result.SHA256 = hash
+	return result, nil
+}
+
+// uploadProductImageData is uploadProductImage for an image already in memory
+func uploadProductImageData(ctx context.Context, db *gorm.DB, s3Service *S3Service, fileName string, data []byte) (*UploadResult, error) {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	if result, err := reuseImageObject(ctx, db, hash, fileName); result != nil || err != nil {
+		return result, err
+	}
+
+	result, err := s3Service.UploadImageData(fileName, data)
+	if err != nil {
+		return nil, err
+	}
+	result.SHA256 = hash
+	return result, nil
+}
+
+// reuseImageObject returns an upload result pointing at the stored object
+// with the given hash, or nil when the content is not stored yet
+func reuseImageObject(ctx context.Context, db *gorm.DB, hash, fileName string) (*UploadResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var object models.ImageObject
+	if err := db.WithContext(ctx).Where("sha256 = ? AND ref_count > 0", hash).First(&object).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%w: failed to look up image object: %v", ErrDatabaseQuery, err)
+	}
+	return &UploadResult{
+		Key:         object.S3Key,
+		URL:         object.S3URL,
+		FileName:    fileName,
+		ContentType: object.ContentType,
+		Size:        object.Size,
+		SHA256:      hash,
+		Reused:      true,
+	}, nil
+}
+
+// uploadedKeys returns the keys of the objects results created, leaving out
+// reused ones that other images still point at
+func uploadedKeys(results []*UploadResult) []string {
+	keys := make([]string, 0, len(results))
+	for _, result := range results {
+		if !result.Reused {
+			keys = append(keys, result.Key)
+		}
+	}
+	return keys
+}
+
+// retainImageObjects counts a reference for each image about to be created.
+// If another request stored the same content first, the image is pointed at
+// that object and the one uploaded for it is queued for deletion.
+func retainImageObjects(tx *gorm.DB, images []models.Image) error {
+	var superseded []string
+	for i := range images {
+		image := &images[i]
+		if image.SHA256 == "" {
+			continue
+		}
+
+		object := models.ImageObject{
+			SHA256:      image.SHA256,
+			S3Key:       image.S3Key,
+			S3URL:       image.S3URL,
+			ContentType: image.ContentType,
+			Size:        image.Size,
+			RefCount:    1,
+		}
+		err := tx.Clauses(
+			clause.OnConflict{
+				Columns: []clause.Column{{Name: "sha256"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"ref_count":  gorm.Expr("image_objects.ref_count + 1"),
+					"updated_at": time.Now(),
+				}),
+			},
+			clause.Returning{},
+		).Create(&object).Error
+		if err != nil {
+			return fmt.Errorf("%w: failed to reference image object: %v", ErrDatabaseQuery, err)
+		}
+
+		if object.S3Key != image.S3Key {
+			superseded = append(superseded, image.S3Key)
+			image.S3Key = object.S3Key
+			image.S3URL = object.S3URL
+		}
+	}
+	return queueS3Deletions(tx, DeletionReasonDuplicateUpload, superseded)
+}
+
+// releaseImageObjects drops the references of active images being removed
+// and returns the keys of objects no image uses any more. Images uploaded
+// before deduplication own their object outright.
+func releaseImageObjects(tx *gorm.DB, images []models.Image) ([]string, error) {
+	var keys []string
+	for _, image := range images {
+		if image.S3Key == "" {
+			continue
+		}
+		if image.SHA256 == "" {
+			keys = append(keys, image.S3Key)
+			continue
+		}
+
+		var object models.ImageObject
+		result := tx.Model(&object).Clauses(clause.Returning{}).
+			Where("sha256 = ?", image.SHA256).
+			Update("ref_count", gorm.Expr("ref_count - 1"))
+		if result.Error != nil {
+			return nil, fmt.Errorf("%w: failed to release image object: %v", ErrDatabaseQuery, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			keys = append(keys, image.S3Key)
+			continue
+		}
+		if object.RefCount <= 0 {
+			if err := tx.Delete(&object).Error; err != nil {
+				return nil, fmt.Errorf("%w: failed to delete image object: %v", ErrDatabaseQuery, err)
+			}
+			keys = append(keys, object.S3Key)
+		}
+	}
+	return keys, nil
+}
+
+// referencedImageKeys returns which of keys still belong to an image object
+// in use, so the deletion worker can skip objects that were reused after
+// their deletion was queued
+func referencedImageKeys(tx *gorm.DB, keys []string) (map[string]bool, error) {
+	referenced := make(map[string]bool)
+	if len(keys) == 0 {
+		return referenced, nil
+	}
+
+	var inUse []string
+	if err := tx.Model(&models.ImageObject{}).
+		Where("s3_key IN ? AND ref_count > 0", keys).
+		Pluck("s3_key", &inUse).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to check image objects: %v", ErrDatabaseQuery, err)
+	}
+	for _, key := range inUse {
+		referenced[key] = true
+	}
+	return referenced, nil
+}
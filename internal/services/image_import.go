@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// imageFetchTimeout bounds the download of one remote image
+const imageFetchTimeout = 30 * time.Second
+
+// errPrivateAddress is returned when a remote image resolves to an address
+// inside our network
+var errPrivateAddress = errors.New("address is not publicly routable")
+
+// imageExtensions names downloaded images by their sniffed content type, as
+// S3Service.UploadImageData checks the type by extension
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+	"image/bmp":  ".bmp",
+}
+
+// imageFetchClient downloads remote images
+var imageFetchClient = newPublicHTTPClient(imageFetchTimeout)
+
+// newPublicHTTPClient returns a client for URLs supplied by admins. It
+// refuses to connect to private, loopback and link-local addresses, also
+// after redirects, so it cannot be pointed at internal services.
+func newPublicHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout: 10 * time.Second,
+				Control: func(network, address string, _ syscall.RawConn) error {
+					host, _, err := net.SplitHostPort(address)
+					if err != nil {
+						return err
+					}
+					ip := net.ParseIP(host)
+					if ip == nil || ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() {
+						return errPrivateAddress
+					}
+					return nil
+				},
+			}).DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+	}
+}
+
+// StartImageImport queues a job that downloads remote images and attaches
+// them to a product. Each URL is reported in the job log; URLs that fail do
+// not stop the others.
+func (s *AdminService) StartImageImport(ctx context.Context, userID, productID uint, rawURLs []string) (*models.Job, error) {
+	urls := make([]string, 0, len(rawURLs))
+	seen := make(map[string]bool, len(rawURLs))
+	for _, rawURL := range rawURLs {
+		rawURL = strings.TrimSpace(rawURL)
+		parsed, err := url.ParseRequestURI(rawURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("%w: %q is not an absolute http(s) URL", ErrInvalidInput, rawURL)
+		}
+		if !seen[rawURL] {
+			seen[rawURL] = true
+			urls = append(urls, rawURL)
+		}
+	}
+
+	if err := s.checkProductExists(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	return s.jobService.Enqueue(ctx, models.JobTypeImageImport, userID, func(ctx context.Context, run *JobRun) error {
+		run.SetTotal(len(urls))
+		run.Infof("Importing %d images for product %d", len(urls), productID)
+
+		uploads := make([]*UploadResult, 0, len(urls))
+		sources := make([]string, 0, len(urls))
+		for _, rawURL := range urls {
+			if err := ctx.Err(); err != nil {
+				s.discardUploads(ctx, uploads)
+				return err
+			}
+
+			upload, err := s.importRemoteImage(ctx, rawURL)
+			if err != nil {
+				run.Warnf("%s: %v", rawURL, err)
+				run.Advance(0, 1)
+				continue
+			}
+			uploads = append(uploads, upload)
+			sources = append(sources, rawURL)
+		}
+
+		if len(uploads) == 0 {
+			run.SetResult(fmt.Sprintf("Imported 0 of %d images", len(urls)), "")
+			return nil
+		}
+
+		images := make([]models.Image, 0, len(uploads))
+		for _, upload := range uploads {
+			images = append(images, models.Image{
+				ProductID:   productID,
+				FileName:    upload.FileName,
+				S3Key:       upload.Key,
+				S3URL:       upload.URL,
+				ContentType: upload.ContentType,
+				Size:        upload.Size,
+				SHA256:      upload.SHA256,
+				IsActive:    true,
+			})
+		}
+
+		err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			// The product may have been deleted while images downloaded
+			var product models.Product
+			if err := tx.Select("id").First(&product, productID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return fmt.Errorf("%w: product with ID %d not found", ErrProductNotFound, productID)
+				}
+				return fmt.Errorf("%w: failed to fetch product: %v", ErrDatabaseQuery, err)
+			}
+			if err := retainImageObjects(tx, images); err != nil {
+				return err
+			}
+			if err := tx.Create(&images).Error; err != nil {
+				return fmt.Errorf("%w: failed to create image records: %v", ErrDatabaseQuery, err)
+			}
+			return publishProductUpdated(tx, productID)
+		})
+		if err != nil {
+			s.discardUploads(ctx, uploads)
+			run.Advance(0, len(uploads))
+			return err
+		}
+
+		for i, image := range images {
+			run.Infof("%s: attached as image %s", sources[i], image.ID)
+		}
+		run.Advance(len(images), 0)
+		run.SetResult(fmt.Sprintf("Imported %d of %d images", len(images), len(urls)), "")
+		return nil
+	})
+}
+
+// checkProductExists returns ErrProductNotFound unless the product exists
+func (s *AdminService) checkProductExists(ctx context.Context, productID uint) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.Product{}).Where("id = ?", productID).Count(&count).Error; err != nil {
+		return fmt.Errorf("%w: failed to fetch product: %v", ErrDatabaseQuery, err)
+	}
+	if count == 0 {
+		return fmt.Errorf("%w: product with ID %d not found", ErrProductNotFound, productID)
+	}
+	return nil
+}
+
+// importRemoteImage downloads an image, checks its size and content, and
+// stores it like an uploaded product image
+func (s *AdminService) importRemoteImage(ctx context.Context, rawURL string) (*UploadResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %v", err)
+	}
+	req.Header.Set("Accept", "image/*")
+
+	resp, err := imageFetchClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("server returned HTTP %d", resp.StatusCode)
+	}
+	if resp.ContentLength > MaxImageSize {
+		return nil, fmt.Errorf("image is larger than %d bytes", MaxImageSize)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxImageSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("download failed: %v", err)
+	}
+	if len(data) > MaxImageSize {
+		return nil, fmt.Errorf("image is larger than %d bytes", MaxImageSize)
+	}
+
+	// Trust the content, not the server's Content-Type or the URL's extension
+	contentType := http.DetectContentType(data)
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported content type %s", contentType)
+	}
+
+	name := strings.TrimSuffix(path.Base(resp.Request.URL.Path), path.Ext(resp.Request.URL.Path))
+	if name == "" || name == "." || name == "/" {
+		name = "image"
+	}
+
+	upload, err := uploadProductImageData(ctx, s.db, s.s3Service, name+ext, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrS3Upload, err)
+	}
+	return upload, nil
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+const (
+	maxAltTextLength = 250
+	maxCaptionLength = 1000
+	// altTextBatchSize is how many images are sent to the AI service per call
+	altTextBatchSize = 20
+)
+
+// UpdateImageMetadata sets the alt text and/or caption of one of a product's
+// images
+func (s *AdminService) UpdateImageMetadata(ctx context.Context, productID uint, imageID uuid.UUID, req *models.UpdateImageRequest) (*models.Image, error) {
+	updates := map[string]interface{}{}
+	if req.AltText != nil {
+		altText := strings.TrimSpace(*req.AltText)
+		if utf8.RuneCountInString(altText) > maxAltTextLength {
+			return nil, fmt.Errorf("%w: alt_text cannot exceed %d characters", ErrInvalidInput, maxAltTextLength)
+		}
+		updates["alt_text"] = altText
+	}
+	if req.Caption != nil {
+		caption := strings.TrimSpace(*req.Caption)
+		if utf8.RuneCountInString(caption) > maxCaptionLength {
+			return nil, fmt.Errorf("%w: caption cannot exceed %d characters", ErrInvalidInput, maxCaptionLength)
+		}
+		updates["caption"] = caption
+	}
+	if len(updates) == 0 {
+		return nil, fmt.Errorf("%w: nothing to update", ErrInvalidInput)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var image models.Image
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND product_id = ? AND is_active = ?", imageID, productID, true).First(&image).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: image %s not found on product %d", ErrImageNotFound, imageID, productID)
+			}
+			return fmt.Errorf("%w: failed to find image: %v", ErrDatabaseQuery, err)
+		}
+		if err := tx.Model(&image).Updates(updates).Error; err != nil {
+			return fmt.Errorf("%w: failed to update image: %v", ErrDatabaseQuery, err)
+		}
+		return publishProductUpdated(tx, productID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// StartAltTextGeneration queues a job that asks the AI service for alt text
+// for active images that have none, optionally limited to some products.
+// Images whose alt text is set while the job runs are left alone.
+func (s *AdminService) StartAltTextGeneration(ctx context.Context, userID uint, productIDs []uint) (*models.Job, error) {
+	return s.jobService.Enqueue(ctx, models.JobTypeImageAltText, userID, func(ctx context.Context, run *JobRun) error {
+		query := func() *gorm.DB {
+			q := s.db.WithContext(ctx).Model(&models.Image{}).
+				Where("is_active = ? AND alt_text = ? AND missing_at IS NULL", true, "")
+			if len(productIDs) > 0 {
+				q = q.Where("product_id IN ?", productIDs)
+			}
+			return q
+		}
+
+		var total int64
+		if err := query().Count(&total).Error; err != nil {
+			return fmt.Errorf("%w: failed to count images: %v", ErrDatabaseQuery, err)
+		}
+		run.SetTotal(int(total))
+		run.Infof("Generating alt text for %d images", total)
+
+		// Page by id so images that still have no alt text after a batch are
+		// not fetched again
+		var lastID uuid.UUID
+		var described int
+		for {
+			var batch []models.Image
+			if err := query().Where("id > ?", lastID).Order("id ASC").Limit(altTextBatchSize).Find(&batch).Error; err != nil {
+				return fmt.Errorf("%w: failed to read images: %v", ErrDatabaseQuery, err)
+			}
+			if len(batch) == 0 {
+				break
+			}
+			lastID = batch[len(batch)-1].ID
+
+			ok, err := s.describeImages(ctx, batch)
+			if errors.Is(err, ErrFastAPIBadInput) {
+				run.Warnf("AI service rejected a batch of %d images: %v", len(batch), err)
+				run.Advance(0, len(batch))
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			described += ok
+			run.Advance(ok, len(batch)-ok)
+		}
+
+		run.SetResult(fmt.Sprintf("Generated alt text for %d of %d images", described, total), "")
+		return nil
+	})
+}
+
+// describeImages generates alt text for a batch of images and stores it,
+// returning how many images got one
+func (s *AdminService) describeImages(ctx context.Context, batch []models.Image) (int, error) {
+	inputs := make([]ImageDescriptionInput, 0, len(batch))
+	productIDs := make(map[string]uint, len(batch))
+	for _, image := range batch {
+		inputs = append(inputs, ImageDescriptionInput{ID: image.ID.String(), URL: image.S3URL})
+		productIDs[image.ID.String()] = image.ProductID
+	}
+
+	descriptions, err := s.fastAPIService.DescribeImages(ctx, inputs)
+	if err != nil {
+		return 0, err
+	}
+
+	described := 0
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		changed := make(map[uint]bool)
+		for _, description := range descriptions {
+			productID, ok := productIDs[description.ID]
+			altText := truncateRunes(strings.TrimSpace(description.AltText), maxAltTextLength)
+			if !ok || altText == "" {
+				continue
+			}
+			result := tx.Model(&models.Image{}).
+				Where("id = ? AND alt_text = ?", description.ID, "").
+				Update("alt_text", altText)
+			if result.Error != nil {
+				return fmt.Errorf("%w: failed to save alt text: %v", ErrDatabaseQuery, result.Error)
+			}
+			if result.RowsAffected > 0 {
+				described++
+				changed[productID] = true
+			}
+		}
+		for productID := range changed {
+			if err := publishProductUpdated(tx, productID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return described, nil
+}
+
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return strings.TrimSpace(string([]rune(s)[:max]))
+}
@@ -0,0 +1,355 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // register the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // register the WebP decoder
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	ErrImageNotFound      = errors.New("image not found")
+	ErrInvalidImageParams = errors.New("invalid image parameters")
+)
+
+const (
+	ImageFitCover   = "cover"   // fill the box exactly, cropping the overflow
+	ImageFitContain = "contain" // fit inside the box, keeping the aspect ratio
+
+	imageCachePrefix    = "cache/img/"
+	defaultImageQuality = 82
+	maxSourceImageBytes = 25 * 1024 * 1024
+	// maxSourceImagePixels bounds the decoded size, since a small file can
+	// declare huge dimensions
+	maxSourceImagePixels = 50_000_000
+
+	// Requested sizes are rounded up to a multiple of imageSizeStep, and
+	// qualities to a multiple of imageQualityStep, so callers can't fill the
+	// variant cache by asking for every size in turn
+	imageSizeStep    = 50
+	imageQualityStep = 10
+)
+
+// imageProxyPrefixes are the public image folders the proxy serves from.
+// Other objects in the bucket, such as avatars or exports, are not exposed.
+var imageProxyPrefixes = []string{"products/images/", "banners/"}
+
+// ImageTransform describes a requested variant of a stored image. Zero width
+// or height means "derive from the aspect ratio".
+type ImageTransform struct {
+	Width   int
+	Height  int
+	Format  string // jpeg (default) or png
+	Fit     string
+	Quality int
+}
+
+// ImageVariant is a rendered image ready to be served.
+type ImageVariant struct {
+	Data        []byte
+	ContentType string
+	CacheKey    string
+}
+
+// ImageCache stores rendered variants so each size is only generated once.
+type ImageCache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, data []byte) error
+}
+
+type ImageProxyService struct {
+	s3Service    *S3Service
+	cache        ImageCache
+	maxDimension int
+	group        singleflight.Group
+}
+
+func NewImageProxyService(cfg *config.Config, s3Service *S3Service) *ImageProxyService {
+	var cache ImageCache
+	switch strings.ToLower(cfg.ImageCacheBackend) {
+	case "s3":
+		cache = &s3ImageCache{s3Service: s3Service}
+	case "none":
+		cache = nil
+	default:
+		cache = &localImageCache{dir: cfg.ImageCacheDir}
+	}
+
+	return &ImageProxyService{
+		s3Service:    s3Service,
+		cache:        cache,
+		maxDimension: cfg.ImageMaxDimension,
+	}
+}
+
+// GetVariant returns the stored image at key transformed as requested,
+// rendering and caching it on first use.
+func (s *ImageProxyService) GetVariant(ctx context.Context, key string, t ImageTransform) (*ImageVariant, error) {
+	key = strings.TrimPrefix(key, "/")
+	if err := s.validate(key, &t); err != nil {
+		return nil, err
+	}
+
+	cacheKey := imageVariantKey(key, t)
+	contentType := imageContentType(t.Format)
+
+	if s.cache != nil {
+		if data, ok := s.cache.Get(cacheKey); ok {
+			return &ImageVariant{Data: data, ContentType: contentType, CacheKey: cacheKey}, nil
+		}
+	}
+
+	// Concurrent requests for the same variant share one render
+	result, err, _ := s.group.Do(cacheKey, func() (interface{}, error) {
+		data, err := s.render(key, t)
+		if err != nil {
+			return nil, err
+		}
+		if s.cache != nil {
+			if err := s.cache.Set(cacheKey, data); err != nil {
+				logger.Warn("Failed to cache image variant ", cacheKey, ": ", err)
+			}
+		}
+		return data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ImageVariant{Data: result.([]byte), ContentType: contentType, CacheKey: cacheKey}, nil
+}
+
+func (s *ImageProxyService) validate(key string, t *ImageTransform) error {
+	if key == "" || strings.Contains(key, "..") || !hasImageProxyPrefix(key) {
+		return fmt.Errorf("%w: invalid image key", ErrInvalidImageParams)
+	}
+	if t.Width < 0 || t.Height < 0 || t.Width > s.maxDimension || t.Height > s.maxDimension {
+		return fmt.Errorf("%w: width and height must be between 0 and %d", ErrInvalidImageParams, s.maxDimension)
+	}
+
+	switch strings.ToLower(t.Format) {
+	case "", "jpg", "jpeg":
+		t.Format = "jpeg"
+	case "png":
+		t.Format = "png"
+	default:
+		return fmt.Errorf("%w: unsupported format %q", ErrInvalidImageParams, t.Format)
+	}
+
+	switch t.Fit {
+	case "":
+		t.Fit = ImageFitContain
+	case ImageFitCover, ImageFitContain:
+	default:
+		return fmt.Errorf("%w: fit must be %s or %s", ErrInvalidImageParams, ImageFitCover, ImageFitContain)
+	}
+
+	if t.Quality == 0 {
+		t.Quality = defaultImageQuality
+	}
+	if t.Quality < 1 || t.Quality > 100 {
+		return fmt.Errorf("%w: quality must be between 1 and 100", ErrInvalidImageParams)
+	}
+
+	t.Width = roundUpToStep(t.Width, imageSizeStep, s.maxDimension)
+	t.Height = roundUpToStep(t.Height, imageSizeStep, s.maxDimension)
+	t.Quality = roundUpToStep(t.Quality, imageQualityStep, 100)
+	return nil
+}
+
+// roundUpToStep rounds value up to a multiple of step, without passing max
+func roundUpToStep(value, step, max int) int {
+	value = (value + step - 1) / step * step
+	if value > max {
+		return max
+	}
+	return value
+}
+
+func hasImageProxyPrefix(key string) bool {
+	for _, prefix := range imageProxyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *ImageProxyService) render(key string, t ImageTransform) ([]byte, error) {
+	body, err := s.s3Service.OpenObject(key)
+	if err != nil {
+		if errors.Is(err, ErrObjectNotFound) {
+			return nil, ErrImageNotFound
+		}
+		return nil, err
+	}
+	defer body.Close()
+
+	source, err := io.ReadAll(io.LimitReader(body, maxSourceImageBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source image: %v", err)
+	}
+	if len(source) > maxSourceImageBytes {
+		return nil, fmt.Errorf("%w: source image too large", ErrInvalidImageParams)
+	}
+
+	// Check the header before decoding so a small file cannot claim
+	// dimensions that exhaust memory
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(source))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decode image: %v", ErrInvalidImageParams, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > maxSourceImagePixels {
+		return nil, fmt.Errorf("%w: source image dimensions too large", ErrInvalidImageParams)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(source))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decode image: %v", ErrInvalidImageParams, err)
+	}
+
+	dst := resizeImage(src, t.Width, t.Height, t.Fit, s.maxDimension)
+
+	var buf bytes.Buffer
+	switch t.Format {
+	case "png":
+		err = png.Encode(&buf, dst)
+	default:
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: t.Quality})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode image: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// resizeImage scales src into a width x height box. With cover the source is
+// center-cropped to the target aspect ratio first so no letterboxing occurs.
+// A dimension derived from a very tall or wide source is clamped to
+// maxDimension, shrinking the other to keep the aspect ratio.
+func resizeImage(src image.Image, width, height int, fit string, maxDimension int) image.Image {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if width == 0 && height == 0 {
+		return src
+	}
+
+	// Derive the missing dimension from the aspect ratio
+	if width == 0 {
+		width = srcW * height / srcH
+	}
+	if height == 0 {
+		height = srcH * width / srcW
+	}
+	if width > maxDimension {
+		height = height * maxDimension / width
+		width = maxDimension
+	}
+	if height > maxDimension {
+		width = width * maxDimension / height
+		height = maxDimension
+	}
+
+	srcRect := bounds
+	if fit == ImageFitCover {
+		if srcW*height > srcH*width {
+			cropW := srcH * width / height
+			x0 := bounds.Min.X + (srcW-cropW)/2
+			srcRect = image.Rect(x0, bounds.Min.Y, x0+cropW, bounds.Max.Y)
+		} else {
+			cropH := srcW * height / width
+			y0 := bounds.Min.Y + (srcH-cropH)/2
+			srcRect = image.Rect(bounds.Min.X, y0, bounds.Max.X, y0+cropH)
+		}
+	} else {
+		if srcW*height > srcH*width {
+			height = srcH * width / srcW
+		} else {
+			width = srcW * height / srcH
+		}
+	}
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, srcRect, draw.Over, nil)
+	return dst
+}
+
+func imageVariantKey(key string, t ImageTransform) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%d|%s|%d", key, t.Width, t.Height, t.Fit, t.Quality)))
+	return hex.EncodeToString(sum[:16]) + "." + t.Format
+}
+
+func imageContentType(format string) string {
+	if format == "png" {
+		return "image/png"
+	}
+	return "image/jpeg"
+}
+
+type localImageCache struct {
+	dir string
+}
+
+func (c *localImageCache) path(key string) string {
+	// Fan out into subdirectories to keep directory sizes reasonable
+	return filepath.Join(c.dir, key[:2], key)
+}
+
+func (c *localImageCache) Get(key string) ([]byte, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+func (c *localImageCache) Set(key string, data []byte) error {
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// Write then rename so readers never see a partial file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+type s3ImageCache struct {
+	s3Service *S3Service
+}
+
+func (c *s3ImageCache) Get(key string) ([]byte, bool) {
+	data, _, err := c.s3Service.GetObject(imageCachePrefix + key)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+func (c *s3ImageCache) Set(key string, data []byte) error {
+	return c.s3Service.PutObject(imageCachePrefix+key, imageContentType(strings.TrimPrefix(filepath.Ext(key), ".")), data)
+}
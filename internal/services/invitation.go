@@ -0,0 +1,325 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const defaultInvitationTTL = 72 * time.Hour
+
+var (
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrInvitationInvalid  = errors.New("invitation is no longer valid")
+)
+
+// InvitationService lets admins invite other admins and vendor staff by
+// email. Public signup only creates customers, so invitations are the way
+// staff accounts are created besides promotion by an admin.
+type InvitationService struct {
+	db           *gorm.DB
+	emailService *EmailService
+	baseURL      string
+	ttl          time.Duration
+}
+
+func NewInvitationService(db *gorm.DB, emailService *EmailService, baseURL string, ttl time.Duration) *InvitationService {
+	if ttl <= 0 {
+		ttl = defaultInvitationTTL
+	}
+	return &InvitationService{db: db, emailService: emailService, baseURL: baseURL, ttl: ttl}
+}
+
+// CreateInvitation records an invitation and emails its token. Earlier
+// pending invitations for the same email are revoked. The email is sent
+// after commit, so a slow mail server holds no locks; when sending fails
+// the new invitation is revoked again.
+func (s *InvitationService) CreateInvitation(ctx context.Context, adminID uint, req *models.CreateInvitationRequest) (*models.Invitation, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if req.Role == models.RoleVendor && req.VendorID == 0 {
+		return nil, fmt.Errorf("%w: vendor_id is required for vendor invitations", ErrInvalidInput)
+	}
+	if s.emailService == nil {
+		return nil, errors.New("email service unavailable")
+	}
+
+	token, err := generateInvitationToken()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	invitation := models.Invitation{
+		Email:     email,
+		Role:      req.Role,
+		TokenHash: hashRefreshToken(token),
+		Status:    models.InvitationStatusPending,
+		InvitedBy: adminID,
+		ExpiresAt: time.Now().Add(s.ttl),
+	}
+	if req.Role == models.RoleVendor {
+		invitation.VendorID = &req.VendorID
+	}
+
+	var inviter models.User
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if invitation.VendorID != nil {
+			var vendors int64
+			if err := tx.Model(&models.Vendor{}).Where("id = ?", *invitation.VendorID).Count(&vendors).Error; err != nil {
+				return fmt.Errorf("%w: failed to check vendor: %v", ErrDatabaseQuery, err)
+			}
+			if vendors == 0 {
+				return fmt.Errorf("%w: vendor %d not found", ErrVendorNotFound, *invitation.VendorID)
+			}
+		}
+
+		var existing models.User
+		err := tx.Where("email = ?", email).First(&existing).Error
+		switch {
+		case err == nil && existing.Role == "admin":
+			return fmt.Errorf("%w: %s is already an admin", ErrInvalidInput, email)
+		case err == nil && existing.Role == models.RoleVendor && req.Role == models.RoleVendor:
+			return fmt.Errorf("%w: %s already works for a vendor", ErrInvalidInput, email)
+		case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+			return fmt.Errorf("%w: failed to check existing users: %v", ErrDatabaseQuery, err)
+		}
+
+		if err := tx.Model(&models.Invitation{}).
+			Where("email = ? AND status = ?", email, models.InvitationStatusPending).
+			Update("status", models.InvitationStatusRevoked).Error; err != nil {
+			return fmt.Errorf("%w: failed to revoke earlier invitations: %v", ErrDatabaseQuery, err)
+		}
+		if err := tx.Create(&invitation).Error; err != nil {
+			return fmt.Errorf("%w: failed to create invitation: %v", ErrDatabaseQuery, err)
+		}
+
+		if err := tx.Select("first_name", "last_name", "email").First(&inviter, adminID).Error; err != nil {
+			return fmt.Errorf("%w: failed to load inviter: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.emailService.SendInvitationEmail(email, inviterName(&inviter), invitation.Role, token, s.baseURL, invitation.ExpiresAt); err != nil {
+		// Nobody holds the token, so do not leave a pending invitation behind
+		if revokeErr := s.db.WithContext(ctx).Model(&invitation).Update("status", models.InvitationStatusRevoked).Error; revokeErr != nil {
+			return nil, fmt.Errorf("failed to send invitation email: %v (and failed to revoke invitation %d: %v)", err, invitation.ID, revokeErr)
+		}
+		return nil, fmt.Errorf("failed to send invitation email: %v", err)
+	}
+	return &invitation, nil
+}
+
+// GetInvitations lists invitations, newest first. status is one of the
+// invitation states or empty for all; pending excludes expired invitations.
+func (s *InvitationService) GetInvitations(ctx context.Context, status string, page, limit int) ([]models.Invitation, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	now := time.Now()
+	query := s.db.WithContext(ctx).Model(&models.Invitation{})
+	switch status {
+	case "":
+	case models.InvitationStatusPending:
+		query = query.Where("status = ? AND expires_at > ?", models.InvitationStatusPending, now)
+	case models.InvitationStatusExpired:
+		query = query.Where("status = ? AND expires_at <= ?", models.InvitationStatusPending, now)
+	default:
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to count invitations: %v", ErrDatabaseQuery, err)
+	}
+
+	invitations := make([]models.Invitation, 0)
+	if err := query.Preload("Inviter").Preload("Vendor").
+		Order("created_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&invitations).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to fetch invitations: %v", ErrDatabaseQuery, err)
+	}
+	for i := range invitations {
+		markExpired(&invitations[i], now)
+	}
+	return invitations, total, nil
+}
+
+// RevokeInvitation cancels a pending invitation
+func (s *InvitationService) RevokeInvitation(ctx context.Context, id uint) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var invitation models.Invitation
+	if err := s.db.WithContext(ctx).First(&invitation, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: invitation %d not found", ErrInvitationNotFound, id)
+		}
+		return fmt.Errorf("%w: failed to fetch invitation: %v", ErrDatabaseQuery, err)
+	}
+	if invitation.Status != models.InvitationStatusPending {
+		return fmt.Errorf("%w: invitation is %s", ErrInvalidInput, invitation.Status)
+	}
+
+	if err := s.db.WithContext(ctx).Model(&invitation).Update("status", models.InvitationStatusRevoked).Error; err != nil {
+		return fmt.Errorf("%w: failed to revoke invitation: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
+
+// PreviewInvitation shows who an invitation is for, so the accept page can
+// tell a new user from one who signs in with an existing account
+func (s *InvitationService) PreviewInvitation(ctx context.Context, token string) (*models.InvitationPreview, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	invitation, err := s.findValid(s.db.WithContext(ctx), token)
+	if err != nil {
+		return nil, err
+	}
+	return &models.InvitationPreview{
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		ExpiresAt: invitation.ExpiresAt,
+	}, nil
+}
+
+// AcceptInvitation creates the invited account, or gives an existing account
+// the invited role when req.Password matches it. Existing sessions of that
+// account are revoked since tokens carry the role.
+func (s *InvitationService) AcceptInvitation(ctx context.Context, req *models.AcceptInvitationRequest, ipAddress, userAgent string) (*models.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var user models.User
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		invitation, err := s.findValid(tx.Clauses(clause.Locking{Strength: "UPDATE"}), req.Token)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("email = ?", invitation.Email).First(&user).Error
+		switch {
+		case err == nil:
+			if !user.IsActive {
+				return fmt.Errorf("%w: the account for %s is deactivated", ErrInvalidInput, invitation.Email)
+			}
+			if !user.CheckPassword(req.Password) {
+				return fmt.Errorf("%w: password does not match the existing account for %s", ErrInvalidInput, invitation.Email)
+			}
+			user.Role, user.VendorID = invitation.Role, invitation.VendorID
+			if err := tx.Model(&user).Select("role", "vendor_id").Updates(&user).Error; err != nil {
+				return fmt.Errorf("%w: failed to update user: %v", ErrDatabaseQuery, err)
+			}
+			if err := tx.Model(&models.RefreshToken{}).
+				Where("user_id = ? AND is_revoked = ?", user.ID, false).
+				Update("is_revoked", true).Error; err != nil {
+				return fmt.Errorf("%w: failed to revoke sessions: %v", ErrDatabaseQuery, err)
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if !utils.IsValidPassword(req.Password) {
+				return fmt.Errorf("%w: password must be at least 8 characters", ErrInvalidInput)
+			}
+			user = models.User{
+				Email:       invitation.Email,
+				Password:    req.Password, // Will be hashed in BeforeCreate hook
+				FirstName:   utils.SanitizeString(req.FirstName),
+				LastName:    utils.SanitizeString(req.LastName),
+				PhoneNumber: models.EncryptedString(utils.SanitizeString(req.PhoneNumber)),
+				Role:        invitation.Role,
+				VendorID:    invitation.VendorID,
+				IsActive:    true,
+			}
+			if err := tx.Create(&user).Error; err != nil {
+				return fmt.Errorf("%w: failed to create user: %v", ErrDatabaseQuery, err)
+			}
+		default:
+			return fmt.Errorf("%w: failed to check existing users: %v", ErrDatabaseQuery, err)
+		}
+
+		now := time.Now()
+		if err := tx.Model(invitation).Updates(map[string]interface{}{
+			"status":      models.InvitationStatusAccepted,
+			"accepted_by": user.ID,
+			"accepted_at": now,
+		}).Error; err != nil {
+			return fmt.Errorf("%w: failed to update invitation: %v", ErrDatabaseQuery, err)
+		}
+
+		entry := models.AuditLog{
+			ActorID:       invitation.InvitedBy,
+			SubjectUserID: &user.ID,
+			Action:        models.AuditActionInvitationAccepted,
+			IPAddress:     ipAddress,
+			UserAgent:     userAgent,
+			Details:       fmt.Sprintf("invitation %d granted role %s", invitation.ID, invitation.Role),
+		}
+		if err := tx.Create(&entry).Error; err != nil {
+			return fmt.Errorf("%w: failed to write audit log: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(fmt.Sprintf("User %d accepted an invitation as %s", user.ID, user.Role))
+	return &user, nil
+}
+
+// findValid looks an invitation up by token, failing unless it is pending
+// and unexpired
+func (s *InvitationService) findValid(db *gorm.DB, token string) (*models.Invitation, error) {
+	var invitation models.Invitation
+	if err := db.Where("token_hash = ?", hashRefreshToken(strings.TrimSpace(token))).First(&invitation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: unknown invitation token", ErrInvitationNotFound)
+		}
+		return nil, fmt.Errorf("%w: failed to fetch invitation: %v", ErrDatabaseQuery, err)
+	}
+
+	markExpired(&invitation, time.Now())
+	if invitation.Status != models.InvitationStatusPending {
+		return nil, fmt.Errorf("%w: invitation is %s", ErrInvitationInvalid, invitation.Status)
+	}
+	return &invitation, nil
+}
+
+// markExpired reports pending invitations past their expiry as expired. The
+// stored status is left alone.
+func markExpired(invitation *models.Invitation, now time.Time) {
+	if invitation.Status == models.InvitationStatusPending && !invitation.ExpiresAt.After(now) {
+		invitation.Status = models.InvitationStatusExpired
+	}
+}
+
+func generateInvitationToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate invitation token: %v", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func inviterName(user *models.User) string {
+	if name := strings.TrimSpace(user.FirstName + " " + user.LastName); name != "" {
+		return name
+	}
+	return user.Email
+}
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+)
+
+var ErrJobNotFound = errors.New("job not found")
+
+const (
+	jobSubscriberBuffer = 64
+	jobUpdateTimeout    = 5 * time.Second
+)
+
+// JobMessage is pushed to connected admin consoles whenever a job logs a line,
+// reports progress or changes status.
+type JobMessage struct {
+	Kind  string         `json:"kind"` // log, progress or status
+	JobID uint           `json:"job_id"`
+	Job   *models.Job    `json:"job,omitempty"`
+	Log   *models.JobLog `json:"log,omitempty"`
+}
+
+// JobHub fans job messages out to live subscribers. A subscriber either
+// follows one job or, with jobID 0, every job.
+type JobHub struct {
+	mu          sync.RWMutex
+	subscribers map[chan JobMessage]uint
+}
+
+func NewJobHub() *JobHub {
+	return &JobHub{subscribers: make(map[chan JobMessage]uint)}
+}
+
+func (h *JobHub) Subscribe(jobID uint) (<-chan JobMessage, func()) {
+	ch := make(chan JobMessage, jobSubscriberBuffer)
+
+	h.mu.Lock()
+	h.subscribers[ch] = jobID
+	h.mu.Unlock()
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		if _, ok := h.subscribers[ch]; ok {
+			delete(h.subscribers, ch)
+			close(ch)
+		}
+		h.mu.Unlock()
+	}
+	return ch, unsubscribe
+}
+
+func (h *JobHub) Broadcast(msg JobMessage) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch, jobID := range h.subscribers {
+		if jobID != 0 && jobID != msg.JobID {
+			continue
+		}
+		// Drop messages for slow consumers instead of blocking the job;
+		// the full history stays available through the jobs API.
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
+
+type JobService struct {
+	db  *gorm.DB
+	hub *JobHub
+}
+
+func NewJobService(db *gorm.DB, hub *JobHub) *JobService {
+	return &JobService{db: db, hub: hub}
+}
+
+func (s *JobService) Hub() *JobHub {
+	return s.hub
+}
+
+// JobFunc performs the work of a job, reporting through the JobRun.
+type JobFunc func(ctx context.Context, run *JobRun) error
+
+// Enqueue records a new job and runs fn in the background.
+func (s *JobService) Enqueue(ctx context.Context, jobType string, createdBy uint, fn JobFunc) (*models.Job, error) {
+	job := &models.Job{
+		Type:      jobType,
+		Status:    models.JobStatusQueued,
+		CreatedBy: createdBy,
+	}
+	if err := s.db.WithContext(ctx).Create(job).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to create job: %v", ErrDatabaseQuery, err)
+	}
+
+	snapshot := *job
+	go s.run(&snapshot, fn)
+	return job, nil
+}
+
+func (s *JobService) run(job *models.Job, fn JobFunc) {
+	run := &JobRun{service: s, job: job}
+
+	defer func() {
+		if r := recover(); r != nil {
+			run.finish(fmt.Errorf("job panicked: %v", r))
+		}
+	}()
+
+	now := time.Now()
+	job.Status = models.JobStatusRunning
+	job.StartedAt = &now
+	run.save()
+	run.broadcastStatus()
+
+	run.finish(fn(context.Background(), run))
+}
+
+func (s *JobService) GetJobs(ctx context.Context, jobType string, page, limit int) ([]models.Job, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	query := s.db.WithContext(ctx).Model(&models.Job{})
+	if jobType != "" {
+		query = query.Where("type = ?", jobType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to count jobs: %v", ErrDatabaseQuery, err)
+	}
+
+	jobs := make([]models.Job, 0)
+	if err := query.Order("created_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&jobs).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to fetch jobs: %v", ErrDatabaseQuery, err)
+	}
+	return jobs, total, nil
+}
+
+func (s *JobService) GetJobByID(ctx context.Context, id uint) (*models.Job, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var job models.Job
+	if err := s.db.WithContext(ctx).First(&job, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrJobNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to fetch job: %v", ErrDatabaseQuery, err)
+	}
+	return &job, nil
+}
+
+// GetJobLogs returns a job's log lines in order, starting after afterID so
+// consoles can resume where their live stream left off.
+func (s *JobService) GetJobLogs(ctx context.Context, jobID uint, afterID uint, limit int) ([]models.JobLog, error) {
+	if _, err := s.GetJobByID(ctx, jobID); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	logs := make([]models.JobLog, 0)
+	if err := s.db.WithContext(ctx).
+		Where("job_id = ? AND id > ?", jobID, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&logs).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch job logs: %v", ErrDatabaseQuery, err)
+	}
+	return logs, nil
+}
+
+// JobRun is handed to a running JobFunc to report logs and progress.
+type JobRun struct {
+	service *JobService
+	job     *models.Job
+	mu      sync.Mutex
+}
+
+func (r *JobRun) JobID() uint {
+	return r.job.ID
+}
+
+func (r *JobRun) Infof(format string, args ...interface{}) {
+	r.log(models.JobLogInfo, fmt.Sprintf(format, args...))
+}
+
+func (r *JobRun) Warnf(format string, args ...interface{}) {
+	r.log(models.JobLogWarn, fmt.Sprintf(format, args...))
+}
+
+func (r *JobRun) Errorf(format string, args ...interface{}) {
+	r.log(models.JobLogError, fmt.Sprintf(format, args...))
+}
+
+func (r *JobRun) log(level, message string) {
+	entry := &models.JobLog{
+		JobID:   r.job.ID,
+		Level:   level,
+		Message: message,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), jobUpdateTimeout)
+	defer cancel()
+	if err := r.service.db.WithContext(ctx).Create(entry).Error; err != nil {
+		logger.Error("Failed to store job log for job ", r.job.ID, ": ", err)
+	}
+
+	r.service.hub.Broadcast(JobMessage{Kind: "log", JobID: r.job.ID, Log: entry})
+}
+
+// SetTotal declares how many units of work the job will process.
+func (r *JobRun) SetTotal(total int) {
+	r.mu.Lock()
+	r.job.Total = total
+	r.mu.Unlock()
+	r.save()
+}
+
+// Advance records processed (and failed) units and broadcasts progress.
+func (r *JobRun) Advance(processed, failed int) {
+	r.mu.Lock()
+	r.job.Processed += processed
+	r.job.Failed += failed
+	if r.job.Total > 0 {
+		r.job.Progress = (r.job.Processed + r.job.Failed) * 100 / r.job.Total
+		if r.job.Progress > 100 {
+			r.job.Progress = 100
+		}
+	}
+	snapshot := *r.job
+	r.mu.Unlock()
+
+	r.save()
+	r.service.hub.Broadcast(JobMessage{Kind: "progress", JobID: snapshot.ID, Job: &snapshot})
+}
+
+// SetProgress reports a percentage directly, for work that is not row based
+// such as an S3 upload.
+func (r *JobRun) SetProgress(percent int) {
+	r.mu.Lock()
+	r.job.Progress = percent
+	snapshot := *r.job
+	r.mu.Unlock()
+
+	r.save()
+	r.service.hub.Broadcast(JobMessage{Kind: "progress", JobID: snapshot.ID, Job: &snapshot})
+}
+
+func (r *JobRun) SetResult(result, resultURL string) {
+	r.mu.Lock()
+	r.job.Result = result
+	r.job.ResultURL = resultURL
+	r.mu.Unlock()
+}
+
+func (r *JobRun) finish(err error) {
+	now := time.Now()
+	r.mu.Lock()
+	r.job.FinishedAt = &now
+	if err != nil {
+		r.job.Status = models.JobStatusFailed
+		r.job.Error = err.Error()
+	} else {
+		r.job.Status = models.JobStatusSucceeded
+		r.job.Progress = 100
+	}
+	r.mu.Unlock()
+
+	if err != nil {
+		r.Errorf("Job failed: %v", err)
+	}
+	r.save()
+	r.broadcastStatus()
+}
+
+func (r *JobRun) broadcastStatus() {
+	r.mu.Lock()
+	snapshot := *r.job
+	r.mu.Unlock()
+	r.service.hub.Broadcast(JobMessage{Kind: "status", JobID: snapshot.ID, Job: &snapshot})
+}
+
+func (r *JobRun) save() {
+	r.mu.Lock()
+	snapshot := *r.job
+	r.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), jobUpdateTimeout)
+	defer cancel()
+	if err := r.service.db.WithContext(ctx).Save(&snapshot).Error; err != nil {
+		logger.Error("Failed to update job ", snapshot.ID, ": ", err)
+	}
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+)
+
+// APIVersion is reported to clients through /api/v1/meta. Bump the minor
+// version for additive changes so SDKs can feature-detect.
+const APIVersion = "1.1.0"
+
+// APIMeta describes what this deployment supports so client SDKs and the
+// frontend do not have to hardcode limits and enum values.
+type APIMeta struct {
+	APIVersion string              `json:"api_version"`
+	BasePath   string              `json:"base_path"`
+	Versions   []string            `json:"versions"`
+	Features   map[string]bool     `json:"features"`
+	Limits     APILimits           `json:"limits"`
+	Enums      map[string][]string `json:"enums"`
+	Categories []string            `json:"categories"`
+}
+
+type APILimits struct {
+	MaxImageUploadBytes int `json:"max_image_upload_bytes"`
+	DefaultPageSize     int `json:"default_page_size"`
+	MaxPageSize         int `json:"max_page_size"`
+	MaxImageDimension   int `json:"max_image_dimension"`
+	MaxTrendDays        int `json:"max_trend_days"`
+	RateLimitRPS        int `json:"rate_limit_rps"`
+	RateLimitBurst      int `json:"rate_limit_burst"`
+	MaxAvatarBytes      int `json:"max_avatar_bytes"`
+}
+
+type MetaService struct {
+	cfg            *config.Config
+	productService *ProductService
+}
+
+func NewMetaService(cfg *config.Config, productService *ProductService) *MetaService {
+	return &MetaService{cfg: cfg, productService: productService}
+}
+
+// GetMeta builds the capabilities document; categories are those of the
+// given store, or of every store when storeID is zero
+func (s *MetaService) GetMeta(ctx context.Context, storeID uint) *APIMeta {
+	categories, err := s.productService.GetCategories(ctx, storeID)
+	if err != nil {
+		// Categories are a convenience; the rest of the document is static
+		logger.Warn("Failed to load categories for API meta: ", err)
+		categories = []string{}
+	}
+
+	return &APIMeta{
+		APIVersion: APIVersion,
+		BasePath:   "/api/v1",
+		Versions:   []string{"v1", "v2"},
+		Features: map[string]bool{
+			"announcements":       true,
+			"webhooks":            true,
+			"image_proxy":         true,
+			"review_moderation":   true,
+			"stock_ledger":        true,
+			"product_import":      true,
+			"feed_import":         true,
+			"product_export":      s.cfg.S3BucketName != "",
+			"review_export":       s.cfg.S3BucketName != "",
+			"event_streaming":     !strings.EqualFold(s.cfg.EventBroker, "none") && s.cfg.EventBroker != "",
+			"email_validation":    s.cfg.AbstractEmailAPIKey != "",
+			"phone_validation":    s.cfg.AbstractPhoneNumberAPIKey != "",
+			"ai_image_extraction": s.cfg.FastAPIURL != "",
+			"phone_otp":           true,
+			"cookie_auth":         s.cfg.AuthCookieMode != "" && !strings.EqualFold(s.cfg.AuthCookieMode, "off"),
+			"feature_flags":       true,
+			"api_v2":              true,
+			"sparse_fieldsets":    true,
+			"compression":         s.cfg.CompressionEnabled,
+			"product_bundles":     true,
+			"admin_events":        true,
+			"product_updates":     true,
+			"product_suggest":     true,
+			"saved_searches":      true,
+			"vendors":             true,
+			"multi_tenant":        s.cfg.TenancyMode == "multi",
+			"impersonation":       true,
+			"avatars":             true,
+			"preferences":         true,
+			"collections":         true,
+			"banners":             true,
+			"product_reports":     true,
+			"admin_notes":         true,
+			"referrals":           true,
+			"fraud_screening":     s.cfg.FraudScreening != "off",
+			"geoip":               s.cfg.GeoIPProvider != "off" || s.cfg.GeoIPCountryHeader != "",
+			"admin_invitations":   s.cfg.SMTPUsername != "",
+			"catalog_sync":        true,
+		},
+		Limits: APILimits{
+			MaxImageUploadBytes: MaxImageSize,
+			DefaultPageSize:     DefaultPageSize,
+			MaxPageSize:         MaxPageSize,
+			MaxImageDimension:   s.cfg.ImageMaxDimension,
+			MaxTrendDays:        MaxTrendDays,
+			RateLimitRPS:        s.cfg.RateLimitRPS,
+			RateLimitBurst:      s.cfg.RateLimitBurst,
+			MaxAvatarBytes:      MaxAvatarFileBytes,
+		},
+		Enums: map[string][]string{
+			"product_status":        {models.ProductStatusActive, models.ProductStatusInactive, models.ProductStatusArchived, models.ProductStatusDraft},
+			"user_role":             {"admin", models.RoleVendor, "customer"},
+			"review_visibility":     {models.ReviewVisibilityPublished, models.ReviewVisibilityPending, models.ReviewVisibilityShadowHidden, models.ReviewVisibilityRemoved},
+			"stock_reason":          {models.StockReasonInitial, models.StockReasonRestock, models.StockReasonSale, models.StockReasonReturn, models.StockReasonDamage, models.StockReasonLoss, models.StockReasonCorrection},
+			"announcement_severity": {models.AnnouncementSeverityInfo, models.AnnouncementSeverityWarning, models.AnnouncementSeverityCritical},
+			"announcement_audience": {models.AnnouncementAudienceAll, models.AnnouncementAudienceCustomers, models.AnnouncementAudienceAdmins},
+			"webhook_event":         KnownEventTypes,
+			"image_format":          {"jpeg", "png"},
+			"image_fit":             {ImageFitCover, ImageFitContain},
+			"otp_purpose":           {models.OTPPurposeSignup, models.OTPPurposePasswordChange, models.OTPPurposeCheckout},
+			"product_expand":        {ExpandImages, ExpandServices, ExpandReviews},
+			"bundle_pricing":        {models.BundlePricingSumDiscount, models.BundlePricingFixed},
+			"suggestion_type":       {SuggestTypeTitle, SuggestTypeCategory, SuggestTypeMaterial},
+			"audit_action":          {models.AuditActionImpersonationStarted, models.AuditActionImpersonatedRequest},
+			"notification_event":    {models.NotificationSavedSearchMatch},
+			"notification_channel":  {models.ChannelEmail, models.ChannelSMS},
+			"banner_placement":      BannerPlacements,
+			"product_sort":          {ProductSortNewest, ProductSortRating, ProductSortPriceAsc, ProductSortPriceDesc},
+			"report_reason":         {models.ReportReasonCounterfeit, models.ReportReasonIncorrectInfo, models.ReportReasonProhibited, models.ReportReasonOffensive, models.ReportReasonOther},
+			"report_status":         {models.ReportStatusOpen, models.ReportStatusReviewing, models.ReportStatusResolved, models.ReportStatusDismissed},
+			"invitation_status":     {models.InvitationStatusPending, models.InvitationStatusAccepted, models.InvitationStatusRevoked, models.InvitationStatusExpired},
+			"product_availability":  {models.AvailabilityInStock, models.AvailabilityOutOfStock},
+			"out_of_stock_display":  {models.OutOfStockShow, models.OutOfStockHide},
+			"referral_flag":         {models.ReferralFlagSameIP, models.ReferralFlagSameDevice, models.ReferralFlagRepeatIP},
+			"risk_reason":           {models.RiskReasonDisposableEmail, models.RiskReasonIPVelocity, models.RiskReasonDeviceVelocity, models.RiskReasonCountryMismatch},
+			"fraud_review_status":   {models.FraudReviewPending, models.FraudReviewCleared, models.FraudReviewBlocked},
+		},
+		Categories: categories,
+	}
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrQuantityLimit is wrapped by QuantityLimitError
+var ErrQuantityLimit = fmt.Errorf("%w: quantity outside the product's order limits", ErrInvalidInput)
+
+// QuantityLimitError is returned when a purchase quantity is below a
+// product's minimum or above its maximum. It carries the limits so clients
+// can correct the quantity.
+type QuantityLimitError struct {
+	ProductID   uint `json:"product_id"`
+	Quantity    int  `json:"quantity"`
+	MinOrderQty int  `json:"min_order_qty,omitempty"`
+	MaxOrderQty int  `json:"max_order_qty,omitempty"`
+}
+
+func (e *QuantityLimitError) Error() string {
+	if e.MinOrderQty > 0 && e.Quantity < e.MinOrderQty {
+		return fmt.Sprintf("product %d must be ordered in quantities of at least %d, got %d", e.ProductID, e.MinOrderQty, e.Quantity)
+	}
+	return fmt.Sprintf("product %d can be ordered in quantities of at most %d, got %d", e.ProductID, e.MaxOrderQty, e.Quantity)
+}
+
+func (e *QuantityLimitError) Unwrap() error {
+	return ErrQuantityLimit
+}
+
+// checkOrderQuantity enforces the product's order limits on quantity.
+// product must have min_order_qty and max_order_qty loaded.
+func checkOrderQuantity(product *models.Product, quantity int) error {
+	if (product.MinOrderQty > 0 && quantity < product.MinOrderQty) ||
+		(product.MaxOrderQty > 0 && quantity > product.MaxOrderQty) {
+		return &QuantityLimitError{
+			ProductID:   product.ID,
+			Quantity:    quantity,
+			MinOrderQty: product.MinOrderQty,
+			MaxOrderQty: product.MaxOrderQty,
+		}
+	}
+	return nil
+}
+
+// validateOrderLimits checks a product's order limits before they are saved
+func validateOrderLimits(minQty, maxQty int) error {
+	if minQty < 0 || maxQty < 0 {
+		return fmt.Errorf("%w: order quantity limits cannot be negative", ErrInvalidInput)
+	}
+	if minQty > 0 && maxQty > 0 && minQty > maxQty {
+		return fmt.Errorf("%w: min_order_qty cannot exceed max_order_qty", ErrInvalidInput)
+	}
+	return nil
+}
+
+// checkOrderLimits loads productID's order limits and enforces them on
+// quantity
+func checkOrderLimits(db *gorm.DB, productID uint, quantity int) error {
+	var product models.Product
+	if err := db.Select("id", "min_order_qty", "max_order_qty").First(&product, productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: product with ID %d not found", ErrProductNotFound, productID)
+		}
+		return fmt.Errorf("%w: failed to find product: %v", ErrDatabaseQuery, err)
+	}
+	return checkOrderQuantity(&product, quantity)
+}
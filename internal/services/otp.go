@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOTPInvalid         = errors.New("invalid verification code")
+	ErrOTPExpired         = errors.New("verification code has expired")
+	ErrOTPTooManyAttempts = errors.New("too many incorrect attempts, request a new code")
+	ErrOTPRateLimited     = errors.New("too many verification codes requested")
+	ErrOTPNotVerified     = errors.New("phone number has not been verified")
+	ErrSMSDelivery        = errors.New("failed to send SMS")
+)
+
+const (
+	otpResendCooldown     = time.Minute
+	otpMaxSendsPerHour    = 5
+	otpVerificationWindow = 15 * time.Minute // how long a verified code can be used
+)
+
+type OTPService struct {
+	db           *gorm.DB
+	sender       SMSSender
+	usageService *APIUsageService
+	secret       []byte
+	ttl          time.Duration
+	maxAttempts  int
+	required     map[string]bool
+}
+
+func NewOTPService(db *gorm.DB, cfg *config.Config, sender SMSSender, usageService *APIUsageService) *OTPService {
+	required := make(map[string]bool, len(cfg.OTPRequiredFor))
+	for _, purpose := range cfg.OTPRequiredFor {
+		required[purpose] = true
+	}
+	return &OTPService{
+		db:           db,
+		sender:       sender,
+		usageService: usageService,
+		secret:       []byte(cfg.JWTSecret),
+		ttl:          cfg.OTPTTL,
+		maxAttempts:  cfg.OTPMaxAttempts,
+		required:     required,
+	}
+}
+
+// IsRequired reports whether the given purpose is configured to need a verified OTP
+func (s *OTPService) IsRequired(purpose string) bool {
+	return s != nil && s.required[purpose]
+}
+
+// SendCode generates a new code for phone and purpose, superseding any
+// earlier unverified code, and delivers it by SMS.
+func (s *OTPService) SendCode(ctx context.Context, phone, purpose, ip string) (*models.SendOTPResponse, error) {
+	phone = utils.NormalizePhone(phone)
+	if !utils.IsValidPhone(phone) {
+		return nil, fmt.Errorf("%w: invalid phone number", ErrInvalidInput)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	now := time.Now()
+	var recent []models.OTPCode
+	if err := s.db.WithContext(ctx).
+		Where("phone_hash = ? AND created_at > ?", models.BlindIndex(phone), now.Add(-time.Hour)).
+		Order("created_at DESC").
+		Find(&recent).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to check recent codes: %v", ErrDatabaseQuery, err)
+	}
+	if len(recent) >= otpMaxSendsPerHour {
+		return nil, ErrOTPRateLimited
+	}
+	for _, otp := range recent {
+		if otp.Purpose == purpose && now.Sub(otp.CreatedAt) < otpResendCooldown {
+			return nil, fmt.Errorf("%w: wait before requesting another code", ErrOTPRateLimited)
+		}
+	}
+
+	code, err := generateOTPCode()
+	if err != nil {
+		return nil, err
+	}
+
+	otp := &models.OTPCode{
+		Phone:       models.EncryptedString(phone),
+		Purpose:     purpose,
+		CodeHash:    s.hashCode(phone, purpose, code),
+		ExpiresAt:   now.Add(s.ttl),
+		RequestedIP: ip,
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.OTPCode{}).
+			Where("phone_hash = ? AND purpose = ? AND verified_at IS NULL AND consumed_at IS NULL", models.BlindIndex(phone), purpose).
+			Update("consumed_at", now).Error; err != nil {
+			return fmt.Errorf("%w: failed to supersede old codes: %v", ErrDatabaseQuery, err)
+		}
+		if err := tx.Create(otp).Error; err != nil {
+			return fmt.Errorf("%w: failed to store code: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	message := fmt.Sprintf("Your Sipfinity verification code is %s. It expires in %d minutes.", code, int(s.ttl.Minutes()))
+	sendErr := s.sender.SendSMS(ctx, phone, message)
+	s.usageService.Record(ExternalAPISMS, sendErr == nil, time.Since(start))
+	if sendErr != nil {
+		s.db.Delete(otp)
+		return nil, fmt.Errorf("%w: %v", ErrSMSDelivery, sendErr)
+	}
+
+	return &models.SendOTPResponse{
+		ExpiresAt:   otp.ExpiresAt,
+		ResendAfter: otp.CreatedAt.Add(otpResendCooldown),
+	}, nil
+}
+
+// VerifyCode checks a code against the latest one sent for phone and purpose
+func (s *OTPService) VerifyCode(ctx context.Context, phone, purpose, code string) error {
+	phone = utils.NormalizePhone(phone)
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var otp models.OTPCode
+	if err := s.db.WithContext(ctx).
+		Where("phone_hash = ? AND purpose = ? AND verified_at IS NULL AND consumed_at IS NULL", models.BlindIndex(phone), purpose).
+		Order("created_at DESC").
+		First(&otp).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrOTPInvalid
+		}
+		return fmt.Errorf("%w: failed to fetch code: %v", ErrDatabaseQuery, err)
+	}
+
+	if time.Now().After(otp.ExpiresAt) {
+		return ErrOTPExpired
+	}
+
+	// Count the attempt before comparing so parallel guesses can't exceed the limit
+	result := s.db.WithContext(ctx).Model(&otp).
+		Where("attempts < ?", s.maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		return fmt.Errorf("%w: failed to record attempt: %v", ErrDatabaseQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrOTPTooManyAttempts
+	}
+
+	if !hmac.Equal([]byte(otp.CodeHash), []byte(s.hashCode(phone, purpose, code))) {
+		return ErrOTPInvalid
+	}
+
+	if err := s.db.WithContext(ctx).Model(&otp).Update("verified_at", time.Now()).Error; err != nil {
+		return fmt.Errorf("%w: failed to mark code verified: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
+
+// ConsumeVerification uses up a recent successful verification. Flows such as
+// signup, password change or checkout call it right before they proceed.
+func (s *OTPService) ConsumeVerification(ctx context.Context, phone, purpose string) error {
+	phone = utils.NormalizePhone(phone)
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var otp models.OTPCode
+	if err := s.db.WithContext(ctx).
+		Where("phone_hash = ? AND purpose = ? AND verified_at > ? AND consumed_at IS NULL", models.BlindIndex(phone), purpose, time.Now().Add(-otpVerificationWindow)).
+		Order("verified_at DESC").
+		First(&otp).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrOTPNotVerified
+		}
+		return fmt.Errorf("%w: failed to fetch verification: %v", ErrDatabaseQuery, err)
+	}
+
+	result := s.db.WithContext(ctx).Model(&otp).
+		Where("consumed_at IS NULL").
+		Update("consumed_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("%w: failed to consume verification: %v", ErrDatabaseQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrOTPNotVerified
+	}
+	return nil
+}
+
+func (s *OTPService) hashCode(phone, purpose, code string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(phone + ":" + purpose + ":" + code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func generateOTPCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate code: %v", err)
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
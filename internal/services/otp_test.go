@@ -0,0 +1,85 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/testutil"
+)
+
+// capturedSMS keeps the last message sent to each number
+type capturedSMS map[string]string
+
+func (c capturedSMS) SendSMS(ctx context.Context, to, message string) error {
+	c[to] = message
+	return nil
+}
+
+var otpCodePattern = regexp.MustCompile(`\b\d{6}\b`)
+
+func TestOTPVerification(t *testing.T) {
+	env := testutil.NewEnv(t)
+	cfg := *env.Config
+	cfg.OTPTTL, cfg.OTPMaxAttempts = 5*time.Minute, 3
+	sms := capturedSMS{}
+	otp := services.NewOTPService(env.DB, &cfg, sms, nil)
+	purpose := models.OTPPurposeSignup
+
+	tests := []struct {
+		name         string
+		wrongGuesses int
+		expire       bool
+		verifyErr    error
+		consumeErrs  []error
+	}{
+		{"verified code is consumed once", 0, false, nil, []error{nil, services.ErrOTPNotVerified}},
+		{"wrong guesses below the limit", 2, false, nil, []error{nil}},
+		{"wrong guesses up to the limit", 3, false, services.ErrOTPTooManyAttempts, []error{services.ErrOTPNotVerified}},
+		{"expired code", 0, true, services.ErrOTPExpired, []error{services.ErrOTPNotVerified}},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			phone := fmt.Sprintf("+1555777%04d", i)
+			if _, err := otp.SendCode(context.Background(), phone, purpose, "127.0.0.1"); err != nil {
+				t.Fatalf("failed to send code: %v", err)
+			}
+			code := otpCodePattern.FindString(sms[phone])
+			if code == "" {
+				t.Fatalf("no code in %q", sms[phone])
+			}
+			if tt.expire {
+				if err := env.DB.Model(&models.OTPCode{}).Where("phone_hash = ?", models.BlindIndex(phone)).
+					Update("expires_at", time.Now().Add(-time.Minute)).Error; err != nil {
+					t.Fatalf("failed to expire code: %v", err)
+				}
+			}
+
+			// A wrong code that can't be the real one
+			wrong := "000000"
+			if code == wrong {
+				wrong = "111111"
+			}
+			for n := 0; n < tt.wrongGuesses; n++ {
+				if err := otp.VerifyCode(context.Background(), phone, purpose, wrong); !errors.Is(err, services.ErrOTPInvalid) {
+					t.Fatalf("guess %d: expected an invalid code, got %v", n+1, err)
+				}
+			}
+
+			if err := otp.VerifyCode(context.Background(), phone, purpose, code); !errors.Is(err, tt.verifyErr) {
+				t.Fatalf("expected verification error %v, got %v", tt.verifyErr, err)
+			}
+			for n, want := range tt.consumeErrs {
+				if err := otp.ConsumeVerification(context.Background(), phone, purpose); !errors.Is(err, want) {
+					t.Fatalf("consume %d: expected %v, got %v", n+1, want, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	outboxBatchSize      = 100
+	outboxPublishTimeout = 10 * time.Second
+)
+
+// recordOutboxEvent stores a domain event using the caller's transaction so
+// the event exists if and only if the business change commits.
+func recordOutboxEvent(tx *gorm.DB, eventType, aggregateType string, aggregateID interface{}, data interface{}) error {
+	event, err := newOutboxEvent(eventType, aggregateType, aggregateID, data)
+	if err != nil {
+		return err
+	}
+	if err := tx.Create(event).Error; err != nil {
+		return fmt.Errorf("%w: failed to record %s event: %v", ErrDatabaseQuery, eventType, err)
+	}
+	return nil
+}
+
+// newOutboxEvent builds an event without storing it, for callers that insert
+// many at once
+func newOutboxEvent(eventType, aggregateType string, aggregateID interface{}, data interface{}) (*models.OutboxEvent, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s event: %v", eventType, err)
+	}
+
+	return &models.OutboxEvent{
+		EventID:       uuid.New().String(),
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   fmt.Sprint(aggregateID),
+		Payload:       string(payload),
+	}, nil
+}
+
+// OutboxRelay publishes committed outbox events to the configured broker and
+// to the in-process event bus, marking them published once both succeed.
+type OutboxRelay struct {
+	db          *gorm.DB
+	publisher   EventPublisher
+	eventBus    *EventBus
+	topicPrefix string
+}
+
+func NewOutboxRelay(db *gorm.DB, publisher EventPublisher, eventBus *EventBus, topicPrefix string) *OutboxRelay {
+	return &OutboxRelay{
+		db:          db,
+		publisher:   publisher,
+		eventBus:    eventBus,
+		topicPrefix: topicPrefix,
+	}
+}
+
+func (r *OutboxRelay) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if r.publisher != nil {
+				r.publisher.Close()
+			}
+			return
+		case <-ticker.C:
+			if _, err := r.RelayBatch(ctx); err != nil {
+				logger.Error("Outbox relay failed: ", err)
+			}
+		}
+	}
+}
+
+// RelayBatch publishes up to one batch of pending events in order. Rows are
+// locked with SKIP LOCKED so several instances can relay concurrently.
+func (r *OutboxRelay) RelayBatch(ctx context.Context) (int, error) {
+	var relayed []Event
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var events []models.OutboxEvent
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL").
+			Order("id ASC").
+			Limit(outboxBatchSize).
+			Find(&events).Error; err != nil {
+			return fmt.Errorf("%w: failed to load outbox events: %v", ErrDatabaseQuery, err)
+		}
+
+		for i := range events {
+			event := &events[i]
+			if err := r.publish(ctx, event); err != nil {
+				// Stop at the first failure to preserve ordering; retry next tick
+				tx.Model(event).Updates(map[string]interface{}{
+					"attempts":   gorm.Expr("attempts + 1"),
+					"last_error": err.Error(),
+				})
+				return nil
+			}
+
+			now := time.Now()
+			if err := tx.Model(event).Update("published_at", now).Error; err != nil {
+				return fmt.Errorf("%w: failed to mark outbox event published: %v", ErrDatabaseQuery, err)
+			}
+			relayed = append(relayed, outboxToEvent(event))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// In-process subscribers (webhooks, ...) only see events once committed
+	for _, event := range relayed {
+		r.eventBus.PublishEvent(event)
+	}
+	return len(relayed), nil
+}
+
+func outboxToEvent(event *models.OutboxEvent) Event {
+	return Event{
+		ID:         event.EventID,
+		Type:       event.Type,
+		OccurredAt: event.CreatedAt.UTC(),
+		Data:       json.RawMessage(event.Payload),
+	}
+}
+
+func (r *OutboxRelay) publish(ctx context.Context, event *models.OutboxEvent) error {
+	if r.publisher == nil {
+		return nil
+	}
+
+	message, err := json.Marshal(outboxToEvent(event))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, outboxPublishTimeout)
+	defer cancel()
+
+	topic := r.topicPrefix + "." + event.AggregateType
+	return r.publisher.Publish(ctx, topic, event.AggregateID, message)
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// passwordChangeTokenTTL is how long a user with an expired password has to
+// change it after signing in
+const passwordChangeTokenTTL = 10 * time.Minute
+
+var ErrPasswordReused = errors.New("password was used recently")
+
+// PasswordPolicy adds rotation rules for privileged accounts (admins and
+// vendor staff). Zero values disable each rule.
+type PasswordPolicy struct {
+	HistorySize int           // previous passwords that cannot be reused
+	MaxAge      time.Duration // age after which the password must be changed
+}
+
+// PasswordExpiredError is returned by Login instead of a token pair. Token
+// only authorizes the change-password endpoint.
+type PasswordExpiredError struct {
+	Token     string `json:"password_change_token"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+func (e *PasswordExpiredError) Error() string {
+	return "password expired"
+}
+
+func (p PasswordPolicy) appliesTo(user *models.User) bool {
+	return user.Role == "admin" || user.Role == models.RoleVendor
+}
+
+// passwordExpired reports whether user has to change their password before
+// getting a session
+func (s *AuthService) passwordExpired(user *models.User) bool {
+	if s.passwordPolicy.MaxAge <= 0 || !s.passwordPolicy.appliesTo(user) {
+		return false
+	}
+	changedAt := user.CreatedAt
+	if user.PasswordChangedAt != nil {
+		changedAt = *user.PasswordChangedAt
+	}
+	return time.Since(changedAt) > s.passwordPolicy.MaxAge
+}
+
+func (s *AuthService) passwordExpiredError(user *models.User) error {
+	token, expiresAt, err := utils.GeneratePasswordChangeToken(user.ID, user.StoreID, user.Email, user.Role, passwordChangeTokenTTL, s.jwtSecret)
+	if err != nil {
+		return errors.New("failed to generate tokens")
+	}
+	return &PasswordExpiredError{Token: token, ExpiresAt: expiresAt.Unix()}
+}
+
+// checkPasswordReuse rejects the current password and, for users under the
+// policy, the last HistorySize passwords
+func (s *AuthService) checkPasswordReuse(user *models.User, newPassword string) error {
+	if !s.passwordPolicy.appliesTo(user) || s.passwordPolicy.HistorySize <= 0 {
+		return nil
+	}
+	if user.CheckPassword(newPassword) {
+		return fmt.Errorf("%w: choose a password you have not used before", ErrPasswordReused)
+	}
+
+	var hashes []string
+	if err := s.db.Model(&models.PasswordHistory{}).
+		Where("user_id = ?", user.ID).
+		Order("created_at DESC").
+		Limit(s.passwordPolicy.HistorySize).
+		Pluck("password_hash", &hashes).Error; err != nil {
+		return fmt.Errorf("%w: failed to load password history: %v", ErrDatabaseQuery, err)
+	}
+	for _, hash := range hashes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(newPassword)) == nil {
+			return fmt.Errorf("%w: choose a password you have not used in your last %d changes", ErrPasswordReused, s.passwordPolicy.HistorySize)
+		}
+	}
+	return nil
+}
+
+// recordPasswordChange stamps the change and, for users under the policy,
+// keeps oldHash in the history, trimmed to the last HistorySize entries. Call
+// it in the transaction that saves the new password.
+func (s *AuthService) recordPasswordChange(tx *gorm.DB, user *models.User, oldHash string) error {
+	now := time.Now()
+	user.PasswordChangedAt = &now
+	if err := tx.Model(user).Update("password_changed_at", now).Error; err != nil {
+		return fmt.Errorf("%w: failed to record password change: %v", ErrDatabaseQuery, err)
+	}
+
+	if !s.passwordPolicy.appliesTo(user) || s.passwordPolicy.HistorySize <= 0 {
+		return nil
+	}
+	if err := tx.Create(&models.PasswordHistory{UserID: user.ID, PasswordHash: oldHash}).Error; err != nil {
+		return fmt.Errorf("%w: failed to record password history: %v", ErrDatabaseQuery, err)
+	}
+
+	keep := tx.Model(&models.PasswordHistory{}).Select("id").
+		Where("user_id = ?", user.ID).
+		Order("created_at DESC").
+		Limit(s.passwordPolicy.HistorySize)
+	if err := tx.Where("user_id = ? AND id NOT IN (?)", user.ID, keep).Delete(&models.PasswordHistory{}).Error; err != nil {
+		return fmt.Errorf("%w: failed to trim password history: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
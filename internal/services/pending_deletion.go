@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"github.com/princeprakhar/ecommerce-backend/pkg/metrics"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	deletionBatchSize = 100
+	deletionMaxDelay  = time.Hour
+
+	// DeletionStuckAttempts is the number of failed attempts after which a
+	// pending deletion is reported as stuck; the worker keeps retrying it
+	DeletionStuckAttempts = 5
+)
+
+// Reasons recorded on pending deletions
+const (
+	DeletionReasonProductDeleted = "product_deleted"
+	DeletionReasonImageRemoved   = "image_removed"
+	DeletionReasonUploadAborted  = "upload_aborted"
+)
+
+var ErrPendingDeletionNotFound = errors.New("pending deletion not found")
+
+// queueS3Deletions records objects to delete once the caller's transaction
+// commits, so a crash after commit cannot leave them behind
+func queueS3Deletions(tx *gorm.DB, reason string, keys []string) error {
+	now := time.Now()
+	deletions := make([]models.PendingDeletion, 0, len(keys))
+	for _, key := range keys {
+		if key != "" {
+			deletions = append(deletions, models.PendingDeletion{S3Key: key, Reason: reason, NextAttemptAt: now})
+		}
+	}
+	if len(deletions) == 0 {
+		return nil
+	}
+	if err := tx.Create(&deletions).Error; err != nil {
+		return fmt.Errorf("%w: failed to queue S3 deletions: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
+
+// RunDeletions drains the pending deletion queue on every interval until the
+// context is cancelled
+func (s *StorageService) RunDeletions(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.DrainDeletions(ctx); err != nil {
+				logger.Error("Pending deletion worker failed: ", err)
+			}
+
+			var pending int64
+			if err := s.db.WithContext(ctx).Model(&models.PendingDeletion{}).Count(&pending).Error; err == nil {
+				metrics.PendingDeletions.Set(float64(pending))
+			}
+		}
+	}
+}
+
+// DrainDeletions deletes up to one batch of due objects. Rows are locked with
+// SKIP LOCKED so several instances can drain concurrently; a failed deletion
+// is retried with exponential backoff capped at deletionMaxDelay.
+func (s *StorageService) DrainDeletions(ctx context.Context) (int, error) {
+	deleted := 0
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var deletions []models.PendingDeletion
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("next_attempt_at <= ?", time.Now()).
+			Order("next_attempt_at ASC").
+			Limit(deletionBatchSize).
+			Find(&deletions).Error; err != nil {
+			return fmt.Errorf("%w: failed to load pending deletions: %v", ErrDatabaseQuery, err)
+		}
+
+		keys := make([]string, 0, len(deletions))
+		for _, deletion := range deletions {
+			keys = append(keys, deletion.S3Key)
+		}
+		referenced, err := referencedImageKeys(tx, keys)
+		if err != nil {
+			return err
+		}
+
+		for i := range deletions {
+			deletion := &deletions[i]
+			// A new upload of the same content took the object over; keep it
+			if referenced[deletion.S3Key] {
+				if err := tx.Delete(deletion).Error; err != nil {
+					return fmt.Errorf("%w: failed to remove pending deletion: %v", ErrDatabaseQuery, err)
+				}
+				continue
+			}
+			if err := s.s3Service.DeleteImage(deletion.S3Key); err != nil {
+				delay := time.Duration(1<<uint(min(deletion.Attempts, 12))) * time.Minute
+				if delay > deletionMaxDelay {
+					delay = deletionMaxDelay
+				}
+				if err := tx.Model(deletion).Updates(map[string]interface{}{
+					"attempts":        gorm.Expr("attempts + 1"),
+					"last_error":      err.Error(),
+					"next_attempt_at": time.Now().Add(delay),
+				}).Error; err != nil {
+					return fmt.Errorf("%w: failed to record deletion failure: %v", ErrDatabaseQuery, err)
+				}
+				continue
+			}
+
+			if err := tx.Delete(deletion).Error; err != nil {
+				return fmt.Errorf("%w: failed to remove pending deletion: %v", ErrDatabaseQuery, err)
+			}
+			deleted++
+		}
+		return nil
+	})
+	return deleted, err
+}
+
+// GetPendingDeletions lists queued deletions, oldest first. stuckOnly limits
+// the list to deletions that failed at least DeletionStuckAttempts times.
+func (s *StorageService) GetPendingDeletions(ctx context.Context, stuckOnly bool, page, limit int) ([]models.PendingDeletion, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	query := s.db.WithContext(ctx).Model(&models.PendingDeletion{})
+	if stuckOnly {
+		query = query.Where("attempts >= ?", DeletionStuckAttempts)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to count pending deletions: %v", ErrDatabaseQuery, err)
+	}
+
+	deletions := make([]models.PendingDeletion, 0)
+	if err := query.Order("id ASC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&deletions).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to fetch pending deletions: %v", ErrDatabaseQuery, err)
+	}
+	return deletions, total, nil
+}
+
+// RetryDeletion makes a pending deletion due immediately; the worker picks it
+// up on its next tick
+func (s *StorageService) RetryDeletion(ctx context.Context, id uint) (*models.PendingDeletion, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var deletion models.PendingDeletion
+	if err := s.db.WithContext(ctx).First(&deletion, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: pending deletion %d not found", ErrPendingDeletionNotFound, id)
+		}
+		return nil, fmt.Errorf("%w: failed to fetch pending deletion: %v", ErrDatabaseQuery, err)
+	}
+
+	if err := s.db.WithContext(ctx).Model(&deletion).Update("next_attempt_at", time.Now()).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to reschedule pending deletion: %v", ErrDatabaseQuery, err)
+	}
+	return &deletion, nil
+}
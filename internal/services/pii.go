@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// NewPIIKeyring builds the PII keyring from PII_ENCRYPTION_KEYS and
+// PII_BLIND_INDEX_KEY. With PII_KEYS_KMS=true both hold base64 KMS ciphertext
+// blobs, decrypted once here. It returns nil when no keys are configured.
+func NewPIIKeyring(cfg *config.Config) (*models.PIIKeyring, error) {
+	if len(cfg.PIIEncryptionKeys) == 0 && cfg.PIIBlindIndexKey == "" {
+		return nil, nil
+	}
+
+	decode := func(value string) ([]byte, error) {
+		return base64.StdEncoding.DecodeString(value)
+	}
+	if cfg.PIIKeysKMS {
+		// KMS shares the AWS credentials used for S3
+		sess, err := session.NewSession(&aws.Config{
+			Region:      aws.String(cfg.S3Region),
+			Credentials: credentials.NewStaticCredentials(cfg.S3AccessKey, cfg.S3SecretKey, ""),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create AWS session: %v", err)
+		}
+		client := kms.New(sess)
+		decode = func(value string) ([]byte, error) {
+			blob, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return nil, err
+			}
+			out, err := client.Decrypt(&kms.DecryptInput{CiphertextBlob: blob})
+			if err != nil {
+				return nil, fmt.Errorf("KMS decrypt failed: %v", err)
+			}
+			return out.Plaintext, nil
+		}
+	}
+
+	keys := make([]models.PIIKey, 0, len(cfg.PIIEncryptionKeys))
+	for _, entry := range cfg.PIIEncryptionKeys {
+		id, value, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("PII_ENCRYPTION_KEYS entry %q is not id:key", entry)
+		}
+		key, err := decode(value)
+		if err != nil {
+			return nil, fmt.Errorf("PII key %q: %v", id, err)
+		}
+		keys = append(keys, models.PIIKey{ID: id, Key: key})
+	}
+	blindKey, err := decode(cfg.PIIBlindIndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("PII_BLIND_INDEX_KEY: %v", err)
+	}
+
+	return models.NewPIIKeyring(keys, blindKey)
+}
+
+// piiColumn is an encrypted column, with the blind index kept next to it if
+// it has one
+type piiColumn struct {
+	table     string
+	column    string
+	hashIndex string
+}
+
+var piiColumns = []piiColumn{
+	{table: "users", column: "phone_number"},
+	{table: "otp_codes", column: "phone", hashIndex: "phone_hash"},
+	{table: "catalog_connectors", column: "access_token"},
+	{table: "catalog_connectors", column: "api_secret"},
+}
+
+type PIIService struct {
+	db *gorm.DB
+}
+
+func NewPIIService(db *gorm.DB) *PIIService {
+	return &PIIService{db: db}
+}
+
+// Reencrypt rewrites every PII value that is still plaintext or encrypted with
+// an older key, batchSize rows at a time, and returns the rows rewritten per
+// table. Run it after putting a new key first in PII_ENCRYPTION_KEYS; the old
+// key can be dropped once it reports nothing left to do.
+func (s *PIIService) Reencrypt(ctx context.Context, batchSize int) (map[string]int64, error) {
+	keyring := models.CurrentPIIKeyring()
+	if keyring == nil {
+		return nil, fmt.Errorf("%w: PII_ENCRYPTION_KEYS is not configured", ErrInvalidInput)
+	}
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	counts := make(map[string]int64)
+	for _, col := range piiColumns {
+		for {
+			var rows []struct {
+				ID    uint
+				Value models.EncryptedString
+			}
+			err := s.db.WithContext(ctx).Table(col.table).
+				Select("id, "+col.column+" AS value").
+				Where(col.column+" <> '' AND "+col.column+" NOT LIKE ?", keyring.CurrentPrefix()+"%").
+				Order("id").
+				Limit(batchSize).
+				Scan(&rows).Error
+			if err != nil {
+				return counts, fmt.Errorf("%w: failed to fetch %s.%s: %v", ErrDatabaseQuery, col.table, col.column, err)
+			}
+			if len(rows) == 0 {
+				break
+			}
+
+			err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+				for _, row := range rows {
+					updates := map[string]interface{}{col.column: row.Value}
+					if col.hashIndex != "" {
+						updates[col.hashIndex] = models.BlindIndex(string(row.Value))
+					}
+					if err := tx.Table(col.table).Where("id = ?", row.ID).UpdateColumns(updates).Error; err != nil {
+						return fmt.Errorf("%w: failed to re-encrypt %s #%d: %v", ErrDatabaseQuery, col.table, row.ID, err)
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return counts, err
+			}
+			counts[col.table] += int64(len(rows))
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,111 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/testutil"
+)
+
+func piiKeyring(t *testing.T, keys ...models.PIIKey) *models.PIIKeyring {
+	t.Helper()
+	keyring, err := models.NewPIIKeyring(keys, bytes.Repeat([]byte{9}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keyring
+}
+
+func TestReencrypt(t *testing.T) {
+	env := testutil.NewEnv(t)
+	t.Cleanup(func() { models.SetPIIKeyring(nil) })
+	pii := services.NewPIIService(env.DB)
+
+	oldKey := models.PIIKey{ID: "old", Key: bytes.Repeat([]byte{1}, 32)}
+	newKey := models.PIIKey{ID: "new", Key: bytes.Repeat([]byte{2}, 32)}
+	before := piiKeyring(t, oldKey)
+	rotated := piiKeyring(t, newKey, oldKey)
+
+	models.SetPIIKeyring(nil)
+	plaintext := env.CreateUser(t)
+	models.SetPIIKeyring(before)
+	encrypted := env.CreateUser(t)
+	otp := models.OTPCode{Phone: "+15550009999", Purpose: models.OTPPurposeSignup, CodeHash: "x", ExpiresAt: encrypted.CreatedAt}
+	if err := env.DB.Create(&otp).Error; err != nil {
+		t.Fatalf("failed to create OTP code: %v", err)
+	}
+
+	storedPhone := func(t *testing.T, table string, id uint, column string) string {
+		t.Helper()
+		var value string
+		if err := env.DB.Table(table).Where("id = ?", id).Pluck(column, &value).Error; err != nil {
+			t.Fatalf("failed to read %s.%s: %v", table, column, err)
+		}
+		return value
+	}
+
+	models.SetPIIKeyring(rotated)
+	counts, err := pii.Reencrypt(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("re-encryption failed: %v", err)
+	}
+	if counts["users"] < 2 || counts["otp_codes"] != 1 {
+		t.Fatalf("unexpected counts %v", counts)
+	}
+
+	tests := []struct {
+		name   string
+		table  string
+		id     uint
+		column string
+		want   string
+	}{
+		{"plaintext user", "users", plaintext.ID, "phone_number", string(plaintext.PhoneNumber)},
+		{"user under the old key", "users", encrypted.ID, "phone_number", string(encrypted.PhoneNumber)},
+		{"OTP code under the old key", "otp_codes", otp.ID, "phone", "+15550009999"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := storedPhone(t, tt.table, tt.id, tt.column)
+			if !strings.HasPrefix(stored, rotated.CurrentPrefix()) {
+				t.Fatalf("expected a value under the new key, got %q", stored)
+			}
+			var got models.EncryptedString
+			if err := got.Scan(stored); err != nil || string(got) != tt.want {
+				t.Fatalf("expected %q, got %q (%v)", tt.want, got, err)
+			}
+		})
+	}
+
+	t.Run("blind index is unchanged", func(t *testing.T) {
+		if hash := storedPhone(t, "otp_codes", otp.ID, "phone_hash"); hash != models.BlindIndex("+15550009999") {
+			t.Fatalf("blind index changed to %q", hash)
+		}
+	})
+
+	t.Run("nothing left to do", func(t *testing.T) {
+		counts, err := pii.Reencrypt(context.Background(), 100)
+		if err != nil {
+			t.Fatalf("re-encryption failed: %v", err)
+		}
+		for table, n := range counts {
+			if n != 0 {
+				t.Fatalf("expected nothing to re-encrypt, got %d rows of %s", n, table)
+			}
+		}
+	})
+
+	t.Run("old key dropped too early", func(t *testing.T) {
+		models.SetPIIKeyring(before)
+		env.CreateUser(t)
+		models.SetPIIKeyring(piiKeyring(t, newKey))
+		if _, err := pii.Reencrypt(context.Background(), 100); !errors.Is(err, services.ErrDatabaseQuery) || !strings.Contains(err.Error(), models.ErrUnknownPIIKey.Error()) {
+			t.Fatalf("expected an unknown key error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/geo"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PreferenceService stores user preferences and answers whether a
+// notification may be sent. Senders should call Allows before delivering
+// anything that is not a security message.
+type PreferenceService struct {
+	db *gorm.DB
+}
+
+func NewPreferenceService(db *gorm.DB) *PreferenceService {
+	return &PreferenceService{db: db}
+}
+
+// GetPreferences returns a user's preferences, or the defaults when they
+// have never saved any. The default currency is that of the country the
+// request comes from, when it is known.
+func (s *PreferenceService) GetPreferences(ctx context.Context, userID uint) (*models.UserPreference, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var pref models.UserPreference
+	err := s.db.WithContext(ctx).Where("user_id = ?", userID).First(&pref).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		defaults := defaultPreferences(userID)
+		if loc := geo.FromContext(ctx); loc != nil {
+			if currency := geo.CurrencyForCountry(loc.Country); currency != "" {
+				defaults.Currency = currency
+			}
+		}
+		return defaults, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch preferences: %v", ErrDatabaseQuery, err)
+	}
+	pref.Notifications = withDefaultNotifications(pref.Notifications)
+	return &pref, nil
+}
+
+func (s *PreferenceService) UpdatePreferences(ctx context.Context, userID uint, req *models.UpdatePreferencesRequest) (*models.UserPreference, error) {
+	pref, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.MarketingEmails != nil {
+		pref.MarketingEmails = *req.MarketingEmails
+	}
+	if req.Currency != nil {
+		pref.Currency = strings.ToUpper(strings.TrimSpace(*req.Currency))
+	}
+	if req.Locale != nil {
+		pref.Locale = strings.TrimSpace(*req.Locale)
+	}
+	for event, channels := range req.Notifications {
+		allowed, ok := models.NotificationChannels[event]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown notification event %q", ErrInvalidInput, event)
+		}
+		for _, channel := range channels {
+			if !containsString(allowed, channel) {
+				return nil, fmt.Errorf("%w: %s notifications cannot be sent by %s", ErrInvalidInput, event, channel)
+			}
+		}
+		pref.Notifications[event] = channels
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	// Upsert on user_id; Select keeps an explicit marketing_emails=false
+	err = s.db.WithContext(ctx).Select("*").Omit("id").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"marketing_emails", "currency", "locale", "notifications", "updated_at"}),
+		}).
+		Create(pref).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to save preferences: %v", ErrDatabaseQuery, err)
+	}
+	return pref, nil
+}
+
+// Allows reports whether a user wants an event delivered on a channel. A
+// failed lookup falls back to the defaults rather than dropping the message.
+func (s *PreferenceService) Allows(ctx context.Context, userID uint, event, channel string) bool {
+	pref, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		pref = defaultPreferences(userID)
+	}
+	return containsString(pref.Notifications[event], channel)
+}
+
+// AllowsMarketing reports whether a user opted in to marketing emails.
+// Unlike Allows it fails closed, since marketing needs explicit consent.
+func (s *PreferenceService) AllowsMarketing(ctx context.Context, userID uint) bool {
+	pref, err := s.GetPreferences(ctx, userID)
+	return err == nil && pref.MarketingEmails
+}
+
+func defaultPreferences(userID uint) *models.UserPreference {
+	return &models.UserPreference{
+		UserID:        userID,
+		Currency:      "USD",
+		Locale:        "en",
+		Notifications: models.DefaultNotificationSettings(),
+	}
+}
+
+// withDefaultNotifications fills in event types added after the user last
+// saved their preferences
+func withDefaultNotifications(settings map[string][]string) map[string][]string {
+	if settings == nil {
+		settings = make(map[string][]string)
+	}
+	for event, channels := range models.DefaultNotificationSettings() {
+		if _, ok := settings[event]; !ok {
+			settings[event] = channels
+		}
+	}
+	return settings
+}
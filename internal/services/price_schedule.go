@@ -0,0 +1,304 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrPriceChangeNotFound = errors.New("scheduled price change not found")
+	// ErrPriceChangeOverlap means a sale window would overlap another
+	// scheduled change of the same product
+	ErrPriceChangeOverlap = errors.New("scheduled price change overlaps another")
+)
+
+// PriceScheduleService manages scheduled price changes and sale windows, and
+// applies them as they come due
+type PriceScheduleService struct {
+	db *gorm.DB
+}
+
+func NewPriceScheduleService(db *gorm.DB) *PriceScheduleService {
+	return &PriceScheduleService{db: db}
+}
+
+// GetPriceChanges lists a product's scheduled price changes, soonest first
+func (s *PriceScheduleService) GetPriceChanges(ctx context.Context, productID uint) ([]models.ScheduledPriceChange, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	db := s.db.WithContext(ctx)
+	if err := findProductForPriceChange(db, productID); err != nil {
+		return nil, err
+	}
+
+	changes := []models.ScheduledPriceChange{}
+	if err := db.Where("product_id = ?", productID).Order("starts_at ASC, id ASC").Find(&changes).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch price changes: %v", ErrDatabaseQuery, err)
+	}
+	return changes, nil
+}
+
+// SchedulePriceChange schedules a new price for a product. Sale windows
+// cannot overlap each other, and a permanent change cannot fall inside one,
+// since ending the sale would undo it. A start in the past applies on the
+// scheduler's next run.
+func (s *PriceScheduleService) SchedulePriceChange(ctx context.Context, actorID, productID uint, req *models.SchedulePriceChangeRequest) (*models.ScheduledPriceChange, error) {
+	if req.EndsAt != nil {
+		if !req.EndsAt.After(req.StartsAt) {
+			return nil, fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidInput)
+		}
+		if !req.EndsAt.After(time.Now()) {
+			return nil, fmt.Errorf("%w: ends_at must be in the future", ErrInvalidInput)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	change := models.ScheduledPriceChange{
+		ProductID: productID,
+		Price:     req.Price,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+		Status:    models.PriceChangePending,
+		CreatedBy: actorID,
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The product row lock serialises scheduling for the product
+		if err := findProductForPriceChange(tx.Clauses(clause.Locking{Strength: "UPDATE"}), productID); err != nil {
+			return err
+		}
+
+		overlapping := tx.Model(&models.ScheduledPriceChange{}).
+			Where("product_id = ? AND status IN ?", productID, []string{models.PriceChangePending, models.PriceChangeActive})
+		if change.IsSale() {
+			overlapping = overlapping.Where(
+				"(ends_at IS NOT NULL AND starts_at < ? AND ends_at > ?) OR (ends_at IS NULL AND starts_at >= ? AND starts_at < ?)",
+				*change.EndsAt, change.StartsAt, change.StartsAt, *change.EndsAt)
+		} else {
+			overlapping = overlapping.Where("ends_at IS NOT NULL AND starts_at <= ? AND ends_at > ?", change.StartsAt, change.StartsAt)
+		}
+		var count int64
+		if err := overlapping.Count(&count).Error; err != nil {
+			return fmt.Errorf("%w: failed to check price changes: %v", ErrDatabaseQuery, err)
+		}
+		if count > 0 {
+			return ErrPriceChangeOverlap
+		}
+
+		if err := tx.Create(&change).Error; err != nil {
+			return fmt.Errorf("%w: failed to schedule price change: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+// CancelPriceChange cancels a pending change, or ends a running sale early
+// and restores the product's price
+func (s *PriceScheduleService) CancelPriceChange(ctx context.Context, productID, changeID uint) (*models.ScheduledPriceChange, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var change models.ScheduledPriceChange
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND product_id = ?", changeID, productID).
+			First(&change).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: %d on product %d", ErrPriceChangeNotFound, changeID, productID)
+			}
+			return fmt.Errorf("%w: failed to find price change: %v", ErrDatabaseQuery, err)
+		}
+
+		switch change.Status {
+		case models.PriceChangePending:
+			return finishPriceChange(tx, &change, models.PriceChangeCancelled)
+		case models.PriceChangeActive:
+			if err := endSale(tx, &change); err != nil {
+				return err
+			}
+			return finishPriceChange(tx, &change, models.PriceChangeCancelled)
+		default:
+			return fmt.Errorf("%w: price change is already %s", ErrInvalidInput, change.Status)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+// Run applies due price changes every interval until ctx is cancelled
+func (s *PriceScheduleService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.ApplyDue(ctx); err != nil {
+				logger.Error("Failed to apply scheduled price changes: ", err)
+			}
+		}
+	}
+}
+
+// ApplyDue ends sale windows that are over, then starts changes that are due.
+// Ending first lets back-to-back windows hand over cleanly.
+func (s *PriceScheduleService) ApplyDue(ctx context.Context) error {
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	now := time.Now()
+	var ending []uint
+	if err := s.db.WithContext(queryCtx).Model(&models.ScheduledPriceChange{}).
+		Where("status = ? AND ends_at <= ?", models.PriceChangeActive, now).
+		Order("ends_at ASC").
+		Pluck("id", &ending).Error; err != nil {
+		return fmt.Errorf("%w: failed to find ending sales: %v", ErrDatabaseQuery, err)
+	}
+	for _, id := range ending {
+		if err := s.step(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	var due []uint
+	if err := s.db.WithContext(queryCtx).Model(&models.ScheduledPriceChange{}).
+		Where("status = ? AND starts_at <= ?", models.PriceChangePending, now).
+		Order("starts_at ASC").
+		Pluck("id", &due).Error; err != nil {
+		return fmt.Errorf("%w: failed to find due price changes: %v", ErrDatabaseQuery, err)
+	}
+	for _, id := range due {
+		if err := s.step(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// step moves one change forward: a due pending change is applied and a
+// finished sale is ended. The change is re-read under lock, so a concurrent
+// cancellation wins.
+func (s *PriceScheduleService) step(ctx context.Context, id uint) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var change models.ScheduledPriceChange
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&change, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return fmt.Errorf("%w: failed to find price change: %v", ErrDatabaseQuery, err)
+		}
+
+		now := time.Now()
+		switch {
+		case change.Status == models.PriceChangeActive && !change.EndsAt.After(now):
+			if err := endSale(tx, &change); err != nil {
+				return err
+			}
+			return finishPriceChange(tx, &change, models.PriceChangeCompleted)
+		case change.Status == models.PriceChangePending && !change.StartsAt.After(now):
+			if change.IsSale() && !change.EndsAt.After(now) {
+				// The whole window passed while the scheduler was down
+				return finishPriceChange(tx, &change, models.PriceChangeCompleted)
+			}
+			return applyPriceChange(tx, &change)
+		default:
+			return nil
+		}
+	})
+}
+
+// applyPriceChange sets the product's new price. A sale that lowers the price
+// shows the old one as the compare-at price.
+func applyPriceChange(tx *gorm.DB, change *models.ScheduledPriceChange) error {
+	var product models.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "price").First(&product, change.ProductID).Error; err != nil {
+		return fmt.Errorf("%w: failed to find product: %v", ErrDatabaseQuery, err)
+	}
+
+	var compareAt *float64
+	if change.IsSale() && change.Price < product.Price {
+		compareAt = &product.Price
+	}
+	if err := tx.Model(&product).Updates(map[string]interface{}{
+		"price":            change.Price,
+		"compare_at_price": compareAt,
+		"updated_at":       time.Now(),
+	}).Error; err != nil {
+		return fmt.Errorf("%w: failed to update price: %v", ErrDatabaseQuery, err)
+	}
+
+	now := time.Now()
+	change.OriginalPrice = &product.Price
+	change.AppliedAt = &now
+	change.Status = models.PriceChangeCompleted
+	if change.IsSale() {
+		change.Status = models.PriceChangeActive
+	}
+	if err := tx.Model(change).Updates(map[string]interface{}{
+		"status":         change.Status,
+		"original_price": change.OriginalPrice,
+		"applied_at":     change.AppliedAt,
+	}).Error; err != nil {
+		return fmt.Errorf("%w: failed to update price change: %v", ErrDatabaseQuery, err)
+	}
+	return publishProductUpdated(tx, change.ProductID)
+}
+
+// endSale restores the price a sale replaced and clears the compare-at price
+func endSale(tx *gorm.DB, change *models.ScheduledPriceChange) error {
+	updates := map[string]interface{}{
+		"compare_at_price": nil,
+		"updated_at":       time.Now(),
+	}
+	if change.OriginalPrice != nil {
+		updates["price"] = *change.OriginalPrice
+	}
+	if err := tx.Model(&models.Product{}).Where("id = ?", change.ProductID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("%w: failed to restore price: %v", ErrDatabaseQuery, err)
+	}
+	return publishProductUpdated(tx, change.ProductID)
+}
+
+func finishPriceChange(tx *gorm.DB, change *models.ScheduledPriceChange, status string) error {
+	now := time.Now()
+	change.Status = status
+	change.EndedAt = &now
+	if err := tx.Model(change).Updates(map[string]interface{}{"status": status, "ended_at": now}).Error; err != nil {
+		return fmt.Errorf("%w: failed to update price change: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
+
+func findProductForPriceChange(db *gorm.DB, productID uint) error {
+	if err := db.Select("id").First(&models.Product{}, productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: product with ID %d not found", ErrProductNotFound, productID)
+		}
+		return fmt.Errorf("%w: failed to find product: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
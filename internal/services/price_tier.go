@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPriceTierNotFound = errors.New("price tier not found")
+	ErrPriceTierExists   = errors.New("a price tier for this quantity already exists")
+)
+
+// GetPriceTiers lists a product's bulk price tiers by quantity
+func (s *AdminService) GetPriceTiers(ctx context.Context, productID uint) ([]models.PriceTier, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	db := s.db.WithContext(ctx)
+	if err := db.Select("id").First(&models.Product{}, productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: product with ID %d not found", ErrProductNotFound, productID)
+		}
+		return nil, fmt.Errorf("%w: failed to find product: %v", ErrDatabaseQuery, err)
+	}
+	return loadPriceTiers(db, productID)
+}
+
+// AddPriceTier adds a bulk price for buying at least MinQuantity
+func (s *AdminService) AddPriceTier(ctx context.Context, productID uint, req *models.CreatePriceTierRequest) (*models.PriceTier, error) {
+	tier := models.PriceTier{
+		ProductID:   productID,
+		MinQuantity: req.MinQuantity,
+		UnitPrice:   req.UnitPrice,
+	}
+	err := s.changeProduct(ctx, productID, func(tx *gorm.DB) error {
+		if err := tx.Create(&tier).Error; err != nil {
+			if isUniqueViolation(err) {
+				return fmt.Errorf("%w: min_quantity %d", ErrPriceTierExists, tier.MinQuantity)
+			}
+			return fmt.Errorf("%w: failed to create price tier: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tier, nil
+}
+
+// UpdatePriceTier changes the quantity and/or unit price of a tier
+func (s *AdminService) UpdatePriceTier(ctx context.Context, productID, tierID uint, req *models.UpdatePriceTierRequest) (*models.PriceTier, error) {
+	var tier models.PriceTier
+	err := s.changeProduct(ctx, productID, func(tx *gorm.DB) error {
+		if err := findPriceTier(tx, productID, tierID, &tier); err != nil {
+			return err
+		}
+		if req.MinQuantity != nil {
+			tier.MinQuantity = *req.MinQuantity
+		}
+		if req.UnitPrice != nil {
+			tier.UnitPrice = *req.UnitPrice
+		}
+
+		if err := tx.Model(&tier).Updates(map[string]interface{}{
+			"min_quantity": tier.MinQuantity,
+			"unit_price":   tier.UnitPrice,
+		}).Error; err != nil {
+			if isUniqueViolation(err) {
+				return fmt.Errorf("%w: min_quantity %d", ErrPriceTierExists, tier.MinQuantity)
+			}
+			return fmt.Errorf("%w: failed to update price tier: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tier, nil
+}
+
+// DeletePriceTier removes a tier from a product
+func (s *AdminService) DeletePriceTier(ctx context.Context, productID, tierID uint) error {
+	return s.changeProduct(ctx, productID, func(tx *gorm.DB) error {
+		var tier models.PriceTier
+		if err := findPriceTier(tx, productID, tierID, &tier); err != nil {
+			return err
+		}
+		if err := tx.Delete(&tier).Error; err != nil {
+			return fmt.Errorf("%w: failed to delete price tier: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+}
+
+// QuotePrice prices quantity units of an active product for userID (0 for
+// guests), starting from their customer group's price and applying the best
+// tier the quantity reaches. This is the calculation carts and checkout use.
+// Quantities outside the product's order limits fail with a
+// QuantityLimitError.
+func (s *ProductService) QuotePrice(ctx context.Context, userID, productID uint, quantity int) (*models.PriceQuote, error) {
+	if quantity < 1 {
+		return nil, fmt.Errorf("%w: quantity must be at least 1", ErrInvalidInput)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	db := s.db.WithContext(ctx)
+	var product models.Product
+	if err := db.Select("id", "price", "compare_at_price", "min_order_qty", "max_order_qty").Where("id = ? AND status = ?", productID, models.ProductStatusActive).First(&product).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: product with ID %d not found", ErrProductNotFound, productID)
+		}
+		return nil, fmt.Errorf("%w: failed to find product: %v", ErrDatabaseQuery, err)
+	}
+	if err := checkOrderQuantity(&product, quantity); err != nil {
+		return nil, err
+	}
+	if userID != 0 {
+		rules, err := loadGroupPrices(db, userID, []models.Product{product})
+		if err != nil {
+			return nil, err
+		}
+		applyGroupPrice(&product, rules)
+	}
+	tiers, err := loadPriceTiers(db, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	unitPrice, tier := tierUnitPrice(product.Price, tiers, quantity)
+	return &models.PriceQuote{
+		ProductID: productID,
+		Quantity:  quantity,
+		UnitPrice: unitPrice,
+		Total:     math.Round(unitPrice*float64(quantity)*100) / 100,
+		Tier:      tier,
+	}, nil
+}
+
+// tierUnitPrice returns the unit price for quantity: that of the highest tier
+// the quantity reaches, unless the base price is lower, e.g. during a sale.
+// tiers must be sorted by MinQuantity.
+func tierUnitPrice(basePrice float64, tiers []models.PriceTier, quantity int) (float64, *models.PriceTier) {
+	for i := len(tiers) - 1; i >= 0; i-- {
+		if tiers[i].MinQuantity <= quantity {
+			if tiers[i].UnitPrice < basePrice {
+				return tiers[i].UnitPrice, &tiers[i]
+			}
+			break
+		}
+	}
+	return basePrice, nil
+}
+
+func loadPriceTiers(db *gorm.DB, productID uint) ([]models.PriceTier, error) {
+	tiers := []models.PriceTier{}
+	if err := db.Where("product_id = ?", productID).Order("min_quantity ASC").Find(&tiers).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch price tiers: %v", ErrDatabaseQuery, err)
+	}
+	return tiers, nil
+}
+
+func findPriceTier(tx *gorm.DB, productID, tierID uint, tier *models.PriceTier) error {
+	if err := tx.Where("id = ? AND product_id = ?", tierID, productID).First(tier).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: tier %d not found on product %d", ErrPriceTierNotFound, tierID, productID)
+		}
+		return fmt.Errorf("%w: failed to find price tier: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
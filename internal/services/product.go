@@ -7,7 +7,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/database"
 	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/types"
 	"gorm.io/gorm"
 )
 
@@ -17,6 +20,22 @@ const (
 	QueryTimeout    = 30 * time.Second
 )
 
+// Sort orders accepted by ProductFilter.Sort
+const (
+	ProductSortNewest    = "newest"
+	ProductSortRating    = "rating" // weighted rating score, best first
+	ProductSortPriceAsc  = "price_asc"
+	ProductSortPriceDesc = "price_desc"
+)
+
+// productSortOrders maps each sort to its ORDER BY; ties fall back to newest
+var productSortOrders = map[string]string{
+	ProductSortNewest:    "created_at DESC",
+	ProductSortRating:    "rating_score DESC, rating_count DESC, created_at DESC",
+	ProductSortPriceAsc:  "price ASC, created_at DESC",
+	ProductSortPriceDesc: "price DESC, created_at DESC",
+}
+
 var (
 	ErrProductNotFound = errors.New("product not found")
 	ErrInvalidFilter   = errors.New("invalid filter parameters")
@@ -24,16 +43,30 @@ var (
 )
 
 type ProductService struct {
-	db *gorm.DB
+	db             *gorm.DB
+	counts         *productCountCache
+	estimateAbove  int64
+	hideOutOfStock bool
 }
 
-func NewProductService(db *gorm.DB) *ProductService {
+// NewProductService caches list totals for PRODUCT_COUNT_CACHE_TTL, clearing
+// them on product events from eventBus. Lists the planner expects to be
+// larger than PRODUCT_COUNT_ESTIMATE_ABOVE report an estimated total; 0
+// always counts exactly.
+func NewProductService(db *gorm.DB, cfg *config.Config, eventBus *EventBus) *ProductService {
 	if db == nil {
 		panic("database connection cannot be nil")
 	}
-	return &ProductService{
-		db: db,
+	s := &ProductService{
+		db:             db,
+		counts:         newProductCountCache(cfg.ProductCountCacheTTL),
+		estimateAbove:  cfg.ProductCountEstimateAbove,
+		hideOutOfStock: cfg.OutOfStockDisplay == models.OutOfStockHide,
+	}
+	if eventBus != nil {
+		eventBus.Subscribe(s.counts.handleEvent)
 	}
+	return s
 }
 
 type ProductFilter struct {
@@ -43,16 +76,20 @@ type ProductFilter struct {
 	MinPrice float64 `form:"min_price" validate:"min=0"`
 	MaxPrice float64 `form:"max_price" validate:"min=0"`
 	Search   string  `form:"search" validate:"max=255"`
+	Sort     string  `form:"sort" validate:"omitempty,oneof=newest rating price_asc price_desc"`
+	// MinReviews hides products with fewer published reviews
+	MinReviews int `form:"min_reviews" validate:"min=0"`
 	Page     int     `form:"page" validate:"min=1"`
 	Limit    int     `form:"limit" validate:"min=1,max=100"`
-}
-
-type ProductResponse struct {
-	Products []models.Product `json:"products"`
-	Total    int64            `json:"total"`
-	Page     int              `json:"page"`
-	Limit    int              `json:"limit"`
-	Pages    int              `json:"pages"`
+	// ExactCount always runs COUNT(*) instead of reporting an estimated total
+	ExactCount bool `form:"exact_count"`
+	// InStock leaves out products that cannot be bought now
+	InStock bool `form:"in_stock"`
+
+	// Projection limits the loaded columns and relations; nil loads images and services
+	Projection *ProductProjection `form:"-"`
+	// StoreID limits results to one store in multi-tenant mode
+	StoreID uint `form:"-"`
 }
 
 type ProductRequest struct {
@@ -98,11 +135,21 @@ func (f *ProductFilter) ValidateAndNormalize() error {
 		return fmt.Errorf("%w: search term too long", ErrInvalidFilter)
 	}
 
+	if f.Sort == "" {
+		f.Sort = ProductSortNewest
+	}
+	if _, ok := productSortOrders[f.Sort]; !ok {
+		return fmt.Errorf("%w: unknown sort %q", ErrInvalidFilter, f.Sort)
+	}
+	if f.MinReviews < 0 {
+		return fmt.Errorf("%w: min_reviews cannot be negative", ErrInvalidFilter)
+	}
+
 	return nil
 }
 
 // GetProducts retrieves products with filtering and pagination (public access - active products only)
-func (s *ProductService) GetProducts(ctx context.Context, filter ProductFilter) (*ProductResponse, error) {
+func (s *ProductService) GetProducts(ctx context.Context, filter ProductFilter) (*types.Paginated[models.Product], error) {
 	// Validate and normalize filter
 	if err := filter.ValidateAndNormalize(); err != nil {
 		return nil, err
@@ -113,62 +160,53 @@ func (s *ProductService) GetProducts(ctx context.Context, filter ProductFilter)
 	defer cancel()
 
 	var products []models.Product
-	var total int64
-
-	// Build base query - only active products for public access
-	query := s.db.WithContext(ctx).Model(&models.Product{}).Where("status = ?", "active")
 
-	// Apply filters
-	query = s.applyFilters(query, filter)
+	query := s.listQuery(database.Replica(s.db).WithContext(ctx), filter)
 
-	// Count total records first (more efficient)
-	if err := query.Count(&total).Error; err != nil {
-		return nil, fmt.Errorf("%w: failed to count products: %v", ErrDatabaseQuery, err)
+	// Count total records first (cached, or estimated for large lists)
+	total, exact, err := s.countProducts(ctx, query, filter)
+	if err != nil {
+		return nil, err
 	}
 
 	// Early return if no products found
 	if total == 0 {
-		return &ProductResponse{
-			Products: []models.Product{},
-			Total:    0,
-			Page:     filter.Page,
-			Limit:    filter.Limit,
-			Pages:    0,
-		}, nil
+		response := types.NewPaginated("products", []models.Product{}, filter.Page, filter.Limit, 0)
+		return &response, nil
 	}
 
 	// Apply pagination and ordering
 	offset := (filter.Page - 1) * filter.Limit
+	if columns := filter.Projection.columns(); columns != nil {
+		query = query.Select(columns)
+	}
 	if err := query.
 		Offset(offset).
 		Limit(filter.Limit).
-		Order("created_at DESC").
+		Order(productSortOrders[filter.Sort]).
 		Find(&products).Error; err != nil {
 		return nil, fmt.Errorf("%w: failed to fetch products: %v", ErrDatabaseQuery, err)
 	}
 
 	// Load related data efficiently
-	if err := s.loadProductRelations(ctx, products); err != nil {
+	if err := s.loadProductRelations(ctx, products, filter.Projection); err != nil {
 		return nil, fmt.Errorf("failed to load product relations: %v", err)
 	}
-
-	// Calculate total pages
-	pages := int(total) / filter.Limit
-	if int(total)%filter.Limit > 0 {
-		pages++
+	if err := s.setAvailability(ctx, products, filter.Projection); err != nil {
+		return nil, err
 	}
 
-	return &ProductResponse{
-		Products: products,
-		Total:    total,
-		Page:     filter.Page,
-		Limit:    filter.Limit,
-		Pages:    pages,
-	}, nil
+	response := types.NewPaginated("products", products, filter.Page, filter.Limit, total)
+	if !exact {
+		// An estimate can be off either way, so only a full page promises more
+		response.Pagination.TotalExact = false
+		response.Pagination.HasNext = len(products) == filter.Limit
+	}
+	return &response, nil
 }
 
 // GetProductByID retrieves a single product by ID (public access - active products only)
-func (s *ProductService) GetProductByID(ctx context.Context, id uint) (*models.Product, error) {
+func (s *ProductService) GetProductByID(ctx context.Context, id uint, proj *ProductProjection) (*models.Product, error) {
 	if id == 0 {
 		return nil, fmt.Errorf("%w: invalid product ID", ErrInvalidFilter)
 	}
@@ -178,8 +216,12 @@ func (s *ProductService) GetProductByID(ctx context.Context, id uint) (*models.P
 	defer cancel()
 
 	var product models.Product
-	
-	if err := s.db.WithContext(ctx).
+
+	query := s.db.WithContext(ctx)
+	if columns := proj.columns(); columns != nil {
+		query = query.Select(columns)
+	}
+	if err := query.
 		Where("id = ? AND status = ?", id, "active").
 		First(&product).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -189,11 +231,39 @@ func (s *ProductService) GetProductByID(ctx context.Context, id uint) (*models.P
 	}
 
 	// Load related data
-	if err := s.loadProductRelations(ctx, []models.Product{product}); err != nil {
+	products := []models.Product{product}
+	if err := s.loadProductRelations(ctx, products, proj); err != nil {
 		return nil, fmt.Errorf("failed to load product relations: %v", err)
 	}
 
-	return &product, nil
+	// Bundles list their contents, price and availability
+	bundle, err := loadBundle(s.db.WithContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+	products[0].Bundle = bundle
+
+	// Bulk discounts, so the page can show prices by quantity
+	tiers, err := loadPriceTiers(s.db.WithContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+	products[0].PriceTiers = tiers
+
+	// Products without stock stay viewable, marked out of stock
+	if err := s.setAvailability(ctx, products, proj); err != nil {
+		return nil, err
+	}
+
+	return &products[0], nil
+}
+
+// listQuery selects the active products of the filter's store that match
+// it, before pagination and ordering
+func (s *ProductService) listQuery(db *gorm.DB, filter ProductFilter) *gorm.DB {
+	// Build base query - only active products for public access
+	query := db.Model(&models.Product{}).Where("status = ?", "active")
+	return s.applyFilters(query, filter).Scopes(storeScope(filter.StoreID))
 }
 
 // applyFilters applies search filters to the query
@@ -217,18 +287,28 @@ func (s *ProductService) applyFilters(query *gorm.DB, filter ProductFilter) *gor
 	if filter.Search != "" {
 		searchTerm := "%" + strings.ToLower(filter.Search) + "%"
 		query = query.Where(
-			"LOWER(name) LIKE ? OR LOWER(description) LIKE ? OR LOWER(brand) LIKE ?",
-			searchTerm, searchTerm, searchTerm,
+			"LOWER(title) LIKE ? OR LOWER(description) LIKE ?",
+			searchTerm, searchTerm,
 		)
 	}
 
-	return query
+	if filter.MinReviews > 0 {
+		query = query.Where("rating_count >= ?", filter.MinReviews)
+	}
+
+	return query.Scopes(s.stockScope(filter.InStock))
 }
 
-func (s *ProductService) loadProductRelations(ctx context.Context, products []models.Product) error {
+// loadProductRelations batch-loads the expanded relations. Without a
+// projection, images and services are loaded as they always have been.
+func (s *ProductService) loadProductRelations(ctx context.Context, products []models.Product, proj *ProductProjection) error {
 	if len(products) == 0 {
 		return nil
 	}
+	loadImages, loadServices, loadReviews := true, true, false
+	if proj != nil {
+		loadImages, loadServices, loadReviews = proj.Expands(ExpandImages), proj.Expands(ExpandServices), proj.Expands(ExpandReviews)
+	}
 
 	// Extract product IDs
 	productIDs := make([]uint, len(products))
@@ -241,18 +321,33 @@ func (s *ProductService) loadProductRelations(ctx context.Context, products []mo
 
 	// Load all images in batch
 	var images []models.Image
-	if err := s.db.WithContext(ctx).
-		Where("product_id IN ?", productIDs).
-		Find(&images).Error; err != nil {
-		return fmt.Errorf("failed to load product images: %v", err)
+	if loadImages {
+		if err := s.db.WithContext(ctx).
+			Where("product_id IN ?", productIDs).
+			Find(&images).Error; err != nil {
+			return fmt.Errorf("failed to load product images: %v", err)
+		}
 	}
 
 	// Load all services in batch
 	var services []models.Service
-	if err := s.db.WithContext(ctx).
-		Where("product_id IN ?", productIDs).
-		Find(&services).Error; err != nil {
-		return fmt.Errorf("failed to load product services: %v", err)
+	if loadServices {
+		if err := s.db.WithContext(ctx).
+			Where("product_id IN ?", productIDs).
+			Find(&services).Error; err != nil {
+			return fmt.Errorf("failed to load product services: %v", err)
+		}
+	}
+
+	// Reviews are only loaded on request; only published ones are public
+	var reviews []models.Review
+	if loadReviews {
+		if err := s.db.WithContext(ctx).
+			Where("product_id IN ? AND visibility = ?", productIDs, models.ReviewVisibilityPublished).
+			Order("created_at DESC").
+			Find(&reviews).Error; err != nil {
+			return fmt.Errorf("failed to load product reviews: %v", err)
+		}
 	}
 
 	// Group images and services by product ID
@@ -268,6 +363,43 @@ func (s *ProductService) loadProductRelations(ctx context.Context, products []mo
 		}
 	}
 
+	for _, review := range reviews {
+		// Public listings must not reveal who wrote an anonymous review
+		if review.IsAnonymous {
+			review.UserID = 0
+		}
+		if idx, exists := productMap[review.ProductID]; exists {
+			products[idx].Reviews = append(products[idx].Reviews, review)
+		}
+	}
+
+	// Products name their vendor; inactive vendors are left out
+	if proj.includesVendor() {
+		vendorIDs := make([]uint, 0)
+		for _, product := range products {
+			if product.VendorID != nil {
+				vendorIDs = append(vendorIDs, *product.VendorID)
+			}
+		}
+		if len(vendorIDs) > 0 {
+			var vendors []models.Vendor
+			if err := s.db.WithContext(ctx).
+				Where("id IN ? AND is_active = ?", vendorIDs, true).
+				Find(&vendors).Error; err != nil {
+				return fmt.Errorf("failed to load product vendors: %v", err)
+			}
+			byID := make(map[uint]*models.Vendor, len(vendors))
+			for i := range vendors {
+				byID[vendors[i].ID] = &vendors[i]
+			}
+			for i := range products {
+				if products[i].VendorID != nil {
+					products[i].Vendor = byID[*products[i].VendorID]
+				}
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -275,16 +407,19 @@ func (s *ProductService) loadProductRelations(ctx context.Context, products []mo
 
 
 
-func (s *ProductService) GetCategories(ctx context.Context) ([]string, error) {
+// GetCategories lists the distinct categories; a non-zero storeID limits
+// them to that store's products
+func (s *ProductService) GetCategories(ctx context.Context, storeID uint) ([]string, error) {
 	query := `
 		SELECT DISTINCT category
 		FROM products
 		WHERE category IS NOT NULL AND category != ''
+		AND (? = 0 OR store_id = ?)
 		ORDER BY category
 	`
 	
 	categories := make([]string, 0)
-	if err := s.db.WithContext(ctx).Raw(query).Scan(&categories).Error; err != nil {
+	if err := database.Replica(s.db).WithContext(ctx).Raw(query, storeID, storeID).Scan(&categories).Error; err != nil {
 		return nil, fmt.Errorf("%w: failed to fetch categories: %v", ErrDatabaseQuery, err)
 	}
 	
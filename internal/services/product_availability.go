@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// productInStockSQL holds for products that can be bought now: plain
+// products with stock, and bundles whose components are all active with
+// enough stock for one kit. A bundle's own stock column is not used.
+const productInStockSQL = `(
+	(products.stock > 0 AND NOT EXISTS (SELECT 1 FROM product_bundles b WHERE b.product_id = products.id))
+	OR EXISTS (
+		SELECT 1 FROM product_bundles b
+		WHERE b.product_id = products.id
+		AND EXISTS (SELECT 1 FROM product_bundle_items i WHERE i.bundle_id = b.id)
+		AND NOT EXISTS (
+			SELECT 1 FROM product_bundle_items i JOIN products c ON c.id = i.component_id
+			WHERE i.bundle_id = b.id AND (c.status <> 'active' OR c.stock < i.quantity)
+		)
+	)
+)`
+
+// stockScope limits a storefront listing by availability. Products without
+// stock are listed, to be marked out of stock, unless inStock is set or
+// their display setting, falling back to OUT_OF_STOCK_DISPLAY, hides them.
+func (s *ProductService) stockScope(inStock bool) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		switch {
+		case inStock:
+			return db.Where(productInStockSQL)
+		case s.hideOutOfStock:
+			return db.Where("COALESCE(products.out_of_stock_display, '') = ? OR "+productInStockSQL, models.OutOfStockShow)
+		default:
+			return db.Where("COALESCE(products.out_of_stock_display, '') <> ? OR "+productInStockSQL, models.OutOfStockHide)
+		}
+	}
+}
+
+// setAvailability marks each product in or out of stock. It needs the stock
+// column, so projections that leave it out get no availability.
+func (s *ProductService) setAvailability(ctx context.Context, products []models.Product, proj *ProductProjection) error {
+	if len(products) == 0 || !proj.selectsColumn("stock") {
+		return nil
+	}
+
+	ids := make([]uint, len(products))
+	for i := range products {
+		ids[i] = products[i].ID
+	}
+	var available []uint
+	if err := s.db.WithContext(ctx).Model(&models.Product{}).Where("id IN ?", ids).Where(productInStockSQL).Pluck("id", &available).Error; err != nil {
+		return fmt.Errorf("%w: failed to check availability: %v", ErrDatabaseQuery, err)
+	}
+
+	inStock := make(map[uint]bool, len(available))
+	for _, id := range available {
+		inStock[id] = true
+	}
+	for i := range products {
+		products[i].Availability = models.AvailabilityOutOfStock
+		if inStock[products[i].ID] {
+			products[i].Availability = models.AvailabilityInStock
+		}
+	}
+	return nil
+}
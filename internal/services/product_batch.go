@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const defaultImportBatchSize = 500
+
+// importBatchSize returns IMPORT_BATCH_SIZE, or the default when unset
+func importBatchSize(size int) int {
+	if size <= 0 {
+		return defaultImportBatchSize
+	}
+	return size
+}
+
+// errBatchConflict means another writer took one of a batch's SKUs after
+// they were checked; the batch was rolled back and should be retried row by
+// row, which reports the conflicting row precisely
+var errBatchConflict = errors.New("a SKU in the batch was taken concurrently")
+
+// insertProductBatch creates new products with their initial stock movements
+// and created events in one transaction, with one multi-row INSERT per
+// table. SKUs are expected to be free. A row whose SKU was taken in the
+// meantime is skipped by ON CONFLICT instead of aborting the statement, and
+// the batch is then rolled back with errBatchConflict, since the IDs Postgres
+// returns no longer line up with the rows.
+func insertProductBatch(ctx context.Context, db *gorm.DB, products []*models.Product, reference string, userID uint) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{
+			Columns:     []clause.Column{{Name: "sku"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "sku <> ''"}}},
+			DoNothing:   true,
+		}).Create(&products)
+		if result.Error != nil {
+			return fmt.Errorf("%w: failed to create products: %v", ErrDatabaseQuery, result.Error)
+		}
+		if int(result.RowsAffected) < len(products) {
+			return errBatchConflict
+		}
+
+		var movements []*models.StockMovement
+		events := make([]*models.OutboxEvent, 0, 2*len(products))
+		for _, product := range products {
+			event, err := newOutboxEvent(EventProductCreated, "product", product.ID, product)
+			if err != nil {
+				return err
+			}
+			events = append(events, event)
+
+			if product.Stock <= 0 {
+				continue
+			}
+			movements = append(movements, &models.StockMovement{
+				ProductID:  product.ID,
+				Delta:      product.Stock,
+				StockAfter: product.Stock,
+				Reason:     models.StockReasonInitial,
+				Reference:  reference,
+				CreatedBy:  userID,
+			})
+			// Starting from zero never crosses the low stock threshold, so
+			// stock.low is not needed here
+			event, err = newOutboxEvent(EventStockChanged, "product", product.ID, map[string]interface{}{
+				"product_id": product.ID,
+				"delta":      product.Stock,
+				"stock":      product.Stock,
+				"reason":     models.StockReasonInitial,
+			})
+			if err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
+
+		if len(movements) > 0 {
+			if err := tx.Create(&movements).Error; err != nil {
+				return fmt.Errorf("%w: failed to record stock movements: %v", ErrDatabaseQuery, err)
+			}
+		}
+		if err := tx.Create(&events).Error; err != nil {
+			return fmt.Errorf("%w: failed to record product events: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+}
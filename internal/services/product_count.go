@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/database"
+	"gorm.io/gorm"
+)
+
+// productCountCacheSize bounds the cached totals; search terms make the key
+// space unbounded, so the cache simply starts over when it fills up
+const productCountCacheSize = 1000
+
+type productCount struct {
+	total    int64
+	cachedAt time.Time
+}
+
+// productCountCache keeps exact product list totals per filter, so paging
+// through a list runs COUNT(*) once rather than on every page. Entries expire
+// after ttl and are all dropped whenever a product is created, updated or
+// deleted, goes in or out of stock, or categories are renamed or merged.
+type productCountCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]productCount
+}
+
+func newProductCountCache(ttl time.Duration) *productCountCache {
+	return &productCountCache{ttl: ttl, entries: make(map[string]productCount)}
+}
+
+func (c *productCountCache) get(key string) (int64, bool) {
+	if c.ttl <= 0 {
+		return 0, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Since(entry.cachedAt) >= c.ttl {
+		return 0, false
+	}
+	return entry.total, true
+}
+
+func (c *productCountCache) set(key string, total int64) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= productCountCacheSize {
+		c.entries = make(map[string]productCount)
+	}
+	c.entries[key] = productCount{total: total, cachedAt: time.Now()}
+}
+
+func (c *productCountCache) handleEvent(event Event) {
+	switch event.Type {
+	case EventProductCreated, EventProductUpdated, EventProductDeleted, EventCatalogChanged:
+		c.clear()
+	case EventStockChanged:
+		// Only running out or coming back changes which products are listed
+		var change struct {
+			Delta int `json:"delta"`
+			Stock int `json:"stock"`
+		}
+		if err := decodeEventData(event.Data, &change); err != nil {
+			return
+		}
+		if (change.Stock > 0) != (change.Stock-change.Delta > 0) {
+			c.clear()
+		}
+	}
+}
+
+func (c *productCountCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]productCount)
+}
+
+// productCountKey identifies the rows a filter matches; paging, sorting and
+// projection do not change the total
+func productCountKey(filter ProductFilter) string {
+	return fmt.Sprintf("%d|%q|%q|%q|%g|%g|%d|%t",
+		filter.StoreID, filter.Category, filter.Material, filter.Search,
+		filter.MinPrice, filter.MaxPrice, filter.MinReviews, filter.InStock)
+}
+
+// countProducts returns the total for a product list and whether it is
+// exact. A cached total is reused while fresh. Otherwise the planner's row
+// estimate, which Postgres derives from pg_class.reltuples and the column
+// statistics, decides: lists expected to be larger than the estimate
+// threshold report that estimate instead of paying for COUNT(*), unless the
+// caller asked for an exact count.
+func (s *ProductService) countProducts(ctx context.Context, query *gorm.DB, filter ProductFilter) (int64, bool, error) {
+	key := productCountKey(filter)
+	if total, ok := s.counts.get(key); ok {
+		return total, true, nil
+	}
+
+	if !filter.ExactCount && s.estimateAbove > 0 {
+		estimate, err := estimateRows(database.Replica(s.db).WithContext(ctx), query)
+		if err != nil {
+			return 0, false, fmt.Errorf("%w: failed to estimate products: %v", ErrDatabaseQuery, err)
+		}
+		if estimate > s.estimateAbove {
+			return estimate, false, nil
+		}
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return 0, false, fmt.Errorf("%w: failed to count products: %v", ErrDatabaseQuery, err)
+	}
+	s.counts.set(key, total)
+	return total, true, nil
+}
+
+// estimateRows returns the number of rows the planner expects query to
+// return, without running it
+func estimateRows(db, query *gorm.DB) (int64, error) {
+	subquery := query.Session(&gorm.Session{}).Select("1")
+
+	var raw string
+	if err := db.Raw("EXPLAIN (FORMAT JSON) ?", subquery).Row().Scan(&raw); err != nil {
+		return 0, err
+	}
+
+	var plans []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(raw), &plans); err != nil {
+		return 0, err
+	}
+	if len(plans) == 0 {
+		return 0, fmt.Errorf("empty query plan")
+	}
+	return int64(plans[0].Plan.Rows), nil
+}
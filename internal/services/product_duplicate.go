@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// DuplicateProduct copies a product and its services into a new draft so
+// catalogue managers can create variants without re-entering everything.
+// Images are copied to new S3 keys when requested, so deleting either
+// product never removes the other's files. The copy starts with no stock.
+func (s *AdminService) DuplicateProduct(ctx context.Context, productID uint, req *models.DuplicateProductRequest) (*models.Product, error) {
+	if productID == 0 {
+		return nil, fmt.Errorf("%w: invalid product ID", ErrInvalidInput)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var source models.Product
+	err := s.db.WithContext(ctx).
+		Preload("Images", "is_active = ?", true).
+		Preload("Services").
+		First(&source, productID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: product with ID %d not found", ErrProductNotFound, productID)
+		}
+		return nil, fmt.Errorf("%w: failed to load product: %v", ErrDatabaseQuery, err)
+	}
+
+	product := &models.Product{
+		Title:       source.Title + " (Copy)",
+		SKU:         strings.TrimSpace(req.SKU),
+		Description: source.Description,
+		Price:       source.Price,
+		Category:    source.Category,
+		Size:        source.Size,
+		Material:    source.Material,
+		MinOrderQty: source.MinOrderQty,
+		MaxOrderQty: source.MaxOrderQty,
+		Status:      models.ProductStatusDraft,
+		VendorID:    source.VendorID,
+		Images:      []models.Image{},
+		Services:    []models.Service{},
+	}
+	if req.Title != nil {
+		product.Title = strings.TrimSpace(*req.Title)
+		if product.Title == "" {
+			return nil, fmt.Errorf("%w: product title cannot be empty", ErrInvalidInput)
+		}
+	}
+	if product.SKU != "" {
+		var taken int64
+		if err := s.db.WithContext(ctx).Model(&models.Product{}).Where("sku = ?", product.SKU).Count(&taken).Error; err != nil {
+			return nil, fmt.Errorf("%w: failed to check SKU: %v", ErrDatabaseQuery, err)
+		}
+		if taken > 0 {
+			return nil, fmt.Errorf("%w: SKU %q is already in use", ErrInvalidInput, product.SKU)
+		}
+	}
+	for _, service := range source.Services {
+		product.Services = append(product.Services, models.Service{Name: service.Name, Link: service.Link})
+	}
+
+	// Copy the files before opening the transaction; S3 calls can be slow.
+	// Deduplicated images share the source's object instead.
+	var copiedKeys []string
+	if req.CopyImages {
+		for _, image := range source.Images {
+			if image.SHA256 != "" {
+				product.Images = append(product.Images, models.Image{
+					FileName:    image.FileName,
+					S3Key:       image.S3Key,
+					S3URL:       image.S3URL,
+					ContentType: image.ContentType,
+					Size:        image.Size,
+					SHA256:      image.SHA256,
+					AltText:     image.AltText,
+					Caption:     image.Caption,
+					IsActive:    true,
+				})
+				continue
+			}
+
+			data, _, err := s.s3Service.GetObject(image.S3Key)
+			if err != nil {
+				s.discardCopiedImages(ctx, copiedKeys)
+				return nil, fmt.Errorf("%w: failed to read image %s: %v", ErrS3Upload, image.S3Key, err)
+			}
+			result, err := uploadProductImageData(ctx, s.db, s.s3Service, image.FileName, data)
+			if err != nil {
+				s.discardCopiedImages(ctx, copiedKeys)
+				return nil, fmt.Errorf("%w: failed to copy image %s: %v", ErrS3Upload, image.S3Key, err)
+			}
+			copiedKeys = append(copiedKeys, uploadedKeys([]*UploadResult{result})...)
+			product.Images = append(product.Images, models.Image{
+				FileName:    result.FileName,
+				S3Key:       result.Key,
+				S3URL:       result.URL,
+				ContentType: result.ContentType,
+				Size:        result.Size,
+				SHA256:      result.SHA256,
+				AltText:     image.AltText,
+				Caption:     image.Caption,
+				IsActive:    true,
+			})
+		}
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := retainImageObjects(tx, product.Images); err != nil {
+			return err
+		}
+		if err := tx.Create(product).Error; err != nil {
+			return fmt.Errorf("%w: failed to create product copy: %v", ErrDatabaseQuery, err)
+		}
+		return recordOutboxEvent(tx, EventProductCreated, "product", product.ID, product)
+	})
+	if err != nil {
+		s.discardCopiedImages(ctx, copiedKeys)
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// discardCopiedImages removes files uploaded for a copy that was never saved
+func (s *AdminService) discardCopiedImages(ctx context.Context, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	if err := s.s3Service.DeleteMultipleImages(keys); err != nil {
+		s.log.WithContext(ctx).WithFields(map[string]interface{}{"s3_keys": keys}).Error("Failed to clean up copied product images: ", err)
+	}
+}
@@ -0,0 +1,433 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrProductDraftNotFound   = errors.New("product draft not found")
+	ErrProductDraftNotPending = errors.New("product draft has already been reviewed")
+	ErrExtractionFailed       = errors.New("product extraction failed")
+)
+
+const (
+	maxExtractionImages    = 20
+	maxExtractionImageSize = 10 * 1024 * 1024
+)
+
+// ProductExtractionService turns uploaded product photos into draft products
+// via the FastAPI extraction service and publishes them once approved.
+type ProductExtractionService struct {
+	db             *gorm.DB
+	fastAPIService *FastAPIService
+	s3Service      *S3Service
+	stagingDir     string
+}
+
+func NewProductExtractionService(db *gorm.DB, cfg *config.Config, fastAPIService *FastAPIService, s3Service *S3Service) *ProductExtractionService {
+	return &ProductExtractionService{
+		db:             db,
+		fastAPIService: fastAPIService,
+		s3Service:      s3Service,
+		stagingDir:     cfg.ExtractionStagingDir,
+	}
+}
+
+// StageImages saves the uploads to the staging directory, runs extraction
+// and stores one pending draft per product the service recognised.
+func (s *ProductExtractionService) StageImages(ctx context.Context, files []*multipart.FileHeader, userID uint) (*models.ProductExtractionResponse, error) {
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: no images provided", ErrInvalidInput)
+	}
+	if len(files) > maxExtractionImages {
+		return nil, fmt.Errorf("%w: at most %d images can be processed at once", ErrInvalidInput, maxExtractionImages)
+	}
+
+	batchID := uuid.New().String()
+	batchDir := filepath.Join(s.stagingDir, batchID)
+	if err := os.MkdirAll(batchDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %v", err)
+	}
+
+	staged, err := s.saveStagedFiles(batchDir, files)
+	if err != nil {
+		os.RemoveAll(batchDir)
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(staged))
+	for _, name := range staged {
+		paths = append(paths, filepath.Join(batchDir, name))
+	}
+
+	result, err := s.fastAPIService.ProcessImages(ctx, paths)
+	if err != nil {
+		os.RemoveAll(batchDir)
+		return nil, fmt.Errorf("%w: %w", ErrExtractionFailed, err)
+	}
+
+	drafts := make([]models.ProductDraft, 0, len(result.ProductData))
+	for _, data := range result.ProductData {
+		images := matchStagedImages(data.Images, staged)
+		if len(images) == 0 && len(result.ProductData) == 1 {
+			images = staged
+		}
+		drafts = append(drafts, models.ProductDraft{
+			BatchID:     batchID,
+			Status:      models.ProductDraftStatusPending,
+			Title:       strings.TrimSpace(data.Name),
+			Description: strings.TrimSpace(data.Description),
+			Price:       data.Price,
+			Category:    strings.TrimSpace(data.Category),
+			Brand:       strings.TrimSpace(data.Brand),
+			SKU:         strings.TrimSpace(data.SKU),
+			Images:      images,
+			CreatedBy:   userID,
+		})
+	}
+
+	if len(drafts) == 0 {
+		os.RemoveAll(batchDir)
+		return &models.ProductExtractionResponse{
+			BatchID: batchID,
+			Message: result.Message,
+			Drafts:  drafts,
+		}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Create(&drafts).Error; err != nil {
+		os.RemoveAll(batchDir)
+		return nil, fmt.Errorf("%w: failed to save product drafts: %v", ErrDatabaseQuery, err)
+	}
+
+	return &models.ProductExtractionResponse{
+		BatchID: batchID,
+		Message: result.Message,
+		Drafts:  drafts,
+	}, nil
+}
+
+func (s *ProductExtractionService) GetDrafts(ctx context.Context, status string, page, limit int) ([]models.ProductDraft, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	query := s.db.WithContext(ctx).Model(&models.ProductDraft{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to count product drafts: %v", ErrDatabaseQuery, err)
+	}
+
+	drafts := make([]models.ProductDraft, 0)
+	if err := query.Order("created_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&drafts).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to fetch product drafts: %v", ErrDatabaseQuery, err)
+	}
+	return drafts, total, nil
+}
+
+func (s *ProductExtractionService) GetDraft(ctx context.Context, id uint) (*models.ProductDraft, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var draft models.ProductDraft
+	if err := s.db.WithContext(ctx).First(&draft, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductDraftNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to fetch product draft: %v", ErrDatabaseQuery, err)
+	}
+	return &draft, nil
+}
+
+// GetStagedImage returns a staged image so reviewers can see it before approving
+func (s *ProductExtractionService) GetStagedImage(ctx context.Context, id uint, name string) (string, error) {
+	draft, err := s.GetDraft(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	for _, image := range draft.Images {
+		if image == name {
+			return filepath.Join(s.stagingDir, draft.BatchID, image), nil
+		}
+	}
+	return "", ErrImageNotFound
+}
+
+// ApproveDraft creates the product, moving the staged images to S3
+func (s *ProductExtractionService) ApproveDraft(ctx context.Context, id, userID uint, req *models.ApproveProductDraftRequest) (*models.Product, error) {
+	draft, err := s.GetDraft(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if draft.Status != models.ProductDraftStatusPending {
+		return nil, ErrProductDraftNotPending
+	}
+
+	product := &models.Product{
+		Title:       draft.Title,
+		SKU:         draft.SKU,
+		Description: draft.Description,
+		Price:       draft.Price,
+		Category:    draft.Category,
+		Material:    req.Material,
+		Size:        req.Size,
+		Stock:       req.Stock,
+		Status:      req.Status,
+	}
+	if req.Title != nil {
+		product.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.SKU != nil {
+		product.SKU = strings.TrimSpace(*req.SKU)
+	}
+	if req.Description != nil {
+		product.Description = *req.Description
+	}
+	if req.Price != nil {
+		product.Price = *req.Price
+	}
+	if req.Category != nil {
+		product.Category = *req.Category
+	}
+	if product.Status == "" {
+		product.Status = models.ProductStatusActive
+	}
+	if product.Title == "" {
+		return nil, fmt.Errorf("%w: product title cannot be empty", ErrInvalidInput)
+	}
+	if product.Price <= 0 {
+		return nil, fmt.Errorf("%w: product price must be greater than 0", ErrInvalidInput)
+	}
+
+	images := draft.Images
+	if req.Images != nil {
+		images = make([]string, 0, len(req.Images))
+		for _, name := range req.Images {
+			if !containsString(draft.Images, name) {
+				return nil, fmt.Errorf("%w: image %q is not part of this draft", ErrInvalidInput, name)
+			}
+			images = append(images, name)
+		}
+	}
+
+	uploads, err := s.uploadStagedImages(ctx, draft.BatchID, images)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(product).Error; err != nil {
+			return fmt.Errorf("%w: failed to create product: %v", ErrDatabaseQuery, err)
+		}
+		if product.Stock > 0 {
+			if _, err := recordStockMovement(tx, product.ID, product.Stock, product.Stock, models.StockReasonInitial, fmt.Sprintf("draft #%d", draft.ID), "", userID); err != nil {
+				return err
+			}
+		}
+
+		var images []models.Image
+		for _, upload := range uploads {
+			images = append(images, models.Image{
+				ProductID:   product.ID,
+				FileName:    upload.FileName,
+				S3Key:       upload.Key,
+				S3URL:       upload.URL,
+				ContentType: upload.ContentType,
+				Size:        upload.Size,
+				SHA256:      upload.SHA256,
+				IsActive:    true,
+			})
+		}
+		if err := retainImageObjects(tx, images); err != nil {
+			return err
+		}
+		if len(images) > 0 {
+			if err := tx.Create(&images).Error; err != nil {
+				return fmt.Errorf("%w: failed to create image records: %v", ErrDatabaseQuery, err)
+			}
+			product.Images = append(product.Images, images...)
+		}
+
+		// Guard against two reviewers approving the same draft at once
+		result := tx.Model(&models.ProductDraft{}).
+			Where("id = ? AND status = ?", draft.ID, models.ProductDraftStatusPending).
+			Updates(map[string]interface{}{
+				"status":      models.ProductDraftStatusApproved,
+				"product_id":  product.ID,
+				"reviewed_by": userID,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("%w: failed to update product draft: %v", ErrDatabaseQuery, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrProductDraftNotPending
+		}
+
+		return recordOutboxEvent(tx, EventProductCreated, "product", product.ID, product)
+	})
+	if err != nil {
+		// Reused objects belong to other images
+		keys := uploadedKeys(uploads)
+		if len(keys) > 0 {
+			if cleanupErr := s.s3Service.DeleteMultipleImages(keys); cleanupErr != nil {
+				logger.Error("Failed to clean up images for draft ", draft.ID, ": ", cleanupErr)
+			}
+		}
+		return nil, err
+	}
+
+	s.cleanupBatch(draft.BatchID)
+	return product, nil
+}
+
+func (s *ProductExtractionService) RejectDraft(ctx context.Context, id, userID uint) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	draft, err := s.GetDraft(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	result := s.db.WithContext(ctx).Model(&models.ProductDraft{}).
+		Where("id = ? AND status = ?", id, models.ProductDraftStatusPending).
+		Updates(map[string]interface{}{
+			"status":      models.ProductDraftStatusRejected,
+			"reviewed_by": userID,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("%w: failed to update product draft: %v", ErrDatabaseQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrProductDraftNotPending
+	}
+
+	s.cleanupBatch(draft.BatchID)
+	return nil
+}
+
+func (s *ProductExtractionService) saveStagedFiles(batchDir string, files []*multipart.FileHeader) ([]string, error) {
+	names := make([]string, 0, len(files))
+	used := make(map[string]bool, len(files))
+
+	for i, header := range files {
+		if header.Size > maxExtractionImageSize {
+			return nil, fmt.Errorf("%w: %s is larger than %d bytes", ErrInvalidInput, header.Filename, maxExtractionImageSize)
+		}
+		if !s.s3Service.isValidImageType(s.s3Service.getContentTypeFromExtension(header.Filename)) {
+			return nil, fmt.Errorf("%w: %s is not a supported image type", ErrInvalidInput, header.Filename)
+		}
+
+		name := filepath.Base(header.Filename)
+		if used[name] {
+			name = fmt.Sprintf("%d-%s", i+1, name)
+		}
+		used[name] = true
+
+		if err := saveUploadedFile(header, filepath.Join(batchDir, name)); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func saveUploadedFile(header *multipart.FileHeader, path string) error {
+	src, err := header.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", header.Filename, err)
+	}
+	defer src.Close()
+
+	dst, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to stage %s: %v", header.Filename, err)
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		return fmt.Errorf("failed to stage %s: %v", header.Filename, err)
+	}
+	return nil
+}
+
+func (s *ProductExtractionService) uploadStagedImages(ctx context.Context, batchID string, images []string) ([]*UploadResult, error) {
+	uploads := make([]*UploadResult, 0, len(images))
+	for _, name := range images {
+		data, err := os.ReadFile(filepath.Join(s.stagingDir, batchID, name))
+		if err == nil {
+			var upload *UploadResult
+			upload, err = uploadProductImageData(ctx, s.db, s.s3Service, name, data)
+			if err == nil {
+				uploads = append(uploads, upload)
+				continue
+			}
+		}
+
+		for _, key := range uploadedKeys(uploads) {
+			s.s3Service.DeleteImage(key)
+		}
+		return nil, fmt.Errorf("%w: %s: %v", ErrS3Upload, name, err)
+	}
+	return uploads, nil
+}
+
+// cleanupBatch removes the staged files once no draft in the batch is pending
+func (s *ProductExtractionService) cleanupBatch(batchID string) {
+	var pending int64
+	if err := s.db.Model(&models.ProductDraft{}).
+		Where("batch_id = ? AND status = ?", batchID, models.ProductDraftStatusPending).
+		Count(&pending).Error; err != nil || pending > 0 {
+		return
+	}
+	if err := os.RemoveAll(filepath.Join(s.stagingDir, batchID)); err != nil {
+		logger.Warn("Failed to remove staging directory for batch ", batchID, ": ", err)
+	}
+}
+
+// matchStagedImages maps the image names FastAPI returns (which may be
+// full paths) back to staged file names.
+func matchStagedImages(images, staged []string) []string {
+	matched := make([]string, 0, len(images))
+	for _, image := range images {
+		name := filepath.Base(image)
+		if containsString(staged, name) && !containsString(matched, name) {
+			matched = append(matched, name)
+		}
+	}
+	return matched
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
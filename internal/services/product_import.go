@@ -0,0 +1,672 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/metrics"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrProductImportNotFound = errors.New("product import not found")
+	ErrImportAlreadyRunning  = errors.New("product import is already running")
+)
+
+const (
+	maxImportRows    = 50000
+	importSampleRows = 5
+)
+
+// importHeaderAliases maps normalized spreadsheet headers to product fields
+// for the suggested mapping.
+var importHeaderAliases = map[string]string{
+	"sku": "sku", "code": "sku", "itemcode": "sku", "productcode": "sku", "articlenumber": "sku",
+	"title": "title", "name": "title", "productname": "title", "product": "title",
+	"description": "description", "details": "description",
+	"price": "price", "unitprice": "price", "cost": "price",
+	"category": "category", "type": "category",
+	"material": "material", "brand": "material",
+	"size":  "size",
+	"stock": "stock", "quantity": "stock", "qty": "stock", "inventory": "stock",
+	"status": "status",
+}
+
+type ProductImportService struct {
+	db         *gorm.DB
+	jobService *JobService
+	batchSize  int
+}
+
+func NewProductImportService(db *gorm.DB, jobService *JobService, batchSize int) *ProductImportService {
+	return &ProductImportService{db: db, jobService: jobService, batchSize: importBatchSize(batchSize)}
+}
+
+// UploadImport parses a CSV or XLSX file and stores its rows, returning the
+// detected headers and a suggested column mapping for the second step.
+func (s *ProductImportService) UploadImport(ctx context.Context, content []byte, filename string, userID uint) (*models.ProductImportUploadResponse, error) {
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")
+
+	var records [][]string
+	var err error
+	switch format {
+	case "csv":
+		reader := csv.NewReader(bytes.NewReader(content))
+		reader.FieldsPerRecord = -1
+		records, err = reader.ReadAll()
+	case "xlsx":
+		records, err = readXLSXRows(content)
+	default:
+		return nil, fmt.Errorf("%w: unsupported file type %q, use .csv or .xlsx", ErrInvalidInput, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse %s file: %v", ErrInvalidInput, format, err)
+	}
+
+	if len(records) < 2 {
+		return nil, fmt.Errorf("%w: file must have a header and at least one data row", ErrInvalidInput)
+	}
+
+	headers := make([]string, len(records[0]))
+	for i, header := range records[0] {
+		headers[i] = strings.TrimSpace(header)
+	}
+
+	// Blank rows are kept so row numbers in error reports match the sheet
+	rows := records[1:]
+	dataRows := 0
+	for _, record := range rows {
+		if !isBlankRow(record) {
+			dataRows++
+		}
+	}
+	if dataRows == 0 {
+		return nil, fmt.Errorf("%w: file has no data rows", ErrInvalidInput)
+	}
+	if dataRows > maxImportRows {
+		return nil, fmt.Errorf("%w: file has %d rows, the maximum is %d", ErrInvalidInput, dataRows, maxImportRows)
+	}
+
+	productImport := &models.ProductImport{
+		FileName:  filename,
+		Format:    format,
+		Headers:   headers,
+		Rows:      rows,
+		Status:    models.ImportStatusUploaded,
+		TotalRows: dataRows,
+		CreatedBy: userID,
+	}
+	if err := s.db.WithContext(ctx).Create(productImport).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to store import: %v", ErrDatabaseQuery, err)
+	}
+
+	sample := make([][]string, 0, importSampleRows)
+	for _, record := range rows {
+		if len(sample) == importSampleRows {
+			break
+		}
+		if !isBlankRow(record) {
+			sample = append(sample, record)
+		}
+	}
+
+	return &models.ProductImportUploadResponse{
+		Import:           productImport,
+		Headers:          headers,
+		SampleRows:       sample,
+		SuggestedMapping: suggestImportMapping(headers),
+		Fields:           models.ProductImportFields,
+	}, nil
+}
+
+func (s *ProductImportService) GetImport(ctx context.Context, id uint) (*models.ProductImport, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var productImport models.ProductImport
+	if err := s.db.WithContext(ctx).First(&productImport, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductImportNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to fetch import: %v", ErrDatabaseQuery, err)
+	}
+	return &productImport, nil
+}
+
+// RunImport validates the column mapping and imports the stored rows as a
+// background job; progress and row errors stream through the job console.
+func (s *ProductImportService) RunImport(ctx context.Context, id uint, req *models.RunProductImportRequest, userID uint) (*models.ProductImport, error) {
+	productImport, err := s.GetImport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if productImport.Status == models.ImportStatusRunning {
+		return nil, ErrImportAlreadyRunning
+	}
+
+	mode := req.Mode
+	if mode == "" {
+		mode = models.ImportModeInsert
+	}
+	columns, err := resolveImportMapping(productImport.Headers, req.Mapping, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	productImport.Mapping = req.Mapping
+	productImport.Mode = mode
+	productImport.Status = models.ImportStatusRunning
+	productImport.Created, productImport.Updated, productImport.Failed = 0, 0, 0
+	productImport.Errors = nil
+	if err := s.db.WithContext(ctx).Save(productImport).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to update import: %v", ErrDatabaseQuery, err)
+	}
+
+	job, err := s.jobService.Enqueue(ctx, models.JobTypeProductMappedImport, userID, func(ctx context.Context, run *JobRun) error {
+		return s.runImport(ctx, run, productImport.ID, columns, mode, userID)
+	})
+	if err != nil {
+		s.db.Model(productImport).Update("status", models.ImportStatusFailed)
+		return nil, err
+	}
+
+	productImport.JobID = &job.ID
+	s.db.WithContext(ctx).Model(productImport).Update("job_id", job.ID)
+	return productImport, nil
+}
+
+func (s *ProductImportService) runImport(ctx context.Context, run *JobRun, importID uint, columns map[string]int, mode string, userID uint) error {
+	var productImport models.ProductImport
+	if err := s.db.WithContext(ctx).First(&productImport, importID).Error; err != nil {
+		return fmt.Errorf("%w: failed to load import: %v", ErrDatabaseQuery, err)
+	}
+
+	completed := false
+	defer func() {
+		// Never leave the import stuck in running if the job fails or panics
+		if !completed {
+			s.db.Model(&models.ProductImport{}).Where("id = ?", importID).Update("status", models.ImportStatusFailed)
+		}
+	}()
+
+	run.SetTotal(productImport.TotalRows)
+	run.Infof("Importing %d rows from %s in %s mode", productImport.TotalRows, productImport.FileName, mode)
+	reference := fmt.Sprintf("import #%d", productImport.ID)
+
+	for start := 0; start < len(productImport.Rows); start += s.batchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		end := min(start+s.batchSize, len(productImport.Rows))
+
+		var rows []importRowValues
+		for i, row := range productImport.Rows[start:end] {
+			if isBlankRow(row) {
+				continue
+			}
+			values := make(map[string]string, len(columns))
+			for field, index := range columns {
+				if index < len(row) {
+					values[field] = strings.TrimSpace(row[index])
+				}
+			}
+			rows = append(rows, importRowValues{row: start + i + 2, values: values}) // header is row 1
+		}
+
+		processed, failed := 0, 0
+		for _, outcome := range s.importRows(ctx, rows, mode, reference, userID) {
+			switch {
+			case outcome.err != nil:
+				productImport.Failed++
+				productImport.Errors = append(productImport.Errors, models.ImportRowError{Row: outcome.row, Message: outcome.err.Error()})
+				run.Errorf("Row %d: %v", outcome.row, outcome.err)
+				metrics.CSVRows.WithLabelValues(metrics.CSVRowFailed).Inc()
+				failed++
+			case outcome.created:
+				productImport.Created++
+				metrics.CSVRows.WithLabelValues(metrics.CSVRowImported).Inc()
+				metrics.ProductsCreated.WithLabelValues("import").Inc()
+				processed++
+			default:
+				productImport.Updated++
+				metrics.CSVRows.WithLabelValues(metrics.CSVRowImported).Inc()
+				processed++
+			}
+		}
+		run.Advance(processed, failed)
+	}
+
+	productImport.Status = models.ImportStatusCompleted
+	if err := s.db.Save(&productImport).Error; err != nil {
+		return fmt.Errorf("%w: failed to save import results: %v", ErrDatabaseQuery, err)
+	}
+	completed = true
+
+	summary := fmt.Sprintf("%d created, %d updated, %d failed", productImport.Created, productImport.Updated, productImport.Failed)
+	run.SetResult(summary, "")
+	run.Infof("Import finished: %s", summary)
+	return nil
+}
+
+// importRowValues is a non-blank sheet row keyed by product field
+type importRowValues struct {
+	row    int
+	values map[string]string
+}
+
+// importRowOutcome reports whether a row created a product, updated one, or
+// failed
+type importRowOutcome struct {
+	row     int
+	created bool
+	err     error
+}
+
+// importRows imports a batch of rows. Existing SKUs are looked up with one
+// query; matched rows are updated one at a time since each touches only its
+// own non-empty columns, while new products are inserted together. A SKU that
+// appears again in the batch is imported after the batch so it sees the
+// product its first row created. Outcomes are returned in row order.
+func (s *ProductImportService) importRows(ctx context.Context, rows []importRowValues, mode, reference string, userID uint) []importRowOutcome {
+	existing, err := s.productsBySKU(ctx, rows)
+	if err != nil {
+		// Let every row look itself up and report the failure
+		outcomes := make([]importRowOutcome, 0, len(rows))
+		for _, r := range rows {
+			created, err := s.importRow(ctx, r.values, mode, reference, userID)
+			outcomes = append(outcomes, importRowOutcome{row: r.row, created: created, err: err})
+		}
+		return outcomes
+	}
+
+	outcomes := make([]importRowOutcome, 0, len(rows))
+	var newProducts []*models.Product
+	var newRows, repeated []importRowValues
+	batched := make(map[string]bool)
+	for _, r := range rows {
+		sku := r.values["sku"]
+		product, found := existing[sku]
+		if sku == "" {
+			found = false
+		}
+
+		switch {
+		case mode == models.ImportModeInsert && found:
+			outcomes = append(outcomes, importRowOutcome{row: r.row, err: fmt.Errorf("SKU %q already exists", sku)})
+		case mode == models.ImportModeUpdate && sku == "":
+			outcomes = append(outcomes, importRowOutcome{row: r.row, err: fmt.Errorf("sku is required in update mode")})
+		case mode == models.ImportModeUpdate && !found:
+			outcomes = append(outcomes, importRowOutcome{row: r.row, err: fmt.Errorf("no product with SKU %q", sku)})
+		case found:
+			err := s.updateImportedProduct(ctx, product, r.values, reference, userID)
+			outcomes = append(outcomes, importRowOutcome{row: r.row, err: err})
+		case sku != "" && batched[sku]:
+			repeated = append(repeated, r)
+		default:
+			newProduct, err := buildImportedProduct(r.values)
+			if err != nil {
+				outcomes = append(outcomes, importRowOutcome{row: r.row, err: err})
+				continue
+			}
+			if sku != "" {
+				batched[sku] = true
+			}
+			newProducts = append(newProducts, newProduct)
+			newRows = append(newRows, r)
+		}
+	}
+
+	if err := insertProductBatch(ctx, s.db, newProducts, reference, userID); err != nil {
+		// Retry row by row so only the offending rows fail
+		repeated = append(newRows, repeated...)
+	} else {
+		for _, r := range newRows {
+			outcomes = append(outcomes, importRowOutcome{row: r.row, created: true})
+		}
+	}
+	for _, r := range repeated {
+		created, err := s.importRow(ctx, r.values, mode, reference, userID)
+		outcomes = append(outcomes, importRowOutcome{row: r.row, created: created, err: err})
+	}
+
+	sort.Slice(outcomes, func(i, j int) bool { return outcomes[i].row < outcomes[j].row })
+	return outcomes
+}
+
+// productsBySKU loads the existing products matching the rows' SKUs
+func (s *ProductImportService) productsBySKU(ctx context.Context, rows []importRowValues) (map[string]*models.Product, error) {
+	skus := make([]string, 0, len(rows))
+	for _, r := range rows {
+		if sku := r.values["sku"]; sku != "" {
+			skus = append(skus, sku)
+		}
+	}
+	found := make(map[string]*models.Product, len(skus))
+	if len(skus) == 0 {
+		return found, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var products []models.Product
+	if err := s.db.WithContext(ctx).Where("sku IN ?", skus).Find(&products).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to look up SKUs: %v", ErrDatabaseQuery, err)
+	}
+	for i := range products {
+		found[products[i].SKU] = &products[i]
+	}
+	return found, nil
+}
+
+// importRow creates or updates one product, reporting whether it was created
+func (s *ProductImportService) importRow(ctx context.Context, values map[string]string, mode, reference string, userID uint) (bool, error) {
+	sku := values["sku"]
+
+	var existing models.Product
+	found := false
+	if sku != "" {
+		err := s.db.WithContext(ctx).Where("sku = ?", sku).First(&existing).Error
+		switch {
+		case err == nil:
+			found = true
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return false, fmt.Errorf("failed to look up SKU %q: %v", sku, err)
+		}
+	}
+
+	switch {
+	case mode == models.ImportModeInsert && found:
+		return false, fmt.Errorf("SKU %q already exists", sku)
+	case mode == models.ImportModeUpdate && sku == "":
+		return false, fmt.Errorf("sku is required in update mode")
+	case mode == models.ImportModeUpdate && !found:
+		return false, fmt.Errorf("no product with SKU %q", sku)
+	case found:
+		return false, s.updateImportedProduct(ctx, &existing, values, reference, userID)
+	default:
+		return true, s.createImportedProduct(ctx, values, reference, userID)
+	}
+}
+
+func (s *ProductImportService) createImportedProduct(ctx context.Context, values map[string]string, reference string, userID uint) error {
+	product, err := buildImportedProduct(values)
+	if err != nil {
+		return err
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(product).Error; err != nil {
+			return fmt.Errorf("failed to create product: %v", err)
+		}
+		if product.Stock > 0 {
+			if _, err := recordStockMovement(tx, product.ID, product.Stock, product.Stock, models.StockReasonInitial, reference, "", userID); err != nil {
+				return err
+			}
+		}
+		return recordOutboxEvent(tx, EventProductCreated, "product", product.ID, product)
+	})
+}
+
+// buildImportedProduct validates a row for a new product
+func buildImportedProduct(values map[string]string) (*models.Product, error) {
+	product := &models.Product{
+		SKU:         values["sku"],
+		Title:       values["title"],
+		Description: values["description"],
+		Category:    values["category"],
+		Material:    values["material"],
+		Size:        values["size"],
+		Status:      models.ProductStatusActive,
+	}
+	if product.Title == "" {
+		return nil, fmt.Errorf("title is required")
+	}
+
+	price, err := parseImportPrice(values["price"])
+	if err != nil {
+		return nil, err
+	}
+	product.Price = price
+
+	if raw, ok := values["stock"]; ok && raw != "" {
+		if product.Stock, err = parseImportStock(raw); err != nil {
+			return nil, err
+		}
+	}
+	if raw := values["status"]; raw != "" {
+		if product.Status, err = parseImportStatus(raw); err != nil {
+			return nil, err
+		}
+	}
+
+	return product, nil
+}
+
+// updateImportedProduct only touches the mapped columns that have a value, so
+// a sparse sheet (e.g. SKU + price) can be used for bulk repricing.
+func (s *ProductImportService) updateImportedProduct(ctx context.Context, product *models.Product, values map[string]string, reference string, userID uint) error {
+	updates := make(map[string]interface{})
+	for _, field := range []string{"title", "description", "category", "material", "size"} {
+		if value := values[field]; value != "" {
+			updates[field] = value
+		}
+	}
+	if raw := values["price"]; raw != "" {
+		price, err := parseImportPrice(raw)
+		if err != nil {
+			return err
+		}
+		updates["price"] = price
+	}
+	if raw := values["status"]; raw != "" {
+		status, err := parseImportStatus(raw)
+		if err != nil {
+			return err
+		}
+		updates["status"] = status
+	}
+
+	stock := -1
+	if raw := values["stock"]; raw != "" {
+		var err error
+		if stock, err = parseImportStock(raw); err != nil {
+			return err
+		}
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(updates) > 0 {
+			if err := tx.Model(product).Updates(updates).Error; err != nil {
+				return fmt.Errorf("failed to update product: %v", err)
+			}
+		}
+		if stock >= 0 {
+			if err := setStockTx(tx, product.ID, stock, models.StockReasonCorrection, reference, userID); err != nil {
+				return err
+			}
+		}
+
+		var changed models.Product
+		if err := tx.First(&changed, product.ID).Error; err != nil {
+			return fmt.Errorf("failed to reload product: %v", err)
+		}
+		return recordOutboxEvent(tx, EventProductUpdated, "product", product.ID, &changed)
+	})
+}
+
+// ErrorWorkbook builds an XLSX file with the failed rows and an extra Error
+// column so admins can fix and re-upload them.
+func (s *ProductImportService) ErrorWorkbook(ctx context.Context, id uint) ([]byte, error) {
+	productImport, err := s.GetImport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	file := excelize.NewFile()
+	defer file.Close()
+	sheet := file.GetSheetName(0)
+
+	header := make([]interface{}, 0, len(productImport.Headers)+2)
+	header = append(header, "Row")
+	for _, h := range productImport.Headers {
+		header = append(header, h)
+	}
+	header = append(header, "Error")
+	if err := file.SetSheetRow(sheet, "A1", &header); err != nil {
+		return nil, fmt.Errorf("failed to write error workbook: %v", err)
+	}
+
+	for i, rowError := range productImport.Errors {
+		values := make([]interface{}, 0, len(header))
+		values = append(values, rowError.Row)
+		if index := rowError.Row - 2; index >= 0 && index < len(productImport.Rows) {
+			row := productImport.Rows[index]
+			for col := range productImport.Headers {
+				if col < len(row) {
+					values = append(values, row[col])
+				} else {
+					values = append(values, "")
+				}
+			}
+		}
+		values = append(values, rowError.Message)
+
+		cell, _ := excelize.CoordinatesToCellName(1, i+2)
+		if err := file.SetSheetRow(sheet, cell, &values); err != nil {
+			return nil, fmt.Errorf("failed to write error workbook: %v", err)
+		}
+	}
+
+	buf, err := file.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write error workbook: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func readXLSXRows(content []byte) ([][]string, error) {
+	file, err := excelize.OpenReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("workbook has no sheets")
+	}
+	// Only the first sheet is imported
+	return file.GetRows(sheets[0])
+}
+
+// resolveImportMapping turns "field -> header" into "field -> column index"
+func resolveImportMapping(headers []string, mapping map[string]string, mode string) (map[string]int, error) {
+	known := make(map[string]bool, len(models.ProductImportFields))
+	for _, field := range models.ProductImportFields {
+		known[field] = true
+	}
+
+	columns := make(map[string]int, len(mapping))
+	for field, header := range mapping {
+		if header == "" {
+			continue
+		}
+		if !known[field] {
+			return nil, fmt.Errorf("%w: unknown product field %q", ErrInvalidInput, field)
+		}
+		index := -1
+		for i, h := range headers {
+			if strings.EqualFold(h, strings.TrimSpace(header)) {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("%w: column %q not found in file", ErrInvalidInput, header)
+		}
+		columns[field] = index
+	}
+
+	if mode != models.ImportModeInsert {
+		if _, ok := columns["sku"]; !ok {
+			return nil, fmt.Errorf("%w: a sku column is required in %s mode", ErrInvalidInput, mode)
+		}
+	}
+	if mode != models.ImportModeUpdate {
+		for _, field := range []string{"title", "price"} {
+			if _, ok := columns[field]; !ok {
+				return nil, fmt.Errorf("%w: a %s column is required to create products", ErrInvalidInput, field)
+			}
+		}
+	}
+	return columns, nil
+}
+
+func suggestImportMapping(headers []string) map[string]string {
+	mapping := make(map[string]string)
+	for _, header := range headers {
+		var normalized strings.Builder
+		for _, r := range strings.ToLower(header) {
+			if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+				normalized.WriteRune(r)
+			}
+		}
+		if field, ok := importHeaderAliases[normalized.String()]; ok {
+			if _, taken := mapping[field]; !taken {
+				mapping[field] = header
+			}
+		}
+	}
+	return mapping
+}
+
+func parseImportPrice(raw string) (float64, error) {
+	price, err := strconv.ParseFloat(strings.TrimPrefix(strings.ReplaceAll(raw, ",", ""), "$"), 64)
+	if err != nil || price <= 0 {
+		return 0, fmt.Errorf("invalid price %q", raw)
+	}
+	return price, nil
+}
+
+func parseImportStock(raw string) (int, error) {
+	stock, err := strconv.Atoi(raw)
+	if err != nil {
+		// Spreadsheets often store whole numbers as floats
+		f, ferr := strconv.ParseFloat(raw, 64)
+		if ferr != nil || f != float64(int(f)) {
+			return 0, fmt.Errorf("invalid stock %q", raw)
+		}
+		stock = int(f)
+	}
+	if stock < 0 {
+		return 0, fmt.Errorf("stock cannot be negative")
+	}
+	return stock, nil
+}
+
+func parseImportStatus(raw string) (string, error) {
+	status := strings.ToLower(raw)
+	if status != models.ProductStatusActive && status != models.ProductStatusInactive {
+		return "", fmt.Errorf("invalid status %q, use active or inactive", raw)
+	}
+	return status, nil
+}
+
+func isBlankRow(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+package services_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/testutil"
+)
+
+// TestProductListIndexes pins the plans of the public product listing to the
+// indexes migrated in database/indexes.go, so a change to the query or the
+// indexes that drops one back to a scan of every product fails here
+func TestProductListIndexes(t *testing.T) {
+	env := testutil.NewEnv(t)
+	seedListingProducts(t, env)
+	products := services.NewProductService(env.DB, env.Config, nil)
+
+	tests := []struct {
+		name    string
+		filter  services.ProductFilter
+		indexes []string
+	}{
+		{"newest", services.ProductFilter{}, []string{"idx_products_status_created"}},
+		{"later page", services.ProductFilter{Page: 3}, []string{"idx_products_status_created"}},
+		{"price ascending", services.ProductFilter{Sort: services.ProductSortPriceAsc}, []string{"idx_products_status_price"}},
+		{"price descending", services.ProductFilter{Sort: services.ProductSortPriceDesc}, []string{"idx_products_status_price"}},
+		{"price range", services.ProductFilter{MinPrice: 10, MaxPrice: 12, Sort: services.ProductSortPriceAsc}, []string{"idx_products_status_price"}},
+		{"rating", services.ProductFilter{Sort: services.ProductSortRating}, []string{"idx_products_status_rating"}},
+		{"store", services.ProductFilter{StoreID: 1}, []string{"idx_products_store_status_created"}},
+		{"category", services.ProductFilter{Category: "Kitchen"}, []string{"idx_products_category_trgm"}},
+		{"material", services.ProductFilter{Material: "bamboo"}, []string{"idx_products_material_trgm"}},
+		{"search", services.ProductFilter{Search: "lantern"}, []string{"idx_products_title_trgm", "idx_products_description_trgm"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := services.ProductListSQL(products, tt.filter)
+			if err != nil {
+				t.Fatalf("invalid filter: %v", err)
+			}
+			for _, index := range tt.indexes {
+				env.ExpectIndex(t, index, query)
+			}
+		})
+	}
+}
+
+// seedListingProducts fills the catalog so that the planner has statistics
+// to pick indexes from. Every hundredth product matches the category,
+// material and search filters above.
+func seedListingProducts(t *testing.T, env *testutil.Env) {
+	t.Helper()
+
+	products := make([]models.Product, 0, 5000)
+	for i := 0; i < cap(products); i++ {
+		product := models.Product{
+			Title:       fmt.Sprintf("Plain item %d", i),
+			SKU:         fmt.Sprintf("INDEX-%d", i),
+			Description: fmt.Sprintf("Everyday item number %d", i),
+			Price:       float64(5 + i%100),
+			Category:    fmt.Sprintf("aisle %d", i%40),
+			Material:    fmt.Sprintf("blend %d", i%40),
+			Status:      "active",
+			Stock:       10,
+			RatingScore: float64(i%50) / 10,
+			RatingCount: i % 7,
+		}
+		switch i % 100 {
+		case 0:
+			product.Category = "Kitchen"
+		case 1:
+			product.Material = "Bamboo"
+		case 2:
+			product.Title = fmt.Sprintf("Paper lantern %d", i)
+		}
+		products = append(products, product)
+	}
+	if err := env.DB.CreateInBatches(&products, 500).Error; err != nil {
+		t.Fatalf("failed to seed products: %v", err)
+	}
+	if err := env.DB.Exec("ANALYZE products").Error; err != nil {
+		t.Fatalf("failed to analyze products: %v", err)
+	}
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/metrics"
+	"gorm.io/gorm"
+)
+
+const (
+	exportBatchSize    = 500
+	exportURLExpiry    = 24 * time.Hour
+	uploadProgressStep = 10 // percent between S3 upload progress log lines
+)
+
+// StartProductImport queues a CSV product import. The file content is read by
+// the caller since multipart temp files do not outlive the request.
+//
+// Expected CSV format: name,description,price,category,brand,sku,stock
+func (s *AdminService) StartProductImport(ctx context.Context, content []byte, filename string, userID uint) (*models.Job, error) {
+	records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse CSV file: %v", ErrInvalidInput, err)
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("%w: CSV file must have header and at least one data row", ErrInvalidInput)
+	}
+
+	batchSize := importBatchSize(s.cfg.ImportBatchSize)
+
+	return s.jobService.Enqueue(ctx, models.JobTypeProductImport, userID, func(ctx context.Context, run *JobRun) error {
+		rows := records[1:] // Skip header
+		run.SetTotal(len(rows))
+		run.Infof("Importing %d rows from %s", len(rows), filename)
+
+		for start := 0; start < len(rows); start += batchSize {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			end := min(start+batchSize, len(rows))
+
+			// Invalid rows fail on their own; the rest are inserted together
+			var products []*models.Product
+			var sheetRows []int
+			failed := 0
+			for i, record := range rows[start:end] {
+				product, err := parseCSVProductRow(record)
+				if err != nil {
+					run.Errorf("Row %d: %v", start+i+2, err)
+					failed++
+					continue
+				}
+				products = append(products, product)
+				sheetRows = append(sheetRows, start+i+2)
+			}
+
+			processed := len(products)
+			if err := insertProductBatch(ctx, s.db, products, "csv import", 0); err != nil {
+				run.Infof("Batch insert of rows %d-%d failed (%v); retrying them one by one", start+2, end+1, err)
+				processed = 0
+				for i, product := range products {
+					product.ID = 0
+					if err := s.createCSVImportProduct(ctx, product); err != nil {
+						run.Errorf("Row %d: %v", sheetRows[i], err)
+						failed++
+						continue
+					}
+					processed++
+				}
+			}
+
+			metrics.CSVRows.WithLabelValues(metrics.CSVRowImported).Add(float64(processed))
+			metrics.CSVRows.WithLabelValues(metrics.CSVRowFailed).Add(float64(failed))
+			metrics.ProductsCreated.WithLabelValues("csv").Add(float64(processed))
+			run.Advance(processed, failed)
+		}
+
+		summary := fmt.Sprintf("Import finished: %d rows processed", len(rows))
+		run.SetResult(summary, "")
+		run.Infof("%s", summary)
+		return nil
+	})
+}
+
+// parseCSVProductRow validates one CSV row and builds its product
+func parseCSVProductRow(record []string) (*models.Product, error) {
+	if len(record) < 7 {
+		return nil, fmt.Errorf("insufficient columns")
+	}
+
+	price, err := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid price %q", record[2])
+	}
+
+	stock, err := strconv.Atoi(strings.TrimSpace(record[6]))
+	if err != nil {
+		stock = 0
+	}
+
+	product := &models.Product{
+		Title:       strings.TrimSpace(record[0]),
+		Description: strings.TrimSpace(record[1]),
+		Price:       price,
+		Category:    strings.TrimSpace(record[3]),
+		Material:    strings.TrimSpace(record[4]),
+		Size:        strings.TrimSpace(record[5]),
+		Stock:       stock,
+		Status:      models.ProductStatusActive,
+	}
+	if product.Title == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	return product, nil
+}
+
+// createCSVImportProduct creates one product of a CSV import on its own
+func (s *AdminService) createCSVImportProduct(ctx context.Context, product *models.Product) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(product).Error; err != nil {
+			return err
+		}
+		if product.Stock > 0 {
+			if _, err := recordStockMovement(tx, product.ID, product.Stock, product.Stock, models.StockReasonInitial, "csv import", "", 0); err != nil {
+				return err
+			}
+		}
+		return recordOutboxEvent(tx, EventProductCreated, "product", product.ID, product)
+	})
+}
+
+// StartProductExport queues a CSV export of all products. The file is
+// uploaded to S3 and a presigned download URL is stored on the job.
+func (s *AdminService) StartProductExport(ctx context.Context, userID uint) (*models.Job, error) {
+	return s.jobService.Enqueue(ctx, models.JobTypeProductExport, userID, func(ctx context.Context, run *JobRun) error {
+		var total int64
+		if err := s.db.WithContext(ctx).Model(&models.Product{}).Count(&total).Error; err != nil {
+			return fmt.Errorf("%w: failed to count products: %v", ErrDatabaseQuery, err)
+		}
+		// Half the progress bar covers building the file, half the upload
+		run.SetTotal(int(total) * 2)
+		run.Infof("Exporting %d products", total)
+
+		var buf bytes.Buffer
+		writer := csv.NewWriter(&buf)
+		writer.Write([]string{"id", "name", "description", "price", "category", "material", "size", "stock", "status"})
+
+		var batch []models.Product
+		err := s.db.WithContext(ctx).Order("id ASC").FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+			for _, p := range batch {
+				writer.Write([]string{
+					strconv.FormatUint(uint64(p.ID), 10),
+					p.Title,
+					p.Description,
+					strconv.FormatFloat(p.Price, 'f', 2, 64),
+					p.Category,
+					p.Material,
+					p.Size,
+					strconv.Itoa(p.Stock),
+					p.Status,
+				})
+			}
+			run.Advance(len(batch), 0)
+			return nil
+		}).Error
+		if err != nil {
+			return fmt.Errorf("%w: failed to read products: %v", ErrDatabaseQuery, err)
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return fmt.Errorf("failed to write CSV: %v", err)
+		}
+
+		key := fmt.Sprintf("exports/products/%s-job-%d.csv", time.Now().Format("20060102-150405"), run.JobID())
+		url, err := s.uploadExport(ctx, run, key, &buf)
+		if err != nil {
+			return err
+		}
+
+		run.SetResult(fmt.Sprintf("Exported %d products to %s", total, key), url)
+		run.Infof("Export ready")
+		return nil
+	})
+}
+
+// uploadExport uploads a finished export file as the second half of the job's
+// progress and returns a presigned download URL
+func (s *AdminService) uploadExport(ctx context.Context, run *JobRun, key string, buf *bytes.Buffer) (string, error) {
+	run.Infof("Uploading %d bytes to S3 as %s", buf.Len(), key)
+
+	lastLogged := -uploadProgressStep
+	err := s.s3Service.UploadFile(ctx, key, "text/csv", buf, int64(buf.Len()), func(sent, size int64) {
+		if size == 0 {
+			return
+		}
+		percent := int(sent * 100 / size)
+		if percent-lastLogged >= uploadProgressStep || sent == size {
+			lastLogged = percent
+			run.SetProgress(50 + percent/2)
+			run.Infof("S3 upload %d%% (%d/%d bytes)", percent, sent, size)
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+
+	url, err := s.s3Service.PresignGetURL(key, exportURLExpiry)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign export URL: %v", err)
+	}
+	return url, nil
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/types"
+	"gorm.io/gorm"
+)
+
+// Relations that can be requested with ?expand=
+const (
+	ExpandImages   = "images"
+	ExpandServices = "services"
+	ExpandReviews  = "reviews"
+)
+
+// productFieldColumns maps the JSON fields accepted by ?fields= to columns
+var productFieldColumns = map[string]string{
+	"id":               "id",
+	"title":            "title",
+	"sku":              "sku",
+	"description":      "description",
+	"price":            "price",
+	"compare_at_price": "compare_at_price",
+	"category":         "category",
+	"size":             "size",
+	"material":         "material",
+	"status":           "status",
+	"stock":            "stock",
+	"availability":     "stock",
+	"min_order_qty":    "min_order_qty",
+	"max_order_qty":    "max_order_qty",
+	"avg_rating":       "avg_rating",
+	"rating_count":     "rating_count",
+	"rating_score":     "rating_score",
+	"vendor_id":        "vendor_id",
+	"vendor":           "vendor_id",
+	"created_at":       "created_at",
+	"updated_at":       "updated_at",
+}
+
+var productRelations = map[string]bool{
+	ExpandImages:   true,
+	ExpandServices: true,
+	ExpandReviews:  true,
+}
+
+// ProductProjection controls which columns and relations a product query
+// loads (?fields=title,price&expand=images). A nil projection keeps each
+// endpoint's historical response.
+type ProductProjection struct {
+	fields []string
+	expand map[string]bool
+}
+
+// NewProductProjection validates the requested fields and relations. A nil
+// expand means the caller did not ask, so defaultExpand applies.
+func NewProductProjection(fields, expand []string, defaultExpand ...string) (*ProductProjection, error) {
+	p := &ProductProjection{expand: make(map[string]bool)}
+
+	for _, field := range fields {
+		field = strings.TrimSpace(strings.ToLower(field))
+		if field == "" {
+			continue
+		}
+		if _, ok := productFieldColumns[field]; !ok {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidFilter, field)
+		}
+		p.fields = append(p.fields, field)
+	}
+
+	if expand == nil {
+		expand = defaultExpand
+	}
+	for _, relation := range expand {
+		relation = strings.TrimSpace(strings.ToLower(relation))
+		if relation == "" {
+			continue
+		}
+		if !productRelations[relation] {
+			return nil, fmt.Errorf("%w: cannot expand %q", ErrInvalidFilter, relation)
+		}
+		p.expand[relation] = true
+	}
+	return p, nil
+}
+
+// Expands reports whether a relation should be loaded
+func (p *ProductProjection) Expands(relation string) bool {
+	return p == nil || p.expand[relation]
+}
+
+// columns lists the columns to select, or nil for all of them. The primary
+// key is always selected so relations can be attached.
+func (p *ProductProjection) columns() []string {
+	if p == nil || len(p.fields) == 0 {
+		return nil
+	}
+	columns := []string{"id"}
+	seen := map[string]bool{"id": true}
+	for _, field := range p.fields {
+		if column := productFieldColumns[field]; !seen[column] {
+			seen[column] = true
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// selectsColumn reports whether column is loaded
+func (p *ProductProjection) selectsColumn(column string) bool {
+	if p == nil || len(p.fields) == 0 {
+		return true
+	}
+	for _, field := range p.fields {
+		if productFieldColumns[field] == column {
+			return true
+		}
+	}
+	return false
+}
+
+// includesVendor reports whether the vendor object is part of the response
+func (p *ProductProjection) includesVendor() bool {
+	if p == nil || len(p.fields) == 0 {
+		return true
+	}
+	for _, field := range p.fields {
+		if field == "vendor" {
+			return true
+		}
+	}
+	return false
+}
+
+// apply adds the column selection and relation preloads to a product query
+func (p *ProductProjection) apply(query *gorm.DB, imageConds ...interface{}) *gorm.DB {
+	if columns := p.columns(); columns != nil {
+		query = query.Select(columns)
+	}
+	if p.Expands(ExpandImages) {
+		query = query.Preload("Images", imageConds...)
+	}
+	if p.Expands(ExpandServices) {
+		query = query.Preload("Services")
+	}
+	if p.Expands(ExpandReviews) {
+		query = query.Preload("Reviews")
+	}
+	if p.includesVendor() {
+		query = query.Preload("Vendor")
+	}
+	return query
+}
+
+// Shape trims a product to the requested fields and relations
+func (p *ProductProjection) Shape(product *models.Product) (interface{}, error) {
+	if p == nil {
+		return product, nil
+	}
+	return p.shape(product)
+}
+
+// ShapePage trims every product of a page, keeping the pagination metadata
+func (p *ProductProjection) ShapePage(page *types.Paginated[models.Product]) (interface{}, error) {
+	if p == nil {
+		return page, nil
+	}
+
+	items := make([]map[string]interface{}, 0, len(page.Items))
+	for i := range page.Items {
+		item, err := p.shape(&page.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return types.Paginated[map[string]interface{}]{Key: page.Key, Items: items, Pagination: page.Pagination}, nil
+}
+
+func (p *ProductProjection) shape(product *models.Product) (map[string]interface{}, error) {
+	raw, err := json.Marshal(product)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, err
+	}
+
+	keep := make(map[string]bool, len(p.fields)+len(p.expand))
+	for _, field := range p.fields {
+		keep[field] = true
+	}
+	for key := range object {
+		if productRelations[key] {
+			keep[key] = p.expand[key]
+		} else if len(p.fields) == 0 {
+			keep[key] = true
+		}
+	}
+	keep["id"] = true
+
+	for key := range object {
+		if !keep[key] {
+			delete(object, key)
+		}
+	}
+	// Expanded relations are always lists, even when empty
+	for relation := range p.expand {
+		if object[relation] == nil {
+			object[relation] = []interface{}{}
+		}
+	}
+	return object, nil
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// Product data quality issues
+const (
+	QualityNoImages         = "no_images"
+	QualityNoDescription    = "no_description"
+	QualityActiveOutOfStock = "active_out_of_stock"
+	QualityNoCategory       = "no_category"
+	QualityNoPriceHistory   = "no_price_history"
+)
+
+// productQualityChecks are the SQL conditions behind each issue, in report
+// order. A product has no price history when its price was never changed by
+// a scheduled price change or an edit recorded in its revisions.
+var productQualityChecks = []struct {
+	Issue     string
+	Condition string
+}{
+	{QualityNoImages, "NOT EXISTS (SELECT 1 FROM images i WHERE i.product_id = products.id AND i.is_active)"},
+	{QualityNoDescription, "TRIM(COALESCE(products.description, '')) = ''"},
+	{QualityActiveOutOfStock, "products.status = 'active' AND products.stock <= 0"},
+	{QualityNoCategory, "TRIM(COALESCE(products.category, '')) = ''"},
+	{QualityNoPriceHistory, "NOT EXISTS (SELECT 1 FROM scheduled_price_changes c WHERE c.product_id = products.id AND c.applied_at IS NOT NULL) " +
+		"AND NOT EXISTS (SELECT 1 FROM product_revisions r WHERE r.product_id = products.id AND (r.snapshot::jsonb->>'price')::float8 <> products.price)"},
+}
+
+// ProductQualityEntry is a product with at least one quality issue
+type ProductQualityEntry struct {
+	ID       uint     `json:"id"`
+	Title    string   `json:"title"`
+	SKU      string   `json:"sku,omitempty"`
+	Status   string   `json:"status"`
+	Category string   `json:"category"`
+	Stock    int      `json:"stock"`
+	Issues   []string `json:"issues"`
+}
+
+// ProductQualityReport lists products missing data, worst first, with the
+// number of products that have each issue
+type ProductQualityReport struct {
+	Counts   map[string]int64      `json:"counts"`
+	Products []ProductQualityEntry `json:"products"`
+	Total    int64                 `json:"-"`
+}
+
+// GetProductQualityReport finds products within scope that are missing data.
+// Archived products are left out. With issue set, only products with that
+// issue are listed; the counts always cover every issue.
+func (s *AdminService) GetProductQualityReport(ctx context.Context, scope ProductScope, issue string, page, limit int) (*ProductQualityReport, error) {
+	conditions := make([]string, 0, len(productQualityChecks))
+	counts := make([]string, 0, len(productQualityChecks))
+	flags := make([]string, 0, len(productQualityChecks))
+	severity := make([]string, 0, len(productQualityChecks))
+	filter := ""
+	for _, check := range productQualityChecks {
+		conditions = append(conditions, "("+check.Condition+")")
+		counts = append(counts, fmt.Sprintf("COUNT(*) FILTER (WHERE %s)", check.Condition))
+		flags = append(flags, fmt.Sprintf("(%s) AS %s", check.Condition, check.Issue))
+		severity = append(severity, fmt.Sprintf("(%s)::int", check.Condition))
+		if check.Issue == issue {
+			filter = check.Condition
+		}
+	}
+	if issue != "" && filter == "" {
+		return nil, fmt.Errorf("%w: unknown issue %q", ErrInvalidInput, issue)
+	}
+	if filter == "" {
+		filter = strings.Join(conditions, " OR ")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+	db := s.db.WithContext(ctx)
+
+	products := func() *gorm.DB {
+		return scope.apply(db.Model(&models.Product{})).Where("products.status <> ?", models.ProductStatusArchived)
+	}
+
+	tallies := make([]int64, len(productQualityChecks))
+	dest := make([]interface{}, len(tallies))
+	for i := range tallies {
+		dest[i] = &tallies[i]
+	}
+	if err := products().Select(strings.Join(counts, ", ")).Row().Scan(dest...); err != nil {
+		return nil, fmt.Errorf("%w: failed to count product issues: %v", ErrDatabaseQuery, err)
+	}
+	report := &ProductQualityReport{
+		Counts:   make(map[string]int64, len(productQualityChecks)),
+		Products: make([]ProductQualityEntry, 0),
+	}
+	for i, check := range productQualityChecks {
+		report.Counts[check.Issue] = tallies[i]
+	}
+
+	if err := products().Where(filter).Count(&report.Total).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to count products with issues: %v", ErrDatabaseQuery, err)
+	}
+	if report.Total == 0 {
+		return report, nil
+	}
+
+	rows, err := products().
+		Select("products.id, products.title, COALESCE(products.sku, ''), COALESCE(products.status, ''), COALESCE(products.category, ''), products.stock, " + strings.Join(flags, ", ")).
+		Where(filter).
+		Order(strings.Join(severity, " + ") + " DESC, products.id").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Rows()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch products with issues: %v", ErrDatabaseQuery, err)
+	}
+	defer rows.Close()
+
+	flagged := make([]bool, len(productQualityChecks))
+	for rows.Next() {
+		var entry ProductQualityEntry
+		dest := []interface{}{&entry.ID, &entry.Title, &entry.SKU, &entry.Status, &entry.Category, &entry.Stock}
+		for i := range flagged {
+			dest = append(dest, &flagged[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("%w: failed to read products with issues: %v", ErrDatabaseQuery, err)
+		}
+		entry.Issues = make([]string, 0, len(flagged))
+		for i, check := range productQualityChecks {
+			if flagged[i] {
+				entry.Issues = append(entry.Issues, check.Issue)
+			}
+		}
+		report.Products = append(report.Products, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: failed to read products with issues: %v", ErrDatabaseQuery, err)
+	}
+	return report, nil
+}
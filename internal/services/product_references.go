@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var ErrProductHasReferences = errors.New("product is referenced and cannot be deleted; archive it instead")
+
+// productReferenceCheck describes a table that may point at a product. Blocking
+// references prevent a hard delete; the rest are cleaned up with the product.
+// Tables that are not migrated yet (orders, carts, wishlists) are skipped.
+type productReferenceCheck struct {
+	Type     string
+	Table    string
+	Column   string
+	Blocking bool
+}
+
+var productReferenceChecks = []productReferenceCheck{
+	{Type: "orders", Table: "order_items", Column: "product_id", Blocking: true},
+	{Type: "bundles", Table: "product_bundle_items", Column: "component_id", Blocking: true},
+	{Type: "carts", Table: "cart_items", Column: "product_id", Blocking: false},
+	{Type: "wishlists", Table: "wishlist_items", Column: "product_id", Blocking: false},
+	{Type: "reviews", Table: "reviews", Column: "product_id", Blocking: false},
+	{Type: "reactions", Table: "product_reactions", Column: "product_id", Blocking: false},
+}
+
+type ProductReference struct {
+	Type     string `json:"type"`
+	Count    int64  `json:"count"`
+	Blocking bool   `json:"blocking"`
+}
+
+type ProductReferenceReport struct {
+	ProductID  uint               `json:"product_id"`
+	Status     string             `json:"status"`
+	CanDelete  bool               `json:"can_delete"`
+	Reason     string             `json:"reason,omitempty"`
+	References []ProductReference `json:"references"`
+}
+
+// GetProductReferences reports which records reference the product and
+// whether it can be hard-deleted.
+func (s *AdminService) GetProductReferences(ctx context.Context, productID uint) (*ProductReferenceReport, error) {
+	if productID == 0 {
+		return nil, fmt.Errorf("%w: invalid product ID", ErrInvalidInput)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var product models.Product
+	if err := s.db.WithContext(ctx).Select("id", "status").First(&product, productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: product with ID %d not found", ErrProductNotFound, productID)
+		}
+		return nil, fmt.Errorf("%w: failed to find product: %v", ErrDatabaseQuery, err)
+	}
+
+	return s.collectProductReferences(s.db.WithContext(ctx), &product)
+}
+
+func (s *AdminService) collectProductReferences(db *gorm.DB, product *models.Product) (*ProductReferenceReport, error) {
+	report := &ProductReferenceReport{
+		ProductID:  product.ID,
+		Status:     product.Status,
+		CanDelete:  true,
+		References: []ProductReference{},
+	}
+
+	for _, check := range productReferenceChecks {
+		if !db.Migrator().HasTable(check.Table) {
+			continue
+		}
+
+		var count int64
+		if err := db.Table(check.Table).Where(check.Column+" = ?", product.ID).Count(&count).Error; err != nil {
+			return nil, fmt.Errorf("%w: failed to count %s references: %v", ErrDatabaseQuery, check.Type, err)
+		}
+
+		report.References = append(report.References, ProductReference{
+			Type:     check.Type,
+			Count:    count,
+			Blocking: check.Blocking,
+		})
+
+		if check.Blocking && count > 0 {
+			report.CanDelete = false
+			report.Reason = fmt.Sprintf("referenced by %d %s", count, check.Type)
+		}
+	}
+
+	return report, nil
+}
+
+// ArchiveProduct hides a product from the storefront while keeping it and its
+// history intact for orders that reference it.
+func (s *AdminService) ArchiveProduct(ctx context.Context, productID uint) (*models.Product, error) {
+	if productID == 0 {
+		return nil, fmt.Errorf("%w: invalid product ID", ErrInvalidInput)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var product models.Product
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Product{}).
+			Where("id = ?", productID).
+			Updates(map[string]interface{}{
+				"status":     models.ProductStatusArchived,
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("%w: failed to archive product: %v", ErrDatabaseQuery, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: product with ID %d not found", ErrProductNotFound, productID)
+		}
+
+		if err := tx.Preload("Images").Preload("Services").First(&product, productID).Error; err != nil {
+			return fmt.Errorf("%w: failed to reload product: %v", ErrDatabaseQuery, err)
+		}
+		return recordOutboxEvent(tx, EventProductUpdated, "product", productID, &product)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &product, nil
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrProductReportNotFound  = errors.New("product report not found")
+	ErrProductAlreadyReported = errors.New("product already reported")
+)
+
+// ProductReportService takes customer reports of problematic listings and
+// feeds them to the admin queue. Once a product has threshold open reports it
+// is set inactive until an admin reviews it; a threshold of 0 disables
+// automatic de-listing.
+type ProductReportService struct {
+	db        *gorm.DB
+	threshold int
+}
+
+func NewProductReportService(db *gorm.DB, threshold int) *ProductReportService {
+	return &ProductReportService{db: db, threshold: threshold}
+}
+
+// ReportProduct records a customer's report and de-lists the product when it
+// reaches the threshold
+func (s *ProductReportService) ReportProduct(ctx context.Context, productID, reporterID uint, req *models.CreateProductReportRequest) (*models.ProductReport, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	report := models.ProductReport{
+		ProductID:  productID,
+		ReporterID: reporterID,
+		Reason:     req.Reason,
+		Details:    strings.TrimSpace(req.Details),
+		Status:     models.ReportStatusOpen,
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the product so concurrent reports cannot both miss the threshold
+		var product models.Product
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("status <> ?", models.ProductStatusArchived).
+			First(&product, productID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: product with ID %d not found", ErrProductNotFound, productID)
+			}
+			return fmt.Errorf("%w: failed to find product: %v", ErrDatabaseQuery, err)
+		}
+
+		var existing int64
+		if err := tx.Model(&models.ProductReport{}).
+			Where("product_id = ? AND reporter_id = ?", productID, reporterID).
+			Count(&existing).Error; err != nil {
+			return fmt.Errorf("%w: failed to check existing reports: %v", ErrDatabaseQuery, err)
+		}
+		if existing > 0 {
+			return ErrProductAlreadyReported
+		}
+
+		if err := tx.Create(&report).Error; err != nil {
+			return fmt.Errorf("%w: failed to create report: %v", ErrDatabaseQuery, err)
+		}
+
+		if s.threshold <= 0 || product.Status != models.ProductStatusActive {
+			return nil
+		}
+
+		var open int64
+		if err := tx.Model(&models.ProductReport{}).
+			Where("product_id = ? AND status IN ?", productID, []string{models.ReportStatusOpen, models.ReportStatusReviewing}).
+			Count(&open).Error; err != nil {
+			return fmt.Errorf("%w: failed to count reports: %v", ErrDatabaseQuery, err)
+		}
+		if open < int64(s.threshold) {
+			return nil
+		}
+
+		product.Status = models.ProductStatusInactive
+		if err := tx.Model(&product).Update("status", product.Status).Error; err != nil {
+			return fmt.Errorf("%w: failed to de-list product: %v", ErrDatabaseQuery, err)
+		}
+		now := time.Now()
+		report.DelistedAt = &now
+		if err := tx.Model(&report).Update("delisted_at", now).Error; err != nil {
+			return fmt.Errorf("%w: failed to update report: %v", ErrDatabaseQuery, err)
+		}
+		return recordOutboxEvent(tx, EventProductUpdated, "product", product.ID, &product)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// GetReports lists reports for the admin queue, oldest first so the queue is
+// worked in order. Empty status and zero productID match everything.
+func (s *ProductReportService) GetReports(ctx context.Context, status string, productID uint, page, limit int) ([]models.ProductReport, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	query := s.db.WithContext(ctx).Model(&models.ProductReport{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if productID != 0 {
+		query = query.Where("product_id = ?", productID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to count reports: %v", ErrDatabaseQuery, err)
+	}
+
+	reports := make([]models.ProductReport, 0)
+	if err := query.Preload("Product").Preload("Reporter").
+		Order("id ASC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&reports).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to fetch reports: %v", ErrDatabaseQuery, err)
+	}
+	return reports, total, nil
+}
+
+func (s *ProductReportService) GetReport(ctx context.Context, id uint) (*models.ProductReport, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var report models.ProductReport
+	if err := s.db.WithContext(ctx).Preload("Product").Preload("Reporter").First(&report, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: report %d not found", ErrProductReportNotFound, id)
+		}
+		return nil, fmt.Errorf("%w: failed to fetch report: %v", ErrDatabaseQuery, err)
+	}
+	return &report, nil
+}
+
+// UpdateReport moves a report through the admin queue. Resolving or
+// dismissing a report records the admin and note; it does not relist a
+// de-listed product, which stays inactive until an admin activates it.
+func (s *ProductReportService) UpdateReport(ctx context.Context, id, adminID uint, req *models.UpdateProductReportRequest) (*models.ProductReport, error) {
+	report, err := s.GetReport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updateData := map[string]interface{}{"status": req.Status}
+	switch req.Status {
+	case models.ReportStatusResolved, models.ReportStatusDismissed:
+		updateData["resolved_by"] = adminID
+		updateData["resolved_at"] = time.Now()
+		updateData["resolution_note"] = strings.TrimSpace(req.Note)
+	default:
+		updateData["resolved_by"] = nil
+		updateData["resolved_at"] = nil
+		updateData["resolution_note"] = ""
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := s.db.WithContext(queryCtx).Model(report).Updates(updateData).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to update report: %v", ErrDatabaseQuery, err)
+	}
+	return s.GetReport(ctx, id)
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrRevisionNotFound = errors.New("product revision not found")
+
+// GetProductRevisions lists a product's revisions, newest first, each with
+// the fields it changed compared to the revision before it
+func (s *AdminService) GetProductRevisions(ctx context.Context, productID uint, page, limit int) ([]models.ProductRevision, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	db := s.db.WithContext(ctx)
+	if err := db.Select("id").First(&models.Product{}, productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, fmt.Errorf("%w: product with ID %d not found", ErrProductNotFound, productID)
+		}
+		return nil, 0, fmt.Errorf("%w: failed to find product: %v", ErrDatabaseQuery, err)
+	}
+
+	var total int64
+	if err := db.Model(&models.ProductRevision{}).Where("product_id = ?", productID).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to count revisions: %v", ErrDatabaseQuery, err)
+	}
+
+	// One extra row gives the oldest revision on the page something to diff against
+	var revisions []models.ProductRevision
+	if err := db.Where("product_id = ?", productID).
+		Order("number DESC").
+		Offset((page - 1) * limit).
+		Limit(limit + 1).
+		Find(&revisions).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to fetch revisions: %v", ErrDatabaseQuery, err)
+	}
+
+	for i := range revisions {
+		if i+1 < len(revisions) {
+			revisions[i].Changes = diffSnapshots(&revisions[i+1].Snapshot, &revisions[i].Snapshot)
+		} else {
+			revisions[i].Changes = []models.FieldChange{}
+		}
+	}
+	if len(revisions) > limit {
+		revisions = revisions[:limit]
+	}
+	return revisions, total, nil
+}
+
+// RestoreProductRevision reverts a product's fields and services to those of
+// revision number and records the result as a new revision. Stock is left
+// alone, since it only moves through the stock ledger, and so are images,
+// whose removed objects may already be gone from S3. The vendor is only
+// restored when restoreVendor is set, i.e. for admins.
+func (s *AdminService) RestoreProductRevision(ctx context.Context, actorID, productID uint, number int, restoreVendor bool) (*models.Product, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var revision models.ProductRevision
+	if err := s.db.WithContext(queryCtx).Where("product_id = ? AND number = ?", productID, number).First(&revision).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: revision %d of product %d", ErrRevisionNotFound, number, productID)
+		}
+		return nil, fmt.Errorf("%w: failed to find revision: %v", ErrDatabaseQuery, err)
+	}
+
+	snapshot := revision.Snapshot
+	req := models.UpdateProductRequest{
+		Title:       &snapshot.Title,
+		SKU:         &snapshot.SKU,
+		Description: &snapshot.Description,
+		Price:       &snapshot.Price,
+		Category:    &snapshot.Category,
+		Material:    &snapshot.Material,
+		Size:        &snapshot.Size,
+		Status:      &snapshot.Status,
+		Services:    snapshot.Services,
+	}
+	if req.Services == nil {
+		// A nil list leaves services untouched; restore "no services" too
+		req.Services = []models.CreateServiceRequest{}
+	}
+	if restoreVendor {
+		vendorID := uint(0)
+		if snapshot.VendorID != nil {
+			vendorID = *snapshot.VendorID
+		}
+		req.VendorID = &vendorID
+	}
+
+	return s.updateProduct(ctx, productID, &req, nil, nil, models.ProductRevision{
+		Source:       models.RevisionSourceRestore,
+		RestoredFrom: &revision.Number,
+		ActorID:      &actorID,
+	})
+}
+
+// recordProductRevision snapshots the product as it stands in tx and stores
+// it as its next revision. revision supplies the source, actor and restore
+// origin.
+func recordProductRevision(tx *gorm.DB, productID uint, revision models.ProductRevision) error {
+	snapshot, err := snapshotProduct(tx, productID)
+	if err != nil {
+		return err
+	}
+
+	var last int
+	if err := tx.Model(&models.ProductRevision{}).
+		Where("product_id = ?", productID).
+		Select("COALESCE(MAX(number), 0)").
+		Scan(&last).Error; err != nil {
+		return fmt.Errorf("%w: failed to number revision: %v", ErrDatabaseQuery, err)
+	}
+
+	revision.ProductID = productID
+	revision.Number = last + 1
+	revision.Snapshot = *snapshot
+	if err := tx.Create(&revision).Error; err != nil {
+		return fmt.Errorf("%w: failed to record revision: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
+
+// recordBaselineRevision records the product's current state before its
+// first recorded edit, so products created before revisions existed (or
+// never edited) get something to diff against. It locks the product row,
+// which keeps concurrent edits from taking the same revision number.
+func recordBaselineRevision(tx *gorm.DB, productID uint) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Product{}, productID).Error; err != nil {
+		return fmt.Errorf("%w: failed to lock product: %v", ErrDatabaseQuery, err)
+	}
+
+	var count int64
+	if err := tx.Model(&models.ProductRevision{}).Where("product_id = ?", productID).Count(&count).Error; err != nil {
+		return fmt.Errorf("%w: failed to count revisions: %v", ErrDatabaseQuery, err)
+	}
+	if count > 0 {
+		return nil
+	}
+	return recordProductRevision(tx, productID, models.ProductRevision{Source: models.RevisionSourceBaseline})
+}
+
+func snapshotProduct(tx *gorm.DB, productID uint) (*models.ProductSnapshot, error) {
+	var product models.Product
+	if err := tx.Preload("Images", func(db *gorm.DB) *gorm.DB {
+		return db.Where("is_active = ?", true).Order("created_at ASC")
+	}).Preload("Services", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).First(&product, productID).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to load product for revision: %v", ErrDatabaseQuery, err)
+	}
+
+	snapshot := models.ProductSnapshot{
+		Title:       product.Title,
+		SKU:         product.SKU,
+		Description: product.Description,
+		Price:       product.Price,
+		Category:    product.Category,
+		Size:        product.Size,
+		Material:    product.Material,
+		Status:      product.Status,
+		Stock:       product.Stock,
+		VendorID:    product.VendorID,
+		Services:    []models.CreateServiceRequest{},
+		Images:      []models.ImageSnapshot{},
+	}
+	for _, service := range product.Services {
+		snapshot.Services = append(snapshot.Services, models.CreateServiceRequest{Name: service.Name, Link: service.Link})
+	}
+	for _, image := range product.Images {
+		snapshot.Images = append(snapshot.Images, models.ImageSnapshot{
+			ID:      image.ID.String(),
+			URL:     image.S3URL,
+			AltText: image.AltText,
+			Caption: image.Caption,
+		})
+	}
+	return &snapshot, nil
+}
+
+// diffSnapshots lists the snapshot fields that differ from before to after,
+// by JSON field name. Lists (services, images) are compared as a whole.
+func diffSnapshots(before, after *models.ProductSnapshot) []models.FieldChange {
+	from, to := snapshotFields(before), snapshotFields(after)
+
+	fields := make([]string, 0, len(to))
+	for field := range to {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	changes := []models.FieldChange{}
+	for _, field := range fields {
+		if !reflect.DeepEqual(from[field], to[field]) {
+			changes = append(changes, models.FieldChange{Field: field, From: from[field], To: to[field]})
+		}
+	}
+	return changes
+}
+
+func snapshotFields(snapshot *models.ProductSnapshot) map[string]interface{} {
+	fields := make(map[string]interface{})
+	data, err := json.Marshal(snapshot)
+	if err == nil {
+		err = json.Unmarshal(data, &fields)
+	}
+	if err != nil {
+		// Snapshots are plain data, so this cannot happen
+		return map[string]interface{}{}
+	}
+	return fields
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var ErrServiceNotFound = errors.New("product service not found")
+
+// GetProductServices lists the services (name and link) of an active product
+func (s *ProductService) GetProductServices(ctx context.Context, productID uint) ([]models.Service, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	db := s.db.WithContext(ctx)
+	if err := db.Select("id").Where("id = ? AND status = ?", productID, models.ProductStatusActive).First(&models.Product{}).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: product with ID %d not found", ErrProductNotFound, productID)
+		}
+		return nil, fmt.Errorf("%w: failed to find product: %v", ErrDatabaseQuery, err)
+	}
+
+	services := []models.Service{}
+	if err := db.Where("product_id = ?", productID).Order("id ASC").Find(&services).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch services: %v", ErrDatabaseQuery, err)
+	}
+	return services, nil
+}
+
+// AddProductService adds one service to a product
+func (s *AdminService) AddProductService(ctx context.Context, productID uint, req *models.CreateServiceRequest) (*models.Service, error) {
+	service := models.Service{
+		ProductID: productID,
+		Name:      strings.TrimSpace(req.Name),
+		Link:      strings.TrimSpace(req.Link),
+	}
+	if err := validateService(service.Name, service.Link); err != nil {
+		return nil, err
+	}
+
+	err := s.changeProduct(ctx, productID, func(tx *gorm.DB) error {
+		if err := tx.Create(&service).Error; err != nil {
+			return fmt.Errorf("%w: failed to create service: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// UpdateProductService changes the name and/or link of one of a product's
+// services
+func (s *AdminService) UpdateProductService(ctx context.Context, productID, serviceID uint, req *models.UpdateServiceRequest) (*models.Service, error) {
+	var service models.Service
+	err := s.changeProduct(ctx, productID, func(tx *gorm.DB) error {
+		if err := findProductService(tx, productID, serviceID, &service); err != nil {
+			return err
+		}
+		if req.Name != nil {
+			service.Name = strings.TrimSpace(*req.Name)
+		}
+		if req.Link != nil {
+			service.Link = strings.TrimSpace(*req.Link)
+		}
+		if err := validateService(service.Name, service.Link); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&service).Updates(map[string]interface{}{"name": service.Name, "link": service.Link}).Error; err != nil {
+			return fmt.Errorf("%w: failed to update service: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// DeleteProductService removes one service from a product
+func (s *AdminService) DeleteProductService(ctx context.Context, productID, serviceID uint) error {
+	return s.changeProduct(ctx, productID, func(tx *gorm.DB) error {
+		var service models.Service
+		if err := findProductService(tx, productID, serviceID, &service); err != nil {
+			return err
+		}
+		if err := tx.Delete(&service).Error; err != nil {
+			return fmt.Errorf("%w: failed to delete service: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+}
+
+// changeProduct runs change in a transaction after checking the product
+// exists, then publishes product.updated with the product as changed
+func (s *AdminService) changeProduct(ctx context.Context, productID uint, change func(tx *gorm.DB) error) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").First(&models.Product{}, productID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: product with ID %d not found", ErrProductNotFound, productID)
+			}
+			return fmt.Errorf("%w: failed to find product: %v", ErrDatabaseQuery, err)
+		}
+
+		if err := change(tx); err != nil {
+			return err
+		}
+		return publishProductUpdated(tx, productID)
+	})
+}
+
+// publishProductUpdated records product.updated with the product as it now
+// stands in tx, including its active images and services. It also bumps
+// updated_at, which catalog connectors sync by, so changes made only to
+// images or services are pushed too.
+func publishProductUpdated(tx *gorm.DB, productID uint) error {
+	if err := tx.Model(&models.Product{}).Where("id = ?", productID).Update("updated_at", time.Now()).Error; err != nil {
+		return fmt.Errorf("%w: failed to touch product: %v", ErrDatabaseQuery, err)
+	}
+
+	var product models.Product
+	if err := tx.Preload("Images", "is_active = ?", true).Preload("Services").First(&product, productID).Error; err != nil {
+		return fmt.Errorf("%w: failed to reload product: %v", ErrDatabaseQuery, err)
+	}
+	return recordOutboxEvent(tx, EventProductUpdated, "product", productID, &product)
+}
+
+func findProductService(tx *gorm.DB, productID, serviceID uint, service *models.Service) error {
+	if err := tx.Where("id = ? AND product_id = ?", serviceID, productID).First(service).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: service %d not found on product %d", ErrServiceNotFound, serviceID, productID)
+		}
+		return fmt.Errorf("%w: failed to find service: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
+
+// validateService checks a service has a name and an absolute http(s) link
+func validateService(name, link string) error {
+	if name == "" {
+		return fmt.Errorf("%w: service name cannot be empty", ErrInvalidInput)
+	}
+	parsed, err := url.ParseRequestURI(link)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: service link must be an absolute http(s) URL", ErrInvalidInput)
+	}
+	return nil
+}
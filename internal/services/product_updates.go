@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+)
+
+const (
+	productUpdateSubscriberBuffer = 32
+
+	// MaxProductSubscriptions caps how many products one stream may follow
+	MaxProductSubscriptions = 50
+)
+
+// ProductUpdate is the storefront state of a product after a stock or price
+// change. Available turns false, with the other fields omitted, when the
+// product leaves the storefront: it is deactivated, archived, deleted or
+// hidden while out of stock.
+type ProductUpdate struct {
+	ProductID      uint      `json:"product_id"`
+	Price          *float64  `json:"price,omitempty"`
+	CompareAtPrice *float64  `json:"compare_at_price,omitempty"` // set while a sale runs
+	Stock          *int      `json:"stock,omitempty"`
+	InStock        *bool     `json:"in_stock,omitempty"`
+	Available      *bool     `json:"available,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// ProductViewer is who a product update stream is shaped for: the store
+// resolved for the request (0 in single-tenant mode) and the signed-in user,
+// whose customer group sets the prices (0 for guests)
+type ProductViewer struct {
+	StoreID uint
+	UserID  uint
+}
+
+// ProductUpdateHub turns product and stock events from the EventBus into
+// notifications for storefront pages following a set of product IDs. Each
+// stream reads the changed product back through State, so it only sees what
+// the public product API would show its viewer.
+type ProductUpdateHub struct {
+	products    *ProductService
+	mu          sync.RWMutex
+	subscribers map[chan uint]map[uint]bool
+}
+
+func NewProductUpdateHub(products *ProductService, eventBus *EventBus) *ProductUpdateHub {
+	h := &ProductUpdateHub{products: products, subscribers: make(map[chan uint]map[uint]bool)}
+	if eventBus != nil {
+		eventBus.Subscribe(h.handleEvent)
+	}
+	return h
+}
+
+// Subscribe follows the given products until unsubscribe is called. The
+// channel carries the ID of each product that changed.
+func (h *ProductUpdateHub) Subscribe(productIDs []uint) (<-chan uint, func()) {
+	ids := make(map[uint]bool, len(productIDs))
+	for _, id := range productIDs {
+		ids[id] = true
+	}
+
+	ch := make(chan uint, productUpdateSubscriberBuffer)
+	h.mu.Lock()
+	h.subscribers[ch] = ids
+	h.mu.Unlock()
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		if _, ok := h.subscribers[ch]; ok {
+			delete(h.subscribers, ch)
+			close(ch)
+		}
+		h.mu.Unlock()
+	}
+	return ch, unsubscribe
+}
+
+// State returns the current storefront state of the given products for
+// viewer. As in the public product API, only active products of the
+// viewer's store are included, products that OUT_OF_STOCK_DISPLAY or their
+// own setting hide while out of stock are left out, and prices are those of
+// the viewer's customer group. Products left out are simply missing.
+func (h *ProductUpdateHub) State(ctx context.Context, viewer ProductViewer, productIDs []uint) ([]ProductUpdate, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var products []models.Product
+	err := h.products.db.WithContext(ctx).
+		Where("id IN ? AND status = ?", productIDs, models.ProductStatusActive).
+		Scopes(storeScope(viewer.StoreID), h.products.stockScope(false)).
+		Find(&products).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch products: %v", ErrDatabaseQuery, err)
+	}
+	if err := h.products.ApplyCustomerPricing(ctx, viewer.UserID, products); err != nil {
+		return nil, err
+	}
+	if err := h.products.setAvailability(ctx, products, nil); err != nil {
+		return nil, err
+	}
+
+	updates := make([]ProductUpdate, 0, len(products))
+	for i := range products {
+		updates = append(updates, productUpdateFrom(&products[i]))
+	}
+	return updates, nil
+}
+
+func (h *ProductUpdateHub) handleEvent(event Event) {
+	var productID uint
+	switch event.Type {
+	case EventProductUpdated, EventProductDeleted:
+		var product struct {
+			ID uint `json:"id"`
+		}
+		if err := decodeEventData(event.Data, &product); err != nil {
+			return
+		}
+		productID = product.ID
+	case EventStockChanged:
+		var change struct {
+			ProductID uint `json:"product_id"`
+		}
+		if err := decodeEventData(event.Data, &change); err != nil {
+			return
+		}
+		productID = change.ProductID
+	default:
+		return
+	}
+	if productID == 0 {
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for ch, ids := range h.subscribers {
+		if !ids[productID] {
+			continue
+		}
+		// Slow clients miss updates rather than stalling the bus
+		select {
+		case ch <- productID:
+		default:
+		}
+	}
+}
+
+// UnavailableUpdate reports a product that left the viewer's storefront,
+// e.g. because it was deactivated, deleted or hidden while out of stock
+func UnavailableUpdate(productID uint) ProductUpdate {
+	available := false
+	return ProductUpdate{ProductID: productID, Available: &available, UpdatedAt: time.Now()}
+}
+
+func productUpdateFrom(product *models.Product) ProductUpdate {
+	available := true
+	price, stock := product.Price, product.Stock
+	inStock := product.Availability == models.AvailabilityInStock
+	return ProductUpdate{
+		ProductID:      product.ID,
+		Price:          &price,
+		CompareAtPrice: product.CompareAtPrice,
+		Stock:          &stock,
+		InStock:        &inStock,
+		Available:      &available,
+		UpdatedAt:      product.UpdatedAt,
+	}
+}
+
+// decodeEventData reads an event payload, which is raw JSON when the event
+// came through the outbox and a Go value when published directly.
+func decodeEventData(data interface{}, target interface{}) error {
+	raw, ok := data.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(data); err != nil {
+			return err
+		}
+	}
+	return json.Unmarshal(raw, target)
+}
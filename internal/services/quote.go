@@ -0,0 +1,392 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrQuoteNotFound = errors.New("quote not found")
+	// ErrQuoteState means the quote's status doesn't allow the action, e.g.
+	// accepting a quote that hasn't been priced or has expired
+	ErrQuoteState = errors.New("quote cannot be changed in its current state")
+)
+
+const quoteExpiryInterval = 15 * time.Minute
+
+// QuoteService handles B2B quote requests: customers ask for prices on a
+// list of products, admins answer with negotiated unit prices, and the
+// customer accepts or declines before the quote expires
+type QuoteService struct {
+	db             *gorm.DB
+	cfg            *config.Config
+	productService *ProductService
+	emailService   *EmailService
+}
+
+func NewQuoteService(db *gorm.DB, cfg *config.Config, productService *ProductService, emailService *EmailService) *QuoteService {
+	return &QuoteService{
+		db:             db,
+		cfg:            cfg,
+		productService: productService,
+		emailService:   emailService,
+	}
+}
+
+// CreateQuote records a quote request, pricing each item at what the
+// customer would pay today so admins can see the starting point
+func (s *QuoteService) CreateQuote(ctx context.Context, userID uint, req *models.CreateQuoteRequest) (*models.Quote, error) {
+	quote := models.Quote{
+		UserID:       userID,
+		Status:       models.QuoteStatusRequested,
+		CustomerNote: req.Note,
+	}
+	productIDs := make([]uint, 0, len(req.Items))
+	seen := make(map[uint]bool, len(req.Items))
+	for _, item := range req.Items {
+		if seen[item.ProductID] {
+			return nil, fmt.Errorf("%w: product %d is listed more than once", ErrInvalidInput, item.ProductID)
+		}
+		seen[item.ProductID] = true
+		productIDs = append(productIDs, item.ProductID)
+
+		price, err := s.productService.QuotePrice(ctx, userID, item.ProductID, item.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		productID := item.ProductID
+		quote.Items = append(quote.Items, models.QuoteItem{
+			ProductID:     &productID,
+			Quantity:      item.Quantity,
+			ListUnitPrice: price.UnitPrice,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var products []models.Product
+		if err := tx.Select("id", "title", "sku").Where("id IN ?", productIDs).Find(&products).Error; err != nil {
+			return fmt.Errorf("%w: failed to load products: %v", ErrDatabaseQuery, err)
+		}
+		byID := make(map[uint]models.Product, len(products))
+		for _, product := range products {
+			byID[product.ID] = product
+		}
+		for i := range quote.Items {
+			product := byID[*quote.Items[i].ProductID]
+			quote.Items[i].ProductTitle = product.Title
+			quote.Items[i].SKU = product.SKU
+		}
+		quote.Total = quoteTotal(quote.Items)
+
+		if err := tx.Create(&quote).Error; err != nil {
+			return fmt.Errorf("%w: failed to create quote: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
+
+// GetUserQuotes lists a customer's quotes, newest first
+func (s *QuoteService) GetUserQuotes(ctx context.Context, userID uint, page, limit int) ([]models.Quote, int64, error) {
+	return s.listQuotes(ctx, s.db.Where("user_id = ?", userID), page, limit)
+}
+
+// GetQuotes lists all quotes for admins, optionally filtered by status
+func (s *QuoteService) GetQuotes(ctx context.Context, status string, page, limit int) ([]models.Quote, int64, error) {
+	query := s.db
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	return s.listQuotes(ctx, query.Preload("User"), page, limit)
+}
+
+func (s *QuoteService) listQuotes(ctx context.Context, query *gorm.DB, page, limit int) ([]models.Quote, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	query = query.WithContext(ctx).Model(&models.Quote{})
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to count quotes: %v", ErrDatabaseQuery, err)
+	}
+
+	quotes := []models.Quote{}
+	if err := query.Preload("Items").
+		Order("created_at DESC, id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&quotes).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to fetch quotes: %v", ErrDatabaseQuery, err)
+	}
+	return quotes, total, nil
+}
+
+// GetQuote returns a quote with its items. A non-zero userID restricts the
+// lookup to that customer's quotes.
+func (s *QuoteService) GetQuote(ctx context.Context, userID, quoteID uint) (*models.Quote, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var quote models.Quote
+	if err := findQuote(s.db.WithContext(ctx).Preload("Items").Preload("User"), userID, quoteID, &quote); err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
+
+// GetQuotePDF renders a customer's quote as a PDF
+func (s *QuoteService) GetQuotePDF(ctx context.Context, userID, quoteID uint) ([]byte, error) {
+	quote, err := s.GetQuote(ctx, userID, quoteID)
+	if err != nil {
+		return nil, err
+	}
+	return renderQuotePDF(quote, quoteCustomerName(quote.User)), nil
+}
+
+// RespondToQuote prices a requested quote and emails it to the customer.
+// Items missing from req keep their list price. A quote can be re-priced
+// until the customer decides.
+func (s *QuoteService) RespondToQuote(ctx context.Context, actorID, quoteID uint, req *models.RespondQuoteRequest) (*models.Quote, error) {
+	expiresAt := time.Now().Add(s.cfg.QuoteValidity)
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			return nil, fmt.Errorf("%w: expires_at must be in the future", ErrInvalidInput)
+		}
+		expiresAt = *req.ExpiresAt
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var quote models.Quote
+	err := s.db.WithContext(queryCtx).Transaction(func(tx *gorm.DB) error {
+		if err := findQuote(tx.Clauses(clause.Locking{Strength: "UPDATE"}), 0, quoteID, &quote); err != nil {
+			return err
+		}
+		if quote.Status != models.QuoteStatusRequested && quote.Status != models.QuoteStatusQuoted {
+			return fmt.Errorf("%w: quote is %s", ErrQuoteState, quote.Status)
+		}
+		if err := tx.Where("quote_id = ?", quote.ID).Order("id ASC").Find(&quote.Items).Error; err != nil {
+			return fmt.Errorf("%w: failed to load quote items: %v", ErrDatabaseQuery, err)
+		}
+
+		itemIDs := make(map[uint]bool, len(quote.Items))
+		for _, item := range quote.Items {
+			itemIDs[item.ID] = true
+		}
+		prices := make(map[uint]float64, len(req.Items))
+		for _, price := range req.Items {
+			if !itemIDs[price.ItemID] {
+				return fmt.Errorf("%w: item %d is not part of quote %d", ErrInvalidInput, price.ItemID, quote.ID)
+			}
+			prices[price.ItemID] = price.UnitPrice
+		}
+
+		for i := range quote.Items {
+			item := &quote.Items[i]
+			unitPrice, ok := prices[item.ID]
+			if !ok {
+				unitPrice = item.ListUnitPrice
+			}
+			item.QuotedUnitPrice = &unitPrice
+			if err := tx.Model(item).Update("quoted_unit_price", unitPrice).Error; err != nil {
+				return fmt.Errorf("%w: failed to price quote item: %v", ErrDatabaseQuery, err)
+			}
+		}
+
+		now := time.Now()
+		quote.Status = models.QuoteStatusQuoted
+		quote.AdminNote = req.Note
+		quote.Total = quoteTotal(quote.Items)
+		quote.ExpiresAt = &expiresAt
+		quote.RespondedBy = &actorID
+		quote.RespondedAt = &now
+		if err := tx.Model(&quote).Updates(map[string]interface{}{
+			"status":       quote.Status,
+			"admin_note":   quote.AdminNote,
+			"total":        quote.Total,
+			"expires_at":   quote.ExpiresAt,
+			"responded_by": quote.RespondedBy,
+			"responded_at": quote.RespondedAt,
+		}).Error; err != nil {
+			return fmt.Errorf("%w: failed to update quote: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The quote stands even if the email fails; the customer can still see
+	// and download it from their account
+	var customer models.User
+	if err := s.db.WithContext(queryCtx).First(&customer, quote.UserID).Error; err != nil {
+		logger.Error("Failed to load customer for quote email: ", err)
+		return &quote, nil
+	}
+	pdf := renderQuotePDF(&quote, quoteCustomerName(&customer))
+	if err := s.emailService.SendQuoteEmail(customer.Email, quote.ID, quote.Total, expiresAt, pdf); err != nil {
+		logger.Error("Failed to send quote email: ", err)
+	}
+	return &quote, nil
+}
+
+// RejectQuote closes a quote an admin won't price
+func (s *QuoteService) RejectQuote(ctx context.Context, actorID, quoteID uint, note string) (*models.Quote, error) {
+	return s.decide(ctx, 0, quoteID, models.QuoteStatusRejected, func(tx *gorm.DB, quote *models.Quote) error {
+		if quote.Status != models.QuoteStatusRequested && quote.Status != models.QuoteStatusQuoted {
+			return fmt.Errorf("%w: quote is %s", ErrQuoteState, quote.Status)
+		}
+		quote.AdminNote = note
+		quote.RespondedBy = &actorID
+		return tx.Model(quote).Updates(map[string]interface{}{
+			"admin_note":   note,
+			"responded_by": actorID,
+		}).Error
+	})
+}
+
+// DeclineQuote lets a customer withdraw a request or turn down a priced quote
+func (s *QuoteService) DeclineQuote(ctx context.Context, userID, quoteID uint) (*models.Quote, error) {
+	return s.decide(ctx, userID, quoteID, models.QuoteStatusDeclined, func(tx *gorm.DB, quote *models.Quote) error {
+		if quote.Status != models.QuoteStatusRequested && quote.Status != models.QuoteStatusQuoted {
+			return fmt.Errorf("%w: quote is %s", ErrQuoteState, quote.Status)
+		}
+		return nil
+	})
+}
+
+// AcceptQuote accepts a priced quote before it expires. A quote.accepted
+// event carries the agreed items and prices so they can be turned into an
+// order.
+func (s *QuoteService) AcceptQuote(ctx context.Context, userID, quoteID uint) (*models.Quote, error) {
+	return s.decide(ctx, userID, quoteID, models.QuoteStatusAccepted, func(tx *gorm.DB, quote *models.Quote) error {
+		if quote.Status != models.QuoteStatusQuoted {
+			return fmt.Errorf("%w: quote is %s", ErrQuoteState, quote.Status)
+		}
+		if quote.ExpiresAt != nil && !quote.ExpiresAt.After(time.Now()) {
+			return fmt.Errorf("%w: quote expired at %s", ErrQuoteState, quote.ExpiresAt.UTC().Format(time.RFC3339))
+		}
+		if err := tx.Where("quote_id = ?", quote.ID).Order("id ASC").Find(&quote.Items).Error; err != nil {
+			return fmt.Errorf("%w: failed to load quote items: %v", ErrDatabaseQuery, err)
+		}
+		return recordOutboxEvent(tx, EventQuoteAccepted, "quote", quote.ID, map[string]interface{}{
+			"quote_id": quote.ID,
+			"user_id":  quote.UserID,
+			"items":    quote.Items,
+			"total":    quote.Total,
+		})
+	})
+}
+
+// decide moves a quote to a final status after check approves it. check runs
+// in the transaction with the quote locked.
+func (s *QuoteService) decide(ctx context.Context, userID, quoteID uint, status string, check func(tx *gorm.DB, quote *models.Quote) error) (*models.Quote, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var quote models.Quote
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := findQuote(tx.Clauses(clause.Locking{Strength: "UPDATE"}), userID, quoteID, &quote); err != nil {
+			return err
+		}
+		if err := check(tx, &quote); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		quote.Status = status
+		quote.DecidedAt = &now
+		if err := tx.Model(&quote).Updates(map[string]interface{}{
+			"status":     status,
+			"decided_at": now,
+		}).Error; err != nil {
+			return fmt.Errorf("%w: failed to update quote: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
+
+// Run marks priced quotes past their expiry date as expired until ctx is
+// cancelled
+func (s *QuoteService) Run(ctx context.Context) {
+	ticker := time.NewTicker(quoteExpiryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.expireQuotes(ctx); err != nil {
+				logger.Error("Failed to expire quotes: ", err)
+			}
+		}
+	}
+}
+
+func (s *QuoteService) expireQuotes(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Model(&models.Quote{}).
+		Where("status = ? AND expires_at <= ?", models.QuoteStatusQuoted, time.Now()).
+		Update("status", models.QuoteStatusExpired).Error; err != nil {
+		return fmt.Errorf("%w: failed to expire quotes: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
+
+func findQuote(db *gorm.DB, userID, quoteID uint, quote *models.Quote) error {
+	query := db.Where("id = ?", quoteID)
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if err := query.First(quote).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: quote %d", ErrQuoteNotFound, quoteID)
+		}
+		return fmt.Errorf("%w: failed to find quote: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
+
+// quoteTotal sums the items at their quoted price, or list price until
+// quoted
+func quoteTotal(items []models.QuoteItem) float64 {
+	var total float64
+	for _, item := range items {
+		unitPrice := item.ListUnitPrice
+		if item.QuotedUnitPrice != nil {
+			unitPrice = *item.QuotedUnitPrice
+		}
+		total += unitPrice * float64(item.Quantity)
+	}
+	return math.Round(total*100) / 100
+}
+
+func quoteCustomerName(user *models.User) string {
+	if user == nil {
+		return ""
+	}
+	return inviterName(user)
+}
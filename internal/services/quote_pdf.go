@@ -0,0 +1,51 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+)
+
+// renderQuotePDF lays a priced quote out as a plain A4 PDF in Courier, so
+// the columns line up without font metrics
+func renderQuotePDF(quote *models.Quote, customer string) []byte {
+	lines := []string{
+		fmt.Sprintf("QUOTE #%d", quote.ID),
+		"",
+		"Customer: " + customer,
+		"Date:     " + quote.CreatedAt.UTC().Format("January 2, 2006"),
+	}
+	if quote.ExpiresAt != nil {
+		lines = append(lines, "Valid to: "+quote.ExpiresAt.UTC().Format("January 2, 2006 15:04 MST"))
+	}
+	lines = append(lines, "",
+		fmt.Sprintf("%-40s %6s %12s %12s", "Product", "Qty", "Unit price", "Amount"),
+		strings.Repeat("-", 73))
+
+	for _, item := range quote.Items {
+		unitPrice := item.ListUnitPrice
+		if item.QuotedUnitPrice != nil {
+			unitPrice = *item.QuotedUnitPrice
+		}
+		title := item.ProductTitle
+		if item.SKU != "" {
+			title += " (" + item.SKU + ")"
+		}
+		if runes := []rune(title); len(runes) > 40 {
+			title = string(runes[:39]) + "~"
+		}
+		lines = append(lines, fmt.Sprintf("%-40s %6d %12.2f %12.2f",
+			title, item.Quantity, unitPrice, unitPrice*float64(item.Quantity)))
+	}
+	lines = append(lines, strings.Repeat("-", 73), fmt.Sprintf("%60s %12.2f", "Total", quote.Total))
+
+	if quote.AdminNote != "" {
+		lines = append(lines, "", "Notes:")
+		lines = append(lines, wrapText(quote.AdminNote, 73)...)
+	}
+	lines = append(lines, "", "Generated "+time.Now().UTC().Format(time.RFC1123))
+
+	return buildTextPDF(lines)
+}
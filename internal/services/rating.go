@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// RatingService keeps the denormalized rating columns on products. The
+// ranking score is a Bayesian average: each product's published ratings are
+// blended with priorWeight virtual reviews at the catalogue-wide mean, so a
+// single 5-star review does not outrank hundreds averaging 4.8. Products with
+// fewer than minReviews published reviews score 0 and sort last.
+type RatingService struct {
+	db          *gorm.DB
+	priorWeight float64
+	minReviews  int
+}
+
+func NewRatingService(db *gorm.DB, priorWeight, minReviews int) *RatingService {
+	if priorWeight < 0 {
+		priorWeight = 0
+	}
+	return &RatingService{db: db, priorWeight: float64(priorWeight), minReviews: minReviews}
+}
+
+// Refresh recomputes the rating of one product. Call it in the transaction
+// that changed one of the product's reviews.
+func (s *RatingService) Refresh(tx *gorm.DB, productID uint) error {
+	return s.refresh(tx, &productID)
+}
+
+// RefreshAll recomputes every product, so scores follow the catalogue mean
+// as it drifts
+func (s *RatingService) RefreshAll(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+	return s.refresh(s.db.WithContext(ctx), nil)
+}
+
+// Run refreshes all ratings at startup, which also fills in products from
+// before ratings were stored, then on every interval until the context is
+// cancelled
+func (s *RatingService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	if err := s.RefreshAll(ctx); err != nil {
+		logger.Error("Failed to refresh product ratings: ", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.RefreshAll(ctx); err != nil {
+				logger.Error("Failed to refresh product ratings: ", err)
+			}
+		}
+	}
+}
+
+func (s *RatingService) refresh(db *gorm.DB, productID *uint) error {
+	var mean float64
+	err := db.Model(&models.Review{}).
+		Where("visibility = ?", models.ReviewVisibilityPublished).
+		Select("COALESCE(AVG(rating), 0)").
+		Scan(&mean).Error
+	if err != nil {
+		return fmt.Errorf("%w: failed to compute mean rating: %v", ErrDatabaseQuery, err)
+	}
+
+	sql := `UPDATE products SET
+		avg_rating = COALESCE(r.average, 0),
+		rating_count = COALESCE(r.total, 0),
+		rating_score = CASE WHEN COALESCE(r.total, 0) < @min_reviews OR COALESCE(r.total, 0) = 0 THEN 0
+			ELSE (@weight * @mean + r.sum) / (@weight + r.total) END
+	FROM products p
+	LEFT JOIN (
+		SELECT product_id, COUNT(*) AS total, AVG(rating) AS average, SUM(rating) AS sum
+		FROM reviews WHERE visibility = @published GROUP BY product_id
+	) r ON r.product_id = p.id
+	WHERE products.id = p.id`
+	args := map[string]interface{}{
+		"min_reviews": s.minReviews,
+		"weight":      s.priorWeight,
+		"mean":        mean,
+		"published":   models.ReviewVisibilityPublished,
+	}
+	if productID != nil {
+		sql += " AND p.id = @product_id"
+		args["product_id"] = *productID
+	}
+
+	if err := db.Exec(sql, args).Error; err != nil {
+		return fmt.Errorf("%w: failed to update product ratings: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
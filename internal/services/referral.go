@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrReferralCodeNotFound is returned when a signup names a referral code
+// that does not exist
+var ErrReferralCodeNotFound = errors.New("referral code not found")
+
+const (
+	referralCodeLength = 8
+	// referralCodeAlphabet leaves out characters that are easily misread
+	referralCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+// ReferralService hands out referral codes and reports on the signups they
+// brought in. Signups are attributed by AuthService.Signup through
+// recordReferral.
+type ReferralService struct {
+	db *gorm.DB
+}
+
+func NewReferralService(db *gorm.DB) *ReferralService {
+	return &ReferralService{db: db}
+}
+
+// GetMyReferral returns the user's referral code, creating it on first use,
+// with the number of signups it brought in. The IP and device of the request
+// are remembered for the self-referral checks.
+func (s *ReferralService) GetMyReferral(ctx context.Context, userID uint, ipAddress, deviceID string) (*models.ReferralSummary, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+	db := s.db.WithContext(ctx)
+
+	var code models.ReferralCode
+	err := db.Where("user_id = ?", userID).First(&code).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if code, err = s.createCode(db, userID, ipAddress, deviceID); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, fmt.Errorf("%w: failed to find referral code: %v", ErrDatabaseQuery, err)
+	default:
+		updates := map[string]interface{}{"last_ip_address": ipAddress}
+		if deviceID != "" {
+			updates["last_device_id"] = deviceID
+		}
+		if err := db.Model(&code).Updates(updates).Error; err != nil {
+			return nil, fmt.Errorf("%w: failed to update referral code: %v", ErrDatabaseQuery, err)
+		}
+	}
+
+	summary := &models.ReferralSummary{Code: code.Code}
+	if err := db.Model(&models.Referral{}).Where("referrer_id = ?", userID).Count(&summary.Referrals).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to count referrals: %v", ErrDatabaseQuery, err)
+	}
+	return summary, nil
+}
+
+// createCode stores a new random code for userID, retrying on the rare
+// collision. A concurrent request creating the same user's code wins.
+func (s *ReferralService) createCode(db *gorm.DB, userID uint, ipAddress, deviceID string) (models.ReferralCode, error) {
+	for attempt := 0; attempt < 5; attempt++ {
+		value, err := generateReferralCode()
+		if err != nil {
+			return models.ReferralCode{}, err
+		}
+		code := models.ReferralCode{UserID: userID, Code: value, LastIPAddress: ipAddress, LastDeviceID: deviceID}
+		res := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "user_id"}}, DoNothing: true}).Create(&code)
+		if res.Error != nil {
+			if isUniqueViolation(res.Error) {
+				continue
+			}
+			return models.ReferralCode{}, fmt.Errorf("%w: failed to create referral code: %v", ErrDatabaseQuery, res.Error)
+		}
+		if res.RowsAffected == 0 {
+			if err := db.Where("user_id = ?", userID).First(&code).Error; err != nil {
+				return models.ReferralCode{}, fmt.Errorf("%w: failed to find referral code: %v", ErrDatabaseQuery, err)
+			}
+		}
+		return code, nil
+	}
+	return models.ReferralCode{}, fmt.Errorf("%w: failed to generate a unique referral code", ErrDatabaseQuery)
+}
+
+func generateReferralCode() (string, error) {
+	var b strings.Builder
+	max := big.NewInt(int64(len(referralCodeAlphabet)))
+	for i := 0; i < referralCodeLength; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate referral code: %v", err)
+		}
+		b.WriteByte(referralCodeAlphabet[n.Int64()])
+	}
+	return b.String(), nil
+}
+
+// findReferralCode looks up a code as typed by a customer
+func findReferralCode(db *gorm.DB, value string) (*models.ReferralCode, error) {
+	var code models.ReferralCode
+	if err := db.Where("code = ?", strings.ToUpper(strings.TrimSpace(value))).First(&code).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReferralCodeNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to find referral code: %v", ErrDatabaseQuery, err)
+	}
+	return &code, nil
+}
+
+// recordReferral attributes the new user to the referral code they signed
+// up with, flagging signups that share an IP or device with the referrer or
+// with an earlier signup on the same code. Call it in the signup
+// transaction.
+func recordReferral(tx *gorm.DB, code *models.ReferralCode, referredID uint, signup SignupOrigin) error {
+	referral := models.Referral{
+		ReferrerID: code.UserID,
+		ReferredID: referredID,
+		Code:       code.Code,
+		IPAddress:  signup.IPAddress,
+		DeviceID:   signup.DeviceID,
+		UserAgent:  signup.UserAgent,
+		Flags:      []string{},
+	}
+	if signup.IPAddress != "" && signup.IPAddress == code.LastIPAddress {
+		referral.Flags = append(referral.Flags, models.ReferralFlagSameIP)
+	}
+	if signup.DeviceID != "" && signup.DeviceID == code.LastDeviceID {
+		referral.Flags = append(referral.Flags, models.ReferralFlagSameDevice)
+	}
+	if signup.IPAddress != "" {
+		var repeats int64
+		if err := tx.Model(&models.Referral{}).Where("referrer_id = ? AND ip_address = ?", code.UserID, signup.IPAddress).Count(&repeats).Error; err != nil {
+			return fmt.Errorf("%w: failed to check referral IP: %v", ErrDatabaseQuery, err)
+		}
+		if repeats > 0 {
+			referral.Flags = append(referral.Flags, models.ReferralFlagRepeatIP)
+		}
+	}
+	referral.Flagged = len(referral.Flags) > 0
+
+	if err := tx.Create(&referral).Error; err != nil {
+		return fmt.Errorf("%w: failed to record referral: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
+
+// GetReferrals lists referred signups, newest first, optionally only those
+// flagged by the fraud checks or those of one referrer
+func (s *ReferralService) GetReferrals(ctx context.Context, flaggedOnly bool, referrerID uint, page, limit int) ([]models.Referral, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	query := s.db.WithContext(ctx).Model(&models.Referral{})
+	if flaggedOnly {
+		query = query.Where("flagged = ?", true)
+	}
+	if referrerID != 0 {
+		query = query.Where("referrer_id = ?", referrerID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to count referrals: %v", ErrDatabaseQuery, err)
+	}
+
+	referrals := make([]models.Referral, 0)
+	if err := query.Preload("Referrer").Preload("Referred").
+		Order("id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&referrals).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to fetch referrals: %v", ErrDatabaseQuery, err)
+	}
+	return referrals, total, nil
+}
+
+// GetReferralReport ranks referrers by the signups they brought in, with how
+// many of those were flagged
+func (s *ReferralService) GetReferralReport(ctx context.Context, page, limit int) ([]models.ReferrerStats, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+	db := s.db.WithContext(ctx)
+
+	var total int64
+	if err := db.Model(&models.Referral{}).Distinct("referrer_id").Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to count referrers: %v", ErrDatabaseQuery, err)
+	}
+
+	stats := make([]models.ReferrerStats, 0)
+	err := db.Table("referrals r").
+		Select("r.referrer_id, u.email, COALESCE(c.code, '') AS code, COUNT(*) AS referrals, " +
+			"COUNT(*) FILTER (WHERE r.flagged) AS flagged, MIN(r.created_at) AS first_referral, MAX(r.created_at) AS latest_referral").
+		Joins("JOIN users u ON u.id = r.referrer_id").
+		Joins("LEFT JOIN referral_codes c ON c.user_id = r.referrer_id").
+		Group("r.referrer_id, u.email, c.code").
+		Order("referrals DESC, r.referrer_id").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to build referral report: %v", ErrDatabaseQuery, err)
+	}
+	return stats, total, nil
+}
@@ -0,0 +1,586 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+)
+
+var ErrReportNotFound = errors.New("report not found")
+
+const (
+	reportURLExpiry = 15 * time.Minute
+	reportPrefix    = "reports/"
+)
+
+// ReportTypes lists the canned reports
+var ReportTypes = []string{
+	models.ReportInventoryValuation,
+	models.ReportSlowMovingStock,
+	models.ReportReviewSentiment,
+	models.ReportUserGrowth,
+}
+
+// reportTable is a generated report before it is written out as CSV or PDF
+type reportTable struct {
+	title   string
+	columns []string
+	rows    [][]string
+	summary []string // lines shown under the table in PDFs
+}
+
+// ReportService generates canned admin reports as CSV or PDF files in S3,
+// on demand or on the REPORT_SCHEDULE
+type ReportService struct {
+	db         *gorm.DB
+	cfg        *config.Config
+	s3Service  *S3Service
+	jobService *JobService
+}
+
+func NewReportService(db *gorm.DB, cfg *config.Config, s3Service *S3Service, jobService *JobService) *ReportService {
+	return &ReportService{
+		db:         db,
+		cfg:        cfg,
+		s3Service:  s3Service,
+		jobService: jobService,
+	}
+}
+
+// Run generates the scheduled reports every REPORT_INTERVAL until ctx is
+// cancelled. Schedule entries are a report type, optionally with a format:
+// "user_growth" or "inventory_valuation:pdf".
+func (s *ReportService) Run(ctx context.Context) {
+	if len(s.cfg.ReportSchedule) == 0 {
+		return
+	}
+	interval := s.cfg.ReportInterval
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, entry := range s.cfg.ReportSchedule {
+				reportType, format, _ := strings.Cut(entry, ":")
+				if _, err := s.GenerateReport(ctx, 0, "schedule", reportType, format); err != nil {
+					logger.Error("Failed to start scheduled report ", entry, ": ", err)
+				}
+			}
+		}
+	}
+}
+
+// GenerateReport records a report and builds it in a background job. The
+// report is listed straight away and completes when the job does.
+func (s *ReportService) GenerateReport(ctx context.Context, userID uint, trigger, reportType, format string) (*models.Report, error) {
+	if !containsString(ReportTypes, reportType) {
+		return nil, fmt.Errorf("%w: unknown report type %q", ErrInvalidInput, reportType)
+	}
+	if format == "" {
+		format = models.ReportFormatCSV
+	}
+	if format != models.ReportFormatCSV && format != models.ReportFormatPDF {
+		return nil, fmt.Errorf("%w: format must be csv or pdf", ErrInvalidInput)
+	}
+
+	report := models.Report{
+		Type:    reportType,
+		Format:  format,
+		Status:  models.ReportStatusPending,
+		Trigger: trigger,
+	}
+	if userID != 0 {
+		report.CreatedBy = &userID
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := s.db.WithContext(queryCtx).Create(&report).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to create report: %v", ErrDatabaseQuery, err)
+	}
+
+	reportID := report.ID
+	job, err := s.jobService.Enqueue(queryCtx, models.JobTypeReport, userID, func(ctx context.Context, run *JobRun) error {
+		return s.buildReport(ctx, run, reportID)
+	})
+	if err != nil {
+		s.finishReport(reportID, map[string]interface{}{"status": models.ReportStatusFailed, "error": err.Error()})
+		return nil, err
+	}
+
+	report.JobID = &job.ID
+	if err := s.db.WithContext(queryCtx).Model(&models.Report{}).Where("id = ?", reportID).Update("job_id", job.ID).Error; err != nil {
+		logger.Error("Failed to link report to its job: ", err)
+	}
+	return &report, nil
+}
+
+// GetReports lists generated reports, newest first, with download links for
+// the completed ones
+func (s *ReportService) GetReports(ctx context.Context, reportType string, page, limit int) ([]models.Report, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	query := s.db.WithContext(ctx).Model(&models.Report{})
+	if reportType != "" {
+		query = query.Where("type = ?", reportType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to count reports: %v", ErrDatabaseQuery, err)
+	}
+
+	reports := []models.Report{}
+	if err := query.Order("created_at DESC, id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&reports).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to fetch reports: %v", ErrDatabaseQuery, err)
+	}
+	for i := range reports {
+		s.signReport(&reports[i])
+	}
+	return reports, total, nil
+}
+
+func (s *ReportService) GetReport(ctx context.Context, id uint) (*models.Report, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var report models.Report
+	if err := s.db.WithContext(ctx).First(&report, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: report %d", ErrReportNotFound, id)
+		}
+		return nil, fmt.Errorf("%w: failed to fetch report: %v", ErrDatabaseQuery, err)
+	}
+	s.signReport(&report)
+	return &report, nil
+}
+
+// DeleteReport removes a report and its file
+func (s *ReportService) DeleteReport(ctx context.Context, id uint) error {
+	report, err := s.GetReport(ctx, id)
+	if err != nil {
+		return err
+	}
+	if report.FileKey != "" {
+		if err := s.s3Service.DeleteImage(report.FileKey); err != nil {
+			return fmt.Errorf("failed to delete report file: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Delete(&models.Report{}, id).Error; err != nil {
+		return fmt.Errorf("%w: failed to delete report: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
+
+func (s *ReportService) signReport(report *models.Report) {
+	if report.Status != models.ReportStatusCompleted || report.FileKey == "" {
+		return
+	}
+	url, err := s.s3Service.PresignGetURL(report.FileKey, reportURLExpiry)
+	if err != nil {
+		logger.Error("Failed to sign report URL: ", err)
+		return
+	}
+	report.DownloadURL = url
+}
+
+// buildReport runs inside the report's job
+func (s *ReportService) buildReport(ctx context.Context, run *JobRun, reportID uint) error {
+	var report models.Report
+	if err := s.db.WithContext(ctx).First(&report, reportID).Error; err != nil {
+		return fmt.Errorf("%w: failed to load report: %v", ErrDatabaseQuery, err)
+	}
+	s.finishReport(reportID, map[string]interface{}{"status": models.ReportStatusRunning})
+	run.Infof("Generating %s report as %s", report.Type, report.Format)
+
+	data, rows, err := s.renderReport(ctx, report.Type, report.Format)
+	if err != nil {
+		s.finishReport(reportID, map[string]interface{}{"status": models.ReportStatusFailed, "error": err.Error()})
+		return err
+	}
+	run.SetProgress(50)
+
+	contentType := "text/csv"
+	if report.Format == models.ReportFormatPDF {
+		contentType = "application/pdf"
+	}
+	key := fmt.Sprintf("%s%s/%s-%d.%s", reportPrefix, report.Type, time.Now().Format("20060102-150405"), report.ID, report.Format)
+	if err := s.s3Service.PutObject(key, contentType, data); err != nil {
+		s.finishReport(reportID, map[string]interface{}{"status": models.ReportStatusFailed, "error": err.Error()})
+		return err
+	}
+
+	s.finishReport(reportID, map[string]interface{}{
+		"status":      models.ReportStatusCompleted,
+		"rows":        rows,
+		"file_key":    key,
+		"size":        len(data),
+		"finished_at": time.Now(),
+	})
+	run.SetResult(fmt.Sprintf("Report #%d: %d rows in %s", report.ID, rows, key), "")
+	return nil
+}
+
+// finishReport saves a status change outside any request context, so it
+// lands even if the caller's context is gone
+func (s *ReportService) finishReport(reportID uint, updates map[string]interface{}) {
+	if status, _ := updates["status"].(string); status == models.ReportStatusFailed {
+		updates["finished_at"] = time.Now()
+	}
+	if err := s.db.Model(&models.Report{}).Where("id = ?", reportID).Updates(updates).Error; err != nil {
+		logger.Error("Failed to update report: ", err)
+	}
+}
+
+// renderReport builds a report and writes it in format, returning the file
+// and its number of data rows
+func (s *ReportService) renderReport(ctx context.Context, reportType, format string) ([]byte, int, error) {
+	var table *reportTable
+	var err error
+	switch reportType {
+	case models.ReportInventoryValuation:
+		table, err = s.inventoryValuation(ctx)
+	case models.ReportSlowMovingStock:
+		table, err = s.slowMovingStock(ctx)
+	case models.ReportReviewSentiment:
+		table, err = s.reviewSentiment(ctx)
+	case models.ReportUserGrowth:
+		table, err = s.userGrowth(ctx)
+	default:
+		err = fmt.Errorf("%w: unknown report type %q", ErrInvalidInput, reportType)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if format == models.ReportFormatPDF {
+		return renderTablePDF(table), len(table.rows), nil
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write(table.columns)
+	writer.WriteAll(table.rows)
+	if err := writer.Error(); err != nil {
+		return nil, 0, fmt.Errorf("failed to write CSV: %v", err)
+	}
+	return buf.Bytes(), len(table.rows), nil
+}
+
+// inventoryValuation values the stock of every product that isn't archived
+// at its current price
+func (s *ReportService) inventoryValuation(ctx context.Context) (*reportTable, error) {
+	type row struct {
+		ID       uint
+		SKU      string
+		Title    string
+		Category string
+		Stock    int
+		Price    float64
+	}
+	var products []row
+	if err := s.db.WithContext(ctx).Model(&models.Product{}).
+		Select("id", "sku", "title", "category", "stock", "price").
+		Where("status <> ?", models.ProductStatusArchived).
+		Order("category ASC, title ASC").
+		Scan(&products).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to read products: %v", ErrDatabaseQuery, err)
+	}
+
+	table := &reportTable{
+		title:   "Inventory valuation",
+		columns: []string{"product_id", "sku", "title", "category", "stock", "unit_price", "value"},
+	}
+	var units int
+	var value float64
+	for _, p := range products {
+		units += p.Stock
+		value += float64(p.Stock) * p.Price
+		table.rows = append(table.rows, []string{
+			strconv.FormatUint(uint64(p.ID), 10),
+			p.SKU,
+			p.Title,
+			p.Category,
+			strconv.Itoa(p.Stock),
+			formatAmount(p.Price),
+			formatAmount(float64(p.Stock) * p.Price),
+		})
+	}
+	table.summary = []string{
+		fmt.Sprintf("Products: %d", len(products)),
+		fmt.Sprintf("Units in stock: %d", units),
+		fmt.Sprintf("Total value: %s", formatAmount(value)),
+	}
+	return table, nil
+}
+
+// slowMovingStock lists active products with stock that haven't sold online
+// within REPORT_SLOW_MOVING_DAYS, ignoring products listed more recently
+func (s *ReportService) slowMovingStock(ctx context.Context) (*reportTable, error) {
+	days := s.cfg.ReportSlowMovingDays
+	if days <= 0 {
+		days = 90
+	}
+	since := time.Now().AddDate(0, 0, -days)
+
+	type row struct {
+		ID         uint
+		SKU        string
+		Title      string
+		Stock      int
+		Price      float64
+		CreatedAt  time.Time
+		LastSaleAt *time.Time
+	}
+	var products []row
+	if err := s.db.WithContext(ctx).Raw(`
+		SELECT p.id, p.sku, p.title, p.stock, p.price, p.created_at,
+			(SELECT MAX(m.created_at) FROM stock_movements_all m
+				WHERE m.product_id = p.id AND m.reason = ? AND m.location_id IS NULL) AS last_sale_at
+		FROM products p
+		WHERE p.status = ? AND p.stock > 0 AND p.created_at < ?
+			AND NOT EXISTS (SELECT 1 FROM stock_movements_all m
+				WHERE m.product_id = p.id AND m.reason = ? AND m.location_id IS NULL AND m.created_at >= ?)
+		ORDER BY last_sale_at ASC NULLS FIRST, p.stock * p.price DESC`,
+		models.StockReasonSale, models.ProductStatusActive, since, models.StockReasonSale, since).
+		Scan(&products).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to read slow-moving stock: %v", ErrDatabaseQuery, err)
+	}
+
+	table := &reportTable{
+		title:   fmt.Sprintf("Slow-moving stock (no sales in %d days)", days),
+		columns: []string{"product_id", "sku", "title", "stock", "value", "last_sale", "days_since_sale"},
+	}
+	var value float64
+	for _, p := range products {
+		value += float64(p.Stock) * p.Price
+		lastSale, daysSince := "never", ""
+		if p.LastSaleAt != nil {
+			lastSale = p.LastSaleAt.UTC().Format("2006-01-02")
+			daysSince = strconv.Itoa(int(time.Since(*p.LastSaleAt).Hours() / 24))
+		}
+		table.rows = append(table.rows, []string{
+			strconv.FormatUint(uint64(p.ID), 10),
+			p.SKU,
+			p.Title,
+			strconv.Itoa(p.Stock),
+			formatAmount(float64(p.Stock) * p.Price),
+			lastSale,
+			daysSince,
+		})
+	}
+	table.summary = []string{
+		fmt.Sprintf("Products: %d", len(products)),
+		fmt.Sprintf("Value tied up: %s", formatAmount(value)),
+	}
+	return table, nil
+}
+
+// reviewSentiment summarises published reviews per product by star rating:
+// 4-5 stars count as positive, 3 as neutral and 1-2 as negative. Products
+// with the largest negative share come first.
+func (s *ReportService) reviewSentiment(ctx context.Context) (*reportTable, error) {
+	type row struct {
+		ProductID uint
+		Title     string
+		Reviews   int
+		AvgRating float64
+		Positive  int
+		Neutral   int
+		Negative  int
+	}
+	var products []row
+	if err := s.db.WithContext(ctx).Raw(`
+		SELECT r.product_id, p.title, COUNT(*) AS reviews, AVG(r.rating) AS avg_rating,
+			COUNT(*) FILTER (WHERE r.rating >= 4) AS positive,
+			COUNT(*) FILTER (WHERE r.rating = 3) AS neutral,
+			COUNT(*) FILTER (WHERE r.rating <= 2) AS negative
+		FROM reviews r
+		JOIN products p ON p.id = r.product_id
+		WHERE r.visibility = ?
+		GROUP BY r.product_id, p.title
+		ORDER BY COUNT(*) FILTER (WHERE r.rating <= 2)::float / COUNT(*) DESC, COUNT(*) DESC`,
+		models.ReviewVisibilityPublished).
+		Scan(&products).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to read reviews: %v", ErrDatabaseQuery, err)
+	}
+
+	table := &reportTable{
+		title:   "Review sentiment by product",
+		columns: []string{"product_id", "title", "reviews", "avg_rating", "positive", "neutral", "negative", "negative_pct"},
+	}
+	var reviews, positive, neutral, negative int
+	for _, p := range products {
+		reviews += p.Reviews
+		positive += p.Positive
+		neutral += p.Neutral
+		negative += p.Negative
+		table.rows = append(table.rows, []string{
+			strconv.FormatUint(uint64(p.ProductID), 10),
+			p.Title,
+			strconv.Itoa(p.Reviews),
+			strconv.FormatFloat(p.AvgRating, 'f', 2, 64),
+			strconv.Itoa(p.Positive),
+			strconv.Itoa(p.Neutral),
+			strconv.Itoa(p.Negative),
+			percent(p.Negative, p.Reviews),
+		})
+	}
+	table.summary = []string{
+		fmt.Sprintf("Published reviews: %d across %d products", reviews, len(products)),
+		fmt.Sprintf("Positive %s, neutral %s, negative %s",
+			percent(positive, reviews), percent(neutral, reviews), percent(negative, reviews)),
+	}
+	return table, nil
+}
+
+// userGrowth counts sign-ups per month over the last year
+func (s *ReportService) userGrowth(ctx context.Context) (*reportTable, error) {
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -11, 0)
+
+	db := s.db.WithContext(ctx)
+	var before int64
+	if err := db.Model(&models.User{}).Where("created_at < ?", start).Count(&before).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to count users: %v", ErrDatabaseQuery, err)
+	}
+
+	type row struct {
+		Month     time.Time
+		Customers int
+		Staff     int
+	}
+	var months []row
+	if err := db.Raw(`
+		SELECT date_trunc('month', created_at) AS month,
+			COUNT(*) FILTER (WHERE role = 'customer') AS customers,
+			COUNT(*) FILTER (WHERE role <> 'customer') AS staff
+		FROM users
+		WHERE created_at >= ?
+		GROUP BY 1
+		ORDER BY 1`, start).
+		Scan(&months).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to count sign-ups: %v", ErrDatabaseQuery, err)
+	}
+	byMonth := make(map[string]row, len(months))
+	for _, m := range months {
+		byMonth[m.Month.UTC().Format("2006-01")] = m
+	}
+
+	table := &reportTable{
+		title:   "User growth (last 12 months)",
+		columns: []string{"month", "new_customers", "new_staff", "total_users", "growth_pct"},
+	}
+	total := int(before)
+	for month := start; !month.After(now); month = month.AddDate(0, 1, 0) {
+		m := byMonth[month.Format("2006-01")]
+		previous := total
+		total += m.Customers + m.Staff
+		table.rows = append(table.rows, []string{
+			month.Format("2006-01"),
+			strconv.Itoa(m.Customers),
+			strconv.Itoa(m.Staff),
+			strconv.Itoa(total),
+			percent(total-previous, previous),
+		})
+	}
+	table.summary = []string{
+		fmt.Sprintf("Users at start of period: %d", before),
+		fmt.Sprintf("Users now: %d", total),
+	}
+	return table, nil
+}
+
+// renderTablePDF lays a report table out in fixed-width columns, shrinking
+// the widest columns until a row fits on the page
+func renderTablePDF(table *reportTable) []byte {
+	widths := make([]int, len(table.columns))
+	for i, column := range table.columns {
+		widths[i] = len(column)
+	}
+	for _, row := range table.rows {
+		for i, cell := range row {
+			if n := len([]rune(cell)); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	for {
+		lineWidth := len(widths) - 1
+		widest := 0
+		for i, width := range widths {
+			lineWidth += width
+			if width > widths[widest] {
+				widest = i
+			}
+		}
+		if lineWidth <= pdfLineWidth || widths[widest] <= 4 {
+			break
+		}
+		widths[widest]--
+	}
+
+	formatRow := func(cells []string) string {
+		parts := make([]string, len(cells))
+		for i, cell := range cells {
+			if runes := []rune(cell); len(runes) > widths[i] {
+				cell = string(runes[:widths[i]-1]) + "~"
+			}
+			parts[i] = fmt.Sprintf("%-*s", widths[i], cell)
+		}
+		return strings.TrimRight(strings.Join(parts, " "), " ")
+	}
+
+	rule := strings.Repeat("-", pdfLineWidth)
+	lines := []string{
+		strings.ToUpper(table.title),
+		"Generated " + time.Now().UTC().Format(time.RFC1123),
+		"",
+		formatRow(table.columns),
+		rule,
+	}
+	for _, row := range table.rows {
+		lines = append(lines, formatRow(row))
+	}
+	lines = append(lines, rule)
+	lines = append(lines, table.summary...)
+	return buildTextPDF(lines)
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+// percent formats part as a percentage of whole, or "-" when whole is zero
+func percent(part, whole int) string {
+	if whole == 0 {
+		return "-"
+	}
+	return strconv.FormatFloat(float64(part)*100/float64(whole), 'f', 1, 64) + "%"
+}
@@ -0,0 +1,105 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/database"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/testutil"
+)
+
+// TestRequestTransaction checks that services join a transaction carried by
+// the context, as middleware.Transaction sets up, so writes made through
+// several services commit or roll back together
+func TestRequestTransaction(t *testing.T) {
+	env := testutil.NewEnv(t)
+	stock := services.NewStockService(env.DB)
+	carts := services.NewCartService(env.DB, services.NewProductService(env.DB, env.Config, nil), stock)
+
+	// writeBoth adds product to user's cart and takes one unit of its stock
+	writeBoth := func(ctx context.Context, user *models.User, product *models.Product) error {
+		owner := services.CartOwner{UserID: user.ID}
+		if _, err := carts.AddItem(ctx, owner, &models.CartItemRequest{ProductID: product.ID, Quantity: 1}); err != nil {
+			return err
+		}
+		_, err := stock.AdjustStock(ctx, product.ID, user.ID, &models.StockAdjustmentRequest{Delta: -1, Reason: models.StockReasonSale})
+		return err
+	}
+
+	tests := []struct {
+		name   string
+		commit bool
+	}{
+		{"rollback undoes every service's writes", false},
+		{"commit keeps every service's writes", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := env.CreateUser(t)
+			product := env.CreateProduct(t, 12)
+			var before models.Product
+			if err := env.DB.First(&before, product.ID).Error; err != nil {
+				t.Fatalf("failed to load product: %v", err)
+			}
+
+			tx := env.DB.Begin()
+			if err := writeBoth(database.ContextWithTx(context.Background(), tx), user, product); err != nil {
+				tx.Rollback()
+				t.Fatalf("write failed: %v", err)
+			}
+			if tt.commit {
+				if err := tx.Commit().Error; err != nil {
+					t.Fatalf("commit failed: %v", err)
+				}
+			} else {
+				tx.Rollback()
+			}
+
+			var carts, movements int64
+			env.DB.Model(&models.Cart{}).Where("user_id = ?", user.ID).Count(&carts)
+			env.DB.Model(&models.StockMovement{}).Where("product_id = ?", product.ID).Count(&movements)
+			var after models.Product
+			if err := env.DB.First(&after, product.ID).Error; err != nil {
+				t.Fatalf("failed to load product: %v", err)
+			}
+
+			want := int64(0)
+			wantStock := before.Stock
+			if tt.commit {
+				want, wantStock = 1, before.Stock-1
+			}
+			if carts != want || movements != want || after.Stock != wantStock {
+				t.Fatalf("got %d carts, %d stock movements and stock %d, want %d, %d and %d", carts, movements, after.Stock, want, want, wantStock)
+			}
+		})
+	}
+
+	t.Run("a failed service write only undoes its own savepoint", func(t *testing.T) {
+		user := env.CreateUser(t)
+		product := env.CreateProduct(t, 12)
+
+		tx := env.DB.Begin()
+		ctx := database.ContextWithTx(context.Background(), tx)
+		if _, err := carts.AddItem(ctx, services.CartOwner{UserID: user.ID}, &models.CartItemRequest{ProductID: product.ID, Quantity: 1}); err != nil {
+			tx.Rollback()
+			t.Fatalf("add to cart failed: %v", err)
+		}
+		if _, err := stock.DecrementStock(ctx, product.ID, 1_000_000, "test"); !errors.Is(err, services.ErrInsufficientStock) {
+			tx.Rollback()
+			t.Fatalf("expected insufficient stock, got %v", err)
+		}
+		if err := tx.Commit().Error; err != nil {
+			t.Fatalf("commit after a failed savepoint failed: %v", err)
+		}
+
+		var carts int64
+		env.DB.Model(&models.Cart{}).Where("user_id = ?", user.ID).Count(&carts)
+		if carts != 1 {
+			t.Fatalf("expected the cart write to survive, got %d carts", carts)
+		}
+	})
+}
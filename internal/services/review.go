@@ -2,25 +2,49 @@ package services
 
 import (
 	"errors"
+	"fmt"
 
+	"github.com/princeprakhar/ecommerce-backend/internal/database"
 	"github.com/princeprakhar/ecommerce-backend/internal/models"
 	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+	"github.com/princeprakhar/ecommerce-backend/pkg/metrics"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
 	"time"
 )
 
+var (
+	ErrReviewNotFound          = errors.New("review not found")
+	ErrInvalidVisibilityChange = errors.New("invalid visibility change")
+	ErrReviewForbidden         = errors.New("only the author or an admin can change this review")
+)
+
 type ReviewService struct {
-	db *gorm.DB
+	db       *gorm.DB
+	ratings  *RatingService
+	counters *CounterService
 }
 
-func NewReviewService(db *gorm.DB) *ReviewService {
-	return &ReviewService{db: db}
+// NewReviewService keeps product ratings current through ratings whenever a
+// review is written, edited, deleted or moderated, and like counts through
+// counters whenever a product or review is liked
+func NewReviewService(db *gorm.DB, ratings *RatingService, counters *CounterService) *ReviewService {
+	return &ReviewService{db: db, ratings: ratings, counters: counters}
 }
 
 type CreateReviewRequest struct {
-	ProductID uint   `json:"product_id" binding:"required"`
-	Rating    int    `json:"rating"`
-	Comment   string `json:"comment"`
+	ProductID   uint   `json:"product_id" binding:"required"`
+	Rating      int    `json:"rating"`
+	Comment     string `json:"comment"`
+	IsAnonymous bool   `json:"is_anonymous"` // show "Anonymous" instead of the author's name
+}
+
+// UpdateReviewRequest replaces the rating and comment of a review
+type UpdateReviewRequest struct {
+	Rating      int    `json:"rating" binding:"required"`
+	Comment     string `json:"comment"`
+	IsAnonymous *bool  `json:"is_anonymous"` // unchanged when omitted
 }
 
 type CreateLikeRequest struct {
@@ -39,6 +63,9 @@ type ReviewResponse struct {
 	CreatedAt    string `json:"created_at"`
 	LikeCount    int    `json:"like_count"`
 	DislikeCount int    `json:"dislike_count"`
+	Visibility   string `json:"visibility,omitempty"` // only set on the viewer's own reviews
+	EditedAt     string `json:"edited_at,omitempty"`
+	IsAnonymous  bool   `json:"is_anonymous"`
 }
 
 // services/review_service.go
@@ -57,64 +84,47 @@ func (s *ReviewService) GetProductReaction(userID, productID uint) (*models.Prod
 
 func (s *ReviewService) LikeOrDislikeProduct(userID, productID uint, req CreateLikeRequest) error {
 	var product models.Product
-	if err := s.db.Where("id = ? AND status = ?", productID, "active").First(&product).Error; err != nil {
+	if err := s.db.Select("id").Where("id = ? AND status = ?", productID, "active").First(&product).Error; err != nil {
 		return errors.New("product not found")
 	}
 
-	var reaction models.ProductReaction
-	err := s.db.Where("user_id = ? AND product_id = ?", userID, productID).First(&reaction).Error
-
-	if err == nil {
-		// Update existing reaction
-		if reaction.IsLike && !req.Like {
-			product.LikeCount -= 1
-		}
-		if reaction.IsDislike && !req.DisLike {
-			product.DislikeCount -= 1
-		}
-		if !reaction.IsLike && req.Like {
-			product.LikeCount += 1
-		}
-		if !reaction.IsDislike && req.DisLike {
-			product.DislikeCount += 1
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var reaction models.ProductReaction
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND product_id = ?", userID, productID).
+			First(&reaction).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			reaction = models.ProductReaction{UserID: userID, ProductID: productID, CreatedAt: time.Now()}
+		} else if err != nil {
+			return errors.New("failed to fetch existing reaction")
 		}
 
+		likes := reactionDelta(reaction.IsLike, req.Like)
+		dislikes := reactionDelta(reaction.IsDislike, req.DisLike)
 		reaction.IsLike = req.Like
 		reaction.IsDislike = req.DisLike
-
-		if err := s.db.Save(&reaction).Error; err != nil {
+		if err := tx.Save(&reaction).Error; err != nil {
 			return errors.New("failed to update reaction")
 		}
-	} else if errors.Is(err, gorm.ErrRecordNotFound) {
-		// New reaction
-		newReaction := models.ProductReaction{
-			UserID:     userID,
-			ProductID:  productID,
-			IsLike:     req.Like,
-			IsDislike:  req.DisLike,
-			CreatedAt:  time.Now(),
-		}
-
-		if req.Like {
-			product.LikeCount += 1
-		}
-		if req.DisLike {
-			product.DislikeCount += 1
-		}
 
-		if err := s.db.Create(&newReaction).Error; err != nil {
-			return errors.New("failed to create reaction")
+		// The counts are incremented in place, so concurrent reactions
+		// cannot overwrite each other
+		if err := s.counters.Add(tx, CounterProductLikes, productID, likes); err != nil {
+			return err
 		}
-	} else {
-		return errors.New("failed to fetch existing reaction")
-	}
+		return s.counters.Add(tx, CounterProductDislikes, productID, dislikes)
+	})
+}
 
-	// Save updated like/dislike count on product
-	if err := s.db.Save(&product).Error; err != nil {
-		return errors.New("failed to update product counts")
+// reactionDelta is the change to a count when a reaction goes from was to now
+func reactionDelta(was, now bool) int {
+	switch {
+	case now && !was:
+		return 1
+	case was && !now:
+		return -1
 	}
-
-	return nil
+	return 0
 }
 
 
@@ -122,25 +132,30 @@ func (s *ReviewService) LikeOrDislikeProduct(userID, productID uint, req CreateL
 func (s *ReviewService) CreateReview(userID uint, req CreateReviewRequest) (*models.Review, error) {
 	// Validate rating
 	if !utils.IsValidRating(req.Rating) {
-		return nil, errors.New("rating must be between 1 and 5")
+		return nil, fmt.Errorf("%w: rating must be between 1 and 5", ErrInvalidInput)
 	}
 
 	// Check if product exists
 	var product models.Product
 	if err := s.db.Where("id = ? AND status = ?", req.ProductID, "active").First(&product).Error; err != nil {
-		return nil, errors.New("product not found")
+		return nil, fmt.Errorf("%w: product with ID %d not found", ErrProductNotFound, req.ProductID)
 	}
 
 	// Check if user already reviewed this product
 	var review models.Review
 	if err := s.db.Where("user_id = ? AND product_id = ?", userID, req.ProductID).First(&review).Error; err == nil {
 		// Review exists — update it
-		review.Rating = req.Rating
-		review.Comment = utils.SanitizeString(req.Comment)
-		review.IsActive = true
-
-		if err := s.db.Save(&review).Error; err != nil {
-			return nil, errors.New("failed to update existing review")
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := setReviewAnonymous(tx, &review, req.IsAnonymous); err != nil {
+				return err
+			}
+			if err := editReview(tx, &review, userID, req.Rating, req.Comment); err != nil {
+				return err
+			}
+			return s.ratings.Refresh(tx, review.ProductID)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to update existing review: %v", ErrDatabaseQuery, err)
 		}
 
 		// Preload user and product info
@@ -153,12 +168,29 @@ func (s *ReviewService) CreateReview(userID uint, req CreateReviewRequest) (*mod
 		UserID:    userID,
 		ProductID: req.ProductID,
 		Rating:    req.Rating,
-		Comment:   utils.SanitizeString(req.Comment),
-		IsActive:  true,
+		Comment:    utils.SanitizeString(req.Comment),
+		Visibility: models.ReviewVisibilityPublished,
+		IsAnonymous: req.IsAnonymous,
 	}
 
-	if err := s.db.Create(&review).Error; err != nil {
-		return nil, errors.New("failed to create review")
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&review).Error; err != nil {
+			return err
+		}
+		if err := s.ratings.Refresh(tx, review.ProductID); err != nil {
+			return err
+		}
+		return recordOutboxEvent(tx, EventReviewCreated, "review", review.ID, map[string]interface{}{
+			"id":         review.ID,
+			"product_id": review.ProductID,
+			"user_id":    review.UserID,
+			"rating":     review.Rating,
+			"comment":    review.Comment,
+			"created_at": review.CreatedAt,
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to create review: %v", ErrDatabaseQuery, err)
 	}
 
 	s.db.Preload("User").Preload("Product").First(&review, review.ID)
@@ -166,97 +198,281 @@ func (s *ReviewService) CreateReview(userID uint, req CreateReviewRequest) (*mod
 }
 
 
-func (s *ReviewService) GetProductReviews(productID uint, page, limit int) ([]ReviewResponse, error) {
+// GetProductReviews lists published reviews. When viewerID is set the viewer's
+// own pending and shadow-hidden reviews are included as well.
+func (s *ReviewService) GetProductReviews(productID, viewerID uint, page, limit int) ([]ReviewResponse, int64, error) {
 	// First check if product exists
 	var product models.Product
 	if err := s.db.Where("id = ? AND status = ?", productID, "active").First(&product).Error; err != nil {
-		return nil, errors.New("product not found")
+		return nil, 0, errors.New("product not found")
 	}
 
 	var reviews []models.Review
+	var total int64
 	offset := (page - 1) * limit
 
-	query := s.db.Preload("User").
-		Where("product_id = ? AND is_active = ?", productID, true).
+	// Served from a replica; an author may miss a review posted moments ago
+	visible := database.Replica(s.db).Model(&models.Review{}).
+		Where("product_id = ?", productID).
+		Where(s.db.Where("visibility = ?", models.ReviewVisibilityPublished).
+			Or("user_id = ? AND visibility IN ?", viewerID, []string{models.ReviewVisibilityPending, models.ReviewVisibilityShadowHidden}))
+
+	if err := visible.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, errors.New("failed to count reviews")
+	}
+
+	query := visible.Preload("User").
 		Order("created_at DESC").
 		Offset(offset).
 		Limit(limit)
 
 	if err := query.Find(&reviews).Error; err != nil {
-		return nil, errors.New("failed to fetch reviews")
+		return nil, 0, errors.New("failed to fetch reviews")
 	}
 
 	var response []ReviewResponse
 	for _, review := range reviews {
-		// Count likes and dislikes
-		var likeCount, dislikeCount int64
-		s.db.Model(&models.ReviewLike{}).Where("review_id = ? AND is_like = ?", review.ID, true).Count(&likeCount)
-		s.db.Model(&models.ReviewLike{}).Where("review_id = ? AND is_like = ?", review.ID, false).Count(&dislikeCount)
-
 		// Handle case where User might be nil
 		userName := "Anonymous"
-		if review.User.ID != 0 {
+		if review.User.ID != 0 && !review.IsAnonymous {
 			userName = review.User.FirstName + " " + review.User.LastName
 		}
+		ownReview := viewerID != 0 && review.UserID == viewerID
 
 		reviewResp := ReviewResponse{
 			ID:           review.ID,
 			UserID:       review.UserID,
+			IsAnonymous:  review.IsAnonymous,
 			ProductID:    review.ProductID,
 			Rating:       review.Rating,
 			Comment:      review.Comment,
 			UserName:     userName,
 			CreatedAt:    review.CreatedAt.Format("2006-01-02 15:04:05"),
-			LikeCount:    int(likeCount),
-			DislikeCount: int(dislikeCount),
+			LikeCount:    review.LikeCount,
+			DislikeCount: review.DislikeCount,
+		}
+		if review.EditedAt != nil {
+			reviewResp.EditedAt = review.EditedAt.Format("2006-01-02 15:04:05")
+		}
+		if ownReview {
+			reviewResp.Visibility = authorVisibility(review.Visibility)
+		}
+		// The user ID would identify an anonymous author to everyone else
+		if review.IsAnonymous && !ownReview {
+			reviewResp.UserID = 0
 		}
 		response = append(response, reviewResp)
 	}
 
-	return response, nil
+	return response, total, nil
 }
 
-func (s *ReviewService) LikeReview(userID, reviewID uint, isLike bool) error {
-	// Check if review exists and is active
+// UpdateReview changes the rating and comment of a review. Only its author or
+// an admin may edit it; the previous text is kept in the edit history.
+func (s *ReviewService) UpdateReview(reviewID, userID uint, isAdmin bool, req UpdateReviewRequest) (*models.Review, error) {
+	if !utils.IsValidRating(req.Rating) {
+		return nil, fmt.Errorf("%w: rating must be between 1 and 5", ErrInvalidInput)
+	}
+
 	var review models.Review
-	if err := s.db.Where("id = ? AND is_active = ?", reviewID, true).First(&review).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return errors.New("review not found")
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&review, reviewID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrReviewNotFound
+			}
+			return fmt.Errorf("%w: failed to find review: %v", ErrDatabaseQuery, err)
 		}
-		return errors.New("failed to find review")
+		if review.UserID != userID && !isAdmin {
+			return ErrReviewForbidden
+		}
+
+		if req.IsAnonymous != nil {
+			if err := setReviewAnonymous(tx, &review, *req.IsAnonymous); err != nil {
+				return err
+			}
+		}
+		if err := editReview(tx, &review, userID, req.Rating, req.Comment); err != nil {
+			return err
+		}
+		if err := s.ratings.Refresh(tx, review.ProductID); err != nil {
+			return err
+		}
+		return recordOutboxEvent(tx, EventReviewUpdated, "review", review.ID, map[string]interface{}{
+			"id":         review.ID,
+			"product_id": review.ProductID,
+			"user_id":    review.UserID,
+			"rating":     review.Rating,
+			"comment":    review.Comment,
+			"edited_at":  review.EditedAt,
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Check existing like/dislike
-	var existingLike models.ReviewLike
-	err := s.db.Where("user_id = ? AND review_id = ?", userID, reviewID).First(&existingLike).Error
+	s.db.Preload("User").Preload("Product").First(&review, review.ID)
+	return &review, nil
+}
 
-	if err == nil {
-		// Update existing like/dislike
-		existingLike.IsLike = isLike
-		if err := s.db.Save(&existingLike).Error; err != nil {
-			return errors.New("failed to update like/dislike")
+// DeleteReview removes a review with its likes and history. Only its author
+// or an admin may delete it.
+func (s *ReviewService) DeleteReview(reviewID, userID uint, isAdmin bool) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var review models.Review
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&review, reviewID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrReviewNotFound
+			}
+			return fmt.Errorf("%w: failed to find review: %v", ErrDatabaseQuery, err)
 		}
-		return nil
-	} else if err == gorm.ErrRecordNotFound {
-		// Create new like/dislike
-		newLike := models.ReviewLike{
-			UserID:   userID,
-			ReviewID: reviewID,
-			IsLike:   isLike,
+		if review.UserID != userID && !isAdmin {
+			return ErrReviewForbidden
+		}
+
+		for _, model := range []interface{}{&models.ReviewLike{}, &models.ReviewEdit{}, &models.ReviewModeration{}} {
+			if err := tx.Where("review_id = ?", reviewID).Delete(model).Error; err != nil {
+				return fmt.Errorf("%w: failed to delete review details: %v", ErrDatabaseQuery, err)
+			}
+		}
+		if err := tx.Delete(&review).Error; err != nil {
+			return fmt.Errorf("%w: failed to delete review: %v", ErrDatabaseQuery, err)
 		}
-		if err := s.db.Create(&newLike).Error; err != nil {
-			return errors.New("failed to create like/dislike")
+		if err := s.ratings.Refresh(tx, review.ProductID); err != nil {
+			return err
+		}
+		return recordOutboxEvent(tx, EventReviewDeleted, "review", review.ID, map[string]interface{}{
+			"id":         review.ID,
+			"product_id": review.ProductID,
+			"user_id":    review.UserID,
+			"deleted_by": userID,
+		})
+	})
+}
+
+// GetReviewEdits returns the earlier versions of a review, oldest first
+func (s *ReviewService) GetReviewEdits(reviewID uint) ([]models.ReviewEdit, error) {
+	if err := s.db.First(&models.Review{}, reviewID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
 		}
+		return nil, fmt.Errorf("%w: failed to find review: %v", ErrDatabaseQuery, err)
+	}
+
+	edits := make([]models.ReviewEdit, 0)
+	if err := s.db.Where("review_id = ?", reviewID).Order("created_at ASC").Find(&edits).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch review edits: %v", ErrDatabaseQuery, err)
+	}
+	return edits, nil
+}
+
+// setReviewAnonymous changes whether a review is shown without its author's
+// name. This is a display setting, so it is not an edit.
+func setReviewAnonymous(tx *gorm.DB, review *models.Review, anonymous bool) error {
+	if review.IsAnonymous == anonymous {
+		return nil
+	}
+	if err := tx.Model(review).Update("is_anonymous", anonymous).Error; err != nil {
+		return fmt.Errorf("%w: failed to update review: %v", ErrDatabaseQuery, err)
+	}
+	review.IsAnonymous = anonymous
+	return nil
+}
+
+// editReview records the current rating and comment in the edit history,
+// then applies the new ones. Unchanged reviews are left alone.
+func editReview(tx *gorm.DB, review *models.Review, editorID uint, rating int, comment string) error {
+	comment = utils.SanitizeString(comment)
+	changed := review.Rating != rating || review.Comment != comment
+	// A removed review rewritten by its author goes back to moderation
+	// instead of reappearing
+	resubmitted := review.Visibility == models.ReviewVisibilityRemoved && editorID == review.UserID
+	if !changed && !resubmitted {
 		return nil
 	}
 
-	return errors.New("failed to process like/dislike")
+	if changed {
+		entry := models.ReviewEdit{
+			ReviewID:        review.ID,
+			EditorID:        editorID,
+			PreviousRating:  review.Rating,
+			PreviousComment: review.Comment,
+		}
+		if err := tx.Create(&entry).Error; err != nil {
+			return fmt.Errorf("%w: failed to record review edit: %v", ErrDatabaseQuery, err)
+		}
+		now := time.Now()
+		review.EditedAt = &now
+	}
+
+	review.Rating = rating
+	review.Comment = comment
+	if resubmitted {
+		review.Visibility = models.ReviewVisibilityPending
+	}
+
+	err := tx.Model(review).
+		Select("rating", "comment", "visibility", "edited_at").
+		Updates(review).Error
+	if err != nil {
+		return fmt.Errorf("%w: failed to update review: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
+
+func (s *ReviewService) LikeReview(userID, reviewID uint, isLike bool) error {
+	// Check if review exists and is published
+	var review models.Review
+	if err := s.db.Where("id = ? AND visibility = ?", reviewID, models.ReviewVisibilityPublished).First(&review).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrReviewNotFound
+		}
+		return fmt.Errorf("%w: failed to find review: %v", ErrDatabaseQuery, err)
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		// Check existing like/dislike
+		var existingLike models.ReviewLike
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND review_id = ?", userID, reviewID).
+			First(&existingLike).Error
+
+		if err == nil {
+			if existingLike.IsLike == isLike {
+				return nil
+			}
+			// Update existing like/dislike
+			existingLike.IsLike = isLike
+			if err := tx.Save(&existingLike).Error; err != nil {
+				return fmt.Errorf("%w: failed to update like/dislike: %v", ErrDatabaseQuery, err)
+			}
+			if err := s.counters.Add(tx, CounterReviewLikes, reviewID, reactionDelta(!isLike, isLike)); err != nil {
+				return err
+			}
+			return s.counters.Add(tx, CounterReviewDislikes, reviewID, reactionDelta(isLike, !isLike))
+		} else if err == gorm.ErrRecordNotFound {
+			// Create new like/dislike
+			newLike := models.ReviewLike{
+				UserID:   userID,
+				ReviewID: reviewID,
+				IsLike:   isLike,
+			}
+			if err := tx.Create(&newLike).Error; err != nil {
+				return fmt.Errorf("%w: failed to create like/dislike: %v", ErrDatabaseQuery, err)
+			}
+			if isLike {
+				return s.counters.Add(tx, CounterReviewLikes, reviewID, 1)
+			}
+			return s.counters.Add(tx, CounterReviewDislikes, reviewID, 1)
+		}
+
+		return fmt.Errorf("%w: failed to process like/dislike: %v", ErrDatabaseQuery, err)
+	})
 }
 
 func (s *ReviewService) FlagReview(reviewID uint) error {
-	// Check if review exists and is active
+	// Check if review exists and is published
 	var review models.Review
-	if err := s.db.Where("id = ? AND is_active = ?", reviewID, true).First(&review).Error; err != nil {
+	if err := s.db.Where("id = ? AND visibility = ?", reviewID, models.ReviewVisibilityPublished).First(&review).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return errors.New("review not found")
 		}
@@ -264,9 +480,21 @@ func (s *ReviewService) FlagReview(reviewID uint) error {
 	}
 
 	// Update the review to flagged
-	if err := s.db.Model(&models.Review{}).Where("id = ?", reviewID).Update("is_flagged", true).Error; err != nil {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Review{}).Where("id = ?", reviewID).Update("is_flagged", true).Error; err != nil {
+			return err
+		}
+		return recordOutboxEvent(tx, EventReviewFlagged, "review", review.ID, map[string]interface{}{
+			"id":         review.ID,
+			"product_id": review.ProductID,
+			"user_id":    review.UserID,
+			"rating":     review.Rating,
+		})
+	})
+	if err != nil {
 		return errors.New("failed to flag review")
 	}
+	metrics.ReviewFlags.Inc()
 
 	return nil
 }
@@ -274,7 +502,7 @@ func (s *ReviewService) FlagReview(reviewID uint) error {
 func (s *ReviewService) GetFlaggedReviews() ([]models.Review, error) {
 	var reviews []models.Review
 	err := s.db.Preload("User").Preload("Product").
-		Where("is_flagged = ? AND is_active = ?", true, true).
+		Where("is_flagged = ? AND visibility <> ?", true, models.ReviewVisibilityRemoved).
 		Find(&reviews).Error
 
 	if err != nil {
@@ -284,28 +512,99 @@ func (s *ReviewService) GetFlaggedReviews() ([]models.Review, error) {
 	return reviews, nil
 }
 
-func (s *ReviewService) ModerateReview(reviewID uint, action string) error {
-	// Check if review exists
-	var review models.Review
-	if err := s.db.Where("id = ?", reviewID).First(&review).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return errors.New("review not found")
+// ModerateReview keeps the original approve/remove actions as shortcuts for
+// visibility transitions.
+func (s *ReviewService) ModerateReview(reviewID, moderatorID uint, action, reason string) error {
+	switch action {
+	case "approve":
+		_, err := s.SetReviewVisibility(reviewID, moderatorID, models.ReviewVisibilityPublished, reason)
+		return err
+	case "remove":
+		if reason == "" {
+			reason = "Removed by moderator"
 		}
-		return errors.New("failed to find review")
+		_, err := s.SetReviewVisibility(reviewID, moderatorID, models.ReviewVisibilityRemoved, reason)
+		return err
+	default:
+		return fmt.Errorf("%w: invalid action, use 'approve' or 'remove'", ErrInvalidVisibilityChange)
 	}
+}
 
-	switch action {
-	case "approve":
-		if err := s.db.Model(&models.Review{}).Where("id = ?", reviewID).Update("is_flagged", false).Error; err != nil {
-			return errors.New("failed to approve review")
+// SetReviewVisibility moves a review to a new visibility state and records the
+// transition. Hiding or removing a review requires a reason.
+func (s *ReviewService) SetReviewVisibility(reviewID, moderatorID uint, visibility, reason string) (*models.Review, error) {
+	switch visibility {
+	case models.ReviewVisibilityPublished, models.ReviewVisibilityPending:
+	case models.ReviewVisibilityShadowHidden, models.ReviewVisibilityRemoved:
+		if strings.TrimSpace(reason) == "" {
+			return nil, fmt.Errorf("%w: a reason is required to %s a review", ErrInvalidVisibilityChange, strings.ReplaceAll(visibility, "_", "-"))
 		}
-		return nil
-	case "remove":
-		if err := s.db.Model(&models.Review{}).Where("id = ?", reviewID).Update("is_active", false).Error; err != nil {
-			return errors.New("failed to remove review")
+	default:
+		return nil, fmt.Errorf("%w: unknown visibility %q", ErrInvalidVisibilityChange, visibility)
+	}
+
+	var review models.Review
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&review, reviewID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrReviewNotFound
+			}
+			return fmt.Errorf("%w: failed to find review: %v", ErrDatabaseQuery, err)
+		}
+
+		updates := map[string]interface{}{"visibility": visibility}
+		// A moderator decision resolves any outstanding flag
+		if review.IsFlagged {
+			updates["is_flagged"] = false
+		}
+		if err := tx.Model(&review).Updates(updates).Error; err != nil {
+			return fmt.Errorf("%w: failed to update review visibility: %v", ErrDatabaseQuery, err)
 		}
+		if err := s.ratings.Refresh(tx, review.ProductID); err != nil {
+			return err
+		}
+
+		entry := models.ReviewModeration{
+			ReviewID:       review.ID,
+			ModeratorID:    moderatorID,
+			FromVisibility: review.Visibility,
+			ToVisibility:   visibility,
+			Reason:         utils.SanitizeString(reason),
+		}
+		if err := tx.Create(&entry).Error; err != nil {
+			return fmt.Errorf("%w: failed to record moderation: %v", ErrDatabaseQuery, err)
+		}
+		review.Visibility = visibility
+		review.IsFlagged = false
 		return nil
-	default:
-		return errors.New("invalid action, use 'approve' or 'remove'")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// GetReviewModerationHistory returns the visibility transitions of a review
+func (s *ReviewService) GetReviewModerationHistory(reviewID uint) ([]models.ReviewModeration, error) {
+	if err := s.db.First(&models.Review{}, reviewID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to find review: %v", ErrDatabaseQuery, err)
+	}
+
+	history := make([]models.ReviewModeration, 0)
+	if err := s.db.Where("review_id = ?", reviewID).Order("created_at ASC").Find(&history).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch moderation history: %v", ErrDatabaseQuery, err)
+	}
+	return history, nil
+}
+
+// authorVisibility hides shadow-hiding from the author, who should not be able
+// to tell their review is invisible to others.
+func authorVisibility(visibility string) string {
+	if visibility == models.ReviewVisibilityShadowHidden {
+		return models.ReviewVisibilityPublished
 	}
+	return visibility
 }
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// ReviewExportFilter narrows a review export. Zero values match everything.
+type ReviewExportFilter struct {
+	From       *time.Time // reviews created at or after
+	To         *time.Time // reviews created before
+	Visibility string
+}
+
+// reviewExportRow is one review joined with its product, author and reactions
+type reviewExportRow struct {
+	ID           uint
+	ProductID    uint
+	ProductTitle string
+	UserID       uint
+	UserEmail    string
+	Rating       int
+	Comment      string
+	Visibility   string
+	IsFlagged    bool
+	IsAnonymous  bool
+	Likes        int
+	Dislikes     int
+	CreatedAt    time.Time
+	EditedAt     *time.Time
+}
+
+// StartReviewExport queues a CSV export of the reviews matching filter, with
+// the real author even for anonymous reviews. Like StartProductExport the file
+// is uploaded to S3 and a presigned download URL is stored on the job.
+func (s *AdminService) StartReviewExport(ctx context.Context, userID uint, filter ReviewExportFilter) (*models.Job, error) {
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		return nil, fmt.Errorf("%w: to must be after from", ErrInvalidInput)
+	}
+
+	return s.jobService.Enqueue(ctx, models.JobTypeReviewExport, userID, func(ctx context.Context, run *JobRun) error {
+		var total int64
+		if err := s.reviewExportQuery(ctx, filter).Count(&total).Error; err != nil {
+			return fmt.Errorf("%w: failed to count reviews: %v", ErrDatabaseQuery, err)
+		}
+		// Half the progress bar covers building the file, half the upload
+		run.SetTotal(int(total) * 2)
+		run.Infof("Exporting %d reviews", total)
+
+		var buf bytes.Buffer
+		writer := csv.NewWriter(&buf)
+		writer.Write([]string{
+			"id", "product_id", "product_title", "user_id", "user_email", "rating", "comment",
+			"visibility", "is_flagged", "is_anonymous", "likes", "dislikes", "created_at", "edited_at",
+		})
+
+		// Page by id rather than offset so rows added during the export do not
+		// shift later batches
+		var lastID uint
+		for {
+			var batch []reviewExportRow
+			err := s.reviewExportQuery(ctx, filter).
+				Select(`reviews.id, reviews.product_id, products.title AS product_title,
+					reviews.user_id, users.email AS user_email, reviews.rating, reviews.comment,
+					reviews.visibility, reviews.is_flagged, reviews.is_anonymous,
+					(SELECT COUNT(*) FROM review_likes WHERE review_likes.review_id = reviews.id AND review_likes.is_like) AS likes,
+					(SELECT COUNT(*) FROM review_likes WHERE review_likes.review_id = reviews.id AND NOT review_likes.is_like) AS dislikes,
+					reviews.created_at, reviews.edited_at`).
+				Where("reviews.id > ?", lastID).
+				Order("reviews.id ASC").
+				Limit(exportBatchSize).
+				Scan(&batch).Error
+			if err != nil {
+				return fmt.Errorf("%w: failed to read reviews: %v", ErrDatabaseQuery, err)
+			}
+			if len(batch) == 0 {
+				break
+			}
+
+			for _, r := range batch {
+				editedAt := ""
+				if r.EditedAt != nil {
+					editedAt = r.EditedAt.UTC().Format(time.RFC3339)
+				}
+				writer.Write([]string{
+					strconv.FormatUint(uint64(r.ID), 10),
+					strconv.FormatUint(uint64(r.ProductID), 10),
+					r.ProductTitle,
+					strconv.FormatUint(uint64(r.UserID), 10),
+					r.UserEmail,
+					strconv.Itoa(r.Rating),
+					r.Comment,
+					r.Visibility,
+					strconv.FormatBool(r.IsFlagged),
+					strconv.FormatBool(r.IsAnonymous),
+					strconv.Itoa(r.Likes),
+					strconv.Itoa(r.Dislikes),
+					r.CreatedAt.UTC().Format(time.RFC3339),
+					editedAt,
+				})
+			}
+			run.Advance(len(batch), 0)
+			lastID = batch[len(batch)-1].ID
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return fmt.Errorf("failed to write CSV: %v", err)
+		}
+
+		key := fmt.Sprintf("exports/reviews/%s-job-%d.csv", time.Now().Format("20060102-150405"), run.JobID())
+		url, err := s.uploadExport(ctx, run, key, &buf)
+		if err != nil {
+			return err
+		}
+
+		run.SetResult(fmt.Sprintf("Exported %d reviews to %s", total, key), url)
+		run.Infof("Export ready")
+		return nil
+	})
+}
+
+func (s *AdminService) reviewExportQuery(ctx context.Context, filter ReviewExportFilter) *gorm.DB {
+	query := s.db.WithContext(ctx).Table("reviews").
+		Joins("LEFT JOIN products ON products.id = reviews.product_id").
+		Joins("LEFT JOIN users ON users.id = reviews.user_id")
+	if filter.From != nil {
+		query = query.Where("reviews.created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("reviews.created_at < ?", *filter.To)
+	}
+	if filter.Visibility != "" {
+		query = query.Where("reviews.visibility = ?", filter.Visibility)
+	}
+	return query
+}
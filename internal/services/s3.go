@@ -3,6 +3,8 @@ package services
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -11,32 +13,49 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/google/uuid"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"github.com/princeprakhar/ecommerce-backend/pkg/metrics"
 )
 
+var ErrObjectNotFound = errors.New("object not found")
+
 type S3Service struct {
 	client     *s3.S3
 	bucketName string
 	region     string
+	endpoint   string
+	log        logger.Logger
 }
 
-func NewS3Service(region, bucketName string, accessKey, secretKey string) *S3Service {
-	sess := session.Must(session.NewSession(&aws.Config{
+// NewS3Service talks to AWS S3, or to an S3-compatible endpoint such as
+// localstack or MinIO when endpoint is set
+func NewS3Service(region, endpoint, bucketName string, accessKey, secretKey string, log logger.Logger) *S3Service {
+	awsConfig := &aws.Config{
 		Region: aws.String(region),
 		Credentials: credentials.NewStaticCredentials(
 			accessKey,
 			secretKey,
 			"",
 		),
-	}))
+	}
+	if endpoint != "" {
+		awsConfig.Endpoint = aws.String(endpoint)
+		awsConfig.S3ForcePathStyle = aws.Bool(true)
+	}
+	sess := session.Must(session.NewSession(awsConfig))
 
 	return &S3Service{
 		client:     s3.New(sess),
 		bucketName: bucketName,
 		region:     region,
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		log:        log,
 	}
 }
 
@@ -46,9 +65,18 @@ type UploadResult struct {
 	FileName    string
 	ContentType string
 	Size        int64
+	SHA256      string // content hash, set for product images
+	Reused      bool   // the content was already stored and Key is the existing object
 }
 
-func (s *S3Service) UploadImage(file multipart.File, header *multipart.FileHeader) (*UploadResult, error) {
+func (s *S3Service) UploadImage(ctx context.Context, file multipart.File, header *multipart.FileHeader) (*UploadResult, error) {
+	return s.UploadImageAt(ctx, "products/images/", file, header)
+}
+
+// UploadImageAt uploads an image under the given key prefix, e.g. "banners/".
+// Product images must stay under products/images/, which storage
+// reconciliation scans for orphans. Cancelling ctx aborts the upload.
+func (s *S3Service) UploadImageAt(ctx context.Context, prefix string, file multipart.File, header *multipart.FileHeader) (*UploadResult, error) {
 	// Validate file type
 	contentType := header.Header.Get("Content-Type")
 	if contentType == "" {
@@ -69,7 +97,7 @@ func (s *S3Service) UploadImage(file multipart.File, header *multipart.FileHeade
 	// Generate unique key with timestamp for better organization
 	fileExt := filepath.Ext(header.Filename)
 	timestamp := time.Now().Format("2006/01/02")
-	key := fmt.Sprintf("products/images/%s/%s%s", timestamp, uuid.New().String(), fileExt)
+	key := fmt.Sprintf("%s%s/%s%s", prefix, timestamp, uuid.New().String(), fileExt)
 
 	// Read file content
 	buffer := bytes.NewBuffer(nil)
@@ -78,7 +106,7 @@ func (s *S3Service) UploadImage(file multipart.File, header *multipart.FileHeade
 	}
 
 	// Upload to S3
-	_, err := s.client.PutObject(&s3.PutObjectInput{
+	_, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
 		Bucket:      aws.String(s.bucketName),
 		Key:         aws.String(key),
 		Body:        bytes.NewReader(buffer.Bytes()),
@@ -90,9 +118,10 @@ func (s *S3Service) UploadImage(file multipart.File, header *multipart.FileHeade
 	if err != nil {
 		return nil, fmt.Errorf("failed to upload to S3: %v", err)
 	}
+	metrics.S3UploadBytes.Add(float64(buffer.Len()))
 
 	// Generate S3 URL
-	url := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.bucketName, s.region, key)
+	url := s.ObjectURL(key)
 
 	return &UploadResult{
 		Key:         key,
@@ -103,18 +132,47 @@ func (s *S3Service) UploadImage(file multipart.File, header *multipart.FileHeade
 	}, nil
 }
 
-func (s *S3Service) UploadMultipleImages(files []*multipart.FileHeader) ([]*UploadResult, error) {
+// UploadImageData stores an image that is already in memory, e.g. one staged
+// on disk before it was attached to a product.
+func (s *S3Service) UploadImageData(fileName string, data []byte) (*UploadResult, error) {
+	contentType := s.getContentTypeFromExtension(fileName)
+	if !s.isValidImageType(contentType) {
+		return nil, fmt.Errorf("invalid file type: %s", contentType)
+	}
+
+	key := fmt.Sprintf("products/images/%s/%s%s", time.Now().Format("2006/01/02"), uuid.New().String(), filepath.Ext(fileName))
+	if err := s.PutObject(key, contentType, data); err != nil {
+		return nil, err
+	}
+
+	return &UploadResult{
+		Key:         key,
+		URL:         s.ObjectURL(key),
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+	}, nil
+}
+
+// UploadMultipleImages uploads every file or none: on any failure, including
+// ctx being cancelled, the files already uploaded are deleted again.
+func (s *S3Service) UploadMultipleImages(ctx context.Context, files []*multipart.FileHeader) ([]*UploadResult, error) {
 	var results []*UploadResult
 	var uploadErrors []string
 
 	for i, fileHeader := range files {
+		if err := ctx.Err(); err != nil {
+			uploadErrors = append(uploadErrors, fmt.Sprintf("upload cancelled: %v", err))
+			break
+		}
+
 		file, err := fileHeader.Open()
 		if err != nil {
 			uploadErrors = append(uploadErrors, fmt.Sprintf("file %d: failed to open - %v", i+1, err))
 			continue
 		}
 
-		result, err := s.UploadImage(file, fileHeader)
+		result, err := s.UploadImage(ctx, file, fileHeader)
 		file.Close()
 		
 		if err != nil {
@@ -128,7 +186,9 @@ func (s *S3Service) UploadMultipleImages(files []*multipart.FileHeader) ([]*Uplo
 	if len(uploadErrors) > 0 {
 		// If some uploads failed, clean up successful ones
 		for _, result := range results {
-			s.DeleteImage(result.Key)
+			if err := s.DeleteImage(result.Key); err != nil {
+				s.log.WithContext(ctx).WithFields(map[string]interface{}{"s3_key": result.Key}).Warn("Failed to clean up partial upload: ", err)
+			}
 		}
 		return nil, fmt.Errorf("upload errors: %s", strings.Join(uploadErrors, "; "))
 	}
@@ -174,6 +234,207 @@ func (s *S3Service) DeleteMultipleImages(keys []string) error {
 	return err
 }
 
+type S3Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// ListObjects calls fn for every object under prefix, one page at a time.
+func (s *S3Service) ListObjects(prefix string, fn func(S3Object)) error {
+	err := s.client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucketName),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, obj := range page.Contents {
+			fn(S3Object{
+				Key:          aws.StringValue(obj.Key),
+				Size:         aws.Int64Value(obj.Size),
+				LastModified: aws.TimeValue(obj.LastModified),
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list S3 objects: %v", err)
+	}
+	return nil
+}
+
+// UploadFile streams an arbitrary object (e.g. an export) to S3, calling
+// onProgress with the number of bytes sent so far. Cancelling ctx aborts the
+// upload.
+func (s *S3Service) UploadFile(ctx context.Context, key, contentType string, body io.Reader, size int64, onProgress func(sent, total int64)) error {
+	uploader := s3manager.NewUploaderWithClient(s.client)
+	_, err := uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket:      aws.String(s.bucketName),
+		Key:         aws.String(key),
+		Body:        &progressReader{reader: body, total: size, onProgress: onProgress},
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload to S3: %v", err)
+	}
+	metrics.S3UploadBytes.Add(float64(size))
+	return nil
+}
+
+// GetObject downloads an object into memory, returning ErrObjectNotFound when
+// the key does not exist.
+func (s *S3Service) GetObject(key string) ([]byte, string, error) {
+	output, err := s.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, "", ErrObjectNotFound
+		}
+		return nil, "", fmt.Errorf("failed to download from S3: %v", err)
+	}
+	defer output.Body.Close()
+
+	data, err := io.ReadAll(output.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read S3 object: %v", err)
+	}
+	return data, aws.StringValue(output.ContentType), nil
+}
+
+// OpenObject streams an object so callers can cap how much of it they read.
+// The caller must close the returned body.
+func (s *S3Service) OpenObject(key string) (io.ReadCloser, error) {
+	output, err := s.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to download from S3: %v", err)
+	}
+	return output.Body, nil
+}
+
+// PutObject stores a small in-memory object such as a cached image variant.
+func (s *S3Service) PutObject(key, contentType string, data []byte) error {
+	_, err := s.client.PutObject(&s3.PutObjectInput{
+		Bucket:       aws.String(s.bucketName),
+		Key:          aws.String(key),
+		Body:         bytes.NewReader(data),
+		ContentType:  aws.String(contentType),
+		CacheControl: aws.String("max-age=31536000"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload to S3: %v", err)
+	}
+	metrics.S3UploadBytes.Add(float64(len(data)))
+	return nil
+}
+
+// ObjectURL returns the public URL of an object in the bucket.
+func (s *S3Service) ObjectURL(key string) string {
+	if s.endpoint != "" {
+		return fmt.Sprintf("%s/%s/%s", s.endpoint, s.bucketName, key)
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.bucketName, s.region, key)
+}
+
+// PresignGetURL returns a time-limited download URL for a private object.
+func (s *S3Service) PresignGetURL(key string, expiry time.Duration) (string, error) {
+	req, _ := s.client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	return req.Presign(expiry)
+}
+
+// CreateMultipartUpload starts an S3 multipart upload and returns its ID
+func (s *S3Service) CreateMultipartUpload(ctx context.Context, key, contentType string) (string, error) {
+	out, err := s.client.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:       aws.String(s.bucketName),
+		Key:          aws.String(key),
+		ContentType:  aws.String(contentType),
+		CacheControl: aws.String("max-age=31536000"),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to start multipart upload: %v", err)
+	}
+	return aws.StringValue(out.UploadId), nil
+}
+
+// UploadPart uploads one part of a multipart upload and returns its ETag.
+// Uploading the same part number again replaces it.
+func (s *S3Service) UploadPart(ctx context.Context, key, uploadID string, number int, data []byte) (string, error) {
+	out, err := s.client.UploadPartWithContext(ctx, &s3.UploadPartInput{
+		Bucket:     aws.String(s.bucketName),
+		Key:        aws.String(key),
+		UploadId:   aws.String(uploadID),
+		PartNumber: aws.Int64(int64(number)),
+		Body:       bytes.NewReader(data),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload part %d: %v", number, err)
+	}
+	metrics.S3UploadBytes.Add(float64(len(data)))
+	return aws.StringValue(out.ETag), nil
+}
+
+// CompleteMultipartUpload assembles the parts, given in part number order,
+// into the final object
+func (s *S3Service) CompleteMultipartUpload(ctx context.Context, key, uploadID string, etags []string) error {
+	parts := make([]*s3.CompletedPart, 0, len(etags))
+	for i, etag := range etags {
+		parts = append(parts, &s3.CompletedPart{PartNumber: aws.Int64(int64(i + 1)), ETag: aws.String(etag)})
+	}
+	_, err := s.client.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(s.bucketName),
+		Key:             aws.String(key),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %v", err)
+	}
+	return nil
+}
+
+// AbortMultipartUpload discards a multipart upload and the parts stored for
+// it. Unknown uploads are not an error.
+func (s *S3Service) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	_, err := s.client.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(s.bucketName),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchUpload {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to abort multipart upload: %v", err)
+	}
+	return nil
+}
+
+type progressReader struct {
+	reader     io.Reader
+	total      int64
+	sent       int64
+	onProgress func(sent, total int64)
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.sent += int64(n)
+		if r.onProgress != nil {
+			r.onProgress(r.sent, r.total)
+		}
+	}
+	return n, err
+}
+
 func (s *S3Service) isValidImageType(contentType string) bool {
 	validTypes := []string{
 		"image/jpeg",
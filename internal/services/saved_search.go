@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MaxSavedSearchesPerUser keeps the publish-time matcher cheap
+const MaxSavedSearchesPerUser = 20
+
+var (
+	ErrSavedSearchNotFound = errors.New("saved search not found")
+	ErrSavedSearchLimit    = errors.New("saved search limit reached")
+)
+
+type SavedSearchService struct {
+	db           *gorm.DB
+	emailService *EmailService
+	preferences  *PreferenceService
+	baseURL      string
+}
+
+// NewSavedSearchService also subscribes the publish-time matcher to the
+// event bus, so notifications go out once a product is committed as active.
+func NewSavedSearchService(db *gorm.DB, emailService *EmailService, preferences *PreferenceService, eventBus *EventBus, baseURL string) *SavedSearchService {
+	s := &SavedSearchService{db: db, emailService: emailService, preferences: preferences, baseURL: baseURL}
+	if eventBus != nil {
+		eventBus.Subscribe(s.handleEvent)
+	}
+	return s
+}
+
+func (s *SavedSearchService) GetSavedSearches(ctx context.Context, userID uint) ([]models.SavedSearch, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	searches := make([]models.SavedSearch, 0)
+	if err := s.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&searches).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch saved searches: %v", ErrDatabaseQuery, err)
+	}
+	return searches, nil
+}
+
+func (s *SavedSearchService) GetSavedSearch(ctx context.Context, userID, id uint) (*models.SavedSearch, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var search models.SavedSearch
+	if err := s.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&search).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: saved search %d not found", ErrSavedSearchNotFound, id)
+		}
+		return nil, fmt.Errorf("%w: failed to fetch saved search: %v", ErrDatabaseQuery, err)
+	}
+	return &search, nil
+}
+
+func (s *SavedSearchService) CreateSavedSearch(ctx context.Context, userID uint, req *models.CreateSavedSearchRequest) (*models.SavedSearch, error) {
+	search := models.SavedSearch{
+		UserID:   userID,
+		Name:     strings.TrimSpace(req.Name),
+		Category: strings.TrimSpace(req.Category),
+		Material: strings.TrimSpace(req.Material),
+		Search:   strings.TrimSpace(req.Search),
+		MinPrice: req.MinPrice,
+		MaxPrice: req.MaxPrice,
+		Notify:   req.Notify,
+	}
+	if err := validateSavedSearch(&search); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.SavedSearch{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to count saved searches: %v", ErrDatabaseQuery, err)
+	}
+	if count >= MaxSavedSearchesPerUser {
+		return nil, fmt.Errorf("%w: at most %d saved searches are allowed", ErrSavedSearchLimit, MaxSavedSearchesPerUser)
+	}
+
+	if err := createAllColumns(s.db.WithContext(ctx), &search); err != nil {
+		return nil, fmt.Errorf("%w: failed to create saved search: %v", ErrDatabaseQuery, err)
+	}
+	return &search, nil
+}
+
+func (s *SavedSearchService) UpdateSavedSearch(ctx context.Context, userID, id uint, req *models.UpdateSavedSearchRequest) (*models.SavedSearch, error) {
+	search, err := s.GetSavedSearch(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		search.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Category != nil {
+		search.Category = strings.TrimSpace(*req.Category)
+	}
+	if req.Material != nil {
+		search.Material = strings.TrimSpace(*req.Material)
+	}
+	if req.Search != nil {
+		search.Search = strings.TrimSpace(*req.Search)
+	}
+	if req.MinPrice != nil {
+		search.MinPrice = *req.MinPrice
+	}
+	if req.MaxPrice != nil {
+		search.MaxPrice = *req.MaxPrice
+	}
+	if req.Notify != nil {
+		search.Notify = *req.Notify
+	}
+	if err := validateSavedSearch(search); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	// Name the columns so cleared filters and notify=false are written too
+	err = s.db.WithContext(ctx).Model(search).
+		Select("name", "category", "material", "search", "min_price", "max_price", "notify").
+		Updates(search).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to update saved search: %v", ErrDatabaseQuery, err)
+	}
+	return search, nil
+}
+
+func (s *SavedSearchService) DeleteSavedSearch(ctx context.Context, userID, id uint) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&models.SavedSearch{})
+	if result.Error != nil {
+		return fmt.Errorf("%w: failed to delete saved search: %v", ErrDatabaseQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: saved search %d not found", ErrSavedSearchNotFound, id)
+	}
+	return nil
+}
+
+// SavedSearchFilter turns a saved search into the public listing filter, so
+// running a saved search returns exactly what the storefront search would.
+func SavedSearchFilter(search *models.SavedSearch) ProductFilter {
+	return ProductFilter{
+		Category: search.Category,
+		Material: search.Material,
+		Search:   search.Search,
+		MinPrice: search.MinPrice,
+		MaxPrice: search.MaxPrice,
+	}
+}
+
+func validateSavedSearch(search *models.SavedSearch) error {
+	if search.Name == "" {
+		return fmt.Errorf("%w: name cannot be empty", ErrInvalidInput)
+	}
+	if search.MinPrice > 0 && search.MaxPrice > 0 && search.MinPrice > search.MaxPrice {
+		return fmt.Errorf("%w: min_price cannot be greater than max_price", ErrInvalidInput)
+	}
+	if search.Category == "" && search.Material == "" && search.Search == "" && search.MinPrice == 0 && search.MaxPrice == 0 {
+		return fmt.Errorf("%w: a saved search needs at least one filter", ErrInvalidInput)
+	}
+	return nil
+}
+
+func (s *SavedSearchService) handleEvent(event Event) {
+	if event.Type != EventProductCreated && event.Type != EventProductUpdated {
+		return
+	}
+	var product models.Product
+	if err := decodeEventData(event.Data, &product); err != nil || product.ID == 0 {
+		return
+	}
+	if product.Status != models.ProductStatusActive {
+		return
+	}
+
+	if err := s.notifyMatches(context.Background(), &product); err != nil {
+		logger.Error("Failed to match saved searches for product ", product.ID, ": ", err)
+	}
+}
+
+// notifyMatches emails the owners of every saved search the product matches
+// and has not been announced to before.
+func (s *SavedSearchService) notifyMatches(ctx context.Context, product *models.Product) error {
+	// Price bounds narrow the candidates in SQL; text filters are checked below
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+	var searches []models.SavedSearch
+	err := s.db.WithContext(queryCtx).Preload("User").
+		Where("notify = ?", true).
+		Where("min_price = 0 OR min_price <= ?", product.Price).
+		Where("max_price = 0 OR max_price >= ?", product.Price).
+		Find(&searches).Error
+	if err != nil {
+		return fmt.Errorf("%w: failed to fetch saved searches: %v", ErrDatabaseQuery, err)
+	}
+
+	for i := range searches {
+		search := &searches[i]
+		if !savedSearchMatches(search, product) || !search.User.IsActive {
+			continue
+		}
+		// In multi-tenant mode customers only hear about their own store
+		if product.StoreID != nil && search.User.StoreID != nil && *product.StoreID != *search.User.StoreID {
+			continue
+		}
+		if !s.preferences.Allows(ctx, search.UserID, models.NotificationSavedSearchMatch, models.ChannelEmail) {
+			continue
+		}
+
+		// Sending mail can take a while, so these writes are not bound to
+		// the query deadline above
+		result := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.SavedSearchMatch{SavedSearchID: search.ID, ProductID: product.ID})
+		if result.Error != nil {
+			return fmt.Errorf("%w: failed to record saved search match: %v", ErrDatabaseQuery, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			continue // already announced
+		}
+
+		productURL := fmt.Sprintf("%s/products/%d", strings.TrimRight(s.baseURL, "/"), product.ID)
+		if err := s.emailService.SendSavedSearchMatch(search.User.Email, search.Name, product.Title, product.Price, productURL); err != nil {
+			logger.Error("Failed to send saved search notification: ", err)
+			continue
+		}
+		s.db.WithContext(ctx).Model(search).Update("last_notified_at", time.Now())
+	}
+	return nil
+}
+
+// savedSearchMatches mirrors ProductService.applyFilters
+func savedSearchMatches(search *models.SavedSearch, product *models.Product) bool {
+	contains := func(value, term string) bool {
+		return strings.Contains(strings.ToLower(value), strings.ToLower(term))
+	}
+	if search.Category != "" && !contains(product.Category, search.Category) {
+		return false
+	}
+	if search.Material != "" && !contains(product.Material, search.Material) {
+		return false
+	}
+	if search.Search != "" && !contains(product.Title, search.Search) && !contains(product.Description, search.Search) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+)
+
+// SMSSender delivers a text message to a phone number in E.164 format.
+type SMSSender interface {
+	SendSMS(ctx context.Context, to, message string) error
+}
+
+// NewSMSSender picks the provider from SMS_PROVIDER. "log" writes messages
+// to the application log and is meant for local development only.
+func NewSMSSender(cfg *config.Config) (SMSSender, error) {
+	switch strings.ToLower(cfg.SMSProvider) {
+	case "twilio":
+		if cfg.TwilioAccountSID == "" || cfg.TwilioAuthToken == "" || cfg.TwilioFromNumber == "" {
+			return nil, fmt.Errorf("twilio SMS provider requires TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN and TWILIO_FROM_NUMBER")
+		}
+		return &twilioSMSSender{
+			accountSID: cfg.TwilioAccountSID,
+			authToken:  cfg.TwilioAuthToken,
+			from:       cfg.TwilioFromNumber,
+			client:     &http.Client{Timeout: 10 * time.Second},
+		}, nil
+	case "sns":
+		// SNS shares the AWS credentials used for S3
+		sess, err := session.NewSession(&aws.Config{
+			Region:      aws.String(cfg.S3Region),
+			Credentials: credentials.NewStaticCredentials(cfg.S3AccessKey, cfg.S3SecretKey, ""),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create AWS session: %v", err)
+		}
+		return &snsSMSSender{client: sns.New(sess)}, nil
+	case "", "log":
+		return logSMSSender{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported SMS provider %q", cfg.SMSProvider)
+	}
+}
+
+type twilioSMSSender struct {
+	accountSID string
+	authToken  string
+	from       string
+	client     *http.Client
+}
+
+func (t *twilioSMSSender) SendSMS(ctx context.Context, to, message string) error {
+	endpoint := fmt.Sprintf("https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json", t.accountSID)
+	form := url.Values{"To": {to}, "From": {t.from}, "Body": {message}}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create twilio request: %v", err)
+	}
+	req.SetBasicAuth(t.accountSID, t.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send SMS via twilio: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("twilio returned status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+type snsSMSSender struct {
+	client *sns.SNS
+}
+
+func (s *snsSMSSender) SendSMS(ctx context.Context, to, message string) error {
+	_, err := s.client.PublishWithContext(ctx, &sns.PublishInput{
+		PhoneNumber: aws.String(to),
+		Message:     aws.String(message),
+		MessageAttributes: map[string]*sns.MessageAttributeValue{
+			"AWS.SNS.SMS.SMSType": {
+				DataType:    aws.String("String"),
+				StringValue: aws.String("Transactional"),
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send SMS via SNS: %v", err)
+	}
+	return nil
+}
+
+type logSMSSender struct{}
+
+func (logSMSSender) SendSMS(ctx context.Context, to, message string) error {
+	logger.WithFields(map[string]interface{}{"to": to}).Info("SMS (log provider): ", message)
+	return nil
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const MaxTrendDays = 365
+
+type SnapshotService struct {
+	db *gorm.DB
+}
+
+func NewSnapshotService(db *gorm.DB) *SnapshotService {
+	return &SnapshotService{db: db}
+}
+
+type MetricDelta struct {
+	Current       float64  `json:"current"`
+	Previous      float64  `json:"previous"`
+	Change        float64  `json:"change"`
+	ChangePercent *float64 `json:"change_percent"`
+}
+
+type MetricTrends struct {
+	Days      int                     `json:"days"`
+	Snapshots []models.MetricSnapshot `json:"snapshots"`
+	Deltas    map[string]MetricDelta  `json:"deltas"`
+}
+
+// CaptureSnapshot computes today's metrics and upserts them, so repeated runs
+// during the day keep the row current.
+func (s *SnapshotService) CaptureSnapshot(ctx context.Context) (*models.MetricSnapshot, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	db := s.db.WithContext(ctx)
+	now := time.Now().UTC()
+	snapshot := models.MetricSnapshot{
+		Date: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+	}
+
+	if err := db.Model(&models.Product{}).
+		Where("status <> ?", models.ProductStatusArchived).
+		Count(&snapshot.TotalProducts).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to count products: %v", ErrDatabaseQuery, err)
+	}
+	if err := db.Model(&models.Product{}).
+		Where("status = ?", models.ProductStatusActive).
+		Count(&snapshot.ActiveProducts).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to count active products: %v", ErrDatabaseQuery, err)
+	}
+	if err := db.Model(&models.User{}).
+		Where("is_active = ?", true).
+		Count(&snapshot.TotalUsers).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to count users: %v", ErrDatabaseQuery, err)
+	}
+	if err := db.Model(&models.Review{}).
+		Where("visibility = ?", models.ReviewVisibilityPublished).
+		Count(&snapshot.TotalReviews).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to count reviews: %v", ErrDatabaseQuery, err)
+	}
+
+	var stock struct {
+		Units int64
+		Value float64
+	}
+	if err := db.Model(&models.Product{}).
+		Select("COALESCE(SUM(stock), 0) AS units, COALESCE(SUM(stock * price), 0) AS value").
+		Where("status <> ?", models.ProductStatusArchived).
+		Scan(&stock).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to compute stock value: %v", ErrDatabaseQuery, err)
+	}
+	snapshot.StockUnits = stock.Units
+	snapshot.StockValue = stock.Value
+
+	err := db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"total_products", "active_products", "total_users", "total_reviews",
+			"stock_units", "stock_value", "updated_at",
+		}),
+	}).Create(&snapshot).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to store metric snapshot: %v", ErrDatabaseQuery, err)
+	}
+
+	return &snapshot, nil
+}
+
+// GetTrends returns the snapshots of the last `days` days together with the
+// change of each metric against the snapshot taken `days` before the latest.
+func (s *SnapshotService) GetTrends(ctx context.Context, days int) (*MetricTrends, error) {
+	if days < 1 || days > MaxTrendDays {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", ErrInvalidInput, MaxTrendDays)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	since := time.Now().UTC().AddDate(0, 0, -days)
+	snapshots := make([]models.MetricSnapshot, 0)
+	if err := s.db.WithContext(ctx).
+		Where("date >= ?", since).
+		Order("date ASC").
+		Find(&snapshots).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch metric snapshots: %v", ErrDatabaseQuery, err)
+	}
+
+	trends := &MetricTrends{
+		Days:      days,
+		Snapshots: snapshots,
+		Deltas:    map[string]MetricDelta{},
+	}
+	if len(snapshots) == 0 {
+		return trends, nil
+	}
+
+	latest := snapshots[len(snapshots)-1]
+	var previous models.MetricSnapshot
+	err := s.db.WithContext(ctx).
+		Where("date <= ?", latest.Date.AddDate(0, 0, -days)).
+		Order("date DESC").
+		First(&previous).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: failed to fetch previous snapshot: %v", ErrDatabaseQuery, err)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Not enough history yet, compare with the oldest snapshot in range
+		previous = snapshots[0]
+	}
+
+	trends.Deltas["total_products"] = newMetricDelta(float64(latest.TotalProducts), float64(previous.TotalProducts))
+	trends.Deltas["active_products"] = newMetricDelta(float64(latest.ActiveProducts), float64(previous.ActiveProducts))
+	trends.Deltas["total_users"] = newMetricDelta(float64(latest.TotalUsers), float64(previous.TotalUsers))
+	trends.Deltas["total_reviews"] = newMetricDelta(float64(latest.TotalReviews), float64(previous.TotalReviews))
+	trends.Deltas["stock_units"] = newMetricDelta(float64(latest.StockUnits), float64(previous.StockUnits))
+	trends.Deltas["stock_value"] = newMetricDelta(latest.StockValue, previous.StockValue)
+
+	return trends, nil
+}
+
+func newMetricDelta(current, previous float64) MetricDelta {
+	delta := MetricDelta{
+		Current:  current,
+		Previous: previous,
+		Change:   current - previous,
+	}
+	if previous != 0 {
+		percent := (current - previous) / previous * 100
+		delta.ChangePercent = &percent
+	}
+	return delta
+}
+
+// Run captures a snapshot immediately and then on every interval until the
+// context is cancelled.
+func (s *SnapshotService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.CaptureSnapshot(ctx); err != nil {
+			logger.Error("Failed to capture metric snapshot: ", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
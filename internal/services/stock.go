@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/database"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+// LowStockThreshold is the stock level at or below which a stock.low event
+// is raised, once per crossing.
+const LowStockThreshold = 5
+
+type StockService struct {
+	db *gorm.DB
+}
+
+func NewStockService(db *gorm.DB) *StockService {
+	return &StockService{db: db}
+}
+
+// AdjustStock applies a signed stock change and records it in the ledger.
+// Decrements never take stock below zero, even under concurrent requests.
+func (s *StockService) AdjustStock(ctx context.Context, productID, userID uint, req *models.StockAdjustmentRequest) (*models.StockMovement, error) {
+	if req.Delta == 0 {
+		return nil, fmt.Errorf("%w: delta must not be zero", ErrInvalidInput)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var movement *models.StockMovement
+	err := database.Conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		var err error
+		movement, err = adjustStockTx(tx, productID, req.Delta, req.Reason, req.Reference, req.Note, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return movement, nil
+}
+
+// DecrementStock removes quantity units for a sale, failing with
+// ErrInsufficientStock rather than overselling. Selling a bundle draws from
+// each of its components, all or nothing. The product's order limits apply
+// to quantity.
+func (s *StockService) DecrementStock(ctx context.Context, productID uint, quantity int, reference string) ([]models.StockMovement, error) {
+	if quantity <= 0 {
+		return nil, fmt.Errorf("%w: quantity must be positive", ErrInvalidInput)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var movements []models.StockMovement
+	err := database.Conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		if err := checkOrderLimits(tx, productID, quantity); err != nil {
+			return err
+		}
+		items, err := bundleItemsTx(tx, productID)
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			items = []models.ProductBundleItem{{ComponentID: productID, Quantity: 1}}
+		}
+
+		for _, item := range items {
+			movement, err := adjustStockTx(tx, item.ComponentID, -quantity*item.Quantity, models.StockReasonSale, reference, "", 0)
+			if err != nil {
+				return err
+			}
+			movements = append(movements, *movement)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+// CheckAvailability reports whether quantity units can be sold. A bundle is
+// available only when every component has enough stock. Quantities outside
+// the product's order limits fail with a QuantityLimitError.
+func (s *StockService) CheckAvailability(ctx context.Context, productID uint, quantity int) error {
+	if quantity <= 0 {
+		return fmt.Errorf("%w: quantity must be positive", ErrInvalidInput)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+	db := database.Conn(ctx, s.db)
+
+	if err := checkOrderLimits(db, productID, quantity); err != nil {
+		return err
+	}
+	items, err := bundleItemsTx(db, productID)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		items = []models.ProductBundleItem{{ComponentID: productID, Quantity: 1}}
+	}
+
+	for _, item := range items {
+		var product models.Product
+		if err := db.Select("id", "status", "stock").First(&product, item.ComponentID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: product with ID %d not found", ErrProductNotFound, item.ComponentID)
+			}
+			return fmt.Errorf("%w: failed to find product: %v", ErrDatabaseQuery, err)
+		}
+		needed := quantity * item.Quantity
+		if product.Status != models.ProductStatusActive || product.Stock < needed {
+			return fmt.Errorf("%w: product %d has %d of %d units", ErrInsufficientStock, product.ID, product.Stock, needed)
+		}
+	}
+	return nil
+}
+
+// GetStockMovements returns a product's online stock ledger, or that of one
+// store location when locationID is set, including archived movements
+func (s *StockService) GetStockMovements(ctx context.Context, productID, locationID uint, page, limit int) ([]models.StockMovement, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := database.Conn(ctx, s.db).Select("id").First(&models.Product{}, productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, fmt.Errorf("%w: product with ID %d not found", ErrProductNotFound, productID)
+		}
+		return nil, 0, fmt.Errorf("%w: failed to find product: %v", ErrDatabaseQuery, err)
+	}
+
+	query := database.Conn(ctx, s.db).Table("stock_movements_all").Where("product_id = ?", productID)
+	if locationID != 0 {
+		query = query.Where("location_id = ?", locationID)
+	} else {
+		query = query.Where("location_id IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to count stock movements: %v", ErrDatabaseQuery, err)
+	}
+
+	movements := make([]models.StockMovement, 0)
+	if err := query.Order("id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&movements).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to fetch stock movements: %v", ErrDatabaseQuery, err)
+	}
+	return movements, total, nil
+}
+
+// adjustStockTx performs an atomic conditional update so two concurrent
+// decrements can never both succeed against the last unit in stock.
+func adjustStockTx(tx *gorm.DB, productID uint, delta int, reason, reference, note string, userID uint) (*models.StockMovement, error) {
+	var updated models.Product
+	result := tx.Model(&updated).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "stock"}}}).
+		Where("id = ? AND stock + ? >= 0", productID, delta).
+		Update("stock", gorm.Expr("stock + ?", delta))
+	if result.Error != nil {
+		return nil, fmt.Errorf("%w: failed to update stock: %v", ErrDatabaseQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := tx.Model(&models.Product{}).Where("id = ?", productID).Count(&count).Error; err != nil {
+			return nil, fmt.Errorf("%w: failed to find product: %v", ErrDatabaseQuery, err)
+		}
+		if count == 0 {
+			return nil, fmt.Errorf("%w: product with ID %d not found", ErrProductNotFound, productID)
+		}
+		return nil, fmt.Errorf("%w: cannot remove %d units from product %d", ErrInsufficientStock, -delta, productID)
+	}
+
+	return recordStockMovement(tx, productID, delta, updated.Stock, reason, reference, note, userID)
+}
+
+// setStockTx overwrites the stock level, e.g. from the product edit form,
+// locking the row so the recorded delta is exact.
+func setStockTx(tx *gorm.DB, productID uint, stock int, reason, reference string, userID uint) error {
+	var product models.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "stock").First(&product, productID).Error; err != nil {
+		return fmt.Errorf("%w: failed to lock product stock: %v", ErrDatabaseQuery, err)
+	}
+	if product.Stock == stock {
+		return nil
+	}
+
+	if err := tx.Model(&product).Update("stock", stock).Error; err != nil {
+		return fmt.Errorf("%w: failed to update stock: %v", ErrDatabaseQuery, err)
+	}
+	_, err := recordStockMovement(tx, productID, stock-product.Stock, stock, reason, reference, "", userID)
+	return err
+}
+
+func recordStockMovement(tx *gorm.DB, productID uint, delta, stockAfter int, reason, reference, note string, userID uint) (*models.StockMovement, error) {
+	movement := &models.StockMovement{
+		ProductID:  productID,
+		Delta:      delta,
+		StockAfter: stockAfter,
+		Reason:     reason,
+		Reference:  reference,
+		Note:       note,
+		CreatedBy:  userID,
+	}
+	if err := tx.Create(movement).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to record stock movement: %v", ErrDatabaseQuery, err)
+	}
+
+	err := recordOutboxEvent(tx, EventStockChanged, "product", productID, map[string]interface{}{
+		"product_id": productID,
+		"delta":      delta,
+		"stock":      stockAfter,
+		"reason":     reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if stockAfter <= LowStockThreshold && stockAfter-delta > LowStockThreshold {
+		err = recordOutboxEvent(tx, EventStockLow, "product", productID, map[string]interface{}{
+			"product_id": productID,
+			"stock":      stockAfter,
+			"threshold":  LowStockThreshold,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return movement, nil
+}
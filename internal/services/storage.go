@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+)
+
+var ErrStorageReportNotFound = errors.New("no storage report available")
+
+const (
+	productImagePrefix     = "products/images/"
+	maxStorageReportItems  = 100
+	s3DeleteBatchSize      = 1000 // DeleteObjects limit
+	storageReconcileLookup = 30 * time.Second
+)
+
+// StorageService reconciles the product image prefix in S3 against Image rows
+type StorageService struct {
+	db         *gorm.DB
+	s3Service  *S3Service
+	jobService *JobService
+	cfg        *config.Config
+}
+
+func NewStorageService(db *gorm.DB, cfg *config.Config, s3Service *S3Service, jobService *JobService) *StorageService {
+	return &StorageService{
+		db:         db,
+		s3Service:  s3Service,
+		jobService: jobService,
+		cfg:        cfg,
+	}
+}
+
+// Run reconciles on every interval until the context is cancelled
+func (s *StorageService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Reconcile(ctx, "schedule"); err != nil {
+				logger.Error("Storage reconciliation failed: ", err)
+			}
+		}
+	}
+}
+
+// StartReconcile runs a reconciliation as a background job visible in the job console
+func (s *StorageService) StartReconcile(ctx context.Context, userID uint) (*models.Job, error) {
+	return s.jobService.Enqueue(ctx, models.JobTypeStorageReconcile, userID, func(ctx context.Context, run *JobRun) error {
+		run.Infof("Reconciling s3://%s against image records", productImagePrefix)
+		report, err := s.Reconcile(ctx, "manual")
+		if err != nil {
+			return err
+		}
+		run.Infof("Scanned %d objects: %d orphaned (%d deleted, %d in grace period), %d image rows missing their object",
+			report.ScannedObjects, report.OrphanObjects, report.DeletedOrphans, report.PendingOrphans, report.MissingImages)
+		run.SetResult(fmt.Sprintf("report #%d", report.ID), "")
+		return nil
+	})
+}
+
+// GetLatestReport returns the most recent finished reconciliation
+func (s *StorageService) GetLatestReport(ctx context.Context) (*models.StorageReport, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var report models.StorageReport
+	if err := s.db.WithContext(ctx).Order("started_at DESC").First(&report).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStorageReportNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to fetch storage report: %v", ErrDatabaseQuery, err)
+	}
+	return &report, nil
+}
+
+// Reconcile lists the image prefix, deletes orphans older than the grace
+// period (when enabled) and flags Image rows whose object is missing.
+func (s *StorageService) Reconcile(ctx context.Context, trigger string) (*models.StorageReport, error) {
+	report := &models.StorageReport{
+		Prefix:    productImagePrefix,
+		Trigger:   trigger,
+		StartedAt: time.Now(),
+	}
+
+	err := s.reconcile(ctx, report)
+	if err != nil {
+		report.Error = err.Error()
+	}
+	finished := time.Now()
+	report.FinishedAt = &finished
+
+	if saveErr := s.db.Create(report).Error; saveErr != nil {
+		logger.Error("Failed to save storage report: ", saveErr)
+	}
+	return report, err
+}
+
+func (s *StorageService) reconcile(ctx context.Context, report *models.StorageReport) error {
+	objects := make(map[string]S3Object)
+	if err := s.s3Service.ListObjects(productImagePrefix, func(obj S3Object) {
+		objects[obj.Key] = obj
+		report.ScannedObjects++
+		report.ScannedBytes += obj.Size
+	}); err != nil {
+		return err
+	}
+
+	var images []models.Image
+	lookupCtx, cancel := context.WithTimeout(ctx, storageReconcileLookup)
+	defer cancel()
+	if err := s.db.WithContext(lookupCtx).
+		Select("id", "product_id", "s3_key", "missing_at", "created_at").
+		Find(&images).Error; err != nil {
+		return fmt.Errorf("%w: failed to load image records: %v", ErrDatabaseQuery, err)
+	}
+
+	referenced := make(map[string]bool, len(images))
+	var missingIDs, foundIDs []string
+	for _, image := range images {
+		referenced[image.S3Key] = true
+		if _, ok := objects[image.S3Key]; ok {
+			report.ReferencedObjects++
+			if image.MissingAt != nil {
+				foundIDs = append(foundIDs, image.ID.String())
+			}
+			continue
+		}
+		// Rows created after the listing started may point at objects it didn't see
+		if image.CreatedAt.After(report.StartedAt) {
+			continue
+		}
+		report.MissingImages++
+		if image.MissingAt == nil {
+			missingIDs = append(missingIDs, image.ID.String())
+		}
+		if len(report.Missing) < maxStorageReportItems {
+			report.Missing = append(report.Missing, models.MissingImageRecord{
+				ImageID:   image.ID.String(),
+				ProductID: image.ProductID,
+				S3Key:     image.S3Key,
+			})
+		} else {
+			report.Truncated = true
+		}
+	}
+
+	if len(missingIDs) > 0 {
+		if err := s.db.WithContext(ctx).Model(&models.Image{}).Where("id IN ?", missingIDs).
+			Update("missing_at", report.StartedAt).Error; err != nil {
+			return fmt.Errorf("%w: failed to flag missing images: %v", ErrDatabaseQuery, err)
+		}
+	}
+	if len(foundIDs) > 0 {
+		if err := s.db.WithContext(ctx).Model(&models.Image{}).Where("id IN ?", foundIDs).
+			Update("missing_at", nil).Error; err != nil {
+			return fmt.Errorf("%w: failed to clear missing flags: %v", ErrDatabaseQuery, err)
+		}
+	}
+
+	cutoff := report.StartedAt.Add(-s.cfg.StorageOrphanGracePeriod)
+	var deletable []string
+	for key, obj := range objects {
+		if referenced[key] {
+			continue
+		}
+		report.OrphanObjects++
+		report.OrphanBytes += obj.Size
+		if len(report.Orphans) < maxStorageReportItems {
+			report.Orphans = append(report.Orphans, key)
+		} else {
+			report.Truncated = true
+		}
+
+		if obj.LastModified.After(cutoff) {
+			report.PendingOrphans++
+			continue
+		}
+		deletable = append(deletable, key)
+	}
+
+	if !s.cfg.StorageDeleteOrphans {
+		return nil
+	}
+	for start := 0; start < len(deletable); start += s3DeleteBatchSize {
+		end := start + s3DeleteBatchSize
+		if end > len(deletable) {
+			end = len(deletable)
+		}
+		if err := s.s3Service.DeleteMultipleImages(deletable[start:end]); err != nil {
+			return fmt.Errorf("failed to delete orphaned objects: %v", err)
+		}
+		report.DeletedOrphans += end - start
+	}
+	return nil
+}
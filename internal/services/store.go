@@ -0,0 +1,285 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// storeCacheTTL bounds how long another instance's store changes take to
+// reach this one; changes made here apply immediately
+const storeCacheTTL = time.Minute
+
+var (
+	ErrStoreNotFound = errors.New("store not found")
+	ErrStoreExists   = errors.New("store slug or domain already in use")
+	ErrStoreInUse    = errors.New("store still has products or users")
+)
+
+// StoreService manages the storefronts of a multi-tenant deployment and
+// resolves requests to a store from an in-memory copy of the store table.
+type StoreService struct {
+	db *gorm.DB
+
+	mu       sync.RWMutex
+	bySlug   map[string]*models.Store
+	byDomain map[string]*models.Store
+	loadedAt time.Time
+}
+
+func NewStoreService(db *gorm.DB) *StoreService {
+	return &StoreService{db: db}
+}
+
+func (s *StoreService) GetStores(ctx context.Context) ([]models.Store, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	stores := make([]models.Store, 0)
+	if err := s.db.WithContext(ctx).Order("name ASC").Find(&stores).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch stores: %v", ErrDatabaseQuery, err)
+	}
+	return stores, nil
+}
+
+func (s *StoreService) GetStore(ctx context.Context, id uint) (*models.Store, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var store models.Store
+	if err := s.db.WithContext(ctx).First(&store, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: store %d not found", ErrStoreNotFound, id)
+		}
+		return nil, fmt.Errorf("%w: failed to fetch store: %v", ErrDatabaseQuery, err)
+	}
+	return &store, nil
+}
+
+func (s *StoreService) CreateStore(ctx context.Context, req *models.CreateStoreRequest) (*models.Store, error) {
+	store := models.Store{
+		Name:     strings.TrimSpace(req.Name),
+		Slug:     slugify(req.Slug),
+		Domain:   normalizeHost(req.Domain),
+		IsActive: true,
+	}
+	if store.Slug == "" {
+		store.Slug = slugify(store.Name)
+	}
+	if req.IsActive != nil {
+		store.IsActive = *req.IsActive
+	}
+	if store.Name == "" || store.Slug == "" {
+		return nil, fmt.Errorf("%w: store name must contain letters or digits", ErrInvalidInput)
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := s.checkStoreFree(queryCtx, &store); err != nil {
+		return nil, err
+	}
+	if err := createAllColumns(s.db.WithContext(queryCtx), &store); err != nil {
+		return nil, fmt.Errorf("%w: failed to create store: %v", ErrDatabaseQuery, err)
+	}
+
+	s.invalidate()
+	return &store, nil
+}
+
+func (s *StoreService) UpdateStore(ctx context.Context, id uint, req *models.UpdateStoreRequest) (*models.Store, error) {
+	store, err := s.GetStore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		store.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Slug != nil {
+		store.Slug = slugify(*req.Slug)
+	}
+	if req.Domain != nil {
+		store.Domain = normalizeHost(*req.Domain)
+	}
+	if req.IsActive != nil {
+		store.IsActive = *req.IsActive
+	}
+	if store.Name == "" || store.Slug == "" {
+		return nil, fmt.Errorf("%w: store name and slug cannot be empty", ErrInvalidInput)
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := s.checkStoreFree(queryCtx, store); err != nil {
+		return nil, err
+	}
+	err = s.db.WithContext(queryCtx).Model(store).
+		Select("name", "slug", "domain", "is_active").
+		Updates(store).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to update store: %v", ErrDatabaseQuery, err)
+	}
+
+	s.invalidate()
+	return store, nil
+}
+
+// DeleteStore removes an empty store. Stores with products or users are
+// deactivated instead, so their data is never orphaned.
+func (s *StoreService) DeleteStore(ctx context.Context, id uint) error {
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	for _, model := range []interface{}{&models.Product{}, &models.User{}} {
+		var count int64
+		if err := s.db.WithContext(queryCtx).Model(model).Where("store_id = ?", id).Count(&count).Error; err != nil {
+			return fmt.Errorf("%w: failed to check store usage: %v", ErrDatabaseQuery, err)
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: deactivate it instead", ErrStoreInUse)
+		}
+	}
+
+	result := s.db.WithContext(queryCtx).Delete(&models.Store{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("%w: failed to delete store: %v", ErrDatabaseQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: store %d not found", ErrStoreNotFound, id)
+	}
+
+	s.invalidate()
+	return nil
+}
+
+// Resolve finds the active store for a request: by slug when the tenant
+// header is set, otherwise by the request host.
+func (s *StoreService) Resolve(ctx context.Context, slug, host string) (*models.Store, error) {
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var store *models.Store
+	if slug = strings.ToLower(strings.TrimSpace(slug)); slug != "" {
+		store = s.bySlug[slug]
+	} else {
+		store = s.byDomain[normalizeHost(host)]
+	}
+	if store == nil || !store.IsActive {
+		return nil, ErrStoreNotFound
+	}
+	return store, nil
+}
+
+// OwnsProduct reports whether a product belongs to a store
+func (s *StoreService) OwnsProduct(ctx context.Context, storeID, productID uint) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var count int64
+	err := s.db.WithContext(ctx).Model(&models.Product{}).
+		Where("id = ? AND store_id = ?", productID, storeID).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("%w: failed to check product store: %v", ErrDatabaseQuery, err)
+	}
+	return count > 0, nil
+}
+
+func (s *StoreService) refresh(ctx context.Context) error {
+	s.mu.RLock()
+	fresh := time.Since(s.loadedAt) < storeCacheTTL
+	s.mu.RUnlock()
+	if fresh {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var stores []models.Store
+	if err := s.db.WithContext(ctx).Find(&stores).Error; err != nil {
+		return fmt.Errorf("%w: failed to load stores: %v", ErrDatabaseQuery, err)
+	}
+
+	bySlug := make(map[string]*models.Store, len(stores))
+	byDomain := make(map[string]*models.Store, len(stores))
+	for i := range stores {
+		bySlug[stores[i].Slug] = &stores[i]
+		if stores[i].Domain != "" {
+			byDomain[stores[i].Domain] = &stores[i]
+		}
+	}
+
+	s.mu.Lock()
+	s.bySlug, s.byDomain, s.loadedAt = bySlug, byDomain, time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *StoreService) invalidate() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+func (s *StoreService) checkStoreFree(ctx context.Context, store *models.Store) error {
+	query := s.db.WithContext(ctx).Model(&models.Store{}).Where("id <> ?", store.ID)
+	if store.Domain != "" {
+		query = query.Where("slug = ? OR domain = ?", store.Slug, store.Domain)
+	} else {
+		query = query.Where("slug = ?", store.Slug)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return fmt.Errorf("%w: failed to check store: %v", ErrDatabaseQuery, err)
+	}
+	if count > 0 {
+		return ErrStoreExists
+	}
+	return nil
+}
+
+// storeScope limits a query to one store; zero (single-tenant mode) leaves it
+// unscoped
+func storeScope(storeID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if storeID == 0 {
+			return db
+		}
+		return db.Where("store_id = ?", storeID)
+	}
+}
+
+// storeUserScope limits a user query to the accounts that can sign in to a
+// store: its own users and users without a store. Zero (single-tenant mode)
+// leaves it unscoped.
+func storeUserScope(storeID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if storeID == 0 {
+			return db
+		}
+		return db.Where("(store_id = ? OR store_id IS NULL)", storeID)
+	}
+}
+
+// normalizeHost lowercases a host name and drops any port
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if i := strings.LastIndex(host, ":"); i != -1 && !strings.Contains(host[i:], "]") {
+		host = host[:i]
+	}
+	return strings.TrimSuffix(host, ".")
+}
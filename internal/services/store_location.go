@@ -0,0 +1,384 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrLocationNotFound = errors.New("store location not found")
+
+// LocationService manages physical store locations, their pickup
+// availability and the stock each one holds
+type LocationService struct {
+	db *gorm.DB
+}
+
+func NewLocationService(db *gorm.DB) *LocationService {
+	return &LocationService{db: db}
+}
+
+// GetLocations lists all of a store's locations for admins
+func (s *LocationService) GetLocations(ctx context.Context, storeID uint) ([]models.StoreLocation, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	locations := []models.StoreLocation{}
+	if err := s.db.WithContext(ctx).Scopes(storeScope(storeID)).Order("name ASC").Find(&locations).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch locations: %v", ErrDatabaseQuery, err)
+	}
+	return locations, nil
+}
+
+func (s *LocationService) GetLocation(ctx context.Context, storeID, id uint) (*models.StoreLocation, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var location models.StoreLocation
+	if err := findLocation(s.db.WithContext(ctx), storeID, id, &location); err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+// GetPickupLocations lists the active locations offering pickup. With a
+// productID each location says whether it holds quantity units; with
+// coordinates the nearest come first and carry their distance.
+func (s *LocationService) GetPickupLocations(ctx context.Context, storeID, productID uint, quantity int, lat, lng *float64) ([]models.PickupLocation, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	db := s.db.WithContext(ctx)
+	var locations []models.StoreLocation
+	if err := db.Scopes(storeScope(storeID)).
+		Where("is_active = ? AND pickup_enabled = ?", true, true).
+		Order("name ASC").
+		Find(&locations).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch locations: %v", ErrDatabaseQuery, err)
+	}
+
+	stock := make(map[uint]int)
+	if productID != 0 {
+		if quantity < 1 {
+			quantity = 1
+		}
+		var rows []models.LocationStock
+		if err := db.Where("product_id = ?", productID).Find(&rows).Error; err != nil {
+			return nil, fmt.Errorf("%w: failed to fetch location stock: %v", ErrDatabaseQuery, err)
+		}
+		for _, row := range rows {
+			stock[row.LocationID] = row.Quantity
+		}
+	}
+
+	pickups := make([]models.PickupLocation, len(locations))
+	for i, location := range locations {
+		pickups[i].StoreLocation = location
+		if productID != 0 {
+			held := stock[location.ID]
+			available := held >= quantity
+			pickups[i].Quantity = &held
+			pickups[i].Available = &available
+		}
+		if lat != nil && lng != nil && location.Latitude != nil && location.Longitude != nil {
+			distance := math.Round(haversineKm(*lat, *lng, *location.Latitude, *location.Longitude)*10) / 10
+			pickups[i].DistanceKm = &distance
+		}
+	}
+
+	if lat != nil && lng != nil {
+		// Locations without coordinates go last
+		sort.SliceStable(pickups, func(i, j int) bool {
+			a, b := pickups[i].DistanceKm, pickups[j].DistanceKm
+			if a == nil || b == nil {
+				return a != nil
+			}
+			return *a < *b
+		})
+	}
+	return pickups, nil
+}
+
+func (s *LocationService) CreateLocation(ctx context.Context, storeID uint, req *models.CreateStoreLocationRequest) (*models.StoreLocation, error) {
+	if err := validateOpeningHours(req.Hours); err != nil {
+		return nil, err
+	}
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		return nil, fmt.Errorf("%w: latitude and longitude must be set together", ErrInvalidInput)
+	}
+
+	location := models.StoreLocation{
+		Name:          strings.TrimSpace(req.Name),
+		AddressLine1:  req.AddressLine1,
+		AddressLine2:  req.AddressLine2,
+		City:          req.City,
+		Region:        req.Region,
+		Postcode:      strings.TrimSpace(req.Postcode),
+		Country:       strings.ToUpper(req.Country),
+		Latitude:      req.Latitude,
+		Longitude:     req.Longitude,
+		Phone:         req.Phone,
+		Hours:         req.Hours,
+		PickupEnabled: true,
+		IsActive:      true,
+	}
+	if location.Hours == nil {
+		location.Hours = []models.OpeningHours{}
+	}
+	if storeID != 0 {
+		location.StoreID = &storeID
+	}
+	if req.PickupEnabled != nil {
+		location.PickupEnabled = *req.PickupEnabled
+	}
+	if req.IsActive != nil {
+		location.IsActive = *req.IsActive
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := createAllColumns(s.db.WithContext(ctx), &location); err != nil {
+		return nil, fmt.Errorf("%w: failed to create location: %v", ErrDatabaseQuery, err)
+	}
+	return &location, nil
+}
+
+func (s *LocationService) UpdateLocation(ctx context.Context, storeID, id uint, req *models.UpdateStoreLocationRequest) (*models.StoreLocation, error) {
+	location, err := s.GetLocation(ctx, storeID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updateData := make(map[string]interface{})
+	if req.Name != nil {
+		updateData["name"] = strings.TrimSpace(*req.Name)
+	}
+	if req.AddressLine1 != nil {
+		updateData["address_line1"] = *req.AddressLine1
+	}
+	if req.AddressLine2 != nil {
+		updateData["address_line2"] = *req.AddressLine2
+	}
+	if req.City != nil {
+		updateData["city"] = *req.City
+	}
+	if req.Region != nil {
+		updateData["region"] = *req.Region
+	}
+	if req.Postcode != nil {
+		updateData["postcode"] = strings.TrimSpace(*req.Postcode)
+	}
+	if req.Country != nil {
+		updateData["country"] = strings.ToUpper(*req.Country)
+	}
+	if req.Latitude != nil || req.Longitude != nil {
+		if req.Latitude == nil || req.Longitude == nil {
+			return nil, fmt.Errorf("%w: latitude and longitude must be set together", ErrInvalidInput)
+		}
+		updateData["latitude"] = *req.Latitude
+		updateData["longitude"] = *req.Longitude
+	}
+	if req.Phone != nil {
+		updateData["phone"] = *req.Phone
+	}
+	if req.Hours != nil {
+		if err := validateOpeningHours(*req.Hours); err != nil {
+			return nil, err
+		}
+		location.Hours = *req.Hours
+		if location.Hours == nil {
+			location.Hours = []models.OpeningHours{}
+		}
+	}
+	if req.PickupEnabled != nil {
+		updateData["pickup_enabled"] = *req.PickupEnabled
+	}
+	if req.IsActive != nil {
+		updateData["is_active"] = *req.IsActive
+	}
+
+	if len(updateData) == 0 && req.Hours == nil {
+		return location, nil
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	db := s.db.WithContext(queryCtx)
+	if req.Hours != nil {
+		// Hours goes through the struct so its JSON serializer runs
+		if err := db.Model(location).Select("hours").Updates(&models.StoreLocation{Hours: location.Hours}).Error; err != nil {
+			return nil, fmt.Errorf("%w: failed to update location: %v", ErrDatabaseQuery, err)
+		}
+	}
+	if len(updateData) > 0 {
+		if err := db.Model(location).Updates(updateData).Error; err != nil {
+			return nil, fmt.Errorf("%w: failed to update location: %v", ErrDatabaseQuery, err)
+		}
+	}
+	return s.GetLocation(ctx, storeID, id)
+}
+
+// DeleteLocation removes a location together with its stock records. Its
+// ledger entries are kept.
+func (s *LocationService) DeleteLocation(ctx context.Context, storeID, id uint) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).Scopes(storeScope(storeID)).Delete(&models.StoreLocation{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("%w: failed to delete location: %v", ErrDatabaseQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: location %d", ErrLocationNotFound, id)
+	}
+	return nil
+}
+
+// GetLocationStock lists the products a location holds
+func (s *LocationService) GetLocationStock(ctx context.Context, storeID, locationID uint, page, limit int) ([]models.LocationStock, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	db := s.db.WithContext(ctx)
+	if err := findLocation(db, storeID, locationID, &models.StoreLocation{}); err != nil {
+		return nil, 0, err
+	}
+
+	query := db.Model(&models.LocationStock{}).Where("location_id = ?", locationID)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to count location stock: %v", ErrDatabaseQuery, err)
+	}
+
+	stock := []models.LocationStock{}
+	if err := query.Preload("Product", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "title", "sku", "price", "status")
+	}).
+		Order("product_id ASC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&stock).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to fetch location stock: %v", ErrDatabaseQuery, err)
+	}
+	return stock, total, nil
+}
+
+// AdjustLocationStock applies a signed change to a location's stock of a
+// product and records it in the stock ledger. Stock never goes below zero.
+func (s *LocationService) AdjustLocationStock(ctx context.Context, storeID, locationID, userID uint, req *models.LocationStockAdjustmentRequest) (*models.StockMovement, error) {
+	if req.Delta == 0 {
+		return nil, fmt.Errorf("%w: delta must not be zero", ErrInvalidInput)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var movement models.StockMovement
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := findLocation(tx, storeID, locationID, &models.StoreLocation{}); err != nil {
+			return err
+		}
+		if err := tx.Scopes(storeScope(storeID)).Select("id").First(&models.Product{}, req.ProductID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: product with ID %d not found", ErrProductNotFound, req.ProductID)
+			}
+			return fmt.Errorf("%w: failed to find product: %v", ErrDatabaseQuery, err)
+		}
+
+		// Make sure the row exists, then lock it so the check and the
+		// update see the same quantity
+		stock := models.LocationStock{LocationID: locationID, ProductID: req.ProductID}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&stock).Error; err != nil {
+			return fmt.Errorf("%w: failed to create location stock: %v", ErrDatabaseQuery, err)
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("location_id = ? AND product_id = ?", locationID, req.ProductID).
+			First(&stock).Error; err != nil {
+			return fmt.Errorf("%w: failed to lock location stock: %v", ErrDatabaseQuery, err)
+		}
+		if stock.Quantity+req.Delta < 0 {
+			return fmt.Errorf("%w: location %d holds %d units of product %d", ErrInsufficientStock, locationID, stock.Quantity, req.ProductID)
+		}
+
+		stock.Quantity += req.Delta
+		if err := tx.Model(&stock).Updates(map[string]interface{}{
+			"quantity":   stock.Quantity,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+			return fmt.Errorf("%w: failed to update location stock: %v", ErrDatabaseQuery, err)
+		}
+
+		movement = models.StockMovement{
+			ProductID:  req.ProductID,
+			LocationID: &locationID,
+			Delta:      req.Delta,
+			StockAfter: stock.Quantity,
+			Reason:     req.Reason,
+			Reference:  req.Reference,
+			Note:       req.Note,
+			CreatedBy:  userID,
+		}
+		if err := tx.Create(&movement).Error; err != nil {
+			return fmt.Errorf("%w: failed to record stock movement: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &movement, nil
+}
+
+func findLocation(db *gorm.DB, storeID, id uint, location *models.StoreLocation) error {
+	if err := db.Scopes(storeScope(storeID)).First(location, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: location %d", ErrLocationNotFound, id)
+		}
+		return fmt.Errorf("%w: failed to find location: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
+
+// validateOpeningHours checks that every period is a valid HH:MM range that
+// closes after it opens
+func validateOpeningHours(hours []models.OpeningHours) error {
+	for _, period := range hours {
+		if period.Day < 0 || period.Day > 6 {
+			return fmt.Errorf("%w: day must be 0 (Sunday) to 6", ErrInvalidInput)
+		}
+		opens, err := time.Parse("15:04", period.Opens)
+		if err != nil {
+			return fmt.Errorf("%w: invalid opening time %q", ErrInvalidInput, period.Opens)
+		}
+		closes, err := time.Parse("15:04", period.Closes)
+		if err != nil {
+			return fmt.Errorf("%w: invalid closing time %q", ErrInvalidInput, period.Closes)
+		}
+		if !closes.After(opens) {
+			return fmt.Errorf("%w: closing time must be after opening time", ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// haversineKm is the great-circle distance between two points in kilometres
+func haversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	const earthRadiusKm = 6371
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
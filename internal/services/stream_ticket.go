@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// streamTicketTTL is how long a stream ticket can open its stream. A stream
+// opened in time stays open after the ticket expires.
+const streamTicketTTL = 30 * time.Second
+
+type StreamTicketRequest struct {
+	// Path is the stream the ticket opens, e.g. /api/v1/admin/events
+	Path string `json:"path" binding:"required"`
+}
+
+type StreamTicket struct {
+	Ticket    string `json:"ticket"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// IssueStreamTicket hands the caller a ticket for opening the stream at path
+// from a browser, which cannot send the Authorization header on WebSocket
+// and EventSource requests. The ticket acts as the caller, including an
+// impersonating admin, but only on that path and only briefly, so a copy
+// left in a log or the browser history is of little use.
+func (s *AuthService) IssueStreamTicket(ctx context.Context, userID, impersonatorID uint, req *StreamTicketRequest) (*StreamTicket, error) {
+	if !strings.HasPrefix(req.Path, "/") || strings.ContainsAny(req.Path, "?#") {
+		return nil, fmt.Errorf("%w: path must be a URL path such as /api/v1/admin/events", ErrInvalidInput)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var user models.User
+	if err := s.db.WithContext(ctx).Where("id = ? AND is_active = ?", userID, true).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to fetch user: %v", ErrDatabaseQuery, err)
+	}
+
+	ticket, expiresAt, err := utils.GenerateStreamTicket(user.ID, user.StoreID, user.Email, user.Role, impersonatorID, req.Path, streamTicketTTL, s.jwtSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate stream ticket: %v", err)
+	}
+	return &StreamTicket{Ticket: ticket, ExpiresAt: expiresAt.Unix()}, nil
+}
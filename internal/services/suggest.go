@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// Suggestion types
+const (
+	SuggestTypeTitle    = "title"
+	SuggestTypeCategory = "category"
+	SuggestTypeMaterial = "material"
+)
+
+const (
+	MaxSuggestions = 20
+
+	// suggestRebuildDelay batches bursts of product changes (e.g. an import)
+	// into a single rebuild
+	suggestRebuildDelay = 2 * time.Second
+	// suggestScanLimit bounds how many prefix matches are ranked per query
+	suggestScanLimit = 500
+)
+
+type Suggestion struct {
+	Text      string `json:"text"`
+	Type      string `json:"type"`
+	ProductID uint   `json:"product_id,omitempty"` // set for title suggestions
+	Count     int    `json:"count,omitempty"`      // products in a category or material
+}
+
+// suggestEntry indexes a suggestion under one of its words, so "shirt"
+// finds "Linen Shirt" as well as "Shirt Dress".
+type suggestEntry struct {
+	key        string
+	wordOffset int  // 0 when key is the start of the suggestion
+	storeID    uint // 0 for products outside any store (single-tenant mode)
+	suggestion *Suggestion
+}
+
+// SuggestService answers typeahead queries from an in-memory prefix index of
+// active product titles, categories and materials. The index is rebuilt
+// periodically and shortly after product changes on the event bus.
+type SuggestService struct {
+	db      *gorm.DB
+	mu      sync.RWMutex
+	entries []suggestEntry // sorted by key
+	stale   chan struct{}
+}
+
+func NewSuggestService(db *gorm.DB, eventBus *EventBus) *SuggestService {
+	s := &SuggestService{db: db, stale: make(chan struct{}, 1)}
+	if eventBus != nil {
+		eventBus.Subscribe(s.handleEvent)
+	}
+	return s
+}
+
+// Run builds the index and keeps it fresh until ctx is cancelled
+func (s *SuggestService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+
+	s.rebuild(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var pending <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.rebuild(ctx)
+		case <-s.stale:
+			if pending == nil {
+				pending = time.After(suggestRebuildDelay)
+			}
+		case <-pending:
+			pending = nil
+			s.rebuild(ctx)
+		}
+	}
+}
+
+func (s *SuggestService) handleEvent(event Event) {
+	switch event.Type {
+	case EventProductCreated, EventProductUpdated, EventProductDeleted, EventCatalogChanged:
+		select {
+		case s.stale <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (s *SuggestService) rebuild(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var products []models.Product
+	err := s.db.WithContext(ctx).
+		Select("id", "title", "category", "material", "store_id").
+		Where("status = ?", models.ProductStatusActive).
+		Find(&products).Error
+	if err != nil {
+		logger.Error("Failed to rebuild suggestion index: ", err)
+		return
+	}
+
+	var entries []suggestEntry
+	grouped := map[string]*Suggestion{}
+	// Categories and materials are counted per store
+	group := func(storeID uint, kind, text string) {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return
+		}
+		id := fmt.Sprintf("%d:%s:%s", storeID, kind, strings.ToLower(text))
+		if existing, ok := grouped[id]; ok {
+			existing.Count++
+			return
+		}
+		suggestion := &Suggestion{Text: text, Type: kind, Count: 1}
+		grouped[id] = suggestion
+		entries = append(entries, indexSuggestion(suggestion, storeID)...)
+	}
+
+	for _, product := range products {
+		var storeID uint
+		if product.StoreID != nil {
+			storeID = *product.StoreID
+		}
+		if title := strings.TrimSpace(product.Title); title != "" {
+			entries = append(entries, indexSuggestion(&Suggestion{Text: title, Type: SuggestTypeTitle, ProductID: product.ID}, storeID)...)
+		}
+		group(storeID, SuggestTypeCategory, product.Category)
+		group(storeID, SuggestTypeMaterial, product.Material)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+
+	s.mu.Lock()
+	s.entries = entries
+	s.mu.Unlock()
+}
+
+func indexSuggestion(suggestion *Suggestion, storeID uint) []suggestEntry {
+	words := strings.Fields(strings.ToLower(suggestion.Text))
+	entries := make([]suggestEntry, 0, len(words))
+	for i := range words {
+		entries = append(entries, suggestEntry{
+			key:        strings.Join(words[i:], " "),
+			wordOffset: i,
+			storeID:    storeID,
+			suggestion: suggestion,
+		})
+	}
+	return entries
+}
+
+// Suggest returns up to limit suggestions for a typed prefix. Matches at the
+// start of a suggestion rank above matches on a later word, then categories
+// and materials with more products, then shorter text. A non-zero storeID
+// limits suggestions to that store's products.
+func (s *SuggestService) Suggest(query string, limit int, storeID uint) ([]Suggestion, error) {
+	query = strings.Join(strings.Fields(strings.ToLower(query)), " ")
+	if query == "" {
+		return nil, fmt.Errorf("%w: query is required", ErrInvalidInput)
+	}
+	if limit <= 0 || limit > MaxSuggestions {
+		limit = MaxSuggestions
+	}
+
+	s.mu.RLock()
+	entries := s.entries
+	s.mu.RUnlock()
+
+	type match struct {
+		suggestion *Suggestion
+		wordOffset int
+	}
+	best := map[*Suggestion]int{}
+	var matches []match
+	start := sort.Search(len(entries), func(i int) bool { return entries[i].key >= query })
+	for i := start; i < len(entries) && len(matches) < suggestScanLimit; i++ {
+		entry := entries[i]
+		if !strings.HasPrefix(entry.key, query) {
+			break
+		}
+		if storeID != 0 && entry.storeID != storeID {
+			continue
+		}
+		if index, ok := best[entry.suggestion]; ok {
+			if entry.wordOffset < matches[index].wordOffset {
+				matches[index].wordOffset = entry.wordOffset
+			}
+			continue
+		}
+		best[entry.suggestion] = len(matches)
+		matches = append(matches, match{suggestion: entry.suggestion, wordOffset: entry.wordOffset})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if (a.wordOffset == 0) != (b.wordOffset == 0) {
+			return a.wordOffset == 0
+		}
+		if a.suggestion.Count != b.suggestion.Count {
+			return a.suggestion.Count > b.suggestion.Count
+		}
+		return len(a.suggestion.Text) < len(b.suggestion.Text)
+	})
+
+	suggestions := make([]Suggestion, 0, limit)
+	for _, m := range matches {
+		if len(suggestions) == limit {
+			break
+		}
+		suggestions = append(suggestions, *m.suggestion)
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/metrics"
+)
+
+const (
+	tokenCleanupBatchSize = 1000
+	// tokenCleanupRunRetention is how long cleanup run records are kept
+	tokenCleanupRunRetention = 30 * 24 * time.Hour
+)
+
+// RunTokenCleanup periodically calls CleanupTokens until ctx is cancelled
+func (s *AuthService) RunTokenCleanup(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.CleanupTokens(ctx); err != nil {
+				s.log.Error("Token cleanup failed: ", err)
+			}
+		}
+	}
+}
+
+// CleanupTokens deletes refresh tokens that are expired, or were revoked more
+// than refreshTokenRetention ago, and password reset tokens that are used or
+// expired. Revoked refresh tokens are kept for a while so reuse of a recently
+// rotated token is still detected. Rows are deleted in batches so a large
+// backlog does not hold locks for long. The run is recorded for the admin
+// dashboard even when it fails part way.
+func (s *AuthService) CleanupTokens(ctx context.Context) (*models.TokenCleanupRun, error) {
+	run := &models.TokenCleanupRun{StartedAt: time.Now()}
+
+	err := s.cleanupTokens(ctx, run)
+	if err != nil {
+		run.Error = err.Error()
+	}
+	finished := time.Now()
+	run.FinishedAt = &finished
+
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), QueryTimeout)
+	defer cancel()
+	if saveErr := s.db.WithContext(saveCtx).Create(run).Error; saveErr != nil {
+		s.log.Error("Failed to record token cleanup run: ", saveErr)
+	}
+	if pruneErr := s.db.WithContext(saveCtx).
+		Where("started_at < ?", finished.Add(-tokenCleanupRunRetention)).
+		Delete(&models.TokenCleanupRun{}).Error; pruneErr != nil {
+		s.log.Error("Failed to prune token cleanup runs: ", pruneErr)
+	}
+
+	if run.RefreshTokens > 0 || run.PasswordResetTokens > 0 {
+		s.log.Info(fmt.Sprintf("Deleted %d refresh tokens and %d password reset tokens",
+			run.RefreshTokens, run.PasswordResetTokens))
+	}
+	return run, err
+}
+
+func (s *AuthService) cleanupTokens(ctx context.Context, run *models.TokenCleanupRun) error {
+	now := time.Now()
+
+	var err error
+	run.RefreshTokens, err = s.deleteInBatches(ctx, &models.RefreshToken{}, metrics.TokenKindRefresh,
+		"expires_at < ? OR (is_revoked = ? AND updated_at < ?)", now, true, now.Add(-refreshTokenRetention))
+	if err != nil {
+		return fmt.Errorf("%w: failed to delete refresh tokens: %v", ErrDatabaseQuery, err)
+	}
+
+	run.PasswordResetTokens, err = s.deleteInBatches(ctx, &models.PasswordResetToken{}, metrics.TokenKindPasswordReset,
+		"is_used = ? OR expires_at < ?", true, now)
+	if err != nil {
+		return fmt.Errorf("%w: failed to delete password reset tokens: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
+
+// deleteInBatches deletes the rows of model matching the condition,
+// tokenCleanupBatchSize at a time, and returns how many were deleted
+func (s *AuthService) deleteInBatches(ctx context.Context, model interface{}, kind string, query string, args ...interface{}) (int64, error) {
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		batchCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+		ids := s.db.WithContext(batchCtx).Model(model).Select("id").Where(query, args...).Limit(tokenCleanupBatchSize)
+		result := s.db.WithContext(batchCtx).Where("id IN (?)", ids).Delete(model)
+		cancel()
+		if result.Error != nil {
+			return total, result.Error
+		}
+
+		total += result.RowsAffected
+		metrics.TokensCleaned.WithLabelValues(kind).Add(float64(result.RowsAffected))
+		if result.RowsAffected < tokenCleanupBatchSize {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,355 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// s3MaxParts is the most parts an S3 multipart upload can have
+	s3MaxParts = 10000
+	// uploadExpiryInterval is how often abandoned uploads are looked for
+	uploadExpiryInterval = 15 * time.Minute
+)
+
+var (
+	ErrUploadNotFound = errors.New("upload not found")
+	// ErrUploadClosed means the upload was completed, aborted or expired
+	ErrUploadClosed = errors.New("upload is no longer open")
+)
+
+// videoContentTypes are accepted by resumable uploads besides images
+var videoContentTypes = map[string]bool{
+	"video/mp4":       true,
+	"video/webm":      true,
+	"video/quicktime": true,
+}
+
+// UploadService runs resumable uploads of large media on top of S3 multipart
+// uploads, so a dropped connection only costs the part in flight. Sessions
+// left unfinished past UploadSessionTTL are aborted by Run.
+type UploadService struct {
+	db        *gorm.DB
+	cfg       *config.Config
+	s3Service *S3Service
+}
+
+func NewUploadService(db *gorm.DB, cfg *config.Config, s3Service *S3Service) *UploadService {
+	return &UploadService{db: db, cfg: cfg, s3Service: s3Service}
+}
+
+// CreateUpload starts a session and its S3 multipart upload. Images uploaded
+// for a product are added to it on completion, under the product image prefix
+// so storage reconciliation covers them; everything else goes under uploads/.
+func (s *UploadService) CreateUpload(ctx context.Context, userID uint, req *models.CreateUploadRequest) (*models.UploadSession, error) {
+	contentType := strings.ToLower(strings.TrimSpace(req.ContentType))
+	isImage := s.s3Service.isValidImageType(contentType)
+	if !isImage && !videoContentTypes[contentType] {
+		return nil, fmt.Errorf("%w: unsupported content type %q", ErrInvalidInput, req.ContentType)
+	}
+	if maxSize := int64(s.cfg.UploadMaxSizeMB) << 20; req.Size > maxSize {
+		return nil, fmt.Errorf("%w: file exceeds the %d MB upload limit", ErrInvalidInput, s.cfg.UploadMaxSizeMB)
+	}
+	if req.ProductID != nil && !isImage {
+		return nil, fmt.Errorf("%w: only images can be attached to a product", ErrInvalidInput)
+	}
+
+	partSize := int64(s.cfg.UploadPartSizeMB) << 20
+	partCount := int((req.Size + partSize - 1) / partSize)
+	if partCount > s3MaxParts {
+		return nil, fmt.Errorf("%w: file needs more than %d parts", ErrInvalidInput, s3MaxParts)
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	prefix := "uploads/"
+	if req.ProductID != nil {
+		if err := s.db.WithContext(queryCtx).Select("id").First(&models.Product{}, *req.ProductID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("%w: product with ID %d not found", ErrProductNotFound, *req.ProductID)
+			}
+			return nil, fmt.Errorf("%w: failed to find product: %v", ErrDatabaseQuery, err)
+		}
+		prefix = productImagePrefix
+	}
+
+	session := &models.UploadSession{
+		ID:          uuid.New(),
+		CreatedBy:   userID,
+		ProductID:   req.ProductID,
+		FileName:    filepath.Base(req.FileName),
+		ContentType: contentType,
+		Size:        req.Size,
+		PartSize:    partSize,
+		PartCount:   partCount,
+		Parts:       []models.UploadPart{},
+		Status:      models.UploadStatusUploading,
+		ExpiresAt:   time.Now().Add(s.cfg.UploadSessionTTL),
+	}
+	session.S3Key = fmt.Sprintf("%s%s/%s%s", prefix, time.Now().Format("2006/01/02"), session.ID, strings.ToLower(filepath.Ext(session.FileName)))
+
+	uploadID, err := s.s3Service.CreateMultipartUpload(ctx, session.S3Key, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrS3Upload, err)
+	}
+	session.S3UploadID = uploadID
+
+	if err := s.db.WithContext(queryCtx).Create(session).Error; err != nil {
+		s.abortMultipart(context.WithoutCancel(ctx), session)
+		return nil, fmt.Errorf("%w: failed to create upload: %v", ErrDatabaseQuery, err)
+	}
+	return session, nil
+}
+
+// GetUpload returns a session with the parts received so far, so a client
+// can resume by sending only the missing ones
+func (s *UploadService) GetUpload(ctx context.Context, id uuid.UUID) (*models.UploadSession, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var session models.UploadSession
+	if err := findUpload(s.db.WithContext(ctx), id, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// UploadPart stores part number (1-based) read from body. Every part but the
+// last must be exactly PartSize bytes. Sending a part again replaces it.
+func (s *UploadService) UploadPart(ctx context.Context, id uuid.UUID, number int, body io.Reader) (*models.UploadSession, error) {
+	session, err := s.GetUpload(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkUploadOpen(session); err != nil {
+		return nil, err
+	}
+	if number < 1 || number > session.PartCount {
+		return nil, fmt.Errorf("%w: part number must be between 1 and %d", ErrInvalidInput, session.PartCount)
+	}
+
+	expected := session.PartSize
+	if number == session.PartCount {
+		expected = session.Size - session.PartSize*int64(session.PartCount-1)
+	}
+	data, err := io.ReadAll(io.LimitReader(body, expected+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read part: %v", ErrInvalidInput, err)
+	}
+	if int64(len(data)) != expected {
+		return nil, fmt.Errorf("%w: part %d must be %d bytes, got %d or more", ErrInvalidInput, number, expected, len(data))
+	}
+
+	etag, err := s.s3Service.UploadPart(ctx, session.S3Key, session.S3UploadID, number, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrS3Upload, err)
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	// Parts may arrive concurrently, so merge under a row lock
+	err = s.db.WithContext(queryCtx).Transaction(func(tx *gorm.DB) error {
+		if err := findUpload(tx.Clauses(clause.Locking{Strength: "UPDATE"}), id, session); err != nil {
+			return err
+		}
+		if err := checkUploadOpen(session); err != nil {
+			return err
+		}
+
+		parts := make([]models.UploadPart, 0, len(session.Parts)+1)
+		for _, part := range session.Parts {
+			if part.Number != number {
+				parts = append(parts, part)
+			}
+		}
+		parts = append(parts, models.UploadPart{Number: number, Size: int64(len(data)), ETag: etag})
+		sort.Slice(parts, func(i, j int) bool { return parts[i].Number < parts[j].Number })
+		session.Parts = parts
+
+		if err := tx.Model(session).Update("parts", session.Parts).Error; err != nil {
+			return fmt.Errorf("%w: failed to record part: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// CompleteUpload assembles the parts into the final object once all of them
+// are in. Product image uploads become an image of the product.
+func (s *UploadService) CompleteUpload(ctx context.Context, id uuid.UUID) (*models.UploadSession, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var session models.UploadSession
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := findUpload(tx.Clauses(clause.Locking{Strength: "UPDATE"}), id, &session); err != nil {
+			return err
+		}
+		if err := checkUploadOpen(&session); err != nil {
+			return err
+		}
+		if len(session.Parts) != session.PartCount {
+			return fmt.Errorf("%w: %d of %d parts uploaded", ErrInvalidInput, len(session.Parts), session.PartCount)
+		}
+
+		etags := make([]string, 0, len(session.Parts))
+		for _, part := range session.Parts {
+			etags = append(etags, part.ETag)
+		}
+		if err := s.s3Service.CompleteMultipartUpload(ctx, session.S3Key, session.S3UploadID, etags); err != nil {
+			return fmt.Errorf("%w: %v", ErrS3Upload, err)
+		}
+
+		now := time.Now()
+		session.Status = models.UploadStatusCompleted
+		session.URL = s.s3Service.ObjectURL(session.S3Key)
+		session.CompletedAt = &now
+
+		if session.ProductID != nil {
+			// The object is not hashed, so like images uploaded before
+			// deduplication it is not shared with other images
+			image := models.Image{
+				ProductID:   *session.ProductID,
+				FileName:    session.FileName,
+				S3Key:       session.S3Key,
+				S3URL:       session.URL,
+				ContentType: session.ContentType,
+				Size:        session.Size,
+				IsActive:    true,
+			}
+			if err := tx.Create(&image).Error; err != nil {
+				return fmt.Errorf("%w: failed to create image record: %v", ErrDatabaseQuery, err)
+			}
+			session.ImageID = &image.ID
+			if err := publishProductUpdated(tx, image.ProductID); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&session).Updates(map[string]interface{}{
+			"status":       session.Status,
+			"url":          session.URL,
+			"completed_at": session.CompletedAt,
+			"image_id":     session.ImageID,
+		}).Error; err != nil {
+			return fmt.Errorf("%w: failed to complete upload: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// AbortUpload cancels an open session and discards its parts
+func (s *UploadService) AbortUpload(ctx context.Context, id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var session models.UploadSession
+		if err := findUpload(tx.Clauses(clause.Locking{Strength: "UPDATE"}), id, &session); err != nil {
+			return err
+		}
+		if err := checkUploadOpen(&session); err != nil {
+			return err
+		}
+		if err := s.s3Service.AbortMultipartUpload(ctx, session.S3Key, session.S3UploadID); err != nil {
+			return fmt.Errorf("%w: %v", ErrS3Upload, err)
+		}
+		if err := tx.Model(&session).Update("status", models.UploadStatusAborted).Error; err != nil {
+			return fmt.Errorf("%w: failed to abort upload: %v", ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+}
+
+// Run aborts uploads left open past their expiry until ctx is cancelled
+func (s *UploadService) Run(ctx context.Context) {
+	ticker := time.NewTicker(uploadExpiryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.expireUploads(ctx); err != nil {
+				logger.Error("Failed to expire uploads: ", err)
+			}
+		}
+	}
+}
+
+func (s *UploadService) expireUploads(ctx context.Context) error {
+	queryCtx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var sessions []models.UploadSession
+	if err := s.db.WithContext(queryCtx).
+		Where("status = ? AND expires_at < ?", models.UploadStatusUploading, time.Now()).
+		Limit(100).
+		Find(&sessions).Error; err != nil {
+		return fmt.Errorf("%w: failed to find expired uploads: %v", ErrDatabaseQuery, err)
+	}
+
+	for i := range sessions {
+		session := &sessions[i]
+		if err := s.s3Service.AbortMultipartUpload(ctx, session.S3Key, session.S3UploadID); err != nil {
+			// Retried on the next run
+			logger.Warn("Failed to abort expired upload ", session.ID, ": ", err)
+			continue
+		}
+		if err := s.db.WithContext(queryCtx).Model(session).
+			Where("status = ?", models.UploadStatusUploading).
+			Update("status", models.UploadStatusExpired).Error; err != nil {
+			return fmt.Errorf("%w: failed to expire upload: %v", ErrDatabaseQuery, err)
+		}
+	}
+	return nil
+}
+
+func (s *UploadService) abortMultipart(ctx context.Context, session *models.UploadSession) {
+	if err := s.s3Service.AbortMultipartUpload(ctx, session.S3Key, session.S3UploadID); err != nil {
+		logger.Warn("Failed to abort multipart upload for ", session.S3Key, ": ", err)
+	}
+}
+
+func findUpload(db *gorm.DB, id uuid.UUID, session *models.UploadSession) error {
+	if err := db.First(session, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: %s", ErrUploadNotFound, id)
+		}
+		return fmt.Errorf("%w: failed to find upload: %v", ErrDatabaseQuery, err)
+	}
+	return nil
+}
+
+func checkUploadOpen(session *models.UploadSession) error {
+	if session.Status != models.UploadStatusUploading {
+		return fmt.Errorf("%w: upload is %s", ErrUploadClosed, session.Status)
+	}
+	if time.Now().After(session.ExpiresAt) {
+		return fmt.Errorf("%w: upload expired", ErrUploadClosed)
+	}
+	return nil
+}
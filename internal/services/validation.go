@@ -5,13 +5,24 @@ import (
     "fmt"
     "io"
     "net/http"
+    "net/url"
+    "strings"
+    "sync"
     "time"
+
+    "github.com/princeprakhar/ecommerce-backend/internal/utils"
+    "github.com/princeprakhar/ecommerce-backend/pkg/logger"
 )
 
+const maxValidationCacheEntries = 10000
+
 type ValidationService struct {
-    emailAPIKey string
-    phoneAPIKey string
-    client      *http.Client
+    emailAPIKey   string
+    phoneAPIKey   string
+    client        *http.Client
+    usageService  *APIUsageService
+    cache         *validationCache
+    fallbackLocal bool
 }
 
 // Email validation response struct matching the actual API response
@@ -56,19 +67,28 @@ type PhoneCountry struct {
     Prefix string `json:"prefix"`
 }
 
-func NewValidationService(emailAPIKey, phoneAPIKey string) *ValidationService {
+// NewValidationService caches verdicts for cacheTTL (0 disables caching). With
+// fallbackLocal, API errors and timeouts degrade to local format checks
+// instead of failing the caller.
+func NewValidationService(emailAPIKey, phoneAPIKey string, cacheTTL time.Duration, fallbackLocal bool, usageService *APIUsageService) *ValidationService {
     return &ValidationService{
         emailAPIKey: emailAPIKey,
         phoneAPIKey: phoneAPIKey,
         client: &http.Client{
             Timeout: 10 * time.Second,
         },
+        usageService:  usageService,
+        cache:         newValidationCache(cacheTTL),
+        fallbackLocal: fallbackLocal,
     }
 }
 
-func (v *ValidationService) ValidateEmail(email string) (*EmailValidationResponse, error) {
+func (v *ValidationService) ValidateEmail(email string) (_ *EmailValidationResponse, err error) {
+    start := time.Now()
+    defer func() { v.usageService.Record(ExternalAPIAbstractEmail, err == nil, time.Since(start)) }()
+
     url := fmt.Sprintf("https://emailvalidation.abstractapi.com/v1/?api_key=%s&email=%s", 
-        v.emailAPIKey, email)
+        v.emailAPIKey, url.QueryEscape(email))
     
     resp, err := v.client.Get(url)
     if err != nil {
@@ -93,9 +113,12 @@ func (v *ValidationService) ValidateEmail(email string) (*EmailValidationRespons
     return &result, nil
 }
 
-func (v *ValidationService) ValidatePhone(phone string) (*PhoneValidationResponse, error) {
+func (v *ValidationService) ValidatePhone(phone string) (_ *PhoneValidationResponse, err error) {
+    start := time.Now()
+    defer func() { v.usageService.Record(ExternalAPIAbstractPhone, err == nil, time.Since(start)) }()
+
     url := fmt.Sprintf("https://phonevalidation.abstractapi.com/v1/?api_key=%s&phone=%s", 
-        v.phoneAPIKey, phone)
+        v.phoneAPIKey, url.QueryEscape(phone))
     
     resp, err := v.client.Get(url)
     if err != nil {
@@ -121,8 +144,17 @@ func (v *ValidationService) ValidatePhone(phone string) (*PhoneValidationRespons
 }
 
 func (v *ValidationService) IsEmailValid(email string) (bool, error) {
+    key := "email:" + strings.ToLower(strings.TrimSpace(email))
+    if valid, ok := v.cache.get(key); ok {
+        return valid, nil
+    }
+
     result, err := v.ValidateEmail(email)
     if err != nil {
+        if v.fallbackLocal {
+            logger.Warn("Email validation API failed, falling back to local check: ", err)
+            return utils.IsValidEmail(email), nil
+        }
         return false, err
     }
 
@@ -134,15 +166,26 @@ func (v *ValidationService) IsEmailValid(email string) (bool, error) {
                result.IsSmtpValid.Value &&        // SMTP must be valid
                result.Deliverability == "DELIVERABLE" // Must be deliverable
 
+    v.cache.set(key, isValid)
     return isValid, nil
 }
 
 func (v *ValidationService) IsPhoneValid(phone string) (bool, error) {
+    key := "phone:" + strings.TrimSpace(phone)
+    if valid, ok := v.cache.get(key); ok {
+        return valid, nil
+    }
+
     result, err := v.ValidatePhone(phone)
     if err != nil {
+        if v.fallbackLocal {
+            logger.Warn("Phone validation API failed, falling back to local check: ", err)
+            return utils.IsValidPhone(phone), nil
+        }
         return false, err
     }
 
+    v.cache.set(key, result.Valid)
     return result.Valid, nil
 }
 
@@ -153,4 +196,58 @@ func (v *ValidationService) GetEmailValidationDetails(email string) (*EmailValid
 
 func (v *ValidationService) GetPhoneValidationDetails(phone string) (*PhoneValidationResponse, error) {
     return v.ValidatePhone(phone)
+}
+
+// validationCache remembers API verdicts so repeated signups and profile
+// updates with the same address don't spend quota. Fallback results are never
+// cached.
+type validationCache struct {
+    mu      sync.Mutex
+    ttl     time.Duration
+    entries map[string]validationCacheEntry
+}
+
+type validationCacheEntry struct {
+    valid     bool
+    expiresAt time.Time
+}
+
+func newValidationCache(ttl time.Duration) *validationCache {
+    return &validationCache{ttl: ttl, entries: make(map[string]validationCacheEntry)}
+}
+
+func (c *validationCache) get(key string) (bool, bool) {
+    if c.ttl <= 0 {
+        return false, false
+    }
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    entry, ok := c.entries[key]
+    if !ok || time.Now().After(entry.expiresAt) {
+        delete(c.entries, key)
+        return false, false
+    }
+    return entry.valid, true
+}
+
+func (c *validationCache) set(key string, valid bool) {
+    if c.ttl <= 0 {
+        return
+    }
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    if len(c.entries) >= maxValidationCacheEntries {
+        now := time.Now()
+        for k, entry := range c.entries {
+            if now.After(entry.expiresAt) {
+                delete(c.entries, k)
+            }
+        }
+        if len(c.entries) >= maxValidationCacheEntries {
+            c.entries = make(map[string]validationCacheEntry)
+        }
+    }
+    c.entries[key] = validationCacheEntry{valid: valid, expiresAt: time.Now().Add(c.ttl)}
 }
\ No newline at end of file
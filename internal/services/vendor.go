@@ -0,0 +1,332 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrVendorNotFound = errors.New("vendor not found")
+	ErrVendorExists   = errors.New("vendor slug already in use")
+	// ErrVendorAccess is returned when a vendor user has no active vendor
+	ErrVendorAccess = errors.New("no active vendor for this user")
+)
+
+var slugInvalid = regexp.MustCompile(`[^a-z0-9]+`)
+
+type VendorService struct {
+	db *gorm.DB
+}
+
+func NewVendorService(db *gorm.DB) *VendorService {
+	return &VendorService{db: db}
+}
+
+// VendorStats counts one vendor's products for the admin dashboard. The
+// store's own catalogue is reported with a zero VendorID.
+type VendorStats struct {
+	VendorID           uint   `json:"vendor_id"`
+	VendorName         string `json:"vendor_name"`
+	TotalProducts      int64  `json:"total_products"` // every product that is not archived
+	ActiveProducts     int64  `json:"active_products"`
+	OutOfStockProducts int64  `json:"out_of_stock_products"`
+}
+
+func (s *VendorService) GetVendors(ctx context.Context) ([]models.Vendor, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	vendors := make([]models.Vendor, 0)
+	if err := s.db.WithContext(ctx).Order("name ASC").Find(&vendors).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch vendors: %v", ErrDatabaseQuery, err)
+	}
+	return vendors, nil
+}
+
+func (s *VendorService) GetVendor(ctx context.Context, id uint) (*models.Vendor, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var vendor models.Vendor
+	if err := s.db.WithContext(ctx).First(&vendor, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: vendor %d not found", ErrVendorNotFound, id)
+		}
+		return nil, fmt.Errorf("%w: failed to fetch vendor: %v", ErrDatabaseQuery, err)
+	}
+	return &vendor, nil
+}
+
+func (s *VendorService) CreateVendor(ctx context.Context, req *models.CreateVendorRequest) (*models.Vendor, error) {
+	vendor := models.Vendor{
+		Name:        strings.TrimSpace(req.Name),
+		Slug:        slugify(req.Slug),
+		Description: strings.TrimSpace(req.Description),
+		LogoURL:     strings.TrimSpace(req.LogoURL),
+		IsActive:    true,
+	}
+	if vendor.Slug == "" {
+		vendor.Slug = slugify(vendor.Name)
+	}
+	if req.IsActive != nil {
+		vendor.IsActive = *req.IsActive
+	}
+	if vendor.Name == "" || vendor.Slug == "" {
+		return nil, fmt.Errorf("%w: vendor name must contain letters or digits", ErrInvalidInput)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := s.checkSlugFree(ctx, vendor.Slug, 0); err != nil {
+		return nil, err
+	}
+	if err := createAllColumns(s.db.WithContext(ctx), &vendor); err != nil {
+		return nil, fmt.Errorf("%w: failed to create vendor: %v", ErrDatabaseQuery, err)
+	}
+	return &vendor, nil
+}
+
+func (s *VendorService) UpdateVendor(ctx context.Context, id uint, req *models.UpdateVendorRequest) (*models.Vendor, error) {
+	vendor, err := s.GetVendor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		vendor.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Slug != nil {
+		vendor.Slug = slugify(*req.Slug)
+	}
+	if req.Description != nil {
+		vendor.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.LogoURL != nil {
+		vendor.LogoURL = strings.TrimSpace(*req.LogoURL)
+	}
+	if req.IsActive != nil {
+		vendor.IsActive = *req.IsActive
+	}
+	if vendor.Name == "" || vendor.Slug == "" {
+		return nil, fmt.Errorf("%w: vendor name and slug cannot be empty", ErrInvalidInput)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := s.checkSlugFree(ctx, vendor.Slug, vendor.ID); err != nil {
+		return nil, err
+	}
+	err = s.db.WithContext(ctx).Model(vendor).
+		Select("name", "slug", "description", "logo_url", "is_active").
+		Updates(vendor).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to update vendor: %v", ErrDatabaseQuery, err)
+	}
+	return vendor, nil
+}
+
+// DeleteVendor removes a vendor. Its products move back to the store's own
+// catalogue and its users become customers again.
+func (s *VendorService) DeleteVendor(ctx context.Context, id uint) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.User{}).Where("vendor_id = ?", id).
+			Updates(map[string]interface{}{"vendor_id": nil, "role": "customer"}).Error
+		if err != nil {
+			return fmt.Errorf("%w: failed to detach vendor users: %v", ErrDatabaseQuery, err)
+		}
+		if err := tx.Model(&models.Product{}).Where("vendor_id = ?", id).Update("vendor_id", nil).Error; err != nil {
+			return fmt.Errorf("%w: failed to detach vendor products: %v", ErrDatabaseQuery, err)
+		}
+
+		result := tx.Delete(&models.Vendor{}, id)
+		if result.Error != nil {
+			return fmt.Errorf("%w: failed to delete vendor: %v", ErrDatabaseQuery, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: vendor %d not found", ErrVendorNotFound, id)
+		}
+		return nil
+	})
+}
+
+// GetVendorUsers lists the users who manage a vendor's products
+func (s *VendorService) GetVendorUsers(ctx context.Context, vendorID uint) ([]models.User, error) {
+	if _, err := s.GetVendor(ctx, vendorID); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	users := make([]models.User, 0)
+	if err := s.db.WithContext(ctx).Where("vendor_id = ?", vendorID).Order("email ASC").Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch vendor users: %v", ErrDatabaseQuery, err)
+	}
+	return users, nil
+}
+
+// AssignUser makes a customer a vendor user. Admins keep their role so they
+// cannot lock themselves out of the rest of the admin API.
+func (s *VendorService) AssignUser(ctx context.Context, vendorID, userID uint) (*models.User, error) {
+	if _, err := s.GetVendor(ctx, vendorID); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var user models.User
+	if err := s.db.WithContext(ctx).First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: user %d not found", ErrInvalidInput, userID)
+		}
+		return nil, fmt.Errorf("%w: failed to fetch user: %v", ErrDatabaseQuery, err)
+	}
+	if user.Role == "admin" {
+		return nil, fmt.Errorf("%w: admins already manage every vendor", ErrInvalidInput)
+	}
+
+	user.Role, user.VendorID = models.RoleVendor, &vendorID
+	if err := s.db.WithContext(ctx).Model(&user).Select("role", "vendor_id").Updates(&user).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to assign vendor user: %v", ErrDatabaseQuery, err)
+	}
+	return &user, nil
+}
+
+// RemoveUser turns a vendor user back into a customer
+func (s *VendorService) RemoveUser(ctx context.Context, vendorID, userID uint) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND vendor_id = ?", userID, vendorID).
+		Updates(map[string]interface{}{"vendor_id": nil, "role": "customer"})
+	if result.Error != nil {
+		return fmt.Errorf("%w: failed to remove vendor user: %v", ErrDatabaseQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: user %d does not belong to vendor %d", ErrVendorNotFound, userID, vendorID)
+	}
+	return nil
+}
+
+// VendorIDForUser returns the active vendor a vendor user works for
+func (s *VendorService) VendorIDForUser(ctx context.Context, userID uint) (uint, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var vendor models.Vendor
+	err := s.db.WithContext(ctx).
+		Joins("JOIN users ON users.vendor_id = vendors.id").
+		Where("users.id = ? AND users.role = ? AND users.is_active = ?", userID, models.RoleVendor, true).
+		Where("vendors.is_active = ?", true).
+		First(&vendor).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrVendorAccess
+		}
+		return 0, fmt.Errorf("%w: failed to fetch vendor: %v", ErrDatabaseQuery, err)
+	}
+	return vendor.ID, nil
+}
+
+// OwnsProduct reports whether a product belongs to a vendor
+func (s *VendorService) OwnsProduct(ctx context.Context, vendorID, productID uint) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var count int64
+	err := s.db.WithContext(ctx).Model(&models.Product{}).
+		Where("id = ? AND vendor_id = ?", productID, vendorID).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("%w: failed to check product vendor: %v", ErrDatabaseQuery, err)
+	}
+	return count > 0, nil
+}
+
+// vendorProductStats counts products per vendor, including the store's own
+// products under vendor 0
+func vendorProductStats(db *gorm.DB) ([]VendorStats, error) {
+	var rows []struct {
+		VendorID   *uint
+		VendorName *string
+		Total      int64
+		Active     int64
+		OutOfStock int64
+	}
+	err := db.Model(&models.Product{}).
+		Select(`products.vendor_id, vendors.name AS vendor_name,
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE products.status = ?) AS active,
+			COUNT(*) FILTER (WHERE products.status = ? AND products.stock <= 0) AS out_of_stock`,
+			models.ProductStatusActive, models.ProductStatusActive).
+		Joins("LEFT JOIN vendors ON vendors.id = products.vendor_id").
+		Where("products.status <> ?", models.ProductStatusArchived).
+		Group("products.vendor_id, vendors.name").
+		Order("vendors.name ASC NULLS FIRST").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to count vendor products: %v", ErrDatabaseQuery, err)
+	}
+
+	stats := make([]VendorStats, 0, len(rows))
+	for _, row := range rows {
+		stat := VendorStats{TotalProducts: row.Total, ActiveProducts: row.Active, OutOfStockProducts: row.OutOfStock}
+		if row.VendorID != nil {
+			stat.VendorID = *row.VendorID
+		}
+		if row.VendorName != nil {
+			stat.VendorName = *row.VendorName
+		}
+		stats = append(stats, stat)
+	}
+	return stats, nil
+}
+
+// vendorScope limits a product query to one vendor; zero leaves it unscoped
+func vendorScope(vendorID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if vendorID == 0 {
+			return db
+		}
+		return db.Where("vendor_id = ?", vendorID)
+	}
+}
+
+func (s *VendorService) checkSlugFree(ctx context.Context, slug string, exceptID uint) error {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&models.Vendor{}).
+		Where("slug = ? AND id <> ?", slug, exceptID).
+		Count(&count).Error
+	if err != nil {
+		return fmt.Errorf("%w: failed to check vendor slug: %v", ErrDatabaseQuery, err)
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: %q", ErrVendorExists, slug)
+	}
+	return nil
+}
+
+func slugify(value string) string {
+	return strings.Trim(slugInvalid.ReplaceAllString(strings.ToLower(strings.TrimSpace(value)), "-"), "-")
+}
+
+// vendorIDOrNil maps the request convention of 0 meaning "no vendor" to NULL
+func vendorIDOrNil(vendorID *uint) *uint {
+	if vendorID == nil || *vendorID == 0 {
+		return nil
+	}
+	return vendorID
+}
@@ -0,0 +1,432 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/utils"
+	"github.com/princeprakhar/ecommerce-backend/pkg/logger"
+	"gorm.io/gorm"
+)
+
+const (
+	WebhookMaxAttempts      = 6
+	webhookTimeout          = 10 * time.Second
+	webhookRetryPoll        = 15 * time.Second
+	webhookBaseBackoff      = 30 * time.Second
+	webhookResponseBodySize = 2048
+)
+
+// webhookClaimLease is how far next_attempt_at moves while an attempt is in
+// flight, so the poller does not send the same delivery again
+const webhookClaimLease = 2 * time.Minute
+
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+type WebhookService struct {
+	db     *gorm.DB
+	client *http.Client
+}
+
+func NewWebhookService(db *gorm.DB, eventBus *EventBus) *WebhookService {
+	s := &WebhookService{
+		db:     db,
+		client: newPublicHTTPClient(webhookTimeout),
+	}
+	if eventBus != nil {
+		eventBus.Subscribe(s.HandleEvent)
+	}
+	return s
+}
+
+// CreateWebhookResponse exposes the signing secret once, at creation time.
+type CreateWebhookResponse struct {
+	Webhook *models.Webhook `json:"webhook"`
+	Secret  string          `json:"secret"`
+}
+
+func validateWebhookURL(rawURL string) error {
+	parsed, err := url.ParseRequestURI(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: webhook URL must be an absolute http(s) URL", ErrInvalidInput)
+	}
+	// Hostnames are checked again when connecting, since they may resolve to
+	// a private address
+	host := strings.ToLower(parsed.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: webhook URL must point to a public host", ErrInvalidInput)
+	}
+	if ip := net.ParseIP(host); ip != nil && (ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsUnspecified()) {
+		return fmt.Errorf("%w: webhook URL must point to a public host", ErrInvalidInput)
+	}
+	return nil
+}
+
+func validateWebhookEvents(events []string) error {
+	if len(events) == 0 {
+		return fmt.Errorf("%w: at least one event is required", ErrInvalidInput)
+	}
+	for _, event := range events {
+		if event != "*" && !isKnownEventType(event) {
+			return fmt.Errorf("%w: unknown event type %q", ErrInvalidInput, event)
+		}
+	}
+	return nil
+}
+
+func (s *WebhookService) CreateWebhook(ctx context.Context, req *models.CreateWebhookRequest) (*CreateWebhookResponse, error) {
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookEvents(req.Events); err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		generated, err := utils.GenerateRandomString(32)
+		if err != nil {
+			return nil, errors.New("failed to generate webhook secret")
+		}
+		secret = generated
+	}
+
+	webhook := &models.Webhook{
+		URL:         req.URL,
+		Secret:      secret,
+		Events:      req.Events,
+		Description: strings.TrimSpace(req.Description),
+		IsActive:    true,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Create(webhook).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to create webhook: %v", ErrDatabaseQuery, err)
+	}
+
+	return &CreateWebhookResponse{Webhook: webhook, Secret: secret}, nil
+}
+
+func (s *WebhookService) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	webhooks := make([]models.Webhook, 0)
+	if err := s.db.WithContext(ctx).Order("created_at DESC").Find(&webhooks).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch webhooks: %v", ErrDatabaseQuery, err)
+	}
+	return webhooks, nil
+}
+
+func (s *WebhookService) GetWebhookByID(ctx context.Context, id uint) (*models.Webhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var webhook models.Webhook
+	if err := s.db.WithContext(ctx).First(&webhook, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to fetch webhook: %v", ErrDatabaseQuery, err)
+	}
+	return &webhook, nil
+}
+
+func (s *WebhookService) UpdateWebhook(ctx context.Context, id uint, req *models.UpdateWebhookRequest) (*models.Webhook, error) {
+	webhook, err := s.GetWebhookByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+		webhook.URL = *req.URL
+	}
+	if req.Events != nil {
+		if err := validateWebhookEvents(req.Events); err != nil {
+			return nil, err
+		}
+		webhook.Events = req.Events
+	}
+	if req.Secret != nil && *req.Secret != "" {
+		webhook.Secret = *req.Secret
+	}
+	if req.Description != nil {
+		webhook.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.IsActive != nil {
+		webhook.IsActive = *req.IsActive
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Save(webhook).Error; err != nil {
+		return nil, fmt.Errorf("%w: failed to update webhook: %v", ErrDatabaseQuery, err)
+	}
+	return webhook, nil
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id uint) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", id).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return fmt.Errorf("%w: failed to delete webhook deliveries: %v", ErrDatabaseQuery, err)
+		}
+		result := tx.Delete(&models.Webhook{}, id)
+		if result.Error != nil {
+			return fmt.Errorf("%w: failed to delete webhook: %v", ErrDatabaseQuery, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrWebhookNotFound
+		}
+		return nil
+	})
+}
+
+func (s *WebhookService) GetDeliveries(ctx context.Context, webhookID uint, page, limit int) ([]models.WebhookDelivery, int64, error) {
+	if _, err := s.GetWebhookByID(ctx, webhookID); err != nil {
+		return nil, 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var total int64
+	query := s.db.WithContext(ctx).Model(&models.WebhookDelivery{}).Where("webhook_id = ?", webhookID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to count deliveries: %v", ErrDatabaseQuery, err)
+	}
+
+	deliveries := make([]models.WebhookDelivery, 0)
+	if err := query.Order("created_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&deliveries).Error; err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to fetch deliveries: %v", ErrDatabaseQuery, err)
+	}
+	return deliveries, total, nil
+}
+
+// HandleEvent queues a delivery for every active webhook subscribed to the
+// event and makes the first attempt right away. The delivery is created
+// already claimed, so the poller leaves it alone unless the attempt is lost.
+func (s *WebhookService) HandleEvent(event Event) {
+	ctx, cancel := context.WithTimeout(context.Background(), QueryTimeout)
+	var webhooks []models.Webhook
+	err := s.db.WithContext(ctx).Where("is_active = ?", true).Find(&webhooks).Error
+	cancel()
+	if err != nil {
+		logger.Error("Failed to load webhooks for event ", event.Type, ": ", err)
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		logger.Error("Failed to encode webhook payload for event ", event.Type, ": ", err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		if !webhookSubscribed(webhook, event.Type) {
+			continue
+		}
+
+		leaseUntil := time.Now().Add(webhookClaimLease)
+		delivery := models.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       string(payload),
+			Status:        models.WebhookDeliveryPending,
+			NextAttemptAt: &leaseUntil,
+		}
+		if err := s.createDelivery(&delivery); err != nil {
+			logger.Error("Failed to queue webhook delivery: ", err)
+			continue
+		}
+
+		s.attemptDelivery(context.Background(), webhook, &delivery)
+	}
+}
+
+func (s *WebhookService) createDelivery(delivery *models.WebhookDelivery) error {
+	ctx, cancel := context.WithTimeout(context.Background(), QueryTimeout)
+	defer cancel()
+	return s.db.WithContext(ctx).Create(delivery).Error
+}
+
+func webhookSubscribed(webhook models.Webhook, eventType string) bool {
+	for _, subscribed := range webhook.Events {
+		if subscribed == "*" || subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// SignPayload returns the hex HMAC-SHA256 of "<timestamp>.<body>", which
+// receivers recompute to verify the X-Webhook-Signature header.
+func SignPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// attemptDelivery sends a claimed delivery once and records the outcome.
+// Each attempt gets its own timeout, so one slow endpoint does not eat the
+// time of the deliveries after it.
+func (s *WebhookService) attemptDelivery(ctx context.Context, webhook models.Webhook, delivery *models.WebhookDelivery) {
+	delivery.Attempts++
+	timestamp := time.Now().Unix()
+	body := []byte(delivery.Payload)
+
+	sendCtx, cancelSend := context.WithTimeout(ctx, webhookTimeout)
+	statusCode, responseBody, err := s.send(sendCtx, webhook, delivery, timestamp, body)
+	cancelSend()
+	delivery.ResponseStatus = statusCode
+	delivery.ResponseBody = responseBody
+
+	now := time.Now()
+	switch {
+	case err == nil && statusCode >= 200 && statusCode < 300:
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.Error = ""
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+	default:
+		if err != nil {
+			delivery.Error = err.Error()
+		} else {
+			delivery.Error = fmt.Sprintf("endpoint responded with status %d", statusCode)
+		}
+		if delivery.Attempts >= WebhookMaxAttempts {
+			delivery.Status = models.WebhookDeliveryFailed
+			delivery.NextAttemptAt = nil
+		} else {
+			// Exponential backoff: 30s, 1m, 2m, 4m, ...
+			next := now.Add(webhookBaseBackoff * time.Duration(1<<(delivery.Attempts-1)))
+			delivery.NextAttemptAt = &next
+		}
+	}
+
+	saveCtx, cancelSave := context.WithTimeout(ctx, QueryTimeout)
+	defer cancelSave()
+	if err := s.db.WithContext(saveCtx).Save(delivery).Error; err != nil {
+		logger.Error("Failed to update webhook delivery ", delivery.ID, ": ", err)
+	}
+}
+
+func (s *WebhookService) send(ctx context.Context, webhook models.Webhook, delivery *models.WebhookDelivery, timestamp int64, body []byte) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Sipfinity-Webhooks/1.0")
+	req.Header.Set("X-Webhook-ID", strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", "sha256="+SignPayload(webhook.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseBodySize))
+	return resp.StatusCode, string(responseBody), nil
+}
+
+// Run retries pending deliveries whose backoff has elapsed until the context
+// is cancelled. Pending rows survive restarts, so nothing is lost on deploy.
+func (s *WebhookService) Run(ctx context.Context) {
+	ticker := time.NewTicker(webhookRetryPoll)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.retryDueDeliveries(ctx)
+		}
+	}
+}
+
+func (s *WebhookService) retryDueDeliveries(ctx context.Context) {
+	now := time.Now()
+	var deliveries []models.WebhookDelivery
+	if err := s.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, now).
+		Order("next_attempt_at ASC").
+		Limit(100).
+		Find(&deliveries).Error; err != nil {
+		logger.Error("Failed to load pending webhook deliveries: ", err)
+		return
+	}
+
+	for i := range deliveries {
+		webhook, err := s.GetWebhookByID(ctx, deliveries[i].WebhookID)
+		if err != nil {
+			continue
+		}
+		if !webhook.IsActive {
+			continue
+		}
+		claimed, err := s.claimDelivery(ctx, &deliveries[i], now)
+		if err != nil {
+			logger.Error("Failed to claim webhook delivery ", deliveries[i].ID, ": ", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		s.attemptDelivery(ctx, *webhook, &deliveries[i])
+	}
+}
+
+// claimDelivery moves next_attempt_at past the attempt about to be made.
+// Only one caller wins the update, so another poller or instance that loaded
+// the same row skips it.
+func (s *WebhookService) claimDelivery(ctx context.Context, delivery *models.WebhookDelivery, due time.Time) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	leaseUntil := time.Now().Add(webhookClaimLease)
+	result := s.db.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at <= ?", delivery.ID, models.WebhookDeliveryPending, due).
+		Update("next_attempt_at", leaseUntil)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	delivery.NextAttemptAt = &leaseUntil
+	return true, nil
+}
@@ -0,0 +1,43 @@
+package services_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+)
+
+func TestSignPayload(t *testing.T) {
+	body := []byte(`{"type":"product.created","data":{"id":7}}`)
+	sign := func(secret, message string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(message))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+	signature := services.SignPayload("whsec_test", 1700000000, body)
+
+	tests := []struct {
+		name  string
+		got   string
+		want  string
+		equal bool
+	}{
+		{"HMAC-SHA256 of timestamp.body", signature, sign("whsec_test", `1700000000.{"type":"product.created","data":{"id":7}}`), true},
+		{"stable for the same input", signature, services.SignPayload("whsec_test", 1700000000, body), true},
+		{"other timestamp", signature, services.SignPayload("whsec_test", 1700000001, body), false},
+		{"other body", signature, services.SignPayload("whsec_test", 1700000000, []byte(`{}`)), false},
+		{"other secret", signature, services.SignPayload("whsec_other", 1700000000, body), false},
+		{"timestamp not separated from the body", signature, sign("whsec_test", `1700000000{"type":"product.created","data":{"id":7}}`), false},
+		{"empty body", services.SignPayload("whsec_test", 1700000000, nil), sign("whsec_test", "1700000000."), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if (tt.got == tt.want) != tt.equal {
+				t.Fatalf("expected equal=%v, got %s and %s", tt.equal, tt.got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package testutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/localstack"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+// Container images, pinned so test runs are reproducible
+const (
+	PostgresImage   = "postgres:16-alpine"
+	LocalstackImage = "localstack/localstack:3.8"
+)
+
+// Credentials localstack accepts; they never reach AWS
+const (
+	S3Region    = "us-east-1"
+	S3AccessKey = "test"
+	S3SecretKey = "test"
+)
+
+// StartPostgres runs a throwaway Postgres container for the test and returns
+// its connection URL. The test is skipped when Docker is not available.
+func StartPostgres(t *testing.T) string {
+	t.Helper()
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	ctx := context.Background()
+	container, err := postgres.Run(ctx, PostgresImage,
+		postgres.WithDatabase("sipfinity_test"),
+		postgres.WithUsername("sipfinity"),
+		postgres.WithPassword("sipfinity"),
+		postgres.BasicWaitStrategies(),
+	)
+	testcontainers.CleanupContainer(t, container)
+	if err != nil {
+		t.Fatalf("failed to start postgres: %v", err)
+	}
+
+	url, err := container.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		t.Fatalf("failed to get postgres connection string: %v", err)
+	}
+	return url
+}
+
+// StartS3 runs localstack with S3 only, creates bucket and returns the
+// endpoint to set as S3_ENDPOINT. The test is skipped when Docker is not
+// available.
+func StartS3(t *testing.T, bucket string) string {
+	t.Helper()
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	ctx := context.Background()
+	container, err := localstack.Run(ctx, LocalstackImage, testcontainers.WithEnv(map[string]string{"SERVICES": "s3"}))
+	testcontainers.CleanupContainer(t, container)
+	if err != nil {
+		t.Fatalf("failed to start localstack: %v", err)
+	}
+
+	endpoint, err := container.PortEndpoint(ctx, "4566/tcp", "http")
+	if err != nil {
+		t.Fatalf("failed to get localstack endpoint: %v", err)
+	}
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:           aws.String(S3Region),
+		Endpoint:         aws.String(endpoint),
+		S3ForcePathStyle: aws.Bool(true),
+		Credentials:      credentials.NewStaticCredentials(S3AccessKey, S3SecretKey, ""),
+	})
+	if err != nil {
+		t.Fatalf("failed to create AWS session: %v", err)
+	}
+	if _, err := s3.New(sess).CreateBucket(&s3.CreateBucketInput{Bucket: aws.String(bucket)}); err != nil {
+		t.Fatalf("failed to create bucket %s: %v", bucket, err)
+	}
+	return endpoint
+}
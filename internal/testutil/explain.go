@@ -0,0 +1,83 @@
+package testutil
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// ExpectIndex fails the test unless the plan of query uses index. Sequential
+// scans are disabled while planning, since a test database is small enough
+// that Postgres would otherwise scan every table; the check is whether the
+// index can serve the query shape at all. Build query with DB.ToSQL to check
+// the statement a service actually sends.
+func (e *Env) ExpectIndex(t *testing.T, index, query string, args ...interface{}) {
+	t.Helper()
+
+	used, err := plannedIndexes(e.DB, query, args...)
+	if err != nil {
+		t.Fatalf("failed to explain %q: %v", query, err)
+	}
+	for _, name := range used {
+		if name == index {
+			return
+		}
+	}
+	t.Fatalf("expected %s in the plan of %q, got [%s]", index, query, strings.Join(used, ", "))
+}
+
+// plannedIndexes returns the indexes in the plan of query
+func plannedIndexes(db *gorm.DB, query string, args ...interface{}) ([]string, error) {
+	var raw string
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET LOCAL enable_seqscan = off").Error; err != nil {
+			return err
+		}
+		return tx.Raw("EXPLAIN (FORMAT JSON) "+query, args...).Row().Scan(&raw)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var plans []struct {
+		Plan json.RawMessage `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(raw), &plans); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, p := range plans {
+		var node interface{}
+		if err := json.Unmarshal(p.Plan, &node); err != nil {
+			return nil, err
+		}
+		collectIndexNames(node, seen)
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func collectIndexNames(node interface{}, seen map[string]bool) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if name, ok := n["Index Name"].(string); ok {
+			seen[name] = true
+		}
+		for _, child := range n {
+			collectIndexNames(child, seen)
+		}
+	case []interface{}:
+		for _, child := range n {
+			collectIndexNames(child, seen)
+		}
+	}
+}
@@ -0,0 +1,328 @@
+package testutil
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+)
+
+// The recorded contracts of the FastAPI extraction service. Each file is one
+// exchange: the request FastAPIService sends and the response the Python
+// service returned. Re-record them when the service's API changes.
+//
+//go:embed testdata/fastapi/*.json
+var fastAPIContractFiles embed.FS
+
+// FastAPIContract is one recorded request/response exchange
+type FastAPIContract struct {
+	Name    string `json:"-"` // file name without .json
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Request struct {
+		ContentType string          `json:"content_type"`
+		Fields      []string        `json:"fields,omitempty"` // multipart form fields
+		Body        json.RawMessage `json:"body,omitempty"`   // JSON requests
+	} `json:"request"`
+	Status   int             `json:"status"`
+	Response json.RawMessage `json:"response"`
+}
+
+// fastAPIResponseTypes are the types FastAPIService decodes successful
+// responses into, by path
+var fastAPIResponseTypes = map[string]func() interface{}{
+	"/upload/images": func() interface{} { return &services.FastAPIResponse{} },
+	"/describe/images": func() interface{} {
+		return &struct {
+			Descriptions []services.ImageDescription `json:"descriptions"`
+		}{}
+	},
+}
+
+// FastAPIContracts returns the recorded contracts sorted by name
+func FastAPIContracts() ([]*FastAPIContract, error) {
+	files, err := fastAPIContractFiles.ReadDir("testdata/fastapi")
+	if err != nil {
+		return nil, err
+	}
+
+	contracts := make([]*FastAPIContract, 0, len(files))
+	for _, file := range files {
+		data, err := fastAPIContractFiles.ReadFile("testdata/fastapi/" + file.Name())
+		if err != nil {
+			return nil, err
+		}
+		contract := &FastAPIContract{Name: strings.TrimSuffix(file.Name(), path.Ext(file.Name()))}
+		if err := json.Unmarshal(data, contract); err != nil {
+			return nil, fmt.Errorf("contract %s: %v", contract.Name, err)
+		}
+		contracts = append(contracts, contract)
+	}
+	sort.Slice(contracts, func(i, j int) bool { return contracts[i].Name < contracts[j].Name })
+	return contracts, nil
+}
+
+// CheckFastAPIContracts verifies that every recorded response still matches
+// the Go types FastAPIService decodes it into: a field the service sends that
+// the Go type lacks, or a Go field the service no longer sends, is reported
+// as drift. Error responses must carry a message or detail.
+func CheckFastAPIContracts() error {
+	contracts, err := FastAPIContracts()
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	for _, contract := range contracts {
+		for _, problem := range checkFastAPIContract(contract) {
+			problems = append(problems, contract.Name+": "+problem)
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("FastAPI contract drift:\n%s", strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+func checkFastAPIContract(contract *FastAPIContract) []string {
+	if contract.Status != http.StatusOK {
+		var errResp struct {
+			Message string      `json:"message"`
+			Detail  interface{} `json:"detail"`
+		}
+		if err := json.Unmarshal(contract.Response, &errResp); err != nil {
+			return []string{"error response is not a JSON object: " + err.Error()}
+		}
+		if errResp.Message == "" && errResp.Detail == nil {
+			return []string{"error response has neither message nor detail"}
+		}
+		return nil
+	}
+
+	newType, ok := fastAPIResponseTypes[contract.Path]
+	if !ok {
+		return []string{"no response type registered for " + contract.Path}
+	}
+
+	// Fields the service sends that the Go type would silently drop
+	decoded := newType()
+	decoder := json.NewDecoder(bytes.NewReader(contract.Response))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(decoded); err != nil {
+		return []string{"response does not decode: " + err.Error()}
+	}
+
+	// Fields the Go type expects that the service no longer sends
+	encoded, err := json.Marshal(decoded)
+	if err != nil {
+		return []string{"response does not encode: " + err.Error()}
+	}
+	var want, got interface{}
+	json.Unmarshal(encoded, &want)
+	json.Unmarshal(contract.Response, &got)
+	return jsonShapeDiff("response", want, got)
+}
+
+// jsonShapeDiff lists the object keys present in one value but not the other.
+// Arrays are compared by their first elements.
+func jsonShapeDiff(at string, want, got interface{}) []string {
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok {
+			return []string{at + ": expected an object"}
+		}
+		var diff []string
+		for key, value := range w {
+			if _, ok := g[key]; !ok {
+				diff = append(diff, fmt.Sprintf("%s.%s: missing", at, key))
+				continue
+			}
+			diff = append(diff, jsonShapeDiff(at+"."+key, value, g[key])...)
+		}
+		for key := range g {
+			if _, ok := w[key]; !ok {
+				diff = append(diff, fmt.Sprintf("%s.%s: unexpected", at, key))
+			}
+		}
+		sort.Strings(diff)
+		return diff
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok {
+			return []string{at + ": expected an array"}
+		}
+		if len(w) == 0 || len(g) == 0 {
+			return nil
+		}
+		return jsonShapeDiff(at+"[0]", w[0], g[0])
+	}
+	return nil
+}
+
+// FastAPIRequest is a request received by the mock
+type FastAPIRequest struct {
+	Method      string
+	Path        string
+	ContentType string
+	Body        []byte
+}
+
+// FastAPIMock serves the recorded contracts in place of the Python service.
+// Each path answers with its successful contract until Use selects another
+// one. Requests that do not match the recorded request (wrong method,
+// content type, API key, missing form fields or differently shaped JSON) are
+// rejected with a 400 and fail the test when it ends.
+type FastAPIMock struct {
+	Server *httptest.Server
+	key    string
+
+	mu         sync.Mutex
+	contracts  map[string]*FastAPIContract // by name
+	active     map[string]*FastAPIContract // by path
+	requests   []FastAPIRequest
+	violations []string
+}
+
+// StartFastAPIMock serves the contracts and expects key as the internal API key
+func StartFastAPIMock(t *testing.T, key string) *FastAPIMock {
+	t.Helper()
+
+	contracts, err := FastAPIContracts()
+	if err != nil {
+		t.Fatalf("failed to load FastAPI contracts: %v", err)
+	}
+
+	mock := &FastAPIMock{
+		key:       key,
+		contracts: make(map[string]*FastAPIContract),
+		active:    make(map[string]*FastAPIContract),
+	}
+	for _, contract := range contracts {
+		mock.contracts[contract.Name] = contract
+		if contract.Status == http.StatusOK {
+			mock.active[contract.Path] = contract
+		}
+	}
+
+	mock.Server = httptest.NewServer(http.HandlerFunc(mock.serve))
+	t.Cleanup(func() {
+		mock.Server.Close()
+		for _, violation := range mock.Violations() {
+			t.Errorf("FastAPI contract violation: %s", violation)
+		}
+	})
+	return mock
+}
+
+// Use makes the named contract the answer for its path, e.g.
+// Use("upload_images_unavailable") to exercise the retry and breaker paths
+func (m *FastAPIMock) Use(t *testing.T, name string) {
+	t.Helper()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	contract, ok := m.contracts[name]
+	if !ok {
+		t.Fatalf("unknown FastAPI contract %q", name)
+	}
+	m.active[contract.Path] = contract
+}
+
+// Requests returns the requests received so far
+func (m *FastAPIMock) Requests() []FastAPIRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]FastAPIRequest(nil), m.requests...)
+}
+
+// Violations returns the contract violations seen so far
+func (m *FastAPIMock) Violations() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.violations...)
+}
+
+func (m *FastAPIMock) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	m.mu.Lock()
+	m.requests = append(m.requests, FastAPIRequest{
+		Method:      r.Method,
+		Path:        r.URL.Path,
+		ContentType: r.Header.Get("Content-Type"),
+		Body:        body,
+	})
+	contract := m.active[r.URL.Path]
+	m.mu.Unlock()
+
+	if contract == nil {
+		m.reject(w, fmt.Sprintf("%s %s: no recorded contract", r.Method, r.URL.Path))
+		return
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if problems := m.checkRequest(contract, r, body); len(problems) > 0 {
+		m.reject(w, fmt.Sprintf("%s %s: %s", r.Method, r.URL.Path, strings.Join(problems, "; ")))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(contract.Status)
+	w.Write(contract.Response)
+}
+
+func (m *FastAPIMock) checkRequest(contract *FastAPIContract, r *http.Request, body []byte) []string {
+	var problems []string
+	if r.Method != contract.Method {
+		problems = append(problems, "expected method "+contract.Method)
+	}
+	if r.Header.Get("X-Internal-API-Key") != m.key {
+		problems = append(problems, "wrong X-Internal-API-Key")
+	}
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), contract.Request.ContentType) {
+		problems = append(problems, "expected content type "+contract.Request.ContentType)
+		return problems
+	}
+
+	switch contract.Request.ContentType {
+	case "multipart/form-data":
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			return append(problems, "invalid multipart form: "+err.Error())
+		}
+		for _, field := range contract.Request.Fields {
+			if len(r.MultipartForm.File[field]) == 0 && len(r.MultipartForm.Value[field]) == 0 {
+				problems = append(problems, "missing form field "+field)
+			}
+		}
+	case "application/json":
+		var want, got interface{}
+		json.Unmarshal(contract.Request.Body, &want)
+		if err := json.Unmarshal(body, &got); err != nil {
+			return append(problems, "invalid JSON body: "+err.Error())
+		}
+		problems = append(problems, jsonShapeDiff("request", want, got)...)
+	}
+	return problems
+}
+
+func (m *FastAPIMock) reject(w http.ResponseWriter, violation string) {
+	m.mu.Lock()
+	m.violations = append(m.violations, violation)
+	m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{"detail": "contract violation: " + violation})
+}
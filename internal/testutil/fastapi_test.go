@@ -0,0 +1,84 @@
+package testutil_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/config"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+	"github.com/princeprakhar/ecommerce-backend/internal/testutil"
+)
+
+// TestFastAPIContracts fails when a recorded response no longer matches the
+// Go types it is decoded into. Re-record the contracts or update the types.
+func TestFastAPIContracts(t *testing.T) {
+	if err := testutil.CheckFastAPIContracts(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestFastAPIServiceAgainstMock checks that the requests FastAPIService sends
+// still match the recorded ones; the mock fails the test on any mismatch
+func TestFastAPIServiceAgainstMock(t *testing.T) {
+	const key = "contract-test-key"
+	mock := testutil.StartFastAPIMock(t, key)
+	service := services.NewFastAPIService(&config.Config{
+		FastAPIURL:              mock.Server.URL,
+		FastAPIKey:              key,
+		FastAPITimeout:          5 * time.Second,
+		FastAPIBreakerThreshold: 100,
+		FastAPIBreakerCooldown:  time.Second,
+	})
+	ctx := context.Background()
+
+	image := filepath.Join(t.TempDir(), "front.png")
+	if err := os.WriteFile(image, testutil.PNG(t, 8, 8, 10), 0o600); err != nil {
+		t.Fatalf("failed to write image: %v", err)
+	}
+
+	t.Run("upload images", func(t *testing.T) {
+		resp, err := service.ProcessImages(ctx, []string{image})
+		if err != nil {
+			t.Fatalf("ProcessImages failed: %v", err)
+		}
+		if !resp.Success || len(resp.ProductData) != 1 || resp.ProductData[0].SKU == "" {
+			t.Fatalf("unexpected response %+v", resp)
+		}
+	})
+
+	t.Run("describe images", func(t *testing.T) {
+		descriptions, err := service.DescribeImages(ctx, []services.ImageDescriptionInput{
+			{ID: "42", URL: "https://example.com/front.jpg"},
+		})
+		if err != nil {
+			t.Fatalf("DescribeImages failed: %v", err)
+		}
+		if len(descriptions) != 1 || descriptions[0].ID != "42" || descriptions[0].AltText == "" {
+			t.Fatalf("unexpected descriptions %+v", descriptions)
+		}
+	})
+
+	t.Run("rejected input", func(t *testing.T) {
+		mock.Use(t, "upload_images_rejected")
+		if _, err := service.ProcessImages(ctx, []string{image}); !errors.Is(err, services.ErrFastAPIBadInput) {
+			t.Fatalf("expected ErrFastAPIBadInput, got %v", err)
+		}
+
+		mock.Use(t, "describe_images_rejected")
+		_, err := service.DescribeImages(ctx, []services.ImageDescriptionInput{{ID: "42", URL: "https://example.com/front.jpg"}})
+		if !errors.Is(err, services.ErrFastAPIBadInput) {
+			t.Fatalf("expected ErrFastAPIBadInput, got %v", err)
+		}
+	})
+
+	t.Run("unavailable", func(t *testing.T) {
+		mock.Use(t, "upload_images_unavailable")
+		if _, err := service.ProcessImages(ctx, []string{image}); !errors.Is(err, services.ErrFastAPIUnavailable) {
+			t.Fatalf("expected ErrFastAPIUnavailable, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package testutil
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/princeprakhar/ecommerce-backend/internal/models"
+	"github.com/princeprakhar/ecommerce-backend/internal/services"
+)
+
+// FixturePassword is the password of every user created by the fixtures
+const FixturePassword = "Fixture-Passw0rd!"
+
+var fixtureSeq atomic.Int64
+
+// nextSeq keeps fixture emails and SKUs unique within a database
+func nextSeq() int64 {
+	return fixtureSeq.Add(1)
+}
+
+// CreateUser inserts an active customer
+func (e *Env) CreateUser(t *testing.T) *models.User {
+	t.Helper()
+	return e.createUser(t, "customer")
+}
+
+// CreateAdmin inserts an active admin
+func (e *Env) CreateAdmin(t *testing.T) *models.User {
+	t.Helper()
+	return e.createUser(t, "admin")
+}
+
+func (e *Env) createUser(t *testing.T, role string) *models.User {
+	t.Helper()
+
+	n := nextSeq()
+	user := &models.User{
+		Email:       fmt.Sprintf("%s%d@example.com", role, n),
+		Password:    FixturePassword, // hashed by the BeforeCreate hook
+		FirstName:   "Test",
+		LastName:    fmt.Sprintf("User %d", n),
+		PhoneNumber: models.EncryptedString(fmt.Sprintf("+1555%07d", n)),
+		Role:        role,
+		IsActive:    true,
+	}
+	if err := e.DB.Create(user).Error; err != nil {
+		t.Fatalf("failed to create %s: %v", role, err)
+	}
+	return user
+}
+
+// Login signs the user in through the API and returns the access token
+func (e *Env) Login(t *testing.T, user *models.User) string {
+	t.Helper()
+
+	var auth services.AuthResponse
+	e.Do(t, http.MethodPost, "/api/v1/auth/login", services.LoginRequest{
+		Email:    user.Email,
+		Password: FixturePassword,
+		IsAdmin:  user.Role != "customer",
+	}, "").ExpectStatus(t, http.StatusOK).Decode(t, &auth)
+
+	if auth.Token.AccessToken == "" {
+		t.Fatalf("login of %s returned no access token", user.Email)
+	}
+	return auth.Token.AccessToken
+}
+
+// CreateProduct inserts an active product with stock
+func (e *Env) CreateProduct(t *testing.T, price float64) *models.Product {
+	t.Helper()
+
+	n := nextSeq()
+	product := &models.Product{
+		Title:       fmt.Sprintf("Test product %d", n),
+		SKU:         fmt.Sprintf("TEST-%d", n),
+		Description: "Created by testutil",
+		Price:       price,
+		Category:    "test",
+		Status:      "active",
+		Stock:       100,
+	}
+	if err := e.DB.Create(product).Error; err != nil {
+		t.Fatalf("failed to create product: %v", err)
+	}
+	return product
+}
@@ -0,0 +1,67 @@
+package testutil
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"testing"
+)
+
+// FormFile is a file part of a multipart request
+type FormFile struct {
+	Field       string
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Multipart encodes fields and files as multipart/form-data and returns the
+// body with the headers to pass to Env.Do
+func Multipart(t *testing.T, fields map[string]string, files ...FormFile) (io.Reader, map[string]string) {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			t.Fatalf("failed to write form field %s: %v", name, err)
+		}
+	}
+	for _, file := range files {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename=%q`, file.Field, file.Name))
+		header.Set("Content-Type", file.ContentType)
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			t.Fatalf("failed to create form file %s: %v", file.Name, err)
+		}
+		part.Write(file.Data)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("failed to close multipart body: %v", err)
+	}
+	return &body, map[string]string{"Content-Type": writer.FormDataContentType()}
+}
+
+// PNG returns a solid width x height PNG. Vary shade to get images with
+// different content, since identical uploads share one stored object.
+func PNG(t *testing.T, width, height int, shade uint8) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: shade, G: 128, B: 255 - shade, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("failed to encode PNG: %v", err)
+	}
+	return buf.Bytes()
+}
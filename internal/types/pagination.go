@@ -0,0 +1,61 @@
+package types
+
+import (
+	"encoding/json"
+)
+
+// PageInfo describes where a page sits in the full result set. Total comes
+// from a COUNT over the same filters, not from the length of the page, unless
+// TotalExact is false, in which case it is the query planner's estimate.
+type PageInfo struct {
+	Page       int   `json:"page"`
+	Limit      int   `json:"limit"`
+	Total      int64 `json:"total"`
+	TotalExact bool  `json:"total_exact"`
+	TotalPages int   `json:"total_pages"`
+	HasNext    bool  `json:"has_next"`
+}
+
+// Paginated is the shared list response. It serializes as
+// {"<key>": [...], "pagination": {...}}, so each endpoint keeps its
+// resource-named items field (products, reviews, ...).
+type Paginated[T any] struct {
+	Key        string
+	Items      []T
+	Pagination PageInfo
+}
+
+func NewPaginated[T any](key string, items []T, page, limit int, total int64) Paginated[T] {
+	totalPages := 0
+	if limit > 0 {
+		totalPages = int((total + int64(limit) - 1) / int64(limit))
+	}
+
+	return Paginated[T]{
+		Key:   key,
+		Items: items,
+		Pagination: PageInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalExact: true,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+		},
+	}
+}
+
+func (p Paginated[T]) MarshalJSON() ([]byte, error) {
+	key := p.Key
+	if key == "" {
+		key = "items"
+	}
+	items := p.Items
+	if items == nil {
+		items = []T{}
+	}
+	return json.Marshal(map[string]interface{}{
+		key:          items,
+		"pagination": p.Pagination,
+	})
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cookie names and headers used by cookie-based auth for web clients
+const (
+	AccessTokenCookie  = "access_token"
+	RefreshTokenCookie = "refresh_token"
+	CSRFCookie         = "csrf_token"
+	CSRFHeader         = "X-CSRF-Token"
+
+	// CartTokenCookie carries an anonymous visitor's cart token
+	CartTokenCookie = "cart_token"
+
+	// defaultRefreshCookiePath is used when the request is not under an auth group
+	defaultRefreshCookiePath = "/api/v1/auth"
+)
+
+type CookieOptions struct {
+	Domain   string
+	Secure   bool
+	SameSite string
+}
+
+func (o CookieOptions) sameSite() http.SameSite {
+	switch strings.ToLower(o.SameSite) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+// SetAuthCookies stores both tokens in httpOnly cookies and issues a fresh
+// CSRF token in a cookie readable by the page's JavaScript.
+func SetAuthCookies(c *gin.Context, opts CookieOptions, accessToken string, accessExpiresAt int64, refreshToken string, refreshExpiresAt int64) error {
+	csrfBytes := make([]byte, 32)
+	if _, err := rand.Read(csrfBytes); err != nil {
+		return err
+	}
+
+	setCookie(c, opts, AccessTokenCookie, accessToken, "/", time.Unix(accessExpiresAt, 0), true)
+	setCookie(c, opts, RefreshTokenCookie, refreshToken, refreshCookiePath(c), time.Unix(refreshExpiresAt, 0), true)
+	setCookie(c, opts, CSRFCookie, hex.EncodeToString(csrfBytes), "/", time.Unix(refreshExpiresAt, 0), false)
+	return nil
+}
+
+func ClearAuthCookies(c *gin.Context, opts CookieOptions) {
+	expired := time.Unix(0, 0)
+	setCookie(c, opts, AccessTokenCookie, "", "/", expired, true)
+	setCookie(c, opts, RefreshTokenCookie, "", refreshCookiePath(c), expired, true)
+	setCookie(c, opts, CSRFCookie, "", "/", expired, false)
+}
+
+// SetCartCookie stores an anonymous cart's token in an httpOnly cookie
+func SetCartCookie(c *gin.Context, opts CookieOptions, token string, expiresAt time.Time) {
+	setCookie(c, opts, CartTokenCookie, token, "/", expiresAt, true)
+}
+
+func ClearCartCookie(c *gin.Context, opts CookieOptions) {
+	setCookie(c, opts, CartTokenCookie, "", "/", time.Unix(0, 0), true)
+}
+
+// refreshCookiePath limits the refresh cookie to the auth endpoints of the
+// API version that issued it, e.g. /api/v2/auth
+func refreshCookiePath(c *gin.Context) string {
+	path := c.Request.URL.Path
+	if idx := strings.Index(path, "/auth/"); idx >= 0 {
+		return path[:idx+len("/auth")]
+	}
+	return defaultRefreshCookiePath
+}
+
+func setCookie(c *gin.Context, opts CookieOptions, name, value, path string, expires time.Time, httpOnly bool) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   opts.Domain,
+		Expires:  expires,
+		MaxAge:   int(time.Until(expires).Seconds()),
+		Secure:   opts.Secure,
+		HttpOnly: httpOnly,
+		SameSite: opts.sameSite(),
+	})
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"net/http"
+)
+
+// V2Response is the standardized envelope for /api/v2. Successful responses
+// carry data (and meta such as pagination); failures carry a machine-readable
+// error code instead of a success flag.
+type V2Response struct {
+	Data  interface{}            `json:"data,omitempty"`
+	Meta  map[string]interface{} `json:"meta,omitempty"`
+	Error *V2Error               `json:"error,omitempty"`
+}
+
+type V2Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// ErrorCodeForStatus maps an HTTP status to the v2 error code clients switch on
+func ErrorCodeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "invalid_request"
+	case http.StatusUnauthorized:
+		return "unauthorized"
+	case http.StatusForbidden:
+		return "forbidden"
+	case http.StatusNotFound:
+		return "not_found"
+	case http.StatusConflict:
+		return "conflict"
+	case http.StatusRequestEntityTooLarge:
+		return "payload_too_large"
+	case http.StatusUnprocessableEntity:
+		return "unprocessable"
+	case http.StatusTooManyRequests:
+		return "rate_limited"
+	case http.StatusServiceUnavailable:
+		return "unavailable"
+	case http.StatusGatewayTimeout, http.StatusBadGateway:
+		return "upstream_error"
+	}
+	if status >= 500 {
+		return "internal_error"
+	}
+	return "request_failed"
+}
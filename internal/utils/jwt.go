@@ -14,6 +14,13 @@ type TokenType string
 const (
 	AccessToken  TokenType = "access"
 	RefreshToken TokenType = "refresh"
+	// PasswordChangeToken is issued instead of a token pair when a password
+	// has expired; it is only accepted by the change-password endpoint
+	PasswordChangeToken TokenType = "password_change"
+	// StreamTicket opens one WebSocket or EventSource stream, whose path is
+	// its audience. Browsers cannot set headers on those requests, so it is
+	// sent as ?ticket= and only accepted there.
+	StreamTicket TokenType = "stream_ticket"
 )
 
 type Claims struct {
@@ -21,6 +28,11 @@ type Claims struct {
 	Email  string `json:"email"`
 	Role   string `json:"role"`
 	Type   string `json:"type"`
+	// ImpersonatorID is the admin acting as this user; zero for normal tokens
+	ImpersonatorID uint `json:"impersonator_id,omitempty"`
+	// StoreID is the store the user belongs to; nil for deployment-level
+	// users, who may act in any store
+	StoreID *uint `json:"store_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -32,14 +44,15 @@ type TokenPair struct {
 }
 
 // Generate access token (short-lived: 15 minutes)
-func GenerateAccessToken(userID uint, email, role, jwtSecret string) (string, time.Time, error) {
+func GenerateAccessToken(userID uint, storeID *uint, email, role, jwtSecret string) (string, time.Time, error) {
 	expirationTime := time.Now().Add( 15* time.Minute)
 	
 	claims := &Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
-		Type:   string(AccessToken),
+		UserID:  userID,
+		Email:   email,
+		Role:    role,
+		Type:    string(AccessToken),
+		StoreID: storeID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -57,15 +70,103 @@ func GenerateAccessToken(userID uint, email, role, jwtSecret string) (string, ti
 	return tokenString, expirationTime, nil
 }
 
+// GenerateImpersonationToken issues an access token that acts as a user on
+// behalf of an admin. It carries the impersonator claim and has no refresh token.
+func GenerateImpersonationToken(userID uint, storeID *uint, email, role string, impersonatorID uint, ttl time.Duration, jwtSecret string) (string, time.Time, error) {
+	expirationTime := time.Now().Add(ttl)
+
+	claims := &Claims{
+		UserID:         userID,
+		Email:          email,
+		Role:           role,
+		Type:           string(AccessToken),
+		ImpersonatorID: impersonatorID,
+		StoreID:        storeID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			Subject:   email,
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString([]byte(jwtSecret))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return tokenString, expirationTime, nil
+}
+
+// GeneratePasswordChangeToken issues a short-lived token that only allows
+// changing an expired password
+func GeneratePasswordChangeToken(userID uint, storeID *uint, email, role string, ttl time.Duration, jwtSecret string) (string, time.Time, error) {
+	expirationTime := time.Now().Add(ttl)
+
+	claims := &Claims{
+		UserID:  userID,
+		Email:   email,
+		Role:    role,
+		Type:    string(PasswordChangeToken),
+		StoreID: storeID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			Subject:   email,
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString([]byte(jwtSecret))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return tokenString, expirationTime, nil
+}
+
+// GenerateStreamTicket issues a short-lived ticket that authenticates the
+// opening of the stream at path, and nothing else
+func GenerateStreamTicket(userID uint, storeID *uint, email, role string, impersonatorID uint, path string, ttl time.Duration, jwtSecret string) (string, time.Time, error) {
+	expirationTime := time.Now().Add(ttl)
+
+	claims := &Claims{
+		UserID:         userID,
+		Email:          email,
+		Role:           role,
+		Type:           string(StreamTicket),
+		ImpersonatorID: impersonatorID,
+		StoreID:        storeID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			Subject:   email,
+			Audience:  jwt.ClaimStrings{path},
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString([]byte(jwtSecret))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return tokenString, expirationTime, nil
+}
+
 // Generate refresh token (long-lived: 7 days)
-func GenerateRefreshToken(userID uint, email, role, jwtSecret string) (string, time.Time, error) {
+func GenerateRefreshToken(userID uint, storeID *uint, email, role, jwtSecret string) (string, time.Time, error) {
 	expirationTime := time.Now().Add(7 * 24 * time.Hour) // 7 days
 	
 	claims := &Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
-		Type:   string(RefreshToken),
+		UserID:  userID,
+		Email:   email,
+		Role:    role,
+		Type:    string(RefreshToken),
+		StoreID: storeID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -84,13 +185,13 @@ func GenerateRefreshToken(userID uint, email, role, jwtSecret string) (string, t
 }
 
 // Generate both tokens
-func GenerateTokenPair(userID uint, email, role, jwtSecret string) (*TokenPair, error) {
-	accessToken, accessExp, err := GenerateAccessToken(userID, email, role, jwtSecret)
+func GenerateTokenPair(userID uint, storeID *uint, email, role, jwtSecret string) (*TokenPair, error) {
+	accessToken, accessExp, err := GenerateAccessToken(userID, storeID, email, role, jwtSecret)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, refreshExp, err := GenerateRefreshToken(userID, email, role, jwtSecret)
+	refreshToken, refreshExp, err := GenerateRefreshToken(userID, storeID, email, role, jwtSecret)
 	if err != nil {
 		return nil, err
 	}
@@ -134,6 +235,6 @@ func GenerateRandomString(length int) (string, error) {
 
 // Legacy function for backward compatibility
 func GenerateToken(userID uint, email, role, jwtSecret string) (string, error) {
-	token, _, err := GenerateAccessToken(userID, email, role, jwtSecret)
+	token, _, err := GenerateAccessToken(userID, nil, email, role, jwtSecret)
 	return token, err
 }
\ No newline at end of file
@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxPageLimit caps the page size of every list endpoint
+const MaxPageLimit = 100
+
+// ParsePagination reads ?page= and ?limit=, falling back to page 1 and
+// defaultLimit for missing or out-of-range values.
+func ParsePagination(c *gin.Context, defaultLimit int) (page, limit int) {
+	page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ = strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultLimit)))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > MaxPageLimit {
+		limit = defaultLimit
+	}
+	return page, limit
+}
@@ -11,6 +11,18 @@ func IsValidEmail(email string) bool {
 	return matched
 }
 
+// IsValidPhone is a local format check: an optional leading + and 7-15
+// digits once spaces, dashes, dots and parentheses are removed.
+func IsValidPhone(phone string) bool {
+	matched, _ := regexp.MatchString(`^\+?[0-9]{7,15}$`, NormalizePhone(phone))
+	return matched
+}
+
+// NormalizePhone strips the formatting characters people type into numbers
+func NormalizePhone(phone string) string {
+	return strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "").Replace(strings.TrimSpace(phone))
+}
+
 func IsValidPassword(password string) bool {
 	return len(password) >= 8
 }
@@ -0,0 +1,49 @@
+// Package geo carries the location of a request's client IP through the
+// request context, so services, models and logs can use it without
+// depending on the HTTP layer.
+package geo
+
+import (
+	"context"
+	"strings"
+)
+
+// Location is where a client IP is, as ISO 3166 codes
+type Location struct {
+	Country string `json:"country,omitempty"` // e.g. "DE"
+	Region  string `json:"region,omitempty"`  // subdivision, e.g. "BY"
+}
+
+type locationKey struct{}
+
+// ContextWithLocation returns a copy of ctx carrying loc
+func ContextWithLocation(ctx context.Context, loc *Location) context.Context {
+	return context.WithValue(ctx, locationKey{}, loc)
+}
+
+// FromContext returns the location carried by ctx, or nil
+func FromContext(ctx context.Context) *Location {
+	if ctx == nil {
+		return nil
+	}
+	loc, _ := ctx.Value(locationKey{}).(*Location)
+	return loc
+}
+
+// countryCurrencies maps countries to the currency prices default to there
+var countryCurrencies = map[string]string{
+	"US": "USD", "CA": "CAD", "MX": "MXN", "BR": "BRL", "AR": "ARS",
+	"GB": "GBP", "IE": "EUR", "FR": "EUR", "DE": "EUR", "NL": "EUR",
+	"BE": "EUR", "LU": "EUR", "AT": "EUR", "IT": "EUR", "ES": "EUR",
+	"PT": "EUR", "FI": "EUR", "GR": "EUR", "SK": "EUR", "SI": "EUR",
+	"EE": "EUR", "LV": "EUR", "LT": "EUR", "MT": "EUR", "CY": "EUR",
+	"HR": "EUR", "CH": "CHF", "SE": "SEK", "NO": "NOK", "DK": "DKK",
+	"PL": "PLN", "CZ": "CZK", "HU": "HUF", "RO": "RON", "TR": "TRY",
+	"IN": "INR", "JP": "JPY", "CN": "CNY", "KR": "KRW", "SG": "SGD",
+	"HK": "HKD", "AU": "AUD", "NZ": "NZD", "ZA": "ZAR", "AE": "AED",
+}
+
+// CurrencyForCountry returns the currency of a country, or "" when unknown
+func CurrencyForCountry(country string) string {
+	return countryCurrencies[strings.ToUpper(country)]
+}
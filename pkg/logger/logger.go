@@ -40,4 +40,10 @@ func Warn(args ...interface{}) {
 
 func Fatal(args ...interface{}) {
 	log.Fatal(args...)
+}
+
+// WithFields returns an entry carrying structured fields, e.g.
+// logger.WithFields(map[string]interface{}{"service": name}).Warn("...")
+func WithFields(fields map[string]interface{}) *logrus.Entry {
+	return log.WithFields(logrus.Fields(fields))
 }
\ No newline at end of file
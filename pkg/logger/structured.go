@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/princeprakhar/ecommerce-backend/pkg/geo"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Logger is a leveled, structured logger that services receive from their
+// constructors instead of calling the package functions directly
+type Logger interface {
+	Debug(args ...interface{})
+	Info(args ...interface{})
+	Warn(args ...interface{})
+	Error(args ...interface{})
+
+	// WithFields returns a logger that adds fields such as product_id or
+	// user_id to every entry
+	WithFields(fields map[string]interface{}) Logger
+
+	// WithContext returns a logger that adds the request_id and client
+	// country carried by ctx, if any, so entries can be correlated with the
+	// request that caused them
+	WithContext(ctx context.Context) Logger
+}
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying the request ID
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request ID carried by ctx, or ""
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// New returns the application logger as a Logger, optionally tagged with
+// fields. Call it after Init.
+func New(fields map[string]interface{}) Logger {
+	return entryLogger{entry: log.WithFields(logrus.Fields(fields))}
+}
+
+type entryLogger struct {
+	entry *logrus.Entry
+}
+
+func (l entryLogger) Debug(args ...interface{}) { l.entry.Debug(args...) }
+func (l entryLogger) Info(args ...interface{})  { l.entry.Info(args...) }
+func (l entryLogger) Warn(args ...interface{})  { l.entry.Warn(args...) }
+func (l entryLogger) Error(args ...interface{}) { l.entry.Error(args...) }
+
+func (l entryLogger) WithFields(fields map[string]interface{}) Logger {
+	return entryLogger{entry: l.entry.WithFields(logrus.Fields(fields))}
+}
+
+func (l entryLogger) WithContext(ctx context.Context) Logger {
+	entry := l.entry
+	if id := RequestID(ctx); id != "" {
+		entry = entry.WithField("request_id", id)
+	}
+	if loc := geo.FromContext(ctx); loc != nil {
+		entry = entry.WithField("country", loc.Country)
+	}
+	return entryLogger{entry: entry}
+}
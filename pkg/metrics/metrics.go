@@ -0,0 +1,147 @@
+// Package metrics holds the Prometheus collectors for business events. They
+// are registered once on Registry, which /metrics serves, so dashboards and
+// alerts can follow signups, imports and failures alongside the logs.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "sipfinity"
+
+// Login failure reasons. They stay internal; the client always sees
+// "invalid credentials".
+const (
+	LoginUnknownUser = "unknown_user"
+	LoginBadPassword = "bad_password"
+	LoginWrongRole   = "wrong_role"
+	LoginWrongStore  = "wrong_store"
+)
+
+// CSV row outcomes
+const (
+	CSVRowImported = "imported"
+	CSVRowFailed   = "failed"
+)
+
+// Password reset stages
+const (
+	PasswordResetRequested = "requested"
+	PasswordResetCompleted = "completed"
+)
+
+// Token kinds removed by the token cleanup job
+const (
+	TokenKindRefresh       = "refresh"
+	TokenKindPasswordReset = "password_reset"
+)
+
+var (
+	// Registry is the registry every collector below is registered on. It
+	// also carries the Go runtime and process collectors.
+	Registry = prometheus.NewRegistry()
+
+	ProductsCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "catalog",
+		Name:      "products_created_total",
+		Help:      "Products created, by source (form or csv).",
+	}, []string{"source"})
+
+	CSVRows = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "catalog",
+		Name:      "csv_rows_total",
+		Help:      "Rows processed by CSV product uploads, by outcome.",
+	}, []string{"outcome"})
+
+	Signups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "signups_total",
+		Help:      "Accounts created, by role.",
+	}, []string{"role"})
+
+	LoginFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "login_failures_total",
+		Help:      "Rejected logins, by reason.",
+	}, []string{"reason"})
+
+	PasswordResets = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "password_resets_total",
+		Help:      "Password reset requests and completed resets.",
+	}, []string{"stage"})
+
+	TokensCleaned = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "tokens_cleaned_total",
+		Help:      "Expired, revoked or used tokens deleted by the cleanup job, by kind.",
+	}, []string{"kind"})
+
+	S3UploadBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "storage",
+		Name:      "s3_upload_bytes_total",
+		Help:      "Bytes successfully uploaded to S3.",
+	})
+
+	PendingDeletions = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "storage",
+		Name:      "pending_deletions",
+		Help:      "S3 objects queued for deletion, as of the last drain.",
+	})
+
+	ReviewFlags = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "reviews",
+		Name:      "flags_total",
+		Help:      "Reviews flagged for moderation.",
+	})
+
+	CounterDriftRows = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "counters",
+		Name:      "drift_rows",
+		Help:      "Rows whose count column was wrong at the last reconciliation, by counter.",
+	}, []string{"counter"})
+
+	CounterDrift = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "counters",
+		Name:      "drift_total",
+		Help:      "Sum of the corrections made by reconciliations, by counter.",
+	}, []string{"counter"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		ProductsCreated,
+		CSVRows,
+		Signups,
+		LoginFailures,
+		PasswordResets,
+		TokensCleaned,
+		S3UploadBytes,
+		PendingDeletions,
+		ReviewFlags,
+		CounterDriftRows,
+		CounterDrift,
+	)
+}
+
+// Handler serves Registry in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}